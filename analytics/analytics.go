@@ -0,0 +1,297 @@
+// Package analytics computes performance statistics from closed trades and
+// an equity curve: returns, risk-adjusted ratios, drawdowns and per-symbol
+// breakdowns, with JSON and HTML reports.
+//
+//	trades := analytics.Trades(fills)
+//	report := analytics.Analyze(trades, analytics.Config{StartingEquity: 10000})
+//	report.WriteHTML(w)
+package analytics
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// DefaultPeriodsPerYear annualizes daily ratios; crypto trades every day
+const DefaultPeriodsPerYear = 365
+
+// Trade is a closed round trip
+type Trade struct {
+	Symbol     string      `json:"symbol"`
+	Side       broker.Side `json:"side"`
+	Size       float64     `json:"size"` // Largest open size during the trade
+	EntryPrice float64     `json:"entryPrice"`
+	ExitPrice  float64     `json:"exitPrice"`
+	EntryTime  time.Time   `json:"entryTime"`
+	ExitTime   time.Time   `json:"exitTime"`
+	PnL        float64     `json:"pnl"` // Gross of fees
+	Fees       float64     `json:"fees"`
+	Funding    float64     `json:"funding"`         // Funding received (negative when paid), see AttributeFunding
+	Asset      string      `json:"asset,omitempty"` // Settlement asset of PnL, fees and funding (empty = reporting currency)
+}
+
+// NetPnL returns the PnL after fees and funding
+func (t Trade) NetPnL() float64 {
+	return t.PnL - t.Fees + t.Funding
+}
+
+// EquityPoint is the account equity at a point in time
+type EquityPoint struct {
+	Time   time.Time `json:"time"`
+	Equity float64   `json:"equity"`
+}
+
+// Config configures Analyze
+type Config struct {
+	// StartingEquity is the equity before the first trade
+	StartingEquity float64
+	// Equity is a sampled equity curve, e.g. from balance snapshots. When
+	// empty, the curve is built from StartingEquity and trade PnL at exit.
+	Equity []EquityPoint
+	// PeriodsPerYear annualizes Sharpe and Sortino (default 365)
+	PeriodsPerYear int
+}
+
+// DailyReturn is the equity change over a UTC day
+type DailyReturn struct {
+	Date   time.Time `json:"date"`
+	Return float64   `json:"return"`
+}
+
+// Stats are the trade statistics of a set of trades
+type Stats struct {
+	Trades       int     `json:"trades"`
+	Wins         int     `json:"wins"`
+	Losses       int     `json:"losses"`
+	WinRate      float64 `json:"winRate"`
+	NetPnL       float64 `json:"netPnl"`
+	Fees         float64 `json:"fees"`
+	Funding      float64 `json:"funding"`
+	ProfitFactor float64 `json:"profitFactor"` // Gross wins / gross losses (0 without losses)
+	AverageWin   float64 `json:"averageWin"`
+	AverageLoss  float64 `json:"averageLoss"` // Negative
+}
+
+// Report holds the performance statistics of a trading period
+type Report struct {
+	Start          time.Time     `json:"start"`
+	End            time.Time     `json:"end"`
+	StartingEquity float64       `json:"startingEquity"`
+	EndingEquity   float64       `json:"endingEquity"`
+	TotalReturn    float64       `json:"totalReturn"`
+	Sharpe         float64       `json:"sharpe"`
+	Sortino        float64       `json:"sortino"`
+	MaxDrawdown    float64       `json:"maxDrawdown"` // Largest peak-to-trough decline, as a fraction
+	MaxDrawdownAt  time.Time     `json:"maxDrawdownAt"`
+	ExposureTime   float64       `json:"exposureTime"` // Fraction of the period with a trade open
+	Equity         []EquityPoint `json:"equity"`
+	DailyReturns   []DailyReturn `json:"dailyReturns"`
+	Stats
+	Symbols map[string]Stats `json:"symbols"`
+}
+
+// Analyze computes a Report for trades
+func Analyze(trades []Trade, config Config) *Report {
+	if config.PeriodsPerYear <= 0 {
+		config.PeriodsPerYear = DefaultPeriodsPerYear
+	}
+
+	sorted := append([]Trade(nil), trades...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ExitTime.Before(sorted[j].ExitTime) })
+
+	r := &Report{
+		StartingEquity: config.StartingEquity,
+		Stats:          stats(sorted),
+		Symbols:        make(map[string]Stats),
+	}
+
+	bySymbol := make(map[string][]Trade)
+	for _, t := range sorted {
+		bySymbol[t.Symbol] = append(bySymbol[t.Symbol], t)
+	}
+	for symbol, ts := range bySymbol {
+		r.Symbols[symbol] = stats(ts)
+	}
+
+	r.Equity = config.Equity
+	if len(r.Equity) == 0 {
+		r.Equity = equityCurve(sorted, config.StartingEquity)
+	}
+	if len(r.Equity) == 0 {
+		return r
+	}
+
+	r.Start, r.End = r.Equity[0].Time, r.Equity[len(r.Equity)-1].Time
+	r.EndingEquity = r.Equity[len(r.Equity)-1].Equity
+	if r.StartingEquity == 0 {
+		r.StartingEquity = r.Equity[0].Equity
+	}
+	if r.StartingEquity != 0 {
+		r.TotalReturn = r.EndingEquity/r.StartingEquity - 1
+	}
+
+	r.MaxDrawdown, r.MaxDrawdownAt = maxDrawdown(r.Equity)
+	r.DailyReturns = dailyReturns(r.Equity, r.StartingEquity)
+	r.Sharpe, r.Sortino = ratios(r.DailyReturns, config.PeriodsPerYear)
+	r.ExposureTime = exposure(sorted, r.Start, r.End)
+	return r
+}
+
+// stats computes trade statistics
+func stats(trades []Trade) Stats {
+	var s Stats
+	var grossWin, grossLoss float64
+	for _, t := range trades {
+		pnl := t.NetPnL()
+		s.Trades++
+		s.NetPnL += pnl
+		s.Fees += t.Fees
+		s.Funding += t.Funding
+		switch {
+		case pnl > 0:
+			s.Wins++
+			grossWin += pnl
+		case pnl < 0:
+			s.Losses++
+			grossLoss -= pnl
+		}
+	}
+
+	if s.Trades > 0 {
+		s.WinRate = float64(s.Wins) / float64(s.Trades)
+	}
+	if s.Wins > 0 {
+		s.AverageWin = grossWin / float64(s.Wins)
+	}
+	if s.Losses > 0 {
+		s.AverageLoss = -grossLoss / float64(s.Losses)
+	}
+	if grossLoss > 0 {
+		s.ProfitFactor = grossWin / grossLoss
+	}
+	return s
+}
+
+// equityCurve builds an equity curve from trade exits, starting at the first
+// entry
+func equityCurve(trades []Trade, start float64) []EquityPoint {
+	if len(trades) == 0 {
+		return nil
+	}
+
+	first := trades[0].EntryTime
+	for _, t := range trades {
+		if t.EntryTime.Before(first) {
+			first = t.EntryTime
+		}
+	}
+
+	curve := []EquityPoint{{Time: first, Equity: start}}
+	equity := start
+	for _, t := range trades {
+		equity += t.NetPnL()
+		curve = append(curve, EquityPoint{Time: t.ExitTime, Equity: equity})
+	}
+	return curve
+}
+
+// maxDrawdown returns the largest peak-to-trough decline and when its
+// trough was reached
+func maxDrawdown(curve []EquityPoint) (float64, time.Time) {
+	var worst float64
+	var at time.Time
+	peak := curve[0].Equity
+	for _, p := range curve {
+		peak = max(peak, p.Equity)
+		if peak <= 0 {
+			continue
+		}
+		if dd := (peak - p.Equity) / peak; dd > worst {
+			worst, at = dd, p.Time
+		}
+	}
+	return worst, at
+}
+
+// dailyReturns returns the change of the last equity of each UTC day over
+// the previous day's, the first day measured against start
+func dailyReturns(curve []EquityPoint, start float64) []DailyReturn {
+	var returns []DailyReturn
+	prev := start
+	for i, p := range curve {
+		date := p.Time.UTC().Truncate(24 * time.Hour)
+		if i+1 < len(curve) && curve[i+1].Time.UTC().Truncate(24*time.Hour).Equal(date) {
+			continue // Not the last point of the day
+		}
+		if prev != 0 {
+			returns = append(returns, DailyReturn{Date: date, Return: p.Equity/prev - 1})
+		}
+		prev = p.Equity
+	}
+	return returns
+}
+
+// ratios returns the annualized Sharpe and Sortino ratios of daily returns,
+// with a zero risk-free rate
+func ratios(returns []DailyReturn, periodsPerYear int) (sharpe, sortino float64) {
+	if len(returns) < 2 {
+		return 0, 0
+	}
+
+	var mean float64
+	for _, r := range returns {
+		mean += r.Return
+	}
+	mean /= float64(len(returns))
+
+	var variance, downside float64
+	for _, r := range returns {
+		variance += (r.Return - mean) * (r.Return - mean)
+		if r.Return < 0 {
+			downside += r.Return * r.Return
+		}
+	}
+	stddev := math.Sqrt(variance / float64(len(returns)-1))
+	downsideDev := math.Sqrt(downside / float64(len(returns)))
+
+	annualize := math.Sqrt(float64(periodsPerYear))
+	if stddev > 0 {
+		sharpe = mean / stddev * annualize
+	}
+	if downsideDev > 0 {
+		sortino = mean / downsideDev * annualize
+	}
+	return sharpe, sortino
+}
+
+// exposure returns the fraction of [start, end] covered by at least one open
+// trade
+func exposure(trades []Trade, start, end time.Time) float64 {
+	total := end.Sub(start)
+	if total <= 0 {
+		return 0
+	}
+
+	spans := make([][2]time.Time, 0, len(trades))
+	for _, t := range trades {
+		spans = append(spans, [2]time.Time{t.EntryTime, t.ExitTime})
+	}
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0].Before(spans[j][0]) })
+
+	var covered time.Duration
+	var cursor time.Time
+	for _, s := range spans {
+		from := s[0]
+		if from.Before(cursor) {
+			from = cursor
+		}
+		if s[1].After(from) {
+			covered += s[1].Sub(from)
+			cursor = s[1]
+		}
+	}
+	return min(float64(covered)/float64(total), 1)
+}
@@ -0,0 +1,186 @@
+package analytics
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func day(d, hour int) time.Time {
+	return time.Date(2024, 1, d, hour, 0, 0, 0, time.UTC)
+}
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestTrades(t *testing.T) {
+	fills := []Fill{
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Price: 100, Size: 1, Fee: 0.1, Time: day(1, 0)},
+		{Symbol: "ETH-USDT", Side: broker.SideShort, Price: 50, Size: 2, Time: day(1, 1)},
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Price: 110, Size: 1, Fee: 0.1, Time: day(1, 2)},
+		{Symbol: "BTC-USDT", Side: broker.SideShort, Price: 120, Size: 1, Fee: 0.1, Time: day(1, 3)},
+		// Closes the rest of the long and flips short by 0.5
+		{Symbol: "BTC-USDT", Side: broker.SideShort, Price: 100, Size: 1.5, Fee: 0.3, Time: day(1, 4)},
+		{Symbol: "ETH-USDT", Side: broker.SideLong, Price: 40, Size: 2, Time: day(1, 5)},
+	}
+
+	trades := Trades(fills)
+	if len(trades) != 2 {
+		t.Fatalf("Trades() = %+v, want 2 closed trades (the flipped short stays open)", trades)
+	}
+
+	btc := trades[0]
+	// Entry 105 avg; exits 120 and 100: 15 - 5 = 10
+	if btc.Symbol != "BTC-USDT" || btc.Side != broker.SideLong || btc.Size != 2 || btc.EntryPrice != 105 ||
+		btc.ExitPrice != 110 || !approx(btc.PnL, 10) || !approx(btc.Fees, 0.5) || !btc.ExitTime.Equal(day(1, 4)) {
+		t.Errorf("BTC trade = %+v", btc)
+	}
+
+	eth := trades[1]
+	if eth.Side != broker.SideShort || eth.PnL != 20 || !eth.EntryTime.Equal(day(1, 1)) {
+		t.Errorf("ETH trade = %+v", eth)
+	}
+}
+
+func TestTrades_FloatResidue(t *testing.T) {
+	fills := []Fill{
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Price: 100, Size: 0.1, Time: day(1, 0)},
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Price: 100, Size: 0.2, Time: day(1, 1)},
+		{Symbol: "BTC-USDT", Side: broker.SideShort, Price: 110, Size: 0.3, Time: day(1, 2)},
+		{Symbol: "ETH-USDT", Side: broker.SideShort, Price: 50, Size: 0.3, Time: day(1, 0)},
+		{Symbol: "ETH-USDT", Side: broker.SideLong, Price: 40, Size: 0.1, Time: day(1, 1)},
+		{Symbol: "ETH-USDT", Side: broker.SideLong, Price: 40, Size: 0.2, Time: day(1, 2)},
+		{Symbol: "ETH-USDT", Side: broker.SideShort, Price: 45, Size: 1, Time: day(1, 3)},
+	}
+
+	trades := Trades(fills)
+	if len(trades) != 2 || !approx(trades[0].PnL, 3) || !approx(trades[1].PnL, 3) {
+		t.Fatalf("Trades() = %+v, want both positions closed despite float residue", trades)
+	}
+}
+
+func TestAttributeFunding(t *testing.T) {
+	trades := []Trade{
+		{Symbol: "BTC-USDT", EntryTime: day(1, 0), ExitTime: day(1, 12), PnL: 100, Fees: 5},
+		{Symbol: "BTC-USDT", EntryTime: day(2, 0), ExitTime: day(2, 12), PnL: 50},
+		{Symbol: "ETH-USDT", EntryTime: day(1, 0), ExitTime: day(3, 0), PnL: -20},
+	}
+	payments := []FundingPayment{
+		{Symbol: "BTC-USDT", Amount: -3, Time: day(1, 8)},
+		{Symbol: "BTC-USDT", Amount: -2, Time: day(1, 16)}, // Flat between trades
+		{Symbol: "BTC-USDT", Amount: 1, Time: day(2, 8)},
+		{Symbol: "ETH-USDT", Amount: 4, Time: day(1, 8)},
+		{Symbol: "ETH-USDT", Amount: 4, Time: day(2, 8)},
+	}
+
+	attributed, rest := AttributeFunding(trades, payments)
+	if attributed[0].Funding != -3 || attributed[1].Funding != 1 || attributed[2].Funding != 8 {
+		t.Errorf("funding = %v, %v, %v", attributed[0].Funding, attributed[1].Funding, attributed[2].Funding)
+	}
+	if len(rest) != 1 || rest[0].Amount != -2 {
+		t.Errorf("unattributed = %+v", rest)
+	}
+	if trades[0].Funding != 0 {
+		t.Error("AttributeFunding modified its input")
+	}
+	if net := attributed[0].NetPnL(); net != 92 {
+		t.Errorf("NetPnL() = %v, want 92", net)
+	}
+
+	r := Analyze(attributed, Config{StartingEquity: 1000})
+	if r.Funding != 6 || r.Symbols["ETH-USDT"].NetPnL != -12 {
+		t.Errorf("report funding = %v, ETH net = %v", r.Funding, r.Symbols["ETH-USDT"].NetPnL)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	trades := []Trade{
+		{Symbol: "BTC-USDT", EntryTime: day(1, 0), ExitTime: day(1, 12), PnL: 200, Fees: 10},
+		{Symbol: "ETH-USDT", EntryTime: day(2, 0), ExitTime: day(2, 12), PnL: -300},
+		{Symbol: "BTC-USDT", EntryTime: day(3, 0), ExitTime: day(4, 0), PnL: 150},
+	}
+
+	r := Analyze(trades, Config{StartingEquity: 1000})
+
+	if r.Trades != 3 || r.Wins != 2 || r.Losses != 1 || !approx(r.NetPnL, 40) || r.Fees != 10 {
+		t.Errorf("stats = %+v", r.Stats)
+	}
+	if !approx(r.ProfitFactor, 340.0/300) || !approx(r.AverageWin, 170) || r.AverageLoss != -300 {
+		t.Errorf("profit factor %v, average win %v, loss %v", r.ProfitFactor, r.AverageWin, r.AverageLoss)
+	}
+	if s := r.Symbols["BTC-USDT"]; s.Trades != 2 || s.WinRate != 1 || s.NetPnL != 340 {
+		t.Errorf("BTC breakdown = %+v", s)
+	}
+
+	// Equity 1000 → 1190 → 890 → 1040
+	if r.EndingEquity != 1040 || !approx(r.TotalReturn, 0.04) {
+		t.Errorf("ending equity %v, return %v", r.EndingEquity, r.TotalReturn)
+	}
+	if !approx(r.MaxDrawdown, 300.0/1190) || !r.MaxDrawdownAt.Equal(day(2, 12)) {
+		t.Errorf("max drawdown %v at %v", r.MaxDrawdown, r.MaxDrawdownAt)
+	}
+	if len(r.DailyReturns) != 3 || !approx(r.DailyReturns[0].Return, 0.19) || !approx(r.DailyReturns[2].Return, 1040.0/890-1) {
+		t.Errorf("daily returns = %+v", r.DailyReturns)
+	}
+	if r.Sharpe == 0 || r.Sortino <= r.Sharpe {
+		t.Errorf("Sharpe %v, Sortino %v, want Sortino above Sharpe with one losing day", r.Sharpe, r.Sortino)
+	}
+
+	// Open 12h + 12h + 24h of the 72h period
+	if !approx(r.ExposureTime, 48.0/72) {
+		t.Errorf("ExposureTime = %v, want %v", r.ExposureTime, 48.0/72)
+	}
+}
+
+func TestReport_Output(t *testing.T) {
+	r := Analyze([]Trade{
+		{Symbol: "BTC-USDT", EntryTime: day(1, 0), ExitTime: day(1, 12), PnL: 200},
+		{Symbol: "ETH-USDT", EntryTime: day(2, 0), ExitTime: day(2, 12), PnL: -100},
+	}, Config{StartingEquity: 1000})
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Report
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil || decoded.Trades != 2 || decoded.Symbols["ETH-USDT"].Losses != 1 {
+		t.Errorf("JSON round trip = %+v, %v", decoded, err)
+	}
+
+	buf.Reset()
+	if err := r.WriteHTML(&buf); err != nil {
+		t.Fatal(err)
+	}
+	html := buf.String()
+	for _, want := range []string{"<svg", "<td>ETH-USDT</td>", "10.00%"} {
+		if !strings.Contains(html, want) {
+			t.Errorf("HTML report missing %q", want)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	trades := []Trade{
+		{Symbol: "BTC-USDT", PnL: 100, Fees: 2},
+		{Symbol: "BTC-USD", PnL: 0.002, Fees: 0.0001, Funding: -0.0001, Asset: "BTC"},
+	}
+	normalized, err := Normalize(trades, map[string]float64{"BTC": 50000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := normalized[1]; n.PnL != 100 || n.Fees != 5 || n.Funding != -5 || n.Asset != "" {
+		t.Errorf("normalized = %+v", n)
+	}
+	if normalized[0] != trades[0] || trades[1].Asset != "BTC" {
+		t.Error("Normalize() changed trades in the reporting currency or its input")
+	}
+	if _, err := Normalize(trades, nil); err == nil {
+		t.Error("Normalize() without rates succeeded")
+	}
+}
@@ -0,0 +1,126 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Fill is an execution of an order: SideLong buys, SideShort sells
+type Fill struct {
+	Symbol string
+	Side   broker.Side
+	Price  float64
+	Size   float64
+	Fee    float64 // Paid fee in the settlement asset (negative for rebates)
+	Asset  string  // Settlement asset, e.g. BTC for coin-margined (empty = reporting currency)
+	Time   time.Time
+}
+
+// Trades turns fills into round-trip trades. Fills are netted per symbol: a
+// trade opens when the position leaves zero and closes when it returns to
+// zero, to within float rounding. A fill that flips the position closes the
+// trade and opens a new one with the remainder. Trades still open after the
+// last fill are not returned.
+func Trades(fills []Fill) []Trade {
+	sorted := append([]Fill(nil), fills...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	open := make(map[string]*openTrade)
+	var trades []Trade
+	for _, f := range sorted {
+		if f.Size <= 0 {
+			continue
+		}
+		signed := f.Size
+		if f.Side == broker.SideShort {
+			signed = -signed
+		}
+
+		for signed != 0 {
+			t := open[f.Symbol]
+			if t == nil {
+				t = &openTrade{Trade: Trade{Symbol: f.Symbol, Side: f.Side, EntryTime: f.Time, Asset: f.Asset}}
+				open[f.Symbol] = t
+			}
+
+			if sameSign(t.position, signed) || t.position == 0 {
+				t.add(signed, f.Price, f.Fee*abs(signed)/f.Size)
+				signed = 0
+				continue
+			}
+
+			// Reducing fill: close up to the open size
+			closed := min(abs(signed), abs(t.position))
+			if signed < 0 {
+				closed = -closed
+			}
+			t.reduce(closed, f.Price, f.Fee*abs(closed)/f.Size, f.Time)
+			signed -= closed
+			// Float residue of summed sizes counts as flat, e.g. 0.1 + 0.2 - 0.3
+			if dust(signed, f.Size) {
+				signed = 0
+			}
+			if dust(t.position, t.Size) {
+				t.position = 0
+				trades = append(trades, t.Trade)
+				delete(open, f.Symbol)
+			}
+		}
+	}
+	return trades
+}
+
+// openTrade accumulates a trade until its position returns to zero
+type openTrade struct {
+	Trade
+	position  float64 // Signed open size
+	exitValue float64 // Sum of exit price times size, for the average exit
+	exitSize  float64
+}
+
+// add grows the position
+func (t *openTrade) add(signed, price, fee float64) {
+	size := abs(t.position)
+	t.EntryPrice = (t.EntryPrice*size + price*abs(signed)) / (size + abs(signed))
+	t.position += signed
+	t.Size = max(t.Size, abs(t.position))
+	t.Fees += fee
+}
+
+// reduce shrinks the position by signed (opposite in sign to the position)
+func (t *openTrade) reduce(signed, price, fee float64, at time.Time) {
+	size := abs(signed)
+	if t.position > 0 {
+		t.PnL += (price - t.EntryPrice) * size
+	} else {
+		t.PnL += (t.EntryPrice - price) * size
+	}
+	t.position += signed
+	t.Fees += fee
+	t.exitValue += price * size
+	t.exitSize += size
+	t.ExitPrice = t.exitValue / t.exitSize
+	t.ExitTime = at
+}
+
+// sizeTolerance is the fraction of a size below which a remainder is
+// rounding error
+const sizeTolerance = 1e-9
+
+// dust reports whether x is negligible next to size
+func dust(x, size float64) bool {
+	return abs(x) <= size*sizeTolerance
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func sameSign(a, b float64) bool {
+	return (a > 0 && b > 0) || (a < 0 && b < 0)
+}
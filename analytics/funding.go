@@ -0,0 +1,43 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+)
+
+// FundingPayment is a funding settlement on a position, e.g. from an
+// exchange's income history
+type FundingPayment struct {
+	Symbol string
+	Amount float64 // Positive when received, negative when paid
+	Time   time.Time
+}
+
+// AttributeFunding adds each payment to the trade on its symbol that was
+// open when it settled, so NetPnL reflects funding as well as fees. It
+// returns the updated trades and the payments no trade was open for
+// (positions still open or opened before the fills start).
+func AttributeFunding(trades []Trade, payments []FundingPayment) ([]Trade, []FundingPayment) {
+	attributed := append([]Trade(nil), trades...)
+
+	bySymbol := make(map[string][]int)
+	for i, t := range attributed {
+		bySymbol[t.Symbol] = append(bySymbol[t.Symbol], i)
+	}
+	for _, indexes := range bySymbol {
+		sort.Slice(indexes, func(a, b int) bool { return attributed[indexes[a]].EntryTime.Before(attributed[indexes[b]].EntryTime) })
+	}
+
+	var unattributed []FundingPayment
+	for _, p := range payments {
+		indexes := bySymbol[p.Symbol]
+		// First trade on the symbol still open at the payment
+		n := sort.Search(len(indexes), func(i int) bool { return !attributed[indexes[i]].ExitTime.Before(p.Time) })
+		if n == len(indexes) || attributed[indexes[n]].EntryTime.After(p.Time) {
+			unattributed = append(unattributed, p)
+			continue
+		}
+		attributed[indexes[n]].Funding += p.Amount
+	}
+	return attributed, unattributed
+}
@@ -0,0 +1,26 @@
+package analytics
+
+import "fmt"
+
+// Normalize converts the PnL, fees and funding of trades settled in other
+// assets into the reporting currency, so USDT, USDC and coin-margined
+// trades can be analyzed together. rates maps each asset to the value of
+// one unit in the reporting currency, e.g. from fx.Converter.Rates. Trades
+// without an Asset are already in the reporting currency.
+func Normalize(trades []Trade, rates map[string]float64) ([]Trade, error) {
+	normalized := make([]Trade, len(trades))
+	for i, t := range trades {
+		if t.Asset != "" {
+			rate, ok := rates[t.Asset]
+			if !ok {
+				return nil, fmt.Errorf("analytics: no rate for %s", t.Asset)
+			}
+			t.PnL *= rate
+			t.Fees *= rate
+			t.Funding *= rate
+			t.Asset = ""
+		}
+		normalized[i] = t
+	}
+	return normalized, nil
+}
@@ -0,0 +1,111 @@
+package analytics
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strings"
+)
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// WriteHTML writes the report as a standalone HTML page with an equity
+// chart
+func (r *Report) WriteHTML(w io.Writer) error {
+	symbols := make([]string, 0, len(r.Symbols))
+	for symbol := range r.Symbols {
+		symbols = append(symbols, symbol)
+	}
+	sort.Strings(symbols)
+
+	return reportTemplate.Execute(w, struct {
+		*Report
+		SymbolNames []string
+		Chart       template.HTML
+	}{r, symbols, equityChart(r.Equity, 800, 240)})
+}
+
+// equityChart renders the equity curve as an inline SVG polyline
+func equityChart(curve []EquityPoint, width, height float64) template.HTML {
+	if len(curve) < 2 {
+		return ""
+	}
+
+	start, end := curve[0].Time, curve[len(curve)-1].Time
+	low, high := curve[0].Equity, curve[0].Equity
+	for _, p := range curve {
+		low, high = min(low, p.Equity), max(high, p.Equity)
+	}
+	span := end.Sub(start).Seconds()
+	if span <= 0 || high == low {
+		return ""
+	}
+
+	points := make([]string, len(curve))
+	for i, p := range curve {
+		x := p.Time.Sub(start).Seconds() / span * width
+		y := height - (p.Equity-low)/(high-low)*height
+		points[i] = fmt.Sprintf("%.1f,%.1f", x, y)
+	}
+	// Only numbers are interpolated, so the markup is safe
+	return template.HTML(fmt.Sprintf(
+		`<svg viewBox="0 0 %.0f %.0f" width="%.0f" height="%.0f"><polyline fill="none" stroke="#2563eb" stroke-width="2" points="%s"/></svg>`,
+		width, height, width, height, strings.Join(points, " ")))
+}
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"pct": func(f float64) string { return fmt.Sprintf("%.2f%%", f*100) },
+	"num": func(f float64) string { return fmt.Sprintf("%.2f", f) },
+	"stat": func(r *Report, symbol string) Stats {
+		return r.Symbols[symbol]
+	},
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Performance Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+th, td { padding: 4px 12px; border-bottom: 1px solid #ddd; text-align: right; }
+th:first-child, td:first-child { text-align: left; }
+</style>
+</head>
+<body>
+<h1>Performance Report</h1>
+<p>{{.Start.Format "2006-01-02 15:04"}} – {{.End.Format "2006-01-02 15:04"}} UTC</p>
+{{.Chart}}
+<h2>Summary</h2>
+<table>
+<tr><td>Starting equity</td><td>{{num .StartingEquity}}</td></tr>
+<tr><td>Ending equity</td><td>{{num .EndingEquity}}</td></tr>
+<tr><td>Total return</td><td>{{pct .TotalReturn}}</td></tr>
+<tr><td>Sharpe</td><td>{{num .Sharpe}}</td></tr>
+<tr><td>Sortino</td><td>{{num .Sortino}}</td></tr>
+<tr><td>Max drawdown</td><td>{{pct .MaxDrawdown}}</td></tr>
+<tr><td>Exposure time</td><td>{{pct .ExposureTime}}</td></tr>
+<tr><td>Trades</td><td>{{.Trades}}</td></tr>
+<tr><td>Win rate</td><td>{{pct .WinRate}}</td></tr>
+<tr><td>Profit factor</td><td>{{num .ProfitFactor}}</td></tr>
+<tr><td>Average win</td><td>{{num .AverageWin}}</td></tr>
+<tr><td>Average loss</td><td>{{num .AverageLoss}}</td></tr>
+<tr><td>Net PnL</td><td>{{num .NetPnL}}</td></tr>
+<tr><td>Fees</td><td>{{num .Fees}}</td></tr>
+<tr><td>Funding</td><td>{{num .Funding}}</td></tr>
+</table>
+<h2>By Symbol</h2>
+<table>
+<tr><th>Symbol</th><th>Trades</th><th>Win rate</th><th>Profit factor</th><th>Net PnL</th><th>Fees</th><th>Funding</th></tr>
+{{range .SymbolNames}}{{$s := stat $.Report .}}<tr><td>{{.}}</td><td>{{$s.Trades}}</td><td>{{pct $s.WinRate}}</td><td>{{num $s.ProfitFactor}}</td><td>{{num $s.NetPnL}}</td><td>{{num $s.Fees}}</td><td>{{num $s.Funding}}</td></tr>
+{{end}}
+</table>
+</body>
+</html>
+`))
@@ -0,0 +1,229 @@
+// Package announce polls exchange announcements and reports scheduled
+// maintenance, listings and delistings as events, so strategies can reduce
+// risk before the exchange goes down or a contract is removed. Unscheduled
+// downtime is the maintenance package's concern.
+package announce
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+const (
+	// DefaultInterval is the default time between checks in Run
+	DefaultInterval = 5 * time.Minute
+	// DefaultLead is how long before an announced start EventImminent is
+	// reported by default
+	DefaultLead = time.Hour
+)
+
+// EventType is the stage of an announcement an event reports
+type EventType string
+
+const (
+	EventPublished EventType = "PUBLISHED" // First seen
+	EventImminent  EventType = "IMMINENT"  // Starts within Config.Lead
+	EventStarted   EventType = "STARTED"   // Start time reached
+	EventEnded     EventType = "ENDED"     // End time reached
+)
+
+// stages orders the event types
+var stages = map[EventType]int{EventPublished: 0, EventImminent: 1, EventStarted: 2, EventEnded: 3}
+
+// Event reports an announcement reaching a stage. Announcements without a
+// stated start only ever report EventPublished; one found past a stage
+// reports that stage without the ones it skipped.
+type Event struct {
+	Type         EventType
+	Announcement broker.Announcement
+	Time         time.Time
+}
+
+// Handler receives events. Handlers run synchronously on the goroutine
+// that ran the check and should return quickly.
+type Handler func(ctx context.Context, e Event)
+
+// Config configures a Poller
+type Config struct {
+	// Kinds are the announcements reported (default maintenance, listing
+	// and delisting)
+	Kinds []broker.AnnouncementKind
+	// Interval between checks in Run (default 5m)
+	Interval time.Duration
+	// Lead reports EventImminent this long before an announced start
+	// (default 1h)
+	Lead time.Duration
+	// Logger receives events and failures (default: discard)
+	Logger *slog.Logger
+}
+
+// Poller follows the announcements of a broker.AnnouncementSource
+type Poller struct {
+	broker broker.Broker
+	config Config
+	log    *slog.Logger
+
+	mu       sync.Mutex
+	tracked  map[string]*tracked
+	handlers []Handler
+	now      func() time.Time
+}
+
+// tracked is an announcement and the last stage reported for it
+type tracked struct {
+	announcement broker.Announcement
+	stage        EventType
+}
+
+// done reports whether t has no stage left to report
+func (t *tracked) done() bool {
+	if t.stage == EventEnded {
+		return true
+	}
+	return t.announcement.End.IsZero() && (t.announcement.Start.IsZero() || t.stage == EventStarted)
+}
+
+// New creates a poller for b, which must implement
+// broker.AnnouncementSource for checks to succeed
+func New(b broker.Broker, config Config) *Poller {
+	if len(config.Kinds) == 0 {
+		config.Kinds = []broker.AnnouncementKind{broker.AnnouncementMaintenance, broker.AnnouncementListing, broker.AnnouncementDelisting}
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Lead <= 0 {
+		config.Lead = DefaultLead
+	}
+	return &Poller{
+		broker:  b,
+		config:  config,
+		log:     logging.Component(logging.OrDiscard(config.Logger), "announce"),
+		tracked: make(map[string]*tracked),
+		now:     time.Now,
+	}
+}
+
+// OnEvent registers a handler for events
+func (p *Poller) OnEvent(h Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers = append(p.handlers, h)
+}
+
+// Upcoming returns the announcements that haven't ended, with a stated
+// start, soonest first
+func (p *Poller) Upcoming() []broker.Announcement {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var upcoming []broker.Announcement
+	for _, t := range p.tracked {
+		if !t.announcement.Start.IsZero() && t.stage != EventEnded {
+			upcoming = append(upcoming, t.announcement)
+		}
+	}
+	slices.SortFunc(upcoming, func(a, b broker.Announcement) int { return a.Start.Compare(b.Start) })
+	return upcoming
+}
+
+// Check fetches the announcements and returns an event for each new one
+// and each that reached a new stage since the previous check. Announcements
+// that ended before they were first seen are not reported. It returns
+// broker.ErrNotSupported if the broker publishes no announcements.
+func (p *Poller) Check(ctx context.Context) ([]Event, error) {
+	source, ok := p.broker.(broker.AnnouncementSource)
+	if !ok {
+		return nil, broker.ErrNotSupported
+	}
+	announcements, err := source.GetAnnouncements(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := p.now()
+	listed := make(map[string]bool, len(announcements))
+	var events []Event
+
+	p.mu.Lock()
+	for _, a := range announcements {
+		if !slices.Contains(p.config.Kinds, a.Kind) {
+			continue
+		}
+		listed[a.ID] = true
+		if t, ok := p.tracked[a.ID]; ok {
+			t.announcement = a // Edited notices may move their window
+			continue
+		}
+		if p.stage(a, now) == EventEnded {
+			p.tracked[a.ID] = &tracked{announcement: a, stage: EventEnded}
+			continue
+		}
+		p.tracked[a.ID] = &tracked{announcement: a, stage: EventPublished}
+		events = append(events, Event{Type: EventPublished, Announcement: a, Time: now})
+	}
+
+	for id, t := range p.tracked {
+		if stage := p.stage(t.announcement, now); stages[stage] > stages[t.stage] {
+			t.stage = stage
+			events = append(events, Event{Type: stage, Announcement: t.announcement, Time: now})
+		}
+		if !listed[id] && t.done() {
+			delete(p.tracked, id)
+		}
+	}
+	handlers := p.handlers
+	p.mu.Unlock()
+
+	slices.SortStableFunc(events, func(a, b Event) int {
+		return cmp.Or(a.Announcement.Start.Compare(b.Announcement.Start), cmp.Compare(a.Announcement.ID, b.Announcement.ID),
+			cmp.Compare(stages[a.Type], stages[b.Type]))
+	})
+	for _, e := range events {
+		p.log.Info("announcement", "event", e.Type, "kind", e.Announcement.Kind, "title", e.Announcement.Title, "start", e.Announcement.Start)
+		for _, h := range handlers {
+			h(ctx, e)
+		}
+	}
+	return events, nil
+}
+
+// Run checks at the configured interval until the context is canceled.
+// Failed checks are logged and retried on the next tick.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := p.Check(ctx); err != nil {
+			p.log.Error("announcement check failed", logging.KeyError, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// stage returns the stage a reaches at now
+func (p *Poller) stage(a broker.Announcement, now time.Time) EventType {
+	switch {
+	case a.Start.IsZero():
+		return EventPublished
+	case !a.End.IsZero() && !now.Before(a.End):
+		return EventEnded
+	case !now.Before(a.Start):
+		return EventStarted
+	case a.Start.Sub(now) <= p.config.Lead:
+		return EventImminent
+	}
+	return EventPublished
+}
@@ -0,0 +1,95 @@
+package announce
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// feedBroker publishes the announcements set by the test
+type feedBroker struct {
+	*brokertest.Broker
+	feed []broker.Announcement
+}
+
+func (b *feedBroker) GetAnnouncements(ctx context.Context) ([]broker.Announcement, error) {
+	return b.feed, nil
+}
+
+func TestPoller_Stages(t *testing.T) {
+	now := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	maintenance := broker.Announcement{ID: "1", Kind: broker.AnnouncementMaintenance, Title: "System upgrade",
+		Start: now.Add(3 * time.Hour), End: now.Add(5 * time.Hour)}
+	b := &feedBroker{Broker: brokertest.New(), feed: []broker.Announcement{
+		maintenance,
+		{ID: "2", Kind: broker.AnnouncementOther, Title: "Trading competition"},
+		{ID: "3", Kind: broker.AnnouncementListing, Title: "New listing", Symbols: []string{"ABC-USDT"}},
+		{ID: "4", Kind: broker.AnnouncementMaintenance, Title: "Past upgrade", Start: now.Add(-48 * time.Hour), End: now.Add(-47 * time.Hour)},
+	}}
+	p := New(b, Config{})
+	var handled []Event
+	p.OnEvent(func(ctx context.Context, e Event) { handled = append(handled, e) })
+
+	check := func(at time.Duration, want ...string) {
+		t.Helper()
+		p.now = func() time.Time { return now.Add(at) }
+		events, err := p.Check(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range events {
+			got = append(got, e.Announcement.ID+" "+string(e.Type))
+		}
+		if len(got) != len(want) {
+			t.Fatalf("at %v events = %v, want %v", at, got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("at %v events = %v, want %v", at, got, want)
+			}
+		}
+	}
+
+	check(0, "3 PUBLISHED", "1 PUBLISHED")
+	check(time.Hour)
+	check(2*time.Hour+30*time.Minute, "1 IMMINENT")
+	if upcoming := p.Upcoming(); len(upcoming) != 1 || upcoming[0].ID != "1" {
+		t.Errorf("Upcoming() = %+v, want the maintenance", upcoming)
+	}
+	check(3*time.Hour, "1 STARTED")
+	b.feed = b.feed[1:] // Notices leave the feed before they end
+	check(6*time.Hour, "1 ENDED")
+	if upcoming := p.Upcoming(); len(upcoming) != 0 {
+		t.Errorf("Upcoming() after the window = %+v", upcoming)
+	}
+	check(7 * time.Hour)
+	if len(handled) != 5 {
+		t.Errorf("handled %d events, want 5", len(handled))
+	}
+}
+
+func TestPoller_FirstSeenInWindow(t *testing.T) {
+	now := time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC)
+	b := &feedBroker{Broker: brokertest.New(), feed: []broker.Announcement{
+		{ID: "9", Kind: broker.AnnouncementDelisting, Start: now.Add(-time.Minute)},
+	}}
+	p := New(b, Config{Lead: 30 * time.Minute})
+	p.now = func() time.Time { return now }
+
+	events, err := p.Check(context.Background())
+	if err != nil || len(events) != 2 || events[0].Type != EventPublished || events[1].Type != EventStarted {
+		t.Fatalf("Check() = %+v, %v, want published then started", events, err)
+	}
+}
+
+func TestPoller_NotSupported(t *testing.T) {
+	p := New(brokertest.New(), Config{})
+	if _, err := p.Check(context.Background()); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("Check() error = %v, want ErrNotSupported", err)
+	}
+}
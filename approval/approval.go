@@ -0,0 +1,237 @@
+// Package approval adds a manual sign-off step for large orders. The
+// wrapped broker parks orders above a notional threshold in a pending
+// queue, notifies handlers (wired to a chat bot, CLI prompt, etc.) and
+// only submits them once someone calls Approve. Orders nobody approves
+// within the expiry are dropped.
+package approval
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/clock"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// DefaultExpiry is how long an order waits for approval by default
+const DefaultExpiry = 5 * time.Minute
+
+var (
+	// ErrRejected is returned for orders rejected by an approver
+	ErrRejected = errors.New("approval: order rejected")
+	// ErrExpired is returned for orders nobody approved in time
+	ErrExpired = errors.New("approval: order expired unapproved")
+	// ErrUnknownRequest is returned by Approve and Reject for requests that
+	// are not pending, e.g. already decided or expired
+	ErrUnknownRequest = errors.New("approval: no such pending request")
+)
+
+// Request is an order waiting for approval
+type Request struct {
+	ID        string // Sequential, so in arrival order
+	Order     broker.OrderRequest
+	Notional  float64 // Size times the order price, or the last price for market orders
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+// Handler is notified of new pending requests. Handlers run synchronously
+// on the goroutine placing the order, before it starts waiting, and may
+// call Approve or Reject directly.
+type Handler func(ctx context.Context, r Request)
+
+// Config configures a Broker
+type Config struct {
+	// Threshold is the notional above which orders need approval.
+	// Reduce-only orders never do.
+	Threshold float64
+	// Expiry is how long a request waits for a decision (default 5m)
+	Expiry time.Duration
+	Clock  clock.Clock
+	Logger *slog.Logger
+}
+
+// Broker is a broker.Broker whose large orders need approval. PlaceOrder
+// blocks until the order is approved, rejected, expires or its context is
+// canceled.
+type Broker struct {
+	broker.Broker
+	config Config
+	clock  clock.Clock
+	log    *slog.Logger
+
+	mu       sync.Mutex
+	seq      int
+	pending  map[string]*pending
+	handlers []Handler
+}
+
+// pending is a parked order and the channel its decision arrives on
+type pending struct {
+	request  Request
+	decision chan error // nil error approves; buffered so deciding never blocks
+}
+
+// Wrap requires approval for b's large orders
+func Wrap(b broker.Broker, config Config) *Broker {
+	if config.Expiry <= 0 {
+		config.Expiry = DefaultExpiry
+	}
+	if config.Clock == nil {
+		config.Clock = clock.Real{}
+	}
+	return &Broker{
+		Broker:  b,
+		config:  config,
+		clock:   config.Clock,
+		log:     logging.Component(logging.OrDiscard(config.Logger), "approval"),
+		pending: make(map[string]*pending),
+	}
+}
+
+// OnPending registers a handler for new pending requests
+func (b *Broker) OnPending(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Pending returns the requests waiting for a decision, oldest first
+func (b *Broker) Pending() []Request {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	requests := make([]Request, 0, len(b.pending))
+	for _, p := range b.pending {
+		requests = append(requests, p.request)
+	}
+	sort.Slice(requests, func(i, j int) bool {
+		a, _ := strconv.Atoi(requests[i].ID)
+		b, _ := strconv.Atoi(requests[j].ID)
+		return a < b
+	})
+	return requests
+}
+
+// Approve submits the pending order with the given request ID
+func (b *Broker) Approve(id string) error {
+	return b.decide(id, nil)
+}
+
+// Reject drops the pending order with the given request ID. Its PlaceOrder
+// call fails with ErrRejected and the reason.
+func (b *Broker) Reject(id, reason string) error {
+	return b.decide(id, fmt.Errorf("%w: %s", ErrRejected, reason))
+}
+
+func (b *Broker) decide(id string, decision error) error {
+	b.mu.Lock()
+	p, ok := b.pending[id]
+	delete(b.pending, id)
+	b.mu.Unlock()
+
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownRequest, id)
+	}
+	p.decision <- decision
+	return nil
+}
+
+// PlaceOrder places orders up to the threshold directly and parks larger
+// ones until they are decided
+func (b *Broker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	if b.config.Threshold <= 0 || req.ReduceOnly {
+		return b.Broker.PlaceOrder(ctx, req)
+	}
+	notional, err := b.notional(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if notional <= b.config.Threshold {
+		return b.Broker.PlaceOrder(ctx, req)
+	}
+
+	now := b.clock.Now()
+	p := &pending{
+		request: Request{
+			Order:     *req,
+			Notional:  notional,
+			CreatedAt: now,
+			ExpiresAt: now.Add(b.config.Expiry),
+		},
+		decision: make(chan error, 1),
+	}
+	b.mu.Lock()
+	b.seq++
+	p.request.ID = strconv.Itoa(b.seq)
+	b.pending[p.request.ID] = p
+	handlers := append([]Handler(nil), b.handlers...)
+	b.mu.Unlock()
+
+	id := p.request.ID
+	b.log.Info("order awaiting approval", "id", id, "symbol", req.Symbol, "side", req.Side, "notional", notional)
+	for _, h := range handlers {
+		h(ctx, p.request)
+	}
+
+	timer := time.NewTimer(b.config.Expiry)
+	defer timer.Stop()
+
+	var decision error
+	select {
+	case decision = <-p.decision:
+	case <-timer.C:
+		if b.withdraw(id) {
+			b.log.Warn("order expired unapproved", "id", id)
+			return nil, fmt.Errorf("%w: request %s after %v", ErrExpired, id, b.config.Expiry)
+		}
+		decision = <-p.decision // Decided as it expired: the decision wins
+	case <-ctx.Done():
+		if b.withdraw(id) {
+			return nil, ctx.Err()
+		}
+		decision = <-p.decision
+	}
+
+	if decision != nil {
+		b.log.Info("order rejected", "id", id, logging.KeyError, decision)
+		return nil, decision
+	}
+	b.log.Info("order approved", "id", id)
+	return b.Broker.PlaceOrder(ctx, req)
+}
+
+// withdraw removes a request still pending and reports whether it was
+func (b *Broker) withdraw(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if _, ok := b.pending[id]; !ok {
+		return false
+	}
+	delete(b.pending, id)
+	return true
+}
+
+// notional values the order at its limit or trigger price, or the last
+// price when it has neither
+func (b *Broker) notional(ctx context.Context, req *broker.OrderRequest) (float64, error) {
+	price := req.Price
+	if price <= 0 {
+		price = req.StopPrice
+	}
+	if price <= 0 {
+		var err error
+		if price, err = b.Broker.GetCurrentPrice(ctx, req.Symbol); err != nil {
+			return 0, fmt.Errorf("pricing order for approval: %w", err)
+		}
+	}
+	return math.Abs(req.Size) * price, nil
+}
@@ -0,0 +1,105 @@
+package approval
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newApproval(config Config) (*Broker, *brokertest.Broker) {
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	return Wrap(inner, config), inner
+}
+
+func market(size float64) *broker.OrderRequest {
+	return &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: size}
+}
+
+func TestBroker_SmallOrdersPassThrough(t *testing.T) {
+	b, inner := newApproval(Config{Threshold: 10000})
+	b.OnPending(func(ctx context.Context, r Request) { t.Errorf("unexpected request %+v", r) })
+
+	if _, err := b.PlaceOrder(context.Background(), market(0.1)); err != nil {
+		t.Fatal(err)
+	}
+	big := market(1)
+	big.ReduceOnly = true
+	if _, err := b.PlaceOrder(context.Background(), big); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(inner.PlacedOrders()); got != 2 {
+		t.Errorf("orders placed = %d, want 2", got)
+	}
+}
+
+func TestBroker_ApproveAndReject(t *testing.T) {
+	b, inner := newApproval(Config{Threshold: 10000})
+	var requests []Request
+	b.OnPending(func(ctx context.Context, r Request) {
+		requests = append(requests, r)
+		if len(b.Pending()) != 1 {
+			t.Errorf("pending = %+v, want the request", b.Pending())
+		}
+		if r.Notional == 50000 {
+			b.Approve(r.ID)
+		} else {
+			b.Reject(r.ID, "too big")
+		}
+	})
+	ctx := context.Background()
+
+	if _, err := b.PlaceOrder(ctx, market(1)); err != nil {
+		t.Fatalf("approved order: %v", err)
+	}
+	if _, err := b.PlaceOrder(ctx, market(2)); !errors.Is(err, ErrRejected) {
+		t.Errorf("rejected order error = %v, want ErrRejected", err)
+	}
+	if len(requests) != 2 || requests[0].ID == requests[1].ID {
+		t.Errorf("requests = %+v, want two distinct", requests)
+	}
+	if got := len(inner.PlacedOrders()); got != 1 {
+		t.Errorf("orders placed = %d, want 1", got)
+	}
+	if err := b.Approve(requests[1].ID); !errors.Is(err, ErrUnknownRequest) {
+		t.Errorf("deciding twice error = %v, want ErrUnknownRequest", err)
+	}
+}
+
+func TestBroker_ApproveFromAnotherGoroutine(t *testing.T) {
+	b, inner := newApproval(Config{Threshold: 10000})
+	ids := make(chan string, 1)
+	b.OnPending(func(ctx context.Context, r Request) { ids <- r.ID })
+
+	go func() { b.Approve(<-ids) }()
+	if _, err := b.PlaceOrder(context.Background(), market(1)); err != nil {
+		t.Fatal(err)
+	}
+	if got := len(inner.PlacedOrders()); got != 1 {
+		t.Errorf("orders placed = %d, want 1", got)
+	}
+}
+
+func TestBroker_Expiry(t *testing.T) {
+	b, inner := newApproval(Config{Threshold: 10000, Expiry: 10 * time.Millisecond})
+
+	if _, err := b.PlaceOrder(context.Background(), market(1)); !errors.Is(err, ErrExpired) {
+		t.Errorf("error = %v, want ErrExpired", err)
+	}
+	if len(b.Pending()) != 0 || len(inner.PlacedOrders()) != 0 {
+		t.Error("expired order should leave the queue without being placed")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	b.OnPending(func(context.Context, Request) { cancel() })
+	if _, err := b.PlaceOrder(ctx, market(1)); !errors.Is(err, context.Canceled) {
+		t.Errorf("canceled error = %v, want context.Canceled", err)
+	}
+	if len(b.Pending()) != 0 {
+		t.Error("canceled order should leave the queue")
+	}
+}
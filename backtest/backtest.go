@@ -0,0 +1,159 @@
+// Package backtest replays stored candles of several symbols and intervals
+// into one strategy run, so pairs and basket strategies and strategies
+// reading more than one timeframe are tested the way they trade.
+//
+// Every candle is delivered when it closes, in chronological order across
+// all series, on a clock.Loop driving a brokertest.Broker. Before the
+// candles closing at an instant are delivered, the simulated broker is
+// marked to all of their closes, so a strategy reacting to one leg sees
+// the current price of the others. Candles closing at the same instant are
+// delivered shortest interval first, then in the order of Config.Series.
+package backtest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"github.com/agatticelli/trading-go/clock"
+	"github.com/agatticelli/trading-go/history"
+)
+
+// Series is the candles of one symbol and interval, in open time order
+type Series struct {
+	Symbol   string
+	Interval string // e.g. "1m", "4h"
+	Klines   []broker.Kline
+}
+
+// Handler receives each closed candle
+type Handler func(ctx context.Context, k broker.Kline)
+
+// Config configures a Backtest
+type Config struct {
+	// Series are the candles replayed (at least one)
+	Series []Series
+	// Broker is the simulator marked to the candles (default
+	// brokertest.New()). Its clock is set to the loop's.
+	Broker *brokertest.Broker
+}
+
+// Backtest replays candle series into a handler
+type Backtest struct {
+	loop   *clock.Loop
+	broker *brokertest.Broker
+	queue  []delivery
+	latest map[seriesKey]broker.Kline
+}
+
+// delivery is a candle and when it closes
+type delivery struct {
+	at       time.Time
+	interval time.Duration
+	series   int // Index in Config.Series
+	kline    broker.Kline
+}
+
+type seriesKey struct{ symbol, interval string }
+
+// New validates the series and sets the loop's clock to the first close.
+// Each series must have a valid interval and strictly increasing open
+// times.
+func New(config Config) (*Backtest, error) {
+	if len(config.Series) == 0 {
+		return nil, errors.New("backtest: at least one series is required")
+	}
+
+	var queue []delivery
+	for i, s := range config.Series {
+		if s.Symbol == "" {
+			return nil, fmt.Errorf("backtest: series %d has no symbol", i)
+		}
+		interval, err := history.ParseInterval(s.Interval)
+		if err != nil {
+			return nil, fmt.Errorf("backtest: %s: %w", s.Symbol, err)
+		}
+		for j, k := range s.Klines {
+			if j > 0 && !k.OpenTime.After(s.Klines[j-1].OpenTime) {
+				return nil, fmt.Errorf("backtest: %s %s: candle at %s out of order", s.Symbol, s.Interval, k.OpenTime.UTC().Format(time.RFC3339))
+			}
+			k.Symbol, k.Interval, k.Closed = s.Symbol, s.Interval, true
+			queue = append(queue, delivery{at: k.OpenTime.Add(interval), interval: interval, series: i, kline: k})
+		}
+	}
+	slices.SortFunc(queue, func(a, b delivery) int {
+		return cmp.Or(a.at.Compare(b.at), cmp.Compare(a.interval, b.interval), cmp.Compare(a.series, b.series))
+	})
+
+	start := time.Time{}
+	if len(queue) > 0 {
+		start = queue[0].at
+	}
+	sim := config.Broker
+	if sim == nil {
+		sim = brokertest.New()
+	}
+	loop := clock.NewLoop(start)
+	sim.SetClock(loop.Clock())
+	return &Backtest{loop: loop, broker: sim, queue: queue, latest: make(map[seriesKey]broker.Kline)}, nil
+}
+
+// Loop returns the event loop, to schedule other simulated events (funding,
+// timers) interleaved with the candles
+func (b *Backtest) Loop() *clock.Loop {
+	return b.loop
+}
+
+// Broker returns the simulated broker the strategy trades on
+func (b *Backtest) Broker() *brokertest.Broker {
+	return b.broker
+}
+
+// Latest returns the last closed candle of symbol and interval, e.g. the
+// other leg of a pair or the higher timeframe of the same symbol. Candles
+// closing at the current instant count even before they are delivered.
+func (b *Backtest) Latest(symbol, interval string) (broker.Kline, bool) {
+	k, ok := b.latest[seriesKey{symbol, interval}]
+	return k, ok
+}
+
+// Run delivers every candle to h, then runs the loop's remaining events up
+// to the last close. It stops early, returning the context's error, when
+// ctx is canceled between events.
+func (b *Backtest) Run(ctx context.Context, h Handler) error {
+	if len(b.queue) == 0 {
+		return nil
+	}
+	end := b.queue[len(b.queue)-1].at
+	b.scheduleNext(ctx, h, 0)
+	return b.loop.Run(ctx, end)
+}
+
+// scheduleNext schedules the candles closing together from queue[i], one
+// loop event per instant so events scheduled by the strategy interleave
+func (b *Backtest) scheduleNext(ctx context.Context, h Handler, i int) {
+	if i >= len(b.queue) {
+		return
+	}
+	at := b.queue[i].at
+	j := i
+	for j < len(b.queue) && b.queue[j].at.Equal(at) {
+		j++
+	}
+	b.loop.At(at, func() {
+		due := b.queue[i:j]
+		for _, d := range due {
+			b.broker.SetPrice(d.kline.Symbol, d.kline.Close)
+			b.latest[seriesKey{d.kline.Symbol, d.kline.Interval}] = d.kline
+		}
+		b.scheduleNext(ctx, h, j)
+		for _, d := range due {
+			h(ctx, d.kline)
+		}
+	})
+}
@@ -0,0 +1,114 @@
+package backtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/history"
+)
+
+var start = time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+
+// candles builds n candles of interval from start, closing at base+i
+func candles(interval time.Duration, n int, base float64) []broker.Kline {
+	klines := make([]broker.Kline, n)
+	for i := range klines {
+		klines[i] = broker.Kline{OpenTime: start.Add(time.Duration(i) * interval), Close: base + float64(i)}
+	}
+	return klines
+}
+
+func TestBacktest_Interleaving(t *testing.T) {
+	bt, err := New(Config{Series: []Series{
+		{Symbol: "BTC-USDT", Interval: "1h", Klines: candles(time.Hour, 2, 100)},
+		{Symbol: "ETH-USDT", Interval: "15m", Klines: candles(15*time.Minute, 8, 10)},
+		{Symbol: "BTC-USDT", Interval: "15m", Klines: candles(15*time.Minute, 8, 200)},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	var got []string
+	err = bt.Run(ctx, func(ctx context.Context, k broker.Kline) {
+		interval, _ := history.ParseInterval(k.Interval)
+		if now := bt.Loop().Now(); !now.Equal(k.OpenTime.Add(interval)) || !k.Closed {
+			t.Errorf("%s %s delivered at %v, want at its close", k.Symbol, k.Interval, now)
+		}
+		eth, _ := bt.Broker().GetCurrentPrice(ctx, "ETH-USDT")
+		got = append(got, fmt.Sprintf("%s %s %s eth=%v", bt.Loop().Now().Format("15:04"), k.Symbol, k.Interval, eth))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"00:15 ETH-USDT 15m eth=10", "00:15 BTC-USDT 15m eth=10",
+		"00:30 ETH-USDT 15m eth=11", "00:30 BTC-USDT 15m eth=11",
+		"00:45 ETH-USDT 15m eth=12", "00:45 BTC-USDT 15m eth=12",
+		// Shortest interval first, and ETH already marked for the hourly BTC candle
+		"01:00 ETH-USDT 15m eth=13", "01:00 BTC-USDT 15m eth=13", "01:00 BTC-USDT 1h eth=13",
+	}
+	if !slices.Equal(got[:len(want)], want) || len(got) != 18 {
+		t.Errorf("deliveries = %q..., want %q... (18 in all)", got[:min(len(got), len(want))], want)
+	}
+	if k, ok := bt.Latest("BTC-USDT", "1h"); !ok || k.Close != 101 {
+		t.Errorf("Latest(BTC 1h) = %+v, %v", k, ok)
+	}
+}
+
+func TestBacktest_StrategyEvents(t *testing.T) {
+	bt, err := New(Config{Series: []Series{{Symbol: "BTC-USDT", Interval: "1m", Klines: candles(time.Minute, 3, 100)}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	err = bt.Run(context.Background(), func(ctx context.Context, k broker.Kline) {
+		got = append(got, fmt.Sprint("kline ", k.Close))
+		if k.Close == 100 {
+			// Due between the first and second candles
+			bt.Loop().After(30*time.Second, func() { got = append(got, "timer") })
+		}
+	})
+	if err != nil || !slices.Equal(got, []string{"kline 100", "timer", "kline 101", "kline 102"}) {
+		t.Errorf("Run() = %v, events %q", err, got)
+	}
+}
+
+func TestBacktest_Cancel(t *testing.T) {
+	bt, _ := New(Config{Series: []Series{{Symbol: "BTC-USDT", Interval: "1m", Klines: candles(time.Minute, 10, 100)}}})
+	ctx, cancel := context.WithCancel(context.Background())
+	delivered := 0
+	err := bt.Run(ctx, func(ctx context.Context, k broker.Kline) {
+		if delivered++; delivered == 3 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) || delivered != 3 {
+		t.Errorf("Run() = %v after %d candles, want canceled after 3", err, delivered)
+	}
+}
+
+func TestNew_Validation(t *testing.T) {
+	unordered := candles(time.Minute, 3, 100)
+	unordered[1], unordered[2] = unordered[2], unordered[1]
+	tests := []struct {
+		name   string
+		series []Series
+	}{
+		{"no series", nil},
+		{"no symbol", []Series{{Interval: "1m"}}},
+		{"bad interval", []Series{{Symbol: "BTC-USDT", Interval: "1x"}}},
+		{"out of order", []Series{{Symbol: "BTC-USDT", Interval: "1m", Klines: unordered}}},
+	}
+	for _, tt := range tests {
+		if _, err := New(Config{Series: tt.series}); err == nil {
+			t.Errorf("New(%s) succeeded", tt.name)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/internal/adk"
+)
+
+// AnnouncementsURL lists the latest notices of the BingX support center
+const AnnouncementsURL = "https://bingx.com/api/customer/v1/announcement/listArticles"
+
+// WithAnnouncementsURL overrides the URL GetAnnouncements reads, e.g. for a
+// mirror or a test server. The response must have the shape of
+// AnnouncementsResponse.
+func WithAnnouncementsURL(url string) Option {
+	return func(c *Client) {
+		c.announcementsURL = url
+	}
+}
+
+var (
+	// announcedSymbol matches contract names like ABC-USDT in a notice
+	announcedSymbol = regexp.MustCompile(`\b[A-Z0-9]{2,20}-(?:USDT|USDC|USD)\b`)
+	// announcedWindow matches "2025-03-12 06:00", optionally followed by an
+	// end like "- 08:00" or "to 2025-03-13 02:00", in UTC
+	announcedWindow = regexp.MustCompile(`(\d{4}-\d{2}-\d{2})[ T](\d{2}:\d{2})(?:\s*(?:-|~|to)\s*(?:(\d{4}-\d{2}-\d{2})[ T])?(\d{2}:\d{2}))?`)
+)
+
+// GetAnnouncements returns the latest support-center notices, newest first.
+// Kind, symbols and the scheduled window are read from the title; BingX
+// states times in UTC.
+func (c *Client) GetAnnouncements(ctx context.Context) ([]broker.Announcement, error) {
+	body, err := c.retry(ctx, adk.ReadOnly, func() ([]byte, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.announcementsURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		return c.execute(req, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var response AnnouncementsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse announcements response", err)
+	}
+	if response.Code != APISuccessCode {
+		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	}
+
+	announcements := make([]broker.Announcement, 0, len(response.Data.Result))
+	for _, d := range response.Data.Result {
+		a := broker.Announcement{
+			ID:        d.ArticleID.String(),
+			Kind:      announcementKind(d.Title),
+			Title:     d.Title,
+			URL:       d.Link,
+			Symbols:   announcedSymbol.FindAllString(d.Title, -1),
+			Published: unixMilli(d.PublishTime),
+		}
+		a.Start, a.End = announcementWindow(d.Title)
+		announcements = append(announcements, a)
+	}
+	return announcements, nil
+}
+
+// announcementKind classifies a notice by the wording of its title
+func announcementKind(title string) broker.AnnouncementKind {
+	title = strings.ToLower(title)
+	switch {
+	case strings.Contains(title, "delist"):
+		return broker.AnnouncementDelisting
+	case strings.Contains(title, "maintenance"), strings.Contains(title, "upgrade"), strings.Contains(title, "suspension"):
+		return broker.AnnouncementMaintenance
+	case strings.Contains(title, "will list"), strings.Contains(title, "listing"), strings.Contains(title, "launch"):
+		return broker.AnnouncementListing
+	}
+	return broker.AnnouncementOther
+}
+
+// announcementWindow returns the first time stated in a title and the end
+// of its range, if any. An end earlier than the start, without a date of
+// its own, is on the next day.
+func announcementWindow(title string) (start, end time.Time) {
+	m := announcedWindow.FindStringSubmatch(title)
+	if m == nil {
+		return time.Time{}, time.Time{}
+	}
+	start, err := time.Parse("2006-01-02 15:04", m[1]+" "+m[2])
+	if err != nil {
+		return time.Time{}, time.Time{}
+	}
+	if m[4] == "" {
+		return start, time.Time{}
+	}
+
+	date := m[3]
+	if date == "" {
+		date = m[1]
+	}
+	end, err = time.Parse("2006-01-02 15:04", date+" "+m[4])
+	if err != nil {
+		return start, time.Time{}
+	}
+	if m[3] == "" && end.Before(start) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end
+}
@@ -0,0 +1,47 @@
+package bingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_GetAnnouncements(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"data":{"result":[
+			{"articleId":3001,"title":"BingX Will Delist ABC-USDT and XYZ-USDT Perpetual Futures on 2026-03-20 08:00 (UTC)","link":"https://bingx.com/support/articles/3001","publishTime":1773000000000},
+			{"articleId":"3000","title":"Scheduled System Maintenance on 2026-03-12 22:00 - 01:00 (UTC)","publishTime":1772900000000},
+			{"articleId":2999,"title":"BingX Perpetual Futures Will List NEW-USDT","publishTime":1772800000000},
+			{"articleId":2998,"title":"Trading Competition","publishTime":1772700000000}]}}`))
+	}))
+	defer server.Close()
+
+	c := NewPublicClient(false, WithAnnouncementsURL(server.URL))
+	var source broker.AnnouncementSource = c
+	announcements, err := source.GetAnnouncements(context.Background())
+	if err != nil || len(announcements) != 4 {
+		t.Fatalf("GetAnnouncements() = %+v, %v", announcements, err)
+	}
+
+	delist := announcements[0]
+	if delist.ID != "3001" || delist.Kind != broker.AnnouncementDelisting || !slices.Equal(delist.Symbols, []string{"ABC-USDT", "XYZ-USDT"}) ||
+		!delist.Start.Equal(time.Date(2026, 3, 20, 8, 0, 0, 0, time.UTC)) || !delist.End.IsZero() || delist.URL == "" {
+		t.Errorf("delisting = %+v", delist)
+	}
+	maintenance := announcements[1]
+	if maintenance.ID != "3000" || maintenance.Kind != broker.AnnouncementMaintenance ||
+		!maintenance.Start.Equal(time.Date(2026, 3, 12, 22, 0, 0, 0, time.UTC)) || !maintenance.End.Equal(time.Date(2026, 3, 13, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("maintenance = %+v", maintenance)
+	}
+	if announcements[2].Kind != broker.AnnouncementListing || announcements[3].Kind != broker.AnnouncementOther || !announcements[3].Start.IsZero() {
+		t.Errorf("listing and other = %+v", announcements[2:])
+	}
+	if !announcements[3].Published.Equal(time.UnixMilli(1772700000000)) {
+		t.Errorf("published = %v", announcements[3].Published)
+	}
+}
@@ -2,50 +2,106 @@ package bingx
 
 import (
 	"context"
-	"encoding/json"
-	"fmt"
-	"strconv"
+	"math"
+	"strings"
 	"time"
 
 	"github.com/agatticelli/trading-go/broker"
 )
 
-// GetBalance retrieves account balance
+// GetBalance retrieves the balance of the settlement asset (see
+// WithSettlementAsset). Coin-margined amounts are in that coin.
 func (c *Client) GetBalance(ctx context.Context) (*broker.Balance, error) {
-	body, err := c.makeRequest(ctx, "GET", EndpointBalance, nil)
+	balances, err := call[[]BalanceData](ctx, c, "GET", c.endpoints.balance, nil, "balance")
 	if err != nil {
 		return nil, err
 	}
-
-	var response BalanceResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse balance response", err)
+	data, err := c.settlementBalance(balances)
+	if err != nil {
+		return nil, err
 	}
+	return toBalance(data), nil
+}
 
-	if response.Code != APISuccessCode {
-		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+// GetBalances retrieves the balance of every asset in the account, one per
+// margin coin on coin-margined accounts. fx.Converter.Total sums them in
+// one currency.
+func (c *Client) GetBalances(ctx context.Context) ([]*broker.Balance, error) {
+	balances, err := call[[]BalanceData](ctx, c, "GET", c.endpoints.balance, nil, "balance")
+	if err != nil {
+		return nil, err
 	}
-
-	if len(response.Data) == 0 {
-		return nil, broker.NewBrokerError("bingx", "NO_DATA", "No balance data returned", nil)
+	result := make([]*broker.Balance, len(balances))
+	for i, data := range balances {
+		result[i] = toBalance(data)
 	}
+	return result, nil
+}
 
-	// Get USDT balance (assuming first entry is USDT)
-	data := response.Data[0]
-
-	total, _ := strconv.ParseFloat(data.Equity, 64)
-	available, _ := strconv.ParseFloat(data.AvailableMargin, 64)
-	inUse, _ := strconv.ParseFloat(data.UsedMargin, 64)
-	unrealizedPnL, _ := strconv.ParseFloat(data.UnrealizedProfit, 64)
-	realizedPnL, _ := strconv.ParseFloat(data.RealisedProfit, 64)
+// settlementBalance picks the settlement asset's row. A USDT-margined
+// account reporting a single other asset (demo accounts settle in VST) uses
+// that row.
+func (c *Client) settlementBalance(balances []BalanceData) (BalanceData, error) {
+	if len(balances) == 0 {
+		return BalanceData{}, broker.NewBrokerError("bingx", "NO_DATA", "No balance data returned", nil)
+	}
+	for _, data := range balances {
+		if strings.EqualFold(data.Asset, c.settleAsset) {
+			return data, nil
+		}
+	}
+	if len(balances) == 1 && c.instrument == InstrumentUSDTMargined {
+		return balances[0], nil
+	}
+	return BalanceData{}, broker.NewBrokerError("bingx", "NO_DATA", "No "+c.settleAsset+" balance returned", nil)
+}
 
+func toBalance(data BalanceData) *broker.Balance {
 	return &broker.Balance{
 		Asset:         data.Asset,
-		Total:         total,
-		Available:     available,
-		InUse:         inUse,
-		UnrealizedPnL: unrealizedPnL,
-		RealizedPnL:   realizedPnL,
+		Total:         data.Equity.Float64(),
+		Available:     data.AvailableMargin.Float64(),
+		InUse:         data.UsedMargin.Float64(),
+		UnrealizedPnL: data.UnrealizedProfit.Float64(),
+		RealizedPnL:   data.RealisedProfit.Float64(),
 		Timestamp:     time.Now(),
-	}, nil
+	}
+}
+
+// GetAccountRisk summarizes margin from the balance and positions endpoints:
+// the settlement asset's equity, available, used and frozen margin, and the
+// maintenance margin and position value of every open position
+func (c *Client) GetAccountRisk(ctx context.Context) (*broker.AccountRisk, error) {
+	balances, err := call[[]BalanceData](ctx, c, "GET", c.endpoints.balance, nil, "balance")
+	if err != nil {
+		return nil, err
+	}
+	data, err := c.settlementBalance(balances)
+	if err != nil {
+		return nil, err
+	}
+
+	positions, err := call[[]PositionData](ctx, c, "GET", c.endpoints.positions, nil, "positions")
+	if err != nil {
+		return nil, err
+	}
+
+	risk := &broker.AccountRisk{
+		Asset:           data.Asset,
+		Equity:          data.Equity.Float64(),
+		AvailableMargin: data.AvailableMargin.Float64(),
+		UsedMargin:      data.UsedMargin.Float64(),
+		FrozenMargin:    data.FreezedMargin.Float64(),
+		UnrealizedPnL:   data.UnrealizedProfit.Float64(),
+		Timestamp:       time.Now(),
+	}
+	for _, pos := range positions {
+		if pos.PositionAmt.Float64() == 0 {
+			continue
+		}
+		risk.Positions++
+		risk.MaintenanceMargin += pos.MaintenanceMargin.Float64()
+		risk.Notional += math.Abs(pos.PositionValue.Float64())
+	}
+	return risk, nil
 }
@@ -0,0 +1,79 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/internal/adk"
+)
+
+// envelope is the code/msg wrapper around the data of every BingX response
+var envelope = adk.Envelope{Code: "code", Msg: "msg", Data: "data", Success: "0"}
+
+// unwrapped is the envelope of the wallet endpoints, whose successful
+// responses are the payload itself (an object or a bare array) and whose
+// failures carry code/msg
+var unwrapped = adk.Envelope{Code: "code", Msg: "msg", Success: "0"}
+
+// apiErrors maps the codes of API errors to the broker errors they match
+var apiErrors = map[string]error{
+	fmt.Sprintf("API_%d", APISymbolNotExistCode): broker.ErrInvalidSymbol,
+}
+
+// Call sends a request like Raw and returns the data field of the response
+// unmarshaled into T, for adapter authors wrapping endpoints the client
+// doesn't:
+//
+//	rates, err := bingx.Call[CommissionRate](ctx, client, "GET", "/openApi/swap/v2/user/commissionRate", nil)
+//
+// API errors are returned as a *broker.BrokerError with code API_<code>,
+// matching the broker error it stands for where there is one (e.g.
+// broker.ErrInvalidSymbol for 109400), and unparseable responses with code
+// PARSE_ERROR.
+func Call[T any](ctx context.Context, c *Client, method, endpoint string, params map[string]string) (T, error) {
+	body, err := c.Raw(ctx, method, endpoint, params)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decode[T](ctx, body, "")
+}
+
+// call sends a signed request with parameters in the query string and
+// decodes the response; what names the response in parse errors
+func call[T any](ctx context.Context, c *Client, method, endpoint string, params map[string]string, what string) (T, error) {
+	body, err := c.makeRequest(ctx, method, endpoint, params)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decode[T](ctx, body, what)
+}
+
+// callUnwrapped is call for endpoints answering in the unwrapped envelope
+func callUnwrapped[T any](ctx context.Context, c *Client, method, endpoint string, params map[string]string, what string) (T, error) {
+	body, err := c.makeRequest(ctx, method, endpoint, params)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return decodeEnvelope[T](ctx, unwrapped, body, what)
+}
+
+// decode unmarshals a response body, returning its data or the API error it
+// reports; what names the response in parse errors
+func decode[T any](ctx context.Context, body []byte, what string) (T, error) {
+	return decodeEnvelope[T](ctx, envelope, body, what)
+}
+
+// decodeEnvelope is decode for responses wrapped in env
+func decodeEnvelope[T any](ctx context.Context, env adk.Envelope, body []byte, what string) (T, error) {
+	v, err := adk.Decode[T]("bingx", env, body, what)
+	var brokerErr *broker.BrokerError
+	if errors.As(err, &brokerErr) && brokerErr.Err == nil {
+		brokerErr.Err = apiErrors[brokerErr.Code]
+	}
+	return v, adk.Annotate(ctx, err)
+}
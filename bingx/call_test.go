@@ -0,0 +1,72 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name      string
+		body      string
+		want      string
+		wantCode  string
+		wantMsg   string
+		wantError bool
+	}{
+		{name: "success", body: `{"code":0,"msg":"","data":{"symbol":"BTC-USDT","price":"43000.5"}}`, want: "43000.5"},
+		{name: "no data", body: `{"code":0,"msg":""}`},
+		{name: "api error", body: `{"code":101204,"msg":"Insufficient margin","data":{}}`,
+			wantCode: "API_101204", wantMsg: "Insufficient margin", wantError: true},
+		{name: "api error with mismatched data", body: `{"code":80014,"msg":"Invalid parameters","data":[]}`,
+			wantCode: "API_80014", wantMsg: "Invalid parameters", wantError: true},
+		{name: "mismatched data", body: `{"code":0,"msg":"","data":[]}`,
+			wantCode: "PARSE_ERROR", wantMsg: "Failed to parse price response", wantError: true},
+		{name: "not json", body: `upstream timeout`,
+			wantCode: "PARSE_ERROR", wantMsg: "Failed to parse price response", wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := decode[PriceData](context.Background(), []byte(tt.body), "price")
+			if !tt.wantError {
+				if err != nil || data.Price != tt.want {
+					t.Fatalf("decode() = %+v, %v, want price %q", data, err, tt.want)
+				}
+				return
+			}
+			var brokerErr *broker.BrokerError
+			if !errors.As(err, &brokerErr) || brokerErr.Code != tt.wantCode || brokerErr.Message != tt.wantMsg {
+				t.Fatalf("decode() error = %v, want %s %q", err, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestCall(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("signature") == "" {
+			t.Errorf("query = %v, want a signed request", r.URL.Query())
+		}
+		w.Write([]byte(`{"code":0,"msg":"","data":[{"asset":"USDT","balance":"1000"}]}`))
+	}))
+	defer server.Close()
+
+	type asset struct {
+		Asset   string    `json:"asset"`
+		Balance FlexFloat `json:"balance"`
+	}
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	assets, err := Call[[]asset](context.Background(), c, http.MethodGet, "/openApi/swap/v3/user/balance", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assets) != 1 || assets[0].Asset != "USDT" || assets[0].Balance.Float64() != 1000 {
+		t.Errorf("Call() = %+v, want one USDT asset of 1000", assets)
+	}
+}
@@ -1,194 +1,283 @@
 package bingx
 
 import (
-	"context"
-	"crypto/hmac"
-	"crypto/sha256"
-	"encoding/hex"
-	"fmt"
-	"io"
+	"log/slog"
 	"net/http"
-	"net/url"
-	"sort"
-	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/cache"
+	"github.com/agatticelli/trading-go/credentials"
+	"github.com/agatticelli/trading-go/framelog"
+	"github.com/agatticelli/trading-go/logging"
+	"github.com/agatticelli/trading-go/signing"
 )
 
 // Client implements broker.Broker interface for BingX
 type Client struct {
-	apiKey     string
-	secretKey  string
-	baseURL    string
-	httpClient *http.Client
+	creds            credentials.Provider
+	signer           signing.Signer
+	baseURL          string
+	streamURL        string
+	announcementsURL string // Read by GetAnnouncements
+	httpClient       *http.Client
+	instrument       InstrumentType
+	settleAsset      string // Balance GetBalance reports
+	endpoints        endpointSet
+	cache            *cache.TTL[string, any]
+	precisions       precisionTable
+	retryPolicy      *RetryPolicy
+	limits           rateLimits
+	life             lifecycle
+	env              broker.Environment
+	public           bool // No API keys: public endpoints only
+	logger           *slog.Logger
+	log              componentLoggers
+	frames           *framelog.Writer
+	signDebug        bool         // Log signing details of authentication failures
+	clockOffset      atomic.Int64 // Last measured server time offset, in nanoseconds
 }
 
-// NewClient creates a new BingX broker client
-func NewClient(apiKey, secretKey string, demoMode bool) *Client {
-	baseURL := BaseURLProd
-	if demoMode {
-		baseURL = BaseURLDemo
-	}
+// componentLoggers are the client's logger tagged per component
+type componentLoggers struct {
+	transport *slog.Logger
+	streamer  *slog.Logger
+}
 
-	return &Client{
-		apiKey:    apiKey,
-		secretKey: secretKey,
-		baseURL:   baseURL,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+// Option configures optional Client behavior
+type Option func(*Client)
+
+// WithInstrumentType selects the contract family the client trades.
+// Defaults to USDT-margined perpetuals.
+func WithInstrumentType(instrument InstrumentType) Option {
+	return func(c *Client) {
+		c.instrument = instrument
 	}
 }
 
-// Name returns the broker name
-func (c *Client) Name() string {
-	return "bingx"
+// WithSettlementAsset selects the asset GetBalance and GetAccountRisk
+// report. Defaults to USDT on USDT-margined clients and BTC on
+// coin-margined ones, whose accounts hold one balance per margin coin.
+func WithSettlementAsset(asset string) Option {
+	return func(c *Client) {
+		c.settleAsset = strings.ToUpper(asset)
+	}
 }
 
-// SupportedFeatures returns the features supported by BingX
-func (c *Client) SupportedFeatures() broker.Features {
-	return broker.Features{
-		TrailingStop:     true,
-		MultipleTP:       true,
-		BracketOrders:    true,
-		MaxLeverage:      125,
-		ReduceOnlyOrders: true,
+// WithHTTPClient replaces the default HTTP client (30s timeout)
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
 	}
 }
 
-// sign creates HMAC-SHA256 signature for API requests
-func (c *Client) sign(params string) string {
-	h := hmac.New(sha256.New, []byte(c.secretKey))
-	h.Write([]byte(params))
-	signature := hex.EncodeToString(h.Sum(nil))
-	return signature
+// WithCredentials retrieves API keys from p on every request instead of
+// using the keys passed to NewClient. Wrap remote providers in a
+// credentials.Cache so rotated keys are picked up without a round trip per
+// request.
+func WithCredentials(p credentials.Provider) Option {
+	return func(c *Client) {
+		c.creds = p
+	}
 }
 
-// makeRequest makes an HTTP request to BingX API
-func (c *Client) makeRequest(ctx context.Context, method, endpoint string, params map[string]string) ([]byte, error) {
-	timestamp := time.Now().UnixMilli()
-
-	// Add timestamp to parameters
-	if params == nil {
-		params = make(map[string]string)
+// WithSigner selects the request signature scheme for RSA or Ed25519 API
+// keys; the secret key is then the PEM encoded private key. Defaults to
+// signing.HMAC.
+func WithSigner(s signing.Signer) Option {
+	return func(c *Client) {
+		c.signer = s
 	}
-	params["timestamp"] = strconv.FormatInt(timestamp, 10)
+}
 
-	// Build query parameters (sorted keys)
-	values := url.Values{}
-	for key, value := range params {
-		values.Set(key, value)
+// WithCache caches semi-static data (contracts, leverage, fee rates and
+// server time offset) for ttl. Caching is disabled by default.
+func WithCache(ttl time.Duration) Option {
+	return func(c *Client) {
+		c.cache = cache.New[string, any](ttl)
 	}
-	queryString := values.Encode()
-
-	// Create signature
-	signature := c.sign(queryString)
-
-	// Add signature to URL
-	fullURL := fmt.Sprintf("%s%s?%s&signature=%s", c.baseURL, endpoint, queryString, signature)
+}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// WithEnvironment selects production or the VST demo deployment,
+// overriding demoMode
+func WithEnvironment(env broker.Environment) Option {
+	return func(c *Client) {
+		c.env = env
 	}
+}
 
-	// Only add API key header
-	req.Header.Set("X-BX-APIKEY", c.apiKey)
-
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, broker.NewBrokerError("bingx", "REQUEST_FAILED", "HTTP request failed", err)
+// WithBaseURL overrides the API base URL derived from the environment
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
 	}
-	defer resp.Body.Close()
+}
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, broker.NewBrokerError("bingx", "READ_FAILED", "Failed to read response", err)
+// WithStreamURL overrides the market WebSocket URL derived from the
+// environment
+func WithStreamURL(streamURL string) Option {
+	return func(c *Client) {
+		c.streamURL = streamURL
 	}
+}
 
-	// Check HTTP status
-	if resp.StatusCode != http.StatusOK {
-		return nil, broker.NewBrokerError("bingx", "HTTP_ERROR",
-			fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
+// WithLogger logs requests, retries, stream connections and order activity
+// to l. Wrap its handler in logging.Redact when it may receive request URLs
+// from other code; the client itself never logs keys or signatures. Logging
+// is disabled by default.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.logger = l
 	}
+}
 
-	return body, nil
+// WithFrameLog records every market stream frame, as received, to w for
+// later replay (see ReplayTrades). Recording failures are logged and don't
+// interrupt the stream.
+func WithFrameLog(w *framelog.Writer) Option {
+	return func(c *Client) {
+		c.frames = w
+	}
 }
 
-// makeRequestWithPayload makes HTTP request with special handling for JSON parameters
-// This is needed for orders with stopLoss/takeProfit which are sent as JSON strings
-func (c *Client) makeRequestWithPayload(ctx context.Context, method, endpoint string, params map[string]string) ([]byte, error) {
-	timestamp := time.Now().UnixMilli()
+// NewClient creates a new BingX broker client. Without either key it is a
+// public client (see NewPublicClient); with only one of them, signed calls
+// fail with broker.ErrAuthFailed.
+func NewClient(apiKey, secretKey string, demoMode bool, opts ...Option) *Client {
+	env := broker.EnvironmentProduction
+	if demoMode {
+		env = broker.EnvironmentTestnet
+	}
 
-	// Add timestamp
-	if params == nil {
-		params = make(map[string]string)
+	c := &Client{
+		creds:  credentials.Static{APIKey: apiKey, SecretKey: secretKey},
+		signer: signing.HMAC(),
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		instrument:       InstrumentUSDTMargined,
+		env:              env,
+		announcementsURL: AnnouncementsURL,
 	}
-	params["timestamp"] = strconv.FormatInt(timestamp, 10)
 
-	// Build parameters in sorted order
-	keys := make([]string, 0, len(params))
-	for k := range params {
-		keys = append(keys, k)
+	for _, opt := range opts {
+		opt(c)
 	}
-	sort.Strings(keys)
 
-	// First: Build parameters WITHOUT encoding for signature
-	paramPairsNoEncoding := make([]string, 0, len(keys))
-	for _, key := range keys {
-		paramPairsNoEncoding = append(paramPairsNoEncoding, key+"="+params[key])
+	if static, ok := c.creds.(credentials.Static); ok && static.APIKey == "" && static.SecretKey == "" {
+		c.public = true
 	}
-	queryStringForSignature := strings.Join(paramPairsNoEncoding, "&")
 
-	// Sign the NON-encoded parameters
-	signature := c.sign(queryStringForSignature)
+	if c.settleAsset == "" {
+		c.settleAsset = "USDT"
+		if c.instrument == InstrumentCoinMargined {
+			c.settleAsset = "BTC"
+		}
+	}
 
-	// Second: Build parameters WITH URL encoding for the actual request
-	paramPairsEncoded := make([]string, 0, len(keys))
-	for _, key := range keys {
-		value := params[key]
-		// URL encode the value, replacing + with %20
-		encodedValue := url.QueryEscape(value)
-		encodedValue = strings.ReplaceAll(encodedValue, "+", "%20")
-		paramPairsEncoded = append(paramPairsEncoded, key+"="+encodedValue)
+	baseURL, streamURL := BaseURLProd, StreamURLProd
+	if c.env == broker.EnvironmentTestnet {
+		baseURL, streamURL = BaseURLDemo, StreamURLDemo
+	}
+	if c.baseURL == "" {
+		c.baseURL = baseURL
+	}
+	if c.streamURL == "" {
+		c.streamURL = streamURL
+	}
+	c.endpoints = endpointsFor(c.instrument)
+	c.logger = logging.OrDiscard(c.logger).With(logging.KeyBroker, "bingx")
+	c.log = componentLoggers{
+		transport: logging.Component(c.logger, logging.ComponentTransport),
+		streamer:  logging.Component(c.logger, logging.ComponentStreamer),
 	}
-	queryString := strings.Join(paramPairsEncoded, "&")
 
-	// Build full URL
-	fullURL := fmt.Sprintf("%s%s?%s&signature=%s", c.baseURL, endpoint, queryString, signature)
+	return c
+}
 
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
+// NewPublicClient creates a client without API credentials for market data
+// collection. Public calls (prices, funding rates, contracts, server time and
+// trade streams) work as usual; account and trading calls fail immediately
+// with broker.ErrAuthFailed.
+func NewPublicClient(demoMode bool, opts ...Option) *Client {
+	return NewClient("", "", demoMode, opts...)
+}
 
-	// Only add API key header
-	req.Header.Set("X-BX-APIKEY", c.apiKey)
+// Public reports whether the client was created without API credentials
+func (c *Client) Public() bool {
+	return c.public
+}
 
-	// Execute request
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, broker.NewBrokerError("bingx", "REQUEST_FAILED", "HTTP request failed", err)
-	}
-	defer resp.Body.Close()
+func init() {
+	broker.Register("bingx", open)
+}
 
-	// Read response
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, broker.NewBrokerError("bingx", "READ_FAILED", "Failed to read response", err)
+// open builds a Client for broker.Open. Options["instrument"] selects the
+// contract family (USDT-M or COIN-M), Options["settlement_asset"] the
+// balance reported (see WithSettlementAsset) and Options["key_type"] the
+// API key type (HMAC, RSA or Ed25519). Without either key it builds a public
+// client (see NewPublicClient).
+func open(cfg broker.Config) (broker.Broker, error) {
+	if (cfg.APIKey == "") != (cfg.SecretKey == "") {
+		return nil, broker.NewBrokerError("bingx", "CONFIG_ERROR", "API key and secret key are required", broker.ErrAuthFailed)
 	}
 
-	// Check HTTP status
-	if resp.StatusCode != http.StatusOK {
-		return nil, broker.NewBrokerError("bingx", "HTTP_ERROR",
-			fmt.Sprintf("HTTP %d: %s", resp.StatusCode, string(body)), nil)
+	var opts []Option
+	if cfg.BaseURL != "" {
+		opts = append(opts, WithBaseURL(cfg.BaseURL))
+	}
+	if cfg.StreamURL != "" {
+		opts = append(opts, WithStreamURL(cfg.StreamURL))
+	}
+	if instrument := cfg.Options["instrument"]; instrument != "" {
+		opts = append(opts, WithInstrumentType(InstrumentType(strings.ToUpper(instrument))))
+	}
+	if asset := cfg.Options["settlement_asset"]; asset != "" {
+		opts = append(opts, WithSettlementAsset(asset))
+	}
+	switch keyType := strings.ToLower(cfg.Options["key_type"]); keyType {
+	case "", "hmac":
+	case "rsa":
+		opts = append(opts, WithSigner(signing.RSA()))
+	case "ed25519":
+		opts = append(opts, WithSigner(signing.Ed25519()))
+	default:
+		return nil, broker.NewBrokerError("bingx", "CONFIG_ERROR", "Unsupported key_type "+keyType, broker.ErrNotSupported)
 	}
 
-	return body, nil
+	opts = append(opts, WithEnvironment(cfg.Env()))
+
+	return NewClient(cfg.APIKey, cfg.SecretKey, false, opts...), nil
+}
+
+// InstrumentType returns the contract family this client trades
+func (c *Client) InstrumentType() InstrumentType {
+	return c.instrument
+}
+
+// Environment returns the deployment the client connects to
+func (c *Client) Environment() broker.Environment {
+	return c.env
+}
+
+// Name returns the broker name
+func (c *Client) Name() string {
+	return "bingx"
+}
+
+// SupportedFeatures returns the features supported by BingX
+func (c *Client) SupportedFeatures() broker.Features {
+	return broker.Features{
+		TrailingStop:     true,
+		MultipleTP:       true,
+		BracketOrders:    true,
+		MaxLeverage:      125,
+		ReduceOnlyOrders: true,
+		Environments:     []broker.Environment{broker.EnvironmentProduction, broker.EnvironmentTestnet},
+		TimeInForces: []broker.TimeInForce{
+			broker.TimeInForceGTC, broker.TimeInForceIOC, broker.TimeInForceFOK, broker.TimeInForcePostOnly,
+		},
+	}
 }
@@ -1,20 +1,63 @@
 package bingx
 
+import "time"
+
 const (
 	// Base URLs
 	BaseURLProd = "https://open-api.bingx.com"
 	BaseURLDemo = "https://open-api-vst.bingx.com"
 
+	// Market data WebSocket URLs
+	StreamURLProd = "wss://open-api-swap.bingx.com/swap-market"
+	StreamURLDemo = "wss://vst-open-api-ws.bingx.com/swap-market"
+
 	// BingX API endpoints
-	EndpointBalance    = "/openApi/swap/v3/user/balance"
-	EndpointPositions  = "/openApi/swap/v2/user/positions"
-	EndpointPlaceOrder = "/openApi/swap/v2/trade/order"
-	EndpointOpenOrders = "/openApi/swap/v2/trade/openOrders"
-	EndpointCancelAll  = "/openApi/swap/v2/trade/allOpenOrders"
-	EndpointLeverage   = "/openApi/swap/v2/trade/leverage"
-	EndpointServerTime = "/openApi/swap/v2/server/time"
-	EndpointPrice      = "/openApi/swap/v1/ticker/price"
+	EndpointBalance      = "/openApi/swap/v3/user/balance"
+	EndpointPositions    = "/openApi/swap/v2/user/positions"
+	EndpointPlaceOrder   = "/openApi/swap/v2/trade/order"
+	EndpointTestOrder    = "/openApi/swap/v2/trade/order/test"
+	EndpointOpenOrders   = "/openApi/swap/v2/trade/openOrders"
+	EndpointCancelAll    = "/openApi/swap/v2/trade/allOpenOrders"
+	EndpointLeverage     = "/openApi/swap/v2/trade/leverage"
+	EndpointMargin       = "/openApi/swap/v2/trade/positionMargin"
+	EndpointServerTime   = "/openApi/swap/v2/server/time"
+	EndpointPrice        = "/openApi/swap/v1/ticker/price"
+	EndpointPremium      = "/openApi/swap/v2/quote/premiumIndex"
+	EndpointContracts    = "/openApi/swap/v2/quote/contracts"
+	EndpointTickers      = "/openApi/swap/v2/quote/ticker"
+	EndpointCommission   = "/openApi/swap/v2/user/commissionRate"
+	EndpointIncome       = "/openApi/swap/v2/user/income"
+	EndpointReplace      = "/openApi/swap/v1/trade/cancelReplace"
+	EndpointBatchReplace = "/openApi/swap/v1/trade/batchCancelReplace"
+	EndpointQueryOrder   = "/openApi/swap/v2/trade/order" // GET looks up one order
+
+	// BingX TWAP endpoints (USDT-margined only)
+	EndpointTWAPOrder      = "/openApi/swap/v1/twap/order"
+	EndpointTWAPOpenOrders = "/openApi/swap/v1/twap/openOrders"
+	EndpointTWAPCancel     = "/openApi/swap/v1/twap/cancelOrder"
+
+	// BingX coin-margined (inverse) perpetual endpoints
+	EndpointCoinBalance    = "/openApi/cswap/v1/user/balance"
+	EndpointCoinPositions  = "/openApi/cswap/v1/user/positions"
+	EndpointCoinPlaceOrder = "/openApi/cswap/v1/trade/order"
+	EndpointCoinOpenOrders = "/openApi/cswap/v1/trade/openOrders"
+	EndpointCoinCancelAll  = "/openApi/cswap/v1/trade/allOpenOrders"
+	EndpointCoinLeverage   = "/openApi/cswap/v1/trade/leverage"
+	EndpointCoinMargin     = "/openApi/cswap/v1/trade/positionMargin"
+	EndpointCoinPrice      = "/openApi/cswap/v1/market/ticker"
+	EndpointCoinQueryOrder = "/openApi/cswap/v1/trade/orderDetail"
+
+	// BingX wallet endpoints
+	EndpointTransfer        = "/openApi/api/v3/post/asset/transfer"
+	EndpointTransferHistory = "/openApi/api/v3/asset/transfer"
+	EndpointDepositAddress  = "/openApi/wallets/v1/capital/deposit/address"
+	EndpointDepositHistory  = "/openApi/api/v3/capital/deposit/hisrec"
+	EndpointWithdrawHistory = "/openApi/api/v3/capital/withdraw/history"
+
+	// FundingInterval is the settlement period of BingX perpetual funding
+	FundingInterval = 8 * time.Hour
 
 	// API response codes
-	APISuccessCode = 0
+	APISuccessCode        = 0
+	APISymbolNotExistCode = 109400
 )
@@ -0,0 +1,215 @@
+package bingx
+
+import (
+	"fmt"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// OrderSide is the BingX order action
+type OrderSide string
+
+const (
+	OrderSideBuy  OrderSide = "BUY"
+	OrderSideSell OrderSide = "SELL"
+)
+
+// PositionSide is the BingX position leg an order applies to
+type PositionSide string
+
+const (
+	PositionSideLong  PositionSide = "LONG"
+	PositionSideShort PositionSide = "SHORT"
+	PositionSideBoth  PositionSide = "BOTH" // One-way mode
+)
+
+// OrderType is a BingX wire order type
+type OrderType string
+
+const (
+	OrderTypeMarket             OrderType = "MARKET"
+	OrderTypeLimit              OrderType = "LIMIT"
+	OrderTypeStop               OrderType = "STOP"        // Stop-limit
+	OrderTypeStopMarket         OrderType = "STOP_MARKET" // Stop-market
+	OrderTypeTakeProfit         OrderType = "TAKE_PROFIT" // Take-profit limit
+	OrderTypeTakeProfitMarket   OrderType = "TAKE_PROFIT_MARKET"
+	OrderTypeTriggerLimit       OrderType = "TRIGGER_LIMIT"  // Conditional limit entry
+	OrderTypeTriggerMarket      OrderType = "TRIGGER_MARKET" // Conditional market entry
+	OrderTypeTrailingStopMarket OrderType = "TRAILING_STOP_MARKET"
+	OrderTypeTrailingTPSL       OrderType = "TRAILING_TP_SL"
+)
+
+// WorkingType is the price BingX watches to fire trigger orders
+type WorkingType string
+
+const (
+	WorkingTypeMarkPrice     WorkingType = "MARK_PRICE"
+	WorkingTypeContractPrice WorkingType = "CONTRACT_PRICE" // Last traded price
+	WorkingTypeIndexPrice    WorkingType = "INDEX_PRICE"
+)
+
+// TimeInForce is a BingX wire time-in-force
+type TimeInForce string
+
+const (
+	TimeInForceGTC      TimeInForce = "GTC"
+	TimeInForceIOC      TimeInForce = "IOC"
+	TimeInForceFOK      TimeInForce = "FOK"
+	TimeInForcePostOnly TimeInForce = "PostOnly"
+)
+
+// OrderStatus is a BingX wire order status
+type OrderStatus string
+
+const (
+	OrderStatusNew             OrderStatus = "NEW"
+	OrderStatusPending         OrderStatus = "PENDING"
+	OrderStatusPartiallyFilled OrderStatus = "PARTIALLY_FILLED"
+	OrderStatusFilled          OrderStatus = "FILLED"
+	OrderStatusCancelled       OrderStatus = "CANCELLED"
+	OrderStatusExpired         OrderStatus = "EXPIRED"
+)
+
+// orderTypes pairs broker order types with their BingX wire values. A
+// table rather than a switch keeps the mapping valid even when shared
+// constants happen to carry the same string value.
+var orderTypes = []struct {
+	broker broker.OrderType
+	wire   OrderType
+}{
+	{broker.OrderTypeMarket, OrderTypeMarket},
+	{broker.OrderTypeLimit, OrderTypeLimit},
+	{broker.OrderTypeStopMarket, OrderTypeStopMarket},
+	{broker.OrderTypeTakeProfitMarket, OrderTypeTakeProfitMarket},
+	{broker.OrderTypeTriggerLimit, OrderTypeTriggerLimit},
+	{broker.OrderTypeTriggerMarket, OrderTypeTriggerMarket},
+	{broker.OrderTypeTrailingStop, OrderTypeTrailingStopMarket},
+}
+
+// toBingXOrderType converts a broker order type to its BingX wire value.
+// Generic Stop/TakeProfit orders become limit variants when a limit price is
+// set and market variants otherwise.
+func toBingXOrderType(t broker.OrderType, hasLimitPrice bool) (OrderType, error) {
+	if t == broker.OrderTypeStop {
+		if hasLimitPrice {
+			return OrderTypeStop, nil
+		}
+		return OrderTypeStopMarket, nil
+	}
+	if t == broker.OrderTypeTakeProfit {
+		if hasLimitPrice {
+			return OrderTypeTakeProfit, nil
+		}
+		return OrderTypeTakeProfitMarket, nil
+	}
+
+	for _, m := range orderTypes {
+		if m.broker == t {
+			return m.wire, nil
+		}
+	}
+
+	return "", fmt.Errorf("unsupported order type %q", t)
+}
+
+// fromBingXOrderType converts a BingX wire order type to the broker type.
+// Unknown types are passed through unchanged.
+func fromBingXOrderType(t string) broker.OrderType {
+	switch OrderType(t) {
+	case OrderTypeStop:
+		return broker.OrderTypeStop
+	case OrderTypeTakeProfit:
+		return broker.OrderTypeTakeProfit
+	}
+
+	for _, m := range orderTypes {
+		if string(m.wire) == t {
+			return m.broker
+		}
+	}
+
+	return broker.OrderType(t)
+}
+
+// toBingXSides converts a broker side into the BingX order action and
+// position leg. Broker sides describe the direction of the order, so a
+// reduce-only SHORT closes the LONG leg (SELL + LONG) in hedge mode.
+func toBingXSides(side broker.Side, reduceOnly bool) (OrderSide, PositionSide) {
+	if side == broker.SideShort {
+		if reduceOnly {
+			return OrderSideSell, PositionSideLong
+		}
+		return OrderSideSell, PositionSideShort
+	}
+
+	if reduceOnly {
+		return OrderSideBuy, PositionSideShort
+	}
+	return OrderSideBuy, PositionSideLong
+}
+
+// fromBingXPositionSide converts a BingX position leg to the broker side
+func fromBingXPositionSide(positionSide string) broker.Side {
+	if PositionSide(positionSide) == PositionSideLong {
+		return broker.SideLong
+	}
+	return broker.SideShort
+}
+
+// toBingXWorkingType converts a broker working type, defaulting to mark price
+func toBingXWorkingType(w broker.WorkingType) WorkingType {
+	switch w {
+	case broker.WorkingTypeLast:
+		return WorkingTypeContractPrice
+	case broker.WorkingTypeIndex:
+		return WorkingTypeIndexPrice
+	}
+	return WorkingTypeMarkPrice
+}
+
+// isTriggerOrderType reports whether a BingX order type waits for a trigger price
+func isTriggerOrderType(t OrderType) bool {
+	switch t {
+	case OrderTypeStop, OrderTypeStopMarket, OrderTypeTakeProfit, OrderTypeTakeProfitMarket,
+		OrderTypeTriggerLimit, OrderTypeTriggerMarket:
+		return true
+	}
+	return false
+}
+
+// toBingXTimeInForce converts a broker time-in-force to its BingX wire value
+func toBingXTimeInForce(tif broker.TimeInForce) TimeInForce {
+	if tif == broker.TimeInForcePostOnly {
+		return TimeInForcePostOnly
+	}
+	return TimeInForce(tif)
+}
+
+// fromBingXTimeInForce converts a BingX wire time-in-force to the broker value
+func fromBingXTimeInForce(tif string) broker.TimeInForce {
+	if TimeInForce(tif) == TimeInForcePostOnly {
+		return broker.TimeInForcePostOnly
+	}
+	return broker.TimeInForce(tif)
+}
+
+// fromBingXStatus normalizes a BingX order status. For trigger orders
+// (STOP/TAKE_PROFIT/TRIGGER), "NEW" means pending trigger, not active.
+func fromBingXStatus(status string, orderType string) broker.OrderStatus {
+	switch OrderStatus(status) {
+	case OrderStatusNew:
+		if isTriggerOrderType(OrderType(orderType)) {
+			return broker.OrderStatusPending
+		}
+		return broker.OrderStatusNew
+	case OrderStatusPartiallyFilled:
+		return broker.OrderStatusPartiallyFilled
+	case OrderStatusFilled:
+		return broker.OrderStatusFilled
+	case OrderStatusCancelled:
+		return broker.OrderStatusCanceled
+	case OrderStatusExpired:
+		return broker.OrderStatusExpired
+	}
+	return broker.OrderStatus(status)
+}
@@ -0,0 +1,418 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestToBingXOrderType(t *testing.T) {
+	tests := []struct {
+		name          string
+		orderType     broker.OrderType
+		hasLimitPrice bool
+		want          OrderType
+		wantErr       bool
+	}{
+		{"Market", broker.OrderTypeMarket, false, OrderTypeMarket, false},
+		{"Limit", broker.OrderTypeLimit, true, OrderTypeLimit, false},
+		{"Stop without price is stop-market", broker.OrderTypeStop, false, OrderTypeStopMarket, false},
+		{"Stop with price is stop-limit", broker.OrderTypeStop, true, OrderTypeStop, false},
+		{"Take profit without price", broker.OrderTypeTakeProfit, false, OrderTypeTakeProfitMarket, false},
+		{"Take profit with price", broker.OrderTypeTakeProfit, true, OrderTypeTakeProfit, false},
+		{"Explicit stop-market", broker.OrderTypeStopMarket, false, OrderTypeStopMarket, false},
+		{"Explicit take-profit-market", broker.OrderTypeTakeProfitMarket, false, OrderTypeTakeProfitMarket, false},
+		{"Trigger limit", broker.OrderTypeTriggerLimit, true, OrderTypeTriggerLimit, false},
+		{"Trigger market", broker.OrderTypeTriggerMarket, false, OrderTypeTriggerMarket, false},
+		{"Trailing stop", broker.OrderTypeTrailingStop, false, OrderTypeTrailingStopMarket, false},
+		{"Unknown", broker.OrderType("ICEBERG"), false, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := toBingXOrderType(tt.orderType, tt.hasLimitPrice)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("toBingXOrderType() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("toBingXOrderType() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromBingXOrderType_RoundTrip(t *testing.T) {
+	for _, wire := range []OrderType{
+		OrderTypeMarket, OrderTypeLimit, OrderTypeStopMarket, OrderTypeTakeProfitMarket,
+		OrderTypeTriggerLimit, OrderTypeTriggerMarket, OrderTypeTrailingStopMarket,
+	} {
+		got, err := toBingXOrderType(fromBingXOrderType(string(wire)), false)
+		if err != nil {
+			t.Errorf("round trip %q error = %v", wire, err)
+			continue
+		}
+		if got != wire {
+			t.Errorf("round trip %q = %q", wire, got)
+		}
+	}
+
+	if got := fromBingXOrderType("SOMETHING_NEW"); got != broker.OrderType("SOMETHING_NEW") {
+		t.Errorf("unknown type = %q, want passthrough", got)
+	}
+}
+
+func TestToBingXSides(t *testing.T) {
+	tests := []struct {
+		name             string
+		side             broker.Side
+		reduceOnly       bool
+		wantSide         OrderSide
+		wantPositionSide PositionSide
+	}{
+		{"Open long", broker.SideLong, false, OrderSideBuy, PositionSideLong},
+		{"Open short", broker.SideShort, false, OrderSideSell, PositionSideShort},
+		{"Close long", broker.SideShort, true, OrderSideSell, PositionSideLong},
+		{"Close short", broker.SideLong, true, OrderSideBuy, PositionSideShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			side, positionSide := toBingXSides(tt.side, tt.reduceOnly)
+			if side != tt.wantSide || positionSide != tt.wantPositionSide {
+				t.Errorf("toBingXSides() = %s/%s, want %s/%s", side, positionSide, tt.wantSide, tt.wantPositionSide)
+			}
+			if got := isReduceOnly(string(side), string(positionSide)); got != tt.reduceOnly {
+				t.Errorf("isReduceOnly() = %v, want %v", got, tt.reduceOnly)
+			}
+		})
+	}
+}
+
+func TestFromBingXStatus(t *testing.T) {
+	tests := []struct {
+		status    string
+		orderType string
+		want      broker.OrderStatus
+	}{
+		{"NEW", "LIMIT", broker.OrderStatusNew},
+		{"NEW", "STOP_MARKET", broker.OrderStatusPending},
+		{"NEW", "TAKE_PROFIT", broker.OrderStatusPending},
+		{"NEW", "TRIGGER_LIMIT", broker.OrderStatusPending},
+		{"PARTIALLY_FILLED", "LIMIT", broker.OrderStatusPartiallyFilled},
+		{"FILLED", "MARKET", broker.OrderStatusFilled},
+		{"CANCELLED", "LIMIT", broker.OrderStatusCanceled},
+		{"EXPIRED", "LIMIT", broker.OrderStatusExpired},
+	}
+
+	for _, tt := range tests {
+		if got := fromBingXStatus(tt.status, tt.orderType); got != tt.want {
+			t.Errorf("fromBingXStatus(%q, %q) = %q, want %q", tt.status, tt.orderType, got, tt.want)
+		}
+	}
+}
+
+func TestToBingXWorkingType(t *testing.T) {
+	if got := toBingXWorkingType(broker.WorkingTypeLast); got != WorkingTypeContractPrice {
+		t.Errorf("last price = %q, want %q", got, WorkingTypeContractPrice)
+	}
+	if got := toBingXWorkingType(broker.WorkingTypeMark); got != WorkingTypeMarkPrice {
+		t.Errorf("mark price = %q, want %q", got, WorkingTypeMarkPrice)
+	}
+	if got := toBingXWorkingType(broker.WorkingTypeIndex); got != WorkingTypeIndexPrice {
+		t.Errorf("index price = %q, want %q", got, WorkingTypeIndexPrice)
+	}
+	if got := toBingXWorkingType(""); got != WorkingTypeMarkPrice {
+		t.Errorf("default = %q, want %q", got, WorkingTypeMarkPrice)
+	}
+}
+
+func TestClient_PlaceOrder_WireParams(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		got = r.PostForm
+		w.Write([]byte(`{"code":0,"msg":"","data":{"orderId":42,"symbol":"BTC-USDT","side":"SELL",
+			"positionSide":"LONG","type":"STOP_MARKET","origQty":"0.001","price":"0","status":"NEW"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	order, err := c.PlaceOrder(context.Background(), &broker.OrderRequest{
+		Symbol:     "BTC-USDT",
+		Side:       broker.SideShort,
+		Type:       broker.OrderTypeStop,
+		Size:       0.001,
+		StopPrice:  44000,
+		ReduceOnly: true,
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+
+	want := map[string]string{
+		"side":         "SELL",
+		"positionSide": "LONG",
+		"type":         "STOP_MARKET",
+		"reduceOnly":   "true",
+	}
+	for k, v := range want {
+		if got.Get(k) != v {
+			t.Errorf("param %s = %q, want %q", k, got.Get(k), v)
+		}
+	}
+
+	if order.Status != broker.OrderStatusPending {
+		t.Errorf("Status = %q, want %q", order.Status, broker.OrderStatusPending)
+	}
+	if !order.ReduceOnly {
+		t.Error("ReduceOnly = false, want true")
+	}
+}
+
+func TestClient_PlaceOrder_TriggerEntry(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveContracts(w, r) {
+			return
+		}
+		r.ParseForm()
+		got = r.PostForm
+		w.Write([]byte(`{"code":0,"msg":"","data":{"orderId":43,"symbol":"BTC-USDT","side":"BUY",
+			"positionSide":"LONG","type":"TRIGGER_LIMIT","origQty":"0.001","price":"51000","status":"NEW"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	ctx := broker.WithOrderOptions(context.Background(), broker.OrderOptions{WorkingType: broker.WorkingTypeLast, PriceProtect: true, ClientOrderID: "breakout-abc"})
+
+	order, err := c.PlaceOrder(ctx, &broker.OrderRequest{
+		Symbol:    "BTC-USDT",
+		Side:      broker.SideLong,
+		Type:      broker.OrderTypeTriggerLimit,
+		Size:      0.001,
+		Price:     51000,
+		StopPrice: 50500,
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+
+	want := map[string]string{
+		"type":          "TRIGGER_LIMIT",
+		"positionSide":  "LONG",
+		"stopPrice":     "50500.0",
+		"workingType":   "CONTRACT_PRICE",
+		"priceProtect":  "true",
+		"clientOrderID": "breakout-abc",
+	}
+	for k, v := range want {
+		if got.Get(k) != v {
+			t.Errorf("param %s = %q, want %q", k, got.Get(k), v)
+		}
+	}
+	if got.Has("reduceOnly") || order.Status != broker.OrderStatusPending || order.Type != broker.OrderTypeTriggerLimit ||
+		order.ClientOrderID != "breakout-abc" {
+		t.Errorf("order = %+v, params %v", order, got)
+	}
+
+	if _, err := c.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeTriggerMarket, Size: 0.001}); !errors.Is(err, broker.ErrInvalidPrice) {
+		t.Errorf("trigger without stop price: error = %v, want ErrInvalidPrice", err)
+	}
+}
+
+func TestClient_PlaceOrderTimeInForce(t *testing.T) {
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		got = r.PostForm
+		w.Write([]byte(`{"code":0,"msg":"","data":{"orderId":44,"symbol":"BTC-USDT","side":"BUY",
+			"positionSide":"LONG","type":"LIMIT","origQty":"0.001","price":"49000","timeInForce":"PostOnly","status":"NEW"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	req := &broker.OrderRequest{
+		Symbol:      "BTC-USDT",
+		Side:        broker.SideLong,
+		Type:        broker.OrderTypeLimit,
+		Size:        0.001,
+		Price:       49000,
+		TimeInForce: broker.TimeInForcePostOnly,
+	}
+
+	order, err := c.PlaceOrder(context.Background(), req)
+	if err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if got.Get("timeInForce") != "PostOnly" || order.TimeInForce != broker.TimeInForcePostOnly {
+		t.Errorf("timeInForce = %q, order %+v", got.Get("timeInForce"), order)
+	}
+
+	// GTD is rejected locally: BingX has no expiring orders
+	got = nil
+	req.TimeInForce = broker.TimeInForceGTD
+	ctx := broker.WithOrderOptions(context.Background(), broker.OrderOptions{ExpireTime: time.Now().Add(time.Hour)})
+	if _, err := c.PlaceOrder(ctx, req); !errors.Is(err, broker.ErrNotSupported) || got != nil {
+		t.Errorf("GTD: error = %v, request sent = %v", err, got != nil)
+	}
+
+	req.TimeInForce = broker.TimeInForcePostOnly
+	ctx = broker.WithOrderOptions(context.Background(), broker.OrderOptions{SelfTradePrevention: broker.STPExpireMaker})
+	if _, err := c.PlaceOrder(ctx, req); !errors.Is(err, broker.ErrNotSupported) || got != nil {
+		t.Errorf("STP: error = %v, request sent = %v", err, got != nil)
+	}
+}
+
+func TestProtectiveOrderJSON(t *testing.T) {
+	tests := []struct {
+		name        string
+		orderPrice  float64
+		workingType broker.WorkingType
+		want        protectiveOrder
+	}{
+		{
+			name: "Market stop loss on mark price",
+			want: protectiveOrder{Type: OrderTypeStopMarket, StopPrice: "44500", Price: "44500", WorkingType: WorkingTypeMarkPrice},
+		},
+		{
+			name:        "Limit stop loss on last price",
+			orderPrice:  44400,
+			workingType: broker.WorkingTypeLast,
+			want:        protectiveOrder{Type: OrderTypeStop, StopPrice: "44500", Price: "44400", WorkingType: WorkingTypeContractPrice},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := protectiveOrderJSON(unknownPrecision, OrderTypeStopMarket, OrderTypeStop, 44500, tt.orderPrice, tt.workingType)
+			if err != nil {
+				t.Fatalf("protectiveOrderJSON() error = %v", err)
+			}
+
+			var got protectiveOrder
+			if err := json.Unmarshal([]byte(data), &got); err != nil {
+				t.Fatalf("invalid JSON %s: %v", data, err)
+			}
+			if got != tt.want {
+				t.Errorf("protectiveOrderJSON() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_ReplaceOrder(t *testing.T) {
+	response := `{"code":0,"msg":"","data":{"cancelResult":"true","replaceResult":"true",
+		"newOrderResponse":{"orderId":44,"symbol":"BTC-USDT","side":"BUY","positionSide":"LONG","type":"LIMIT","origQty":"0.001","price":"45500","status":"NEW"}}}`
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveContracts(w, r) {
+			return
+		}
+		if r.URL.Path != EndpointReplace {
+			t.Errorf("path = %s, want %s", r.URL.Path, EndpointReplace)
+		}
+		r.ParseForm()
+		got = r.PostForm
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	req := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 0.001, Price: 45500}
+
+	order, err := broker.ReplaceOrder(context.Background(), c, "BTC-USDT", "43", req, broker.ReplaceOptions{})
+	if err != nil || order.ID != "44" || order.Price != 45500 {
+		t.Fatalf("ReplaceOrder() = %+v, %v", order, err)
+	}
+	if got.Get("cancelOrderId") != "43" || got.Get("cancelReplaceMode") != "STOP_ON_FAILURE" || got.Get("type") != "LIMIT" {
+		t.Errorf("params = %v", got)
+	}
+
+	response = `{"code":0,"msg":"","data":{"cancelResult":false,"cancelMsg":"order not exist","replaceResult":false}}`
+	if _, err := c.ReplaceOrder(context.Background(), "BTC-USDT", "43", req); !errors.Is(err, broker.ErrOrderNotFound) {
+		t.Errorf("ReplaceOrder(filled) error = %v, want ErrOrderNotFound", err)
+	}
+}
+
+func TestClient_ReplaceOrders(t *testing.T) {
+	response := `{"code":0,"msg":"","data":[
+		{"cancelResult":"true","replaceResult":"true","newOrderResponse":{"orderId":46,"symbol":"BTC-USDT","side":"BUY","positionSide":"LONG","type":"LIMIT","origQty":"0.001","price":"44950","status":"NEW"}},
+		{"cancelResult":false,"cancelMsg":"order not exist","replaceResult":false}]}`
+	var batch []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveContracts(w, r) {
+			return
+		}
+		if r.URL.Path != EndpointBatchReplace {
+			t.Errorf("path = %s, want %s", r.URL.Path, EndpointBatchReplace)
+		}
+		r.ParseForm()
+		json.Unmarshal([]byte(r.PostForm.Get("batchOrders")), &batch)
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	quote := func(side broker.Side, price float64) *broker.OrderRequest {
+		return &broker.OrderRequest{Symbol: "BTC-USDT", Side: side, Type: broker.OrderTypeLimit, Size: 0.001, Price: price}
+	}
+
+	results, err := broker.ReplaceOrders(context.Background(), c, "BTC-USDT", []broker.Replacement{
+		{OrderID: "43", Order: quote(broker.SideLong, 44950)},
+		{OrderID: "44", Order: quote(broker.SideShort, 45150)},
+	}, broker.ReplaceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(batch) != 2 || batch[0]["cancelOrderId"] != "43" || batch[1]["side"] != "SELL" || batch[1]["cancelReplaceMode"] != "STOP_ON_FAILURE" {
+		t.Errorf("batchOrders = %v", batch)
+	}
+	if results[0].Err != nil || results[0].Order.ID != "46" || !errors.Is(results[1].Err, broker.ErrOrderNotFound) {
+		t.Errorf("results = %+v, want the first replaced and the second gone", results)
+	}
+}
+
+func TestClient_ValidateOrder(t *testing.T) {
+	response := `{"code":0,"msg":"","data":{"order":{"orderId":0,"symbol":"BTC-USDT","side":"BUY","positionSide":"LONG","type":"MARKET","origQty":"0.001"}}}`
+	var got url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if serveContracts(w, r) {
+			return
+		}
+		if r.URL.Path != EndpointTestOrder {
+			t.Errorf("path = %s, want %s", r.URL.Path, EndpointTestOrder)
+		}
+		r.ParseForm()
+		got = r.PostForm
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	req := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 0.001}
+	if err := broker.ValidateOrder(context.Background(), c, req); err != nil {
+		t.Fatalf("ValidateOrder() = %v", err)
+	}
+	if got.Get("type") != "MARKET" || got.Get("positionSide") != "LONG" {
+		t.Errorf("params = %v", got)
+	}
+
+	response = `{"code":101204,"msg":"Insufficient margin"}`
+	var brokerErr *broker.BrokerError
+	if err := c.ValidateOrder(context.Background(), req); !errors.As(err, &brokerErr) || brokerErr.Code != "API_101204" {
+		t.Errorf("ValidateOrder(rejected) = %v, want API_101204", err)
+	}
+
+	coin := NewClient("key", "secret", false, WithBaseURL(server.URL), WithInstrumentType(InstrumentCoinMargined))
+	if err := coin.ValidateOrder(context.Background(), req); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("ValidateOrder(coin-margined) = %v, want ErrNotSupported", err)
+	}
+}
@@ -0,0 +1,270 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/cache"
+)
+
+// Cache keys of semi-static data
+const (
+	cacheKeyContracts  = "contracts"
+	cacheKeyCommission = "commission"
+	cacheKeyTimeOffset = "timeOffset"
+	cacheKeyLeverage   = "leverage:" // + symbol
+)
+
+// Contract describes a perpetual contract's trading rules
+type Contract struct {
+	Symbol            string
+	Asset             string
+	Currency          string
+	PricePrecision    int
+	QuantityPrecision int
+	MinQuantity       float64
+	MinNotional       float64 // Minimum order value in USDT
+	MaxLongLeverage   int
+	MaxShortLeverage  int
+	FeeRate           float64
+	Tradable          bool // Listed and open for API trading
+	CloseOnly         bool // Listed, but the API only accepts closing orders
+	Status            int  // Exchange listing status: 1 online, 5 pre-launch
+	LaunchTime        time.Time
+	DelistTime        time.Time // Zero unless a delisting is scheduled
+}
+
+// LeverageInfo holds the current and maximum leverage of a symbol
+type LeverageInfo struct {
+	Symbol           string
+	LongLeverage     int
+	ShortLeverage    int
+	MaxLongLeverage  int
+	MaxShortLeverage int
+}
+
+// CommissionRate holds the account's fee rates as fractions of notional
+type CommissionRate struct {
+	Taker float64
+	Maker float64
+}
+
+// Cache returns the client's semi-static data cache, or nil when WithCache
+// was not used. Use it to register OnInvalidate hooks or drop entries after
+// out-of-band changes. Keys are "contracts", "commission", "timeOffset" and
+// "leverage:<symbol>"; SetLeverage invalidates the symbol's leverage entry.
+func (c *Client) Cache() *cache.TTL[string, any] {
+	return c.cache
+}
+
+// cached returns the value cached under key, loading it on a miss
+func cached[T any](ctx context.Context, c *Client, key string, load func(context.Context) (T, error)) (T, error) {
+	v, err := c.cache.GetOrLoad(ctx, key, func(ctx context.Context) (any, error) {
+		return load(ctx)
+	})
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return v.(T), nil
+}
+
+// GetContracts lists the USDT-margined perpetual contracts. The slice is a
+// copy the caller may modify, even when served from the cache.
+func (c *Client) GetContracts(ctx context.Context) ([]Contract, error) {
+	if c.instrument != InstrumentUSDTMargined {
+		return nil, broker.ErrNotSupported
+	}
+	contracts, err := cached(ctx, c, cacheKeyContracts, c.fetchContracts)
+	return slices.Clone(contracts), err
+}
+
+// GetContract returns the contract specification of symbol
+func (c *Client) GetContract(ctx context.Context, symbol string) (*Contract, error) {
+	contracts, err := c.GetContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range contracts {
+		if contracts[i].Symbol == symbol {
+			contract := contracts[i]
+			return &contract, nil
+		}
+	}
+	return nil, broker.ErrInvalidSymbol
+}
+
+func (c *Client) fetchContracts(ctx context.Context) ([]Contract, error) {
+	body, err := c.makePublicRequest(ctx, "GET", EndpointContracts, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response ContractsResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse contracts response", err)
+	}
+
+	if response.Code != APISuccessCode {
+		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	}
+
+	contracts := make([]Contract, 0, len(response.Data))
+	for _, d := range response.Data {
+		contracts = append(contracts, Contract{
+			Symbol:            d.Symbol,
+			Asset:             d.Asset,
+			Currency:          d.Currency,
+			PricePrecision:    d.PricePrecision,
+			QuantityPrecision: d.QuantityPrecision,
+			MinQuantity:       d.TradeMinQuantity.Float64(),
+			MinNotional:       d.TradeMinUSDT.Float64(),
+			MaxLongLeverage:   d.MaxLongLeverage,
+			MaxShortLeverage:  d.MaxShortLeverage,
+			FeeRate:           d.FeeRate.Float64(),
+			Tradable:          d.Status == 1 && d.APIStateOpen == "true",
+			CloseOnly:         d.Status == 1 && d.APIStateOpen != "true" && d.APIStateClose == "true",
+			Status:            d.Status,
+			LaunchTime:        unixMilli(d.LaunchTime),
+			DelistTime:        unixMilli(d.OffTime),
+		})
+	}
+
+	return contracts, nil
+}
+
+// GetLeverage returns the current and maximum leverage of a USDT-margined
+// symbol
+func (c *Client) GetLeverage(ctx context.Context, symbol string) (*LeverageInfo, error) {
+	if c.instrument != InstrumentUSDTMargined {
+		return nil, broker.ErrNotSupported
+	}
+
+	return cached(ctx, c, cacheKeyLeverage+symbol, func(ctx context.Context) (*LeverageInfo, error) {
+		body, err := c.makeRequest(ctx, "GET", EndpointLeverage, map[string]string{"symbol": symbol})
+		if err != nil {
+			return nil, err
+		}
+
+		var response LeverageInfoResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse leverage response", err)
+		}
+
+		if response.Code != APISuccessCode {
+			return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+		}
+
+		return &LeverageInfo{
+			Symbol:           symbol,
+			LongLeverage:     response.Data.LongLeverage,
+			ShortLeverage:    response.Data.ShortLeverage,
+			MaxLongLeverage:  response.Data.MaxLongLeverage,
+			MaxShortLeverage: response.Data.MaxShortLeverage,
+		}, nil
+	})
+}
+
+// GetCommissionRate returns the account's USDT-margined perpetual fee rates
+func (c *Client) GetCommissionRate(ctx context.Context) (*CommissionRate, error) {
+	if c.instrument != InstrumentUSDTMargined {
+		return nil, broker.ErrNotSupported
+	}
+
+	return cached(ctx, c, cacheKeyCommission, func(ctx context.Context) (*CommissionRate, error) {
+		body, err := c.makeRequest(ctx, "GET", EndpointCommission, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var response CommissionRateResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse commission rate response", err)
+		}
+
+		if response.Code != APISuccessCode {
+			return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+		}
+
+		return &CommissionRate{
+			Taker: response.Data.Commission.TakerCommissionRate.Float64(),
+			Maker: response.Data.Commission.MakerCommissionRate.Float64(),
+		}, nil
+	})
+}
+
+// ServerTimeOffset returns how far the exchange clock is ahead of the local
+// clock, measured against the midpoint of the request round trip. Signed
+// requests timestamp with the offset last measured here, by Status or by
+// Ping, so calling it at startup keeps a skewed local clock within the
+// exchange's recvWindow.
+func (c *Client) ServerTimeOffset(ctx context.Context) (time.Duration, error) {
+	return cached(ctx, c, cacheKeyTimeOffset, func(ctx context.Context) (time.Duration, error) {
+		_, offset, _, err := c.serverTime(ctx)
+		return offset, err
+	})
+}
+
+// Ping checks that the API is reachable
+func (c *Client) Ping(ctx context.Context) error {
+	_, _, _, err := c.serverTime(ctx)
+	return err
+}
+
+// Status reports API latency and clock drift. Maintenance responses (HTTP
+// 503, maintenance pages) are reported as maintenance rather than an error.
+func (c *Client) Status(ctx context.Context) (*broker.ExchangeStatus, error) {
+	checkedAt := time.Now()
+	serverTime, drift, latency, err := c.serverTime(ctx)
+
+	if errors.Is(err, broker.ErrMaintenance) {
+		return &broker.ExchangeStatus{Latency: time.Since(checkedAt), Maintenance: true, CheckedAt: checkedAt}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &broker.ExchangeStatus{
+		Latency:    latency,
+		ServerTime: serverTime,
+		ClockDrift: drift,
+		CheckedAt:  checkedAt,
+	}, nil
+}
+
+// serverTime fetches the exchange clock and returns it with its offset from
+// the local clock and the request latency
+func (c *Client) serverTime(ctx context.Context) (serverTime time.Time, offset, latency time.Duration, err error) {
+	sent := time.Now()
+	body, err := c.makePublicRequest(ctx, "GET", EndpointServerTime, nil)
+	if err != nil {
+		return time.Time{}, 0, 0, err
+	}
+	latency = time.Since(sent)
+
+	var response ServerTimeResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return time.Time{}, 0, 0, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse server time response", err)
+	}
+
+	if response.Code != APISuccessCode {
+		return time.Time{}, 0, 0, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	}
+
+	serverTime = time.UnixMilli(response.Data.ServerTime)
+	offset = serverTime.Sub(sent.Add(latency / 2))
+	c.clockOffset.Store(int64(offset))
+	return serverTime, offset, latency, nil
+}
+
+// now returns the local time corrected by the last measured server time
+// offset, for request timestamps
+func (c *Client) now() time.Time {
+	return time.Now().Add(time.Duration(c.clockOffset.Load()))
+}
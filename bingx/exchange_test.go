@@ -0,0 +1,161 @@
+package bingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestClient_CachedExchangeData(t *testing.T) {
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits[r.Method+" "+r.URL.Path]++
+		switch r.URL.Path {
+		case EndpointContracts:
+			w.Write([]byte(`{"code":0,"data":[{"symbol":"BTC-USDT","asset":"BTC","currency":"USDT","pricePrecision":1,
+				"quantityPrecision":4,"feeRate":0.0005,"tradeMinQuantity":0.0001,"tradeMinUSDT":2,
+				"maxLongLeverage":125,"maxShortLeverage":100,"status":1,"apiStateOpen":"true","apiStateClose":"true"}]}`))
+		case EndpointLeverage:
+			w.Write([]byte(`{"code":0,"data":{"longLeverage":10,"shortLeverage":5,"maxLongLeverage":125,"maxShortLeverage":125}}`))
+		case EndpointCommission:
+			w.Write([]byte(`{"code":0,"data":{"commission":{"takerCommissionRate":"0.0005","makerCommissionRate":"0.0002"}}}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL), WithCache(time.Minute))
+	ctx := context.Background()
+
+	for range 2 {
+		contract, err := c.GetContract(ctx, "BTC-USDT")
+		if err != nil {
+			t.Fatalf("GetContract() error = %v", err)
+		}
+		if contract.QuantityPrecision != 4 || contract.MinNotional != 2 || contract.MaxLongLeverage != 125 || !contract.Tradable {
+			t.Errorf("GetContract() = %+v", contract)
+		}
+
+		lev, err := c.GetLeverage(ctx, "BTC-USDT")
+		if err != nil || lev.LongLeverage != 10 || lev.MaxShortLeverage != 125 {
+			t.Errorf("GetLeverage() = %+v, %v", lev, err)
+		}
+
+		fees, err := c.GetCommissionRate(ctx)
+		if err != nil || fees.Taker != 0.0005 || fees.Maker != 0.0002 {
+			t.Errorf("GetCommissionRate() = %+v, %v", fees, err)
+		}
+	}
+
+	if hits["GET "+EndpointContracts] != 1 || hits["GET "+EndpointLeverage] != 1 || hits["GET "+EndpointCommission] != 1 {
+		t.Errorf("hits = %v, want one request per endpoint", hits)
+	}
+
+	var invalidated []string
+	c.Cache().OnInvalidate(func(key string) { invalidated = append(invalidated, key) })
+
+	if err := c.SetLeverage(ctx, "BTC-USDT", "LONG", 20); err != nil {
+		t.Fatalf("SetLeverage() error = %v", err)
+	}
+	c.GetLeverage(ctx, "BTC-USDT")
+	if hits["GET "+EndpointLeverage] != 2 {
+		t.Errorf("leverage fetched %d times, want refetch after SetLeverage", hits["GET "+EndpointLeverage])
+	}
+	if len(invalidated) != 1 || invalidated[0] != "leverage:BTC-USDT" {
+		t.Errorf("invalidated = %v", invalidated)
+	}
+}
+
+func TestClient_ServerTimeOffset(t *testing.T) {
+	var timestamp int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != EndpointServerTime {
+			timestamp, _ = strconv.ParseInt(r.URL.Query().Get("timestamp"), 10, 64)
+			w.Write([]byte(`{"code":0,"data":{}}`))
+			return
+		}
+		serverTime := time.Now().Add(3 * time.Second).UnixMilli()
+		w.Write([]byte(`{"code":0,"data":{"serverTime":` + strconv.FormatInt(serverTime, 10) + `}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	offset, err := c.ServerTimeOffset(context.Background())
+	if err != nil {
+		t.Fatalf("ServerTimeOffset() error = %v", err)
+	}
+	if offset < 2900*time.Millisecond || offset > 3100*time.Millisecond {
+		t.Errorf("ServerTimeOffset() = %v, want ~3s", offset)
+	}
+
+	// Signed requests are timestamped on the exchange clock
+	if _, err := c.Raw(context.Background(), http.MethodGet, EndpointBalance, nil); err != nil {
+		t.Fatalf("Raw() error = %v", err)
+	}
+	if ahead := time.Until(time.UnixMilli(timestamp)); ahead < 2900*time.Millisecond || ahead > 3100*time.Millisecond {
+		t.Errorf("request timestamp is %v ahead of the local clock, want ~3s", ahead)
+	}
+}
+
+func TestClient_GetContracts_Copy(t *testing.T) {
+	var hits int
+	server := contractsServer(t, &hits)
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL), WithCache(time.Minute))
+	ctx := context.Background()
+
+	contracts, err := c.GetContracts(ctx)
+	if err != nil || len(contracts) == 0 {
+		t.Fatalf("GetContracts() = %v, %v", contracts, err)
+	}
+	symbol := contracts[0].Symbol
+	contracts[0].Symbol = "MUTATED"
+
+	again, err := c.GetContracts(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again[0].Symbol != symbol || hits != 1 {
+		t.Errorf("cached contracts[0] = %q after %d fetches, want %q unchanged", again[0].Symbol, hits, symbol)
+	}
+}
+
+func TestClient_Status(t *testing.T) {
+	maintenance := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if maintenance {
+			http.Error(w, "system maintenance", http.StatusServiceUnavailable)
+			return
+		}
+		serverTime := time.Now().Add(-time.Second).UnixMilli()
+		w.Write([]byte(`{"code":0,"data":{"serverTime":` + strconv.FormatInt(serverTime, 10) + `}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	if err := c.Ping(ctx); err != nil {
+		t.Fatalf("Ping() error = %v", err)
+	}
+
+	status, err := c.Status(ctx)
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Maintenance || status.ClockDrift > -900*time.Millisecond || status.ClockDrift < -1100*time.Millisecond || status.Latency <= 0 {
+		t.Errorf("Status() = %+v, want ~-1s drift", status)
+	}
+	if status.Healthy(500*time.Millisecond) || !status.Healthy(2*time.Second) {
+		t.Errorf("Healthy() does not respect max drift for %v", status.ClockDrift)
+	}
+
+	maintenance = true
+	if status, err := c.Status(ctx); err != nil || !status.Maintenance {
+		t.Errorf("Status() = %+v, %v, want maintenance", status, err)
+	}
+	if err := c.Ping(ctx); err == nil {
+		t.Error("Ping() error = nil during maintenance")
+	}
+}
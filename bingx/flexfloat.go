@@ -0,0 +1,81 @@
+package bingx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strconv"
+)
+
+// FlexFloat is a float64 that BingX may encode either as a JSON number or
+// as a numeric string. Empty strings and null decode to zero, and so do
+// non-numeric strings, so one odd field doesn't fail the whole response;
+// those are logged to slog.Default, as decoding has no client logger. Other
+// JSON types are an error.
+type FlexFloat float64
+
+// UnmarshalJSON implements json.Unmarshaler
+func (f *FlexFloat) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+
+	if len(data) == 0 || bytes.Equal(data, []byte("null")) {
+		*f = 0
+		return nil
+	}
+
+	// Quoted value: unwrap and parse the string contents
+	if data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return fmt.Errorf("unable to parse number: %s", string(data))
+		}
+		if s == "" {
+			*f = 0
+			return nil
+		}
+		v, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			slog.Default().Warn("bingx: non-numeric string decoded as 0", "value", s)
+			v = 0
+		}
+		*f = FlexFloat(v)
+		return nil
+	}
+
+	v, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("unable to parse number: %s", string(data))
+	}
+	*f = FlexFloat(v)
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, always encoding as a JSON number
+func (f FlexFloat) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.FormatFloat(float64(f), 'f', -1, 64)), nil
+}
+
+// Float64 returns the value as a float64
+func (f FlexFloat) Float64() float64 {
+	return float64(f)
+}
+
+// FlexBool is a bool that BingX may encode either as a JSON boolean or as
+// the string "true"/"false". Empty strings and null decode to false.
+type FlexBool bool
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *FlexBool) UnmarshalJSON(data []byte) error {
+	s := string(bytes.Trim(bytes.TrimSpace(data), `"`))
+	if s == "" || s == "null" {
+		*b = false
+		return nil
+	}
+	v, err := strconv.ParseBool(s)
+	if err != nil {
+		return fmt.Errorf("unable to parse bool: %s", string(data))
+	}
+	*b = FlexBool(v)
+	return nil
+}
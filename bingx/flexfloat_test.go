@@ -0,0 +1,159 @@
+package bingx
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestFlexFloat_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		want    float64
+		wantErr bool
+	}{
+		{
+			name: "Number as string",
+			json: `"10"`,
+			want: 10.0,
+		},
+		{
+			name: "Integer number",
+			json: `25`,
+			want: 25.0,
+		},
+		{
+			name: "Float string",
+			json: `"50.5"`,
+			want: 50.5,
+		},
+		{
+			name: "Float number",
+			json: `125.0`,
+			want: 125.0,
+		},
+		{
+			name: "Very precise string",
+			json: `"45123.456789"`,
+			want: 45123.456789,
+		},
+		{
+			name: "Very low price",
+			json: `"0.01"`,
+			want: 0.01,
+		},
+		{
+			name: "Negative string",
+			json: `"-12.5"`,
+			want: -12.5,
+		},
+		{
+			name: "Empty string",
+			json: `""`,
+			want: 0,
+		},
+		{
+			name: "Null",
+			json: `null`,
+			want: 0,
+		},
+		{
+			name: "Non-numeric string",
+			json: `"invalid"`,
+			want: 0,
+		},
+		{
+			name: "Placeholder string",
+			json: `"--"`,
+			want: 0,
+		},
+		{
+			name:    "Boolean true",
+			json:    `true`,
+			wantErr: true,
+		},
+		{
+			name:    "Boolean false",
+			json:    `false`,
+			wantErr: true,
+		},
+		{
+			name:    "Object",
+			json:    `{"value":1}`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got FlexFloat
+			err := json.Unmarshal([]byte(tt.json), &got)
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Unmarshal(%s) error = nil, want error", tt.json)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Unmarshal(%s) error = %v, want nil", tt.json, err)
+			}
+			if got.Float64() != tt.want {
+				t.Errorf("Unmarshal(%s) = %v, want %v", tt.json, got.Float64(), tt.want)
+			}
+		})
+	}
+}
+
+func TestFlexFloat_InStruct(t *testing.T) {
+	var v struct {
+		A FlexFloat `json:"a"`
+		B FlexFloat `json:"b"`
+		C FlexFloat `json:"c"`
+	}
+
+	// Missing fields stay zero
+	if err := json.Unmarshal([]byte(`{"a":"1.5","b":2}`), &v); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if v.A != 1.5 || v.B != 2 || v.C != 0 {
+		t.Errorf("got a=%v b=%v c=%v, want 1.5 2 0", v.A, v.B, v.C)
+	}
+
+	// A non-numeric field doesn't fail the others
+	if err := json.Unmarshal([]byte(`{"a":"3","b":"n/a","c":"4.5"}`), &v); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if v.A != 3 || v.B != 0 || v.C != 4.5 {
+		t.Errorf("got a=%v b=%v c=%v, want 3 0 4.5", v.A, v.B, v.C)
+	}
+}
+
+func TestFlexFloat_MarshalJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		V FlexFloat `json:"v"`
+	}{V: 42.25})
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(data) != `{"v":42.25}` {
+		t.Errorf("Marshal() = %s, want %s", data, `{"v":42.25}`)
+	}
+}
+
+func TestPositionData_CompatibilityShims(t *testing.T) {
+	var pos PositionData
+	if err := json.Unmarshal([]byte(`{"leverage":"20","liquidationPrice":1850.5}`), &pos); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	lev, err := pos.GetLeverageFloat()
+	if err != nil || lev != 20 {
+		t.Errorf("GetLeverageFloat() = %v, %v; want 20, nil", lev, err)
+	}
+	liq, err := pos.GetLiquidationPriceFloat()
+	if err != nil || liq != 1850.5 {
+		t.Errorf("GetLiquidationPriceFloat() = %v, %v; want 1850.5, nil", liq, err)
+	}
+}
@@ -0,0 +1,80 @@
+package bingx
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest/golden"
+)
+
+// Golden-file contract tests over captured BingX responses. Add a fixture
+// to testdata/golden/<call>/ and run `go test ./bingx -run Golden -update`
+// to record what the parser makes of it.
+func TestGolden(t *testing.T) {
+	ctx := context.Background()
+	newClient := func(fixture *http.Client) *Client {
+		return NewClient("key", "secret", false, WithHTTPClient(fixture))
+	}
+
+	t.Run("balance", func(t *testing.T) {
+		golden.Run(t, "testdata/golden/balance", func(t *testing.T, fixture *http.Client) (any, error) {
+			balance, err := newClient(fixture).GetBalance(ctx)
+			if balance != nil {
+				balance.Timestamp = time.Time{}
+			}
+			return balance, err
+		})
+	})
+
+	t.Run("positions", func(t *testing.T) {
+		golden.Run(t, "testdata/golden/positions", func(t *testing.T, fixture *http.Client) (any, error) {
+			positions, err := newClient(fixture).GetPositions(ctx, nil)
+			for _, p := range positions {
+				p.Timestamp = time.Time{}
+			}
+			return positions, err
+		})
+	})
+
+	t.Run("orders", func(t *testing.T) {
+		golden.Run(t, "testdata/golden/orders", func(t *testing.T, fixture *http.Client) (any, error) {
+			orders, err := newClient(fixture).GetOrders(ctx, nil)
+			for _, o := range orders {
+				o.CreatedAt = o.CreatedAt.UTC()
+				o.UpdatedAt = o.UpdatedAt.UTC()
+			}
+			return orders, err
+		})
+	})
+
+	t.Run("place_order", func(t *testing.T) {
+		golden.Run(t, "testdata/golden/place_order", func(t *testing.T, fixture *http.Client) (any, error) {
+			order, err := newClient(fixture).PlaceOrder(ctx, &broker.OrderRequest{
+				Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 0.01, Price: 100000,
+			})
+			if order != nil {
+				order.CreatedAt, order.UpdatedAt = time.Time{}, time.Time{}
+			}
+			return order, err
+		})
+	})
+
+	t.Run("price", func(t *testing.T) {
+		golden.Run(t, "testdata/golden/price", func(t *testing.T, fixture *http.Client) (any, error) {
+			return newClient(fixture).GetCurrentPrice(ctx, "BTC-USDT")
+		})
+	})
+
+	t.Run("funding", func(t *testing.T) {
+		golden.Run(t, "testdata/golden/funding", func(t *testing.T, fixture *http.Client) (any, error) {
+			rate, err := newClient(fixture).GetFundingRate(ctx, "BTC-USDT")
+			if rate != nil {
+				rate.NextFundingTime = rate.NextFundingTime.UTC()
+			}
+			return rate, err
+		})
+	})
+}
@@ -0,0 +1,112 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// IncomeType classifies an account income record
+type IncomeType string
+
+const (
+	IncomeRealizedPnL IncomeType = "REALIZED_PNL"
+	IncomeFundingFee  IncomeType = "FUNDING_FEE"
+	IncomeTradingFee  IncomeType = "TRADING_FEE"
+	IncomeTransfer    IncomeType = "TRANSFER"
+)
+
+// Income is one change to the futures account balance: realized PnL, a
+// funding payment, a fee or a transfer
+type Income struct {
+	ID      string
+	Symbol  string
+	Type    IncomeType
+	Asset   string
+	Amount  float64 // Positive when received, negative when paid
+	TradeID string
+	Time    time.Time
+}
+
+// IncomeFilter narrows income history queries
+type IncomeFilter struct {
+	Symbol    string     // Empty = all symbols
+	Type      IncomeType // Empty = all types
+	StartTime time.Time
+	EndTime   time.Time
+	PageSize  int // Records per request (0 = 1000)
+}
+
+// GetIncomeHistory returns an iterator over account income, oldest first.
+// Use Type IncomeFundingFee to load funding payments for
+// analytics.AttributeFunding.
+func (c *Client) GetIncomeHistory(filter IncomeFilter) *broker.Iterator[*Income] {
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+
+	fetch := func(ctx context.Context, req broker.PageRequest) ([]*Income, *broker.PageRequest, error) {
+		if c.instrument == InstrumentCoinMargined {
+			return nil, nil, broker.ErrNotSupported
+		}
+
+		params := map[string]string{"limit": strconv.Itoa(req.Limit)}
+		if filter.Symbol != "" {
+			params["symbol"] = filter.Symbol
+		}
+		if filter.Type != "" {
+			params["incomeType"] = string(filter.Type)
+		}
+		if !req.StartTime.IsZero() {
+			params["startTime"] = strconv.FormatInt(req.StartTime.UnixMilli(), 10)
+		}
+		if !req.EndTime.IsZero() {
+			params["endTime"] = strconv.FormatInt(req.EndTime.UnixMilli(), 10)
+		}
+
+		body, err := c.makeRequest(ctx, "GET", EndpointIncome, params)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		var response IncomeResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse income response", err)
+		}
+		if response.Code != APISuccessCode {
+			return nil, nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+		}
+
+		incomes := make([]*Income, 0, len(response.Data))
+		for _, r := range response.Data {
+			incomes = append(incomes, &Income{
+				ID:      r.TranID,
+				Symbol:  r.Symbol,
+				Type:    IncomeType(r.IncomeType),
+				Asset:   r.Asset,
+				Amount:  r.Income.Float64(),
+				TradeID: r.TradeID,
+				Time:    time.UnixMilli(r.Time),
+			})
+		}
+		slices.SortStableFunc(incomes, func(a, b *Income) int { return a.Time.Compare(b.Time) })
+
+		// The endpoint pages by time: a full page continues after its newest record
+		if len(incomes) < req.Limit {
+			return incomes, nil, nil
+		}
+		return incomes, req.After(incomes[len(incomes)-1].Time), nil
+	}
+
+	return broker.NewIterator(fetch, broker.PageRequest{
+		Limit:     pageSize,
+		StartTime: filter.StartTime,
+		EndTime:   filter.EndTime,
+	})
+}
@@ -0,0 +1,47 @@
+package bingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_GetIncomeHistory(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		q := r.URL.Query()
+		if r.URL.Path != EndpointIncome || q.Get("incomeType") != "FUNDING_FEE" || q.Get("limit") != "2" {
+			t.Errorf("request = %s", r.URL)
+		}
+		switch q.Get("startTime") {
+		case "":
+			// Newest first, as BingX returns them
+			w.Write([]byte(`{"code":0,"msg":"","data":[
+				{"symbol":"BTC-USDT","incomeType":"FUNDING_FEE","income":"0.5","asset":"USDT","time":1700028800000,"tranId":"2"},
+				{"symbol":"BTC-USDT","incomeType":"FUNDING_FEE","income":"-1.25","asset":"USDT","time":1700000000000,"tranId":"1"}]}`))
+		case "1700028800001":
+			w.Write([]byte(`{"code":0,"msg":"","data":[
+				{"symbol":"ETH-USDT","incomeType":"FUNDING_FEE","income":"0.1","asset":"USDT","time":1700057600000,"tranId":"3"}]}`))
+		default:
+			t.Errorf("unexpected startTime %s", q.Get("startTime"))
+			w.Write([]byte(`{"code":0,"data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	incomes, err := broker.Collect(context.Background(), c.GetIncomeHistory(IncomeFilter{Type: IncomeFundingFee, PageSize: 2}))
+	if err != nil {
+		t.Fatalf("GetIncomeHistory() error = %v", err)
+	}
+	if requests != 2 || len(incomes) != 3 {
+		t.Fatalf("got %d incomes in %d requests, want 3 in 2", len(incomes), requests)
+	}
+	if first := incomes[0]; first.ID != "1" || first.Amount != -1.25 || first.Type != IncomeFundingFee || first.Time.UnixMilli() != 1700000000000 {
+		t.Errorf("first income = %+v", first)
+	}
+}
@@ -0,0 +1,117 @@
+package bingx
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// InstrumentType selects which BingX contract family a client trades
+type InstrumentType string
+
+const (
+	// InstrumentUSDTMargined is the linear USDT-margined perpetual market (default).
+	// Sizes are in base asset units and PnL is settled in USDT.
+	InstrumentUSDTMargined InstrumentType = "USDT-M"
+
+	// InstrumentCoinMargined is the inverse coin-margined perpetual market
+	// (e.g. BTC-USD). Sizes are in contracts of a fixed USD face value and
+	// margin/PnL are settled in the base asset.
+	InstrumentCoinMargined InstrumentType = "COIN-M"
+)
+
+// endpointSet holds the per-instrument paths for the Broker operations
+type endpointSet struct {
+	balance    string
+	positions  string
+	placeOrder string
+	openOrders string
+	queryOrder string
+	cancelAll  string
+	leverage   string
+	margin     string
+	price      string
+}
+
+// endpointsFor returns the endpoint paths for an instrument type
+func endpointsFor(instrument InstrumentType) endpointSet {
+	if instrument == InstrumentCoinMargined {
+		return endpointSet{
+			balance:    EndpointCoinBalance,
+			positions:  EndpointCoinPositions,
+			placeOrder: EndpointCoinPlaceOrder,
+			openOrders: EndpointCoinOpenOrders,
+			queryOrder: EndpointCoinQueryOrder,
+			cancelAll:  EndpointCoinCancelAll,
+			leverage:   EndpointCoinLeverage,
+			margin:     EndpointCoinMargin,
+			price:      EndpointCoinPrice,
+		}
+	}
+
+	return endpointSet{
+		balance:    EndpointBalance,
+		positions:  EndpointPositions,
+		placeOrder: EndpointPlaceOrder,
+		openOrders: EndpointOpenOrders,
+		queryOrder: EndpointQueryOrder,
+		cancelAll:  EndpointCancelAll,
+		leverage:   EndpointLeverage,
+		margin:     EndpointMargin,
+		price:      EndpointPrice,
+	}
+}
+
+// ContractValue returns the USD face value of one coin-margined contract.
+// BingX lists BTC contracts at 100 USD and every other inverse contract at 10 USD.
+func ContractValue(symbol string) float64 {
+	if strings.HasPrefix(symbol, "BTC-") {
+		return 100
+	}
+	return 10
+}
+
+// ContractSpec returns how symbol's orders are sized: base asset units, in
+// steps of the quantity precision, on USDT-margined contracts, and whole
+// ContractValue contracts on coin-margined ones
+func (c *Client) ContractSpec(ctx context.Context, symbol string) (broker.ContractSpec, error) {
+	if c.instrument == InstrumentCoinMargined {
+		return broker.ContractSpec{Symbol: symbol, Inverse: true, ContractSize: ContractValue(symbol), QuantityStep: 1, MinQuantity: 1}, nil
+	}
+
+	contract, err := c.GetContract(ctx, symbol)
+	if err != nil {
+		return broker.ContractSpec{}, err
+	}
+	return broker.ContractSpec{
+		Symbol:       symbol,
+		QuantityStep: math.Pow10(-contract.QuantityPrecision),
+		MinQuantity:  contract.MinQuantity,
+	}, nil
+}
+
+// InverseNotional returns the base-asset value of a coin-margined position
+// of the given number of contracts at price
+func InverseNotional(symbol string, contracts, price float64) float64 {
+	if price == 0 {
+		return 0
+	}
+	return contracts * ContractValue(symbol) / price
+}
+
+// InversePnL returns the PnL, in base asset, of a coin-margined position.
+// Inverse contracts pay out contracts * faceValue * (1/entry - 1/exit) for
+// longs, and the opposite for shorts.
+func InversePnL(symbol string, side broker.Side, contracts, entryPrice, exitPrice float64) float64 {
+	if entryPrice == 0 || exitPrice == 0 {
+		return 0
+	}
+
+	pnl := contracts * ContractValue(symbol) * (1/entryPrice - 1/exitPrice)
+	if side == broker.SideShort {
+		return -pnl
+	}
+	return pnl
+}
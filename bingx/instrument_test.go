@@ -0,0 +1,235 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestNewClient_InstrumentEndpoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        []Option
+		wantType    InstrumentType
+		wantBalance string
+		wantOrder   string
+	}{
+		{
+			name:        "Default is USDT-margined",
+			wantType:    InstrumentUSDTMargined,
+			wantBalance: EndpointBalance,
+			wantOrder:   EndpointPlaceOrder,
+		},
+		{
+			name:        "Coin-margined",
+			opts:        []Option{WithInstrumentType(InstrumentCoinMargined)},
+			wantType:    InstrumentCoinMargined,
+			wantBalance: EndpointCoinBalance,
+			wantOrder:   EndpointCoinPlaceOrder,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient("key", "secret", true, tt.opts...)
+
+			if c.InstrumentType() != tt.wantType {
+				t.Errorf("InstrumentType() = %q, want %q", c.InstrumentType(), tt.wantType)
+			}
+			if c.endpoints.balance != tt.wantBalance {
+				t.Errorf("balance endpoint = %q, want %q", c.endpoints.balance, tt.wantBalance)
+			}
+			if c.endpoints.placeOrder != tt.wantOrder {
+				t.Errorf("order endpoint = %q, want %q", c.endpoints.placeOrder, tt.wantOrder)
+			}
+		})
+	}
+}
+
+func TestOpen_Registered(t *testing.T) {
+	b, err := broker.Open("bingx", broker.Config{
+		APIKey:    "key",
+		SecretKey: "secret",
+		Demo:      true,
+		Options:   map[string]string{"instrument": "coin-m"},
+	})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	c, ok := b.(*Client)
+	if !ok || c.InstrumentType() != InstrumentCoinMargined || c.baseURL != BaseURLDemo {
+		t.Errorf("Open() = %#v, want demo coin-M client", b)
+	}
+
+	b, err = broker.Open("bingx", broker.Config{APIKey: "key", SecretKey: "secret", Environment: broker.EnvironmentTestnet})
+	if c, ok := b.(*Client); err != nil || !ok || c.Environment() != broker.EnvironmentTestnet || c.streamURL != StreamURLDemo {
+		t.Errorf("Open(testnet) = %#v, %v, want demo client", b, err)
+	}
+
+	if _, err := broker.Open("bingx", broker.Config{APIKey: "key"}); !errors.Is(err, broker.ErrAuthFailed) {
+		t.Errorf("Open() without secret error = %v, want ErrAuthFailed", err)
+	}
+}
+
+func TestInversePnL(t *testing.T) {
+	tests := []struct {
+		name      string
+		symbol    string
+		side      broker.Side
+		contracts float64
+		entry     float64
+		exit      float64
+		want      float64
+	}{
+		{
+			name:      "BTC long in profit",
+			symbol:    "BTC-USD",
+			side:      broker.SideLong,
+			contracts: 100, // 10,000 USD face value
+			entry:     40000,
+			exit:      50000,
+			want:      0.05, // 10000/40000 - 10000/50000
+		},
+		{
+			name:      "BTC short in profit",
+			symbol:    "BTC-USD",
+			side:      broker.SideShort,
+			contracts: 100,
+			entry:     50000,
+			exit:      40000,
+			want:      0.05,
+		},
+		{
+			name:      "ETH long in loss uses 10 USD contracts",
+			symbol:    "ETH-USD",
+			side:      broker.SideLong,
+			contracts: 100, // 1,000 USD face value
+			entry:     2500,
+			exit:      2000,
+			want:      -0.1, // 1000/2500 - 1000/2000
+		},
+		{
+			name:      "Zero price returns zero",
+			symbol:    "BTC-USD",
+			side:      broker.SideLong,
+			contracts: 10,
+			entry:     0,
+			exit:      50000,
+			want:      0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := InversePnL(tt.symbol, tt.side, tt.contracts, tt.entry, tt.exit)
+			if math.Abs(got-tt.want) > 1e-12 {
+				t.Errorf("InversePnL() = %.10f, want %.10f", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestInverseNotional(t *testing.T) {
+	if got := InverseNotional("BTC-USD", 50, 50000); got != 0.1 {
+		t.Errorf("InverseNotional(BTC) = %v, want 0.1", got)
+	}
+	if got := InverseNotional("SOL-USD", 20, 100); got != 2 {
+		t.Errorf("InverseNotional(SOL) = %v, want 2", got)
+	}
+}
+
+func TestClient_SymbolPrecision_CoinMargined(t *testing.T) {
+	inverse := NewClient("key", "secret", true, WithInstrumentType(InstrumentCoinMargined))
+	prec := inverse.symbolPrecision(context.Background(), "BTC-USD")
+	if got, _ := prec.formatQuantity(12); got != "12" {
+		t.Errorf("inverse formatQuantity() = %q, want %q", got, "12")
+	}
+	if got := prec.formatPrice(61234.5); got != "61234.5" {
+		t.Errorf("inverse formatPrice() = %q, want %q", got, "61234.5")
+	}
+}
+
+func TestClient_ContractSpec(t *testing.T) {
+	var hits int
+	c := NewClient("key", "secret", false, WithBaseURL(contractsServer(t, &hits).URL))
+	spec, err := broker.GetContractSpec(context.Background(), c, "ETH-USDT")
+	if err != nil || spec.Inverse || spec.QuantityStep != 0.01 {
+		t.Errorf("ContractSpec(ETH-USDT) = %+v, %v", spec, err)
+	}
+	if q := spec.Quantity(500, 2500); q != 0.2 {
+		t.Errorf("Quantity($500 at 2500) = %v, want 0.2", q)
+	}
+
+	inverse := NewClient("key", "secret", false, WithInstrumentType(InstrumentCoinMargined))
+	spec, err = broker.GetContractSpec(context.Background(), inverse, "BTC-USD")
+	if err != nil || !spec.Inverse || spec.ContractSize != 100 {
+		t.Errorf("ContractSpec(BTC-USD) = %+v, %v", spec, err)
+	}
+	if q := spec.Quantity(550, 60000); q != 5 {
+		t.Errorf("Quantity($550 of BTC-USD) = %v, want 5 contracts", q)
+	}
+}
+
+func TestClient_GetCurrentPrice_CoinMargined(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != EndpointCoinPrice {
+			t.Errorf("request path = %q, want %q", r.URL.Path, EndpointCoinPrice)
+		}
+		w.Write([]byte(`{"code":0,"msg":"","data":[{"symbol":"BTC-USD","lastPrice":"61234.5"}]}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", true,
+		WithInstrumentType(InstrumentCoinMargined),
+		WithBaseURL(server.URL))
+
+	price, err := c.GetCurrentPrice(context.Background(), "BTC-USD")
+	if err != nil {
+		t.Fatalf("GetCurrentPrice() error = %v", err)
+	}
+	if price != 61234.5 {
+		t.Errorf("GetCurrentPrice() = %v, want 61234.5", price)
+	}
+}
+
+func TestClient_CoinMarginedBalance(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != EndpointCoinBalance {
+			t.Errorf("request path = %q, want %q", r.URL.Path, EndpointCoinBalance)
+		}
+		w.Write([]byte(`{"code":0,"data":[
+			{"asset":"ETH","equity":"3.5","availableMargin":"3","usedMargin":"0.5"},
+			{"asset":"BTC","equity":"0.25","availableMargin":"0.2","usedMargin":"0.05","unrealizedProfit":"0.01"}]}`))
+	}))
+	defer server.Close()
+	ctx := context.Background()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL), WithInstrumentType(InstrumentCoinMargined))
+	balance, err := c.GetBalance(ctx)
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	if balance.Asset != "BTC" || balance.Total != 0.25 || balance.Available != 0.2 || balance.UnrealizedPnL != 0.01 {
+		t.Errorf("GetBalance() = %+v, want the BTC row, not the first", balance)
+	}
+
+	eth := NewClient("key", "secret", false, WithBaseURL(server.URL), WithInstrumentType(InstrumentCoinMargined), WithSettlementAsset("eth"))
+	if balance, err := eth.GetBalance(ctx); err != nil || balance.Asset != "ETH" || balance.Total != 3.5 {
+		t.Errorf("GetBalance(ETH) = %+v, %v, want the ETH row", balance, err)
+	}
+	sol := NewClient("key", "secret", false, WithBaseURL(server.URL), WithInstrumentType(InstrumentCoinMargined), WithSettlementAsset("SOL"))
+	if _, err := sol.GetBalance(ctx); err == nil {
+		t.Error("GetBalance(SOL) error = nil, want no SOL balance")
+	}
+
+	balances, err := c.GetBalances(ctx)
+	if err != nil || len(balances) != 2 || balances[0].Asset != "ETH" || balances[1].Asset != "BTC" {
+		t.Errorf("GetBalances() = %+v, %v, want both coins", balances, err)
+	}
+}
@@ -0,0 +1,13 @@
+package bingx
+
+import (
+	"testing"
+
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// TestIntegration runs the broker contract suite against the demo account
+// when BINGX_API_KEY and BINGX_SECRET_KEY are set
+func TestIntegration(t *testing.T) {
+	brokertest.Integration(t, "bingx")
+}
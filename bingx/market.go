@@ -3,8 +3,8 @@ package bingx
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"strconv"
+	"time"
 
 	"github.com/agatticelli/trading-go/broker"
 )
@@ -15,21 +15,40 @@ func (c *Client) GetCurrentPrice(ctx context.Context, symbol string) (float64, e
 		"symbol": symbol,
 	}
 
-	body, err := c.makeRequest(ctx, "GET", EndpointPrice, params)
+	body, err := c.makePublicRequest(ctx, "GET", c.endpoints.price, params)
 	if err != nil {
 		return 0, err
 	}
 
-	var response PriceResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return 0, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse price response", err)
+	if c.instrument == InstrumentCoinMargined {
+		return parseCoinTickerPrice(ctx, body)
 	}
 
-	if response.Code != APISuccessCode {
-		return 0, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	data, err := decode[PriceData](ctx, body, "price")
+	if err != nil {
+		return 0, err
+	}
+
+	price, err := strconv.ParseFloat(data.Price, 64)
+	if err != nil {
+		return 0, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse price value", err)
+	}
+
+	return price, nil
+}
+
+// parseCoinTickerPrice extracts the last price from a coin-margined ticker response
+func parseCoinTickerPrice(ctx context.Context, body []byte) (float64, error) {
+	tickers, err := decode[[]CoinTickerData](ctx, body, "ticker")
+	if err != nil {
+		return 0, err
+	}
+
+	if len(tickers) == 0 {
+		return 0, broker.NewBrokerError("bingx", "NO_DATA", "No ticker data returned", nil)
 	}
 
-	price, err := strconv.ParseFloat(response.Data.Price, 64)
+	price, err := strconv.ParseFloat(tickers[0].LastPrice, 64)
 	if err != nil {
 		return 0, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse price value", err)
 	}
@@ -45,19 +64,40 @@ func (c *Client) SetLeverage(ctx context.Context, symbol string, side string, le
 		"leverage": strconv.Itoa(leverage),
 	}
 
-	body, err := c.makeRequest(ctx, "POST", EndpointLeverage, params)
-	if err != nil {
+	if _, err := call[json.RawMessage](ctx, c, "POST", c.endpoints.leverage, params, "leverage"); err != nil {
 		return err
 	}
 
-	var response LeverageResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse leverage response", err)
+	c.cache.Invalidate(cacheKeyLeverage + symbol)
+	return nil
+}
+
+// GetFundingRate retrieves the current funding rate of a USDT-margined perpetual
+func (c *Client) GetFundingRate(ctx context.Context, symbol string) (*broker.FundingRate, error) {
+	if c.instrument != InstrumentUSDTMargined {
+		return nil, broker.ErrNotSupported
+	}
+
+	params := map[string]string{
+		"symbol": symbol,
 	}
 
-	if response.Code != APISuccessCode {
-		return broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	body, err := c.makePublicRequest(ctx, "GET", EndpointPremium, params)
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	data, err := decode[PremiumIndexData](ctx, body, "premium index")
+	if err != nil {
+		return nil, err
+	}
+
+	return &broker.FundingRate{
+		Symbol:          data.Symbol,
+		Rate:            data.LastFundingRate.Float64(),
+		Interval:        FundingInterval,
+		NextFundingTime: time.UnixMilli(data.NextFundingTime),
+		MarkPrice:       data.MarkPrice.Float64(),
+		IndexPrice:      data.IndexPrice.Float64(),
+	}, nil
 }
@@ -0,0 +1,123 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_GetFundingRate(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != EndpointPremium || r.URL.Query().Get("symbol") != "BTC-USDT" {
+			t.Errorf("request = %s, want %s?symbol=BTC-USDT", r.URL, EndpointPremium)
+		}
+		w.Write([]byte(`{"code":0,"msg":"","data":{"symbol":"BTC-USDT","markPrice":"43000.5",
+			"indexPrice":"42990.1","lastFundingRate":"0.00010000","nextFundingTime":1700006400000}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	rate, err := c.GetFundingRate(context.Background(), "BTC-USDT")
+	if err != nil {
+		t.Fatalf("GetFundingRate() error = %v", err)
+	}
+
+	want := broker.FundingRate{
+		Symbol:          "BTC-USDT",
+		Rate:            0.0001,
+		Interval:        8 * time.Hour,
+		NextFundingTime: time.UnixMilli(1700006400000),
+		MarkPrice:       43000.5,
+		IndexPrice:      42990.1,
+	}
+	if *rate != want {
+		t.Errorf("GetFundingRate() = %+v, want %+v", *rate, want)
+	}
+	if apr := rate.APR(); apr < 0.1094 || apr > 0.1096 {
+		t.Errorf("APR() = %v, want 0.1095", apr)
+	}
+
+	coin := NewClient("key", "secret", false, WithInstrumentType(InstrumentCoinMargined))
+	if _, err := coin.GetFundingRate(context.Background(), "BTC-USD"); err != broker.ErrNotSupported {
+		t.Errorf("coin-margined GetFundingRate() error = %v, want %v", err, broker.ErrNotSupported)
+	}
+}
+
+func TestClient_GetCurrentPrice_InvalidSymbol(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":109400,"msg":"symbol not exist","timestamp":1760500000000}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	_, err := c.GetCurrentPrice(context.Background(), "USDT-BTC")
+	var brokerErr *broker.BrokerError
+	if !errors.Is(err, broker.ErrInvalidSymbol) || !errors.As(err, &brokerErr) || brokerErr.Code != "API_109400" {
+		t.Errorf("GetCurrentPrice() error = %v, want API_109400 matching ErrInvalidSymbol", err)
+	}
+}
+
+func TestNewPublicClient(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("X-BX-APIKEY") != "" || r.URL.Query().Has("signature") {
+			t.Errorf("public request was authenticated: %s", r.URL)
+		}
+		w.Write([]byte(`{"code":0,"msg":"","data":{"symbol":"BTC-USDT","markPrice":"43000.5",
+			"indexPrice":"42990.1","lastFundingRate":"0.0001","nextFundingTime":1700006400000}}`))
+	}))
+	defer server.Close()
+
+	c := NewPublicClient(false, WithBaseURL(server.URL))
+	if !c.Public() {
+		t.Fatal("Public() = false")
+	}
+	if _, err := c.GetFundingRate(context.Background(), "BTC-USDT"); err != nil {
+		t.Fatalf("GetFundingRate() error = %v", err)
+	}
+
+	if _, err := c.GetBalance(context.Background()); !errors.Is(err, broker.ErrAuthFailed) {
+		t.Errorf("GetBalance() error = %v, want ErrAuthFailed", err)
+	}
+	if requests != 1 {
+		t.Errorf("requests = %d, want 1 (private calls must not reach the exchange)", requests)
+	}
+
+	b, err := broker.Open("bingx", broker.Config{})
+	if c, ok := b.(*Client); err != nil || !ok || !c.Public() {
+		t.Errorf("Open() without keys = %#v, %v, want public client", b, err)
+	}
+
+	if _, err := broker.Open("bingx", broker.Config{APIKey: "key"}); !errors.Is(err, broker.ErrAuthFailed) {
+		t.Errorf("Open() with only an API key error = %v, want ErrAuthFailed", err)
+	}
+}
+
+func TestNewClient_PartialKeys(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	for _, keys := range [][2]string{{"key", ""}, {"", "secret"}} {
+		c := NewClient(keys[0], keys[1], false, WithBaseURL(server.URL))
+		if c.Public() {
+			t.Errorf("NewClient(%q, %q).Public() = true, want a misconfigured private client", keys[0], keys[1])
+		}
+		var brokerErr *broker.BrokerError
+		if _, err := c.GetBalance(context.Background()); !errors.Is(err, broker.ErrAuthFailed) || !errors.As(err, &brokerErr) || brokerErr.Code != "CONFIG_ERROR" {
+			t.Errorf("NewClient(%q, %q).GetBalance() error = %v, want CONFIG_ERROR", keys[0], keys[1], err)
+		}
+	}
+	if requests != 0 {
+		t.Errorf("requests = %d, want none with half the credentials", requests)
+	}
+}
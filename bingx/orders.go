@@ -3,125 +3,309 @@ package bingx
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
 )
 
 // PlaceOrder places a new order
 func (c *Client) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	params, err := c.orderParams(ctx, order)
+	if err != nil {
+		return nil, err
+	}
+
+	// Orders go in a form body: embedded TP/SL JSON signs over the raw
+	// values and large payloads don't hit URL length limits
+	body, err := c.makeRequestWithBody(ctx, "POST", c.endpoints.placeOrder, params, encodingForm)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decode[OrderData](ctx, body, "order")
+	if err != nil {
+		c.logger.Warn("order rejected", logging.KeySymbol, order.Symbol, logging.KeyError, err)
+		return nil, err
+	}
+
+	placed := data.toOrder()
+	if placed.ClientOrderID == "" {
+		placed.ClientOrderID = params["clientOrderID"]
+	}
+	c.logger.Info("order placed", logging.KeySymbol, placed.Symbol, logging.KeyOrderID, placed.ID,
+		"side", placed.Side, "type", placed.Type, "size", placed.Size, "price", placed.Price)
+	return placed, nil
+}
+
+// ValidateOrder sends the order to BingX's test-order endpoint, which checks
+// margin, filters and leverage without executing it. Coin-margined clients
+// have no test endpoint and return broker.ErrNotSupported; use
+// broker.ValidateOrder to fall back to local checks.
+func (c *Client) ValidateOrder(ctx context.Context, order *broker.OrderRequest) error {
+	if c.instrument == InstrumentCoinMargined {
+		return broker.ErrNotSupported
+	}
+	params, err := c.orderParams(ctx, order)
+	if err != nil {
+		return err
+	}
+
+	body, err := c.makeRequestWithBody(ctx, "POST", EndpointTestOrder, params, encodingForm)
+	if err != nil {
+		return err
+	}
+
+	_, err = decode[json.RawMessage](ctx, body, "test order")
+	return err
+}
+
+// ReplaceOrder atomically cancels orderID and places req through BingX's
+// cancel-replace endpoint. Nothing is placed when the cancel fails (the
+// order filled or no longer exists); the error then matches
+// broker.ErrOrderNotFound. Use broker.ReplaceOrder to place anyway.
+func (c *Client) ReplaceOrder(ctx context.Context, symbol, orderID string, req *broker.OrderRequest) (*broker.Order, error) {
+	if c.instrument == InstrumentCoinMargined {
+		return nil, broker.ErrNotSupported
+	}
+
+	params, err := c.orderParams(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	params["symbol"] = symbol
+	params["cancelOrderId"] = orderID
+	params["cancelReplaceMode"] = "STOP_ON_FAILURE"
+
+	body, err := c.makeRequestWithBody(ctx, "POST", EndpointReplace, params, encodingForm)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := decode[CancelReplaceData](ctx, body, "cancel-replace")
+	if err != nil {
+		return nil, err
+	}
+	placed, err := data.result(orderID)
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Info("order replaced", logging.KeySymbol, symbol, logging.KeyOrderID, placed.ID, "replaced_order_id", orderID)
+	return placed, nil
+}
+
+// maxBatchReplace is the most cancel-replaces BingX accepts per request
+const maxBatchReplace = 10
+
+// ReplaceOrders cancel-replaces several orders of a symbol through BingX's
+// batch cancel-replace endpoint, in requests of up to 10. Each replacement
+// behaves like ReplaceOrder; a failed request fails the whole call, but
+// replacements in earlier requests have been applied.
+func (c *Client) ReplaceOrders(ctx context.Context, symbol string, replacements []broker.Replacement) ([]broker.ReplaceResult, error) {
+	if c.instrument == InstrumentCoinMargined {
+		return nil, broker.ErrNotSupported
+	}
+
+	results := make([]broker.ReplaceResult, 0, len(replacements))
+	for batch := range slices.Chunk(replacements, maxBatchReplace) {
+		orders := make([]map[string]string, len(batch))
+		for i, r := range batch {
+			params, err := c.orderParams(ctx, r.Order)
+			if err != nil {
+				return nil, err
+			}
+			params["symbol"] = symbol
+			params["cancelOrderId"] = r.OrderID
+			params["cancelReplaceMode"] = "STOP_ON_FAILURE"
+			orders[i] = params
+		}
+		data, err := json.Marshal(orders)
+		if err != nil {
+			return nil, err
+		}
+
+		body, err := c.makeRequestWithBody(ctx, "POST", EndpointBatchReplace, map[string]string{"batchOrders": string(data)}, encodingForm)
+		if err != nil {
+			return nil, err
+		}
+		replaced, err := decode[[]CancelReplaceData](ctx, body, "batch cancel-replace")
+		if err != nil {
+			return nil, err
+		}
+		if len(replaced) != len(batch) {
+			return nil, broker.NewBrokerError("bingx", "PARSE_ERROR",
+				fmt.Sprintf("batch cancel-replace returned %d results for %d orders", len(replaced), len(batch)), nil)
+		}
+
+		for i, data := range replaced {
+			placed, err := data.result(batch[i].OrderID)
+			results = append(results, broker.ReplaceResult{Order: placed, Err: err})
+		}
+	}
+	c.logger.Info("orders replaced", logging.KeySymbol, symbol, "count", len(replacements))
+	return results, nil
+}
+
+// result converts one cancel-replace outcome to the broker.ReplaceOrder
+// contract
+func (d CancelReplaceData) result(orderID string) (*broker.Order, error) {
+	if !d.CancelResult {
+		return nil, broker.NewBrokerError("bingx", "CANCEL_FAILED", d.CancelMsg, broker.ErrOrderNotFound)
+	}
+	if !d.ReplaceResult {
+		return nil, &broker.ReplaceError{OrderID: orderID, Err: broker.NewBrokerError("bingx", "REPLACE_FAILED", d.ReplaceMsg, nil)}
+	}
+	return d.NewOrderResponse.toOrder(), nil
+}
+
+// orderParams converts an order request to BingX order parameters
+func (c *Client) orderParams(ctx context.Context, order *broker.OrderRequest) (map[string]string, error) {
 	// Convert broker types to BingX types
-	side := "BUY"
-	positionSide := "LONG"
-	if order.Side == broker.SideShort {
-		side = "SELL"
-		positionSide = "SHORT"
+	orderType, err := toBingXOrderType(order.Type, order.Price > 0)
+	if err != nil {
+		return nil, err
+	}
+	side, positionSide := toBingXSides(order.Side, order.ReduceOnly)
+	opts := broker.OrderOptionsFrom(ctx)
+	if err := broker.CheckTimeInForce(c.SupportedFeatures(), order, opts); err != nil {
+		return nil, err
+	}
+	if err := broker.CheckSTP(c.SupportedFeatures(), opts); err != nil {
+		return nil, err // BingX has no self-trade prevention setting
+	}
+	if (orderType == OrderTypeTriggerLimit || orderType == OrderTypeTriggerMarket) && order.StopPrice <= 0 {
+		return nil, broker.ErrInvalidPrice // Conditional entries need a trigger price
 	}
 
-	// Build BingX order request
-	params := map[string]string{
-		"symbol":       order.Symbol,
-		"side":         side,
-		"positionSide": positionSide,
-		"type":         string(order.Type),
-		"quantity":     fmt.Sprintf("%.8f", order.Size),
+	// Prices and sizes take the symbol's decimals: more are rejected
+	prec := c.symbolPrecision(ctx, order.Symbol)
+
+	// Build BingX order request, sized for the optional parameters and
+	// the timestamp so it never grows
+	params := make(map[string]string, 16)
+	params["symbol"] = order.Symbol
+	params["side"] = string(side)
+	params["positionSide"] = string(positionSide)
+	params["type"] = string(orderType)
+	quantity, err := prec.formatQuantity(order.Size)
+	if err != nil {
+		return nil, err
 	}
+	params["quantity"] = quantity
 
 	// Add optional parameters
 	if order.Price > 0 {
-		params["price"] = fmt.Sprintf("%.8f", order.Price)
+		params["price"] = prec.formatPrice(order.Price)
 	}
 	if order.StopPrice > 0 {
-		params["stopPrice"] = fmt.Sprintf("%.8f", order.StopPrice)
+		params["stopPrice"] = prec.formatPrice(order.StopPrice)
 	}
 	if order.TimeInForce != "" {
-		params["timeInForce"] = string(order.TimeInForce)
-	} else if order.Type == broker.OrderTypeLimit {
-		params["timeInForce"] = "GTC" // Default for limit orders
+		params["timeInForce"] = string(toBingXTimeInForce(order.TimeInForce))
+	} else if orderType == OrderTypeLimit {
+		params["timeInForce"] = string(TimeInForceGTC) // Default for limit orders
 	}
 	if order.ReduceOnly {
 		params["reduceOnly"] = "true"
 	}
-
-	// Add Stop Loss as JSON string (BingX format)
-	if order.StopLoss != nil {
-		stopLossJSON := fmt.Sprintf(`{"type":"STOP","stopPrice":%g,"price":%g,"workingType":"MARK_PRICE"}`,
-			order.StopLoss.TriggerPrice, order.StopLoss.TriggerPrice)
-		params["stopLoss"] = stopLossJSON
+	if clientOrderID := broker.ClientOrderIDOf(ctx, order); clientOrderID != "" {
+		params["clientOrderID"] = clientOrderID
 	}
 
-	// Add Take Profit as JSON string (BingX format)
-	if order.TakeProfit != nil {
-		takeProfitJSON := fmt.Sprintf(`{"type":"TAKE_PROFIT","stopPrice":%g,"price":%g,"workingType":"MARK_PRICE"}`,
-			order.TakeProfit.TriggerPrice, order.TakeProfit.OrderPrice)
-		params["takeProfit"] = takeProfitJSON
+	// Trigger selection for stop, take-profit and conditional entries
+	if isTriggerOrderType(orderType) {
+		if opts.WorkingType != "" {
+			params["workingType"] = string(toBingXWorkingType(opts.WorkingType))
+		}
+		if opts.PriceProtect {
+			params["priceProtect"] = "true"
+		}
 	}
 
-	// Execute request - use special payload method if TP/SL present (they contain JSON)
-	var body []byte
-	var err error
-	if order.StopLoss != nil || order.TakeProfit != nil {
-		body, err = c.makeRequestWithPayload(ctx, "POST", EndpointPlaceOrder, params)
-	} else {
-		body, err = c.makeRequest(ctx, "POST", EndpointPlaceOrder, params)
-	}
-	if err != nil {
-		return nil, err
+	// Trailing stops trail by a callback rate from an optional activation price
+	if orderType == OrderTypeTrailingStopMarket && order.Trailing != nil {
+		if order.Trailing.ActivationPrice > 0 {
+			params["activationPrice"] = prec.formatPrice(order.Trailing.ActivationPrice)
+		}
+		params["priceRate"] = strconv.FormatFloat(order.Trailing.CallbackRate, 'f', shortestDecimal, 64)
 	}
 
-	var response OrderResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse order response", err)
+	// Add Stop Loss as JSON string (BingX format)
+	if order.StopLoss != nil {
+		stopLoss, err := protectiveOrderJSON(prec, OrderTypeStopMarket, OrderTypeStop,
+			order.StopLoss.TriggerPrice, order.StopLoss.OrderPrice, order.StopLoss.WorkingType)
+		if err != nil {
+			return nil, err
+		}
+		params["stopLoss"] = stopLoss
 	}
 
-	if response.Code != APISuccessCode {
-		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	// Add Take Profit as JSON string (BingX format)
+	if order.TakeProfit != nil {
+		takeProfit, err := protectiveOrderJSON(prec, OrderTypeTakeProfitMarket, OrderTypeTakeProfit,
+			order.TakeProfit.TriggerPrice, order.TakeProfit.OrderPrice, order.TakeProfit.WorkingType)
+		if err != nil {
+			return nil, err
+		}
+		params["takeProfit"] = takeProfit
 	}
 
-	// Convert response to broker.Order
-	price, _ := strconv.ParseFloat(response.Data.Price, 64)
-	size, _ := strconv.ParseFloat(response.Data.Quantity, 64)
+	return params, nil
+}
 
-	var brokerSide broker.Side
-	if response.Data.PositionSide == "LONG" {
-		brokerSide = broker.SideLong
-	} else {
-		brokerSide = broker.SideShort
+// toOrder converts an order acknowledgement to a broker order
+func (d OrderData) toOrder() *broker.Order {
+	return &broker.Order{
+		ID:            fmt.Sprintf("%d", d.OrderId),
+		ClientOrderID: d.ClientOrderID,
+		Symbol:        d.Symbol,
+		Side:          fromBingXPositionSide(d.PositionSide),
+		Type:          fromBingXOrderType(d.Type),
+		Status:        fromBingXStatus(d.Status, d.Type),
+		Size:          d.Quantity.Float64(),
+		Price:         d.Price.Float64(),
+		ReduceOnly:    isReduceOnly(d.Side, d.PositionSide),
+		TimeInForce:   fromBingXTimeInForce(d.TimeInForce),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
 	}
+}
 
-	return &broker.Order{
-		ID:          fmt.Sprintf("%d", response.Data.OrderId),
-		Symbol:      response.Data.Symbol,
-		Side:        brokerSide,
-		Type:        broker.OrderType(response.Data.Type),
-		Status:      broker.OrderStatus(response.Data.Status),
-		Size:        size,
-		Price:       price,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
-	}, nil
+// protectiveOrder is the JSON object BingX expects in the stopLoss/takeProfit
+// params. Prices are json.Numbers so they keep the symbol's decimals and
+// never turn into exponent notation (5e-07) like encoded floats.
+type protectiveOrder struct {
+	Type        OrderType   `json:"type"`
+	StopPrice   json.Number `json:"stopPrice"`
+	Price       json.Number `json:"price"`
+	WorkingType WorkingType `json:"workingType"`
 }
 
-// mapBingXStatus normalizes BingX order status to user-friendly status
-// For trigger orders (STOP/TAKE_PROFIT), "NEW" means pending trigger, not active
-func mapBingXStatus(bingxStatus string, orderType string) broker.OrderStatus {
-	// Trigger order types that should show as PENDING when status is NEW
-	triggerTypes := map[string]bool{
-		"STOP":                true,
-		"STOP_MARKET":         true,
-		"TAKE_PROFIT":         true,
-		"TAKE_PROFIT_MARKET":  true,
+// protectiveOrderJSON encodes an attached TP/SL. Without an order price the
+// market variant fires at the trigger; with one, the limit variant rests there.
+func protectiveOrderJSON(prec precision, marketType, limitType OrderType, triggerPrice, orderPrice float64, workingType broker.WorkingType) (string, error) {
+	trigger := json.Number(prec.formatPrice(triggerPrice))
+	p := protectiveOrder{
+		Type:        marketType,
+		StopPrice:   trigger,
+		Price:       trigger,
+		WorkingType: toBingXWorkingType(workingType),
 	}
-
-	// If it's a trigger order with NEW status, map to PENDING
-	if triggerTypes[orderType] && bingxStatus == "NEW" {
-		return "PENDING"
+	if orderPrice > 0 {
+		p.Type = limitType
+		p.Price = json.Number(prec.formatPrice(orderPrice))
 	}
 
-	// Otherwise return the status as-is
-	return broker.OrderStatus(bingxStatus)
+	data, err := json.Marshal(p)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
 }
 
 // GetOrders retrieves open orders
@@ -131,99 +315,81 @@ func (c *Client) GetOrders(ctx context.Context, filter *broker.OrderFilter) ([]*
 		params["symbol"] = filter.Symbol
 	}
 
-	body, err := c.makeRequest(ctx, "GET", EndpointOpenOrders, params)
+	data, err := call[OpenOrdersData](ctx, c, "GET", c.endpoints.openOrders, params, "orders")
 	if err != nil {
 		return nil, err
 	}
 
-	var response OpenOrdersResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		// Add the response body to the error for debugging
-		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR",
-			fmt.Sprintf("Failed to parse orders response: %s. Body: %s", err.Error(), string(body)), err)
-	}
-
-	if response.Code != APISuccessCode {
-		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
-	}
-
 	var orders []*broker.Order
-	for _, o := range response.Data.Orders {
-		// Determine side based on PositionSide (which side of the position this order affects)
-		// Note: BingX uses PositionSide (LONG/SHORT) to indicate position direction
-		// and Side (BUY/SELL) to indicate order action
-		// For our purposes, we map PositionSide to broker.Side
-		var side broker.Side
-		if o.PositionSide == "LONG" {
-			side = broker.SideLong
-		} else {
-			side = broker.SideShort
-		}
-
-		// Determine if order is reduce-only (closing position)
-		reduceOnly := isReduceOnly(o.Side, o.PositionSide)
-
+	for _, o := range data.Orders {
 		// Apply filter if specified
 		if filter != nil && filter.Side != nil && *filter.Side != broker.OrderStatus(o.Status) {
 			continue
 		}
-
-		// Parse fields
-		size, _ := strconv.ParseFloat(o.Quantity, 64)
-		price, _ := strconv.ParseFloat(o.Price, 64)
-		stopPrice, _ := strconv.ParseFloat(o.StopPrice, 64)
-		filledSize, _ := strconv.ParseFloat(o.ExecutedQty, 64)
-		avgPrice, _ := strconv.ParseFloat(o.AvgPrice, 64)
-
-		// Map BingX status to normalized status
-		status := mapBingXStatus(o.Status, o.Type)
-
-		orders = append(orders, &broker.Order{
-			ID:            fmt.Sprintf("%d", o.OrderId),
-			ClientOrderID: o.ClientOrderID,
-			Symbol:        o.Symbol,
-			Side:          side,
-			Type:          broker.OrderType(o.Type),
-			Status:        status,
-			Size:          size,
-			Price:         price,
-			StopPrice:     stopPrice,
-			FilledSize:    filledSize,
-			AveragePrice:  avgPrice,
-			ReduceOnly:    reduceOnly,
-			TimeInForce:   broker.TimeInForce(o.TimeInForce),
-			CreatedAt:     time.Unix(o.Time/1000, 0),
-			UpdatedAt:     time.Unix(o.UpdateTime/1000, 0),
-		})
+		orders = append(orders, fromBingXOrder(o))
 	}
 
 	return orders, nil
 }
 
-// CancelOrder cancels a specific order
-func (c *Client) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+// GetOrder looks up one order, open or final, so fills and cancels the
+// exchange made on its own (e.g. a post-only order that would cross) are
+// seen. It returns broker.ErrOrderNotFound (wrapped) for unknown orders.
+func (c *Client) GetOrder(ctx context.Context, symbol, orderID string) (*broker.Order, error) {
 	params := map[string]string{
 		"symbol":  symbol,
 		"orderId": orderID,
 	}
 
-	body, err := c.makeRequest(ctx, "DELETE", EndpointPlaceOrder, params)
+	data, err := call[OrderDetailData](ctx, c, "GET", c.endpoints.queryOrder, params, "order")
 	if err != nil {
-		return err
+		var brokerErr *broker.BrokerError
+		if errors.As(err, &brokerErr) && brokerErr.Err == nil && strings.Contains(strings.ToLower(brokerErr.Message), "not exist") {
+			brokerErr.Err = broker.ErrOrderNotFound
+		}
+		return nil, err
 	}
+	if data.Order.OrderId == 0 {
+		return nil, broker.NewBrokerError("bingx", "NOT_FOUND", "order "+orderID+" not found", broker.ErrOrderNotFound)
+	}
+	return fromBingXOrder(data.Order), nil
+}
 
-	var response struct {
-		Code int    `json:"code"`
-		Msg  string `json:"msg"`
+// fromBingXOrder converts an order. BingX uses PositionSide (LONG/SHORT) to
+// indicate the position leg and Side (BUY/SELL) the order action; the
+// broker side is the leg, and closing orders are reduce-only.
+func fromBingXOrder(o OpenOrderData) *broker.Order {
+	return &broker.Order{
+		ID:            fmt.Sprintf("%d", o.OrderId),
+		ClientOrderID: o.ClientOrderID,
+		Symbol:        o.Symbol,
+		Side:          fromBingXPositionSide(o.PositionSide),
+		Type:          fromBingXOrderType(o.Type),
+		Status:        fromBingXStatus(o.Status, o.Type),
+		Size:          o.Quantity.Float64(),
+		Price:         o.Price.Float64(),
+		StopPrice:     o.StopPrice.Float64(),
+		FilledSize:    o.ExecutedQty.Float64(),
+		AveragePrice:  o.AvgPrice.Float64(),
+		ReduceOnly:    isReduceOnly(o.Side, o.PositionSide),
+		TimeInForce:   fromBingXTimeInForce(o.TimeInForce),
+		CreatedAt:     time.Unix(o.Time/1000, 0),
+		UpdatedAt:     time.Unix(o.UpdateTime/1000, 0),
 	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse cancel response", err)
+}
+
+// CancelOrder cancels a specific order
+func (c *Client) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	params := map[string]string{
+		"symbol":  symbol,
+		"orderId": orderID,
 	}
 
-	if response.Code != APISuccessCode {
-		return broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	if _, err := call[json.RawMessage](ctx, c, "DELETE", c.endpoints.placeOrder, params, "cancel"); err != nil {
+		return err
 	}
 
+	c.logger.Info("order canceled", logging.KeySymbol, symbol, logging.KeyOrderID, orderID)
 	return nil
 }
 
@@ -234,29 +400,17 @@ func (c *Client) CancelAllOrders(ctx context.Context, symbol string) error {
 		params["symbol"] = symbol
 	}
 
-	body, err := c.makeRequest(ctx, "DELETE", EndpointCancelAll, params)
-	if err != nil {
+	if _, err := call[json.RawMessage](ctx, c, "DELETE", c.endpoints.cancelAll, params, "cancel all"); err != nil {
 		return err
 	}
 
-	var response struct {
-		Code int    `json:"code"`
-		Msg  string `json:"msg"`
-	}
-	if err := json.Unmarshal(body, &response); err != nil {
-		return broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse cancel all response", err)
-	}
-
-	if response.Code != APISuccessCode {
-		return broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
-	}
-
+	c.logger.Info("orders canceled", logging.KeySymbol, symbol)
 	return nil
 }
 
 // isReduceOnly determines if an order is reduce-only based on Side and PositionSide
 // BingX: SELL+LONG = closing long position, BUY+SHORT = closing short position
 func isReduceOnly(side, positionSide string) bool {
-	return (side == "SELL" && positionSide == "LONG") ||
-		(side == "BUY" && positionSide == "SHORT")
+	return (OrderSide(side) == OrderSideSell && PositionSide(positionSide) == PositionSideLong) ||
+		(OrderSide(side) == OrderSideBuy && PositionSide(positionSide) == PositionSideShort)
 }
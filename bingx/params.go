@@ -0,0 +1,14 @@
+package bingx
+
+import "github.com/agatticelli/trading-go/internal/adk"
+
+// encodeParams canonicalizes request parameters; every request, signed or
+// not, is built from its result so no endpoint can encode differently.
+//
+// payload is what gets signed: BingX verifies signatures over the decoded
+// parameters, so embedded JSON (stopLoss, takeProfit, batchOrders) is
+// signed verbatim. encoded is the same pairs percent-encoded for a query
+// string or form body (see adk.EncodeParams).
+func encodeParams(params map[string]string) (payload, encoded string) {
+	return adk.EncodeParams(params)
+}
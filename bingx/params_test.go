@@ -0,0 +1,60 @@
+package bingx
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestEncodeParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      map[string]string
+		wantPayload string
+		wantEncoded string
+	}{
+		{"empty", nil, "", ""},
+		{"sorted by name", map[string]string{"symbol": "BTC-USDT", "side": "BUY", "quantity": "0.5"},
+			"quantity=0.5&side=BUY&symbol=BTC-USDT", "quantity=0.5&side=BUY&symbol=BTC-USDT"},
+		{"space", map[string]string{"note": "a b"}, "note=a b", "note=a%20b"},
+		{"plus", map[string]string{"time": "12:00+08:00"}, "time=12:00+08:00", "time=12%3A00%2B08%3A00"},
+		{"braces and quotes", map[string]string{"stopLoss": `{"type":"STOP_MARKET","stopPrice":49000}`},
+			`stopLoss={"type":"STOP_MARKET","stopPrice":49000}`,
+			"stopLoss=%7B%22type%22%3A%22STOP_MARKET%22%2C%22stopPrice%22%3A49000%7D"},
+		{"separators", map[string]string{"memo": "a&b=c"}, "memo=a&b=c", "memo=a%26b%3Dc"},
+		{"percent", map[string]string{"memo": "100%"}, "memo=100%", "memo=100%25"},
+		{"unicode", map[string]string{"memo": "é"}, "memo=é", "memo=%C3%A9"},
+		{"empty value", map[string]string{"clientOrderId": ""}, "clientOrderId=", "clientOrderId="},
+		{"array", map[string]string{"batchOrders": `[{"side":"BUY"},{"side":"SELL"}]`},
+			`batchOrders=[{"side":"BUY"},{"side":"SELL"}]`,
+			"batchOrders=%5B%7B%22side%22%3A%22BUY%22%7D%2C%7B%22side%22%3A%22SELL%22%7D%5D"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, encoded := encodeParams(tt.params)
+			if payload != tt.wantPayload {
+				t.Errorf("payload = %q, want %q", payload, tt.wantPayload)
+			}
+			if encoded != tt.wantEncoded {
+				t.Errorf("encoded = %q, want %q", encoded, tt.wantEncoded)
+			}
+			if strings.Contains(encoded, "+") {
+				t.Errorf("encoded %q contains '+', which decodes ambiguously", encoded)
+			}
+
+			// The server decodes exactly the parameters that were signed
+			decoded, err := url.ParseQuery(encoded)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for key, value := range tt.params {
+				if got := decoded.Get(key); got != value {
+					t.Errorf("decoded %s = %q, want %q", key, got, value)
+				}
+			}
+			if got := canonicalString(tt.params); payload != got {
+				t.Errorf("payload = %q, want the canonical string %q", payload, got)
+			}
+		})
+	}
+}
@@ -3,7 +3,6 @@ package bingx
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"strconv"
 	"time"
 
@@ -17,61 +16,34 @@ func (c *Client) GetPositions(ctx context.Context, filter *broker.PositionFilter
 		params["symbol"] = filter.Symbol
 	}
 
-	body, err := c.makeRequest(ctx, "GET", EndpointPositions, params)
+	data, err := call[[]PositionData](ctx, c, "GET", c.endpoints.positions, params, "positions")
 	if err != nil {
 		return nil, err
 	}
 
-	var response PositionsResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse positions response", err)
-	}
-
-	if response.Code != APISuccessCode {
-		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
-	}
-
 	var positions []*broker.Position
-	for _, pos := range response.Data {
-		// Parse position amount
-		size, _ := strconv.ParseFloat(pos.PositionAmt, 64)
+	for _, pos := range data {
+		size := pos.PositionAmt.Float64()
 
 		// Skip positions with zero size
 		if size == 0 {
 			continue
 		}
 
-		// Determine side
-		var side broker.Side
-		if pos.PositionSide == "LONG" {
-			side = broker.SideLong
-		} else {
-			side = broker.SideShort
-		}
+		side := fromBingXPositionSide(pos.PositionSide)
 
 		// Apply filter if specified
 		if filter != nil && filter.Side != nil && *filter.Side != side {
 			continue
 		}
 
-		// Parse other fields
-		entryPrice, _ := strconv.ParseFloat(pos.AvgPrice, 64)
-		markPrice, _ := strconv.ParseFloat(pos.MarkPrice, 64)
-		unrealizedPnL, _ := strconv.ParseFloat(pos.UnrealizedProfit, 64)
-		realizedPnL, _ := strconv.ParseFloat(pos.RealisedProfit, 64)
-		margin, _ := strconv.ParseFloat(pos.InitialMargin, 64)
-		maintenanceMargin, _ := strconv.ParseFloat(pos.MaintenanceMargin, 64)
-
-		// Parse leverage (can be string or number)
-		leverage, err := pos.GetLeverageFloat()
-		if err != nil {
-			leverage = 0
-		}
-
-		// Parse liquidation price (can be string or number)
-		liquidationPrice, err := pos.GetLiquidationPriceFloat()
-		if err != nil {
-			liquidationPrice = 0
+		entryPrice := pos.AvgPrice.Float64()
+		markPrice := pos.MarkPrice.Float64()
+		unrealizedPnL := pos.UnrealizedProfit.Float64()
+		if unrealizedPnL == 0 && c.instrument == InstrumentCoinMargined {
+			// Inverse PnL is denominated in the base asset; derive it from
+			// the contract count when the exchange omits it
+			unrealizedPnL = InversePnL(pos.Symbol, side, size, entryPrice, markPrice)
 		}
 
 		positions = append(positions, &broker.Position{
@@ -80,12 +52,12 @@ func (c *Client) GetPositions(ctx context.Context, filter *broker.PositionFilter
 			Size:              size,
 			EntryPrice:        entryPrice,
 			MarkPrice:         markPrice,
-			LiquidationPrice:  liquidationPrice,
-			Leverage:          int(leverage),
+			LiquidationPrice:  pos.LiquidationPrice.Float64(),
+			Leverage:          int(pos.Leverage.Float64()),
 			UnrealizedPnL:     unrealizedPnL,
-			RealizedPnL:       realizedPnL,
-			Margin:            margin,
-			MaintenanceMargin: maintenanceMargin,
+			RealizedPnL:       pos.RealisedProfit.Float64(),
+			Margin:            pos.InitialMargin.Float64(),
+			MaintenanceMargin: pos.MaintenanceMargin.Float64(),
 			Timestamp:         time.Now(),
 		})
 	}
@@ -107,3 +79,28 @@ func (c *Client) GetPosition(ctx context.Context, symbol string) (*broker.Positi
 
 	return positions[0], nil
 }
+
+// AdjustMargin adds (positive amount) or removes (negative amount) isolated
+// margin on a position leg
+func (c *Client) AdjustMargin(ctx context.Context, symbol string, side broker.Side, amount float64) error {
+	if amount == 0 {
+		return broker.ErrInvalidQuantity
+	}
+
+	marginType := "1" // Add
+	if amount < 0 {
+		marginType = "2" // Reduce
+		amount = -amount
+	}
+
+	_, positionSide := toBingXSides(side, false)
+	params := map[string]string{
+		"symbol":       symbol,
+		"positionSide": string(positionSide),
+		"amount":       strconv.FormatFloat(amount, 'f', -1, 64),
+		"type":         marginType,
+	}
+
+	_, err := call[json.RawMessage](ctx, c, "POST", c.endpoints.margin, params, "margin")
+	return err
+}
@@ -0,0 +1,80 @@
+package bingx
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_AdjustMargin(t *testing.T) {
+	tests := []struct {
+		name             string
+		side             broker.Side
+		amount           float64
+		wantType         string
+		wantAmount       string
+		wantPositionSide string
+	}{
+		{"Add to long", broker.SideLong, 25, "1", "25", "LONG"},
+		{"Remove from short", broker.SideShort, -12.5, "2", "12.5", "SHORT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query()
+				if r.Method != "POST" || r.URL.Path != EndpointMargin {
+					t.Errorf("request = %s %s, want POST %s", r.Method, r.URL.Path, EndpointMargin)
+				}
+				if q.Get("type") != tt.wantType || q.Get("amount") != tt.wantAmount || q.Get("positionSide") != tt.wantPositionSide {
+					t.Errorf("params = %v, want type=%s amount=%s positionSide=%s", q, tt.wantType, tt.wantAmount, tt.wantPositionSide)
+				}
+				w.Write([]byte(`{"code":0,"msg":"","amount":25,"type":1}`))
+			}))
+			defer server.Close()
+
+			c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+			if err := c.AdjustMargin(context.Background(), "BTC-USDT", tt.side, tt.amount); err != nil {
+				t.Errorf("AdjustMargin() error = %v", err)
+			}
+		})
+	}
+
+	c := NewClient("key", "secret", false)
+	if err := c.AdjustMargin(context.Background(), "BTC-USDT", broker.SideLong, 0); err != broker.ErrInvalidQuantity {
+		t.Errorf("AdjustMargin(0) error = %v, want %v", err, broker.ErrInvalidQuantity)
+	}
+}
+
+func TestClient_GetAccountRisk(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EndpointBalance:
+			w.Write([]byte(`{"code":0,"data":[{"asset":"USDT","balance":"1000","equity":"1100","unrealizedProfit":"100",
+				"availableMargin":"700","usedMargin":"350","freezedMargin":"50"}]}`))
+		case EndpointPositions:
+			w.Write([]byte(`{"code":0,"data":[
+				{"symbol":"BTC-USDT","positionSide":"LONG","positionAmt":"0.05","maintenanceMargin":"10","positionValue":"2500"},
+				{"symbol":"ETH-USDT","positionSide":"SHORT","positionAmt":"-1","maintenanceMargin":"12","positionValue":"-3000"},
+				{"symbol":"SOL-USDT","positionSide":"LONG","positionAmt":"0","maintenanceMargin":"0","positionValue":"0"}]}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	risk, err := broker.GetAccountRisk(context.Background(), c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if risk.Equity != 1100 || risk.FrozenMargin != 50 || risk.MaintenanceMargin != 22 || risk.Notional != 5500 || risk.Positions != 2 {
+		t.Errorf("GetAccountRisk() = %+v", risk)
+	}
+	if risk.MarginRatio() != 0.02 || risk.Leverage() != 5 {
+		t.Errorf("margin ratio %v, leverage %v; want 0.02 and 5", risk.MarginRatio(), risk.Leverage())
+	}
+}
@@ -0,0 +1,119 @@
+package bingx
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// shortestDecimal formats a number as the shortest decimal that reads back
+// as the same float
+const shortestDecimal = -1
+
+// precisionRefresh is the least time between contract reloads for symbols
+// missing from the table, so orders on an unknown symbol don't each fetch
+// the contract list
+const precisionRefresh = time.Minute
+
+// precision is the number of decimals a symbol's prices and quantities
+// take, or shortestDecimal when unknown
+type precision struct {
+	price    int
+	quantity int
+}
+
+// unknownPrecision formats numbers as given, never in exponent notation
+var unknownPrecision = precision{price: shortestDecimal, quantity: shortestDecimal}
+
+// formatPrice renders a price, rounded to the nearest tick
+func (p precision) formatPrice(v float64) string {
+	return strconv.FormatFloat(v, 'f', p.price, 64)
+}
+
+// formatQuantity renders an order size, rounded down to the lot so an
+// order never grows past what was sized. A positive size below one lot
+// fails with broker.ErrInvalidQuantity rather than going out as "0".
+func (p precision) formatQuantity(v float64) (string, error) {
+	if p.quantity >= 0 {
+		scale := math.Pow10(p.quantity)
+		floored := math.Floor(v*scale+1e-9) / scale // Tolerate binary fractions like 0.299999...
+		if v > 0 && floored == 0 {
+			return "", broker.NewBrokerError("bingx", "INVALID_QUANTITY",
+				fmt.Sprintf("size %v is below the lot of %d decimals", v, p.quantity), broker.ErrInvalidQuantity)
+		}
+		v = floored
+	}
+	return strconv.FormatFloat(v, 'f', p.quantity, 64), nil
+}
+
+// precisionTable holds the precision of each symbol from the contract
+// specifications
+type precisionTable struct {
+	mu       sync.Mutex
+	bySymbol map[string]precision
+	loadedAt time.Time
+	loading  chan struct{} // Closed when the load in progress ends
+}
+
+// symbolPrecision returns the decimals symbol's orders are formatted with.
+// USDT-margined symbols use the contract specifications, loaded on first
+// use; coin-margined contracts trade in whole contracts. When the
+// specifications can't be loaded the order goes out with numbers as given
+// and the exchange judges them.
+func (c *Client) symbolPrecision(ctx context.Context, symbol string) precision {
+	if c.instrument == InstrumentCoinMargined {
+		return precision{price: shortestDecimal, quantity: 0}
+	}
+
+	t := &c.precisions
+	t.mu.Lock()
+	for t.loading != nil {
+		// Another order is loading the contracts: wait for its result
+		loading := t.loading
+		t.mu.Unlock()
+		select {
+		case <-loading:
+		case <-ctx.Done():
+			return unknownPrecision
+		}
+		t.mu.Lock()
+	}
+	if p, ok := t.bySymbol[symbol]; ok {
+		t.mu.Unlock()
+		return p
+	}
+	if time.Since(t.loadedAt) < precisionRefresh {
+		t.mu.Unlock()
+		return unknownPrecision
+	}
+	t.loadedAt = time.Now()
+	loading := make(chan struct{})
+	t.loading = loading
+	t.mu.Unlock()
+
+	// Fetched unlocked so orders on known symbols don't wait on the request
+	contracts, err := c.GetContracts(ctx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.loading = nil
+	close(loading)
+	if err != nil {
+		c.logger.Warn("loading symbol precision failed", logging.KeySymbol, symbol, logging.KeyError, err)
+		return unknownPrecision
+	}
+	t.bySymbol = make(map[string]precision, len(contracts))
+	for _, contract := range contracts {
+		t.bySymbol[contract.Symbol] = precision{price: contract.PricePrecision, quantity: contract.QuantityPrecision}
+	}
+	if p, ok := t.bySymbol[symbol]; ok {
+		return p
+	}
+	return unknownPrecision
+}
@@ -0,0 +1,141 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// serveContracts answers a contract specifications request for BTC-USDT,
+// ETH-USDT and PEPE-USDT, reporting whether r was one
+func serveContracts(w http.ResponseWriter, r *http.Request) bool {
+	if r.URL.Path != EndpointContracts {
+		return false
+	}
+	w.Write([]byte(`{"code":0,"data":[
+		{"symbol":"BTC-USDT","pricePrecision":1,"quantityPrecision":4,"status":1},
+		{"symbol":"ETH-USDT","pricePrecision":2,"quantityPrecision":2,"status":1},
+		{"symbol":"PEPE-USDT","pricePrecision":10,"quantityPrecision":0,"status":1}]}`))
+	return true
+}
+
+// contractsServer serves only the contract specifications, counting the
+// requests for them
+func contractsServer(t testing.TB, hits *int) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !serveContracts(w, r) {
+			t.Errorf("request path = %q, want %q", r.URL.Path, EndpointContracts)
+			return
+		}
+		*hits++
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPrecision_Format(t *testing.T) {
+	tests := []struct {
+		prec         precision
+		price, size  float64
+		wantPrice    string
+		wantQuantity string
+	}{
+		{precision{price: 1, quantity: 4}, 65000.56, 0.00159, "65000.6", "0.0015"},
+		{precision{price: 2, quantity: 2}, 2500, 0.3, "2500.00", "0.30"},
+		{precision{price: 10, quantity: 0}, 0.0000005, 1500000.9, "0.0000005000", "1500000"},
+		{unknownPrecision, 0.0000005, 0.0015, "0.0000005", "0.0015"},
+	}
+	for _, tt := range tests {
+		if got := tt.prec.formatPrice(tt.price); got != tt.wantPrice {
+			t.Errorf("%+v formatPrice(%v) = %q, want %q", tt.prec, tt.price, got, tt.wantPrice)
+		}
+		if got, err := tt.prec.formatQuantity(tt.size); err != nil || got != tt.wantQuantity {
+			t.Errorf("%+v formatQuantity(%v) = %q, %v, want %q", tt.prec, tt.size, got, err, tt.wantQuantity)
+		}
+	}
+
+	if got, err := (precision{price: 1, quantity: 4}).formatQuantity(0.00004); !errors.Is(err, broker.ErrInvalidQuantity) {
+		t.Errorf("formatQuantity(below the lot) = %q, %v, want ErrInvalidQuantity", got, err)
+	}
+}
+
+func TestClient_SymbolPrecision(t *testing.T) {
+	var hits int
+	server := contractsServer(t, &hits)
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	if got := c.symbolPrecision(ctx, "ETH-USDT"); got != (precision{price: 2, quantity: 2}) {
+		t.Errorf("symbolPrecision(ETH-USDT) = %+v", got)
+	}
+	if got := c.symbolPrecision(ctx, "BTC-USDT"); got != (precision{price: 1, quantity: 4}) {
+		t.Errorf("symbolPrecision(BTC-USDT) = %+v", got)
+	}
+	// Unknown symbols don't reload the contracts until precisionRefresh passed
+	if got := c.symbolPrecision(ctx, "NEW-USDT"); got != unknownPrecision {
+		t.Errorf("symbolPrecision(NEW-USDT) = %+v, want unknownPrecision", got)
+	}
+	c.symbolPrecision(ctx, "NEW-USDT")
+	if hits != 1 {
+		t.Errorf("contract requests = %d, want 1", hits)
+	}
+}
+
+func TestClient_SymbolPrecision_Concurrent(t *testing.T) {
+	release := make(chan struct{})
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		<-release
+		serveContracts(w, r)
+	}))
+	defer server.Close()
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	results := make(chan precision, 2)
+	go func() { results <- c.symbolPrecision(ctx, "BTC-USDT") }()
+	for hits.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// The table isn't locked during the fetch: a second caller waits for
+	// the load in progress instead of fetching again
+	go func() { results <- c.symbolPrecision(ctx, "ETH-USDT") }()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+
+	got := map[precision]bool{<-results: true, <-results: true}
+	if !got[precision{price: 1, quantity: 4}] || !got[precision{price: 2, quantity: 2}] || hits.Load() != 1 {
+		t.Errorf("precisions = %v after %d requests, want BTC and ETH from one request", got, hits.Load())
+	}
+}
+
+func TestClient_OrderParams_Precision(t *testing.T) {
+	var hits int
+	c := NewClient("key", "secret", false, WithBaseURL(contractsServer(t, &hits).URL))
+	params, err := c.orderParams(context.Background(), &broker.OrderRequest{
+		Symbol: "PEPE-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1500000.9, Price: 0.0000123456789,
+		StopLoss:   &broker.StopLossConfig{TriggerPrice: 0.0000005},
+		TakeProfit: &broker.TakeProfitConfig{TriggerPrice: 0.00002, OrderPrice: 0.0000199999999999},
+	})
+	if err != nil {
+		t.Fatalf("orderParams() error = %v", err)
+	}
+	want := map[string]string{
+		"quantity":   "1500000",
+		"price":      "0.0000123457",
+		"stopLoss":   `{"type":"STOP_MARKET","stopPrice":0.0000005000,"price":0.0000005000,"workingType":"MARK_PRICE"}`,
+		"takeProfit": `{"type":"TAKE_PROFIT","stopPrice":0.0000200000,"price":0.0000200000,"workingType":"MARK_PRICE"}`,
+	}
+	for key, want := range want {
+		if params[key] != want {
+			t.Errorf("%s = %q, want %q", key, params[key], want)
+		}
+	}
+}
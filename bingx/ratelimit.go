@@ -0,0 +1,204 @@
+package bingx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/internal/adk"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// Rate-limit response headers sent by BingX
+const (
+	HeaderRateLimitRemaining = "X-RateLimit-Requests-Remain"
+	HeaderRateLimitExpire    = "X-RateLimit-Requests-Expire"
+)
+
+// APIRateLimitedCode is the API code of requests rejected for exceeding the
+// endpoint frequency limit
+const APIRateLimitedCode = 100410
+
+// unblockAt extracts the Unix millisecond unblock time from a 100410 message
+// ("... will be unblocked after 1760500060000")
+var unblockAt = regexp.MustCompile(`unblocked after (\d{13})`)
+
+// RateLimitInfo is the request budget last reported by BingX
+type RateLimitInfo struct {
+	Remaining int       // Requests left in the current window
+	Reset     time.Time // When the window resets
+	UpdatedAt time.Time // When the headers were received (zero if never)
+}
+
+// Exhausted reports whether no requests are left before Reset
+func (i RateLimitInfo) Exhausted(now time.Time) bool {
+	return !i.UpdatedAt.IsZero() && i.Remaining <= 0 && i.Reset.After(now)
+}
+
+// RetryPolicy configures retries of rate-limited and transient failures
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first (default 3)
+	BaseDelay   time.Duration // First backoff when the exchange gives no hint (default 500ms)
+	MaxDelay    time.Duration // Longest wait before giving up (default 1m)
+}
+
+// WithRetry retries requests rejected for rate limiting (HTTP 429 or API
+// code 100410), waiting as long as the exchange asks through Retry-After,
+// the 100410 unblock time or the rate-limit reset header, and backing off
+// exponentially only when it gives no hint. Requests are also held while
+// the reported request budget is exhausted. Rejected requests never reached
+// the matching engine, so retrying orders is safe.
+//
+// Network errors and HTTP 500, 502 and 504 leave the outcome unknown, so
+// they are only retried for calls that are safe to repeat: reads, cancels,
+// leverage changes and orders carrying a client order ID (see
+// broker.OrderOptions). Other orders, margin changes and transfers fail at
+// once rather than risk executing twice.
+func WithRetry(p RetryPolicy) Option {
+	p = RetryPolicy(adk.RetryPolicy(p).WithDefaults())
+	return func(c *Client) {
+		c.retryPolicy = &p
+	}
+}
+
+// RateLimit returns the request budget reported by the last response
+func (c *Client) RateLimit() RateLimitInfo {
+	c.limits.mu.Lock()
+	defer c.limits.mu.Unlock()
+	return c.limits.info
+}
+
+// rateLimits holds the latest RateLimitInfo
+type rateLimits struct {
+	mu   sync.Mutex
+	info RateLimitInfo
+}
+
+// update records the rate-limit headers of a response, if present
+func (l *rateLimits) update(header http.Header, now time.Time) {
+	remaining, err := strconv.Atoi(header.Get(HeaderRateLimitRemaining))
+	if err != nil {
+		return
+	}
+
+	info := RateLimitInfo{Remaining: remaining, UpdatedAt: now}
+	if expire, err := strconv.ParseInt(header.Get(HeaderRateLimitExpire), 10, 64); err == nil {
+		info.Reset = resetTime(expire, now)
+	}
+
+	l.mu.Lock()
+	l.info = info
+	l.mu.Unlock()
+}
+
+// resetTime interprets the expire header, which is a Unix millisecond
+// timestamp or, on some gateways, the seconds left in the window
+func resetTime(expire int64, now time.Time) time.Time {
+	if expire > 1e12 {
+		return time.UnixMilli(expire)
+	}
+	return now.Add(time.Duration(expire) * time.Second)
+}
+
+// rateLimitError detects a 100410 rejection in a successful HTTP response
+func rateLimitError(body []byte, now time.Time) error {
+	if !bytes.Contains(body, []byte(strconv.Itoa(APIRateLimitedCode))) {
+		return nil
+	}
+
+	var response struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if json.Unmarshal(body, &response) != nil || response.Code != APIRateLimitedCode {
+		return nil
+	}
+
+	err := broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, broker.ErrRateLimited)
+	err.RetryAfter = unblockDelay(response.Msg, now)
+	return err
+}
+
+// unblockDelay returns the wait until the unblock time in a 100410 message
+func unblockDelay(msg string, now time.Time) time.Duration {
+	m := unblockAt.FindStringSubmatch(msg)
+	if m == nil {
+		return 0
+	}
+	ms, _ := strconv.ParseInt(m[1], 10, 64)
+	if at := time.UnixMilli(ms); at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// retry runs send, retrying the failures adk.Retryable allows for a call
+// classified safety per the client's policy, and tags a final error with
+// the context's request ID
+func (c *Client) retry(ctx context.Context, safety adk.Safety, send func() ([]byte, error)) (body []byte, err error) {
+	defer func() { err = adk.Annotate(ctx, err) }()
+	p := c.retryPolicy
+	if p == nil {
+		return send()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if info := c.RateLimit(); info.Exhausted(time.Now()) && time.Until(info.Reset) <= p.MaxDelay {
+			if err := adk.Sleep(ctx, time.Until(info.Reset)); err != nil {
+				return nil, err
+			}
+		}
+
+		body, err := send()
+		if !adk.Retryable(err, safety) || attempt >= p.MaxAttempts {
+			return body, err
+		}
+
+		delay := c.retryDelay(err, attempt)
+		if delay > p.MaxDelay {
+			c.log.transport.Warn("giving up on request", "attempt", attempt, "delay", delay, logging.KeyError, err)
+			return nil, err
+		}
+		c.log.transport.Info("retrying request", "attempt", attempt, "delay", delay, "safety", safety, logging.KeyError, err)
+		if err := adk.Sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// retryDelay picks the wait before the next attempt: the exchange's hint
+// when it gave one, exponential backoff otherwise
+func (c *Client) retryDelay(err error, attempt int) time.Duration {
+	if wait, ok := broker.RetryAfter(err); ok {
+		return wait
+	}
+	if info := c.RateLimit(); info.Exhausted(time.Now()) {
+		return time.Until(info.Reset)
+	}
+	return adk.RetryPolicy(*c.retryPolicy).Backoff(attempt)
+}
+
+// endpointSafety overrides the method-based classification of endpoints
+// whose effect doesn't follow from their HTTP method
+var endpointSafety = map[string]adk.Safety{
+	EndpointTestOrder:    adk.ReadOnly,   // Validated, never placed
+	EndpointLeverage:     adk.Idempotent, // Sets an absolute value
+	EndpointCoinLeverage: adk.Idempotent,
+}
+
+// classify tells retry whether a call may be repeated when its outcome is
+// unknown. Orders become idempotent with a client order ID, since BingX
+// rejects one that is already in use.
+func classify(method, endpoint string, params map[string]string) adk.Safety {
+	if s, ok := endpointSafety[endpoint]; ok {
+		return s
+	}
+	return adk.Classify(method, params, "clientOrderID")
+}
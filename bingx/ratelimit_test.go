@@ -0,0 +1,195 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_RateLimit_Headers(t *testing.T) {
+	reset := time.Now().Add(30 * time.Second).UnixMilli()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(HeaderRateLimitRemaining, "42")
+		w.Header().Set(HeaderRateLimitExpire, fmt.Sprint(reset))
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	if info := c.RateLimit(); !info.UpdatedAt.IsZero() {
+		t.Errorf("RateLimit() before any request = %+v, want zero", info)
+	}
+	if _, err := c.makeRequest(context.Background(), "GET", "/test", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	info := c.RateLimit()
+	if info.Remaining != 42 || info.Reset.UnixMilli() != reset || info.UpdatedAt.IsZero() {
+		t.Errorf("RateLimit() = %+v, want 42 remaining until %d", info, reset)
+	}
+	if info.Exhausted(time.Now()) {
+		t.Error("Exhausted() = true with 42 requests remaining")
+	}
+}
+
+func TestClient_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       *RetryPolicy
+		respond      func(w http.ResponseWriter, attempt int32)
+		wantAttempts int32
+		wantErr      error
+		minElapsed   time.Duration
+	}{
+		{
+			name:   "honors 100410 unblock time",
+			policy: &RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Second},
+			respond: func(w http.ResponseWriter, attempt int32) {
+				if attempt == 1 {
+					unblock := time.Now().Add(50 * time.Millisecond).UnixMilli()
+					fmt.Fprintf(w, `{"code":100410,"msg":"The endpoint trigger frequency limit rule is currently in the disabled period and will be unblocked after %d"}`, unblock)
+					return
+				}
+				w.Write([]byte(`{"code":0}`))
+			},
+			wantAttempts: 2,
+			minElapsed:   40 * time.Millisecond,
+		},
+		{
+			name:   "backs off without a hint",
+			policy: &RetryPolicy{MaxAttempts: 3, BaseDelay: 5 * time.Millisecond},
+			respond: func(w http.ResponseWriter, attempt int32) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantAttempts: 3,
+			wantErr:      broker.ErrRateLimited,
+			minElapsed:   15 * time.Millisecond,
+		},
+		{
+			name:   "gives up when Retry-After exceeds MaxDelay",
+			policy: &RetryPolicy{MaxDelay: time.Second},
+			respond: func(w http.ResponseWriter, attempt int32) {
+				w.Header().Set("Retry-After", "120")
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantAttempts: 1,
+			wantErr:      broker.ErrRateLimited,
+		},
+		{
+			name:   "does not retry other errors",
+			policy: &RetryPolicy{},
+			respond: func(w http.ResponseWriter, attempt int32) {
+				w.WriteHeader(http.StatusBadGateway)
+			},
+			wantAttempts: 1,
+			wantErr:      broker.ErrAPIError,
+		},
+		{
+			name: "disabled by default",
+			respond: func(w http.ResponseWriter, attempt int32) {
+				w.WriteHeader(http.StatusTooManyRequests)
+			},
+			wantAttempts: 1,
+			wantErr:      broker.ErrRateLimited,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				tt.respond(w, attempts.Add(1))
+			}))
+			defer server.Close()
+
+			opts := []Option{WithBaseURL(server.URL)}
+			if tt.policy != nil {
+				opts = append(opts, WithRetry(*tt.policy))
+			}
+			c := NewClient("key", "secret", false, opts...)
+
+			start := time.Now()
+			_, err := c.makeRequestWithBody(context.Background(), "POST", "/test", nil, encodingForm)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if elapsed := time.Since(start); elapsed < tt.minElapsed {
+				t.Errorf("elapsed = %v, want at least %v", elapsed, tt.minElapsed)
+			}
+		})
+	}
+}
+
+func TestClient_Retry_WaitsForExhaustedBudget(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			w.Header().Set(HeaderRateLimitRemaining, "0")
+			w.Header().Set(HeaderRateLimitExpire, fmt.Sprint(time.Now().Add(50*time.Millisecond).UnixMilli()))
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL), WithRetry(RetryPolicy{}))
+	ctx := context.Background()
+	c.makeRequest(ctx, "GET", "/test", nil)
+
+	start := time.Now()
+	if _, err := c.makeRequest(ctx, "GET", "/test", nil); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("request sent after %v, want it held until the window reset", elapsed)
+	}
+}
+
+func TestClient_Retry_Classification(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		endpoint     string
+		params       map[string]string
+		wantAttempts int32
+	}{
+		{"price read", "GET", EndpointPrice, map[string]string{"symbol": "BTC-USDT"}, 2},
+		{"cancel", "DELETE", EndpointPlaceOrder, map[string]string{"orderId": "1"}, 2},
+		{"leverage", "POST", EndpointLeverage, map[string]string{"leverage": "5"}, 2},
+		{"order", "POST", EndpointPlaceOrder, map[string]string{"symbol": "BTC-USDT"}, 1},
+		{"order with client ID", "POST", EndpointPlaceOrder, map[string]string{"symbol": "BTC-USDT", "clientOrderID": "abc"}, 2},
+		{"margin", "POST", EndpointMargin, map[string]string{"amount": "10"}, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if attempts.Add(1) == 1 {
+					w.WriteHeader(http.StatusBadGateway)
+					return
+				}
+				w.Write([]byte(`{"code":0}`))
+			}))
+			defer server.Close()
+
+			c := NewClient("key", "secret", false, WithBaseURL(server.URL), WithRetry(RetryPolicy{BaseDelay: time.Millisecond}))
+			_, err := c.makeRequest(context.Background(), tt.method, tt.endpoint, tt.params)
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d (error %v)", got, tt.wantAttempts, err)
+			}
+			if tt.wantAttempts == 1 && !errors.Is(err, broker.ErrAPIError) {
+				t.Errorf("error = %v, want the gateway error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,43 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Raw sends a signed request to an endpoint the client doesn't wrap, e.g.
+// "/openApi/swap/v2/user/commissionRate", and returns the response body.
+// Parameters go in the query string for GET and DELETE and in a form body
+// otherwise; the timestamp and signature are added. The body is returned as
+// is, so API errors reported in it are the caller's to check (see Do).
+func (c *Client) Raw(ctx context.Context, method, endpoint string, params map[string]string) ([]byte, error) {
+	params = maps.Clone(params) // Signing adds the timestamp
+	switch method {
+	case http.MethodGet, http.MethodDelete:
+		return c.makeRequest(ctx, method, endpoint, params)
+	default:
+		return c.makeRequestWithBody(ctx, method, endpoint, params, encodingForm)
+	}
+}
+
+// Do sends a request like Raw, checks the code/msg envelope and unmarshals
+// its data field into out (skipped when out is nil). API errors are
+// returned as a *broker.BrokerError with code API_<code>; see Call for a
+// typed variant.
+func (c *Client) Do(ctx context.Context, method, endpoint string, params map[string]string, out any) error {
+	data, err := Call[json.RawMessage](ctx, c, method, endpoint, params)
+	if err != nil {
+		return err
+	}
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse response data", err)
+	}
+	return nil
+}
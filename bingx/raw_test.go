@@ -0,0 +1,61 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_Raw(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.Method {
+		case http.MethodGet:
+			if r.URL.Query().Get("symbol") != "BTC-USDT" || r.URL.Query().Get("signature") == "" {
+				t.Errorf("GET query = %v, want signed symbol param", r.URL.Query())
+			}
+			w.Write([]byte(`{"code":0,"msg":"","data":{"commission":{"takerCommissionRate":0.0005,"makerCommissionRate":0.0002}}}`))
+		case http.MethodPost:
+			if r.PostForm.Get("leverage") != "5" || r.PostForm.Get("signature") == "" {
+				t.Errorf("POST form = %v, want signed leverage param", r.PostForm)
+			}
+			w.Write([]byte(`{"code":109400,"msg":"invalid leverage","data":{}}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	ctx := context.Background()
+	params := map[string]string{"symbol": "BTC-USDT"}
+
+	body, err := c.Raw(ctx, http.MethodGet, "/openApi/swap/v2/user/commissionRate", params)
+	if err != nil || len(body) == 0 {
+		t.Fatalf("Raw() = %s, %v", body, err)
+	}
+	if _, ok := params["timestamp"]; ok {
+		t.Error("Raw() modified the caller's params")
+	}
+
+	var rates struct {
+		Commission struct {
+			Taker float64 `json:"takerCommissionRate"`
+			Maker float64 `json:"makerCommissionRate"`
+		} `json:"commission"`
+	}
+	if err := c.Do(ctx, http.MethodGet, "/openApi/swap/v2/user/commissionRate", params, &rates); err != nil {
+		t.Fatal(err)
+	}
+	if rates.Commission.Taker != 0.0005 || rates.Commission.Maker != 0.0002 {
+		t.Errorf("rates = %+v, want the response data", rates)
+	}
+
+	err = c.Do(ctx, http.MethodPost, "/openApi/swap/v2/trade/leverage", map[string]string{"leverage": "5"}, nil)
+	var brokerErr *broker.BrokerError
+	if !errors.As(err, &brokerErr) || brokerErr.Code != "API_109400" {
+		t.Errorf("Do() error = %v, want API_109400", err)
+	}
+}
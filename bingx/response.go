@@ -0,0 +1,87 @@
+package bingx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/internal/adk"
+)
+
+// errMaintenance marks responses showing the API is down for maintenance:
+// HTTP 503, maintenance pages and API errors announcing maintenance
+var errMaintenance = adk.ErrMaintenance
+
+// responseError converts an unusable response into a BrokerError: any
+// non-200 status, or a 200 whose body is not JSON (maintenance pages served
+// by edge proxies). It returns nil for a usable response.
+func responseError(resp *http.Response, body []byte) error {
+	isHTML := adk.IsHTML(resp, body)
+	if resp.StatusCode == http.StatusOK && !isHTML {
+		return nil
+	}
+
+	code, sentinel := "HTTP_ERROR", broker.ErrAPIError
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		code, sentinel = "RATE_LIMITED", broker.ErrRateLimited
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		sentinel = errMaintenance
+	case resp.StatusCode == http.StatusInternalServerError, resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		sentinel = adk.ErrServerError
+	case resp.StatusCode == http.StatusOK:
+		code, sentinel = "HTML_RESPONSE", errMaintenance
+	}
+
+	err := broker.NewBrokerError("bingx", code,
+		fmt.Sprintf("HTTP %d: %s", resp.StatusCode, errorText(resp.StatusCode, body, isHTML)), sentinel)
+	err.RetryAfter = adk.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	if err.RetryAfter == 0 && sentinel == broker.ErrRateLimited && !isHTML {
+		// 429 bodies carry the 100410 unblock time instead
+		var apiErr struct {
+			Msg string `json:"msg"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil {
+			err.RetryAfter = unblockDelay(apiErr.Msg, time.Now())
+		}
+	}
+	return err
+}
+
+// maintenanceError detects an API error in a successful HTTP response whose
+// message announces maintenance
+func maintenanceError(body []byte) error {
+	if !bytes.Contains(bytes.ToLower(body), []byte("maintenance")) {
+		return nil
+	}
+
+	var response struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if json.Unmarshal(body, &response) != nil || response.Code == APISuccessCode ||
+		!strings.Contains(strings.ToLower(response.Msg), "maintenance") {
+		return nil
+	}
+	return broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, errMaintenance)
+}
+
+// errorText summarizes an error body: the msg of a BingX JSON error, or
+// what adk.ErrorText makes of anything else
+func errorText(status int, body []byte, isHTML bool) string {
+	if !isHTML {
+		var apiErr struct {
+			Code int    `json:"code"`
+			Msg  string `json:"msg"`
+		}
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Msg != "" {
+			return fmt.Sprintf("%s (code %d)", apiErr.Msg, apiErr.Code)
+		}
+	}
+	return adk.ErrorText(status, body, isHTML)
+}
@@ -0,0 +1,114 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_Execute_ErrorResponses(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		contentType    string
+		retryAfter     string
+		body           string
+		wantCode       string
+		wantErr        error
+		wantMessage    string
+		wantRetryAfter time.Duration
+		wantMaint      bool
+	}{
+		{
+			name: "cloudflare 502", status: http.StatusBadGateway, contentType: "text/html",
+			body:     "<html>\n<head><title>502 Bad Gateway</title></head>\n<body>cloudflare</body></html>",
+			wantCode: "HTTP_ERROR", wantErr: broker.ErrAPIError, wantMessage: "HTTP 502: 502 Bad Gateway",
+		},
+		{
+			name: "cloudflare 429", status: http.StatusTooManyRequests, contentType: "text/html; charset=UTF-8", retryAfter: "30",
+			body:     "<!DOCTYPE html><html><head><title>Access denied | open-api.bingx.com used Cloudflare to restrict access</title></head></html>",
+			wantCode: "RATE_LIMITED", wantErr: broker.ErrRateLimited,
+			wantMessage:    "HTTP 429: Access denied | open-api.bingx.com used Cloudflare to restrict access",
+			wantRetryAfter: 30 * time.Second,
+		},
+		{
+			name: "maintenance page with 200", status: http.StatusOK, contentType: "text/html",
+			body:     "<html><head><title>System Maintenance &amp; Upgrade</title></head></html>",
+			wantCode: "HTML_RESPONSE", wantErr: broker.ErrAPIError, wantMessage: "HTTP 200: System Maintenance & Upgrade",
+			wantMaint: true,
+		},
+		{
+			name: "sniffed html without content type", status: http.StatusForbidden,
+			body:     "  <html><body>blocked</body></html>",
+			wantCode: "HTTP_ERROR", wantErr: broker.ErrAPIError, wantMessage: "HTTP 403: HTML response",
+		},
+		{
+			name: "json error body", status: http.StatusBadRequest, contentType: "application/json",
+			body:     `{"code":100400,"msg":"Invalid parameters"}`,
+			wantCode: "HTTP_ERROR", wantErr: broker.ErrAPIError, wantMessage: "HTTP 400: Invalid parameters (code 100400)",
+		},
+		{
+			name: "empty body", status: http.StatusServiceUnavailable, retryAfter: "120",
+			wantCode: "HTTP_ERROR", wantErr: broker.ErrAPIError, wantMessage: "HTTP 503: Service Unavailable",
+			wantRetryAfter: 2 * time.Minute, wantMaint: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.contentType != "" {
+					w.Header().Set("Content-Type", tt.contentType)
+				} else {
+					w.Header()["Content-Type"] = nil
+				}
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+			_, err := c.makeRequest(context.Background(), "GET", "/test", nil)
+
+			var be *broker.BrokerError
+			if !errors.As(err, &be) {
+				t.Fatalf("error = %v, want *broker.BrokerError", err)
+			}
+			if be.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", be.Code, tt.wantCode)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error = %v, want %v", err, tt.wantErr)
+			}
+			if be.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", be.Message, tt.wantMessage)
+			}
+			if got, _ := broker.RetryAfter(err); got != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %v, want %v", got, tt.wantRetryAfter)
+			}
+			if errors.Is(err, broker.ErrMaintenance) != tt.wantMaint {
+				t.Errorf("maintenance = %v, want %v", errors.Is(err, broker.ErrMaintenance), tt.wantMaint)
+			}
+		})
+	}
+}
+
+func TestMaintenanceError(t *testing.T) {
+	err := maintenanceError([]byte(`{"code":100503,"msg":"System maintenance, please try again later"}`))
+	if !errors.Is(err, broker.ErrMaintenance) || !errors.Is(err, broker.ErrAPIError) {
+		t.Errorf("maintenanceError() = %v, want maintenance API error", err)
+	}
+	for _, body := range []string{`{"code":0,"msg":"","data":{"note":"maintenance"}}`, `{"code":100400,"msg":"Invalid parameters"}`} {
+		if err := maintenanceError([]byte(body)); err != nil {
+			t.Errorf("maintenanceError(%s) = %v, want nil", body, err)
+		}
+	}
+}
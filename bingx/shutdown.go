@@ -0,0 +1,66 @@
+package bingx
+
+import (
+	"context"
+	"sync"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/internal/inflight"
+)
+
+// lifecycle tracks in-flight requests and open streams for Shutdown
+type lifecycle struct {
+	inflight inflight.Tracker
+
+	mu      sync.Mutex
+	streams map[*context.CancelFunc]struct{}
+}
+
+// begin registers an HTTP request or stream, failing once shutting down
+func (l *lifecycle) begin() error {
+	if !l.inflight.Begin() {
+		return broker.NewBrokerError("bingx", "SHUTTING_DOWN", "Client is shut down", broker.ErrShuttingDown)
+	}
+	return nil
+}
+
+// stream derives a context that Shutdown cancels. The returned release
+// function must be called when the stream ends.
+func (l *lifecycle) stream(ctx context.Context) (context.Context, func(), error) {
+	if err := l.begin(); err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	l.mu.Lock()
+	if l.streams == nil {
+		l.streams = make(map[*context.CancelFunc]struct{})
+	}
+	l.streams[&cancel] = struct{}{}
+	l.mu.Unlock()
+
+	release := func() {
+		l.mu.Lock()
+		delete(l.streams, &cancel)
+		l.mu.Unlock()
+		cancel()
+		l.inflight.End()
+	}
+	return ctx, release, nil
+}
+
+// Shutdown rejects new requests and streams, closes open WebSocket streams
+// (their StreamTrades calls return context.Canceled) and waits for in-flight
+// HTTP requests to complete or ctx to end. Open orders are left untouched;
+// use the shutdown package to cancel them first.
+func (c *Client) Shutdown(ctx context.Context) error {
+	c.life.inflight.Close()
+
+	c.life.mu.Lock()
+	for cancel := range c.life.streams {
+		(*cancel)()
+	}
+	c.life.mu.Unlock()
+
+	return c.life.inflight.Wait(ctx)
+}
@@ -0,0 +1,68 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_Shutdown(t *testing.T) {
+	streamURL := newStreamServer(t, "BTC-USDT@trade",
+		`{"code":0,"dataType":"BTC-USDT@trade","data":[{"T":1,"s":"BTC-USDT","m":false,"p":"1","q":"1"}]}`)
+
+	started := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(50 * time.Millisecond)
+		w.Write([]byte(`{"code":0,"data":{"price":"100"}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL), WithStreamURL(streamURL))
+	ctx := context.Background()
+
+	streaming := make(chan struct{})
+	streamErr := make(chan error, 1)
+	go func() {
+		streamErr <- c.StreamTrades(ctx, "BTC-USDT", func(broker.Trade) { close(streaming) })
+	}()
+
+	requestErr := make(chan error, 1)
+	go func() {
+		_, err := c.GetCurrentPrice(ctx, "BTC-USDT")
+		requestErr <- err
+	}()
+
+	<-streaming
+	<-started
+
+	if err := c.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	// In-flight request completed before Shutdown returned
+	select {
+	case err := <-requestErr:
+		if err != nil {
+			t.Errorf("in-flight request error = %v", err)
+		}
+	default:
+		t.Error("Shutdown() returned before the in-flight request finished")
+	}
+
+	if err := <-streamErr; !errors.Is(err, context.Canceled) {
+		t.Errorf("StreamTrades() error = %v, want context.Canceled", err)
+	}
+
+	if _, err := c.GetCurrentPrice(ctx, "BTC-USDT"); !errors.Is(err, broker.ErrShuttingDown) {
+		t.Errorf("request after Shutdown error = %v, want ErrShuttingDown", err)
+	}
+	if err := c.StreamTrades(ctx, "BTC-USDT", func(broker.Trade) {}); !errors.Is(err, broker.ErrShuttingDown) {
+		t.Errorf("stream after Shutdown error = %v, want ErrShuttingDown", err)
+	}
+}
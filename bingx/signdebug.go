@@ -0,0 +1,155 @@
+package bingx
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/internal/adk"
+)
+
+const (
+	// APISignatureErrorCode is the API code of requests whose signature
+	// failed verification
+	APISignatureErrorCode = 100001
+	// APITimestampErrorCode is the API code of requests whose timestamp is
+	// missing or outside the receive window
+	APITimestampErrorCode = 100421
+)
+
+// referenceParams is a sample order request. referencePayload is the string
+// BingX expects to be signed for it: the parameters sorted by key and joined
+// unescaped. referenceSignature is the HMAC-SHA256 of referencePayload under
+// referenceSecret, computed outside this package:
+//
+//	printf %s "$payload" | openssl dgst -sha256 -hmac "$secret"
+const (
+	referenceSecret    = "mheO6dR8ovSsxZQCOYEFCtelpuxcWGTfHw7te326y6jOwq5WpvFQ9JNljoTwBXZGv5It07m9RXSPpDQEK2w"
+	referencePayload   = "positionSide=LONG&quantity=5&side=BUY&symbol=BTC-USDT&timestamp=1667872120843&type=MARKET"
+	referenceSignature = "5d34c309435fa424fc7fd5b87baadae2de927032c11bb1580774ad6038180e66"
+)
+
+var referenceParams = map[string]string{
+	"symbol":       "BTC-USDT",
+	"side":         "BUY",
+	"positionSide": "LONG",
+	"type":         "MARKET",
+	"quantity":     "5",
+	"timestamp":    "1667872120843",
+}
+
+// WithSignatureDebug logs the details of every request rejected for its
+// signature or timestamp: the exact string that was signed, the parameter
+// order, the timestamp and its age, the signature algorithm, a fingerprint
+// of the secret (never the secret itself) and the result of
+// SignatureSelfCheck. It needs WithLogger. The signed string carries the
+// request parameters, so enable it while diagnosing only.
+func WithSignatureDebug() Option {
+	return func(c *Client) {
+		c.signDebug = true
+	}
+}
+
+// SignatureSelfCheck canonicalizes and signs a sample request with the
+// client's signer, and reports whether the signed string and the signature
+// match a known answer computed independently of this package. A mismatch
+// means requests can't be signed correctly whatever the keys. Signers other
+// than HMAC have no reference and return broker.ErrNotSupported.
+func (c *Client) SignatureSelfCheck() error {
+	if algorithm := c.signer.Algorithm(); algorithm != "HMAC-SHA256" {
+		return fmt.Errorf("bingx: no reference signature for %s: %w", algorithm, broker.ErrNotSupported)
+	}
+	payload, _ := encodeParams(referenceParams)
+	if payload != referencePayload {
+		return fmt.Errorf("bingx: signature self-check failed: signed %q, want %q", payload, referencePayload)
+	}
+	signature, err := c.signer.Sign(referenceSecret, []byte(payload))
+	if err != nil {
+		return err
+	}
+	if signature != referenceSignature {
+		return fmt.Errorf("bingx: signature self-check failed: got %s, want %s", signature, referenceSignature)
+	}
+	return nil
+}
+
+// signedRequest is what a request was signed with, kept for debugging
+type signedRequest struct {
+	method    string
+	endpoint  string
+	payload   string            // Exact string that was signed
+	params    map[string]string // Signed parameters, sorted only when logged
+	timestamp int64
+	secret    string
+}
+
+// debugSignature logs sr when the response shows an authentication failure
+func (c *Client) debugSignature(sr signedRequest, body []byte, err error) {
+	if !c.signDebug {
+		return
+	}
+	code, msg := authFailure(body, err)
+	if code == "" {
+		return
+	}
+
+	selfCheck := "ok"
+	if err := c.SignatureSelfCheck(); err != nil {
+		selfCheck = err.Error()
+	}
+	c.log.transport.Warn("authentication failed, signature debug",
+		"method", sr.method,
+		"path", sr.endpoint,
+		"code", code,
+		"msg", msg,
+		"signed_payload", sr.payload,
+		"param_order", strings.Join(adk.SortedKeys(sr.params), ","),
+		"timestamp", sr.timestamp,
+		"timestamp_age", time.Since(time.UnixMilli(sr.timestamp)),
+		"algorithm", c.signer.Algorithm(),
+		"key_fingerprint", fingerprint(sr.secret),
+		"self_check", selfCheck,
+	)
+}
+
+// authFailure returns the code and message of a response rejected for its
+// signature, timestamp or keys, "" for other responses
+func authFailure(body []byte, err error) (code, msg string) {
+	if err != nil {
+		var brokerErr *broker.BrokerError
+		if errors.Is(err, broker.ErrAuthFailed) && errors.As(err, &brokerErr) {
+			return brokerErr.Code, brokerErr.Message
+		}
+		return "", ""
+	}
+	if !bytes.Contains(body, []byte(strconv.Itoa(APISignatureErrorCode))) &&
+		!bytes.Contains(body, []byte(strconv.Itoa(APITimestampErrorCode))) {
+		return "", ""
+	}
+
+	var response struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if json.Unmarshal(body, &response) != nil ||
+		(response.Code != APISignatureErrorCode && response.Code != APITimestampErrorCode) {
+		return "", ""
+	}
+	return fmt.Sprintf("API_%d", response.Code), response.Msg
+}
+
+// fingerprint describes a secret without revealing it: its length, a short
+// SHA-256 prefix to compare against the expected key, and whether it has
+// surrounding whitespace (a common copy-paste mistake)
+func fingerprint(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return fmt.Sprintf("len=%d sha256=%s whitespace=%t", len(secret), hex.EncodeToString(sum[:4]),
+		strings.TrimSpace(secret) != secret)
+}
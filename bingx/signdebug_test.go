@@ -0,0 +1,71 @@
+package bingx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/signing"
+)
+
+func TestClient_SignatureSelfCheck(t *testing.T) {
+	if err := NewClient("key", "secret", false).SignatureSelfCheck(); err != nil {
+		t.Errorf("SignatureSelfCheck() = %v", err)
+	}
+	c := NewClient("key", "secret", false, WithSigner(signing.Ed25519()))
+	if err := c.SignatureSelfCheck(); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("SignatureSelfCheck(Ed25519) = %v, want ErrNotSupported", err)
+	}
+	c = NewClient("key", "secret", false, WithSigner(upperHexSigner{signing.HMAC()}))
+	if err := c.SignatureSelfCheck(); err == nil {
+		t.Error("SignatureSelfCheck() passed with a signer encoding upper-case hex")
+	}
+}
+
+// upperHexSigner is an HMAC signer with the wrong encoding
+type upperHexSigner struct{ signing.Signer }
+
+func (s upperHexSigner) Sign(secret string, payload []byte) (string, error) {
+	signature, err := s.Signer.Sign(secret, payload)
+	return strings.ToUpper(signature), err
+}
+
+func TestClient_SignatureDebug(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":100001,"msg":"Signature verification failed"}`))
+	}))
+	defer server.Close()
+
+	const secret = "topsecretvalue123 "
+	var logs bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logs, nil))
+
+	c := NewClient("key", secret, false, WithBaseURL(server.URL), WithLogger(logger), WithSignatureDebug())
+	if _, err := c.GetBalance(context.Background()); err == nil {
+		t.Fatal("GetBalance() succeeded against a signature failure")
+	}
+
+	out := logs.String()
+	for _, want := range []string{"signature debug", "code=API_100001", "param_order=timestamp", `signed_payload="timestamp=`, "whitespace=true", "self_check=ok"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, strings.TrimSpace(secret)) {
+		t.Error("log contains the secret")
+	}
+
+	// Without the option nothing is logged
+	logs.Reset()
+	c = NewClient("key", secret, false, WithBaseURL(server.URL), WithLogger(logger))
+	c.GetBalance(context.Background())
+	if strings.Contains(logs.String(), "signature debug") {
+		t.Error("signature debug logged without WithSignatureDebug")
+	}
+}
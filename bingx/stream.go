@@ -0,0 +1,265 @@
+package bingx
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/framelog"
+	"github.com/agatticelli/trading-go/internal/ws"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// streamMessage is the envelope of every market stream push
+type streamMessage struct {
+	ID       string          `json:"id"`
+	Code     int             `json:"code"`
+	Msg      string          `json:"msg"`
+	DataType string          `json:"dataType"`
+	Data     json.RawMessage `json:"data"`
+}
+
+// TradeData is a single trade in a <symbol>@trade push
+type TradeData struct {
+	Time         int64     `json:"T"`
+	Symbol       string    `json:"s"`
+	BuyerIsMaker bool      `json:"m"`
+	Price        FlexFloat `json:"p"`
+	Quantity     FlexFloat `json:"q"`
+}
+
+// StreamTrades calls handler for every public trade on symbol until the
+// context is canceled or the connection fails
+func (c *Client) StreamTrades(ctx context.Context, symbol string, handler func(broker.Trade)) error {
+	return c.subscribe(ctx, symbol+"@trade", tradeParser(handler))
+}
+
+// ReplayTrades feeds a trade stream frame recorded with WithFrameLog through
+// the stream parser, calling handler as StreamTrades did when it arrived
+func ReplayTrades(frame framelog.Frame, handler func(broker.Trade)) error {
+	payload, err := decodeFrame(nil, frame.Binary, frame.Data)
+	if err != nil || string(payload) == "Ping" {
+		return err
+	}
+	return dispatch(logging.Discard(), payload, frame.Stream, tradeParser(handler))
+}
+
+// tradeParser converts <symbol>@trade payloads to trades for handler
+func tradeParser(handler func(broker.Trade)) func(json.RawMessage) error {
+	var (
+		trades []broker.Trade // Reused across pushes
+		symbol string
+	)
+	return func(data json.RawMessage) error {
+		var ok bool
+		if trades, ok = scanTrades(data, trades[:0], &symbol); !ok {
+			var pushed []TradeData
+			if err := json.Unmarshal(data, &pushed); err != nil {
+				return broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse trade push", err)
+			}
+			trades = trades[:0]
+			for _, t := range pushed {
+				trades = append(trades, t.trade())
+			}
+		}
+
+		for _, t := range trades {
+			handler(t)
+		}
+		return nil
+	}
+}
+
+// trade converts a pushed trade
+func (t TradeData) trade() broker.Trade {
+	// The maker was the buyer, so the aggressor sold
+	side := broker.SideLong
+	if t.BuyerIsMaker {
+		side = broker.SideShort
+	}
+	return broker.Trade{
+		Symbol: t.Symbol,
+		Price:  t.Price.Float64(),
+		Size:   t.Quantity.Float64(),
+		Side:   side,
+		Time:   time.UnixMilli(t.Time),
+	}
+}
+
+// depthLevels are the book depths the depth stream offers
+var depthLevels = []int{5, 10, 20, 50, 100}
+
+// DepthData is a <symbol>@depth<N>@500ms push. Levels are [price, size]
+// pairs.
+type DepthData struct {
+	Time int64          `json:"T"`
+	Bids [][2]FlexFloat `json:"bids"`
+	Asks [][2]FlexFloat `json:"asks"`
+}
+
+// StreamDepth calls handler with the top levels of symbol's book every
+// 500ms until the context is canceled or the connection fails. levels is
+// rounded up to a depth the exchange offers (5, 10, 20, 50 or 100).
+func (c *Client) StreamDepth(ctx context.Context, symbol string, levels int, handler func(broker.Depth)) error {
+	i := slices.IndexFunc(depthLevels, func(n int) bool { return n >= levels })
+	if i < 0 {
+		return broker.NewBrokerError("bingx", "INVALID_DEPTH",
+			fmt.Sprintf("Depth of %d levels exceeds the maximum of %d", levels, depthLevels[len(depthLevels)-1]), broker.ErrNotSupported)
+	}
+	return c.subscribe(ctx, fmt.Sprintf("%s@depth%d@500ms", symbol, depthLevels[i]), depthParser(symbol, handler))
+}
+
+// depthParser converts depth payloads to books for handler. Levels are
+// sorted best first whatever order the push lists them in.
+func depthParser(symbol string, handler func(broker.Depth)) func(json.RawMessage) error {
+	var bids, asks []broker.Level // Reused across pushes
+	return func(data json.RawMessage) error {
+		var (
+			t  int64
+			ok bool
+		)
+		if t, bids, asks, ok = scanDepth(data, bids[:0], asks[:0]); !ok {
+			var depth DepthData
+			if err := json.Unmarshal(data, &depth); err != nil {
+				return broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse depth push", err)
+			}
+			t, bids, asks = depth.Time, toLevels(depth.Bids), toLevels(depth.Asks)
+		}
+
+		// The handler may keep the book, so it gets its own copy of the
+		// levels, in a single allocation
+		levels := make([]broker.Level, len(bids)+len(asks))
+		copy(levels, bids)
+		copy(levels[len(bids):], asks)
+		book := broker.Depth{
+			Symbol: symbol,
+			Bids:   levels[:len(bids):len(bids)],
+			Asks:   levels[len(bids):],
+			Time:   time.Now(),
+		}
+		if t > 0 {
+			book.Time = time.UnixMilli(t)
+		}
+		slices.SortFunc(book.Bids, func(a, b broker.Level) int { return cmp.Compare(b.Price, a.Price) })
+		slices.SortFunc(book.Asks, func(a, b broker.Level) int { return cmp.Compare(a.Price, b.Price) })
+		handler(book)
+		return nil
+	}
+}
+
+// toLevels converts [price, size] pairs
+func toLevels(pairs [][2]FlexFloat) []broker.Level {
+	levels := make([]broker.Level, len(pairs))
+	for i, p := range pairs {
+		levels[i] = broker.Level{Price: p[0].Float64(), Size: p[1].Float64()}
+	}
+	return levels
+}
+
+// subscribe opens a market stream connection, subscribes to dataType and
+// passes each matching payload to handler until the context is canceled,
+// the connection fails or handler returns an error
+func (c *Client) subscribe(ctx context.Context, dataType string, handler func(json.RawMessage) error) error {
+	ctx, release, err := c.life.stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	log := c.log.streamer.With("data_type", dataType)
+	conn, err := ws.Dial(ctx, c.streamURL, nil)
+	if err != nil {
+		log.Warn("stream connect failed", logging.KeyError, err)
+		return broker.NewBrokerError("bingx", "STREAM_FAILED", "Failed to connect market stream", err)
+	}
+	defer conn.Close()
+	log.Info("stream connected")
+
+	// Unblock ReadMessage when the context ends
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	id := strconv.FormatInt(time.Now().UnixNano(), 10)
+	sub, _ := json.Marshal(map[string]string{"id": id, "reqType": "sub", "dataType": dataType})
+	if err := conn.WriteMessage(ws.OpText, sub); err != nil {
+		return broker.NewBrokerError("bingx", "STREAM_FAILED", "Failed to subscribe", err)
+	}
+
+	var text []byte // Decompression buffer, reused across messages
+	for {
+		op, payload, err := conn.ReadMessage()
+		if err != nil {
+			if ctx.Err() != nil {
+				log.Info("stream closed")
+				return ctx.Err()
+			}
+			log.Warn("stream disconnected", logging.KeyError, err)
+			return broker.NewBrokerError("bingx", "STREAM_FAILED", "Market stream read failed", err)
+		}
+
+		if c.frames != nil {
+			frame := framelog.Frame{Time: time.Now(), Stream: dataType, Binary: op == ws.OpBinary, Data: payload}
+			if err := c.frames.Record(frame); err != nil {
+				log.Warn("recording frame failed", logging.KeyError, err)
+			}
+		}
+
+		if text, err = decodeFrame(text[:0], op == ws.OpBinary, payload); err != nil {
+			return err
+		}
+
+		// Application-level heartbeat
+		if string(text) == "Ping" {
+			if err := conn.WriteMessage(ws.OpText, []byte("Pong")); err != nil {
+				return broker.NewBrokerError("bingx", "STREAM_FAILED", "Failed to answer heartbeat", err)
+			}
+			continue
+		}
+
+		if err := dispatch(log, text, dataType, handler); err != nil {
+			return err
+		}
+	}
+}
+
+// decodeFrame appends the text of a stream frame to dst. Pushes are
+// gzip-compressed binary frames; deflate and uncompressed binary frames
+// are accepted too.
+func decodeFrame(dst []byte, binary bool, payload []byte) ([]byte, error) {
+	if !binary {
+		return append(dst, payload...), nil
+	}
+	text, err := ws.Decompress(dst, payload)
+	if err != nil {
+		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to decompress stream message", err)
+	}
+	return text, nil
+}
+
+// dispatch parses a stream message and passes its data to handler when it
+// is a push of dataType. Subscription acks and unrelated pushes are skipped.
+// The data aliases payload, so handler must not keep it.
+func dispatch(log *slog.Logger, payload []byte, dataType string, handler func(json.RawMessage) error) error {
+	code, pushed, data, ok := scanPush(payload)
+	if !ok || code != APISuccessCode {
+		var msg streamMessage
+		if err := json.Unmarshal(payload, &msg); err != nil {
+			return broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse stream message", err)
+		}
+		if msg.Code != APISuccessCode {
+			log.Warn("stream error", "code", msg.Code, "msg", msg.Msg)
+			return broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", msg.Code), msg.Msg, nil)
+		}
+		pushed, data = []byte(msg.DataType), msg.Data
+	}
+	if string(pushed) != dataType || len(data) == 0 || string(data) == "null" {
+		return nil
+	}
+	return handler(data)
+}
@@ -0,0 +1,283 @@
+package bingx
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/framelog"
+	"github.com/agatticelli/trading-go/internal/ws"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+func gzipped(s string) []byte {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	w.Write([]byte(s))
+	w.Close()
+	return buf.Bytes()
+}
+
+// newStreamServer accepts one connection, checks the subscription and
+// replays messages as gzip binary frames
+func newStreamServer(t *testing.T, wantDataType string, messages ...string) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := ws.Upgrade(w, r)
+		if err != nil {
+			t.Errorf("Upgrade() error = %v", err)
+			return
+		}
+		defer conn.Close()
+
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var sub map[string]string
+		json.Unmarshal(data, &sub)
+		if sub["reqType"] != "sub" || sub["dataType"] != wantDataType {
+			t.Errorf("subscription = %v, want sub %s", sub, wantDataType)
+		}
+
+		for _, msg := range messages {
+			conn.WriteMessage(ws.OpBinary, gzipped(msg))
+			if msg == "Ping" {
+				if _, pong, _ := conn.ReadMessage(); string(pong) != "Pong" {
+					t.Errorf("heartbeat reply = %q, want Pong", pong)
+				}
+			}
+		}
+
+		// Hold the connection open until the client leaves
+		conn.ReadMessage()
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestClient_StreamTrades(t *testing.T) {
+	url := newStreamServer(t, "BTC-USDT@trade",
+		`{"id":"1","code":0,"msg":"","dataType":"","data":null}`,
+		"Ping",
+		`{"code":0,"dataType":"BTC-USDT@trade","data":[
+			{"T":1700000000000,"s":"BTC-USDT","m":false,"p":"43000.5","q":"0.010"},
+			{"T":1700000000001,"s":"BTC-USDT","m":true,"p":"43000.0","q":"0.250"}]}`,
+	)
+
+	c := NewClient("key", "secret", false, WithStreamURL(url))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var trades []broker.Trade
+	err := c.StreamTrades(ctx, "BTC-USDT", func(tr broker.Trade) {
+		trades = append(trades, tr)
+		if len(trades) == 2 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("StreamTrades() error = %v, want %v", err, context.Canceled)
+	}
+
+	want := []broker.Trade{
+		{Symbol: "BTC-USDT", Price: 43000.5, Size: 0.01, Side: broker.SideLong, Time: time.UnixMilli(1700000000000)},
+		{Symbol: "BTC-USDT", Price: 43000, Size: 0.25, Side: broker.SideShort, Time: time.UnixMilli(1700000000001)},
+	}
+	if len(trades) != len(want) {
+		t.Fatalf("got %d trades, want %d", len(trades), len(want))
+	}
+	for i := range want {
+		if trades[i] != want[i] {
+			t.Errorf("trade %d = %+v, want %+v", i, trades[i], want[i])
+		}
+	}
+}
+
+func TestClient_StreamTrades_FrameLog(t *testing.T) {
+	url := newStreamServer(t, "BTC-USDT@trade",
+		`{"id":"1","code":0,"msg":"","dataType":"","data":null}`,
+		"Ping",
+		`{"code":0,"dataType":"BTC-USDT@trade","data":[{"T":1700000000000,"s":"BTC-USDT","m":false,"p":"43000.5","q":"0.010"}]}`,
+	)
+	dir := t.TempDir()
+	frames, err := framelog.Open(framelog.Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	c := NewClient("key", "secret", false, WithStreamURL(url), WithFrameLog(frames))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var live []broker.Trade
+	c.StreamTrades(ctx, "BTC-USDT", func(tr broker.Trade) {
+		live = append(live, tr)
+		cancel()
+	})
+	frames.Close()
+
+	files, err := framelog.Files(dir, "")
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	var recorded int
+	var replayed []broker.Trade
+	err = framelog.ReadFiles(files, func(f framelog.Frame) error {
+		recorded++
+		if f.Stream != "BTC-USDT@trade" || !f.Binary {
+			t.Errorf("frame stream = %q binary = %v, want BTC-USDT@trade binary", f.Stream, f.Binary)
+		}
+		return ReplayTrades(f, func(tr broker.Trade) { replayed = append(replayed, tr) })
+	})
+	if err != nil {
+		t.Fatalf("replay error = %v", err)
+	}
+	if recorded != 3 {
+		t.Errorf("recorded %d frames, want 3", recorded)
+	}
+	if len(live) != 1 || len(replayed) != 1 || replayed[0] != live[0] {
+		t.Errorf("replayed trades = %+v, want %+v", replayed, live)
+	}
+}
+
+func TestClient_StreamDepth(t *testing.T) {
+	url := newStreamServer(t, "BTC-USDT@depth10@500ms",
+		`{"code":0,"dataType":"BTC-USDT@depth10@500ms","data":{"T":1700000000000,
+			"bids":[["42999.5","2.0"],["43000.0","1.5"]],
+			"asks":[["43001.0","0.7"],["43000.5","0.3"]]}}`,
+	)
+	c := NewClient("key", "secret", false, WithStreamURL(url))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var got broker.Depth
+	err := c.StreamDepth(ctx, "BTC-USDT", 8, func(d broker.Depth) {
+		got = d
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("StreamDepth() error = %v, want %v", err, context.Canceled)
+	}
+
+	want := broker.Depth{
+		Symbol: "BTC-USDT",
+		Bids:   []broker.Level{{Price: 43000, Size: 1.5}, {Price: 42999.5, Size: 2}},
+		Asks:   []broker.Level{{Price: 43000.5, Size: 0.3}, {Price: 43001, Size: 0.7}},
+		Time:   time.UnixMilli(1700000000000),
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("depth = %+v, want %+v", got, want)
+	}
+}
+
+func TestClient_StreamDepth_TooDeep(t *testing.T) {
+	c := NewClient("key", "secret", false)
+	err := c.StreamDepth(context.Background(), "BTC-USDT", 500, func(broker.Depth) {})
+	if !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("StreamDepth() error = %v, want %v", err, broker.ErrNotSupported)
+	}
+}
+
+func TestClient_StreamTrades_APIError(t *testing.T) {
+	url := newStreamServer(t, "BAD@trade", `{"code":80015,"msg":"dataType not supported"}`)
+	c := NewClient("key", "secret", false, WithStreamURL(url))
+
+	err := c.StreamTrades(context.Background(), "BAD", func(broker.Trade) {})
+	var brokerErr *broker.BrokerError
+	if !errors.As(err, &brokerErr) || brokerErr.Code != "API_80015" {
+		t.Errorf("StreamTrades() error = %v, want API_80015", err)
+	}
+}
+
+func TestDecodeFrame(t *testing.T) {
+	const push = `{"code":0,"dataType":"BTC-USDT@trade","data":[]}`
+	var deflated bytes.Buffer
+	w, _ := flate.NewWriter(&deflated, flate.BestSpeed)
+	w.Write([]byte(push))
+	w.Close()
+
+	tests := []struct {
+		name    string
+		binary  bool
+		payload []byte
+	}{
+		{"gzip", true, gzipped(push)},
+		{"deflate", true, deflated.Bytes()},
+		{"uncompressed binary", true, []byte(push)},
+		{"text", false, []byte(push)},
+	}
+	for _, tt := range tests {
+		got, err := decodeFrame(nil, tt.binary, tt.payload)
+		if err != nil || string(got) != push {
+			t.Errorf("%s: decodeFrame() = %q, %v, want the push", tt.name, got, err)
+		}
+	}
+
+	if _, err := decodeFrame(nil, true, []byte{0x1f, 0x8b, 0x08}); err == nil {
+		t.Error("decodeFrame(truncated gzip) succeeded")
+	}
+}
+
+// depthFrame is a gzip-compressed 100-level depth push as BingX sends it
+func depthFrame() []byte {
+	var b strings.Builder
+	b.WriteString(`{"code":0,"dataType":"BTC-USDT@depth100@500ms","data":{"T":1760500000000,"bids":[`)
+	for i := range 100 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `["%.1f","%.4f"]`, 65000-float64(i)*0.1, 0.1+float64(i)/1000)
+	}
+	b.WriteString(`],"asks":[`)
+	for i := range 100 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `["%.1f","%.4f"]`, 65000.1+float64(i)*0.1, 0.2+float64(i)/1000)
+	}
+	b.WriteString(`]}}`)
+	return gzipped(b.String())
+}
+
+// BenchmarkDepthPush compares decompressing a full depth push with the rest
+// of handling it, to keep decompression from becoming the bottleneck
+func BenchmarkDepthPush(b *testing.B) {
+	frame := depthFrame()
+	text, _ := decodeFrame(nil, true, frame)
+	parse := depthParser("BTC-USDT", func(broker.Depth) {})
+
+	b.Run("decompress", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for b.Loop() {
+			buf, _ = decodeFrame(buf[:0], true, frame)
+		}
+	})
+	b.Run("parse", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if err := dispatch(logging.Discard(), text, "BTC-USDT@depth100@500ms", parse); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("total", func(b *testing.B) {
+		b.ReportAllocs()
+		var buf []byte
+		for b.Loop() {
+			buf, _ = decodeFrame(buf[:0], true, frame)
+			dispatch(logging.Discard(), buf, "BTC-USDT@depth100@500ms", parse)
+		}
+	})
+}
@@ -0,0 +1,291 @@
+package bingx
+
+import (
+	"strconv"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Stream pushes arrive by the thousand per second, and encoding/json spends
+// most of their handling time on reflection and small allocations. The
+// scanner below reads the fixed push schemas in place instead. Anything it
+// doesn't expect (escaped strings, unknown shapes, malformed numbers) makes
+// it report failure, and the caller decodes with encoding/json as before.
+
+// scanner walks JSON without allocating. After the first failure every
+// method is a no-op and ok stays false.
+type scanner struct {
+	buf []byte
+	pos int
+	ok  bool
+}
+
+func newScanner(data []byte) *scanner {
+	return &scanner{buf: data, ok: true}
+}
+
+// space skips whitespace
+func (s *scanner) space() {
+	for s.pos < len(s.buf) {
+		switch s.buf[s.pos] {
+		case ' ', '\t', '\n', '\r':
+			s.pos++
+		default:
+			return
+		}
+	}
+}
+
+// consume skips whitespace and c, reporting whether c was next
+func (s *scanner) consume(c byte) bool {
+	s.space()
+	if s.ok && s.pos < len(s.buf) && s.buf[s.pos] == c {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// expect consumes c or fails
+func (s *scanner) expect(c byte) {
+	if !s.consume(c) {
+		s.ok = false
+	}
+}
+
+// peek returns the next non-space byte, or 0 at the end
+func (s *scanner) peek() byte {
+	s.space()
+	if !s.ok || s.pos >= len(s.buf) {
+		return 0
+	}
+	return s.buf[s.pos]
+}
+
+// str reads a string without escape sequences
+func (s *scanner) str() []byte {
+	s.expect('"')
+	start := s.pos
+	for s.ok && s.pos < len(s.buf) {
+		switch s.buf[s.pos] {
+		case '"':
+			s.pos++
+			return s.buf[start : s.pos-1]
+		case '\\':
+			s.ok = false
+			return nil
+		}
+		s.pos++
+	}
+	s.ok = false
+	return nil
+}
+
+// token reads a number, true, false or null
+func (s *scanner) token() []byte {
+	s.space()
+	start := s.pos
+	for s.ok && s.pos < len(s.buf) {
+		switch s.buf[s.pos] {
+		case ',', '}', ']', ' ', '\t', '\n', '\r':
+			return s.buf[start:s.pos]
+		}
+		s.pos++
+	}
+	if start == s.pos {
+		s.ok = false
+	}
+	return s.buf[start:s.pos]
+}
+
+// value skips any value, returning its raw bytes
+func (s *scanner) value() []byte {
+	switch s.peek() {
+	case '"':
+		start := s.pos
+		s.str()
+		return s.buf[start:s.pos]
+	case '{', '[':
+		start, depth := s.pos, 0
+		for s.ok && s.pos < len(s.buf) {
+			switch s.buf[s.pos] {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					s.pos++
+					return s.buf[start:s.pos]
+				}
+			case '"':
+				s.str()
+				continue
+			}
+			s.pos++
+		}
+		s.ok = false
+		return nil
+	case 0:
+		s.ok = false
+		return nil
+	}
+	return s.token()
+}
+
+// object calls field with each key of an object; field must consume the
+// value
+func (s *scanner) object(field func(key []byte)) {
+	s.expect('{')
+	if s.consume('}') {
+		return
+	}
+	for s.ok {
+		key := s.str()
+		s.expect(':')
+		if !s.ok {
+			return
+		}
+		field(key)
+		if !s.consume(',') {
+			s.expect('}')
+			return
+		}
+	}
+}
+
+// array calls elem for each element of an array; elem must consume it
+func (s *scanner) array(elem func()) {
+	s.expect('[')
+	if s.consume(']') {
+		return
+	}
+	for s.ok {
+		elem()
+		if !s.consume(',') {
+			s.expect(']')
+			return
+		}
+	}
+}
+
+// float reads a number, numeric string or null like FlexFloat
+func (s *scanner) float() float64 {
+	var b []byte
+	if s.peek() == '"' {
+		b = s.str()
+	} else if b = s.token(); string(b) == "null" {
+		return 0
+	}
+	if !s.ok || len(b) == 0 {
+		return 0
+	}
+	f, err := strconv.ParseFloat(string(b), 64)
+	if err != nil {
+		s.ok = false
+	}
+	return f
+}
+
+// int reads an integer
+func (s *scanner) int() int64 {
+	n, err := strconv.ParseInt(string(s.token()), 10, 64)
+	if err != nil {
+		s.ok = false
+	}
+	return n
+}
+
+// bool reads true or false
+func (s *scanner) bool() bool {
+	switch string(s.token()) {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	s.ok = false
+	return false
+}
+
+// end fails unless only whitespace is left
+func (s *scanner) end() bool {
+	s.space()
+	return s.ok && s.pos == len(s.buf)
+}
+
+// scanPush reads the envelope of a stream message, returning its data
+// undecoded. The slices alias payload.
+func scanPush(payload []byte) (code int64, dataType, data []byte, ok bool) {
+	s := newScanner(payload)
+	s.object(func(key []byte) {
+		switch string(key) {
+		case "code":
+			code = s.int()
+		case "dataType":
+			dataType = s.str()
+		case "data":
+			data = s.value()
+		default:
+			s.value()
+		}
+	})
+	return code, dataType, data, s.end()
+}
+
+// scanDepth reads a DepthData push, appending its levels to bids and asks
+func scanDepth(data []byte, bids, asks []broker.Level) (t int64, _, _ []broker.Level, ok bool) {
+	s := newScanner(data)
+	levels := func(dst []broker.Level) []broker.Level {
+		s.array(func() {
+			s.expect('[')
+			price := s.float()
+			s.expect(',')
+			size := s.float()
+			s.expect(']')
+			dst = append(dst, broker.Level{Price: price, Size: size})
+		})
+		return dst
+	}
+	s.object(func(key []byte) {
+		switch string(key) {
+		case "T":
+			t = s.int()
+		case "bids":
+			bids = levels(bids)
+		case "asks":
+			asks = levels(asks)
+		default:
+			s.value()
+		}
+	})
+	return t, bids, asks, s.end()
+}
+
+// scanTrades reads a []TradeData push, appending the trades to dst. symbol
+// holds the last symbol seen, so repeated symbols share one string.
+func scanTrades(data []byte, dst []broker.Trade, symbol *string) ([]broker.Trade, bool) {
+	s := newScanner(data)
+	s.array(func() {
+		var t TradeData
+		s.object(func(key []byte) {
+			switch string(key) {
+			case "T":
+				t.Time = s.int()
+			case "s":
+				if sym := s.str(); string(sym) != *symbol {
+					*symbol = string(sym)
+				}
+				t.Symbol = *symbol
+			case "m":
+				t.BuyerIsMaker = s.bool()
+			case "p":
+				t.Price = FlexFloat(s.float())
+			case "q":
+				t.Quantity = FlexFloat(s.float())
+			default:
+				s.value()
+			}
+		})
+		dst = append(dst, t.trade())
+	})
+	return dst, s.end()
+}
@@ -0,0 +1,175 @@
+package bingx
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+func TestScanPush(t *testing.T) {
+	tests := []struct {
+		payload      string
+		wantCode     int64
+		wantDataType string
+		wantData     string
+		wantOK       bool
+	}{
+		{`{"code":0,"dataType":"BTC-USDT@trade","data":[{"p":"1"}]}`, 0, "BTC-USDT@trade", `[{"p":"1"}]`, true},
+		{` { "id" : "1", "code" : 0 , "dataType":"x", "data" : {"a":[1,{"b":"]"}]} } `, 0, "x", `{"a":[1,{"b":"]"}]}`, true},
+		{`{"code":80015,"msg":"dataType not supported"}`, 80015, "", "", true},
+		{`{"code":0,"msg":"say \"hi\"","dataType":"x"}`, 0, "", "", false},
+		{`{"code":0,"dataType":"x"`, 0, "", "", false},
+		{`{"code":0} trailing`, 0, "", "", false},
+	}
+	for _, tt := range tests {
+		code, dataType, data, ok := scanPush([]byte(tt.payload))
+		if ok != tt.wantOK {
+			t.Errorf("scanPush(%s) ok = %v, want %v", tt.payload, ok, tt.wantOK)
+			continue
+		}
+		if ok && (code != tt.wantCode || string(dataType) != tt.wantDataType || string(data) != tt.wantData) {
+			t.Errorf("scanPush(%s) = %d, %q, %s, want %d, %q, %s", tt.payload, code, dataType, data, tt.wantCode, tt.wantDataType, tt.wantData)
+		}
+	}
+}
+
+// TestDepthParser_MatchesEncodingJSON feeds pushes the scanner handles and
+// pushes that fall back to encoding/json through the parser
+func TestDepthParser_MatchesEncodingJSON(t *testing.T) {
+	pushes := []string{
+		`{"T":1700000000000,"bids":[["43000.0","1.5"],["42999.5","2.0"]],"asks":[["43000.5","0.3"]]}`,
+		`{"asks":[[43000.5,0.3]],"bids":[[43000,1.5]],"T":1700000000000}`,
+		`{"T":1700000000000,"bids":[["43000.0",null],["",""]],"asks":[]}`,
+		`{"T":1700000000000,"lastUpdateId":"a\"b","bids":[["43000.0","1.5"]],"asks":[]}`,
+		`{"bids":[["43000.0","1.5","extra"]],"asks":null}`,
+		`{"bids":[["x","1"]]}`, // Non-numeric, zero like FlexFloat
+	}
+	for _, push := range pushes {
+		var got broker.Depth
+		if err := depthParser("BTC-USDT", func(d broker.Depth) { got = d })(json.RawMessage(push)); err != nil {
+			t.Fatalf("depthParser(%s) error = %v", push, err)
+		}
+
+		var depth DepthData
+		if err := json.Unmarshal([]byte(push), &depth); err != nil {
+			t.Fatal(err)
+		}
+		bids, asks := toLevels(depth.Bids), toLevels(depth.Asks)
+		sortBook(bids, asks)
+		if fmt.Sprint(got.Bids, got.Asks) != fmt.Sprint(bids, asks) {
+			t.Errorf("depthParser(%s) = %v %v, want %v %v", push, got.Bids, got.Asks, bids, asks)
+		}
+	}
+
+	if err := depthParser("BTC-USDT", func(broker.Depth) {})(json.RawMessage(`{"bids":[[true,"1"]]}`)); err == nil {
+		t.Error("depthParser(bad price) succeeded")
+	}
+}
+
+func TestTradeParser_MatchesEncodingJSON(t *testing.T) {
+	pushes := []string{
+		`[{"T":1700000000000,"s":"BTC-USDT","m":true,"p":"43000.5","q":"0.010"},{"T":1700000000001,"s":"BTC-USDT","m":false,"p":43001,"q":0.5}]`,
+		`[{"T":1700000000000,"s":"ETH-USDT","m":false,"p":"2300","q":"1"},{"T":1700000000000,"m":false,"p":"2300","q":"1"}]`,
+		`[{"T":1700000000000,"s":"BTC-USDT","m":true,"p":"1","q":"1"}]`,
+		`[]`,
+	}
+	parse := func(push string) []broker.Trade {
+		var trades []broker.Trade
+		if err := tradeParser(func(tr broker.Trade) { trades = append(trades, tr) })(json.RawMessage(push)); err != nil {
+			t.Fatalf("tradeParser(%s) error = %v", push, err)
+		}
+		return trades
+	}
+	for _, push := range pushes {
+		var pushed []TradeData
+		if err := json.Unmarshal([]byte(push), &pushed); err != nil {
+			t.Fatal(err)
+		}
+		var want []broker.Trade
+		for _, tr := range pushed {
+			want = append(want, tr.trade())
+		}
+		if got := parse(push); !reflect.DeepEqual(got, want) {
+			t.Errorf("tradeParser(%s) = %+v, want %+v", push, got, want)
+		}
+	}
+}
+
+// sortBook orders levels best first like depthParser
+func sortBook(bids, asks []broker.Level) {
+	slices.SortFunc(bids, func(a, b broker.Level) int { return cmp.Compare(b.Price, a.Price) })
+	slices.SortFunc(asks, func(a, b broker.Level) int { return cmp.Compare(a.Price, b.Price) })
+}
+
+// depthPush is the text of depthFrame
+func depthPush(b *testing.B) []byte {
+	text, err := decodeFrame(nil, true, depthFrame())
+	if err != nil {
+		b.Fatal(err)
+	}
+	return text
+}
+
+func BenchmarkDecodeDepth(b *testing.B) {
+	payload := depthPush(b)
+	const dataType = "BTC-USDT@depth100@500ms"
+
+	b.Run("scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		parse := depthParser("BTC-USDT", func(broker.Depth) {})
+		for b.Loop() {
+			if err := dispatch(logging.Discard(), payload, dataType, parse); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var msg streamMessage
+			var depth DepthData
+			if json.Unmarshal(payload, &msg) != nil || json.Unmarshal(msg.Data, &depth) != nil {
+				b.Fatal("unmarshal failed")
+			}
+			bids, asks := toLevels(depth.Bids), toLevels(depth.Asks)
+			sortBook(bids, asks)
+		}
+	})
+}
+
+func BenchmarkDecodeTrades(b *testing.B) {
+	payload := []byte(`{"code":0,"dataType":"BTC-USDT@trade","data":[` +
+		`{"T":1760500000000,"s":"BTC-USDT","m":true,"p":"65000.1","q":"0.0100"},` +
+		`{"T":1760500000001,"s":"BTC-USDT","m":false,"p":"65000.2","q":"0.2500"},` +
+		`{"T":1760500000002,"s":"BTC-USDT","m":false,"p":"65000.2","q":"1.0000"}]}`)
+	const dataType = "BTC-USDT@trade"
+
+	b.Run("scanner", func(b *testing.B) {
+		b.ReportAllocs()
+		parse := tradeParser(func(broker.Trade) {})
+		for b.Loop() {
+			if err := dispatch(logging.Discard(), payload, dataType, parse); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("encoding/json", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var msg streamMessage
+			var trades []TradeData
+			if json.Unmarshal(payload, &msg) != nil || json.Unmarshal(msg.Data, &trades) != nil {
+				b.Fatal("unmarshal failed")
+			}
+			for _, t := range trades {
+				_ = t.trade()
+			}
+		}
+	})
+}
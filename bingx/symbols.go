@@ -0,0 +1,88 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// ListSymbols lists the USDT-margined perpetual contracts matching filter
+// (nil = all) with their listing status and 24h traded value
+func (c *Client) ListSymbols(ctx context.Context, filter *broker.SymbolFilter) ([]broker.SymbolInfo, error) {
+	contracts, err := c.GetContracts(ctx)
+	if err != nil {
+		return nil, err
+	}
+	volumes, err := c.quoteVolumes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var symbols []broker.SymbolInfo
+	for _, contract := range contracts {
+		info := broker.SymbolInfo{
+			Symbol:     contract.Symbol,
+			BaseAsset:  contract.Asset,
+			QuoteAsset: contract.Currency,
+			Status:     symbolStatus(contract),
+			LaunchTime: contract.LaunchTime,
+			DelistTime: contract.DelistTime,
+			Volume24h:  volumes[contract.Symbol],
+		}
+		if filter.Match(info) {
+			symbols = append(symbols, info)
+		}
+	}
+	return symbols, nil
+}
+
+// quoteVolumes returns the 24h traded value of every symbol
+func (c *Client) quoteVolumes(ctx context.Context) (map[string]float64, error) {
+	body, err := c.makePublicRequest(ctx, "GET", EndpointTickers, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var response TickersResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse tickers response", err)
+	}
+
+	if response.Code != APISuccessCode {
+		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	}
+
+	volumes := make(map[string]float64, len(response.Data))
+	for _, t := range response.Data {
+		volumes[t.Symbol] = t.QuoteVolume.Float64()
+	}
+	return volumes, nil
+}
+
+// symbolStatus maps a contract's listing state. A scheduled delisting
+// takes precedence over the trading state.
+func symbolStatus(contract Contract) broker.SymbolStatus {
+	switch {
+	case !contract.DelistTime.IsZero():
+		return broker.SymbolDelisting
+	case contract.Tradable:
+		return broker.SymbolTrading
+	case contract.CloseOnly:
+		return broker.SymbolReduceOnly
+	case contract.Status == 5:
+		return broker.SymbolPending
+	default:
+		return broker.SymbolHalted
+	}
+}
+
+// unixMilli converts a millisecond timestamp, keeping 0 as the zero time
+func unixMilli(ms int64) time.Time {
+	if ms <= 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(ms)
+}
@@ -0,0 +1,78 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_ListSymbols(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case EndpointContracts:
+			w.Write([]byte(`{"code":0,"data":[
+				{"symbol":"BTC-USDT","asset":"BTC","currency":"USDT","status":1,"apiStateOpen":"true","launchTime":1600000000000},
+				{"symbol":"ETH-USDT","asset":"ETH","currency":"USDT","status":1,"apiStateOpen":"true","launchTime":1600000000000},
+				{"symbol":"OLD-USDT","asset":"OLD","currency":"USDT","status":1,"apiStateOpen":"true","offTime":1800000000000},
+				{"symbol":"ALT-USDT","asset":"ALT","currency":"USDT","status":1,"apiStateOpen":"false","apiStateClose":"true"},
+				{"symbol":"NEW-USDT","asset":"NEW","currency":"USDT","status":5,"apiStateOpen":"false"},
+				{"symbol":"BTC-USDC","asset":"BTC","currency":"USDC","status":0,"apiStateOpen":"false"}]}`))
+		case EndpointTickers:
+			w.Write([]byte(`{"code":0,"data":[
+				{"symbol":"BTC-USDT","lastPrice":"43000","quoteVolume":"900000000"},
+				{"symbol":"ETH-USDT","lastPrice":"2200","quoteVolume":"400000"},
+				{"symbol":"OLD-USDT","lastPrice":"1","quoteVolume":"5000000"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	all, err := c.ListSymbols(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListSymbols() error = %v", err)
+	}
+	want := map[string]broker.SymbolStatus{
+		"BTC-USDT": broker.SymbolTrading,
+		"ETH-USDT": broker.SymbolTrading,
+		"OLD-USDT": broker.SymbolDelisting,
+		"ALT-USDT": broker.SymbolReduceOnly,
+		"NEW-USDT": broker.SymbolPending,
+		"BTC-USDC": broker.SymbolHalted,
+	}
+	if len(all) != len(want) {
+		t.Fatalf("ListSymbols() returned %d symbols, want %d", len(all), len(want))
+	}
+	for _, s := range all {
+		if s.Status != want[s.Symbol] {
+			t.Errorf("%s status = %s, want %s", s.Symbol, s.Status, want[s.Symbol])
+		}
+	}
+	if all[0].LaunchTime != time.UnixMilli(1600000000000) || all[0].Volume24h != 900000000 || all[0].BaseAsset != "BTC" {
+		t.Errorf("BTC-USDT = %+v", all[0])
+	}
+	if !all[4].LaunchTime.IsZero() {
+		t.Errorf("NEW-USDT launch time = %v, want zero", all[4].LaunchTime)
+	}
+
+	liquid, err := c.ListSymbols(ctx, &broker.SymbolFilter{Quote: "USDT", MinVolume24h: 1000000, ExcludeDelisting: true})
+	if err != nil {
+		t.Fatalf("ListSymbols() error = %v", err)
+	}
+	if len(liquid) != 1 || liquid[0].Symbol != "BTC-USDT" {
+		t.Errorf("filtered symbols = %+v, want BTC-USDT only", liquid)
+	}
+}
+
+func TestClient_ListSymbols_CoinMargined(t *testing.T) {
+	c := NewClient("key", "secret", false, WithInstrumentType(InstrumentCoinMargined))
+	if _, err := c.ListSymbols(context.Background(), nil); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("ListSymbols() error = %v, want %v", err, broker.ErrNotSupported)
+	}
+}
@@ -0,0 +1,145 @@
+package bingx
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// WalletType identifies a BingX account wallet for internal transfers
+type WalletType string
+
+const (
+	WalletFund      WalletType = "FUND"     // Funding account
+	WalletPerpetual WalletType = "PFUTURES" // USDT-margined perpetual futures
+	WalletStandard  WalletType = "SFUTURES" // Standard contract futures
+
+	// WalletSpot is an alias of WalletFund: BingX's funding account is the spot wallet
+	WalletSpot = WalletFund
+)
+
+// transferTypes lists the wallet pairs BingX accepts as transfer types
+var transferTypes = map[string]bool{
+	"FUND_PFUTURES":     true,
+	"PFUTURES_FUND":     true,
+	"FUND_SFUTURES":     true,
+	"SFUTURES_FUND":     true,
+	"PFUTURES_SFUTURES": true,
+	"SFUTURES_PFUTURES": true,
+}
+
+// transferType builds the BingX transfer type for a wallet pair
+func transferType(from, to WalletType) (string, error) {
+	t := string(from) + "_" + string(to)
+	if !transferTypes[t] {
+		return "", fmt.Errorf("unsupported transfer from %s to %s", from, to)
+	}
+	return t, nil
+}
+
+// Transfer is a single internal transfer between wallets
+type Transfer struct {
+	ID        string
+	Asset     string
+	Amount    float64
+	From      WalletType
+	To        WalletType
+	Status    string
+	Timestamp time.Time
+}
+
+// TransferFilter narrows transfer history queries
+type TransferFilter struct {
+	From      WalletType // Required
+	To        WalletType // Required
+	StartTime time.Time
+	EndTime   time.Time
+	PageSize  int // Records per page (0 = 100)
+}
+
+// Transfer moves funds between wallets (e.g. funding → perpetual to top up
+// futures margin) and returns the exchange transfer ID
+func (c *Client) Transfer(ctx context.Context, asset string, amount float64, from, to WalletType) (string, error) {
+	if amount <= 0 {
+		return "", broker.ErrInvalidQuantity
+	}
+
+	tType, err := transferType(from, to)
+	if err != nil {
+		return "", err
+	}
+
+	params := map[string]string{
+		"type":   tType,
+		"asset":  asset,
+		"amount": strconv.FormatFloat(amount, 'f', -1, 64),
+	}
+
+	response, err := callUnwrapped[TransferResponse](ctx, c, "POST", EndpointTransfer, params, "transfer")
+	if err != nil {
+		return "", err
+	}
+	return strconv.FormatInt(response.TranID, 10), nil
+}
+
+// GetTransferHistory returns an iterator over past transfers between the
+// filter's wallet pair, newest first
+func (c *Client) GetTransferHistory(filter TransferFilter) *broker.Iterator[*Transfer] {
+	pageSize := filter.PageSize
+	if pageSize <= 0 {
+		pageSize = 100
+	}
+
+	fetch := func(ctx context.Context, req broker.PageRequest) ([]*Transfer, *broker.PageRequest, error) {
+		tType, err := transferType(filter.From, filter.To)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		params := map[string]string{
+			"type":    tType,
+			"current": strconv.Itoa(req.Page),
+			"size":    strconv.Itoa(req.Limit),
+		}
+		if !req.StartTime.IsZero() {
+			params["startTime"] = strconv.FormatInt(req.StartTime.UnixMilli(), 10)
+		}
+		if !req.EndTime.IsZero() {
+			params["endTime"] = strconv.FormatInt(req.EndTime.UnixMilli(), 10)
+		}
+
+		response, err := callUnwrapped[TransferHistoryResponse](ctx, c, "GET", EndpointTransferHistory, params, "transfer history")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		transfers := make([]*Transfer, 0, len(response.Rows))
+		for _, row := range response.Rows {
+			amount, _ := strconv.ParseFloat(row.Amount, 64)
+			transfers = append(transfers, &Transfer{
+				ID:        strconv.FormatInt(row.TranID, 10),
+				Asset:     row.Asset,
+				Amount:    amount,
+				From:      filter.From,
+				To:        filter.To,
+				Status:    row.Status,
+				Timestamp: time.UnixMilli(row.Timestamp),
+			})
+		}
+
+		if req.Page*req.Limit >= response.Total {
+			return transfers, nil, nil
+		}
+		return transfers, req.NextPage(), nil
+	}
+
+	return broker.NewIterator(fetch, broker.PageRequest{
+		Page:      1,
+		Limit:     pageSize,
+		StartTime: filter.StartTime,
+		EndTime:   filter.EndTime,
+	})
+}
@@ -0,0 +1,115 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_Transfer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != "POST" || r.URL.Path != EndpointTransfer {
+			t.Errorf("request = %s %s, want POST %s", r.Method, r.URL.Path, EndpointTransfer)
+		}
+		if q.Get("type") != "FUND_PFUTURES" {
+			t.Errorf("type = %q, want FUND_PFUTURES", q.Get("type"))
+		}
+		if q.Get("amount") != "250.5" {
+			t.Errorf("amount = %q, want 250.5", q.Get("amount"))
+		}
+		w.Write([]byte(`{"tranId":13526853623}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	id, err := c.Transfer(context.Background(), "USDT", 250.5, WalletFund, WalletPerpetual)
+	if err != nil {
+		t.Fatalf("Transfer() error = %v", err)
+	}
+	if id != "13526853623" {
+		t.Errorf("Transfer() id = %q, want 13526853623", id)
+	}
+}
+
+func TestClient_Transfer_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":109400,"msg":"asset not exist"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	_, err := c.Transfer(context.Background(), "XYZ", 1, WalletFund, WalletPerpetual)
+	var brokerErr *broker.BrokerError
+	if !errors.As(err, &brokerErr) || brokerErr.Code != "API_109400" || !errors.Is(err, broker.ErrInvalidSymbol) {
+		t.Errorf("Transfer() error = %v, want API_109400 matching ErrInvalidSymbol", err)
+	}
+}
+
+func TestClient_Transfer_Validation(t *testing.T) {
+	c := NewClient("key", "secret", false, WithBaseURL("http://127.0.0.1:0"))
+
+	if _, err := c.Transfer(context.Background(), "USDT", 0, WalletFund, WalletPerpetual); !errors.Is(err, broker.ErrInvalidQuantity) {
+		t.Errorf("zero amount error = %v, want ErrInvalidQuantity", err)
+	}
+	if _, err := c.Transfer(context.Background(), "USDT", 10, WalletFund, WalletSpot); err == nil {
+		t.Error("transfer between the same wallet should fail")
+	}
+}
+
+func TestClient_GetTransferHistory_Pages(t *testing.T) {
+	pages := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		pages++
+		current := r.URL.Query().Get("current")
+		switch current {
+		case "1":
+			w.Write([]byte(`{"total":3,"rows":[
+				{"asset":"USDT","amount":"10","status":"CONFIRMED","tranId":1,"timestamp":1700000000000},
+				{"asset":"USDT","amount":"20","status":"CONFIRMED","tranId":2,"timestamp":1700000001000}]}`))
+		case "2":
+			w.Write([]byte(`{"total":3,"rows":[
+				{"asset":"USDT","amount":"30","status":"CONFIRMED","tranId":3,"timestamp":1700000002000}]}`))
+		default:
+			t.Errorf("unexpected page %s", current)
+			w.Write([]byte(`{"total":3,"rows":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	transfers, err := broker.Collect(context.Background(), c.GetTransferHistory(TransferFilter{
+		From:     WalletFund,
+		To:       WalletPerpetual,
+		PageSize: 2,
+	}))
+	if err != nil {
+		t.Fatalf("GetTransferHistory() error = %v", err)
+	}
+
+	if pages != 2 {
+		t.Errorf("fetched %d pages, want 2", pages)
+	}
+	if len(transfers) != 3 {
+		t.Fatalf("len(transfers) = %d, want 3", len(transfers))
+	}
+	for i, tr := range transfers {
+		if want := fmt.Sprintf("%d", i+1); tr.ID != want {
+			t.Errorf("transfer %d ID = %q, want %q", i, tr.ID, want)
+		}
+		if tr.From != WalletFund || tr.To != WalletPerpetual {
+			t.Errorf("transfer %d wallets = %s→%s", i, tr.From, tr.To)
+		}
+	}
+	if transfers[2].Amount != 30 {
+		t.Errorf("transfer 3 amount = %v, want 30", transfers[2].Amount)
+	}
+}
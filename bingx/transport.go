@@ -0,0 +1,239 @@
+package bingx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/credentials"
+	"github.com/agatticelli/trading-go/internal/adk"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// bodyEncoding selects how signed parameters are carried in a request
+type bodyEncoding int
+
+const (
+	// encodingQuery sends parameters in the URL query string with no body
+	encodingQuery bodyEncoding = iota
+	// encodingForm sends parameters as an application/x-www-form-urlencoded body
+	encodingForm
+	// encodingJSON sends parameters as an application/json object body
+	encodingJSON
+)
+
+// sign signs the parameter string with the client's signer (HMAC-SHA256
+// unless WithSigner selected another scheme)
+func (c *Client) sign(secretKey, params string) (string, error) {
+	return adk.Sign("bingx", c.signer, secretKey, []byte(params))
+}
+
+// makeRequest makes an HTTP request to BingX API, retrying it when
+// WithRetry is set and classify allows
+func (c *Client) makeRequest(ctx context.Context, method, endpoint string, params map[string]string) ([]byte, error) {
+	return c.retry(ctx, classify(method, endpoint, params), func() ([]byte, error) {
+		return c.sendRequest(ctx, method, endpoint, params)
+	})
+}
+
+// makePublicRequest sends a request to a public market data endpoint. It is
+// signed like makeRequest when the client has API keys and sent unsigned by
+// clients created without them.
+func (c *Client) makePublicRequest(ctx context.Context, method, endpoint string, params map[string]string) ([]byte, error) {
+	if !c.public {
+		return c.makeRequest(ctx, method, endpoint, params)
+	}
+	return c.retry(ctx, classify(method, endpoint, params), func() ([]byte, error) {
+		fullURL := c.baseURL + endpoint
+		if _, encoded := encodeParams(params); encoded != "" {
+			fullURL += "?" + encoded
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		return c.execute(req, "")
+	})
+}
+
+// makeRequestWithBody sends the signed parameters in the request body
+// instead of the query string, retrying like makeRequest
+func (c *Client) makeRequestWithBody(ctx context.Context, method, endpoint string, params map[string]string, encoding bodyEncoding) ([]byte, error) {
+	return c.retry(ctx, classify(method, endpoint, params), func() ([]byte, error) {
+		return c.sendRequestWithBody(ctx, method, endpoint, params, encoding)
+	})
+}
+
+// sendRequest signs and sends one request with parameters in the query string
+func (c *Client) sendRequest(ctx context.Context, method, endpoint string, params map[string]string) ([]byte, error) {
+	creds, err := c.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := c.now().UnixMilli()
+
+	// Add timestamp to parameters
+	if params == nil {
+		params = make(map[string]string)
+	}
+	params["timestamp"] = strconv.FormatInt(timestamp, 10)
+
+	// Sign the raw parameters, send them encoded
+	payload, queryString := encodeParams(params)
+	signature, err := c.sign(creds.SecretKey, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add signature to URL (base64 signatures need escaping)
+	fullURL := c.baseURL + endpoint + "?" + queryString + "&signature=" + adk.EscapeParam(signature)
+
+	// Create request
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	body, err := c.execute(req, creds.APIKey)
+	c.debugSignature(signedRequest{method, endpoint, payload, params, timestamp, creds.SecretKey}, body, err)
+	return body, err
+}
+
+// sendRequestWithBody signs and sends one request with parameters in the
+// body. The signature is computed over the sorted, non-encoded parameter
+// string regardless of body encoding, so embedded JSON (stopLoss/takeProfit)
+// and large payloads sign the same way BingX verifies them.
+func (c *Client) sendRequestWithBody(ctx context.Context, method, endpoint string, params map[string]string, encoding bodyEncoding) ([]byte, error) {
+	if encoding == encodingQuery {
+		return c.sendRequest(ctx, method, endpoint, params)
+	}
+
+	creds, err := c.credentials(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := c.now().UnixMilli()
+
+	// Add timestamp
+	if params == nil {
+		params = make(map[string]string)
+	}
+	params["timestamp"] = strconv.FormatInt(timestamp, 10)
+
+	body, contentType, payload, err := c.signedBody(creds.SecretKey, params, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	respBody, err := c.execute(req, creds.APIKey)
+	c.debugSignature(signedRequest{method, endpoint, payload, params, timestamp, creds.SecretKey}, respBody, err)
+	return respBody, err
+}
+
+// signedBody signs params and encodes them with the signature as a request
+// body, returning the signed payload too
+func (c *Client) signedBody(secretKey string, params map[string]string, encoding bodyEncoding) (body []byte, contentType, payload string, err error) {
+	// Sign the NON-encoded parameters
+	payload, encoded := encodeParams(params)
+	signature, err := c.sign(secretKey, payload)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	if encoding == encodingJSON {
+		fields := make(map[string]string, len(params)+1)
+		for key, value := range params {
+			fields[key] = value
+		}
+		fields["signature"] = signature
+
+		body, err := json.Marshal(fields)
+		if err != nil {
+			return nil, "", "", fmt.Errorf("failed to encode request body: %w", err)
+		}
+		return body, "application/json", payload, nil
+	}
+	body = make([]byte, 0, len(encoded)+len("&signature=")+3*len(signature))
+	body = append(append(body, encoded...), "&signature="...)
+	return adk.AppendEscaped(body, signature), "application/x-www-form-urlencoded", payload, nil
+}
+
+// credentials retrieves the API keys for one request. Clients without keys,
+// or with only one of them, fail immediately.
+func (c *Client) credentials(ctx context.Context) (credentials.Credentials, error) {
+	if c.public {
+		return credentials.Credentials{}, broker.NewBrokerError("bingx", "CREDENTIALS_ERROR", "Client has no API credentials (public endpoints only)", broker.ErrAuthFailed)
+	}
+	creds, err := adk.Credentials(ctx, "bingx", c.creds)
+	if err == nil && (creds.APIKey == "" || creds.SecretKey == "") {
+		return creds, broker.NewBrokerError("bingx", "CONFIG_ERROR", "API key and secret key are required", broker.ErrAuthFailed)
+	}
+	return creds, err
+}
+
+// execute authenticates and sends a prepared request, returning the body of
+// a successful (HTTP 200, non-HTML) response
+func (c *Client) execute(req *http.Request, apiKey string) ([]byte, error) {
+	if err := c.life.begin(); err != nil {
+		return nil, err
+	}
+	defer c.life.inflight.End()
+
+	// Caller headers and request ID first, so they can't replace the API key
+	adk.PrepareRequest(req)
+	// Only add API key header (public requests carry none)
+	if apiKey != "" {
+		req.Header.Set("X-BX-APIKEY", apiKey)
+	}
+
+	// Execute request; the URL path is logged, never the signed query
+	start := time.Now()
+	log := adk.RequestLogger(req.Context(), c.log.transport).With("method", req.Method, "path", req.URL.Path)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		log.Warn("request failed", logging.KeyError, err, "duration", time.Since(start))
+		return nil, adk.TransientError("bingx", "REQUEST_FAILED", "HTTP request failed", err)
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Warn("reading response failed", "status", resp.StatusCode, logging.KeyError, err)
+		return nil, adk.TransientError("bingx", "READ_FAILED", "Failed to read response", err)
+	}
+
+	c.limits.update(resp.Header, time.Now())
+	log.Debug("request", "status", resp.StatusCode, "duration", time.Since(start), "bytes", len(body))
+
+	// Check HTTP status and reject HTML pages from edge proxies
+	if err := responseError(resp, body); err != nil {
+		log.Warn("request rejected", "status", resp.StatusCode, logging.KeyError, err)
+		return nil, err
+	}
+	if err := rateLimitError(body, time.Now()); err != nil {
+		log.Warn("rate limited", logging.KeyError, err)
+		return nil, err
+	}
+	if err := maintenanceError(body); err != nil {
+		log.Warn("exchange under maintenance", logging.KeyError, err)
+		return nil, err
+	}
+
+	return body, nil
+}
@@ -0,0 +1,297 @@
+package bingx
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/credentials"
+	"github.com/agatticelli/trading-go/internal/adk"
+	"github.com/agatticelli/trading-go/signing"
+)
+
+// canonicalString rebuilds the sorted, non-encoded parameter string BingX signs
+func canonicalString(params map[string]string) string {
+	pairs := make([]string, 0, len(params))
+	for _, k := range adk.SortedKeys(params) {
+		pairs = append(pairs, k+"="+params[k])
+	}
+	return strings.Join(pairs, "&")
+}
+
+// mustSign signs params with the test client's "secret" key
+func mustSign(t *testing.T, c *Client, params string) string {
+	t.Helper()
+	signature, err := c.sign("secret", params)
+	if err != nil {
+		t.Fatalf("sign() error = %v", err)
+	}
+	return signature
+}
+
+func TestClient_MakeRequestWithBody_Form(t *testing.T) {
+	c := NewClient("key", "secret", false)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "" {
+			t.Errorf("query = %q, want empty", r.URL.RawQuery)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if r.Header.Get("X-BX-APIKEY") != "key" {
+			t.Errorf("X-BX-APIKEY = %q, want key", r.Header.Get("X-BX-APIKEY"))
+		}
+
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm() error = %v", err)
+		}
+
+		params := map[string]string{}
+		for k := range r.PostForm {
+			if k != "signature" {
+				params[k] = r.PostForm.Get(k)
+			}
+		}
+		if params["stopLoss"] != `{"type":"STOP_MARKET","stopPrice":100.5}` {
+			t.Errorf("stopLoss = %q", params["stopLoss"])
+		}
+		if want := mustSign(t, c, canonicalString(params)); r.PostForm.Get("signature") != want {
+			t.Errorf("signature = %q, want %q", r.PostForm.Get("signature"), want)
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+	c.baseURL = server.URL
+
+	_, err := c.makeRequestWithBody(context.Background(), "POST", "/test", map[string]string{
+		"symbol":   "BTC-USDT",
+		"stopLoss": `{"type":"STOP_MARKET","stopPrice":100.5}`,
+	}, encodingForm)
+	if err != nil {
+		t.Fatalf("makeRequestWithBody() error = %v", err)
+	}
+}
+
+func TestClient_MakeRequestWithBody_JSON(t *testing.T) {
+	c := NewClient("key", "secret", false)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+
+		data, _ := io.ReadAll(r.Body)
+		var payload map[string]string
+		if err := json.Unmarshal(data, &payload); err != nil {
+			t.Fatalf("body is not a JSON object: %s", data)
+		}
+
+		signature := payload["signature"]
+		delete(payload, "signature")
+		if payload["timestamp"] == "" {
+			t.Error("timestamp missing from body")
+		}
+		if want := mustSign(t, c, canonicalString(payload)); signature != want {
+			t.Errorf("signature = %q, want %q", signature, want)
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+	c.baseURL = server.URL
+
+	_, err := c.makeRequestWithBody(context.Background(), "POST", "/test", map[string]string{
+		"batchOrders": `[{"symbol":"BTC-USDT","type":"LIMIT"}]`,
+	}, encodingJSON)
+	if err != nil {
+		t.Fatalf("makeRequestWithBody() error = %v", err)
+	}
+}
+
+func TestClient_MakeRequest_QuerySignature(t *testing.T) {
+	c := NewClient("key", "secret", false)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q, _ := url.ParseQuery(r.URL.RawQuery)
+		signature := q.Get("signature")
+		q.Del("signature")
+		params := make(map[string]string, len(q))
+		for key := range q {
+			params[key] = q.Get(key)
+		}
+		if want := mustSign(t, c, canonicalString(params)); signature != want {
+			t.Errorf("signature = %q, want %q", signature, want)
+		}
+		if params["note"] != "a b+c" || params["stopLoss"] != `{"stopPrice":1}` {
+			t.Errorf("decoded params = %v", params)
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+	c.baseURL = server.URL
+
+	params := map[string]string{"symbol": "ETH-USDT", "note": "a b+c", "stopLoss": `{"stopPrice":1}`}
+	if _, err := c.makeRequest(context.Background(), "GET", "/test", params); err != nil {
+		t.Fatalf("makeRequest() error = %v", err)
+	}
+}
+
+func TestClient_Execute_HTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	_, err := c.makeRequestWithBody(context.Background(), "POST", "/test", nil, encodingForm)
+	brokerErr, ok := err.(*broker.BrokerError)
+	if !ok {
+		t.Fatalf("error = %v, want *broker.BrokerError", err)
+	}
+	if brokerErr.Code != "HTTP_ERROR" {
+		t.Errorf("Code = %q, want HTTP_ERROR", brokerErr.Code)
+	}
+}
+
+type rotatingProvider struct{ key string }
+
+func (p *rotatingProvider) Retrieve(ctx context.Context) (credentials.Credentials, error) {
+	if p.key == "" {
+		return credentials.Credentials{}, credentials.ErrNotFound
+	}
+	return credentials.Credentials{APIKey: p.key, SecretKey: p.key + "-secret"}, nil
+}
+
+func TestClient_WithCredentials_Rotation(t *testing.T) {
+	var gotKeys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotKeys = append(gotKeys, r.Header.Get("X-BX-APIKEY"))
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	p := &rotatingProvider{key: "old"}
+	cache := credentials.NewCache(p, 0)
+	c := NewClient("", "", false, WithBaseURL(server.URL), WithCredentials(cache))
+	ctx := context.Background()
+
+	c.makeRequest(ctx, "GET", "/test", nil)
+	p.key = "new"
+	c.makeRequest(ctx, "GET", "/test", nil)
+	if err := cache.Refresh(ctx); err != nil {
+		t.Fatal(err)
+	}
+	c.makeRequestWithBody(ctx, "POST", "/test", nil, encodingForm)
+
+	if strings.Join(gotKeys, ",") != "old,old,new" {
+		t.Errorf("API keys = %v, want old,old,new", gotKeys)
+	}
+
+	unset := NewClient("", "", false, WithBaseURL(server.URL), WithCredentials(&rotatingProvider{}))
+	if _, err := unset.makeRequest(ctx, "GET", "/test", nil); !errors.Is(err, broker.ErrAuthFailed) {
+		t.Errorf("makeRequest() error = %v, want ErrAuthFailed", err)
+	}
+}
+
+func TestClient_WithSigner_Ed25519(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		signature, _ := base64.StdEncoding.DecodeString(q.Get("signature"))
+		q.Del("signature")
+		if !ed25519.Verify(pub, []byte(q.Encode()), signature) {
+			t.Errorf("signature %q does not verify", r.URL.Query().Get("signature"))
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	seed := base64.StdEncoding.EncodeToString(priv.Seed())
+	c := NewClient("key", seed, false, WithBaseURL(server.URL), WithSigner(signing.Ed25519()))
+	if _, err := c.makeRequest(context.Background(), "GET", "/test", map[string]string{"symbol": "BTC-USDT"}); err != nil {
+		t.Fatalf("makeRequest() error = %v", err)
+	}
+
+	bad := NewClient("key", "not-a-key", false, WithBaseURL(server.URL), WithSigner(signing.RSA()))
+	if _, err := bad.makeRequest(context.Background(), "GET", "/test", nil); !errors.Is(err, broker.ErrAuthFailed) {
+		t.Errorf("makeRequest() with bad key error = %v, want ErrAuthFailed", err)
+	}
+}
+
+func TestClient_WithLogger(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL), WithLogger(logger))
+
+	ctx := context.Background()
+	c.makeRequest(ctx, "GET", "/ok", map[string]string{"symbol": "BTC-USDT"})
+	c.makeRequest(ctx, "GET", "/fail", nil)
+
+	out := buf.String()
+	for _, want := range []string{"broker=bingx", "component=transport", "path=/ok", "status=200", "level=WARN msg=\"request rejected\"", "status=502"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "signature") || strings.Contains(out, "key") {
+		t.Errorf("log contains credentials:\n%s", out)
+	}
+}
+
+func TestClient_RequestContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get(broker.HeaderRequestID); got != "req-1" {
+			t.Errorf("%s = %q, want req-1", broker.HeaderRequestID, got)
+		}
+		if got := r.Header.Get("X-Trace"); got != "abc" {
+			t.Errorf("X-Trace = %q, want abc", got)
+		}
+		if got := r.Header.Get("X-BX-APIKEY"); got != "key" {
+			t.Errorf("X-BX-APIKEY = %q, want the client's key", got)
+		}
+		w.Write([]byte(`{"code":101204,"msg":"Insufficient margin"}`))
+	}))
+	defer server.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL), WithLogger(logger))
+
+	ctx := broker.WithRequestID(context.Background(), "req-1")
+	ctx = broker.WithHeaders(ctx, http.Header{"X-Trace": {"abc"}, "X-Bx-Apikey": {"other"}})
+	_, err := c.GetBalance(ctx)
+
+	var brokerErr *broker.BrokerError
+	if !errors.As(err, &brokerErr) || brokerErr.RequestID != "req-1" || !strings.Contains(err.Error(), "request req-1") {
+		t.Errorf("GetBalance() error = %v, want it tagged with req-1", err)
+	}
+	if !strings.Contains(buf.String(), "request_id=req-1") {
+		t.Errorf("log missing request_id:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,164 @@
+package bingx
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// PlaceTWAPOrder starts a native TWAP order. BingX needs a LimitPrice and
+// an Interval of at least one second; children are placed ChildSize at a
+// time until Size is filled.
+func (c *Client) PlaceTWAPOrder(ctx context.Context, req *broker.TWAPRequest) (*broker.AlgoOrder, error) {
+	if c.instrument == InstrumentCoinMargined {
+		return nil, broker.ErrNotSupported
+	}
+	if req.Size <= 0 || req.ChildSize <= 0 || req.ChildSize > req.Size {
+		return nil, broker.ErrInvalidQuantity
+	}
+	if req.LimitPrice <= 0 {
+		return nil, broker.ErrInvalidPrice
+	}
+	if req.Interval < time.Second {
+		return nil, broker.NewBrokerError("bingx", "INVALID_INTERVAL", "TWAP interval must be at least 1s", nil)
+	}
+
+	side, positionSide := toBingXSides(req.Side, req.ReduceOnly)
+	prec := c.symbolPrecision(ctx, req.Symbol)
+	childSize, err := prec.formatQuantity(req.ChildSize)
+	if err != nil {
+		return nil, err
+	}
+	size, err := prec.formatQuantity(req.Size)
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"symbol":         req.Symbol,
+		"side":           string(side),
+		"positionSide":   string(positionSide),
+		"priceType":      "constant",
+		"priceVariance":  "0",
+		"triggerPrice":   prec.formatPrice(req.LimitPrice),
+		"interval":       strconv.FormatInt(int64(req.Interval/time.Second), 10),
+		"amountPerOrder": childSize,
+		"totalAmount":    size,
+	}
+
+	body, err := c.makeRequestWithBody(ctx, "POST", EndpointTWAPOrder, params, encodingForm)
+	if err != nil {
+		return nil, err
+	}
+
+	var response TWAPOrderResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse TWAP order response", err)
+	}
+	if response.Code != APISuccessCode {
+		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	}
+
+	now := time.Now()
+	return &broker.AlgoOrder{
+		ID:         response.Data.MainOrderID,
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		Type:       "TWAP",
+		Status:     broker.OrderStatusNew,
+		Size:       req.Size,
+		ReduceOnly: req.ReduceOnly,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// GetTWAPOrders returns running TWAP orders (symbol "" = all symbols)
+func (c *Client) GetTWAPOrders(ctx context.Context, symbol string) ([]*broker.AlgoOrder, error) {
+	if c.instrument == InstrumentCoinMargined {
+		return nil, broker.ErrNotSupported
+	}
+
+	params := make(map[string]string)
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
+
+	body, err := c.makeRequest(ctx, "GET", EndpointTWAPOpenOrders, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response TWAPOpenOrdersResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse TWAP orders response", err)
+	}
+	if response.Code != APISuccessCode {
+		return nil, broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	}
+
+	orders := make([]*broker.AlgoOrder, 0, len(response.Data.List))
+	for _, o := range response.Data.List {
+		order := &broker.AlgoOrder{
+			ID:         o.MainOrderID,
+			Symbol:     o.Symbol,
+			Side:       fromBingXPositionSide(o.PositionSide),
+			Type:       "TWAP",
+			Status:     fromBingXTWAPStatus(o.OrderStatus),
+			Size:       o.TotalAmount.Float64(),
+			FilledSize: o.ExecutedQty.Float64(),
+			ReduceOnly: isReduceOnly(o.Side, o.PositionSide),
+			CreatedAt:  time.UnixMilli(o.CreatedTime),
+			UpdatedAt:  time.UnixMilli(o.UpdateTime),
+		}
+		if order.FilledSize > 0 {
+			order.AveragePrice = o.ExecutedNotional.Float64() / order.FilledSize
+		}
+		orders = append(orders, order)
+	}
+	return orders, nil
+}
+
+// CancelTWAPOrder stops a running TWAP order; filled children are kept
+func (c *Client) CancelTWAPOrder(ctx context.Context, orderID string) error {
+	if c.instrument == InstrumentCoinMargined {
+		return broker.ErrNotSupported
+	}
+
+	body, err := c.makeRequestWithBody(ctx, "POST", EndpointTWAPCancel, map[string]string{"mainOrderId": orderID}, encodingForm)
+	if err != nil {
+		return err
+	}
+
+	var response struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return broker.NewBrokerError("bingx", "PARSE_ERROR", "Failed to parse TWAP cancel response", err)
+	}
+	if response.Code != APISuccessCode {
+		return broker.NewBrokerError("bingx", fmt.Sprintf("API_%d", response.Code), response.Msg, nil)
+	}
+	return nil
+}
+
+// fromBingXTWAPStatus maps TWAP states (Running, Filled, Canceled, Failed)
+// to order statuses
+func fromBingXTWAPStatus(status string) broker.OrderStatus {
+	switch strings.ToUpper(status) {
+	case "RUNNING":
+		return broker.OrderStatusNew
+	case "FILLED":
+		return broker.OrderStatusFilled
+	case "CANCELED", "CANCELLED":
+		return broker.OrderStatusCanceled
+	case "FAILED":
+		return broker.OrderStatusRejected
+	}
+	return broker.OrderStatus(status)
+}
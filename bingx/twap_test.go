@@ -0,0 +1,86 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_TWAP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if serveContracts(w, r) {
+			return
+		}
+		switch r.URL.Path {
+		case EndpointTWAPOrder:
+			if r.PostForm.Get("side") != "SELL" || r.PostForm.Get("positionSide") != "LONG" || r.PostForm.Get("interval") != "30" ||
+				r.PostForm.Get("totalAmount") != "1.0000" || r.PostForm.Get("amountPerOrder") != "0.1000" || r.PostForm.Get("triggerPrice") != "49000.0" {
+				t.Errorf("TWAP order params = %v", r.PostForm)
+			}
+			w.Write([]byte(`{"code":0,"msg":"","data":{"mainOrderId":"555"}}`))
+		case EndpointTWAPOpenOrders:
+			w.Write([]byte(`{"code":0,"msg":"","data":{"total":1,"list":[{"mainOrderId":"555","symbol":"BTC-USDT",
+				"side":"SELL","positionSide":"LONG","totalAmount":"1","executedQty":"0.4","executedNotional":"19800",
+				"orderStatus":"Running","createdTime":1700000000000,"updateTime":1700000120000}]}}`))
+		case EndpointTWAPCancel:
+			if r.PostForm.Get("mainOrderId") != "555" {
+				t.Errorf("cancel mainOrderId = %q", r.PostForm.Get("mainOrderId"))
+			}
+			w.Write([]byte(`{"code":0,"msg":""}`))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var algo broker.AlgoOrderBroker = NewClient("key", "secret", false, WithBaseURL(server.URL))
+	ctx := context.Background()
+
+	order, err := algo.PlaceTWAPOrder(ctx, &broker.TWAPRequest{
+		Symbol: "BTC-USDT", Side: broker.SideShort, ReduceOnly: true,
+		Size: 1, ChildSize: 0.1, Interval: 30 * time.Second, LimitPrice: 49000,
+	})
+	if err != nil || order.ID != "555" || order.Type != "TWAP" {
+		t.Fatalf("PlaceTWAPOrder() = %+v, %v", order, err)
+	}
+
+	orders, err := algo.GetTWAPOrders(ctx, "BTC-USDT")
+	if err != nil || len(orders) != 1 {
+		t.Fatalf("GetTWAPOrders() = %v, %v", orders, err)
+	}
+	if o := orders[0]; o.Status != broker.OrderStatusNew || !o.ReduceOnly || o.FilledSize != 0.4 || o.AveragePrice != 49500 {
+		t.Errorf("TWAP order = %+v", o)
+	}
+
+	if err := algo.CancelTWAPOrder(ctx, "555"); err != nil {
+		t.Errorf("CancelTWAPOrder() error = %v", err)
+	}
+}
+
+func TestClient_PlaceTWAPOrder_Validation(t *testing.T) {
+	c := NewClient("key", "secret", false, WithBaseURL("http://unused.invalid"))
+	ctx := context.Background()
+	valid := broker.TWAPRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, ChildSize: 0.1, Interval: time.Minute, LimitPrice: 50000}
+
+	tooBig := valid
+	tooBig.ChildSize = 2
+	if _, err := c.PlaceTWAPOrder(ctx, &tooBig); !errors.Is(err, broker.ErrInvalidQuantity) {
+		t.Errorf("child larger than total: error = %v", err)
+	}
+	noPrice := valid
+	noPrice.LimitPrice = 0
+	if _, err := c.PlaceTWAPOrder(ctx, &noPrice); !errors.Is(err, broker.ErrInvalidPrice) {
+		t.Errorf("no limit price: error = %v", err)
+	}
+
+	coin := NewClient("key", "secret", false, WithInstrumentType(InstrumentCoinMargined))
+	if _, err := coin.PlaceTWAPOrder(ctx, &valid); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("coin-margined: error = %v", err)
+	}
+}
@@ -1,24 +1,20 @@
 package bingx
 
-import (
-	"encoding/json"
-	"fmt"
-	"strconv"
-)
+import "encoding/json"
 
 // BingX API response structures
 
 type BalanceData struct {
-	UserId           string `json:"userId"`
-	Asset            string `json:"asset"`
-	Balance          string `json:"balance"`
-	Equity           string `json:"equity"`
-	UnrealizedProfit string `json:"unrealizedProfit"`
-	RealisedProfit   string `json:"realisedProfit"`
-	AvailableMargin  string `json:"availableMargin"`
-	UsedMargin       string `json:"usedMargin"`
-	FreezedMargin    string `json:"freezedMargin"`
-	ShortUid         string `json:"shortUid"`
+	UserId           string    `json:"userId"`
+	Asset            string    `json:"asset"`
+	Balance          FlexFloat `json:"balance"`
+	Equity           FlexFloat `json:"equity"`
+	UnrealizedProfit FlexFloat `json:"unrealizedProfit"`
+	RealisedProfit   FlexFloat `json:"realisedProfit"`
+	AvailableMargin  FlexFloat `json:"availableMargin"`
+	UsedMargin       FlexFloat `json:"usedMargin"`
+	FreezedMargin    FlexFloat `json:"freezedMargin"`
+	ShortUid         string    `json:"shortUid"`
 }
 
 type BalanceResponse struct {
@@ -28,60 +24,40 @@ type BalanceResponse struct {
 }
 
 type PositionData struct {
-	Symbol            string          `json:"symbol"`
-	PositionSide      string          `json:"positionSide"`
-	PositionAmt       string          `json:"positionAmt"`
-	AvailableAmt      string          `json:"availableAmt"`
-	UnrealizedProfit  string          `json:"unrealizedProfit"`
-	RealisedProfit    string          `json:"realisedProfit"`
-	InitialMargin     string          `json:"initialMargin"`
-	MaintenanceMargin string          `json:"maintenanceMargin"`
-	PositionValue     string          `json:"positionValue"`
-	Leverage          json.RawMessage `json:"leverage"`          // Can be string or number
-	IsolatedMargin    string          `json:"isolatedMargin"`
-	AvgPrice          string          `json:"avgPrice"`
-	MaxNotionalValue  string          `json:"maxNotionalValue"`
-	BidNotional       string          `json:"bidNotional"`
-	AskNotional       string          `json:"askNotional"`
-	LiquidationPrice  json.RawMessage `json:"liquidationPrice"`  // Can be string or number
-	MarkPrice         string          `json:"markPrice"`
-}
-
-// GetLeverageFloat returns the leverage as float64, handling both string and number formats
-func (p *PositionData) GetLeverageFloat() (float64, error) {
-	// First try to unmarshal as a string
-	var leverageStr string
-	if err := json.Unmarshal(p.Leverage, &leverageStr); err == nil {
-		return strconv.ParseFloat(leverageStr, 64)
-	}
-
-	// If that fails, try to unmarshal as a number
-	var leverageFloat float64
-	if err := json.Unmarshal(p.Leverage, &leverageFloat); err == nil {
-		return leverageFloat, nil
-	}
+	Symbol            string    `json:"symbol"`
+	PositionSide      string    `json:"positionSide"`
+	PositionAmt       FlexFloat `json:"positionAmt"`
+	AvailableAmt      FlexFloat `json:"availableAmt"`
+	UnrealizedProfit  FlexFloat `json:"unrealizedProfit"`
+	RealisedProfit    FlexFloat `json:"realisedProfit"`
+	InitialMargin     FlexFloat `json:"initialMargin"`
+	MaintenanceMargin FlexFloat `json:"maintenanceMargin"`
+	PositionValue     FlexFloat `json:"positionValue"`
+	Leverage          FlexFloat `json:"leverage"`
+	IsolatedMargin    FlexFloat `json:"isolatedMargin"`
+	AvgPrice          FlexFloat `json:"avgPrice"`
+	MaxNotionalValue  FlexFloat `json:"maxNotionalValue"`
+	BidNotional       FlexFloat `json:"bidNotional"`
+	AskNotional       FlexFloat `json:"askNotional"`
+	LiquidationPrice  FlexFloat `json:"liquidationPrice"`
+	MarkPrice         FlexFloat `json:"markPrice"`
+}
 
-	return 0, fmt.Errorf("unable to parse leverage: %s", string(p.Leverage))
+// GetLeverageFloat returns the leverage as float64. The error is always
+// nil: unparseable values now fail when the response is decoded, or decode
+// to 0 for non-numeric strings (see FlexFloat).
+//
+// Deprecated: Leverage is a FlexFloat; use p.Leverage.Float64().
+func (p *PositionData) GetLeverageFloat() (float64, error) {
+	return p.Leverage.Float64(), nil
 }
 
-// GetLiquidationPriceFloat returns the liquidation price as float64, handling both string and number formats
+// GetLiquidationPriceFloat returns the liquidation price as float64. The
+// error is always nil, as for GetLeverageFloat.
+//
+// Deprecated: LiquidationPrice is a FlexFloat; use p.LiquidationPrice.Float64().
 func (p *PositionData) GetLiquidationPriceFloat() (float64, error) {
-	// First try to unmarshal as a string
-	var priceStr string
-	if err := json.Unmarshal(p.LiquidationPrice, &priceStr); err == nil {
-		if priceStr == "" {
-			return 0, nil
-		}
-		return strconv.ParseFloat(priceStr, 64)
-	}
-
-	// If that fails, try to unmarshal as a number
-	var priceFloat float64
-	if err := json.Unmarshal(p.LiquidationPrice, &priceFloat); err == nil {
-		return priceFloat, nil
-	}
-
-	return 0, fmt.Errorf("unable to parse liquidation price: %s", string(p.LiquidationPrice))
+	return p.LiquidationPrice.Float64(), nil
 }
 
 type PositionsResponse struct {
@@ -101,68 +77,342 @@ type BingXOrderRequest struct {
 	TimeInForce  string `json:"timeInForce,omitempty"` // GTC, IOC, FOK
 }
 
+type OrderData struct {
+	OrderId       int64     `json:"orderId"`
+	Symbol        string    `json:"symbol"`
+	Side          string    `json:"side"`
+	PositionSide  string    `json:"positionSide"`
+	Type          string    `json:"type"`
+	Quantity      FlexFloat `json:"origQty"`
+	Price         FlexFloat `json:"price"`
+	TimeInForce   string    `json:"timeInForce"`
+	Status        string    `json:"status"`
+	ClientOrderID string    `json:"clientOrderId"`
+}
+
 type OrderResponse struct {
+	Code int       `json:"code"`
+	Data OrderData `json:"data"`
+	Msg  string    `json:"msg"`
+}
+
+type CancelReplaceResponse struct {
+	Code int               `json:"code"`
+	Msg  string            `json:"msg"`
+	Data CancelReplaceData `json:"data"`
+}
+
+type BatchCancelReplaceResponse struct {
+	Code int                 `json:"code"`
+	Msg  string              `json:"msg"`
+	Data []CancelReplaceData `json:"data"`
+}
+
+type CancelReplaceData struct {
+	CancelResult     FlexBool  `json:"cancelResult"`
+	CancelMsg        string    `json:"cancelMsg"`
+	ReplaceResult    FlexBool  `json:"replaceResult"`
+	ReplaceMsg       string    `json:"replaceMsg"`
+	NewOrderResponse OrderData `json:"newOrderResponse"`
+}
+
+type OpenOrderData struct {
+	OrderId       int64     `json:"orderId"`
+	Symbol        string    `json:"symbol"`
+	Side          string    `json:"side"`
+	PositionSide  string    `json:"positionSide"`
+	Type          string    `json:"type"`
+	Quantity      FlexFloat `json:"origQty"`
+	Price         FlexFloat `json:"price"`
+	StopPrice     FlexFloat `json:"stopPrice"`
+	ExecutedQty   FlexFloat `json:"executedQty"`
+	AvgPrice      FlexFloat `json:"avgPrice"`
+	Status        string    `json:"status"`
+	TimeInForce   string    `json:"timeInForce"`
+	ClientOrderID string    `json:"clientOrderId"`
+	WorkingType   string    `json:"workingType"`
+	Time          int64     `json:"time"`
+	UpdateTime    int64     `json:"updateTime"`
+}
+
+type OpenOrdersResponse struct {
+	Code int            `json:"code"`
+	Data OpenOrdersData `json:"data"`
+	Msg  string         `json:"msg"`
+}
+
+type OpenOrdersData struct {
+	Orders []OpenOrderData `json:"orders"`
+}
+
+// OrderDetailData is the data of a single order lookup
+type OrderDetailData struct {
+	Order OpenOrderData `json:"order"`
+}
+
+type PriceResponse struct {
+	Code int       `json:"code"`
+	Data PriceData `json:"data"`
+	Msg  string    `json:"msg"`
+}
+
+type PriceData struct {
+	Symbol string `json:"symbol"`
+	Price  string `json:"price"`
+}
+
+// PremiumIndexResponse carries mark price and funding for a symbol
+type PremiumIndexResponse struct {
+	Code int              `json:"code"`
+	Data PremiumIndexData `json:"data"`
+	Msg  string           `json:"msg"`
+}
+
+type PremiumIndexData struct {
+	Symbol          string    `json:"symbol"`
+	MarkPrice       FlexFloat `json:"markPrice"`
+	IndexPrice      FlexFloat `json:"indexPrice"`
+	LastFundingRate FlexFloat `json:"lastFundingRate"`
+	NextFundingTime int64     `json:"nextFundingTime"`
+}
+
+// TickersResponse carries the 24h statistics of every symbol
+type TickersResponse struct {
+	Code int `json:"code"`
+	Data []struct {
+		Symbol      string    `json:"symbol"`
+		LastPrice   FlexFloat `json:"lastPrice"`
+		QuoteVolume FlexFloat `json:"quoteVolume"`
+	} `json:"data"`
+	Msg string `json:"msg"`
+}
+
+// CoinTickerResponse is the coin-margined ticker payload (data is an array)
+type CoinTickerResponse struct {
+	Code int              `json:"code"`
+	Data []CoinTickerData `json:"data"`
+	Msg  string           `json:"msg"`
+}
+
+type CoinTickerData struct {
+	Symbol    string `json:"symbol"`
+	LastPrice string `json:"lastPrice"`
+}
+
+type LeverageResponse struct {
 	Code int `json:"code"`
 	Data struct {
-		OrderId      int64  `json:"orderId"`
-		Symbol       string `json:"symbol"`
-		Side         string `json:"side"`
-		PositionSide string `json:"positionSide"`
-		Type         string `json:"type"`
-		Quantity     string `json:"origQty"`
-		Price        string `json:"price"`
-		Status       string `json:"status"`
+		Symbol              string    `json:"symbol"`
+		Leverage            FlexFloat `json:"leverage"`
+		AvailableLongVol    string    `json:"availableLongVol"`
+		AvailableShortVol   string    `json:"availableShortVol"`
+		AvailableLongVal    string    `json:"availableLongVal"`
+		AvailableShortVal   string    `json:"availableShortVal"`
+		MaxPositionLongVal  string    `json:"maxPositionLongVal"`
+		MaxPositionShortVal string    `json:"maxPositionShortVal"`
 	} `json:"data"`
 	Msg string `json:"msg"`
 }
 
-type OpenOrderData struct {
-	OrderId           int64  `json:"orderId"`
-	Symbol            string `json:"symbol"`
-	Side              string `json:"side"`
-	PositionSide      string `json:"positionSide"`
-	Type              string `json:"type"`
-	Quantity          string `json:"origQty"`
-	Price             string `json:"price"`
-	StopPrice         string `json:"stopPrice"`
-	ExecutedQty       string `json:"executedQty"`
-	AvgPrice          string `json:"avgPrice"`
-	Status            string `json:"status"`
-	TimeInForce       string `json:"timeInForce"`
-	ClientOrderID     string `json:"clientOrderId"`
-	WorkingType       string `json:"workingType"`
-	Time              int64  `json:"time"`
-	UpdateTime        int64  `json:"updateTime"`
+// MarginResponse is returned by the position margin endpoint
+type MarginResponse struct {
+	Code   int     `json:"code"`
+	Msg    string  `json:"msg"`
+	Amount float64 `json:"amount"`
+	Type   int     `json:"type"`
 }
 
-type OpenOrdersResponse struct {
+// TransferResponse is returned by the wallet transfer endpoint. Successful
+// responses carry only tranId; failures use the usual code/msg envelope.
+type TransferResponse struct {
+	TranID int64 `json:"tranId"`
+}
+
+type TransferRecord struct {
+	Asset     string `json:"asset"`
+	Amount    string `json:"amount"`
+	Type      string `json:"type"`
+	Status    string `json:"status"`
+	TranID    int64  `json:"tranId"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+// TransferHistoryResponse is returned unwrapped, like TransferResponse
+type TransferHistoryResponse struct {
+	Total int              `json:"total"`
+	Rows  []TransferRecord `json:"rows"`
+}
+
+type IncomeRecord struct {
+	Symbol     string    `json:"symbol"`
+	IncomeType string    `json:"incomeType"`
+	Income     FlexFloat `json:"income"`
+	Asset      string    `json:"asset"`
+	Info       string    `json:"info"`
+	Time       int64     `json:"time"`
+	TranID     string    `json:"tranId"`
+	TradeID    string    `json:"tradeId"`
+}
+
+type IncomeResponse struct {
+	Code int            `json:"code"`
+	Msg  string         `json:"msg"`
+	Data []IncomeRecord `json:"data"`
+}
+
+type TWAPOrderResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		MainOrderID string `json:"mainOrderId"`
+	} `json:"data"`
+}
+
+type TWAPOrderData struct {
+	MainOrderID      string    `json:"mainOrderId"`
+	Symbol           string    `json:"symbol"`
+	Side             string    `json:"side"`
+	PositionSide     string    `json:"positionSide"`
+	PriceType        string    `json:"priceType"`
+	PriceVariance    FlexFloat `json:"priceVariance"`
+	TriggerPrice     FlexFloat `json:"triggerPrice"`
+	Interval         int64     `json:"interval"`
+	AmountPerOrder   FlexFloat `json:"amountPerOrder"`
+	TotalAmount      FlexFloat `json:"totalAmount"`
+	OrderStatus      string    `json:"orderStatus"`
+	ExecutedQty      FlexFloat `json:"executedQty"`
+	ExecutedNotional FlexFloat `json:"executedNotional"`
+	CreatedTime      int64     `json:"createdTime"`
+	UpdateTime       int64     `json:"updateTime"`
+}
+
+type TWAPOpenOrdersResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		List  []TWAPOrderData `json:"list"`
+		Total int             `json:"total"`
+	} `json:"data"`
+}
+
+type DepositAddressData struct {
+	Coin              string `json:"coin"`
+	Network           string `json:"network"`
+	Address           string `json:"address"`
+	AddressWithPrefix string `json:"addressWithPrefix"`
+	Tag               string `json:"tag"`
+	Status            int    `json:"status"`
+}
+
+// DepositAddressList is the data of the deposit address endpoint
+type DepositAddressList struct {
+	Data  []DepositAddressData `json:"data"`
+	Total int                  `json:"total"`
+}
+
+type DepositRecord struct {
+	Amount        string `json:"amount"`
+	Coin          string `json:"coin"`
+	Network       string `json:"network"`
+	Status        int    `json:"status"`
+	Address       string `json:"address"`
+	AddressTag    string `json:"addressTag"`
+	TxID          string `json:"txId"`
+	InsertTime    int64  `json:"insertTime"`
+	TransferType  int    `json:"transferType"`
+	UnlockConfirm string `json:"unlockConfirm"`
+	ConfirmTimes  string `json:"confirmTimes"`
+}
+
+type WithdrawRecord struct {
+	ID              string `json:"id"`
+	Address         string `json:"address"`
+	Amount          string `json:"amount"`
+	ApplyTime       string `json:"applyTime"`
+	Coin            string `json:"coin"`
+	WithdrawOrderID string `json:"withdrawOrderId"`
+	Network         string `json:"network"`
+	TransferType    int    `json:"transferType"`
+	Status          int    `json:"status"`
+	TransactionFee  string `json:"transactionFee"`
+	ConfirmNo       int    `json:"confirmNo"`
+	Info            string `json:"info"`
+	TxID            string `json:"txId"`
+}
+
+// ServerTimeResponse carries the exchange clock in milliseconds
+type ServerTimeResponse struct {
 	Code int `json:"code"`
 	Data struct {
-		Orders []OpenOrderData `json:"orders"`
+		ServerTime int64 `json:"serverTime"`
 	} `json:"data"`
 	Msg string `json:"msg"`
 }
 
-type PriceResponse struct {
+// ContractData is one perpetual contract specification
+type ContractData struct {
+	ContractID        string    `json:"contractId"`
+	Symbol            string    `json:"symbol"`
+	Asset             string    `json:"asset"`
+	Currency          string    `json:"currency"`
+	PricePrecision    int       `json:"pricePrecision"`
+	QuantityPrecision int       `json:"quantityPrecision"`
+	FeeRate           FlexFloat `json:"feeRate"`
+	TradeMinQuantity  FlexFloat `json:"tradeMinQuantity"`
+	TradeMinUSDT      FlexFloat `json:"tradeMinUSDT"`
+	MaxLongLeverage   int       `json:"maxLongLeverage"`
+	MaxShortLeverage  int       `json:"maxShortLeverage"`
+	Status            int       `json:"status"`
+	APIStateOpen      string    `json:"apiStateOpen"`
+	APIStateClose     string    `json:"apiStateClose"`
+	LaunchTime        int64     `json:"launchTime"`
+	OffTime           int64     `json:"offTime"` // Scheduled delisting, 0 if none
+}
+
+// ContractsResponse lists every perpetual contract
+type ContractsResponse struct {
+	Code int            `json:"code"`
+	Data []ContractData `json:"data"`
+	Msg  string         `json:"msg"`
+}
+
+// LeverageInfoResponse carries the current and maximum leverage of a symbol
+type LeverageInfoResponse struct {
 	Code int `json:"code"`
 	Data struct {
-		Symbol string `json:"symbol"`
-		Price  string `json:"price"`
+		LongLeverage     int `json:"longLeverage"`
+		ShortLeverage    int `json:"shortLeverage"`
+		MaxLongLeverage  int `json:"maxLongLeverage"`
+		MaxShortLeverage int `json:"maxShortLeverage"`
 	} `json:"data"`
 	Msg string `json:"msg"`
 }
 
-type LeverageResponse struct {
+// CommissionRateResponse carries the account's taker and maker fee rates
+type CommissionRateResponse struct {
 	Code int `json:"code"`
 	Data struct {
-		Symbol             string          `json:"symbol"`
-		Leverage           json.RawMessage `json:"leverage"` // Can be string or number
-		AvailableLongVol   string          `json:"availableLongVol"`
-		AvailableShortVol  string          `json:"availableShortVol"`
-		AvailableLongVal   string          `json:"availableLongVal"`
-		AvailableShortVal  string          `json:"availableShortVal"`
-		MaxPositionLongVal string          `json:"maxPositionLongVal"`
-		MaxPositionShortVal string         `json:"maxPositionShortVal"`
+		Commission struct {
+			TakerCommissionRate FlexFloat `json:"takerCommissionRate"`
+			MakerCommissionRate FlexFloat `json:"makerCommissionRate"`
+		} `json:"commission"`
 	} `json:"data"`
 	Msg string `json:"msg"`
 }
+
+// AnnouncementsResponse lists support-center notices, newest first
+type AnnouncementsResponse struct {
+	Code int    `json:"code"`
+	Msg  string `json:"msg"`
+	Data struct {
+		Result []AnnouncementData `json:"result"`
+	} `json:"data"`
+}
+
+// AnnouncementData is one support-center notice
+type AnnouncementData struct {
+	ArticleID   json.Number `json:"articleId"`
+	Title       string      `json:"title"`
+	Link        string      `json:"link"`
+	PublishTime int64       `json:"publishTime"`
+}
@@ -6,82 +6,42 @@ import (
 )
 
 func TestPositionData_GetLeverageFloat(t *testing.T) {
+	// Invalid values fail decoding rather than the getter; non-numeric
+	// strings decode to 0
 	tests := []struct {
-		name         string
-		leverageJSON string
-		want         float64
-		wantErr      bool
+		name          string
+		leverageJSON  string
+		want          float64
+		wantDecodeErr bool
 	}{
-		{
-			name:         "Leverage as string",
-			leverageJSON: `"10"`,
-			want:         10.0,
-			wantErr:      false,
-		},
-		{
-			name:         "Leverage as number",
-			leverageJSON: `25`,
-			want:         25.0,
-			wantErr:      false,
-		},
-		{
-			name:         "Leverage as float string",
-			leverageJSON: `"50.5"`,
-			want:         50.5,
-			wantErr:      false,
-		},
-		{
-			name:         "Leverage as float number",
-			leverageJSON: `125.0`,
-			want:         125.0,
-			wantErr:      false,
-		},
-		{
-			name:         "High leverage",
-			leverageJSON: `"125"`,
-			want:         125.0,
-			wantErr:      false,
-		},
-		{
-			name:         "Invalid leverage - non-numeric string",
-			leverageJSON: `"invalid"`,
-			want:         0,
-			wantErr:      true,
-		},
-		{
-			name:         "Invalid leverage - null",
-			leverageJSON: `null`,
-			want:         0,
-			wantErr:      true,
-		},
-		{
-			name:         "Invalid leverage - boolean",
-			leverageJSON: `true`,
-			want:         0,
-			wantErr:      true,
-		},
+		{name: "Leverage as string", leverageJSON: `"10"`, want: 10.0},
+		{name: "Leverage as number", leverageJSON: `25`, want: 25.0},
+		{name: "Leverage as float string", leverageJSON: `"50.5"`, want: 50.5},
+		{name: "Leverage as float number", leverageJSON: `125.0`, want: 125.0},
+		{name: "High leverage", leverageJSON: `"125"`, want: 125.0},
+		{name: "Non-numeric string", leverageJSON: `"invalid"`, want: 0},
+		{name: "Null", leverageJSON: `null`, want: 0},
+		{name: "Invalid leverage - boolean", leverageJSON: `true`, wantDecodeErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pos := &PositionData{
-				Leverage: json.RawMessage(tt.leverageJSON),
-			}
-
-			got, err := pos.GetLeverageFloat()
-
-			if tt.wantErr {
+			var pos PositionData
+			err := json.Unmarshal([]byte(`{"leverage": `+tt.leverageJSON+`}`), &pos)
+			if tt.wantDecodeErr {
 				if err == nil {
-					t.Error("GetLeverageFloat() error = nil, want error")
+					t.Error("Unmarshal() error = nil, want error")
 				}
 				return
 			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
 
+			got, err := pos.GetLeverageFloat()
 			if err != nil {
 				t.Errorf("GetLeverageFloat() error = %v, want nil", err)
-				return
 			}
-
 			if got != tt.want {
 				t.Errorf("GetLeverageFloat() = %.2f, want %.2f", got, tt.want)
 			}
@@ -91,93 +51,41 @@ func TestPositionData_GetLeverageFloat(t *testing.T) {
 
 func TestPositionData_GetLiquidationPriceFloat(t *testing.T) {
 	tests := []struct {
-		name         string
-		priceJSON    string
-		want         float64
-		wantErr      bool
+		name          string
+		priceJSON     string
+		want          float64
+		wantDecodeErr bool
 	}{
-		{
-			name:      "Price as string",
-			priceJSON: `"45000.50"`,
-			want:      45000.50,
-			wantErr:   false,
-		},
-		{
-			name:      "Price as number",
-			priceJSON: `42000.0`,
-			want:      42000.0,
-			wantErr:   false,
-		},
-		{
-			name:      "Price as integer string",
-			priceJSON: `"50000"`,
-			want:      50000.0,
-			wantErr:   false,
-		},
-		{
-			name:      "Price as integer",
-			priceJSON: `48000`,
-			want:      48000.0,
-			wantErr:   false,
-		},
-		{
-			name:      "Empty string (no liquidation)",
-			priceJSON: `""`,
-			want:      0,
-			wantErr:   false,
-		},
-		{
-			name:      "Very high price",
-			priceJSON: `"999999.99"`,
-			want:      999999.99,
-			wantErr:   false,
-		},
-		{
-			name:      "Very low price",
-			priceJSON: `"0.01"`,
-			want:      0.01,
-			wantErr:   false,
-		},
-		{
-			name:      "Invalid price - non-numeric string",
-			priceJSON: `"invalid"`,
-			want:      0,
-			wantErr:   true,
-		},
-		{
-			name:      "Null price (no liquidation set)",
-			priceJSON: `null`,
-			want:      0,
-			wantErr:   false,
-		},
-		{
-			name:      "Invalid price - boolean",
-			priceJSON: `false`,
-			want:      0,
-			wantErr:   true,
-		},
+		{name: "Price as string", priceJSON: `"45000.50"`, want: 45000.50},
+		{name: "Price as number", priceJSON: `42000.0`, want: 42000.0},
+		{name: "Price as integer string", priceJSON: `"50000"`, want: 50000.0},
+		{name: "Price as integer", priceJSON: `48000`, want: 48000.0},
+		{name: "Empty string (no liquidation)", priceJSON: `""`, want: 0},
+		{name: "Very high price", priceJSON: `"999999.99"`, want: 999999.99},
+		{name: "Very low price", priceJSON: `"0.01"`, want: 0.01},
+		{name: "Non-numeric string", priceJSON: `"invalid"`, want: 0},
+		{name: "Null price (no liquidation set)", priceJSON: `null`, want: 0},
+		{name: "Invalid price - boolean", priceJSON: `false`, wantDecodeErr: true},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			pos := &PositionData{
-				LiquidationPrice: json.RawMessage(tt.priceJSON),
-			}
-
-			got, err := pos.GetLiquidationPriceFloat()
-
-			if tt.wantErr {
+			var pos PositionData
+			err := json.Unmarshal([]byte(`{"liquidationPrice": `+tt.priceJSON+`}`), &pos)
+			if tt.wantDecodeErr {
 				if err == nil {
-					t.Error("GetLiquidationPriceFloat() error = nil, want error")
+					t.Error("Unmarshal() error = nil, want error")
 				}
 				return
 			}
+			if err != nil {
+				t.Fatalf("Unmarshal() error = %v", err)
+			}
 
+			got, err := pos.GetLiquidationPriceFloat()
 			if err != nil {
 				t.Errorf("GetLiquidationPriceFloat() error = %v, want nil", err)
-				return
 			}
-
 			if got != tt.want {
 				t.Errorf("GetLiquidationPriceFloat() = %.2f, want %.2f", got, tt.want)
 			}
@@ -344,8 +252,11 @@ func TestBalanceResponse_Unmarshal(t *testing.T) {
 	if data.Asset != "USDT" {
 		t.Errorf("Asset = %q, want %q", data.Asset, "USDT")
 	}
-	if data.Balance != "1000.00" {
-		t.Errorf("Balance = %q, want %q", data.Balance, "1000.00")
+	if data.Balance != 1000.00 {
+		t.Errorf("Balance = %.2f, want %.2f", data.Balance, 1000.00)
+	}
+	if data.AvailableMargin != 950.00 {
+		t.Errorf("AvailableMargin = %.2f, want %.2f", data.AvailableMargin, 950.00)
 	}
 }
 
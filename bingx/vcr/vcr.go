@@ -0,0 +1,340 @@
+// Package vcr records the HTTP exchanges of a bingx client into fixture
+// files and replays them in tests, so parsing and mapping can be tested
+// offline against real payload shapes.
+//
+// Record a session once against the live (or demo) API:
+//
+//	rec, _ := vcr.New("testdata/vcr/account.json", vcr.Config{Mode: vcr.Record})
+//	client := bingx.NewClient(key, secret, true, bingx.WithHTTPClient(rec.Client()))
+//	// ... make calls ...
+//	rec.Save()
+//
+// and replay it in tests with the default Replay mode. Credentials never
+// reach the fixture: request headers are not recorded and the timestamp,
+// signature and recvWindow parameters are dropped before requests are
+// stored or matched.
+package vcr
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Mode selects whether a Recorder records or replays
+type Mode int
+
+const (
+	// Replay serves responses from the fixture file without network access
+	Replay Mode = iota
+	// Record forwards requests to the API and captures the exchanges
+	Record
+)
+
+// ModeEnv is the environment variable read by ModeFromEnv
+const ModeEnv = "BINGX_VCR"
+
+// ModeFromEnv returns Record when BINGX_VCR is "record" and Replay otherwise
+func ModeFromEnv() Mode {
+	if os.Getenv(ModeEnv) == "record" {
+		return Record
+	}
+	return Replay
+}
+
+// ErrNoInteraction is returned in Replay mode for requests the fixture does
+// not contain
+var ErrNoInteraction = errors.New("vcr: no recorded interaction for request")
+
+// volatileParams are signing parameters that change on every request
+var volatileParams = []string{"timestamp", "signature", "recvWindow"}
+
+// Config configures a Recorder
+type Config struct {
+	Mode Mode
+	// Redact lists JSON field names whose values are replaced with
+	// "REDACTED" in recorded responses (e.g. "listenKey", "address")
+	Redact []string
+	// Transport sends requests in Record mode (default: http.DefaultTransport)
+	Transport http.RoundTripper
+}
+
+// Interaction is one recorded request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request without credentials or signing parameters
+type Request struct {
+	Method string `json:"method"`
+	Path   string `json:"path"`
+	Query  string `json:"query,omitempty"`
+	Body   string `json:"body,omitempty"`
+}
+
+// Response is a recorded response. JSON bodies are stored as JSON to keep
+// fixtures readable; anything else is stored as text.
+type Response struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+	Text   string            `json:"text,omitempty"`
+}
+
+// fixture is the file format
+type fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder is an http.RoundTripper that records or replays interactions.
+// Replayed interactions are matched on method, path, query and body; each
+// recording is served once, in order, so repeated identical requests replay
+// their successive responses.
+type Recorder struct {
+	path string
+	cfg  Config
+
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// New creates a Recorder for the fixture at path. In Replay mode the
+// fixture is loaded immediately.
+func New(path string, cfg Config) (*Recorder, error) {
+	if cfg.Transport == nil {
+		cfg.Transport = http.DefaultTransport
+	}
+	r := &Recorder{path: path, cfg: cfg}
+	if cfg.Mode == Record {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("vcr: reading fixture: %w", err)
+	}
+	var f fixture
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("vcr: parsing fixture %s: %w", path, err)
+	}
+	r.interactions = f.Interactions
+	r.used = make([]bool, len(f.Interactions))
+	return r, nil
+}
+
+// Client returns an http.Client using the Recorder, for bingx.WithHTTPClient
+func (r *Recorder) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RoundTrip records or replays req
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	recorded, err := newRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	if r.cfg.Mode == Record {
+		return r.record(req, recorded)
+	}
+	return r.replay(req, recorded)
+}
+
+func (r *Recorder) record(req *http.Request, recorded Request) (*http.Response, error) {
+	resp, err := r.cfg.Transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := Response{Status: resp.StatusCode}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		response.Header = map[string]string{"Content-Type": ct}
+	}
+	if redacted, ok := redact(body, r.cfg.Redact); ok {
+		response.Body = redacted
+	} else {
+		response.Text = string(body)
+	}
+
+	r.mu.Lock()
+	r.interactions = append(r.interactions, Interaction{Request: recorded, Response: response})
+	r.mu.Unlock()
+
+	// The caller sees the unredacted response
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, in := range r.interactions {
+		if r.used[i] || in.Request != recorded {
+			continue
+		}
+		r.used[i] = true
+
+		body := []byte(in.Response.Text)
+		if len(in.Response.Body) > 0 {
+			body = in.Response.Body
+		}
+		header := make(http.Header, len(in.Response.Header))
+		for k, v := range in.Response.Header {
+			header.Set(k, v)
+		}
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.Status, http.StatusText(in.Response.Status)),
+			StatusCode:    in.Response.Status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s %s?%s", ErrNoInteraction, recorded.Method, recorded.Path, recorded.Query)
+}
+
+// Interactions returns the recorded or loaded interactions
+func (r *Recorder) Interactions() []Interaction {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Interaction(nil), r.interactions...)
+}
+
+// Save writes the recorded interactions to the fixture file, creating its
+// directory. It does nothing in Replay mode.
+func (r *Recorder) Save() error {
+	if r.cfg.Mode != Record {
+		return nil
+	}
+
+	r.mu.Lock()
+	data, err := json.MarshalIndent(fixture{Interactions: r.interactions}, "", "  ")
+	r.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("vcr: encoding fixture: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("vcr: creating fixture directory: %w", err)
+	}
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// newRequest strips credentials and signing parameters from req
+func newRequest(req *http.Request) (Request, error) {
+	recorded := Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  stripParams(req.URL.RawQuery),
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return recorded, nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return Request{}, fmt.Errorf("vcr: reading request body: %w", err)
+	}
+	req.Body = io.NopCloser(bytes.NewReader(body))
+
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "application/json") {
+		recorded.Body = stripJSONParams(body)
+	} else {
+		recorded.Body = stripParams(string(body))
+	}
+	return recorded, nil
+}
+
+// stripParams removes volatile parameters from a URL-encoded string and
+// re-encodes it with sorted keys
+func stripParams(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return raw
+	}
+	for _, p := range volatileParams {
+		values.Del(p)
+	}
+	return values.Encode()
+}
+
+// stripJSONParams removes volatile parameters from a JSON object body
+func stripJSONParams(body []byte) string {
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(body, &obj); err != nil {
+		return string(body)
+	}
+	for _, p := range volatileParams {
+		delete(obj, p)
+	}
+	data, _ := json.Marshal(obj)
+	return string(data)
+}
+
+// redact returns body as compact JSON with the named fields replaced, or
+// false if body is not JSON
+func redact(body []byte, fields []string) (json.RawMessage, bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, false
+	}
+	if len(fields) == 0 {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, body); err != nil {
+			return nil, false
+		}
+		return buf.Bytes(), true
+	}
+
+	names := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		names[f] = true
+	}
+	data, err := json.Marshal(redactValue(v, names))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+func redactValue(v any, names map[string]bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, child := range v {
+			if names[k] {
+				v[k] = "REDACTED"
+			} else {
+				v[k] = redactValue(child, names)
+			}
+		}
+	case []any:
+		for i, child := range v {
+			v[i] = redactValue(child, names)
+		}
+	}
+	return v
+}
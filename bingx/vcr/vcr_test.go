@@ -0,0 +1,140 @@
+package vcr_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/bingx"
+	"github.com/agatticelli/trading-go/bingx/vcr"
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestRecorder_RecordAndReplay(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case bingx.EndpointPrice:
+			w.Write([]byte(`{"code":0,"msg":"","data":{"symbol":"BTC-USDT","price":"97123.5","time":1700000000000}}`))
+		case bingx.EndpointBalance:
+			w.Write([]byte(`{"code":0,"msg":"","data":[{"userId":"8812345","asset":"USDT","balance":"1000.00","equity":"1050.00","unrealizedProfit":"50.00","realisedProfit":"0","availableMargin":"950.00","usedMargin":"100.00"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	ctx := context.Background()
+
+	rec, err := vcr.New(path, vcr.Config{Mode: vcr.Record, Redact: []string{"userId"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	live := bingx.NewClient("my-api-key", "my-secret", false, bingx.WithBaseURL(server.URL), bingx.WithHTTPClient(rec.Client()))
+	if _, err := live.GetCurrentPrice(ctx, "BTC-USDT"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := live.GetBalance(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"my-api-key", "signature", "timestamp", "8812345"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("fixture contains %q:\n%s", secret, data)
+		}
+	}
+
+	// Replay with different credentials and no server
+	server.Close()
+	rep, err := vcr.New(path, vcr.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	offline := bingx.NewClient("other-key", "other-secret", false, bingx.WithBaseURL(server.URL), bingx.WithHTTPClient(rep.Client()))
+
+	price, err := offline.GetCurrentPrice(ctx, "BTC-USDT")
+	if err != nil {
+		t.Fatalf("replayed GetCurrentPrice() error = %v", err)
+	}
+	if price != 97123.5 {
+		t.Errorf("price = %v, want 97123.5", price)
+	}
+
+	balance, err := offline.GetBalance(ctx)
+	if err != nil {
+		t.Fatalf("replayed GetBalance() error = %v", err)
+	}
+	if balance.Total != 1050 {
+		t.Errorf("balance total = %v, want 1050", balance.Total)
+	}
+
+	// Each interaction replays once
+	if _, err := offline.GetBalance(ctx); !errors.Is(err, vcr.ErrNoInteraction) {
+		t.Errorf("second GetBalance() error = %v, want ErrNoInteraction", err)
+	}
+	if _, err := offline.GetCurrentPrice(ctx, "ETH-USDT"); !errors.Is(err, vcr.ErrNoInteraction) {
+		t.Errorf("unrecorded GetCurrentPrice() error = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestRecorder_MatchesFormBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":0,"msg":"","data":{"orderId":1735950123456789,"symbol":"BTC-USDT","side":"BUY","positionSide":"LONG","type":"LIMIT","origQty":"0.001","price":"90000","status":"NEW"}}`))
+	}))
+	defer server.Close()
+
+	order := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 0.001, Price: 90000}
+	path := filepath.Join(t.TempDir(), "order.json")
+
+	rec, _ := vcr.New(path, vcr.Config{Mode: vcr.Record})
+	c := bingx.NewClient("key", "secret", false, bingx.WithBaseURL(server.URL), bingx.WithHTTPClient(rec.Client()))
+	if _, err := c.PlaceOrder(context.Background(), order); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	rep, err := vcr.New(path, vcr.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = bingx.NewClient("key", "secret", false, bingx.WithBaseURL(server.URL), bingx.WithHTTPClient(rep.Client()))
+	placed, err := c.PlaceOrder(context.Background(), order)
+	if err != nil {
+		t.Fatalf("replayed PlaceOrder() error = %v", err)
+	}
+	if placed.ID != "1735950123456789" {
+		t.Errorf("order ID = %s, want 1735950123456789", placed.ID)
+	}
+
+	other := *order
+	other.Price = 91000
+	if _, err := c.PlaceOrder(context.Background(), &other); !errors.Is(err, vcr.ErrNoInteraction) {
+		t.Errorf("PlaceOrder() at another price error = %v, want ErrNoInteraction", err)
+	}
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(vcr.ModeEnv, "record")
+	if vcr.ModeFromEnv() != vcr.Record {
+		t.Error("ModeFromEnv() != Record with BINGX_VCR=record")
+	}
+	t.Setenv(vcr.ModeEnv, "")
+	if vcr.ModeFromEnv() != vcr.Replay {
+		t.Error("ModeFromEnv() != Replay by default")
+	}
+}
@@ -0,0 +1,53 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/agatticelli/trading-go/bingx/vcr"
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClient_ReplayAccountSession(t *testing.T) {
+	// Re-record with BINGX_VCR=record and real keys in BINGX_API_KEY and
+	// BINGX_SECRET_KEY
+	mode := vcr.ModeFromEnv()
+	rec, err := vcr.New("testdata/vcr/account.json", vcr.Config{Mode: mode, Redact: []string{"userId", "shortUid", "positionId"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := rec.Save(); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	apiKey, secretKey := "key", "secret"
+	if mode == vcr.Record {
+		apiKey, secretKey = os.Getenv("BINGX_API_KEY"), os.Getenv("BINGX_SECRET_KEY")
+	}
+	c := NewClient(apiKey, secretKey, false, WithHTTPClient(rec.Client()))
+	ctx := context.Background()
+
+	balance, err := c.GetBalance(ctx)
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	if balance.Asset != "USDT" || balance.Total != 1561.0254 || balance.Available != 1402.6131 {
+		t.Errorf("balance = %+v", balance)
+	}
+
+	pos, err := c.GetPosition(ctx, "BTC-USDT")
+	if err != nil {
+		t.Fatalf("GetPosition() error = %v", err)
+	}
+	if pos.Side != broker.SideLong || pos.Size != 0.015 || pos.Leverage != 10 || pos.LiquidationPrice != 96211.42 {
+		t.Errorf("position = %+v", pos)
+	}
+
+	if _, err := c.GetPosition(ctx, "ETH-USDT"); !errors.Is(err, broker.ErrPositionNotFound) {
+		t.Errorf("GetPosition(ETH-USDT) error = %v, want ErrPositionNotFound", err)
+	}
+}
@@ -0,0 +1,212 @@
+package bingx
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// WalletService exposes read-only wallet endpoints (deposit addresses,
+// deposit and withdrawal history) for reconciling treasury movements
+type WalletService struct {
+	client *Client
+}
+
+// Wallet returns the wallet service for this client
+func (c *Client) Wallet() *WalletService {
+	return &WalletService{client: c}
+}
+
+// DepositAddress is an address accepting deposits of a coin on a network
+type DepositAddress struct {
+	Coin    string
+	Network string
+	Address string
+	Tag     string // Memo/tag for networks that need one
+}
+
+// Deposit is a single on-chain deposit
+type Deposit struct {
+	Coin      string
+	Network   string
+	Amount    float64
+	Address   string
+	TxID      string
+	Status    string
+	Timestamp time.Time
+}
+
+// Withdrawal is a single withdrawal request
+type Withdrawal struct {
+	ID        string
+	Coin      string
+	Network   string
+	Amount    float64
+	Fee       float64
+	Address   string
+	TxID      string
+	Status    string
+	Timestamp time.Time
+}
+
+// WalletHistoryFilter narrows deposit/withdrawal history queries
+type WalletHistoryFilter struct {
+	Coin      string // Empty = all coins
+	StartTime time.Time
+	EndTime   time.Time
+	PageSize  int // Records per page (0 = 1000)
+}
+
+// depositStatus maps BingX deposit status codes to readable values
+var depositStatus = map[int]string{
+	0: "PENDING",
+	1: "COMPLETED",
+	6: "CREDITED",
+}
+
+// withdrawStatus maps BingX withdrawal status codes to readable values
+var withdrawStatus = map[int]string{
+	4: "PROCESSING",
+	5: "FAILED",
+	6: "COMPLETED",
+}
+
+// statusName looks up a status code, falling back to the raw code
+func statusName(names map[int]string, code int) string {
+	if name, ok := names[code]; ok {
+		return name
+	}
+	return strconv.Itoa(code)
+}
+
+// GetDepositAddresses returns the deposit addresses for a coin on every network
+func (w *WalletService) GetDepositAddresses(ctx context.Context, coin string) ([]*DepositAddress, error) {
+	params := map[string]string{
+		"coin":   coin,
+		"offset": "0",
+		"limit":  "1000",
+	}
+
+	list, err := call[DepositAddressList](ctx, w.client, "GET", EndpointDepositAddress, params, "deposit address")
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*DepositAddress, 0, len(list.Data))
+	for _, a := range list.Data {
+		addresses = append(addresses, &DepositAddress{
+			Coin:    a.Coin,
+			Network: a.Network,
+			Address: a.Address,
+			Tag:     a.Tag,
+		})
+	}
+
+	return addresses, nil
+}
+
+// GetDepositHistory returns an iterator over deposits, newest first
+func (w *WalletService) GetDepositHistory(filter WalletHistoryFilter) *broker.Iterator[*Deposit] {
+	fetch := func(ctx context.Context, req broker.PageRequest) ([]*Deposit, *broker.PageRequest, error) {
+		records, err := historyPage[DepositRecord](ctx, w.client, EndpointDepositHistory, filter.Coin, req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		deposits := make([]*Deposit, 0, len(records))
+		for _, r := range records {
+			amount, _ := strconv.ParseFloat(r.Amount, 64)
+			deposits = append(deposits, &Deposit{
+				Coin:      r.Coin,
+				Network:   r.Network,
+				Amount:    amount,
+				Address:   r.Address,
+				TxID:      r.TxID,
+				Status:    statusName(depositStatus, r.Status),
+				Timestamp: time.UnixMilli(r.InsertTime),
+			})
+		}
+
+		return deposits, nextOffsetPage(req, len(records)), nil
+	}
+
+	return broker.NewIterator(fetch, historyFirstPage(filter))
+}
+
+// GetWithdrawalHistory returns an iterator over withdrawals, newest first
+func (w *WalletService) GetWithdrawalHistory(filter WalletHistoryFilter) *broker.Iterator[*Withdrawal] {
+	fetch := func(ctx context.Context, req broker.PageRequest) ([]*Withdrawal, *broker.PageRequest, error) {
+		records, err := historyPage[WithdrawRecord](ctx, w.client, EndpointWithdrawHistory, filter.Coin, req)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		withdrawals := make([]*Withdrawal, 0, len(records))
+		for _, r := range records {
+			amount, _ := strconv.ParseFloat(r.Amount, 64)
+			fee, _ := strconv.ParseFloat(r.TransactionFee, 64)
+			applied, _ := time.Parse(time.DateTime, r.ApplyTime)
+			withdrawals = append(withdrawals, &Withdrawal{
+				ID:        r.ID,
+				Coin:      r.Coin,
+				Network:   r.Network,
+				Amount:    amount,
+				Fee:       fee,
+				Address:   r.Address,
+				TxID:      r.TxID,
+				Status:    statusName(withdrawStatus, r.Status),
+				Timestamp: applied,
+			})
+		}
+
+		return withdrawals, nextOffsetPage(req, len(records)), nil
+	}
+
+	return broker.NewIterator(fetch, historyFirstPage(filter))
+}
+
+// historyPage requests one offset/limit page of a wallet history endpoint.
+// These endpoints return a bare JSON array on success and the code/msg
+// envelope on failure.
+func historyPage[T any](ctx context.Context, c *Client, endpoint, coin string, req broker.PageRequest) ([]T, error) {
+	params := map[string]string{
+		"offset": strconv.Itoa((req.Page - 1) * req.Limit),
+		"limit":  strconv.Itoa(req.Limit),
+	}
+	if coin != "" {
+		params["coin"] = coin
+	}
+	if !req.StartTime.IsZero() {
+		params["startTime"] = strconv.FormatInt(req.StartTime.UnixMilli(), 10)
+	}
+	if !req.EndTime.IsZero() {
+		params["endTime"] = strconv.FormatInt(req.EndTime.UnixMilli(), 10)
+	}
+
+	return callUnwrapped[[]T](ctx, c, "GET", endpoint, params, "wallet history")
+}
+
+// historyFirstPage builds the initial page request for a history filter
+func historyFirstPage(filter WalletHistoryFilter) broker.PageRequest {
+	limit := filter.PageSize
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	return broker.PageRequest{
+		Page:      1,
+		Limit:     limit,
+		StartTime: filter.StartTime,
+		EndTime:   filter.EndTime,
+	}
+}
+
+// nextOffsetPage returns the following page, or nil when the current page was short
+func nextOffsetPage(req broker.PageRequest, got int) *broker.PageRequest {
+	if got < req.Limit {
+		return nil
+	}
+	return req.NextPage()
+}
@@ -0,0 +1,118 @@
+package bingx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestWalletService_GetDepositAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("coin") != "USDT" {
+			t.Errorf("coin = %q, want USDT", r.URL.Query().Get("coin"))
+		}
+		w.Write([]byte(`{"code":0,"msg":"","data":{"total":2,"data":[
+			{"coin":"USDT","network":"TRC20","address":"TXabc","tag":""},
+			{"coin":"USDT","network":"ERC20","address":"0xdef","tag":""}]}}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	addresses, err := c.Wallet().GetDepositAddresses(context.Background(), "USDT")
+	if err != nil {
+		t.Fatalf("GetDepositAddresses() error = %v", err)
+	}
+	if len(addresses) != 2 {
+		t.Fatalf("len(addresses) = %d, want 2", len(addresses))
+	}
+	if addresses[0].Network != "TRC20" || addresses[0].Address != "TXabc" {
+		t.Errorf("addresses[0] = %+v", addresses[0])
+	}
+}
+
+func TestWalletService_GetDepositHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("offset") {
+		case "0":
+			w.Write([]byte(`[
+				{"amount":"100","coin":"USDT","network":"TRC20","status":1,"txId":"a","insertTime":1700000000000},
+				{"amount":"50","coin":"USDT","network":"TRC20","status":0,"txId":"b","insertTime":1700000001000}]`))
+		case "2":
+			w.Write([]byte(`[{"amount":"5","coin":"USDT","network":"TRC20","status":6,"txId":"c","insertTime":1700000002000}]`))
+		default:
+			t.Errorf("unexpected offset %s", r.URL.Query().Get("offset"))
+		}
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	deposits, err := broker.Collect(context.Background(), c.Wallet().GetDepositHistory(WalletHistoryFilter{PageSize: 2}))
+	if err != nil {
+		t.Fatalf("GetDepositHistory() error = %v", err)
+	}
+	if len(deposits) != 3 {
+		t.Fatalf("len(deposits) = %d, want 3", len(deposits))
+	}
+
+	wantStatus := []string{"COMPLETED", "PENDING", "CREDITED"}
+	for i, d := range deposits {
+		if d.Status != wantStatus[i] {
+			t.Errorf("deposit %d status = %q, want %q", i, d.Status, wantStatus[i])
+		}
+	}
+	if deposits[0].Amount != 100 || !deposits[0].Timestamp.Equal(time.UnixMilli(1700000000000)) {
+		t.Errorf("deposits[0] = %+v", deposits[0])
+	}
+}
+
+func TestWalletService_GetWithdrawalHistory(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id":"w1","address":"0xabc","amount":"20","applyTime":"2024-04-29 16:08:00",
+			"coin":"USDT","network":"ERC20","status":6,"transactionFee":"1.5","txId":"0xtx"}]`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	withdrawals, err := broker.Collect(context.Background(), c.Wallet().GetWithdrawalHistory(WalletHistoryFilter{Coin: "USDT"}))
+	if err != nil {
+		t.Fatalf("GetWithdrawalHistory() error = %v", err)
+	}
+	if len(withdrawals) != 1 {
+		t.Fatalf("len(withdrawals) = %d, want 1", len(withdrawals))
+	}
+
+	w := withdrawals[0]
+	if w.ID != "w1" || w.Amount != 20 || w.Fee != 1.5 || w.Status != "COMPLETED" {
+		t.Errorf("withdrawal = %+v", w)
+	}
+	if want := time.Date(2024, 4, 29, 16, 8, 0, 0, time.UTC); !w.Timestamp.Equal(want) {
+		t.Errorf("Timestamp = %v, want %v", w.Timestamp, want)
+	}
+}
+
+func TestWalletService_HistoryError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"code":100001,"msg":"signature verification failed"}`))
+	}))
+	defer server.Close()
+
+	c := NewClient("key", "secret", false, WithBaseURL(server.URL))
+
+	_, err := broker.Collect(context.Background(), c.Wallet().GetDepositHistory(WalletHistoryFilter{}))
+
+	var brokerErr *broker.BrokerError
+	if !errors.As(err, &brokerErr) {
+		t.Fatalf("error = %v, want *broker.BrokerError", err)
+	}
+	if brokerErr.Code != "API_100001" {
+		t.Errorf("Code = %q, want API_100001", brokerErr.Code)
+	}
+}
@@ -0,0 +1,154 @@
+// Package book keeps a local copy of an order book from depth updates and
+// computes short-horizon features on every update: depth imbalance,
+// microprice and the depth-weighted mid.
+package book
+
+import (
+	"context"
+	"sync"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// DefaultLevels is the default number of levels features are computed over
+const DefaultLevels = 5
+
+// Features are derived from one book update. Prices are zero when a side
+// of the book is empty.
+type Features struct {
+	BestBid float64
+	BestAsk float64
+	Mid     float64
+	Spread  float64 // BestAsk - BestBid
+	// Imbalance is (bid size - ask size) / (bid size + ask size) over the
+	// configured levels, from -1 (all asks) to 1 (all bids)
+	Imbalance float64
+	// Microprice is the top-of-book mid weighted toward the side with less
+	// size, where the next trade is more likely to move the price:
+	// (BestBid*askSize + BestAsk*bidSize) / (bidSize + askSize)
+	Microprice float64
+	// WeightedMid is Microprice extended to the configured levels, using
+	// each side's size-weighted average price and total size
+	WeightedMid float64
+}
+
+// Update is a book update with the features computed from it
+type Update struct {
+	Depth    broker.Depth
+	Features Features
+}
+
+// Handler receives book updates. Handlers run synchronously on the
+// goroutine that applied the update and should return quickly.
+type Handler func(u Update)
+
+// Config configures a Book
+type Config struct {
+	// Levels is the number of levels per side Imbalance and WeightedMid
+	// use (default 5)
+	Levels int
+}
+
+// Book is the local order book of one symbol. It is safe for concurrent
+// use.
+type Book struct {
+	config Config
+
+	mu       sync.Mutex
+	last     Update
+	handlers []Handler
+}
+
+// New creates an empty book
+func New(config Config) *Book {
+	if config.Levels <= 0 {
+		config.Levels = DefaultLevels
+	}
+	return &Book{config: config}
+}
+
+// OnUpdate registers a handler for book updates
+func (b *Book) OnUpdate(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// Apply replaces the book with depth, computes its features and notifies
+// handlers
+func (b *Book) Apply(depth broker.Depth) Features {
+	u := Update{Depth: depth, Features: Compute(depth, b.config.Levels)}
+
+	b.mu.Lock()
+	b.last = u
+	handlers := b.handlers
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		h(u)
+	}
+	return u.Features
+}
+
+// Last returns the latest update
+func (b *Book) Last() Update {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.last
+}
+
+// Run feeds the symbol's depth stream to Apply until the context is
+// canceled or the stream fails
+func (b *Book) Run(ctx context.Context, streamer broker.DepthStreamer, symbol string) error {
+	return streamer.StreamDepth(ctx, symbol, b.config.Levels, func(d broker.Depth) {
+		b.Apply(d)
+	})
+}
+
+// Compute derives the features of depth over the top levels of each side
+func Compute(depth broker.Depth, levels int) Features {
+	if len(depth.Bids) == 0 || len(depth.Asks) == 0 {
+		return Features{}
+	}
+
+	bid, ask := depth.Bids[0], depth.Asks[0]
+	f := Features{
+		BestBid:    bid.Price,
+		BestAsk:    ask.Price,
+		Mid:        (bid.Price + ask.Price) / 2,
+		Spread:     ask.Price - bid.Price,
+		Microprice: weigh(bid.Price, bid.Size, ask.Price, ask.Size),
+	}
+
+	bidPrice, bidSize := vwap(depth.Bids, levels)
+	askPrice, askSize := vwap(depth.Asks, levels)
+	if total := bidSize + askSize; total > 0 {
+		f.Imbalance = (bidSize - askSize) / total
+	}
+	f.WeightedMid = weigh(bidPrice, bidSize, askPrice, askSize)
+	return f
+}
+
+// weigh returns the bid and ask prices weighted by the opposite side's
+// size, or their mid when both sizes are zero
+func weigh(bidPrice, bidSize, askPrice, askSize float64) float64 {
+	total := bidSize + askSize
+	if total == 0 {
+		return (bidPrice + askPrice) / 2
+	}
+	return (bidPrice*askSize + askPrice*bidSize) / total
+}
+
+// vwap returns the size-weighted average price and total size of the top
+// levels
+func vwap(side []broker.Level, levels int) (price, size float64) {
+	var notional float64
+	for _, l := range side[:min(levels, len(side))] {
+		notional += l.Price * l.Size
+		size += l.Size
+	}
+	if size == 0 {
+		return side[0].Price, 0
+	}
+	return notional / size, size
+}
@@ -0,0 +1,95 @@
+package book
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func depth() broker.Depth {
+	return broker.Depth{
+		Symbol: "BTC-USDT",
+		Bids:   []broker.Level{{Price: 100, Size: 3}, {Price: 99, Size: 1}},
+		Asks:   []broker.Level{{Price: 101, Size: 1}, {Price: 102, Size: 1}},
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name   string
+		levels int
+		want   Features
+	}{
+		{"top of book", 1, Features{
+			BestBid: 100, BestAsk: 101, Mid: 100.5, Spread: 1,
+			Imbalance:   0.5,    // (3 - 1) / 4
+			Microprice:  100.75, // (100*1 + 101*3) / 4
+			WeightedMid: 100.75, // Same as microprice at one level
+		}},
+		{"two levels", 2, Features{
+			BestBid: 100, BestAsk: 101, Mid: 100.5, Spread: 1,
+			Imbalance:   1.0 / 3, // (4 - 2) / 6
+			Microprice:  100.75,
+			WeightedMid: 100.9166667, // (99.75*2 + 101.5*4) / 6
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Compute(depth(), tt.levels)
+			if !approx(got.BestBid, tt.want.BestBid) || !approx(got.BestAsk, tt.want.BestAsk) ||
+				!approx(got.Mid, tt.want.Mid) || !approx(got.Spread, tt.want.Spread) ||
+				!approx(got.Imbalance, tt.want.Imbalance) || !approx(got.Microprice, tt.want.Microprice) ||
+				math.Abs(got.WeightedMid-tt.want.WeightedMid) > 1e-6 {
+				t.Errorf("Compute() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompute_EmptySide(t *testing.T) {
+	d := depth()
+	d.Asks = nil
+	if got := Compute(d, 5); got != (Features{}) {
+		t.Errorf("Compute() = %+v, want zero features", got)
+	}
+}
+
+type fakeStreamer struct {
+	levels int
+	depths []broker.Depth
+}
+
+func (f *fakeStreamer) StreamDepth(ctx context.Context, symbol string, levels int, handler func(broker.Depth)) error {
+	f.levels = levels
+	for _, d := range f.depths {
+		handler(d)
+	}
+	return context.Canceled
+}
+
+func TestBook_Run(t *testing.T) {
+	b := New(Config{Levels: 2})
+	var updates []Update
+	b.OnUpdate(func(u Update) { updates = append(updates, u) })
+
+	second := depth()
+	second.Bids[0].Size = 1
+	streamer := &fakeStreamer{depths: []broker.Depth{depth(), second}}
+	b.Run(context.Background(), streamer, "BTC-USDT")
+
+	if streamer.levels != 2 {
+		t.Errorf("streamed %d levels, want 2", streamer.levels)
+	}
+	if len(updates) != 2 {
+		t.Fatalf("got %d updates, want 2", len(updates))
+	}
+	if got := b.Last(); got.Depth.Bids[0].Size != 1 || !approx(got.Features.Imbalance, 0) {
+		t.Errorf("Last() = %+v, want the second book with balanced sizes", got)
+	}
+}
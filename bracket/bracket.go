@@ -0,0 +1,344 @@
+// Package bracket places entries with stop-loss and take-profit orders
+// linked to their fills. Protective orders are placed only once the entry
+// fills, sized to the quantity actually filled and resized as partial fills
+// arrive, so they never exceed the position they protect.
+//
+// Fills are observed through an ordertrack.Tracker: run it (or feed it
+// stream updates) for brackets to progress.
+package bracket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+	"github.com/agatticelli/trading-go/ordertrack"
+)
+
+// ErrUnknownBracket is returned for entry IDs the manager did not place
+var ErrUnknownBracket = errors.New("bracket: unknown bracket")
+
+// State is the stage of a bracket
+type State string
+
+const (
+	StatePending State = "PENDING" // Entry resting, nothing filled
+	StateOpen    State = "OPEN"    // Entry (partially) filled and protected
+	StateClosed  State = "CLOSED"  // Exit filled, or entry ended unfilled
+)
+
+// Request describes a bracket to place
+type Request struct {
+	Entry      *broker.OrderRequest
+	StopLoss   float64 // Stop-loss trigger price (0 = none)
+	TakeProfit float64 // Take-profit trigger price (0 = none)
+}
+
+// Bracket is the state of a placed bracket
+type Bracket struct {
+	EntryID      string
+	Symbol       string
+	Side         broker.Side
+	StopLoss     float64
+	TakeProfit   float64
+	Filled       float64 // Entry quantity covered by the protective orders
+	StopLossID   string
+	TakeProfitID string
+	State        State
+}
+
+// Config configures a Manager
+type Config struct {
+	// Logger receives failures to place or cancel protective orders, which
+	// happen on the tracker's goroutine (default: discard)
+	Logger *slog.Logger
+	// OnUnprotected is called when a fill leaves the position without a
+	// requested stop loss or take profit for its full size, because the
+	// order couldn't be placed. It runs on the goroutine applying the fill.
+	OnUnprotected func(b Bracket, err error)
+}
+
+// Manager places brackets and keeps their protective orders in line with
+// the entry fills
+type Manager struct {
+	tracker *ordertrack.Tracker
+	config  Config
+	log     *slog.Logger
+
+	mu         sync.Mutex
+	brackets   map[string]*managed // By entry order ID
+	protective map[string]string   // Protective order ID -> entry order ID
+}
+
+// managed is a bracket with the lock serializing its order changes
+type managed struct {
+	mu sync.Mutex
+	b  Bracket
+}
+
+// New manages brackets whose orders go through t
+func New(t *ordertrack.Tracker, config Config) *Manager {
+	m := &Manager{
+		tracker:    t,
+		config:     config,
+		log:        logging.Component(logging.OrDiscard(config.Logger), "bracket"),
+		brackets:   make(map[string]*managed),
+		protective: make(map[string]string),
+	}
+	t.OnChange(m.handle)
+	return m
+}
+
+// Place places the entry. Stop-loss and take-profit orders follow its
+// fills; protective configs on the entry request itself are ignored. An
+// entry filled on placement whose protective orders fail returns the
+// bracket with the error.
+func (m *Manager) Place(ctx context.Context, req Request) (Bracket, error) {
+	if req.Entry == nil || req.Entry.ReduceOnly {
+		return Bracket{}, broker.ErrInvalidQuantity
+	}
+	if req.StopLoss < 0 || req.TakeProfit < 0 {
+		return Bracket{}, broker.ErrInvalidPrice
+	}
+
+	entry := *req.Entry
+	entry.StopLoss = nil
+	entry.TakeProfit = nil
+	order, err := m.tracker.PlaceOrder(ctx, &entry)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	mb := &managed{b: Bracket{
+		EntryID:    order.ID,
+		Symbol:     entry.Symbol,
+		Side:       entry.Side,
+		StopLoss:   req.StopLoss,
+		TakeProfit: req.TakeProfit,
+		State:      StatePending,
+	}}
+	m.mu.Lock()
+	m.brackets[order.ID] = mb
+	m.mu.Unlock()
+
+	// The entry may have filled before the bracket was registered
+	var protectErr error
+	if current, ok := m.tracker.Get(order.ID); ok {
+		protectErr = m.update(ctx, mb, current)
+	}
+	b, err := m.Get(order.ID)
+	if err != nil {
+		return b, err
+	}
+	return b, protectErr
+}
+
+// Get returns the state of the bracket opened by entryID
+func (m *Manager) Get(entryID string) (Bracket, error) {
+	m.mu.Lock()
+	mb, ok := m.brackets[entryID]
+	m.mu.Unlock()
+	if !ok {
+		return Bracket{}, ErrUnknownBracket
+	}
+
+	mb.mu.Lock()
+	defer mb.mu.Unlock()
+	return mb.b, nil
+}
+
+// Cancel cancels the entry. An unfilled bracket closes; a partially filled
+// one keeps its protective orders for the filled quantity.
+func (m *Manager) Cancel(ctx context.Context, entryID string) error {
+	b, err := m.Get(entryID)
+	if err != nil {
+		return err
+	}
+	return m.tracker.CancelOrder(ctx, b.Symbol, entryID)
+}
+
+// handle routes tracker events to the bracket they belong to
+func (m *Manager) handle(ctx context.Context, e ordertrack.Event) {
+	m.mu.Lock()
+	mb, isEntry := m.brackets[e.Order.ID]
+	if !isEntry {
+		mb = m.brackets[m.protective[e.Order.ID]]
+	}
+	m.mu.Unlock()
+
+	switch {
+	case mb == nil:
+	case isEntry:
+		m.update(ctx, mb, e.Order)
+	case e.Order.Status == broker.OrderStatusFilled:
+		m.exit(ctx, mb, e.Order.ID)
+	}
+}
+
+// update resizes the protective orders to the entry's filled quantity and
+// closes brackets whose entry ended unfilled. It returns the failure to
+// place protective orders, after reporting it to OnUnprotected.
+func (m *Manager) update(ctx context.Context, mb *managed, entry broker.Order) error {
+	mb.mu.Lock()
+	if mb.b.State == StateClosed {
+		mb.mu.Unlock()
+		return nil
+	}
+	var filledID string
+	var err error
+	if entry.FilledSize > mb.b.Filled {
+		filledID, err = m.protect(ctx, mb, entry.FilledSize)
+	}
+	if mb.b.Filled == 0 && mb.b.State == StatePending && isFinal(entry.Status) {
+		mb.b.State = StateClosed
+	}
+	b := mb.b
+	mb.mu.Unlock()
+
+	// A protective order filled as it was placed, before its events could
+	// be routed here
+	if filledID != "" {
+		m.exit(ctx, mb, filledID)
+	}
+	if err != nil {
+		m.log.Error("position left unprotected", logging.KeySymbol, b.Symbol, "entry_id", b.EntryID, logging.KeyError, err)
+		if m.config.OnUnprotected != nil {
+			m.config.OnUnprotected(b, err)
+		}
+	}
+	return err
+}
+
+// protect resizes the protective orders to size. Each new order is placed
+// before the old one is canceled, so the position is never left without
+// one. If the exchange rejects it while the old order rests (reduce-only
+// orders together exceeding the position), the old order is canceled and
+// the new one placed again, falling back to the old size when that fails
+// too. It returns the ID of a protective order already filled when placed
+// ("" for none) and the placement failures, which leave Filled unchanged.
+// Callers must hold mb.mu.
+func (m *Manager) protect(ctx context.Context, mb *managed, size float64) (filledID string, err error) {
+	b := &mb.b
+	b.State = StateOpen
+
+	var errs []error
+	for _, p := range []struct {
+		orderType broker.OrderType
+		price     float64
+		id        *string
+	}{
+		{broker.OrderTypeStop, b.StopLoss, &b.StopLossID},
+		{broker.OrderTypeTakeProfit, b.TakeProfit, &b.TakeProfitID},
+	} {
+		if p.price == 0 {
+			continue
+		}
+		order, err := m.placeProtective(ctx, b, p.orderType, p.price, size)
+		if err != nil && *p.id != "" && m.cancelProtective(ctx, b, *p.id) {
+			*p.id = ""
+			order, err = m.placeProtective(ctx, b, p.orderType, p.price, size)
+			if err != nil {
+				if restored, rerr := m.placeProtective(ctx, b, p.orderType, p.price, b.Filled); rerr == nil {
+					*p.id = restored.ID
+				} else {
+					err = errors.Join(err, rerr)
+				}
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("bracket: placing %s for %v: %w", p.orderType, size, err))
+			continue
+		}
+
+		if old := *p.id; old != "" {
+			m.cancelProtective(ctx, b, old)
+		}
+		*p.id = order.ID
+		if current, ok := m.tracker.Get(order.ID); ok && current.Status == broker.OrderStatusFilled {
+			filledID = order.ID
+		}
+	}
+	if len(errs) == 0 {
+		b.Filled = size
+	}
+	return filledID, errors.Join(errs...)
+}
+
+// placeProtective places a reduce-only exit triggering at price and routes
+// its events to the bracket
+func (m *Manager) placeProtective(ctx context.Context, b *Bracket, orderType broker.OrderType, price, size float64) (*broker.Order, error) {
+	order, err := m.tracker.PlaceOrder(ctx, &broker.OrderRequest{
+		Symbol:     b.Symbol,
+		Side:       opposite(b.Side),
+		Type:       orderType,
+		Size:       size,
+		StopPrice:  price,
+		ReduceOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	m.protective[order.ID] = b.EntryID
+	m.mu.Unlock()
+	return order, nil
+}
+
+// cancelProtective cancels a protective order being replaced, reporting
+// whether it is gone
+func (m *Manager) cancelProtective(ctx context.Context, b *Bracket, id string) bool {
+	err := m.tracker.CancelOrder(ctx, b.Symbol, id)
+	if err != nil && !errors.Is(err, broker.ErrOrderNotFound) {
+		m.log.Error("cancel protective order failed", logging.KeySymbol, b.Symbol, logging.KeyOrderID, id, logging.KeyError, err)
+		return false
+	}
+	return true
+}
+
+// exit closes the bracket after one of its protective orders filled: the
+// other one and any unfilled rest of the entry are canceled
+func (m *Manager) exit(ctx context.Context, mb *managed, filledID string) {
+	mb.mu.Lock()
+	if mb.b.State == StateClosed {
+		mb.mu.Unlock()
+		return
+	}
+	mb.b.State = StateClosed
+	b := mb.b
+	mb.mu.Unlock()
+
+	// Canceling the entry triggers its own event, so mb.mu must be released
+	for _, id := range []string{b.StopLossID, b.TakeProfitID, b.EntryID} {
+		if id == "" || id == filledID {
+			continue
+		}
+		if order, ok := m.tracker.Get(id); ok && isFinal(order.Status) {
+			continue
+		}
+		if err := m.tracker.CancelOrder(ctx, b.Symbol, id); err != nil && !errors.Is(err, broker.ErrOrderNotFound) {
+			m.log.Error("cancel bracket order failed", logging.KeySymbol, b.Symbol, logging.KeyOrderID, id, logging.KeyError, err)
+		}
+	}
+}
+
+// isFinal reports whether no further fills are expected for status
+func isFinal(status broker.OrderStatus) bool {
+	switch status {
+	case broker.OrderStatusFilled, broker.OrderStatusCanceled, broker.OrderStatusRejected,
+		broker.OrderStatusExpired, ordertrack.StatusClosed:
+		return true
+	}
+	return false
+}
+
+func opposite(side broker.Side) broker.Side {
+	if side == broker.SideLong {
+		return broker.SideShort
+	}
+	return broker.SideLong
+}
@@ -0,0 +1,187 @@
+package bracket
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"github.com/agatticelli/trading-go/ordertrack"
+)
+
+func newManager() (*brokertest.Broker, *ordertrack.Tracker, *Manager) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	t := ordertrack.Wrap(b, ordertrack.Config{})
+	return b, t, New(t, Config{})
+}
+
+func request(orderType broker.OrderType) Request {
+	return Request{
+		Entry:      &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: orderType, Size: 1, Price: 49000},
+		StopLoss:   47000,
+		TakeProfit: 53000,
+	}
+}
+
+// fill reports the entry as filled up to size, as a stream update would
+func fill(t *ordertrack.Tracker, id string, size float64) {
+	order, _ := t.Get(id)
+	order.FilledSize = size
+	order.Status = broker.OrderStatusPartiallyFilled
+	if size >= order.Size {
+		order.Status = broker.OrderStatusFilled
+	}
+	t.Apply(context.Background(), order, ordertrack.SourceStream)
+}
+
+func TestManager_PartialFills(t *testing.T) {
+	b, tr, m := newManager()
+	ctx := context.Background()
+
+	br, err := m.Place(ctx, request(broker.OrderTypeLimit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.State != StatePending || br.StopLossID != "" || len(b.PlacedOrders()) != 1 {
+		t.Fatalf("bracket = %+v, want pending with no protective orders", br)
+	}
+
+	fill(tr, br.EntryID, 0.4)
+	br, _ = m.Get(br.EntryID)
+	if br.State != StateOpen || br.Filled != 0.4 {
+		t.Fatalf("after partial fill: %+v", br)
+	}
+	firstSL := br.StopLossID
+
+	fill(tr, br.EntryID, 1)
+	br, _ = m.Get(br.EntryID)
+	if br.Filled != 1 || br.StopLossID == firstSL {
+		t.Fatalf("after full fill: %+v, want resized protective orders", br)
+	}
+	if old, _ := tr.Get(firstSL); old.Status != broker.OrderStatusCanceled {
+		t.Errorf("first stop loss status = %s, want canceled", old.Status)
+	}
+
+	placed := b.PlacedOrders()
+	sl := placed[len(placed)-2]
+	if sl.Type != broker.OrderTypeStop || sl.Side != broker.SideShort || sl.Size != 1 || sl.StopPrice != 47000 || !sl.ReduceOnly {
+		t.Errorf("stop loss = %+v", sl)
+	}
+
+	// Stop loss fills: the take profit goes away
+	stop, _ := tr.Get(br.StopLossID)
+	stop.Status = broker.OrderStatusFilled
+	stop.FilledSize = 1
+	tr.Apply(ctx, stop, ordertrack.SourceStream)
+
+	br, _ = m.Get(br.EntryID)
+	if tp, _ := tr.Get(br.TakeProfitID); br.State != StateClosed || tp.Status != broker.OrderStatusCanceled {
+		t.Errorf("after stop loss fill: bracket %+v, take profit %s", br, tp.Status)
+	}
+}
+
+func TestManager_MarketEntry(t *testing.T) {
+	b, _, m := newManager()
+
+	br, err := m.Place(context.Background(), request(broker.OrderTypeMarket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.State != StateOpen || br.Filled != 1 || br.StopLossID == "" || br.TakeProfitID == "" {
+		t.Errorf("bracket = %+v, want protected immediately", br)
+	}
+	if len(b.PlacedOrders()) != 3 {
+		t.Errorf("placed %d orders, want entry + SL + TP", len(b.PlacedOrders()))
+	}
+}
+
+func TestManager_CancelUnfilled(t *testing.T) {
+	b, _, m := newManager()
+	ctx := context.Background()
+
+	br, err := m.Place(ctx, request(broker.OrderTypeLimit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Cancel(ctx, br.EntryID); err != nil {
+		t.Fatal(err)
+	}
+	br, _ = m.Get(br.EntryID)
+	if br.State != StateClosed || len(b.PlacedOrders()) != 1 {
+		t.Errorf("bracket = %+v, want closed without protective orders", br)
+	}
+	if _, err := m.Get("missing"); !errors.Is(err, ErrUnknownBracket) {
+		t.Errorf("Get(missing) error = %v", err)
+	}
+}
+
+// limited rejects stop orders larger than max, and acknowledges take
+// profits as already filled when fillTP is set
+type limited struct {
+	*brokertest.Broker
+	max    float64
+	fillTP bool
+}
+
+func (l *limited) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	if req.Type == broker.OrderTypeStop && l.max > 0 && req.Size > l.max {
+		return nil, broker.ErrInsufficientBalance
+	}
+	order, err := l.Broker.PlaceOrder(ctx, req)
+	if err != nil || !l.fillTP || req.Type != broker.OrderTypeTakeProfit {
+		return order, err
+	}
+	filled := *order
+	filled.Status = broker.OrderStatusFilled
+	filled.FilledSize = filled.Size
+	return &filled, nil
+}
+
+func TestManager_ResizeRejected(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	tr := ordertrack.Wrap(&limited{Broker: b, max: 0.5}, ordertrack.Config{})
+	var reported error
+	m := New(tr, Config{OnUnprotected: func(_ Bracket, err error) { reported = err }})
+
+	br, err := m.Place(context.Background(), request(broker.OrderTypeLimit))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fill(tr, br.EntryID, 0.4)
+	fill(tr, br.EntryID, 1)
+
+	br, _ = m.Get(br.EntryID)
+	if !errors.Is(reported, broker.ErrInsufficientBalance) {
+		t.Errorf("reported error = %v, want the rejection", reported)
+	}
+	if br.Filled != 0.4 {
+		t.Errorf("filled = %v, want 0.4 still covered", br.Filled)
+	}
+	if sl, _ := tr.Get(br.StopLossID); sl.Size != 0.4 || sl.Status == broker.OrderStatusCanceled {
+		t.Errorf("stop loss = %+v, want the 0.4 stop kept", sl)
+	}
+	if tp, _ := tr.Get(br.TakeProfitID); tp.Size != 1 {
+		t.Errorf("take profit size = %v, want resized to 1", tp.Size)
+	}
+}
+
+func TestManager_ExitFilledOnPlacement(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	tr := ordertrack.Wrap(&limited{Broker: b, fillTP: true}, ordertrack.Config{})
+	m := New(tr, Config{})
+
+	br, err := m.Place(context.Background(), request(broker.OrderTypeMarket))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if br.State != StateClosed {
+		t.Errorf("state = %s, want closed by the take profit", br.State)
+	}
+	if sl, _ := tr.Get(br.StopLossID); sl.Status != broker.OrderStatusCanceled {
+		t.Errorf("stop loss status = %s, want canceled", sl.Status)
+	}
+}
@@ -0,0 +1,82 @@
+package broker
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// AccountRisk summarizes the margin state of an account, e.g. for risk
+// dashboards. Amounts are in the margin asset.
+type AccountRisk struct {
+	Asset             string
+	Equity            float64 // Wallet balance plus unrealized PnL
+	AvailableMargin   float64
+	UsedMargin        float64 // Initial margin of open positions
+	FrozenMargin      float64 // Held by open orders (0 when not reported)
+	MaintenanceMargin float64
+	UnrealizedPnL     float64
+	Notional          float64 // Aggregate position value, long and short
+	Positions         int
+	Timestamp         time.Time
+}
+
+// MarginRatio is maintenance margin over equity: positions are liquidated
+// as it reaches 1. It is +Inf for an account without equity holding
+// positions.
+func (r *AccountRisk) MarginRatio() float64 {
+	if r.Equity <= 0 {
+		if r.MaintenanceMargin > 0 {
+			return math.Inf(1)
+		}
+		return 0
+	}
+	return r.MaintenanceMargin / r.Equity
+}
+
+// Leverage is the effective account leverage, notional over equity
+func (r *AccountRisk) Leverage() float64 {
+	if r.Equity <= 0 {
+		return 0
+	}
+	return r.Notional / r.Equity
+}
+
+// AccountRiskProvider is implemented by brokers that report the account's
+// margin state in one call
+type AccountRiskProvider interface {
+	GetAccountRisk(ctx context.Context) (*AccountRisk, error)
+}
+
+// GetAccountRisk returns the account's margin state, from the broker when
+// it implements AccountRiskProvider and otherwise derived from its balance
+// and positions (notional at mark price).
+func GetAccountRisk(ctx context.Context, b Broker) (*AccountRisk, error) {
+	if p, ok := b.(AccountRiskProvider); ok {
+		return p.GetAccountRisk(ctx)
+	}
+
+	balance, err := b.GetBalance(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positions, err := b.GetPositions(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	risk := &AccountRisk{
+		Asset:           balance.Asset,
+		Equity:          balance.Total,
+		AvailableMargin: balance.Available,
+		UsedMargin:      balance.InUse,
+		UnrealizedPnL:   balance.UnrealizedPnL,
+		Positions:       len(positions),
+		Timestamp:       balance.Timestamp,
+	}
+	for _, p := range positions {
+		risk.MaintenanceMargin += p.MaintenanceMargin
+		risk.Notional += math.Abs(p.Size) * p.MarkPrice
+	}
+	return risk, nil
+}
@@ -0,0 +1,33 @@
+package broker_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestGetAccountRisk_Derived(t *testing.T) {
+	b := brokertest.New()
+	b.SetBalance(broker.Balance{Asset: "USDT", Total: 2000, Available: 1500, InUse: 500})
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.1, MarkPrice: 50000, MaintenanceMargin: 25})
+	b.SetPosition(broker.Position{Symbol: "ETH-USDT", Side: broker.SideShort, Size: 2, MarkPrice: 2500, MaintenanceMargin: 15})
+
+	risk, err := broker.GetAccountRisk(context.Background(), b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if risk.Notional != 10000 || risk.MaintenanceMargin != 40 || risk.Positions != 2 || risk.AvailableMargin != 1500 {
+		t.Errorf("GetAccountRisk() = %+v", risk)
+	}
+	if risk.MarginRatio() != 0.02 || risk.Leverage() != 5 {
+		t.Errorf("margin ratio %v, leverage %v; want 0.02 and 5", risk.MarginRatio(), risk.Leverage())
+	}
+
+	wiped := broker.AccountRisk{MaintenanceMargin: 10}
+	if !math.IsInf(wiped.MarginRatio(), 1) {
+		t.Errorf("MarginRatio() without equity = %v, want +Inf", wiped.MarginRatio())
+	}
+}
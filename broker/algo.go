@@ -0,0 +1,41 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// TWAPRequest asks the exchange to work a parent order as equal child
+// orders spread over time
+type TWAPRequest struct {
+	Symbol     string
+	Side       Side
+	ReduceOnly bool
+	Size       float64       // Total size of the parent order
+	ChildSize  float64       // Size of each child order
+	Interval   time.Duration // Time between child orders
+	LimitPrice float64       // Worst price a child order may fill at
+}
+
+// AlgoOrder is an exchange-managed algorithmic parent order
+type AlgoOrder struct {
+	ID           string
+	Symbol       string
+	Side         Side
+	Type         string // e.g. "TWAP"
+	Status       OrderStatus
+	Size         float64
+	FilledSize   float64
+	AveragePrice float64
+	ReduceOnly   bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+// AlgoOrderBroker is implemented by brokers with native TWAP orders
+type AlgoOrderBroker interface {
+	PlaceTWAPOrder(ctx context.Context, req *TWAPRequest) (*AlgoOrder, error)
+	// GetTWAPOrders returns running TWAP orders (symbol "" = all symbols)
+	GetTWAPOrders(ctx context.Context, symbol string) ([]*AlgoOrder, error)
+	CancelTWAPOrder(ctx context.Context, orderID string) error
+}
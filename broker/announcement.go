@@ -0,0 +1,35 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// AnnouncementKind classifies exchange announcements
+type AnnouncementKind string
+
+const (
+	AnnouncementMaintenance AnnouncementKind = "MAINTENANCE" // Scheduled downtime or system upgrade
+	AnnouncementListing     AnnouncementKind = "LISTING"     // New contracts
+	AnnouncementDelisting   AnnouncementKind = "DELISTING"   // Contracts being removed
+	AnnouncementOther       AnnouncementKind = "OTHER"
+)
+
+// Announcement is a notice published by an exchange
+type Announcement struct {
+	ID        string
+	Kind      AnnouncementKind
+	Title     string
+	URL       string
+	Symbols   []string // Contracts the notice names
+	Published time.Time
+	Start     time.Time // When the announced event begins (zero if not stated)
+	End       time.Time // When it ends (zero if not stated)
+}
+
+// AnnouncementSource is implemented by brokers that publish announcements,
+// e.g. maintenance and listing notices of their support center
+type AnnouncementSource interface {
+	// GetAnnouncements returns the latest announcements, newest first
+	GetAnnouncements(ctx context.Context) ([]Announcement, error)
+}
@@ -25,11 +25,38 @@ type Broker interface {
 	// Configuration
 	SetLeverage(ctx context.Context, symbol string, side string, leverage int) error
 
+	// Health
+	Ping(ctx context.Context) error
+	Status(ctx context.Context) (*ExchangeStatus, error)
+
 	// Metadata
 	Name() string
 	SupportedFeatures() Features
 }
 
+// MarginAdjuster is implemented by brokers that can move margin in and out
+// of isolated positions
+type MarginAdjuster interface {
+	// AdjustMargin adds (positive amount) or removes (negative amount)
+	// isolated margin on a position leg
+	AdjustMargin(ctx context.Context, symbol string, side Side, amount float64) error
+}
+
+// OrderGetter is implemented by brokers that can look up a single order,
+// including filled and canceled ones that left the open-order list
+type OrderGetter interface {
+	GetOrder(ctx context.Context, symbol, orderID string) (*Order, error)
+}
+
+// Shutdowner is implemented by brokers and streamers that hold resources
+// (in-flight requests, WebSockets) which should be released gracefully
+type Shutdowner interface {
+	// Shutdown stops accepting new work, waits for in-flight work to finish
+	// or ctx to end, and closes connections. Calls made afterwards fail with
+	// ErrShuttingDown.
+	Shutdown(ctx context.Context) error
+}
+
 // Features describes broker capabilities
 type Features struct {
 	TrailingStop     bool
@@ -37,6 +64,9 @@ type Features struct {
 	BracketOrders    bool
 	MaxLeverage      int
 	ReduceOnlyOrders bool
+	Environments     []Environment // Deployments the broker can connect to (empty = production only)
+	TimeInForces     []TimeInForce // Accepted time-in-force values (empty = GTC, IOC and FOK)
+	STPModes         []STPMode     // Accepted self-trade prevention modes
 }
 
 // PositionFilter for filtering positions
@@ -0,0 +1,55 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CancelOrdersByTag cancels the open orders on symbol ("" for all symbols)
+// whose client order ID starts with prefix, leaving other strategies' and
+// manual orders alone. The prefix is matched on the encoded ID, so
+// "prod1-" selects a ClientOrderIDs namespace, "prod1-grid-" one tag in it
+// and "grid-" a tag from NewClientOrderID. It returns the canceled orders;
+// orders that fail to cancel are reported in the joined error and the rest
+// are still canceled.
+func CancelOrdersByTag(ctx context.Context, b Broker, symbol, prefix string) ([]*Order, error) {
+	if prefix == "" {
+		return nil, fmt.Errorf("%w: empty prefix would cancel every order", ErrInvalidTag)
+	}
+	prefix = strings.ToLower(prefix)
+
+	orders, err := b.GetOrders(ctx, &OrderFilter{Symbol: symbol})
+	if err != nil {
+		return nil, err
+	}
+
+	var canceled []*Order
+	var errs []error
+	for _, o := range orders {
+		if !openStatus(o.Status) || !strings.HasPrefix(strings.ToLower(o.ClientOrderID), prefix) {
+			continue
+		}
+		if symbol != "" && o.Symbol != symbol {
+			continue
+		}
+		if err := b.CancelOrder(ctx, o.Symbol, o.ID); errors.Is(err, ErrOrderNotFound) {
+			continue // Filled or canceled since listing
+		} else if err != nil {
+			errs = append(errs, fmt.Errorf("canceling %s order %s: %w", o.Symbol, o.ID, err))
+			continue
+		}
+		canceled = append(canceled, o)
+	}
+	return canceled, errors.Join(errs...)
+}
+
+// openStatus reports whether an order with status can still be canceled
+func openStatus(status OrderStatus) bool {
+	switch status {
+	case OrderStatusFilled, OrderStatusCanceled, OrderStatusRejected, OrderStatusExpired:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,50 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestCancelOrdersByTag(t *testing.T) {
+	b := brokertest.New()
+	ctx := context.Background()
+	rest := func(symbol, clientOrderID string) *broker.Order {
+		return b.AddOrder(broker.Order{Symbol: symbol, ClientOrderID: clientOrderID, Side: broker.SideLong,
+			Type: broker.OrderTypeLimit, Status: broker.OrderStatusNew, Size: 1, Price: 100})
+	}
+	grid := rest("BTC-USDT", "prod1-grid-000000000001")
+	rest("BTC-USDT", "prod1-trend-000000000002")
+	rest("BTC-USDT", "") // Manual
+	rest("ETH-USDT", "prod1-grid-000000000003")
+
+	canceled, err := broker.CancelOrdersByTag(ctx, b, "BTC-USDT", "prod1-grid-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(canceled) != 1 || canceled[0].ID != grid.ID {
+		t.Errorf("canceled = %+v, want only the BTC grid order", canceled)
+	}
+
+	canceled, _ = broker.CancelOrdersByTag(ctx, b, "", "PROD1-")
+	if len(canceled) != 2 {
+		t.Errorf("canceled %d orders across symbols, want the 2 remaining prod1 orders", len(canceled))
+	}
+	open, _ := b.GetOrders(ctx, nil)
+	left := 0
+	for _, o := range open {
+		if o.Status == broker.OrderStatusNew {
+			left++
+		}
+	}
+	if left != 1 {
+		t.Errorf("%d open orders left, want only the manual one", left)
+	}
+
+	if _, err := broker.CancelOrdersByTag(ctx, b, "", ""); !errors.Is(err, broker.ErrInvalidTag) {
+		t.Errorf("empty prefix error = %v, want ErrInvalidTag", err)
+	}
+}
@@ -0,0 +1,188 @@
+package broker
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxClientOrderIDLength is the longest client order ID exchanges commonly
+// accept
+const MaxClientOrderIDLength = 40
+
+// MaxTagLength is the longest order tag NewClientOrderID encodes
+const MaxTagLength = 16
+
+// MaxNamespaceLength is the longest ClientOrderIDs namespace
+const MaxNamespaceLength = 8
+
+// ErrInvalidTag is returned for order tags and namespaces that can't be
+// encoded
+var ErrInvalidTag = errors.New("invalid order tag")
+
+// nonceAlphabet is lowercase because some exchanges lowercase client order
+// IDs
+const nonceAlphabet = "0123456789abcdefghijklmnopqrstuvwxyz"
+
+const nonceLength = 12
+
+// ClientOrderID is a decoded client order ID from NewClientOrderID or
+// ClientOrderIDs
+type ClientOrderID struct {
+	Namespace string // Bot instance that placed the order ("" without one)
+	Tag       string // Strategy or signal that placed the order
+	Nonce     string // Makes the ID unique
+}
+
+func (id ClientOrderID) String() string {
+	if id.Namespace == "" {
+		return id.Tag + "-" + id.Nonce
+	}
+	return id.Namespace + "-" + id.Tag + "-" + id.Nonce
+}
+
+// NewClientOrderID returns a unique client order ID carrying tag, the way
+// MetaTrader orders carry a magic number. Tags are 1 to 16 lowercase
+// letters, digits and underscores; uppercase letters are lowered.
+func NewClientOrderID(tag string) (string, error) {
+	tag = strings.ToLower(tag)
+	if err := checkTag(tag); err != nil {
+		return "", err
+	}
+	return ClientOrderID{Tag: tag, Nonce: nonce()}.String(), nil
+}
+
+// ParseClientOrderID decodes an ID made by NewClientOrderID or
+// ClientOrderIDs. It reports false for other IDs, such as those of manual
+// orders.
+func ParseClientOrderID(id string) (ClientOrderID, bool) {
+	var parsed ClientOrderID
+	switch parts := strings.Split(strings.ToLower(id), "-"); len(parts) {
+	case 2:
+		parsed = ClientOrderID{Tag: parts[0], Nonce: parts[1]}
+	case 3:
+		parsed = ClientOrderID{Namespace: parts[0], Tag: parts[1], Nonce: parts[2]}
+		if checkNamespace(parsed.Namespace) != nil {
+			return ClientOrderID{}, false
+		}
+	default:
+		return ClientOrderID{}, false
+	}
+	if checkTag(parsed.Tag) != nil || len(parsed.Nonce) != nonceLength || strings.Trim(parsed.Nonce, nonceAlphabet) != "" {
+		return ClientOrderID{}, false
+	}
+	return parsed, true
+}
+
+// OrderTag returns the tag encoded in the order's client order ID, or ""
+// for untagged orders
+func OrderTag(o *Order) string {
+	id, _ := ParseClientOrderID(o.ClientOrderID)
+	return id.Tag
+}
+
+// WithOrderTag returns a context that places orders under a new client
+// order ID carrying tag, keeping the other options attached to ctx. Use a
+// fresh context per order: the ID is generated once.
+func WithOrderTag(ctx context.Context, tag string) (context.Context, error) {
+	id, err := NewClientOrderID(tag)
+	if err != nil {
+		return ctx, err
+	}
+	opts := OrderOptionsFrom(ctx)
+	opts.ClientOrderID = id
+	return WithOrderOptions(ctx, opts), nil
+}
+
+// ClientOrderIDs generates the client order IDs of one bot instance:
+// namespace, tag and a sequence number seeded from the start time, so IDs
+// stay unique across restarts and sort in placement order. Orders with
+// another namespace, or none, belong to other bots or were placed by hand.
+type ClientOrderIDs struct {
+	namespace string
+
+	mu  sync.Mutex
+	seq uint64
+}
+
+// NewClientOrderIDs creates a generator for namespace, 1 to 8 lowercase
+// letters, digits and underscores naming e.g. the instance or environment
+func NewClientOrderIDs(namespace string) (*ClientOrderIDs, error) {
+	namespace = strings.ToLower(namespace)
+	if err := checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+	return &ClientOrderIDs{namespace: namespace, seq: uint64(time.Now().UnixMicro())}, nil
+}
+
+// Namespace returns the generator's namespace
+func (g *ClientOrderIDs) Namespace() string {
+	return g.namespace
+}
+
+// Next returns a new client order ID carrying tag
+func (g *ClientOrderIDs) Next(tag string) (string, error) {
+	tag = strings.ToLower(tag)
+	if err := checkTag(tag); err != nil {
+		return "", err
+	}
+	g.mu.Lock()
+	g.seq++
+	seq := g.seq
+	g.mu.Unlock()
+
+	n := strconv.FormatUint(seq, len(nonceAlphabet))
+	n = strings.Repeat("0", max(nonceLength-len(n), 0)) + n
+	return ClientOrderID{Namespace: g.namespace, Tag: tag, Nonce: n}.String(), nil
+}
+
+// WithTag is WithOrderTag with an ID from the generator
+func (g *ClientOrderIDs) WithTag(ctx context.Context, tag string) (context.Context, error) {
+	id, err := g.Next(tag)
+	if err != nil {
+		return ctx, err
+	}
+	opts := OrderOptionsFrom(ctx)
+	opts.ClientOrderID = id
+	return WithOrderOptions(ctx, opts), nil
+}
+
+// Owns reports whether clientOrderID was generated in the namespace
+func (g *ClientOrderIDs) Owns(clientOrderID string) bool {
+	id, ok := ParseClientOrderID(clientOrderID)
+	return ok && id.Namespace == g.namespace
+}
+
+func checkTag(tag string) error {
+	return checkName("order tag", tag, MaxTagLength)
+}
+
+func checkNamespace(namespace string) error {
+	return checkName("namespace", namespace, MaxNamespaceLength)
+}
+
+func checkName(what, name string, maxLength int) error {
+	if name == "" || len(name) > maxLength {
+		return fmt.Errorf("%w: %s %q must be 1 to %d characters", ErrInvalidTag, what, name, maxLength)
+	}
+	for _, r := range name {
+		if (r < 'a' || r > 'z') && (r < '0' || r > '9') && r != '_' {
+			return fmt.Errorf("%w: %s %q may only hold letters, digits and underscores", ErrInvalidTag, what, name)
+		}
+	}
+	return nil
+}
+
+func nonce() string {
+	b := make([]byte, nonceLength)
+	rand.Read(b)
+	for i := range b {
+		b[i] = nonceAlphabet[int(b[i])%len(nonceAlphabet)]
+	}
+	return string(b)
+}
@@ -0,0 +1,88 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClientOrderID(t *testing.T) {
+	id, err := broker.NewClientOrderID("EMA_cross")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(id, "ema_cross-") || len(id) > broker.MaxClientOrderIDLength {
+		t.Errorf("id = %q, want the lowercased tag and a nonce", id)
+	}
+	if other, _ := broker.NewClientOrderID("ema_cross"); other == id {
+		t.Error("ids are not unique")
+	}
+
+	parsed, ok := broker.ParseClientOrderID(id)
+	if !ok || parsed.Tag != "ema_cross" || parsed.String() != id {
+		t.Errorf("ParseClientOrderID(%q) = %+v, %v", id, parsed, ok)
+	}
+	if tag := broker.OrderTag(&broker.Order{ClientOrderID: id}); tag != "ema_cross" {
+		t.Errorf("OrderTag() = %q, want ema_cross", tag)
+	}
+
+	for _, manual := range []string{"", "web-order", "1234567890", "a-b-c"} {
+		if _, ok := broker.ParseClientOrderID(manual); ok {
+			t.Errorf("ParseClientOrderID(%q) decoded a foreign ID", manual)
+		}
+	}
+	for _, tag := range []string{"", "has-dash", "has space", strings.Repeat("x", broker.MaxTagLength+1)} {
+		if _, err := broker.NewClientOrderID(tag); !errors.Is(err, broker.ErrInvalidTag) {
+			t.Errorf("NewClientOrderID(%q) error = %v, want ErrInvalidTag", tag, err)
+		}
+	}
+}
+
+func TestWithOrderTag(t *testing.T) {
+	ctx := broker.WithOrderOptions(context.Background(), broker.OrderOptions{PriceProtect: true})
+	ctx, err := broker.WithOrderTag(ctx, "grid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := broker.OrderOptionsFrom(ctx)
+	if id, ok := broker.ParseClientOrderID(opts.ClientOrderID); !ok || id.Tag != "grid" || !opts.PriceProtect {
+		t.Errorf("options = %+v, want a grid client order ID and the existing options", opts)
+	}
+}
+
+func TestClientOrderIDs(t *testing.T) {
+	ids, err := broker.NewClientOrderIDs("Bot1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := ids.Next("grid")
+	second, _ := ids.Next("grid")
+	if !strings.HasPrefix(first, "bot1-grid-") || first >= second || len(first) != len(second) {
+		t.Errorf("ids %q, %q, want namespaced and increasing", first, second)
+	}
+
+	parsed, ok := broker.ParseClientOrderID(first)
+	if !ok || parsed.Namespace != "bot1" || parsed.Tag != "grid" || parsed.String() != first {
+		t.Errorf("ParseClientOrderID(%q) = %+v, %v", first, parsed, ok)
+	}
+
+	// Longest namespace and tag still fit the exchange limit
+	long, _ := broker.NewClientOrderIDs(strings.Repeat("n", broker.MaxNamespaceLength))
+	if id, _ := long.Next(strings.Repeat("t", broker.MaxTagLength)); len(id) > broker.MaxClientOrderIDLength {
+		t.Errorf("id %q is longer than %d", id, broker.MaxClientOrderIDLength)
+	}
+
+	other, _ := broker.NewClientOrderIDs("bot2")
+	theirs, _ := other.Next("grid")
+	untagged, _ := broker.NewClientOrderID("grid")
+	if !ids.Owns(second) || ids.Owns(theirs) || ids.Owns(untagged) || ids.Owns("manual-1") {
+		t.Error("Owns should only match the generator's namespace")
+	}
+
+	if _, err := broker.NewClientOrderIDs("too_long_ns"); !errors.Is(err, broker.ErrInvalidTag) {
+		t.Errorf("long namespace error = %v, want ErrInvalidTag", err)
+	}
+}
@@ -0,0 +1,111 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"math"
+)
+
+// ContractSpec describes how a symbol's order sizes are counted, so an
+// exposure in quote currency ("$500 of BTC") converts to the size each
+// exchange expects.
+//
+// Linear contracts are sized in contracts of ContractSize base units (on
+// most USDT-margined perpetuals one contract is one unit of the base
+// asset). Inverse contracts are sized in contracts of ContractSize quote
+// currency, e.g. 100 USD per BTC-USD contract.
+type ContractSpec struct {
+	Symbol  string
+	Inverse bool
+	// ContractSize is the base units (linear) or quote value (inverse) of
+	// one contract (0 = 1)
+	ContractSize float64
+	// QuantityStep is the lot size sizes round down to (0 = no rounding)
+	QuantityStep float64
+	// MinQuantity is the smallest accepted size; smaller sizes convert to 0
+	MinQuantity float64
+}
+
+// LinearSpec is the spec of a symbol sized in base asset units, the
+// convention for brokers that don't describe their contracts
+func LinearSpec(symbol string) ContractSpec {
+	return ContractSpec{Symbol: symbol}
+}
+
+// contractSize returns ContractSize, defaulting to 1
+func (s ContractSpec) contractSize() float64 {
+	if s.ContractSize > 0 {
+		return s.ContractSize
+	}
+	return 1
+}
+
+// Quantity returns the order size for notional quote currency at price,
+// rounded down to the lot. It returns 0 when the size is under MinQuantity
+// or price is not positive.
+func (s ContractSpec) Quantity(notional, price float64) float64 {
+	if price <= 0 {
+		return 0
+	}
+	if s.Inverse {
+		return s.RoundQuantity(notional / s.contractSize())
+	}
+	return s.RoundQuantity(notional / (price * s.contractSize()))
+}
+
+// QuantityForBase returns the order size for base units of the asset at
+// price, rounded like Quantity
+func (s ContractSpec) QuantityForBase(base, price float64) float64 {
+	return s.Quantity(base*price, price)
+}
+
+// Notional returns the quote value of quantity at price
+func (s ContractSpec) Notional(quantity, price float64) float64 {
+	if s.Inverse {
+		return quantity * s.contractSize()
+	}
+	return quantity * s.contractSize() * price
+}
+
+// Base returns the base units of quantity at price
+func (s ContractSpec) Base(quantity, price float64) float64 {
+	if s.Inverse {
+		if price <= 0 {
+			return 0
+		}
+		return quantity * s.contractSize() / price
+	}
+	return quantity * s.contractSize()
+}
+
+// RoundQuantity rounds quantity down to the lot, returning 0 under
+// MinQuantity
+func (s ContractSpec) RoundQuantity(quantity float64) float64 {
+	if step := s.QuantityStep; step > 0 {
+		// Tolerate binary fractions like 0.299999... for 0.3
+		quantity = math.Floor(quantity/step+1e-9) * step
+	}
+	if quantity < s.MinQuantity || quantity <= 0 {
+		return 0
+	}
+	return quantity
+}
+
+// ContractSpecProvider is implemented by brokers that know their contract
+// specifications
+type ContractSpecProvider interface {
+	ContractSpec(ctx context.Context, symbol string) (ContractSpec, error)
+}
+
+// GetContractSpec returns the contract spec of symbol from b. Brokers not
+// implementing ContractSpecProvider, or returning ErrNotSupported, get
+// LinearSpec.
+func GetContractSpec(ctx context.Context, b Broker, symbol string) (ContractSpec, error) {
+	if p, ok := b.(ContractSpecProvider); ok {
+		spec, err := p.ContractSpec(ctx, symbol)
+		if !errors.Is(err, ErrNotSupported) {
+			return spec, err
+		}
+	}
+	return LinearSpec(symbol), nil
+}
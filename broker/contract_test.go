@@ -0,0 +1,63 @@
+package broker_test
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestContractSpec_Conversions(t *testing.T) {
+	tests := []struct {
+		name         string
+		spec         broker.ContractSpec
+		notional     float64
+		price        float64
+		wantQuantity float64
+		wantBase     float64 // Base units of wantQuantity
+	}{
+		{"linear", broker.LinearSpec("BTC-USDT"), 500, 50000, 0.01, 0.01},
+		{"linear lot", broker.ContractSpec{QuantityStep: 0.001}, 500, 60000, 0.008, 0.008},
+		{"linear multiplier", broker.ContractSpec{ContractSize: 0.01, QuantityStep: 1}, 500, 2500, 20, 0.2},
+		{"inverse", broker.ContractSpec{Inverse: true, ContractSize: 100, QuantityStep: 1}, 550, 50000, 5, 0.01},
+		{"under minimum", broker.ContractSpec{QuantityStep: 0.001, MinQuantity: 0.001}, 20, 50000, 0, 0},
+		{"no price", broker.LinearSpec("BTC-USDT"), 500, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := tt.spec.Quantity(tt.notional, tt.price)
+			if math.Abs(q-tt.wantQuantity) > 1e-12 {
+				t.Fatalf("Quantity(%v, %v) = %v, want %v", tt.notional, tt.price, q, tt.wantQuantity)
+			}
+			if base := tt.spec.Base(q, tt.price); math.Abs(base-tt.wantBase) > 1e-12 {
+				t.Errorf("Base(%v) = %v, want %v", q, base, tt.wantBase)
+			}
+			if back := tt.spec.QuantityForBase(tt.wantBase, tt.price); math.Abs(back-q) > 1e-12 {
+				t.Errorf("QuantityForBase(%v) = %v, want %v", tt.wantBase, back, q)
+			}
+			if tt.price > 0 && q > 0 && tt.spec.Notional(q, tt.price) > tt.notional {
+				t.Errorf("Notional(%v) = %v, over the %v asked for", q, tt.spec.Notional(q, tt.price), tt.notional)
+			}
+		})
+	}
+}
+
+func TestContractSpec_RoundQuantity(t *testing.T) {
+	spec := broker.ContractSpec{QuantityStep: 0.1}
+	// 0.3 is 0.29999999999999999 in binary and must not round down to 0.2
+	if got := spec.RoundQuantity(0.1 + 0.2); math.Abs(got-0.3) > 1e-12 {
+		t.Errorf("RoundQuantity(0.3) = %v, want 0.3", got)
+	}
+	if got := spec.RoundQuantity(0.39); math.Abs(got-0.3) > 1e-12 {
+		t.Errorf("RoundQuantity(0.39) = %v, want 0.3", got)
+	}
+}
+
+func TestGetContractSpec_Fallback(t *testing.T) {
+	spec, err := broker.GetContractSpec(context.Background(), brokertest.New(), "ETH-USDT")
+	if err != nil || spec != broker.LinearSpec("ETH-USDT") {
+		t.Errorf("GetContractSpec() = %+v, %v, want LinearSpec", spec, err)
+	}
+}
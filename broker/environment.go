@@ -0,0 +1,52 @@
+package broker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Environment selects which of an exchange's deployments a broker talks to
+type Environment string
+
+const (
+	// EnvironmentProduction trades real funds
+	EnvironmentProduction Environment = "production"
+	// EnvironmentTestnet trades simulated funds on the exchange's testnet or
+	// demo deployment
+	EnvironmentTestnet Environment = "testnet"
+)
+
+// ParseEnvironment parses an environment name. "demo", "sandbox" and
+// "paper" are accepted for EnvironmentTestnet, "live" and "mainnet" for
+// EnvironmentProduction; the empty string is production.
+func ParseEnvironment(s string) (Environment, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "production", "prod", "live", "mainnet":
+		return EnvironmentProduction, nil
+	case "testnet", "demo", "sandbox", "paper":
+		return EnvironmentTestnet, nil
+	}
+	return "", fmt.Errorf("unknown environment %q (want production or testnet)", s)
+}
+
+// Env returns the environment the configuration selects, honoring the
+// deprecated Demo flag when Environment is unset
+func (c Config) Env() Environment {
+	if c.Environment != "" {
+		return c.Environment
+	}
+	if c.Demo {
+		return EnvironmentTestnet
+	}
+	return EnvironmentProduction
+}
+
+// SupportsEnvironment reports whether the broker can connect to env. A
+// broker that lists no environments supports production only.
+func (f Features) SupportsEnvironment(env Environment) bool {
+	if len(f.Environments) == 0 {
+		return env == EnvironmentProduction
+	}
+	return slices.Contains(f.Environments, env)
+}
@@ -3,6 +3,7 @@ package broker
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 var (
@@ -16,6 +17,12 @@ var (
 	ErrAuthFailed          = errors.New("authentication failed")
 	ErrRateLimited         = errors.New("rate limited")
 	ErrAPIError            = errors.New("API error")
+	ErrNotSupported        = errors.New("operation not supported")
+	ErrUnknownBroker       = errors.New("unknown broker")
+	ErrShuttingDown        = errors.New("shutting down")
+	ErrKillSwitch          = errors.New("kill switch engaged")
+	ErrInvalidExpiry       = errors.New("invalid expiry")
+	ErrMaintenance         = errors.New("exchange under maintenance")
 )
 
 // BrokerError wraps exchange-specific errors
@@ -24,9 +31,17 @@ type BrokerError struct {
 	Code    string
 	Message string
 	Err     error
+	// RetryAfter is the wait the exchange asked for (Retry-After header),
+	// zero when it gave none
+	RetryAfter time.Duration
+	// RequestID is the ID of the failed request (see WithRequestID)
+	RequestID string
 }
 
 func (e *BrokerError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("%s error [%s]: %s (request %s)", e.Broker, e.Code, e.Message, e.RequestID)
+	}
 	return fmt.Sprintf("%s error [%s]: %s", e.Broker, e.Code, e.Message)
 }
 
@@ -43,3 +58,12 @@ func NewBrokerError(broker, code, message string, err error) *BrokerError {
 		Err:     err,
 	}
 }
+
+// RetryAfter returns the retry hint carried by a BrokerError in err's chain
+func RetryAfter(err error) (time.Duration, bool) {
+	var be *BrokerError
+	if errors.As(err, &be) && be.RetryAfter > 0 {
+		return be.RetryAfter, true
+	}
+	return 0, false
+}
@@ -41,6 +41,16 @@ func TestBrokerError_Error(t *testing.T) {
 			},
 			wantMsg: "BingX error [429]: Too many requests",
 		},
+		{
+			name: "With request ID",
+			err: &BrokerError{
+				Broker:    "BingX",
+				Code:      "API_101204",
+				Message:   "Insufficient margin",
+				RequestID: "req-42",
+			},
+			wantMsg: "BingX error [API_101204]: Insufficient margin (request req-42)",
+		},
 	}
 
 	for _, tt := range tests {
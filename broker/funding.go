@@ -0,0 +1,29 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// FundingRate is the current funding state of a perpetual contract
+type FundingRate struct {
+	Symbol          string
+	Rate            float64 // Per funding interval; positive means longs pay shorts
+	Interval        time.Duration
+	NextFundingTime time.Time
+	MarkPrice       float64
+	IndexPrice      float64
+}
+
+// APR annualizes the funding rate
+func (f FundingRate) APR() float64 {
+	if f.Interval <= 0 {
+		return 0
+	}
+	return f.Rate * float64(365*24*time.Hour) / float64(f.Interval)
+}
+
+// FundingRateProvider is implemented by brokers that trade perpetual contracts
+type FundingRateProvider interface {
+	GetFundingRate(ctx context.Context, symbol string) (*FundingRate, error)
+}
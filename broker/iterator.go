@@ -0,0 +1,132 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// PageRequest describes the window requested from a paginated endpoint.
+// Exchanges paginate differently (page numbers, opaque cursors or time
+// windows); adapters use whichever fields their endpoint understands.
+type PageRequest struct {
+	Page      int    // 1-based page number for page-indexed endpoints
+	Cursor    string // Opaque cursor for cursor-based endpoints
+	StartTime time.Time
+	EndTime   time.Time
+	Limit     int // Max items per page (0 = exchange default)
+}
+
+// NextPage returns a copy of the request pointing at the following page number
+func (r PageRequest) NextPage() *PageRequest {
+	next := r
+	if next.Page < 1 {
+		next.Page = 1
+	}
+	next.Page++
+	return &next
+}
+
+// After returns a copy of the request whose window starts just after t.
+// Used by time-windowed endpoints that page by advancing startTime past the
+// last item returned.
+func (r PageRequest) After(t time.Time) *PageRequest {
+	next := r
+	next.StartTime = t.Add(time.Millisecond)
+	return &next
+}
+
+// PageFunc fetches a single page. It returns the items of that page and the
+// request for the following page, or nil when there are no more pages.
+type PageFunc[T any] func(ctx context.Context, req PageRequest) ([]T, *PageRequest, error)
+
+// Iterator streams items from a paginated endpoint page by page, hiding
+// cursor and time-window management from the caller:
+//
+//	it := broker.NewIterator(fetchPage, broker.PageRequest{Limit: 100})
+//	for it.Next(ctx) {
+//		item := it.Item()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	fetch PageFunc[T]
+	next  *PageRequest
+	page  []T
+	pos   int
+	item  T
+	err   error
+}
+
+// NewIterator creates an iterator starting at the given page request
+func NewIterator[T any](fetch PageFunc[T], first PageRequest) *Iterator[T] {
+	return &Iterator[T]{
+		fetch: fetch,
+		next:  &first,
+	}
+}
+
+// Next advances to the next item, fetching the following page when the
+// current one is exhausted. It returns false when there are no more items or
+// an error occurred; check Err to distinguish the two.
+func (it *Iterator[T]) Next(ctx context.Context) bool {
+	if it.err != nil {
+		return false
+	}
+
+	for it.pos >= len(it.page) {
+		if it.next == nil {
+			return false
+		}
+		if err := ctx.Err(); err != nil {
+			it.err = err
+			return false
+		}
+
+		items, next, err := it.fetch(ctx, *it.next)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		// An empty page ends iteration even if the fetcher returned a next
+		// request, otherwise a misbehaving endpoint could loop forever
+		if len(items) == 0 {
+			it.next = nil
+			return false
+		}
+
+		it.page = items
+		it.pos = 0
+		it.next = next
+	}
+
+	it.item = it.page[it.pos]
+	it.pos++
+	return true
+}
+
+// Item returns the current item. Only valid after Next returned true.
+func (it *Iterator[T]) Item() T {
+	return it.item
+}
+
+// Err returns the first error encountered during iteration
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// Collect drains the iterator into a slice
+func Collect[T any](ctx context.Context, it *Iterator[T]) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Item())
+	}
+	return items, it.Err()
+}
+
+// SliceIterator returns an iterator over an in-memory slice. Useful for
+// adapters whose endpoint returns everything in one response, and in tests.
+func SliceIterator[T any](items []T) *Iterator[T] {
+	return NewIterator(func(ctx context.Context, req PageRequest) ([]T, *PageRequest, error) {
+		return items, nil, nil
+	}, PageRequest{})
+}
@@ -0,0 +1,153 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIterator_PageNumbers(t *testing.T) {
+	pages := map[int][]int{
+		1: {1, 2, 3},
+		2: {4, 5, 6},
+		3: {7},
+	}
+
+	var requested []int
+	fetch := func(ctx context.Context, req PageRequest) ([]int, *PageRequest, error) {
+		requested = append(requested, req.Page)
+		items := pages[req.Page]
+		if len(items) < 3 {
+			return items, nil, nil
+		}
+		return items, req.NextPage(), nil
+	}
+
+	got, err := Collect(context.Background(), NewIterator(fetch, PageRequest{Page: 1, Limit: 3}))
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+
+	want := []int{1, 2, 3, 4, 5, 6, 7}
+	if len(got) != len(want) {
+		t.Fatalf("Collect() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("item %d = %d, want %d", i, got[i], want[i])
+		}
+	}
+
+	if len(requested) != 3 {
+		t.Errorf("fetched %d pages, want 3", len(requested))
+	}
+}
+
+func TestIterator_TimeWindow(t *testing.T) {
+	base := time.UnixMilli(1_700_000_000_000)
+	all := []time.Time{base, base.Add(time.Second), base.Add(2 * time.Second), base.Add(3 * time.Second)}
+
+	fetch := func(ctx context.Context, req PageRequest) ([]time.Time, *PageRequest, error) {
+		var items []time.Time
+		for _, ts := range all {
+			if !ts.Before(req.StartTime) && len(items) < req.Limit {
+				items = append(items, ts)
+			}
+		}
+		if len(items) == 0 {
+			return nil, nil, nil
+		}
+		return items, req.After(items[len(items)-1]), nil
+	}
+
+	got, err := Collect(context.Background(), NewIterator(fetch, PageRequest{StartTime: base, Limit: 3}))
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(got) != len(all) {
+		t.Fatalf("len(Collect()) = %d, want %d", len(got), len(all))
+	}
+	for i := range all {
+		if !got[i].Equal(all[i]) {
+			t.Errorf("item %d = %v, want %v", i, got[i], all[i])
+		}
+	}
+}
+
+func TestIterator_Error(t *testing.T) {
+	wantErr := errors.New("boom")
+	calls := 0
+	fetch := func(ctx context.Context, req PageRequest) ([]string, *PageRequest, error) {
+		calls++
+		if calls == 2 {
+			return nil, nil, wantErr
+		}
+		return []string{"a"}, req.NextPage(), nil
+	}
+
+	it := NewIterator(fetch, PageRequest{Page: 1})
+	ctx := context.Background()
+
+	if !it.Next(ctx) || it.Item() != "a" {
+		t.Fatalf("first Next() should yield %q", "a")
+	}
+	if it.Next(ctx) {
+		t.Fatal("Next() = true after fetch error, want false")
+	}
+	if !errors.Is(it.Err(), wantErr) {
+		t.Errorf("Err() = %v, want %v", it.Err(), wantErr)
+	}
+	if it.Next(ctx) {
+		t.Error("Next() should stay false once an error occurred")
+	}
+}
+
+func TestIterator_EmptyPageStops(t *testing.T) {
+	calls := 0
+	fetch := func(ctx context.Context, req PageRequest) ([]int, *PageRequest, error) {
+		calls++
+		return nil, req.NextPage(), nil
+	}
+
+	it := NewIterator(fetch, PageRequest{})
+	if it.Next(context.Background()) {
+		t.Error("Next() = true on empty page, want false")
+	}
+	if calls != 1 {
+		t.Errorf("fetch called %d times, want 1", calls)
+	}
+	if it.Err() != nil {
+		t.Errorf("Err() = %v, want nil", it.Err())
+	}
+}
+
+func TestIterator_ContextCanceled(t *testing.T) {
+	fetch := func(ctx context.Context, req PageRequest) ([]int, *PageRequest, error) {
+		return []int{1}, req.NextPage(), nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := NewIterator(fetch, PageRequest{})
+	if !it.Next(ctx) {
+		t.Fatal("first Next() = false, want true")
+	}
+
+	cancel()
+	if it.Next(ctx) {
+		t.Error("Next() = true after cancel, want false")
+	}
+	if !errors.Is(it.Err(), context.Canceled) {
+		t.Errorf("Err() = %v, want context.Canceled", it.Err())
+	}
+}
+
+func TestSliceIterator(t *testing.T) {
+	got, err := Collect(context.Background(), SliceIterator([]string{"x", "y"}))
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "x" || got[1] != "y" {
+		t.Errorf("Collect() = %v, want [x y]", got)
+	}
+}
@@ -0,0 +1,160 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"time"
+)
+
+// KillOptions configures what engaging a KillSwitch does
+type KillOptions struct {
+	// Flatten closes every open position after canceling orders
+	Flatten bool
+	// Reason is recorded for KillSwitch.State
+	Reason string
+}
+
+// KillState describes whether a KillSwitch is engaged
+type KillState struct {
+	Engaged   bool
+	Reason    string
+	EngagedAt time.Time
+}
+
+// KillSwitch halts trading across several brokers at once. Brokers are
+// registered through Wrap; while the switch is engaged the wrapped brokers
+// reject every write operation except cancellations with ErrKillSwitch,
+// until Rearm is called.
+type KillSwitch struct {
+	mu      sync.Mutex
+	brokers []Broker
+	state   KillState
+	now     func() time.Time
+}
+
+// NewKillSwitch creates a disarmed kill switch
+func NewKillSwitch() *KillSwitch {
+	return &KillSwitch{now: time.Now}
+}
+
+// Wrap registers b with the kill switch and returns it locked by the switch.
+// Trading must go through the returned broker for the lock to apply.
+func (k *KillSwitch) Wrap(b Broker) Broker {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.brokers = append(k.brokers, b)
+	return &killSwitchBroker{Broker: b, kill: k}
+}
+
+// Engage locks every registered broker, cancels all their open orders and,
+// with Flatten, closes all their positions. Every broker is attempted even
+// when one fails; failures are joined in the returned error and the switch
+// stays engaged regardless. Engaging an engaged switch runs the cleanup
+// again, which is useful when a previous attempt failed.
+func (k *KillSwitch) Engage(ctx context.Context, opts KillOptions) error {
+	k.mu.Lock()
+	if !k.state.Engaged {
+		k.state = KillState{Engaged: true, Reason: opts.Reason, EngagedAt: k.now()}
+	}
+	brokers := append([]Broker(nil), k.brokers...)
+	k.mu.Unlock()
+
+	var errs []error
+	for _, b := range brokers {
+		if err := b.CancelAllOrders(ctx, ""); err != nil {
+			errs = append(errs, fmt.Errorf("%s: canceling orders: %w", b.Name(), err))
+		}
+		if !opts.Flatten {
+			continue
+		}
+
+		positions, err := b.GetPositions(ctx, nil)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: listing positions: %w", b.Name(), err))
+			continue
+		}
+		for _, net := range NetAll(positions) {
+			if _, err := FlattenPosition(ctx, b, net.Symbol); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", b.Name(), err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Rearm unlocks the registered brokers
+func (k *KillSwitch) Rearm() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.state = KillState{}
+}
+
+// State returns whether the switch is engaged, and why
+func (k *KillSwitch) State() KillState {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.state
+}
+
+// NotifySignal engages the switch when one of sigs is received, until ctx
+// ends. The result of each Engage is sent on the returned channel, which is
+// closed when ctx ends; receivers should keep up or results are dropped.
+func (k *KillSwitch) NotifySignal(ctx context.Context, opts KillOptions, sigs ...os.Signal) <-chan error {
+	received := make(chan os.Signal, 1)
+	signal.Notify(received, sigs...)
+	results := make(chan error, 1)
+
+	go func() {
+		defer close(results)
+		defer signal.Stop(received)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-received:
+				err := k.Engage(ctx, opts)
+				select {
+				case results <- err:
+				default:
+				}
+			}
+		}
+	}()
+	return results
+}
+
+// check fails while the switch is engaged
+func (k *KillSwitch) check() error {
+	state := k.State()
+	if !state.Engaged {
+		return nil
+	}
+	if state.Reason == "" {
+		return ErrKillSwitch
+	}
+	return fmt.Errorf("%w: %s", ErrKillSwitch, state.Reason)
+}
+
+// killSwitchBroker rejects write operations while its KillSwitch is engaged
+type killSwitchBroker struct {
+	Broker
+	kill *KillSwitch
+}
+
+func (b *killSwitchBroker) PlaceOrder(ctx context.Context, order *OrderRequest) (*Order, error) {
+	if err := b.kill.check(); err != nil {
+		return nil, err
+	}
+	return b.Broker.PlaceOrder(ctx, order)
+}
+
+func (b *killSwitchBroker) SetLeverage(ctx context.Context, symbol string, side string, leverage int) error {
+	if err := b.kill.check(); err != nil {
+		return err
+	}
+	return b.Broker.SetLeverage(ctx, symbol, side, leverage)
+}
@@ -0,0 +1,82 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestKillSwitch(t *testing.T) {
+	ctx := context.Background()
+	k := broker.NewKillSwitch()
+
+	var inner []*brokertest.Broker
+	var wrapped []broker.Broker
+	for _, symbol := range []string{"BTC-USDT", "ETH-USDT"} {
+		b := brokertest.New()
+		b.SetPrice(symbol, 100)
+		b.SetPosition(broker.Position{Symbol: symbol, Side: broker.SideLong, Size: 1, EntryPrice: 90})
+		b.AddOrder(broker.Order{ID: "1", Symbol: symbol, Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 80, Status: broker.OrderStatusNew})
+		inner = append(inner, b)
+		wrapped = append(wrapped, k.Wrap(b))
+	}
+
+	if err := k.Engage(ctx, broker.KillOptions{Flatten: true, Reason: "drawdown"}); err != nil {
+		t.Fatal(err)
+	}
+	if state := k.State(); !state.Engaged || state.Reason != "drawdown" || state.EngagedAt.IsZero() {
+		t.Errorf("State() = %+v, want engaged for drawdown", state)
+	}
+
+	for i, b := range inner {
+		if orders, _ := b.GetOrders(ctx, nil); len(orders) != 0 {
+			t.Errorf("broker %d: %d open orders left", i, len(orders))
+		}
+		if positions, _ := b.GetPositions(ctx, nil); len(positions) != 0 {
+			t.Errorf("broker %d: %d positions left", i, len(positions))
+		}
+	}
+
+	req := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1}
+	_, err := wrapped[0].PlaceOrder(ctx, req)
+	if !errors.Is(err, broker.ErrKillSwitch) || !strings.Contains(err.Error(), "drawdown") {
+		t.Errorf("PlaceOrder() while engaged error = %v, want ErrKillSwitch with reason", err)
+	}
+	if err := wrapped[1].SetLeverage(ctx, "ETH-USDT", "LONG", 5); !errors.Is(err, broker.ErrKillSwitch) {
+		t.Errorf("SetLeverage() while engaged error = %v, want ErrKillSwitch", err)
+	}
+	if err := wrapped[1].CancelAllOrders(ctx, ""); err != nil {
+		t.Errorf("cancellation while engaged failed: %v", err)
+	}
+
+	k.Rearm()
+	if _, err := wrapped[0].PlaceOrder(ctx, req); err != nil {
+		t.Errorf("PlaceOrder() after Rearm: %v", err)
+	}
+}
+
+func TestKillSwitch_ContinuesPastFailures(t *testing.T) {
+	ctx := context.Background()
+	k := broker.NewKillSwitch()
+
+	failing := brokertest.New()
+	failing.Err = broker.ErrAPIError
+	healthy := brokertest.New()
+	healthy.AddOrder(broker.Order{ID: "1", Symbol: "BTC-USDT", Status: broker.OrderStatusNew})
+	k.Wrap(failing)
+	k.Wrap(healthy)
+
+	if err := k.Engage(ctx, broker.KillOptions{}); !errors.Is(err, broker.ErrAPIError) {
+		t.Errorf("Engage() error = %v, want the failing broker's error", err)
+	}
+	if orders, _ := healthy.GetOrders(ctx, nil); len(orders) != 0 {
+		t.Error("healthy broker's orders must still be canceled")
+	}
+	if !k.State().Engaged {
+		t.Error("switch must stay engaged after a failed cleanup")
+	}
+}
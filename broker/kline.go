@@ -0,0 +1,17 @@
+package broker
+
+import "time"
+
+// Kline is an OHLCV candlestick
+type Kline struct {
+	Symbol    string
+	Interval  string // e.g. "1m", "1h"
+	OpenTime  time.Time
+	CloseTime time.Time
+	Open      float64
+	High      float64
+	Low       float64
+	Close     float64
+	Volume    float64
+	Closed    bool // False while the candle is still forming (streaming updates)
+}
@@ -0,0 +1,71 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultLiquidationBuffer is the default channel capacity of Liquidations
+const DefaultLiquidationBuffer = 256
+
+// Liquidation is a forced-liquidation order executed by the exchange
+type Liquidation struct {
+	Symbol string
+	// Side of the liquidation order: SideShort when a long position was
+	// liquidated (the exchange sold it), SideLong for a short
+	Side  Side
+	Price float64 // Average fill price
+	Size  float64
+	Time  time.Time
+}
+
+// Notional returns the liquidated value in quote currency
+func (l Liquidation) Notional() float64 {
+	return l.Price * l.Size
+}
+
+// LiquidatedSide returns the side of the position that was liquidated
+func (l Liquidation) LiquidatedSide() Side {
+	if l.Side == SideShort {
+		return SideLong
+	}
+	return SideShort
+}
+
+// LiquidationStreamer is implemented by brokers with a public liquidation
+// feed. The bingx client doesn't implement it: BingX has no such feed.
+type LiquidationStreamer interface {
+	// StreamLiquidations calls handler for every forced liquidation on
+	// symbol ("" for all symbols) until the context is canceled or the
+	// connection fails. Handlers run on the stream goroutine and should
+	// return quickly.
+	StreamLiquidations(ctx context.Context, symbol string, handler func(Liquidation)) error
+}
+
+// Liquidations streams liquidations on symbol into a channel with room for
+// buffer events (default 256). When the buffer is full the stream waits for
+// the reader, so consumers must keep up during cascades. Both channels are
+// closed when the stream ends; errs then carries the error that ended it,
+// if any besides the context's cancellation.
+func Liquidations(ctx context.Context, s LiquidationStreamer, symbol string, buffer int) (<-chan Liquidation, <-chan error) {
+	if buffer <= 0 {
+		buffer = DefaultLiquidationBuffer
+	}
+	events := make(chan Liquidation, buffer)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(events)
+		err := s.StreamLiquidations(ctx, symbol, func(l Liquidation) {
+			select {
+			case events <- l:
+			case <-ctx.Done():
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			errs <- err
+		}
+	}()
+	return events, errs
+}
@@ -0,0 +1,66 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// liquidationFeed replays fixed liquidations, then fails with err
+type liquidationFeed struct {
+	events []broker.Liquidation
+	err    error
+}
+
+func (f liquidationFeed) StreamLiquidations(ctx context.Context, symbol string, handler func(broker.Liquidation)) error {
+	for _, l := range f.events {
+		if symbol == "" || l.Symbol == symbol {
+			handler(l)
+		}
+	}
+	if f.err != nil {
+		return f.err
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestLiquidations(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	feed := liquidationFeed{
+		events: []broker.Liquidation{
+			{Symbol: "BTC-USDT", Side: broker.SideShort, Price: 50000, Size: 0.5, Time: at},
+			{Symbol: "ETH-USDT", Side: broker.SideLong, Price: 3000, Size: 2, Time: at},
+		},
+		err: errors.New("stream dropped"),
+	}
+
+	events, errs := broker.Liquidations(context.Background(), feed, "", 1)
+	var got []broker.Liquidation
+	for l := range events {
+		got = append(got, l)
+	}
+	if len(got) != 2 || got[0].Notional() != 25000 || got[0].LiquidatedSide() != broker.SideLong {
+		t.Errorf("events = %+v, want both, the first a 25000 long liquidation", got)
+	}
+	if err := <-errs; err == nil || err.Error() != "stream dropped" {
+		t.Errorf("error = %v, want the stream failure", err)
+	}
+
+	// Cancellation ends the stream without an error
+	ctx, cancel := context.WithCancel(context.Background())
+	feed.err = nil
+	events, errs = broker.Liquidations(ctx, feed, "ETH-USDT", 0)
+	if l := <-events; l.Symbol != "ETH-USDT" {
+		t.Errorf("event = %+v, want the ETH-USDT liquidation", l)
+	}
+	cancel()
+	for range events {
+	}
+	if err := <-errs; err != nil {
+		t.Errorf("error after cancel = %v, want nil", err)
+	}
+}
@@ -0,0 +1,141 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// NetPosition combines the LONG and SHORT legs a symbol can hold in hedge
+// mode into a single exposure
+type NetPosition struct {
+	Symbol string
+	Long   *Position // nil when there is no long leg
+	Short  *Position // nil when there is no short leg
+
+	// NetSize is long size minus short size: positive when net long,
+	// negative when net short
+	NetSize float64
+	// GrossSize is long size plus short size
+	GrossSize float64
+	// Notional is the net exposure valued at mark price
+	Notional      float64
+	UnrealizedPnL float64
+	RealizedPnL   float64
+	Margin        float64
+}
+
+// Side returns the side of the net exposure, or "" when the legs offset
+func (n NetPosition) Side() Side {
+	switch {
+	case n.NetSize > 0:
+		return SideLong
+	case n.NetSize < 0:
+		return SideShort
+	default:
+		return ""
+	}
+}
+
+// Hedged reports whether both legs are open
+func (n NetPosition) Hedged() bool {
+	return n.Long != nil && n.Short != nil
+}
+
+// Net combines the positions of symbol into a NetPosition. Positions of
+// other symbols are ignored.
+func Net(symbol string, positions []*Position) NetPosition {
+	net := NetPosition{Symbol: symbol}
+	for _, p := range positions {
+		if p.Symbol != symbol || p.Size == 0 {
+			continue
+		}
+
+		leg := *p
+		if p.Side == SideLong {
+			net.Long = &leg
+			net.NetSize += p.Size
+		} else {
+			net.Short = &leg
+			net.NetSize -= p.Size
+		}
+		net.GrossSize += p.Size
+		net.UnrealizedPnL += p.UnrealizedPnL
+		net.RealizedPnL += p.RealizedPnL
+		net.Margin += p.Margin
+		if p.MarkPrice > 0 {
+			net.Notional = p.MarkPrice
+		}
+	}
+	net.Notional *= net.NetSize
+	if net.Notional < 0 {
+		net.Notional = -net.Notional
+	}
+	return net
+}
+
+// NetAll combines positions per symbol, sorted by symbol
+func NetAll(positions []*Position) []NetPosition {
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, p := range positions {
+		if !seen[p.Symbol] {
+			seen[p.Symbol] = true
+			symbols = append(symbols, p.Symbol)
+		}
+	}
+	sort.Strings(symbols)
+
+	nets := make([]NetPosition, 0, len(symbols))
+	for _, symbol := range symbols {
+		nets = append(nets, Net(symbol, positions))
+	}
+	return nets
+}
+
+// GetNetPosition fetches the legs of symbol and combines them
+func GetNetPosition(ctx context.Context, b Broker, symbol string) (*NetPosition, error) {
+	positions, err := b.GetPositions(ctx, &PositionFilter{Symbol: symbol})
+	if err != nil {
+		return nil, err
+	}
+	net := Net(symbol, positions)
+	return &net, nil
+}
+
+// FlattenPosition closes every leg of symbol with one reduce-only market
+// order per open leg and returns the orders placed. Legs that fail to close
+// are reported in the joined error; the other legs are still closed.
+func FlattenPosition(ctx context.Context, b Broker, symbol string) ([]*Order, error) {
+	net, err := GetNetPosition(ctx, b, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	var orders []*Order
+	var errs []error
+	for _, leg := range []*Position{net.Long, net.Short} {
+		if leg == nil {
+			continue
+		}
+
+		closeSide := SideShort
+		if leg.Side == SideShort {
+			closeSide = SideLong
+		}
+		order, err := b.PlaceOrder(ctx, &OrderRequest{
+			Symbol:     symbol,
+			Side:       closeSide,
+			Type:       OrderTypeMarket,
+			Size:       leg.Size,
+			ReduceOnly: true,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("closing %s %s leg: %w", symbol, leg.Side, err))
+			continue
+		}
+		orders = append(orders, order)
+	}
+	return orders, errors.Join(errs...)
+}
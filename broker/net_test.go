@@ -0,0 +1,72 @@
+package broker_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestNet(t *testing.T) {
+	positions := []*broker.Position{
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.5, MarkPrice: 50000, UnrealizedPnL: 100, Margin: 2500},
+		{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 0.2, MarkPrice: 50000, UnrealizedPnL: -40, RealizedPnL: 5, Margin: 1000},
+		{Symbol: "ETH-USDT", Side: broker.SideShort, Size: 3, MarkPrice: 3000},
+	}
+
+	net := broker.Net("BTC-USDT", positions)
+	if net.NetSize != 0.3 || net.GrossSize != 0.7 || net.Side() != broker.SideLong || !net.Hedged() {
+		t.Errorf("Net() = %+v, want net long 0.3 of 0.7 gross, hedged", net)
+	}
+	if net.Notional != 15000 || net.UnrealizedPnL != 60 || net.RealizedPnL != 5 || net.Margin != 3500 {
+		t.Errorf("Net() notional %v PnL %v/%v margin %v, want 15000 60/5 3500", net.Notional, net.UnrealizedPnL, net.RealizedPnL, net.Margin)
+	}
+
+	all := broker.NetAll(positions)
+	if len(all) != 2 || all[1].Symbol != "ETH-USDT" || all[1].NetSize != -3 || all[1].Side() != broker.SideShort || all[1].Hedged() {
+		t.Errorf("NetAll() = %+v", all)
+	}
+
+	if flat := broker.Net("SOL-USDT", positions); flat.Side() != "" || flat.Long != nil || flat.Short != nil {
+		t.Errorf("Net() of unknown symbol = %+v, want flat", flat)
+	}
+}
+
+func TestFlattenPosition(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.5, EntryPrice: 48000})
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 0.2, EntryPrice: 51000})
+	b.SetPosition(broker.Position{Symbol: "ETH-USDT", Side: broker.SideLong, Size: 1, EntryPrice: 3000})
+	ctx := context.Background()
+
+	orders, err := broker.FlattenPosition(ctx, b, "BTC-USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 2 {
+		t.Fatalf("orders = %d, want one per leg", len(orders))
+	}
+	for _, req := range b.PlacedOrders() {
+		if !req.ReduceOnly || req.Type != broker.OrderTypeMarket {
+			t.Errorf("order %+v, want reduce-only market", req)
+		}
+	}
+
+	net, err := broker.GetNetPosition(ctx, b, "BTC-USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if net.GrossSize != 0 {
+		t.Errorf("gross size after flatten = %v, want 0", net.GrossSize)
+	}
+	if eth, _ := b.GetPosition(ctx, "ETH-USDT"); eth == nil || eth.Size != 1 {
+		t.Error("other symbols must be left untouched")
+	}
+
+	// Flattening a flat symbol places nothing
+	if orders, err := broker.FlattenPosition(ctx, b, "BTC-USDT"); err != nil || len(orders) != 0 {
+		t.Errorf("FlattenPosition() on flat symbol = %d orders, %v", len(orders), err)
+	}
+}
@@ -0,0 +1,51 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// OrderOptions carries order parameters beyond the shared OrderRequest.
+// Attach them with WithOrderOptions; brokers that don't support an option
+// ignore it.
+type OrderOptions struct {
+	// WorkingType is the price that fires a stop, take-profit or trigger
+	// order (default mark price)
+	WorkingType WorkingType
+	// PriceProtect keeps trigger orders from firing while the mark and last
+	// prices diverge abnormally
+	PriceProtect bool
+	// ExpireTime is when a TimeInForceGTD order is canceled by the exchange
+	ExpireTime time.Time
+	// SelfTradePrevention keeps the order from trading against the
+	// account's own orders
+	SelfTradePrevention STPMode
+	// ClientOrderID is the exchange's client order ID for the order,
+	// returned as Order.ClientOrderID (see NewClientOrderID). It applies
+	// only to requests without their own OrderRequest.ClientOrderID, so
+	// decorators such as ClientOrderIDs.WithTag can supply a default.
+	ClientOrderID string
+}
+
+type orderOptionsKey struct{}
+
+// WithOrderOptions returns a context that makes PlaceOrder apply opts. The
+// options travel through decorators unchanged.
+func WithOrderOptions(ctx context.Context, opts OrderOptions) context.Context {
+	return context.WithValue(ctx, orderOptionsKey{}, opts)
+}
+
+// OrderOptionsFrom returns the options attached to ctx, or the zero value
+func OrderOptionsFrom(ctx context.Context) OrderOptions {
+	opts, _ := ctx.Value(orderOptionsKey{}).(OrderOptions)
+	return opts
+}
+
+// ClientOrderIDOf returns the client order ID req is placed with: its own
+// ClientOrderID, or else the one in the options attached to ctx
+func ClientOrderIDOf(ctx context.Context, req *OrderRequest) string {
+	if req.ClientOrderID != "" {
+		return req.ClientOrderID
+	}
+	return OrderOptionsFrom(ctx).ClientOrderID
+}
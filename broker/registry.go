@@ -0,0 +1,83 @@
+package broker
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Config carries the settings a Factory needs to construct a broker
+type Config struct {
+	APIKey     string
+	SecretKey  string
+	Passphrase string
+	// Environment selects production or testnet (default production)
+	Environment Environment
+	// Demo selects the testnet when Environment is unset.
+	//
+	// Deprecated: use Environment.
+	Demo      bool
+	BaseURL   string
+	StreamURL string
+	Options   map[string]string // Adapter-specific settings
+}
+
+// Factory constructs a broker from configuration
+type Factory func(cfg Config) (Broker, error)
+
+var (
+	factoriesMu sync.RWMutex
+	factories   = map[string]Factory{}
+)
+
+// Register makes a broker adapter available to Open by name. Adapters call
+// it from an init function, so importing the adapter package (even as
+// `import _ ".../bingx"`) is enough to register it. It panics if factory is
+// nil or name is already registered.
+func Register(name string, factory Factory) {
+	factoriesMu.Lock()
+	defer factoriesMu.Unlock()
+
+	if factory == nil {
+		panic("broker: Register factory is nil")
+	}
+	if _, dup := factories[name]; dup {
+		panic("broker: Register called twice for " + name)
+	}
+	factories[name] = factory
+}
+
+// Open constructs the broker registered under name. Non-production
+// environments fail with ErrNotSupported unless the broker lists them in
+// Features.Environments.
+func Open(name string, cfg Config) (Broker, error) {
+	factoriesMu.RLock()
+	factory, ok := factories[name]
+	factoriesMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("%w: %q (forgotten import?)", ErrUnknownBroker, name)
+	}
+
+	b, err := factory(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if env := cfg.Env(); env != EnvironmentProduction && !b.SupportedFeatures().SupportsEnvironment(env) {
+		return nil, NewBrokerError(name, "UNSUPPORTED_ENVIRONMENT", fmt.Sprintf("No %s environment", env), ErrNotSupported)
+	}
+	return b, nil
+}
+
+// Registered returns the sorted names of registered brokers
+func Registered() []string {
+	factoriesMu.RLock()
+	defer factoriesMu.RUnlock()
+
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,88 @@
+package broker
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+type namedBroker struct {
+	Broker
+	name string
+}
+
+func (b namedBroker) Name() string { return b.name }
+
+// register registers factory under name for the duration of the test, so
+// tests can run more than once in a process (go test -count)
+func register(t *testing.T, name string, factory Factory) {
+	t.Helper()
+	Register(name, factory)
+	t.Cleanup(func() {
+		factoriesMu.Lock()
+		defer factoriesMu.Unlock()
+		delete(factories, name)
+	})
+}
+
+func TestRegistry(t *testing.T) {
+	register(t, "registry-test", func(cfg Config) (Broker, error) {
+		if cfg.APIKey == "" {
+			return nil, ErrAuthFailed
+		}
+		return namedBroker{name: cfg.Options["label"]}, nil
+	})
+
+	b, err := Open("registry-test", Config{APIKey: "k", Options: map[string]string{"label": "primary"}})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	if b.Name() != "primary" {
+		t.Errorf("Name() = %q, want primary", b.Name())
+	}
+
+	if _, err := Open("registry-test", Config{}); !errors.Is(err, ErrAuthFailed) {
+		t.Errorf("Open() error = %v, want factory error", err)
+	}
+	if _, err := Open("missing", Config{}); !errors.Is(err, ErrUnknownBroker) {
+		t.Errorf("Open(missing) error = %v, want ErrUnknownBroker", err)
+	}
+	if !slices.Contains(Registered(), "registry-test") {
+		t.Errorf("Registered() = %v, missing registry-test", Registered())
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("duplicate Register did not panic")
+		}
+	}()
+	Register("registry-test", func(Config) (Broker, error) { return nil, nil })
+}
+
+type productionOnly struct{ Broker }
+
+func (productionOnly) SupportedFeatures() Features { return Features{MaxLeverage: 20} }
+
+func TestOpen_Environment(t *testing.T) {
+	register(t, "production-only-test", func(Config) (Broker, error) { return productionOnly{}, nil })
+
+	if _, err := Open("production-only-test", Config{}); err != nil {
+		t.Errorf("Open(production) error = %v", err)
+	}
+	for _, cfg := range []Config{{Environment: EnvironmentTestnet}, {Demo: true}} {
+		if _, err := Open("production-only-test", cfg); !errors.Is(err, ErrNotSupported) {
+			t.Errorf("Open(%+v) error = %v, want ErrNotSupported", cfg, err)
+		}
+	}
+}
+
+func TestParseEnvironment(t *testing.T) {
+	for input, want := range map[string]Environment{"": EnvironmentProduction, "Live": EnvironmentProduction, "demo": EnvironmentTestnet, " testnet ": EnvironmentTestnet} {
+		if got, err := ParseEnvironment(input); err != nil || got != want {
+			t.Errorf("ParseEnvironment(%q) = %q, %v, want %q", input, got, err, want)
+		}
+	}
+	if _, err := ParseEnvironment("staging"); err == nil {
+		t.Error("ParseEnvironment(staging) succeeded")
+	}
+}
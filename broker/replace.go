@@ -0,0 +1,132 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// OrderReplacer is implemented by brokers with an atomic cancel-replace
+// endpoint. ReplaceOrder must not place the new order when the old one
+// could not be canceled, and then fails with ErrOrderNotFound.
+type OrderReplacer interface {
+	ReplaceOrder(ctx context.Context, symbol, orderID string, req *OrderRequest) (*Order, error)
+}
+
+// GonePolicy decides what ReplaceOrder does when the order to replace has
+// already filled or been canceled
+type GonePolicy int
+
+const (
+	// GoneAbort returns ErrOrderNotFound without placing the new order, so a
+	// filled entry is not doubled
+	GoneAbort GonePolicy = iota
+	// GonePlace places the new order anyway
+	GonePlace
+)
+
+// ReplaceOptions configures ReplaceOrder
+type ReplaceOptions struct {
+	IfGone GonePolicy
+}
+
+// ReplaceError reports a replace whose cancel succeeded but whose new order
+// failed: the old order is gone and nothing replaced it
+type ReplaceError struct {
+	OrderID string
+	Err     error
+}
+
+func (e *ReplaceError) Error() string {
+	return fmt.Sprintf("order %s canceled but replacement failed: %v", e.OrderID, e.Err)
+}
+
+func (e *ReplaceError) Unwrap() error {
+	return e.Err
+}
+
+// ReplaceOrder cancels orderID and places req in its place. It uses the
+// broker's OrderReplacer when there is one and otherwise cancels, checks
+// the order is really gone from the open orders, then places req.
+func ReplaceOrder(ctx context.Context, b Broker, symbol, orderID string, req *OrderRequest, opts ReplaceOptions) (*Order, error) {
+	if r, ok := b.(OrderReplacer); ok {
+		order, err := r.ReplaceOrder(ctx, symbol, orderID, req)
+		if errors.Is(err, ErrOrderNotFound) && opts.IfGone == GonePlace {
+			return b.PlaceOrder(ctx, req)
+		}
+		return order, err
+	}
+
+	if err := b.CancelOrder(ctx, symbol, orderID); err != nil {
+		// A failed cancel is only safe to act on once the order is known to
+		// be gone (filled or already canceled)
+		orders, listErr := b.GetOrders(ctx, &OrderFilter{Symbol: symbol})
+		if listErr != nil {
+			return nil, errors.Join(err, listErr)
+		}
+		if slices.ContainsFunc(orders, func(o *Order) bool { return o.ID == orderID }) {
+			return nil, err
+		}
+		if opts.IfGone == GoneAbort {
+			return nil, fmt.Errorf("%w: %s is no longer open: %v", ErrOrderNotFound, orderID, err)
+		}
+	}
+
+	order, err := b.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, &ReplaceError{OrderID: orderID, Err: err}
+	}
+	return order, nil
+}
+
+// Replacement is one cancel-replace in a batch: OrderID is canceled and
+// Order placed in its place
+type Replacement struct {
+	OrderID string
+	Order   *OrderRequest
+}
+
+// ReplaceResult is the outcome of one Replacement. Err follows
+// ReplaceOrder: ErrOrderNotFound when the old order was gone and nothing was
+// placed, *ReplaceError when it was canceled but the new order failed.
+type ReplaceResult struct {
+	Order *Order
+	Err   error
+}
+
+// BatchReplacer is implemented by brokers that can cancel-replace several
+// orders of a symbol in one request. Results are in the order of
+// replacements; the error is for failures of the whole request.
+type BatchReplacer interface {
+	ReplaceOrders(ctx context.Context, symbol string, replacements []Replacement) ([]ReplaceResult, error)
+}
+
+// ReplaceOrders replaces several orders of a symbol, in one request when
+// the broker is a BatchReplacer and otherwise one ReplaceOrder at a time.
+// opts.IfGone applies to each replacement.
+func ReplaceOrders(ctx context.Context, b Broker, symbol string, replacements []Replacement, opts ReplaceOptions) ([]ReplaceResult, error) {
+	if len(replacements) == 0 {
+		return nil, nil
+	}
+	if r, ok := b.(BatchReplacer); ok {
+		results, err := r.ReplaceOrders(ctx, symbol, replacements)
+		if err != nil {
+			return nil, err
+		}
+		if opts.IfGone == GonePlace {
+			for i, result := range results {
+				if errors.Is(result.Err, ErrOrderNotFound) {
+					results[i].Order, results[i].Err = b.PlaceOrder(ctx, replacements[i].Order)
+				}
+			}
+		}
+		return results, nil
+	}
+
+	results := make([]ReplaceResult, len(replacements))
+	for i, r := range replacements {
+		results[i].Order, results[i].Err = ReplaceOrder(ctx, b, symbol, r.OrderID, r.Order, opts)
+	}
+	return results, nil
+}
@@ -0,0 +1,66 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestReplaceOrder(t *testing.T) {
+	b := brokertest.New()
+	old := b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 45000})
+	req := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 45500}
+	ctx := context.Background()
+
+	order, err := broker.ReplaceOrder(ctx, b, "BTC-USDT", old.ID, req, broker.ReplaceOptions{})
+	if err != nil {
+		t.Fatalf("ReplaceOrder() error = %v", err)
+	}
+	if orders, _ := b.GetOrders(ctx, nil); len(orders) != 1 || orders[0].ID != order.ID || orders[0].Price != 45500 {
+		t.Errorf("open orders = %+v, want only the replacement", orders)
+	}
+
+	// The old order already filled: abort by default, place on request
+	if _, err := broker.ReplaceOrder(ctx, b, "BTC-USDT", "filled", req, broker.ReplaceOptions{}); !errors.Is(err, broker.ErrOrderNotFound) {
+		t.Errorf("ReplaceOrder(gone) error = %v, want ErrOrderNotFound", err)
+	}
+	if len(b.PlacedOrders()) != 1 {
+		t.Errorf("placed %d orders, want no placement after an aborted replace", len(b.PlacedOrders()))
+	}
+	if _, err := broker.ReplaceOrder(ctx, b, "BTC-USDT", "filled", req, broker.ReplaceOptions{IfGone: broker.GonePlace}); err != nil {
+		t.Errorf("ReplaceOrder(gone, GonePlace) error = %v", err)
+	}
+
+	// Cancel succeeds, placement fails
+	bad := *req
+	bad.Size = 0
+	var replaceErr *broker.ReplaceError
+	if _, err := broker.ReplaceOrder(ctx, b, "BTC-USDT", order.ID, &bad, broker.ReplaceOptions{}); !errors.As(err, &replaceErr) || replaceErr.OrderID != order.ID {
+		t.Errorf("ReplaceOrder(bad request) error = %v, want ReplaceError", err)
+	}
+}
+
+func TestReplaceOrders(t *testing.T) {
+	b := brokertest.New()
+	bid := b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 44900})
+	ask := b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeLimit, Size: 1, Price: 45100})
+	quote := func(side broker.Side, price float64) *broker.OrderRequest {
+		return &broker.OrderRequest{Symbol: "BTC-USDT", Side: side, Type: broker.OrderTypeLimit, Size: 1, Price: price}
+	}
+
+	results, err := broker.ReplaceOrders(context.Background(), b, "BTC-USDT", []broker.Replacement{
+		{OrderID: bid.ID, Order: quote(broker.SideLong, 44950)},
+		{OrderID: "filled", Order: quote(broker.SideShort, 45150)},
+		{OrderID: ask.ID, Order: quote(broker.SideShort, 45150)},
+	}, broker.ReplaceOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 3 || results[0].Err != nil || results[0].Order.Price != 44950 ||
+		!errors.Is(results[1].Err, broker.ErrOrderNotFound) || results[2].Err != nil {
+		t.Errorf("results = %+v, want the gone order reported and the others replaced", results)
+	}
+}
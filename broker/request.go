@@ -0,0 +1,58 @@
+package broker
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+)
+
+// HeaderRequestID carries the request ID of WithRequestID on outgoing
+// requests
+const HeaderRequestID = "X-Request-ID"
+
+type requestIDKey struct{}
+
+type headersKey struct{}
+
+// WithRequestID returns a context whose requests carry id, so a call can
+// be traced through logs, errors and exchange support tickets. Adapters
+// send it in the X-Request-ID header and set BrokerError.RequestID on the
+// errors they return.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFrom returns the request ID attached to ctx, or ""
+func RequestIDFrom(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID returns a random 16-character request ID
+func NewRequestID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithHeaders returns a context adding header to every request made with
+// it. Headers attached by an outer context are kept unless header sets the
+// same name. Adapters' own headers (API keys, content type) take
+// precedence.
+func WithHeaders(ctx context.Context, header http.Header) context.Context {
+	merged := HeadersFrom(ctx)
+	if merged == nil {
+		merged = make(http.Header, len(header))
+	}
+	for name, values := range header {
+		merged[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	return context.WithValue(ctx, headersKey{}, merged)
+}
+
+// HeadersFrom returns a copy of the headers attached to ctx, or nil
+func HeadersFrom(ctx context.Context) http.Header {
+	header, _ := ctx.Value(headersKey{}).(http.Header)
+	return header.Clone()
+}
@@ -0,0 +1,37 @@
+package broker
+
+import (
+	"context"
+	"net/http"
+	"testing"
+)
+
+func TestWithHeaders(t *testing.T) {
+	ctx := WithHeaders(context.Background(), http.Header{"X-Trace": {"outer"}, "X-Tenant": {"desk-1"}})
+	ctx = WithHeaders(ctx, http.Header{"x-trace": {"inner"}})
+
+	got := HeadersFrom(ctx)
+	if got.Get("X-Trace") != "inner" || got.Get("X-Tenant") != "desk-1" {
+		t.Errorf("HeadersFrom() = %v, want inner trace and the outer tenant", got)
+	}
+	got.Set("X-Tenant", "changed")
+	if HeadersFrom(ctx).Get("X-Tenant") != "desk-1" {
+		t.Error("HeadersFrom() returned the context's own header map")
+	}
+	if HeadersFrom(context.Background()) != nil {
+		t.Error("HeadersFrom() without headers should be nil")
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	if id := RequestIDFrom(context.Background()); id != "" {
+		t.Errorf("RequestIDFrom() = %q, want empty", id)
+	}
+	id := NewRequestID()
+	if len(id) != 16 || id == NewRequestID() {
+		t.Errorf("NewRequestID() = %q, want 16 random hex characters", id)
+	}
+	if got := RequestIDFrom(WithRequestID(context.Background(), id)); got != id {
+		t.Errorf("RequestIDFrom() = %q, want %q", got, id)
+	}
+}
@@ -0,0 +1,19 @@
+package broker
+
+import "time"
+
+// ExchangeStatus is a point-in-time health report of a broker connection
+type ExchangeStatus struct {
+	Latency     time.Duration // Round trip of the status request
+	ServerTime  time.Time
+	ClockDrift  time.Duration // Server clock minus local clock
+	Maintenance bool          // Exchange reports scheduled or ongoing maintenance
+	CheckedAt   time.Time
+}
+
+// Healthy reports whether orders can be routed: not in maintenance and the
+// clock drift stays within maxDrift (exchanges reject requests whose
+// timestamps drift too far)
+func (s *ExchangeStatus) Healthy(maxDrift time.Duration) bool {
+	return !s.Maintenance && s.ClockDrift.Abs() <= maxDrift
+}
@@ -0,0 +1,32 @@
+package broker
+
+import (
+	"fmt"
+	"slices"
+)
+
+// STPMode selects what the exchange does when an order would trade against
+// another order of the same account, e.g. when several strategies share it
+type STPMode string
+
+const (
+	STPNone        STPMode = ""             // Exchange default
+	STPExpireTaker STPMode = "EXPIRE_TAKER" // Cancel the incoming order
+	STPExpireMaker STPMode = "EXPIRE_MAKER" // Cancel the resting order
+	STPExpireBoth  STPMode = "EXPIRE_BOTH"  // Cancel both orders
+)
+
+// SupportsSTP reports whether the broker accepts mode. STPNone is always
+// accepted.
+func (f Features) SupportsSTP(mode STPMode) bool {
+	return mode == STPNone || slices.Contains(f.STPModes, mode)
+}
+
+// CheckSTP rejects self-trade prevention modes the broker doesn't support
+// with ErrNotSupported, before the order reaches the exchange
+func CheckSTP(f Features, opts OrderOptions) error {
+	if !f.SupportsSTP(opts.SelfTradePrevention) {
+		return fmt.Errorf("%w: self-trade prevention %s", ErrNotSupported, opts.SelfTradePrevention)
+	}
+	return nil
+}
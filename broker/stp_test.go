@@ -0,0 +1,19 @@
+package broker
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckSTP(t *testing.T) {
+	f := Features{STPModes: []STPMode{STPExpireTaker}}
+	if err := CheckSTP(Features{}, OrderOptions{}); err != nil {
+		t.Errorf("default mode: error = %v", err)
+	}
+	if err := CheckSTP(f, OrderOptions{SelfTradePrevention: STPExpireTaker}); err != nil {
+		t.Errorf("supported mode: error = %v", err)
+	}
+	if err := CheckSTP(f, OrderOptions{SelfTradePrevention: STPExpireBoth}); !errors.Is(err, ErrNotSupported) {
+		t.Errorf("unsupported mode: error = %v, want ErrNotSupported", err)
+	}
+}
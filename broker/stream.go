@@ -0,0 +1,51 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// Trade is a public trade print
+type Trade struct {
+	Symbol string
+	ID     string
+	Price  float64
+	Size   float64
+	Side   Side // Aggressor side: SideLong for taker buys, SideShort for taker sells
+	Time   time.Time
+}
+
+// Notional returns the trade value in quote currency
+func (t Trade) Notional() float64 {
+	return t.Price * t.Size
+}
+
+// TradeStreamer is implemented by brokers with a public trade feed
+type TradeStreamer interface {
+	// StreamTrades calls handler for every trade on symbol until the context
+	// is canceled or the connection fails. Handlers run on the stream
+	// goroutine and should return quickly.
+	StreamTrades(ctx context.Context, symbol string, handler func(Trade)) error
+}
+
+// Level is one price level of an order book
+type Level struct {
+	Price float64
+	Size  float64
+}
+
+// Depth is the top of an order book
+type Depth struct {
+	Symbol string
+	Bids   []Level // Best (highest) first
+	Asks   []Level // Best (lowest) first
+	Time   time.Time
+}
+
+// DepthStreamer is implemented by brokers with a public order book feed
+type DepthStreamer interface {
+	// StreamDepth calls handler with at least the top levels of the symbol's
+	// book on every update until the context is canceled or the connection
+	// fails. Handlers run on the stream goroutine and should return quickly.
+	StreamDepth(ctx context.Context, symbol string, levels int, handler func(Depth)) error
+}
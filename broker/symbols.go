@@ -0,0 +1,62 @@
+package broker
+
+import (
+	"context"
+	"time"
+)
+
+// SymbolStatus is the listing state of a contract
+type SymbolStatus string
+
+const (
+	SymbolTrading    SymbolStatus = "TRADING"
+	SymbolPending    SymbolStatus = "PENDING"     // Announced, not yet open for trading
+	SymbolReduceOnly SymbolStatus = "REDUCE_ONLY" // Only orders closing positions accepted
+	SymbolHalted     SymbolStatus = "HALTED"      // Listed but not accepting orders
+	SymbolDelisting  SymbolStatus = "DELISTING"   // Trading until DelistTime
+)
+
+// SymbolInfo describes a listed contract
+type SymbolInfo struct {
+	Symbol     string
+	BaseAsset  string
+	QuoteAsset string
+	Status     SymbolStatus
+	LaunchTime time.Time
+	DelistTime time.Time // Zero unless a delisting is scheduled
+	Volume24h  float64   // Traded value over the last 24h, in quote asset
+}
+
+// SymbolFilter selects symbols in ListSymbols. Zero fields match
+// everything.
+type SymbolFilter struct {
+	Quote            string       // Quote asset, e.g. "USDT"
+	Status           SymbolStatus // Only symbols in this status
+	MinVolume24h     float64      // Minimum 24h traded value in quote asset
+	ExcludeDelisting bool         // Skip symbols with a scheduled delisting
+}
+
+// Match reports whether s passes the filter. A nil filter matches
+// everything.
+func (f *SymbolFilter) Match(s SymbolInfo) bool {
+	if f == nil {
+		return true
+	}
+	switch {
+	case f.Quote != "" && s.QuoteAsset != f.Quote:
+		return false
+	case f.Status != "" && s.Status != f.Status:
+		return false
+	case s.Volume24h < f.MinVolume24h:
+		return false
+	case f.ExcludeDelisting && (s.Status == SymbolDelisting || !s.DelistTime.IsZero()):
+		return false
+	}
+	return true
+}
+
+// SymbolLister is implemented by brokers that can list their tradable
+// contracts, e.g. for market scanners
+type SymbolLister interface {
+	ListSymbols(ctx context.Context, filter *SymbolFilter) ([]SymbolInfo, error)
+}
@@ -0,0 +1,49 @@
+package broker
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// SupportsTimeInForce reports whether the broker accepts tif. An empty tif
+// (broker default) is always accepted; a broker that lists no values
+// accepts GTC, IOC and FOK.
+func (f Features) SupportsTimeInForce(tif TimeInForce) bool {
+	if tif == "" {
+		return true
+	}
+	if len(f.TimeInForces) == 0 {
+		return tif == TimeInForceGTC || tif == TimeInForceIOC || tif == TimeInForceFOK
+	}
+	return slices.Contains(f.TimeInForces, tif)
+}
+
+// CheckTimeInForce rejects time-in-force combinations locally, before they
+// reach the exchange: values the broker doesn't support (ErrNotSupported),
+// post-only orders without a limit price (ErrInvalidPrice) and GTD orders
+// without a future expiry or expiries on other orders (ErrInvalidExpiry).
+func CheckTimeInForce(f Features, req *OrderRequest, opts OrderOptions) error {
+	if !f.SupportsTimeInForce(req.TimeInForce) {
+		return fmt.Errorf("%w: time in force %s", ErrNotSupported, req.TimeInForce)
+	}
+
+	switch req.TimeInForce {
+	case TimeInForcePostOnly:
+		if req.Price <= 0 {
+			return fmt.Errorf("%w: post-only orders need a limit price", ErrInvalidPrice)
+		}
+	case TimeInForceGTD:
+		if opts.ExpireTime.IsZero() {
+			return fmt.Errorf("%w: GTD orders need an expire time", ErrInvalidExpiry)
+		}
+		if !opts.ExpireTime.After(time.Now()) {
+			return fmt.Errorf("%w: expire time %s has passed", ErrInvalidExpiry, opts.ExpireTime.UTC().Format(time.RFC3339))
+		}
+	default:
+		if !opts.ExpireTime.IsZero() {
+			return fmt.Errorf("%w: expire time needs time in force GTD", ErrInvalidExpiry)
+		}
+	}
+	return nil
+}
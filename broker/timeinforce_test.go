@@ -0,0 +1,43 @@
+package broker
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckTimeInForce(t *testing.T) {
+	postOnly := Features{TimeInForces: []TimeInForce{TimeInForceGTC, TimeInForcePostOnly}}
+	all := Features{TimeInForces: []TimeInForce{TimeInForceGTC, TimeInForcePostOnly, TimeInForceGTD}}
+	limit := func(tif TimeInForce) *OrderRequest {
+		return &OrderRequest{Symbol: "BTC-USDT", Type: OrderTypeLimit, Size: 1, Price: 50000, TimeInForce: tif}
+	}
+	later := OrderOptions{ExpireTime: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name     string
+		features Features
+		req      *OrderRequest
+		opts     OrderOptions
+		want     error
+	}{
+		{"Default", Features{}, limit(""), OrderOptions{}, nil},
+		{"Shared set by default", Features{}, limit(TimeInForceIOC), OrderOptions{}, nil},
+		{"Post-only unsupported", Features{}, limit(TimeInForcePostOnly), OrderOptions{}, ErrNotSupported},
+		{"Post-only", postOnly, limit(TimeInForcePostOnly), OrderOptions{}, nil},
+		{"Post-only market", postOnly, &OrderRequest{Type: OrderTypeMarket, Size: 1, TimeInForce: TimeInForcePostOnly}, OrderOptions{}, ErrInvalidPrice},
+		{"GTD unsupported", postOnly, limit(TimeInForceGTD), later, ErrNotSupported},
+		{"GTD", all, limit(TimeInForceGTD), later, nil},
+		{"GTD without expiry", all, limit(TimeInForceGTD), OrderOptions{}, ErrInvalidExpiry},
+		{"GTD expired", all, limit(TimeInForceGTD), OrderOptions{ExpireTime: time.Now().Add(-time.Minute)}, ErrInvalidExpiry},
+		{"Expiry without GTD", all, limit(TimeInForceGTC), later, ErrInvalidExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckTimeInForce(tt.features, tt.req, tt.opts)
+			if tt.want == nil && err != nil || tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("CheckTimeInForce() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -47,3 +47,26 @@ const (
 	WorkingTypeMark = types.WorkingTypeMark
 	WorkingTypeLast = types.WorkingTypeLast
 )
+
+// Order types beyond the shared set. Exchanges that distinguish market and
+// limit variants of trigger orders (or conditional entries) map these
+// explicitly; others may reject them as unsupported.
+const (
+	OrderTypeStopMarket       OrderType = "STOP_MARKET"
+	OrderTypeTakeProfitMarket OrderType = "TAKE_PROFIT_MARKET"
+	OrderTypeTriggerLimit     OrderType = "TRIGGER_LIMIT"  // Conditional limit entry
+	OrderTypeTriggerMarket    OrderType = "TRIGGER_MARKET" // Conditional market entry
+)
+
+// Time-in-force values beyond the shared set. Brokers list the ones they
+// accept in Features.TimeInForces.
+const (
+	TimeInForcePostOnly TimeInForce = "POST_ONLY" // Rejected rather than taking liquidity
+	TimeInForceGTD      TimeInForce = "GTD"       // Rests until OrderOptions.ExpireTime
+)
+
+// WorkingTypeIndex fires trigger orders on the index price
+const WorkingTypeIndex WorkingType = "INDEX_PRICE"
+
+// OrderStatusPending marks trigger orders resting until their trigger price is hit
+const OrderStatusPending OrderStatus = "PENDING"
@@ -0,0 +1,69 @@
+package broker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// OrderValidator is implemented by brokers with a test-order endpoint, which
+// checks an order against margin, filters and leverage without executing it
+type OrderValidator interface {
+	ValidateOrder(ctx context.Context, req *OrderRequest) error
+}
+
+// ValidateOrder reports whether b would accept req, without placing it.
+// Brokers implementing OrderValidator check it on the exchange; for the
+// rest, and validators returning ErrNotSupported, only the local checks of
+// CheckOrder run, so margin and exchange filters are not verified.
+func ValidateOrder(ctx context.Context, b Broker, req *OrderRequest) error {
+	if err := CheckOrder(b.SupportedFeatures(), req, OrderOptionsFrom(ctx)); err != nil {
+		return err
+	}
+	if v, ok := b.(OrderValidator); ok {
+		if err := v.ValidateOrder(ctx, req); !errors.Is(err, ErrNotSupported) {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckOrder validates an order request locally: a symbol and positive size
+// (ErrInvalidSymbol, ErrInvalidQuantity), the prices its type needs
+// (ErrInvalidPrice), features the broker lacks (ErrNotSupported), and the
+// CheckTimeInForce and CheckSTP rules.
+func CheckOrder(f Features, req *OrderRequest, opts OrderOptions) error {
+	switch {
+	case req.Symbol == "":
+		return fmt.Errorf("%w: symbol is required", ErrInvalidSymbol)
+	case req.Side != SideLong && req.Side != SideShort:
+		return fmt.Errorf("%w: side %q", ErrNotSupported, req.Side)
+	case req.Size <= 0:
+		return fmt.Errorf("%w: size %v", ErrInvalidQuantity, req.Size)
+	case req.Price < 0 || req.StopPrice < 0:
+		return fmt.Errorf("%w: negative price", ErrInvalidPrice)
+	case req.ReduceOnly && !f.ReduceOnlyOrders:
+		return fmt.Errorf("%w: reduce-only orders", ErrNotSupported)
+	case req.Trailing != nil && !f.TrailingStop:
+		return fmt.Errorf("%w: trailing stops", ErrNotSupported)
+	}
+
+	switch req.Type {
+	case OrderTypeLimit, OrderTypeTriggerLimit:
+		if req.Price <= 0 {
+			return fmt.Errorf("%w: %s orders need a limit price", ErrInvalidPrice, req.Type)
+		}
+	}
+	switch req.Type {
+	case OrderTypeStop, OrderTypeTakeProfit, OrderTypeStopMarket, OrderTypeTakeProfitMarket,
+		OrderTypeTriggerLimit, OrderTypeTriggerMarket:
+		if req.StopPrice <= 0 {
+			return fmt.Errorf("%w: %s orders need a trigger price", ErrInvalidPrice, req.Type)
+		}
+	}
+
+	if err := CheckTimeInForce(f, req, opts); err != nil {
+		return err
+	}
+	return CheckSTP(f, opts)
+}
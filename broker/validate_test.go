@@ -0,0 +1,83 @@
+package broker_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// testOrderBroker has a test-order endpoint answering err
+type testOrderBroker struct {
+	*brokertest.Broker
+	err   error
+	calls int
+}
+
+func (b *testOrderBroker) ValidateOrder(ctx context.Context, req *broker.OrderRequest) error {
+	b.calls++
+	return b.err
+}
+
+func TestCheckOrder(t *testing.T) {
+	f := brokertest.New().SupportedFeatures()
+	limit := broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 45000}
+	tests := []struct {
+		name   string
+		modify func(*broker.OrderRequest)
+		want   error
+	}{
+		{"valid", func(r *broker.OrderRequest) {}, nil},
+		{"no symbol", func(r *broker.OrderRequest) { r.Symbol = "" }, broker.ErrInvalidSymbol},
+		{"no size", func(r *broker.OrderRequest) { r.Size = 0 }, broker.ErrInvalidQuantity},
+		{"bad side", func(r *broker.OrderRequest) { r.Side = "BUY" }, broker.ErrNotSupported},
+		{"limit without price", func(r *broker.OrderRequest) { r.Price = 0 }, broker.ErrInvalidPrice},
+		{"stop without trigger", func(r *broker.OrderRequest) { r.Type = broker.OrderTypeStop }, broker.ErrInvalidPrice},
+		{"stop with trigger", func(r *broker.OrderRequest) { r.Type, r.StopPrice = broker.OrderTypeStop, 44000 }, nil},
+		{"market", func(r *broker.OrderRequest) { r.Type, r.Price = broker.OrderTypeMarket, 0 }, nil},
+		{"GTD without expiry", func(r *broker.OrderRequest) { r.TimeInForce = broker.TimeInForceGTD }, broker.ErrInvalidExpiry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := limit
+			tt.modify(&req)
+			if err := broker.CheckOrder(f, &req, broker.OrderOptions{}); !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("CheckOrder() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if err := broker.CheckOrder(broker.Features{}, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeMarket, Size: 1, ReduceOnly: true}, broker.OrderOptions{}); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("CheckOrder(reduce-only, unsupported) = %v, want ErrNotSupported", err)
+	}
+}
+
+func TestValidateOrder(t *testing.T) {
+	ctx := context.Background()
+	req := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1}
+	rejected := broker.NewBrokerError("test", "API_101204", "Insufficient margin", nil)
+
+	b := &testOrderBroker{Broker: brokertest.New(), err: rejected}
+	if err := broker.ValidateOrder(ctx, b, req); !errors.Is(err, rejected) || b.calls != 1 {
+		t.Errorf("ValidateOrder() = %v after %d calls, want the exchange rejection", err, b.calls)
+	}
+	if len(b.PlacedOrders()) != 0 {
+		t.Error("ValidateOrder() placed an order")
+	}
+
+	// Locally invalid orders never reach the exchange
+	if err := broker.ValidateOrder(ctx, b, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket}); !errors.Is(err, broker.ErrInvalidQuantity) || b.calls != 1 {
+		t.Errorf("ValidateOrder(no size) = %v after %d calls", err, b.calls)
+	}
+
+	// Without a test endpoint only the local checks run
+	b.err = broker.ErrNotSupported
+	if err := broker.ValidateOrder(ctx, b, req); err != nil {
+		t.Errorf("ValidateOrder(unsupported endpoint) = %v", err)
+	}
+	if err := broker.ValidateOrder(ctx, brokertest.New(), req); err != nil {
+		t.Errorf("ValidateOrder(no validator) = %v", err)
+	}
+}
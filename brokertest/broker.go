@@ -0,0 +1,536 @@
+// Package brokertest provides an in-memory broker.Broker for testing
+// components built on top of the broker interface.
+package brokertest
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/clock"
+)
+
+// Broker is an in-memory broker.Broker. Market orders fill immediately at
+// the configured price and update positions; other orders rest as open
+// orders until canceled. All methods are safe for concurrent use.
+type Broker struct {
+	mu sync.Mutex
+
+	name      string
+	features  broker.Features
+	balance   broker.Balance
+	prices    map[string]float64
+	funding   map[string]float64
+	symbols   []broker.SymbolInfo
+	positions map[positionKey]*broker.Position
+	orders    []*broker.Order
+	placed    []broker.OrderRequest
+	leverage  map[string]int
+	status    broker.ExchangeStatus
+	clock     clock.Clock
+	nextID    int
+
+	// Err, when set, is returned by every operation
+	Err error
+}
+
+type positionKey struct {
+	symbol string
+	side   broker.Side
+}
+
+// New creates an empty in-memory broker
+func New() *Broker {
+	return &Broker{
+		name: "brokertest",
+		features: broker.Features{
+			TrailingStop:     true,
+			MultipleTP:       true,
+			BracketOrders:    true,
+			MaxLeverage:      125,
+			ReduceOnlyOrders: true,
+			Environments:     []broker.Environment{broker.EnvironmentProduction, broker.EnvironmentTestnet},
+			TimeInForces: []broker.TimeInForce{
+				broker.TimeInForceGTC, broker.TimeInForceIOC, broker.TimeInForceFOK,
+				broker.TimeInForcePostOnly, broker.TimeInForceGTD,
+			},
+			STPModes: []broker.STPMode{broker.STPExpireTaker, broker.STPExpireMaker, broker.STPExpireBoth},
+		},
+		balance:   broker.Balance{Asset: "USDT"},
+		prices:    make(map[string]float64),
+		funding:   make(map[string]float64),
+		positions: make(map[positionKey]*broker.Position),
+		leverage:  make(map[string]int),
+		clock:     clock.Real{},
+	}
+}
+
+// SetClock sets the clock stamping orders, positions and balances, e.g. a
+// clock.Loop's clock for reproducible simulations
+func (b *Broker) SetClock(c clock.Clock) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.clock = c
+}
+
+// SetName overrides the name reported by Name
+func (b *Broker) SetName(name string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.name = name
+}
+
+// SetFeatures overrides the features reported by SupportedFeatures
+func (b *Broker) SetFeatures(f broker.Features) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.features = f
+}
+
+// SetStatus sets the status returned by Status. ServerTime and CheckedAt
+// default to the current time.
+func (b *Broker) SetStatus(status broker.ExchangeStatus) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.status = status
+}
+
+// SetBalance sets the balance returned by GetBalance
+func (b *Broker) SetBalance(balance broker.Balance) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.balance = balance
+}
+
+// SetPrice sets the current price of a symbol and marks open positions to it
+func (b *Broker) SetPrice(symbol string, price float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.prices[symbol] = price
+	for key, pos := range b.positions {
+		if key.symbol == symbol {
+			pos.MarkPrice = price
+			pos.UnrealizedPnL = pnl(pos.Side, pos.Size, pos.EntryPrice, price)
+		}
+	}
+}
+
+// SetFundingRate sets the 8h funding rate returned by GetFundingRate
+func (b *Broker) SetFundingRate(symbol string, rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.funding[symbol] = rate
+}
+
+// SetSymbols sets the listing returned by ListSymbols
+func (b *Broker) SetSymbols(symbols ...broker.SymbolInfo) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.symbols = symbols
+}
+
+// SetPosition replaces the position for its symbol and side. A zero size removes it.
+func (b *Broker) SetPosition(pos broker.Position) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := positionKey{pos.Symbol, pos.Side}
+	if pos.Size == 0 {
+		delete(b.positions, key)
+		return
+	}
+	p := pos
+	b.positions[key] = &p
+}
+
+// AddOrder inserts an open order, assigning an ID when empty
+func (b *Broker) AddOrder(order broker.Order) *broker.Order {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	o := order
+	if o.ID == "" {
+		o.ID = b.newID()
+	}
+	if o.Status == "" {
+		o.Status = broker.OrderStatusNew
+	}
+	b.orders = append(b.orders, &o)
+	return &o
+}
+
+// PlacedOrders returns every order request received by PlaceOrder, in order
+func (b *Broker) PlacedOrders() []broker.OrderRequest {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]broker.OrderRequest(nil), b.placed...)
+}
+
+// Leverage returns the leverage last set for a symbol and side
+func (b *Broker) Leverage(symbol, side string) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.leverage[symbol+"/"+side]
+}
+
+// GetBalance returns the configured balance
+func (b *Broker) GetBalance(ctx context.Context) (*broker.Balance, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return nil, b.Err
+	}
+	balance := b.balance
+	balance.Timestamp = b.clock.Now()
+	return &balance, nil
+}
+
+// GetPositions returns open positions matching the filter
+func (b *Broker) GetPositions(ctx context.Context, filter *broker.PositionFilter) ([]*broker.Position, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return nil, b.Err
+	}
+
+	var positions []*broker.Position
+	for _, pos := range b.positions {
+		if filter != nil && filter.Symbol != "" && filter.Symbol != pos.Symbol {
+			continue
+		}
+		if filter != nil && filter.Side != nil && *filter.Side != pos.Side {
+			continue
+		}
+		p := *pos
+		positions = append(positions, &p)
+	}
+	// Map order is random; keep results reproducible
+	slices.SortFunc(positions, func(a, c *broker.Position) int {
+		return cmp.Or(cmp.Compare(a.Symbol, c.Symbol), cmp.Compare(a.Side, c.Side))
+	})
+	return positions, nil
+}
+
+// GetPosition returns the first open position for a symbol
+func (b *Broker) GetPosition(ctx context.Context, symbol string) (*broker.Position, error) {
+	positions, err := b.GetPositions(ctx, &broker.PositionFilter{Symbol: symbol})
+	if err != nil {
+		return nil, err
+	}
+	if len(positions) == 0 {
+		return nil, broker.ErrPositionNotFound
+	}
+	return positions[0], nil
+}
+
+// PlaceOrder records the request. Market orders fill immediately at the
+// current price; everything else rests as an open order.
+func (b *Broker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return nil, b.Err
+	}
+	if req.Size <= 0 {
+		return nil, broker.ErrInvalidQuantity
+	}
+	opts := broker.OrderOptionsFrom(ctx)
+	if err := broker.CheckTimeInForce(b.features, req, opts); err != nil {
+		return nil, err
+	}
+	if err := broker.CheckSTP(b.features, opts); err != nil {
+		return nil, err
+	}
+
+	b.placed = append(b.placed, *req)
+
+	// Orders report the position leg they apply to, like exchanges in hedge
+	// mode: a reduce-only order's leg is opposite to its direction
+	leg := req.Side
+	if req.ReduceOnly {
+		leg = opposite(req.Side)
+	}
+	now := b.clock.Now()
+	order := &broker.Order{
+		ID:            b.newID(),
+		ClientOrderID: broker.ClientOrderIDOf(ctx, req),
+		Symbol:        req.Symbol,
+		Side:          leg,
+		Type:          req.Type,
+		Status:        broker.OrderStatusNew,
+		Size:          req.Size,
+		Price:         req.Price,
+		StopPrice:     req.StopPrice,
+		ReduceOnly:    req.ReduceOnly,
+		TimeInForce:   req.TimeInForce,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if req.Type == broker.OrderTypeMarket {
+		price, ok := b.prices[req.Symbol]
+		if !ok {
+			return nil, broker.ErrInvalidSymbol
+		}
+		b.fill(req, price)
+		order.Status = broker.OrderStatusFilled
+		order.FilledSize = req.Size
+		order.AveragePrice = price
+		return order, nil
+	}
+
+	b.orders = append(b.orders, order)
+	o := *order
+	return &o, nil
+}
+
+// fill applies a filled order to positions. Reduce-only orders shrink the
+// opposite leg; other orders grow the leg on their own side.
+func (b *Broker) fill(req *broker.OrderRequest, price float64) {
+	if req.ReduceOnly {
+		key := positionKey{req.Symbol, opposite(req.Side)}
+		pos, ok := b.positions[key]
+		if !ok {
+			return
+		}
+		size := req.Size
+		if size > pos.Size {
+			size = pos.Size
+		}
+		pos.RealizedPnL += pnl(pos.Side, size, pos.EntryPrice, price)
+		pos.Size -= size
+		if pos.Size <= 0 {
+			delete(b.positions, key)
+		}
+		return
+	}
+
+	key := positionKey{req.Symbol, req.Side}
+	pos, ok := b.positions[key]
+	if !ok {
+		pos = &broker.Position{Symbol: req.Symbol, Side: req.Side, Leverage: 1}
+		b.positions[key] = pos
+	}
+	pos.EntryPrice = (pos.EntryPrice*pos.Size + price*req.Size) / (pos.Size + req.Size)
+	pos.Size += req.Size
+	pos.MarkPrice = price
+	pos.UnrealizedPnL = pnl(pos.Side, pos.Size, pos.EntryPrice, price)
+	pos.Timestamp = b.clock.Now()
+}
+
+// GetOrders returns open orders matching the filter
+func (b *Broker) GetOrders(ctx context.Context, filter *broker.OrderFilter) ([]*broker.Order, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return nil, b.Err
+	}
+
+	var orders []*broker.Order
+	for _, o := range b.orders {
+		if filter != nil && filter.Symbol != "" && filter.Symbol != o.Symbol {
+			continue
+		}
+		if filter != nil && filter.Status != nil && *filter.Status != o.Status {
+			continue
+		}
+		order := *o
+		orders = append(orders, &order)
+	}
+	return orders, nil
+}
+
+// CancelOrder removes an open order
+func (b *Broker) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return b.Err
+	}
+
+	for i, o := range b.orders {
+		if o.ID == orderID && o.Symbol == symbol {
+			b.orders = append(b.orders[:i], b.orders[i+1:]...)
+			return nil
+		}
+	}
+	return broker.ErrOrderNotFound
+}
+
+// CancelAllOrders removes every open order for a symbol (or all symbols if empty)
+func (b *Broker) CancelAllOrders(ctx context.Context, symbol string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return b.Err
+	}
+
+	remaining := b.orders[:0]
+	for _, o := range b.orders {
+		if symbol != "" && o.Symbol != symbol {
+			remaining = append(remaining, o)
+		}
+	}
+	b.orders = remaining
+	return nil
+}
+
+// AdjustMargin adds or removes margin on an open position leg
+func (b *Broker) AdjustMargin(ctx context.Context, symbol string, side broker.Side, amount float64) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return b.Err
+	}
+	pos, ok := b.positions[positionKey{symbol, side}]
+	if !ok {
+		return broker.ErrPositionNotFound
+	}
+	if pos.Margin+amount < 0 {
+		return broker.ErrInsufficientBalance
+	}
+	pos.Margin += amount
+	return nil
+}
+
+// GetCurrentPrice returns the configured price for a symbol
+func (b *Broker) GetCurrentPrice(ctx context.Context, symbol string) (float64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return 0, b.Err
+	}
+	price, ok := b.prices[symbol]
+	if !ok {
+		return 0, broker.ErrInvalidSymbol
+	}
+	return price, nil
+}
+
+// GetFundingRate returns the configured funding rate for a symbol
+func (b *Broker) GetFundingRate(ctx context.Context, symbol string) (*broker.FundingRate, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return nil, b.Err
+	}
+	price, ok := b.prices[symbol]
+	if !ok {
+		return nil, broker.ErrInvalidSymbol
+	}
+	return &broker.FundingRate{
+		Symbol:    symbol,
+		Rate:      b.funding[symbol],
+		Interval:  8 * time.Hour,
+		MarkPrice: price,
+	}, nil
+}
+
+// ListSymbols returns the configured symbols matching filter
+func (b *Broker) ListSymbols(ctx context.Context, filter *broker.SymbolFilter) ([]broker.SymbolInfo, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return nil, b.Err
+	}
+	var symbols []broker.SymbolInfo
+	for _, s := range b.symbols {
+		if filter.Match(s) {
+			symbols = append(symbols, s)
+		}
+	}
+	return symbols, nil
+}
+
+// SetLeverage records the leverage for a symbol and side
+func (b *Broker) SetLeverage(ctx context.Context, symbol string, side string, leverage int) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return b.Err
+	}
+	if leverage > b.features.MaxLeverage {
+		return broker.ErrLeverageTooHigh
+	}
+	b.leverage[symbol+"/"+side] = leverage
+	return nil
+}
+
+// Ping returns Err
+func (b *Broker) Ping(ctx context.Context) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.Err
+}
+
+// Status returns the status set with SetStatus
+func (b *Broker) Status(ctx context.Context) (*broker.ExchangeStatus, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.Err != nil {
+		return nil, b.Err
+	}
+
+	status := b.status
+	now := b.clock.Now()
+	if status.ServerTime.IsZero() {
+		status.ServerTime = now.Add(status.ClockDrift)
+	}
+	if status.CheckedAt.IsZero() {
+		status.CheckedAt = now
+	}
+	return &status, nil
+}
+
+// Name returns the broker name
+func (b *Broker) Name() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.name
+}
+
+// SupportedFeatures returns the configured features
+func (b *Broker) SupportedFeatures() broker.Features {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.features
+}
+
+// newID returns the next sequential order ID. Callers must hold b.mu.
+func (b *Broker) newID() string {
+	b.nextID++
+	return strconv.Itoa(b.nextID)
+}
+
+// opposite returns the other side
+func opposite(side broker.Side) broker.Side {
+	if side == broker.SideLong {
+		return broker.SideShort
+	}
+	return broker.SideLong
+}
+
+// pnl returns the linear PnL of a position leg
+func pnl(side broker.Side, size, entry, exit float64) float64 {
+	if side == broker.SideShort {
+		return (entry - exit) * size
+	}
+	return (exit - entry) * size
+}
@@ -0,0 +1,254 @@
+package brokertest
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// ErrDisconnected is returned by Chaos.StreamTrades when it drops a stream
+var ErrDisconnected = errors.New("brokertest: stream disconnected")
+
+// ChaosConfig configures the faults injected by Chaos. Rates are
+// probabilities between 0 and 1; zero values inject nothing.
+type ChaosConfig struct {
+	// Latency delays every call; Jitter adds up to that much more at random
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// ErrorRate fails calls before they reach the underlying broker with an
+	// error picked from Errors (default: broker.ErrRateLimited and
+	// broker.ErrAPIError)
+	ErrorRate float64
+	Errors    []error
+
+	// PartialFillRate fills market orders only partially: the underlying
+	// broker receives a fraction of the size and the returned order is
+	// PARTIALLY_FILLED with the rest unfilled
+	PartialFillRate float64
+
+	// DuplicateRate repeats updates: streamed trades are delivered twice and
+	// orders are listed twice by GetOrders
+	DuplicateRate float64
+
+	// DisconnectRate drops a trade stream before each event, making
+	// StreamTrades return ErrDisconnected
+	DisconnectRate float64
+
+	// Seed makes the injected faults reproducible (default: random)
+	Seed uint64
+}
+
+// Chaos wraps a broker.Broker and injects latency, errors, partial fills,
+// duplicated updates and stream disconnects, for testing how strategies
+// cope with an unreliable exchange
+type Chaos struct {
+	broker.Broker
+	cfg ChaosConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewChaos wraps b with the faults described by cfg
+func NewChaos(b broker.Broker, cfg ChaosConfig) *Chaos {
+	if len(cfg.Errors) == 0 {
+		cfg.Errors = []error{broker.ErrRateLimited, broker.ErrAPIError}
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = rand.Uint64()
+	}
+	return &Chaos{Broker: b, cfg: cfg, rng: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// GetBalance forwards the call with injected faults
+func (c *Chaos) GetBalance(ctx context.Context) (*broker.Balance, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	return c.Broker.GetBalance(ctx)
+}
+
+// GetPositions forwards the call with injected faults
+func (c *Chaos) GetPositions(ctx context.Context, filter *broker.PositionFilter) ([]*broker.Position, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	return c.Broker.GetPositions(ctx, filter)
+}
+
+// GetPosition forwards the call with injected faults
+func (c *Chaos) GetPosition(ctx context.Context, symbol string) (*broker.Position, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	return c.Broker.GetPosition(ctx, symbol)
+}
+
+// PlaceOrder forwards the order with injected faults, filling market orders
+// partially at PartialFillRate
+func (c *Chaos) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	if order.Type != broker.OrderTypeMarket || !c.roll(c.cfg.PartialFillRate) {
+		return c.Broker.PlaceOrder(ctx, order)
+	}
+
+	partial := *order
+	partial.Size = order.Size * (0.1 + 0.8*c.float())
+	placed, err := c.Broker.PlaceOrder(ctx, &partial)
+	if err != nil {
+		return nil, err
+	}
+	placed.Size = order.Size
+	placed.Status = broker.OrderStatusPartiallyFilled
+	return placed, nil
+}
+
+// GetOrders forwards the call with injected faults, listing orders twice at
+// DuplicateRate
+func (c *Chaos) GetOrders(ctx context.Context, filter *broker.OrderFilter) ([]*broker.Order, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	orders, err := c.Broker.GetOrders(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*broker.Order, 0, len(orders))
+	for _, o := range orders {
+		result = append(result, o)
+		if c.roll(c.cfg.DuplicateRate) {
+			dup := *o
+			result = append(result, &dup)
+		}
+	}
+	return result, nil
+}
+
+// CancelOrder forwards the call with injected faults
+func (c *Chaos) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	if err := c.inject(ctx); err != nil {
+		return err
+	}
+	return c.Broker.CancelOrder(ctx, symbol, orderID)
+}
+
+// CancelAllOrders forwards the call with injected faults
+func (c *Chaos) CancelAllOrders(ctx context.Context, symbol string) error {
+	if err := c.inject(ctx); err != nil {
+		return err
+	}
+	return c.Broker.CancelAllOrders(ctx, symbol)
+}
+
+// GetCurrentPrice forwards the call with injected faults
+func (c *Chaos) GetCurrentPrice(ctx context.Context, symbol string) (float64, error) {
+	if err := c.inject(ctx); err != nil {
+		return 0, err
+	}
+	return c.Broker.GetCurrentPrice(ctx, symbol)
+}
+
+// SetLeverage forwards the call with injected faults
+func (c *Chaos) SetLeverage(ctx context.Context, symbol string, side string, leverage int) error {
+	if err := c.inject(ctx); err != nil {
+		return err
+	}
+	return c.Broker.SetLeverage(ctx, symbol, side, leverage)
+}
+
+// Ping forwards the call with injected faults
+func (c *Chaos) Ping(ctx context.Context) error {
+	if err := c.inject(ctx); err != nil {
+		return err
+	}
+	return c.Broker.Ping(ctx)
+}
+
+// Status forwards the call with injected faults
+func (c *Chaos) Status(ctx context.Context) (*broker.ExchangeStatus, error) {
+	if err := c.inject(ctx); err != nil {
+		return nil, err
+	}
+	return c.Broker.Status(ctx)
+}
+
+// StreamTrades forwards the underlying broker's trade stream, duplicating
+// trades at DuplicateRate and dropping the stream at DisconnectRate. It
+// returns broker.ErrNotSupported if the broker is not a broker.TradeStreamer.
+func (c *Chaos) StreamTrades(ctx context.Context, symbol string, handler func(broker.Trade)) error {
+	s, ok := c.Broker.(broker.TradeStreamer)
+	if !ok {
+		return broker.ErrNotSupported
+	}
+	if err := c.inject(ctx); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var dropped atomic.Bool
+	err := s.StreamTrades(ctx, symbol, func(t broker.Trade) {
+		if dropped.Load() {
+			return
+		}
+		if c.roll(c.cfg.DisconnectRate) {
+			dropped.Store(true)
+			cancel()
+			return
+		}
+		handler(t)
+		if c.roll(c.cfg.DuplicateRate) {
+			handler(t)
+		}
+	})
+	if dropped.Load() {
+		return ErrDisconnected
+	}
+	return err
+}
+
+// inject sleeps for the configured latency and rolls for an injected error
+func (c *Chaos) inject(ctx context.Context) error {
+	delay := c.cfg.Latency
+	if c.cfg.Jitter > 0 {
+		delay += time.Duration(c.float() * float64(c.cfg.Jitter))
+	}
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	if !c.roll(c.cfg.ErrorRate) {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.cfg.Errors[c.rng.IntN(len(c.cfg.Errors))]
+}
+
+// roll reports whether an event with probability rate happens
+func (c *Chaos) roll(rate float64) bool {
+	return rate > 0 && c.float() < rate
+}
+
+func (c *Chaos) float() float64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64()
+}
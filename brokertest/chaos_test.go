@@ -0,0 +1,136 @@
+package brokertest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// streamer adds a trade feed that emits n trades to the in-memory broker
+type streamer struct {
+	*Broker
+	n int
+}
+
+func (s *streamer) StreamTrades(ctx context.Context, symbol string, handler func(broker.Trade)) error {
+	for i := range s.n {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		handler(broker.Trade{Symbol: symbol, Price: float64(i)})
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestChaos_Latency(t *testing.T) {
+	c := NewChaos(New(), ChaosConfig{Latency: 20 * time.Millisecond})
+
+	start := time.Now()
+	if _, err := c.GetBalance(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("GetBalance() took %v, want at least 20ms", elapsed)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.GetBalance(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetBalance() with canceled context error = %v, want context.Canceled", err)
+	}
+}
+
+func TestChaos_Errors(t *testing.T) {
+	inner := New()
+	inner.SetPrice("BTC-USDT", 50000)
+	c := NewChaos(inner, ChaosConfig{ErrorRate: 1, Errors: []error{broker.ErrRateLimited}})
+
+	_, err := c.PlaceOrder(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1})
+	if !errors.Is(err, broker.ErrRateLimited) {
+		t.Errorf("PlaceOrder() error = %v, want ErrRateLimited", err)
+	}
+	if placed := inner.PlacedOrders(); len(placed) != 0 {
+		t.Errorf("underlying broker received %d orders, want 0", len(placed))
+	}
+}
+
+func TestChaos_PartialFill(t *testing.T) {
+	inner := New()
+	inner.SetPrice("BTC-USDT", 50000)
+	c := NewChaos(inner, ChaosConfig{PartialFillRate: 1, Seed: 1})
+
+	order, err := c.PlaceOrder(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != broker.OrderStatusPartiallyFilled || order.Size != 1 {
+		t.Errorf("order = %s size %v, want PARTIALLY_FILLED size 1", order.Status, order.Size)
+	}
+	if order.FilledSize <= 0 || order.FilledSize >= 1 {
+		t.Errorf("FilledSize = %v, want between 0 and 1", order.FilledSize)
+	}
+
+	pos, err := inner.GetPosition(context.Background(), "BTC-USDT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos.Size != order.FilledSize {
+		t.Errorf("position size = %v, want filled size %v", pos.Size, order.FilledSize)
+	}
+}
+
+func TestChaos_DuplicateOrders(t *testing.T) {
+	inner := New()
+	inner.AddOrder(broker.Order{Symbol: "BTC-USDT", Type: broker.OrderTypeLimit, Size: 1, Price: 45000})
+	c := NewChaos(inner, ChaosConfig{DuplicateRate: 1})
+
+	orders, err := c.GetOrders(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orders) != 2 || orders[0].ID != orders[1].ID {
+		t.Errorf("GetOrders() = %d orders, want the order listed twice", len(orders))
+	}
+}
+
+func TestChaos_StreamTrades(t *testing.T) {
+	t.Run("duplicates", func(t *testing.T) {
+		c := NewChaos(&streamer{Broker: New(), n: 3}, ChaosConfig{DuplicateRate: 1})
+		ctx, cancel := context.WithCancel(context.Background())
+
+		var got int
+		c.StreamTrades(ctx, "BTC-USDT", func(broker.Trade) {
+			got++
+			if got == 6 {
+				cancel()
+			}
+		})
+		if got != 6 {
+			t.Errorf("received %d trades, want 6", got)
+		}
+	})
+
+	t.Run("disconnects", func(t *testing.T) {
+		c := NewChaos(&streamer{Broker: New(), n: 3}, ChaosConfig{DisconnectRate: 1})
+
+		var got int
+		err := c.StreamTrades(context.Background(), "BTC-USDT", func(broker.Trade) { got++ })
+		if !errors.Is(err, ErrDisconnected) {
+			t.Errorf("StreamTrades() error = %v, want ErrDisconnected", err)
+		}
+		if got != 0 {
+			t.Errorf("received %d trades after disconnect, want 0", got)
+		}
+	})
+
+	t.Run("not supported", func(t *testing.T) {
+		c := NewChaos(New(), ChaosConfig{})
+		if err := c.StreamTrades(context.Background(), "BTC-USDT", func(broker.Trade) {}); !errors.Is(err, broker.ErrNotSupported) {
+			t.Errorf("StreamTrades() error = %v, want ErrNotSupported", err)
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package brokertest
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Conformance is the contract test suite of broker.Broker implementations.
+// Run it from an adapter's tests against a testnet account, or against a
+// fake exchange server:
+//
+//	func TestConformance(t *testing.T) {
+//		if os.Getenv("YOUREXCHANGE_API_KEY") == "" {
+//			t.Skip("API credentials not set")
+//		}
+//		b := NewClient(os.Getenv("YOUREXCHANGE_API_KEY"), os.Getenv("YOUREXCHANGE_SECRET_KEY"), true)
+//		brokertest.RunConformance(t, b)
+//	}
+//
+// Order checks rest a limit order far below the market and cancel it; they
+// never trade. Market orders are only placed with RoundTrip.
+type Conformance struct {
+	// Symbol is traded by the suite (default "BTC-USDT")
+	Symbol string
+	// Size is the order size (default 0.001), above the symbol's minimum
+	Size float64
+	// ReadOnly skips every check that places or cancels orders
+	ReadOnly bool
+	// RoundTrip opens a market position of Size and closes it again
+	RoundTrip bool
+	// Timeout bounds each check (default 30s)
+	Timeout time.Duration
+}
+
+// RunConformance runs the default conformance suite against b
+func RunConformance(t *testing.T, b broker.Broker) {
+	t.Helper()
+	Conformance{}.Run(t, b)
+}
+
+// Run runs the suite against b, one subtest per part of the contract
+func (c Conformance) Run(t *testing.T, b broker.Broker) {
+	t.Helper()
+	if c.Symbol == "" {
+		c.Symbol = "BTC-USDT"
+	}
+	if c.Size == 0 {
+		c.Size = 0.001
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 30 * time.Second
+	}
+
+	run := func(name string, check func(t *testing.T, ctx context.Context)) {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), c.Timeout)
+			defer cancel()
+			check(t, ctx)
+		})
+	}
+
+	run("Metadata", func(t *testing.T, ctx context.Context) { c.metadata(t, b) })
+	run("Balance", func(t *testing.T, ctx context.Context) { c.balance(t, ctx, b) })
+	run("Price", func(t *testing.T, ctx context.Context) { c.price(t, ctx, b) })
+	run("Positions", func(t *testing.T, ctx context.Context) { c.positions(t, ctx, b) })
+	if c.ReadOnly {
+		return
+	}
+	run("LimitOrder", func(t *testing.T, ctx context.Context) { c.limitOrder(t, ctx, b) })
+	run("CancelAllOrders", func(t *testing.T, ctx context.Context) { c.cancelAll(t, ctx, b) })
+	if c.RoundTrip {
+		run("RoundTrip", func(t *testing.T, ctx context.Context) { c.roundTrip(t, ctx, b) })
+	}
+}
+
+func (c Conformance) metadata(t *testing.T, b broker.Broker) {
+	if b.Name() == "" {
+		t.Error("Name() is empty")
+	}
+	if f := b.SupportedFeatures(); f.MaxLeverage <= 0 {
+		t.Errorf("SupportedFeatures().MaxLeverage = %d, want > 0", f.MaxLeverage)
+	}
+}
+
+func (c Conformance) balance(t *testing.T, ctx context.Context, b broker.Broker) {
+	balance, err := b.GetBalance(ctx)
+	if err != nil {
+		t.Fatalf("GetBalance() error = %v", err)
+	}
+	if balance == nil {
+		t.Fatal("GetBalance() = nil without an error")
+	}
+	if balance.Asset == "" {
+		t.Error("Balance.Asset is empty")
+	}
+	if balance.Total < 0 || balance.Available < 0 || balance.InUse < 0 {
+		t.Errorf("Balance = %+v, want non-negative totals", balance)
+	}
+}
+
+func (c Conformance) price(t *testing.T, ctx context.Context, b broker.Broker) {
+	price, err := b.GetCurrentPrice(ctx, c.Symbol)
+	if err != nil {
+		t.Fatalf("GetCurrentPrice(%s) error = %v", c.Symbol, err)
+	}
+	if price <= 0 || math.IsNaN(price) || math.IsInf(price, 0) {
+		t.Errorf("GetCurrentPrice(%s) = %v, want a positive price", c.Symbol, price)
+	}
+}
+
+func (c Conformance) positions(t *testing.T, ctx context.Context, b broker.Broker) {
+	positions, err := b.GetPositions(ctx, nil)
+	if err != nil {
+		t.Fatalf("GetPositions() error = %v", err)
+	}
+	for _, p := range positions {
+		if p.Symbol == "" || (p.Side != broker.SideLong && p.Side != broker.SideShort) || p.Size <= 0 {
+			t.Errorf("GetPositions() returned %+v, want a symbol, LONG or SHORT and a positive size", p)
+		}
+	}
+
+	filtered, err := b.GetPositions(ctx, &broker.PositionFilter{Symbol: c.Symbol})
+	if err != nil {
+		t.Fatalf("GetPositions(%s) error = %v", c.Symbol, err)
+	}
+	for _, p := range filtered {
+		if p.Symbol != c.Symbol {
+			t.Errorf("GetPositions(%s) returned a %s position", c.Symbol, p.Symbol)
+		}
+	}
+
+	position, err := b.GetPosition(ctx, c.Symbol)
+	switch {
+	case len(filtered) == 0 && !errors.Is(err, broker.ErrPositionNotFound):
+		t.Errorf("GetPosition(%s) without a position = %v, %v, want ErrPositionNotFound", c.Symbol, position, err)
+	case len(filtered) > 0 && (err != nil || position == nil || position.Symbol != c.Symbol):
+		t.Errorf("GetPosition(%s) = %v, %v, want the open position", c.Symbol, position, err)
+	}
+}
+
+func (c Conformance) limitOrder(t *testing.T, ctx context.Context, b broker.Broker) {
+	order := c.restingOrder(t, ctx, b)
+	if order.ID == "" {
+		t.Error("PlaceOrder() returned an order without an ID")
+	}
+	if order.Symbol != c.Symbol || order.Side != broker.SideLong || order.Type != broker.OrderTypeLimit {
+		t.Errorf("PlaceOrder() = %s %s %s, want %s LONG LIMIT", order.Symbol, order.Side, order.Type, c.Symbol)
+	}
+	if order.Status == broker.OrderStatusFilled || order.Status == broker.OrderStatusRejected {
+		t.Errorf("PlaceOrder() status = %s, want the order resting", order.Status)
+	}
+
+	if !c.listed(t, ctx, b, order.ID) {
+		t.Errorf("GetOrders(%s) doesn't list order %s", c.Symbol, order.ID)
+	}
+	if err := b.CancelOrder(ctx, c.Symbol, order.ID); err != nil {
+		t.Fatalf("CancelOrder(%s) error = %v", order.ID, err)
+	}
+	if c.listed(t, ctx, b, order.ID) {
+		t.Errorf("GetOrders(%s) still lists canceled order %s", c.Symbol, order.ID)
+	}
+	if err := b.CancelOrder(ctx, c.Symbol, order.ID); err == nil {
+		t.Errorf("CancelOrder(%s) of a canceled order succeeded, want an error", order.ID)
+	}
+}
+
+func (c Conformance) cancelAll(t *testing.T, ctx context.Context, b broker.Broker) {
+	first := c.restingOrder(t, ctx, b)
+	second := c.restingOrder(t, ctx, b)
+	if err := b.CancelAllOrders(ctx, c.Symbol); err != nil {
+		t.Fatalf("CancelAllOrders(%s) error = %v", c.Symbol, err)
+	}
+	for _, id := range []string{first.ID, second.ID} {
+		if c.listed(t, ctx, b, id) {
+			t.Errorf("GetOrders(%s) still lists order %s after CancelAllOrders", c.Symbol, id)
+		}
+	}
+}
+
+func (c Conformance) roundTrip(t *testing.T, ctx context.Context, b broker.Broker) {
+	before := c.positionSize(t, ctx, b)
+	if _, err := b.PlaceOrder(ctx, &broker.OrderRequest{
+		Symbol: c.Symbol, Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: c.Size,
+	}); err != nil {
+		t.Fatalf("PlaceOrder(market) error = %v", err)
+	}
+	if opened := c.positionSize(t, ctx, b); math.Abs(opened-before-c.Size) > c.Size/1e6 {
+		t.Errorf("long position after a %v market order = %v, want %v", c.Size, opened, before+c.Size)
+	}
+
+	if _, err := b.PlaceOrder(ctx, &broker.OrderRequest{
+		Symbol: c.Symbol, Side: broker.SideShort, Type: broker.OrderTypeMarket, Size: c.Size, ReduceOnly: true,
+	}); err != nil {
+		t.Fatalf("PlaceOrder(reduce-only market) error = %v", err)
+	}
+	if closed := c.positionSize(t, ctx, b); math.Abs(closed-before) > c.Size/1e6 {
+		t.Errorf("long position after closing = %v, want %v", closed, before)
+	}
+}
+
+// restingOrder places a long limit order at half the market price,
+// canceled again when the test ends
+func (c Conformance) restingOrder(t *testing.T, ctx context.Context, b broker.Broker) *broker.Order {
+	t.Helper()
+	price, err := b.GetCurrentPrice(ctx, c.Symbol)
+	if err != nil {
+		t.Fatalf("GetCurrentPrice(%s) error = %v", c.Symbol, err)
+	}
+	order, err := b.PlaceOrder(ctx, &broker.OrderRequest{
+		Symbol: c.Symbol, Side: broker.SideLong, Type: broker.OrderTypeLimit,
+		Size: c.Size, Price: math.Round(price / 2),
+	})
+	if err != nil {
+		t.Fatalf("PlaceOrder(limit) error = %v", err)
+	}
+	if order == nil {
+		t.Fatal("PlaceOrder() = nil without an error")
+	}
+	t.Cleanup(func() {
+		err := b.CancelOrder(context.Background(), c.Symbol, order.ID)
+		if err != nil && c.listed(t, context.Background(), b, order.ID) {
+			t.Errorf("cleanup: canceling order %s: %v", order.ID, err)
+		}
+	})
+	return order
+}
+
+// listed reports whether GetOrders lists orderID among the symbol's orders
+func (c Conformance) listed(t *testing.T, ctx context.Context, b broker.Broker, orderID string) bool {
+	t.Helper()
+	orders, err := b.GetOrders(ctx, &broker.OrderFilter{Symbol: c.Symbol})
+	if err != nil {
+		t.Fatalf("GetOrders(%s) error = %v", c.Symbol, err)
+	}
+	return slices.ContainsFunc(orders, func(o *broker.Order) bool { return o.ID == orderID })
+}
+
+// positionSize returns the size of the long leg on the symbol
+func (c Conformance) positionSize(t *testing.T, ctx context.Context, b broker.Broker) float64 {
+	t.Helper()
+	positions, err := b.GetPositions(ctx, &broker.PositionFilter{Symbol: c.Symbol})
+	if err != nil {
+		t.Fatalf("GetPositions(%s) error = %v", c.Symbol, err)
+	}
+	for _, p := range positions {
+		if p.Side == broker.SideLong {
+			return p.Size
+		}
+	}
+	return 0
+}
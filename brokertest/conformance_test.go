@@ -0,0 +1,21 @@
+package brokertest
+
+import (
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestConformance(t *testing.T) {
+	b := New()
+	b.SetPrice("BTC-USDT", 43000)
+	b.SetBalance(broker.Balance{Asset: "USDT", Total: 1000, Available: 1000})
+	b.SetPosition(broker.Position{Symbol: "ETH-USDT", Side: broker.SideShort, Size: 2, EntryPrice: 2200})
+
+	RunConformance(t, b)
+	Conformance{RoundTrip: true}.Run(t, b)
+
+	if orders, _ := b.GetOrders(t.Context(), nil); len(orders) != 0 {
+		t.Errorf("suite left %d orders open", len(orders))
+	}
+}
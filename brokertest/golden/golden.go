@@ -0,0 +1,118 @@
+// Package golden runs contract tests of broker adapters against captured
+// exchange responses. Each fixture is a raw HTTP response (as printed by
+// `curl -i`) stored next to a .golden file holding the JSON encoding of what
+// the adapter returned for it. Run the tests with -update to rewrite the
+// golden files after an intended change, and review the diff.
+//
+//	func TestBalance_Golden(t *testing.T) {
+//		golden.Run(t, "testdata/golden/balance", func(t *testing.T, fixture *http.Client) (any, error) {
+//			c := NewClient("key", "secret", false, WithHTTPClient(fixture))
+//			return c.GetBalance(context.Background())
+//		})
+//	}
+package golden
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+// FixtureExt is the extension of fixture files
+const FixtureExt = ".http"
+
+// Result is the golden encoding of an adapter call
+type Result struct {
+	Value any    `json:"value,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Run calls fn once per fixture in dir, as a subtest named after the
+// fixture, with an http.Client that answers every request with the
+// fixture's response. It compares what fn returns with the fixture's
+// .golden file.
+func Run(t *testing.T, dir string, fn func(t *testing.T, fixture *http.Client) (any, error)) {
+	t.Helper()
+
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+FixtureExt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) == 0 {
+		t.Fatalf("golden: no %s fixtures in %s", FixtureExt, dir)
+	}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), FixtureExt)
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			value, err := fn(t, &http.Client{Transport: Transport(data)})
+
+			result := Result{Value: value}
+			if err != nil {
+				result.Value = nil
+				result.Error = err.Error()
+			}
+			Assert(t, strings.TrimSuffix(path, FixtureExt)+".golden", result)
+		})
+	}
+}
+
+// Assert compares the indented JSON encoding of got with the file at path,
+// or writes it there when tests run with -update
+func Assert(t *testing.T, path string, got any) {
+	t.Helper()
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // Keep captured HTML error pages readable
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(got); err != nil {
+		t.Fatalf("golden: encoding result: %v", err)
+	}
+	data := buf.Bytes()
+
+	if *update {
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("golden: %s is missing; run the test with -update to create it", path)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want, data) {
+		t.Errorf("result does not match %s (run with -update if the change is intended)\ngot:\n%s\nwant:\n%s", path, data, want)
+	}
+}
+
+// Transport returns an http.RoundTripper answering every request with the
+// raw HTTP response in fixture. Bare LF line endings are accepted.
+func Transport(fixture []byte) http.RoundTripper {
+	return roundTripper(func(req *http.Request) (*http.Response, error) {
+		return http.ReadResponse(bufio.NewReader(bytes.NewReader(fixture)), req)
+	})
+}
+
+type roundTripper func(*http.Request) (*http.Response, error)
+
+func (f roundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
@@ -0,0 +1,42 @@
+package golden
+
+import (
+	"io"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransport(t *testing.T) {
+	fixture := []byte("HTTP/1.1 502 Bad Gateway\nContent-Type: text/html\n\n<html>502</html>\n")
+	client := &http.Client{Transport: Transport(fixture)}
+
+	for range 2 {
+		resp, err := client.Get("https://open-api.bingx.com/openApi/swap/v2/user/positions")
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Errorf("StatusCode = %d, want 502", resp.StatusCode)
+		}
+		if resp.Header.Get("Content-Type") != "text/html" {
+			t.Errorf("Content-Type = %q", resp.Header.Get("Content-Type"))
+		}
+		if string(body) != "<html>502</html>\n" {
+			t.Errorf("body = %q", body)
+		}
+	}
+}
+
+func TestAssert_Update(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "result.golden")
+
+	*update = true
+	Assert(t, path, Result{Value: map[string]float64{"price": 1.5}})
+	*update = false
+
+	Assert(t, path, Result{Value: map[string]float64{"price": 1.5}})
+}
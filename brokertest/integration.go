@@ -0,0 +1,74 @@
+package brokertest
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Integration runs the conformance suite against the testnet of the broker
+// registered as name, so every adapter is verified the same way. It reads
+// the credentials from <NAME>_API_KEY, <NAME>_SECRET_KEY and, if the
+// exchange needs one, <NAME>_PASSPHRASE (NAME is name in upper case, e.g.
+// BINGX_API_KEY), and skips the test when the keys aren't set.
+//
+// <NAME>_TEST_SYMBOL and <NAME>_TEST_SIZE override the traded symbol and
+// order size. The suite only rests a tiny limit order far from the market
+// and cancels it.
+func Integration(t *testing.T, name string) {
+	t.Helper()
+	cfg, suite, ok := integrationConfig(t, name)
+	if !ok {
+		t.Skipf("%s_API_KEY and %s_SECRET_KEY not set", envPrefix(name), envPrefix(name))
+	}
+
+	b, err := broker.Open(name, cfg)
+	if err != nil {
+		t.Fatalf("opening %s testnet: %v", name, err)
+	}
+	suite.Run(t, b)
+}
+
+// integrationConfig reads the testnet configuration of name from the
+// environment, reporting false without credentials
+func integrationConfig(t *testing.T, name string) (broker.Config, Conformance, bool) {
+	t.Helper()
+	getenv := func(key string) string {
+		return strings.TrimSpace(os.Getenv(envPrefix(name) + "_" + key))
+	}
+
+	cfg := broker.Config{
+		APIKey:      getenv("API_KEY"),
+		SecretKey:   getenv("SECRET_KEY"),
+		Passphrase:  getenv("PASSPHRASE"),
+		Environment: broker.EnvironmentTestnet,
+	}
+	if cfg.APIKey == "" || cfg.SecretKey == "" {
+		return cfg, Conformance{}, false
+	}
+
+	suite := Conformance{Symbol: getenv("TEST_SYMBOL")}
+	if size := getenv("TEST_SIZE"); size != "" {
+		var err error
+		if suite.Size, err = strconv.ParseFloat(size, 64); err != nil || suite.Size <= 0 {
+			t.Fatalf("%s_TEST_SIZE = %q, want a positive number", envPrefix(name), size)
+		}
+	}
+	return cfg, suite, true
+}
+
+// envPrefix turns a broker name into an environment variable prefix
+func envPrefix(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return r - 'a' + 'A'
+		case r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+			return r
+		}
+		return '_'
+	}, name)
+}
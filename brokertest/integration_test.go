@@ -0,0 +1,49 @@
+package brokertest
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+var registerFake sync.Once
+
+func TestIntegration(t *testing.T) {
+	registerFake.Do(func() {
+		broker.Register("brokertest-fake", func(cfg broker.Config) (broker.Broker, error) {
+			if cfg.APIKey != "key" || cfg.SecretKey != "secret" || cfg.Env() != broker.EnvironmentTestnet {
+				return nil, fmt.Errorf("config %+v, want the testnet keys from the environment", cfg)
+			}
+			b := New()
+			b.SetPrice("ETH-USDT", 2200)
+			b.SetBalance(broker.Balance{Asset: "USDT", Total: 100, Available: 100})
+			return b, nil
+		})
+	})
+
+	t.Setenv("BROKERTEST_FAKE_API_KEY", "key")
+	t.Setenv("BROKERTEST_FAKE_SECRET_KEY", "secret")
+	t.Setenv("BROKERTEST_FAKE_TEST_SYMBOL", "ETH-USDT")
+	t.Setenv("BROKERTEST_FAKE_TEST_SIZE", "0.01")
+	Integration(t, "brokertest-fake")
+}
+
+func TestIntegrationConfig(t *testing.T) {
+	t.Setenv("PAPER_X_API_KEY", "key")
+	t.Setenv("PAPER_X_SECRET_KEY", "")
+	if _, _, ok := integrationConfig(t, "paper.x"); ok {
+		t.Error("integrationConfig() without a secret key = ok, want skipped")
+	}
+
+	t.Setenv("PAPER_X_SECRET_KEY", "secret")
+	t.Setenv("PAPER_X_PASSPHRASE", "pass")
+	cfg, suite, ok := integrationConfig(t, "paper.x")
+	if !ok || cfg.Passphrase != "pass" || cfg.Environment != broker.EnvironmentTestnet {
+		t.Errorf("integrationConfig() = %+v, %v, want testnet credentials", cfg, ok)
+	}
+	if suite.Symbol != "" || suite.Size != 0 {
+		t.Errorf("suite = %+v, want the defaults", suite)
+	}
+}
@@ -0,0 +1,139 @@
+// Package cache provides a small in-memory TTL cache for semi-static exchange
+// data (symbol info, leverage brackets, fee rates, server time offset).
+package cache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TTL caches values for a fixed duration. A nil *TTL is valid and caches
+// nothing, so clients can make caching optional without nil checks.
+type TTL[K comparable, V any] struct {
+	ttl time.Duration
+	now func() time.Time
+
+	mu      sync.Mutex
+	entries map[K]entry[V]
+	hooks   []func(K)
+}
+
+type entry[V any] struct {
+	value   V
+	expires time.Time
+}
+
+// New creates a cache whose entries expire after ttl
+func New[K comparable, V any](ttl time.Duration) *TTL[K, V] {
+	return &TTL[K, V]{ttl: ttl, now: time.Now, entries: make(map[K]entry[V])}
+}
+
+// Get returns the cached value for key if it has not expired
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	var zero V
+	if c == nil {
+		return zero, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return zero, false
+	}
+	if !c.now().Before(e.expires) {
+		delete(c.entries, key)
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Set stores value under key for the cache's TTL
+func (c *TTL[K, V]) Set(key K, value V) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry[V]{value: value, expires: c.now().Add(c.ttl)}
+}
+
+// GetOrLoad returns the cached value for key, calling load and caching its
+// result on a miss. Errors are not cached.
+func (c *TTL[K, V]) GetOrLoad(ctx context.Context, key K, load func(context.Context) (V, error)) (V, error) {
+	if v, ok := c.Get(key); ok {
+		return v, nil
+	}
+
+	v, err := load(ctx)
+	if err != nil {
+		return v, err
+	}
+	c.Set(key, v)
+	return v, nil
+}
+
+// Invalidate drops key and notifies OnInvalidate hooks
+func (c *TTL[K, V]) Invalidate(key K) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	delete(c.entries, key)
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	for _, hook := range hooks {
+		hook(key)
+	}
+}
+
+// InvalidateAll drops every entry and notifies OnInvalidate hooks for each
+func (c *TTL[K, V]) InvalidateAll() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	keys := make([]K, 0, len(c.entries))
+	for key := range c.entries {
+		keys = append(keys, key)
+	}
+	clear(c.entries)
+	hooks := c.hooks
+	c.mu.Unlock()
+
+	for _, key := range keys {
+		for _, hook := range hooks {
+			hook(key)
+		}
+	}
+}
+
+// OnInvalidate registers fn to run whenever a key is explicitly invalidated
+// (expiry does not trigger hooks)
+func (c *TTL[K, V]) OnInvalidate(fn func(key K)) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.hooks = append(c.hooks, fn)
+}
+
+// Len returns the number of stored entries, including expired ones not yet
+// evicted
+func (c *TTL[K, V]) Len() int {
+	if c == nil {
+		return 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
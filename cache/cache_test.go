@@ -0,0 +1,78 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestTTL_GetOrLoad(t *testing.T) {
+	c := New[string, int](time.Minute)
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	loads := 0
+	load := func(context.Context) (int, error) {
+		loads++
+		return loads, nil
+	}
+	ctx := context.Background()
+
+	for range 3 {
+		if v, _ := c.GetOrLoad(ctx, "a", load); v != 1 {
+			t.Fatalf("GetOrLoad() = %d, want cached 1", v)
+		}
+	}
+
+	now = now.Add(time.Minute)
+	if v, _ := c.GetOrLoad(ctx, "a", load); v != 2 {
+		t.Errorf("GetOrLoad() after expiry = %d, want 2", v)
+	}
+
+	failing := func(context.Context) (int, error) { return 0, errors.New("down") }
+	if _, err := c.GetOrLoad(ctx, "b", failing); err == nil {
+		t.Error("GetOrLoad() error = nil, want load error")
+	}
+	if _, ok := c.Get("b"); ok {
+		t.Error("errors must not be cached")
+	}
+}
+
+func TestTTL_InvalidateHooks(t *testing.T) {
+	c := New[string, int](time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	var invalidated []string
+	c.OnInvalidate(func(key string) { invalidated = append(invalidated, key) })
+
+	c.Invalidate("a")
+	if _, ok := c.Get("a"); ok {
+		t.Error("Get(a) hit after Invalidate")
+	}
+
+	c.InvalidateAll()
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d after InvalidateAll", c.Len())
+	}
+	slices.Sort(invalidated)
+	if !slices.Equal(invalidated, []string{"a", "b"}) {
+		t.Errorf("invalidated = %v, want [a b]", invalidated)
+	}
+}
+
+func TestTTL_NilCachesNothing(t *testing.T) {
+	var c *TTL[string, int]
+	c.Set("a", 1)
+	c.Invalidate("a")
+
+	loads := 0
+	for range 2 {
+		c.GetOrLoad(context.Background(), "a", func(context.Context) (int, error) { loads++; return 1, nil })
+	}
+	if loads != 2 {
+		t.Errorf("loads = %d, want 2 with nil cache", loads)
+	}
+}
@@ -0,0 +1,207 @@
+// Package clock makes time injectable so simulations are reproducible. Sim
+// is a clock that only moves when told to, and Loop runs scheduled events
+// on a single goroutine in a fixed order: by time, then by the order they
+// were scheduled. Two runs that schedule the same events produce the same
+// sequence of calls, bit for bit.
+package clock
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// Clock tells the time
+type Clock interface {
+	Now() time.Time
+}
+
+// Real is the system clock
+type Real struct{}
+
+// Now returns the current local time
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// Func adapts a function to a Clock
+type Func func() time.Time
+
+// Now calls f
+func (f Func) Now() time.Time {
+	return f()
+}
+
+// Sim is a clock moved by hand. It is safe for concurrent use.
+type Sim struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewSim creates a clock stopped at start
+func NewSim(start time.Time) *Sim {
+	return &Sim{now: start}
+}
+
+// Now returns the simulated time
+func (s *Sim) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// Set moves the clock to t. Moving it backwards is allowed but breaks the
+// ordering guarantees of a Loop driving it.
+func (s *Sim) Set(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = t
+}
+
+// Advance moves the clock forward by d
+func (s *Sim) Advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+// Loop is a deterministic single-threaded event loop over a Sim clock.
+// Events run one at a time on the goroutine calling Run or Step, with the
+// clock set to their scheduled time. Events may schedule further events.
+type Loop struct {
+	clock *Sim
+	queue events
+	seq   uint64
+}
+
+// event is a scheduled call. seq breaks ties between events due at the
+// same time in scheduling order.
+type event struct {
+	at       time.Time
+	seq      uint64
+	fn       func()
+	every    time.Duration // Repeat interval, 0 for one-off events
+	canceled *bool
+}
+
+// NewLoop creates a loop whose clock starts at start
+func NewLoop(start time.Time) *Loop {
+	return &Loop{clock: NewSim(start)}
+}
+
+// Clock returns the loop's clock, to inject into the simulated components
+func (l *Loop) Clock() *Sim {
+	return l.clock
+}
+
+// Now returns the loop's current time
+func (l *Loop) Now() time.Time {
+	return l.clock.Now()
+}
+
+// At schedules fn at t. Times in the past run at the current time, after
+// the events already due. The returned function cancels the event.
+func (l *Loop) At(t time.Time, fn func()) (cancel func()) {
+	return l.schedule(t, 0, fn)
+}
+
+// After schedules fn d from now
+func (l *Loop) After(d time.Duration, fn func()) (cancel func()) {
+	return l.schedule(l.Now().Add(d), 0, fn)
+}
+
+// Every schedules fn every d, starting d from now, until canceled
+func (l *Loop) Every(d time.Duration, fn func()) (cancel func()) {
+	if d <= 0 {
+		panic("clock: non-positive interval")
+	}
+	return l.schedule(l.Now().Add(d), d, fn)
+}
+
+func (l *Loop) schedule(at time.Time, every time.Duration, fn func()) func() {
+	if now := l.Now(); at.Before(now) {
+		at = now
+	}
+	canceled := new(bool)
+	l.seq++
+	heap.Push(&l.queue, &event{at: at, seq: l.seq, fn: fn, every: every, canceled: canceled})
+	return func() { *canceled = true }
+}
+
+// Pending returns the number of scheduled events, including canceled ones
+// not yet discarded
+func (l *Loop) Pending() int {
+	return len(l.queue)
+}
+
+// Step runs the next event and reports whether there was one
+func (l *Loop) Step() bool {
+	if l.next() != nil {
+		e := heap.Pop(&l.queue).(*event)
+		l.clock.Set(e.at)
+		if e.every > 0 {
+			l.seq++
+			heap.Push(&l.queue, &event{at: e.at.Add(e.every), seq: l.seq, fn: e.fn, every: e.every, canceled: e.canceled})
+		}
+		e.fn()
+		return true
+	}
+	return false
+}
+
+// next discards canceled events at the head of the queue and returns the
+// next event to run, nil if none
+func (l *Loop) next() *event {
+	for len(l.queue) > 0 {
+		if !*l.queue[0].canceled {
+			return l.queue[0]
+		}
+		heap.Pop(&l.queue)
+	}
+	return nil
+}
+
+// Run runs events due up to and including until, then sets the clock to
+// until. It stops early, returning the context's error, when ctx is
+// canceled between events.
+func (l *Loop) Run(ctx context.Context, until time.Time) error {
+	for {
+		next := l.next()
+		if next == nil || next.at.After(until) {
+			break
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		l.Step()
+	}
+	if until.After(l.Now()) {
+		l.clock.Set(until)
+	}
+	return nil
+}
+
+// events is a min-heap of events by time, then sequence
+type events []*event
+
+func (q events) Len() int { return len(q) }
+
+func (q events) Less(i, j int) bool {
+	if !q[i].at.Equal(q[j].at) {
+		return q[i].at.Before(q[j].at)
+	}
+	return q[i].seq < q[j].seq
+}
+
+func (q events) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *events) Push(x any) { *q = append(*q, x.(*event)) }
+
+func (q *events) Pop() any {
+	old := *q
+	e := old[len(old)-1]
+	old[len(old)-1] = nil
+	*q = old[:len(old)-1]
+	return e
+}
@@ -0,0 +1,87 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// trace runs a small simulation and returns its log
+func trace() string {
+	l := NewLoop(start)
+	var log []string
+	record := func(name string) func() {
+		return func() { log = append(log, fmt.Sprintf("%s@%s", name, l.Now().Format("15:04"))) }
+	}
+
+	l.After(2*time.Minute, record("b"))
+	l.After(time.Minute, record("a"))
+	l.After(2*time.Minute, record("c")) // Same time as b: runs after it
+	stop := l.Every(time.Minute, record("tick"))
+	// Scheduled before the third tick was, so it runs first and cancels it
+	l.At(start.Add(3*time.Minute), func() {
+		record("stop")()
+		stop()
+		l.After(0, record("now")) // Scheduled for the current time: runs next
+	})
+	cancel := l.After(90*time.Second, record("canceled"))
+	cancel()
+
+	l.Run(context.Background(), start.Add(10*time.Minute))
+	log = append(log, "end@"+l.Now().Format("15:04"))
+	return strings.Join(log, " ")
+}
+
+func TestLoop_Order(t *testing.T) {
+	want := "a@00:01 tick@00:01 b@00:02 c@00:02 tick@00:02 stop@00:03 now@00:03 end@00:10"
+	if got := trace(); got != want {
+		t.Errorf("trace =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLoop_Deterministic(t *testing.T) {
+	first := trace()
+	for range 20 {
+		if got := trace(); got != first {
+			t.Fatalf("run diverged:\n%s\n%s", got, first)
+		}
+	}
+}
+
+func TestLoop_RunUntil(t *testing.T) {
+	l := NewLoop(start)
+	ran := 0
+	l.After(time.Hour, func() { ran++ })
+	l.After(3*time.Hour, func() { ran++ })
+
+	l.Run(context.Background(), start.Add(2*time.Hour))
+	if ran != 1 || !l.Now().Equal(start.Add(2*time.Hour)) || l.Pending() != 1 {
+		t.Errorf("ran = %d, now = %v, pending = %d; want 1 event run, clock at until and 1 pending", ran, l.Now(), l.Pending())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := l.Run(ctx, start.Add(4*time.Hour)); err != context.Canceled || ran != 1 {
+		t.Errorf("Run() error = %v after %d events, want context.Canceled before running", err, ran)
+	}
+
+	if !l.Step() || ran != 2 || l.Step() {
+		t.Errorf("Step() ran %d events, want the last one and then false", ran)
+	}
+}
+
+func TestSim(t *testing.T) {
+	s := NewSim(start)
+	s.Advance(time.Minute)
+	if !s.Now().Equal(start.Add(time.Minute)) {
+		t.Errorf("Now() = %v after Advance", s.Now())
+	}
+	s.Set(start)
+	if !s.Now().Equal(start) {
+		t.Errorf("Now() = %v after Set", s.Now())
+	}
+}
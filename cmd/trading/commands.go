@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// env is what a command runs with
+type env struct {
+	broker broker.Broker
+	opts   options
+	out    io.Writer
+}
+
+type command func(ctx context.Context, e *env, args []string) error
+
+var commands = map[string]command{
+	"balance":      cmdBalance,
+	"positions":    cmdPositions,
+	"orders list":  cmdOrdersList,
+	"order place":  cmdOrderPlace,
+	"order cancel": cmdOrderCancel,
+	"price":        cmdPrice,
+	"leverage set": cmdLeverageSet,
+}
+
+func cmdBalance(ctx context.Context, e *env, args []string) error {
+	balance, err := e.broker.GetBalance(ctx)
+	if err != nil {
+		return err
+	}
+	if e.opts.json {
+		return printJSON(e.out, balance)
+	}
+	return printTable(e.out, []string{"ASSET", "TOTAL", "AVAILABLE", "IN USE", "UNREALIZED PNL"},
+		[][]string{{balance.Asset, num(balance.Total), num(balance.Available), num(balance.InUse), num(balance.UnrealizedPnL)}})
+}
+
+func cmdPositions(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("positions", flag.ContinueOnError)
+	symbol := fs.String("symbol", "", "only show this symbol")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	positions, err := e.broker.GetPositions(ctx, &broker.PositionFilter{Symbol: *symbol})
+	if err != nil {
+		return err
+	}
+	if e.opts.json {
+		return printJSON(e.out, positions)
+	}
+
+	rows := make([][]string, 0, len(positions))
+	for _, p := range positions {
+		rows = append(rows, []string{p.Symbol, string(p.Side), num(p.Size), num(p.EntryPrice),
+			num(p.MarkPrice), num(p.LiquidationPrice), fmt.Sprintf("%dx", p.Leverage), num(p.UnrealizedPnL)})
+	}
+	return printTable(e.out, []string{"SYMBOL", "SIDE", "SIZE", "ENTRY", "MARK", "LIQUIDATION", "LEVERAGE", "UNREALIZED PNL"}, rows)
+}
+
+func cmdOrdersList(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("orders list", flag.ContinueOnError)
+	symbol := fs.String("symbol", "", "only show this symbol")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	orders, err := e.broker.GetOrders(ctx, &broker.OrderFilter{Symbol: *symbol})
+	if err != nil {
+		return err
+	}
+	if e.opts.json {
+		return printJSON(e.out, orders)
+	}
+
+	rows := make([][]string, 0, len(orders))
+	for _, o := range orders {
+		rows = append(rows, []string{o.ID, o.Symbol, string(o.Side), string(o.Type), string(o.Status),
+			num(o.Size), num(o.Price), num(o.StopPrice), fmt.Sprint(o.ReduceOnly)})
+	}
+	return printTable(e.out, []string{"ID", "SYMBOL", "SIDE", "TYPE", "STATUS", "SIZE", "PRICE", "STOP", "REDUCE ONLY"}, rows)
+}
+
+func cmdOrderPlace(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("order place", flag.ContinueOnError)
+	symbol := fs.String("symbol", "", "symbol, e.g. BTC-USDT")
+	side := fs.String("side", "", "LONG or SHORT")
+	orderType := fs.String("type", "", "MARKET, LIMIT, STOP, TAKE_PROFIT (default MARKET, or LIMIT with -price)")
+	size := fs.Float64("size", 0, "order size")
+	price := fs.Float64("price", 0, "limit price")
+	stop := fs.Float64("stop", 0, "trigger price for stop orders")
+	sl := fs.Float64("sl", 0, "attach a stop loss at this trigger price")
+	tp := fs.Float64("tp", 0, "attach a take profit at this trigger price")
+	reduceOnly := fs.Bool("reduce-only", false, "only reduce an existing position")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *symbol == "" || *size <= 0 {
+		return fmt.Errorf("%w: order place requires -symbol and -size", errUsage)
+	}
+	s := broker.Side(strings.ToUpper(*side))
+	if s != broker.SideLong && s != broker.SideShort {
+		return fmt.Errorf("%w: -side must be LONG or SHORT", errUsage)
+	}
+
+	req := &broker.OrderRequest{
+		Symbol:     *symbol,
+		Side:       s,
+		Type:       broker.OrderType(strings.ToUpper(*orderType)),
+		Size:       *size,
+		Price:      *price,
+		StopPrice:  *stop,
+		ReduceOnly: *reduceOnly,
+	}
+	if req.Type == "" {
+		req.Type = broker.OrderTypeMarket
+		if *price > 0 {
+			req.Type = broker.OrderTypeLimit
+		}
+	}
+	if *sl > 0 {
+		req.StopLoss = &broker.StopLossConfig{TriggerPrice: *sl}
+	}
+	if *tp > 0 {
+		req.TakeProfit = &broker.TakeProfitConfig{TriggerPrice: *tp}
+	}
+
+	if e.opts.dryRun {
+		return printDryRun(e, "PlaceOrder", req)
+	}
+
+	order, err := e.broker.PlaceOrder(ctx, req)
+	if err != nil {
+		return err
+	}
+	if e.opts.json {
+		return printJSON(e.out, order)
+	}
+	fmt.Fprintf(e.out, "Placed %s %s %s order %s (%s)\n", order.Side, order.Type, order.Symbol, order.ID, order.Status)
+	return nil
+}
+
+func cmdOrderCancel(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("order cancel", flag.ContinueOnError)
+	symbol := fs.String("symbol", "", "symbol, e.g. BTC-USDT")
+	id := fs.String("id", "", "order ID to cancel")
+	all := fs.Bool("all", false, "cancel every open order for the symbol")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *symbol == "" || (*id == "") == !*all {
+		return fmt.Errorf("%w: order cancel requires -symbol and one of -id or -all", errUsage)
+	}
+
+	if *all {
+		if e.opts.dryRun {
+			return printDryRun(e, "CancelAllOrders", map[string]string{"symbol": *symbol})
+		}
+		if err := e.broker.CancelAllOrders(ctx, *symbol); err != nil {
+			return err
+		}
+		fmt.Fprintf(e.out, "Canceled all %s orders\n", *symbol)
+		return nil
+	}
+
+	if e.opts.dryRun {
+		return printDryRun(e, "CancelOrder", map[string]string{"symbol": *symbol, "orderId": *id})
+	}
+	if err := e.broker.CancelOrder(ctx, *symbol, *id); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "Canceled order %s\n", *id)
+	return nil
+}
+
+func cmdPrice(ctx context.Context, e *env, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("%w: price requires a symbol", errUsage)
+	}
+
+	price, err := e.broker.GetCurrentPrice(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if e.opts.json {
+		return printJSON(e.out, map[string]any{"symbol": args[0], "price": price})
+	}
+	fmt.Fprintln(e.out, num(price))
+	return nil
+}
+
+func cmdLeverageSet(ctx context.Context, e *env, args []string) error {
+	fs := flag.NewFlagSet("leverage set", flag.ContinueOnError)
+	symbol := fs.String("symbol", "", "symbol, e.g. BTC-USDT")
+	side := fs.String("side", "", "LONG or SHORT")
+	leverage := fs.Int("leverage", 0, "leverage multiplier")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *symbol == "" || *side == "" || *leverage <= 0 {
+		return fmt.Errorf("%w: leverage set requires -symbol, -side and -leverage", errUsage)
+	}
+	s := strings.ToUpper(*side)
+
+	if e.opts.dryRun {
+		return printDryRun(e, "SetLeverage", map[string]any{"symbol": *symbol, "side": s, "leverage": *leverage})
+	}
+	if err := e.broker.SetLeverage(ctx, *symbol, s, *leverage); err != nil {
+		return err
+	}
+	fmt.Fprintf(e.out, "Set %s %s leverage to %dx\n", *symbol, s, *leverage)
+	return nil
+}
@@ -0,0 +1,128 @@
+// Command trading runs common broker operations from the shell.
+//
+// Usage:
+//
+//	trading [flags] <command> [command flags]
+//
+// Commands:
+//
+//	balance                                  Show account balance
+//	positions [-symbol S]                    List open positions
+//	orders list [-symbol S]                  List open orders
+//	order place -symbol S -side LONG|SHORT -size N [-type T] [-price P] [-stop P] [-sl P] [-tp P] [-reduce-only]
+//	order cancel -symbol S (-id ID | -all)   Cancel one or all orders
+//	price SYMBOL                             Show the current price
+//	leverage set -symbol S -side LONG|SHORT -leverage N
+//
+// With -config, brokers come from a config file (see package config) and
+// -broker selects an entry, defaulting to the file's default. Otherwise
+// credentials are read from BINGX_API_KEY and BINGX_SECRET_KEY.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+
+	_ "github.com/agatticelli/trading-go/bingx"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/config"
+)
+
+// errUsage marks errors that should be followed by usage help
+var errUsage = errors.New("usage")
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, os.Args[1:], os.Stdout, openBroker); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		if errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "run 'trading -h' for usage")
+		}
+		os.Exit(1)
+	}
+}
+
+// options are the global flags shared by every command
+type options struct {
+	config string
+	broker string
+	demo   bool
+	json   bool
+	dryRun bool
+}
+
+// opener creates the broker a command runs against
+type opener func(opts options) (broker.Broker, error)
+
+// run parses global flags and dispatches to a command
+func run(ctx context.Context, args []string, stdout io.Writer, open opener) error {
+	fs := flag.NewFlagSet("trading", flag.ContinueOnError)
+	var opts options
+	fs.StringVar(&opts.config, "config", "", "config file with broker settings")
+	fs.StringVar(&opts.broker, "broker", "", "broker to connect to (default bingx, or the config default)")
+	fs.BoolVar(&opts.demo, "demo", true, "use the demo environment (ignored with -config)")
+	fs.BoolVar(&opts.json, "json", false, "print JSON instead of tables")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "print mutating requests instead of sending them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 {
+		return fmt.Errorf("%w: missing command", errUsage)
+	}
+
+	cmd, cmdArgs := fs.Arg(0), fs.Args()[1:]
+	if len(cmdArgs) > 0 && (cmd == "orders" || cmd == "order" || cmd == "leverage") {
+		cmd, cmdArgs = cmd+" "+cmdArgs[0], cmdArgs[1:]
+	}
+
+	command, ok := commands[cmd]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %q", errUsage, cmd)
+	}
+
+	b, err := open(opts)
+	if err != nil {
+		return err
+	}
+
+	return command(ctx, &env{broker: b, opts: opts, out: stdout}, cmdArgs)
+}
+
+// openBroker connects to a broker from the config file, or using
+// credentials from the environment
+func openBroker(opts options) (broker.Broker, error) {
+	if opts.config != "" {
+		cfg, err := config.Load(opts.config)
+		if err != nil {
+			return nil, err
+		}
+		if opts.broker == "" {
+			return cfg.OpenDefault()
+		}
+		return cfg.Open(opts.broker)
+	}
+
+	if opts.broker != "" && opts.broker != "bingx" {
+		return nil, fmt.Errorf("%w: %q needs -config", broker.ErrUnknownBroker, opts.broker)
+	}
+
+	apiKey := os.Getenv("BINGX_API_KEY")
+	secretKey := os.Getenv("BINGX_SECRET_KEY")
+	if apiKey == "" || secretKey == "" {
+		return nil, errors.New("BINGX_API_KEY and BINGX_SECRET_KEY must be set")
+	}
+
+	env := broker.EnvironmentProduction
+	if opts.demo {
+		env = broker.EnvironmentTestnet
+	}
+	return broker.Open("bingx", broker.Config{APIKey: apiKey, SecretKey: secretKey, Environment: env})
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newFake() *brokertest.Broker {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetBalance(broker.Balance{Asset: "USDT", Total: 1000, Available: 750})
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.1, EntryPrice: 48000, Leverage: 10})
+	b.AddOrder(broker.Order{ID: "42", Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 45000})
+	return b
+}
+
+func runWith(b broker.Broker, args ...string) (string, error) {
+	var out bytes.Buffer
+	err := run(context.Background(), args, &out, func(options) (broker.Broker, error) { return b, nil })
+	return out.String(), err
+}
+
+func TestRun_ReadCommands(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"balance"}, "750"},
+		{[]string{"positions"}, "10x"},
+		{[]string{"orders", "list", "-symbol", "BTC-USDT"}, "45000"},
+		{[]string{"price", "BTC-USDT"}, "50000"},
+		{[]string{"-json", "price", "BTC-USDT"}, `"price": 50000`},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			out, err := runWith(newFake(), tt.args...)
+			if err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("output = %q, want to contain %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_OrderPlace(t *testing.T) {
+	b := newFake()
+	out, err := runWith(b, "order", "place", "-symbol", "BTC-USDT", "-side", "short", "-size", "0.1", "-price", "52000", "-sl", "53000")
+	if err != nil {
+		t.Fatalf("run() error = %v", err)
+	}
+	if !strings.Contains(out, "Placed SHORT LIMIT BTC-USDT") {
+		t.Errorf("output = %q", out)
+	}
+
+	placed := b.PlacedOrders()
+	if len(placed) != 1 || placed[0].Type != broker.OrderTypeLimit || placed[0].StopLoss.TriggerPrice != 53000 {
+		t.Errorf("placed = %+v, want limit with stop loss", placed)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	b := newFake()
+
+	for _, args := range [][]string{
+		{"-dry-run", "order", "place", "-symbol", "BTC-USDT", "-side", "LONG", "-size", "1"},
+		{"-dry-run", "order", "cancel", "-symbol", "BTC-USDT", "-all"},
+		{"-dry-run", "leverage", "set", "-symbol", "BTC-USDT", "-side", "LONG", "-leverage", "20"},
+	} {
+		out, err := runWith(b, args...)
+		if err != nil {
+			t.Fatalf("run(%v) error = %v", args, err)
+		}
+		if !strings.HasPrefix(out, "[dry-run]") {
+			t.Errorf("run(%v) output = %q, want dry-run notice", args, out)
+		}
+	}
+
+	out, _ := runWith(b, "-json", "-dry-run", "order", "cancel", "-symbol", "BTC-USDT", "-id", "42")
+	var v map[string]any
+	if err := json.Unmarshal([]byte(out), &v); err != nil || v["operation"] != "CancelOrder" {
+		t.Errorf("JSON dry-run output = %q, want CancelOrder", out)
+	}
+
+	if placed := b.PlacedOrders(); len(placed) != 0 {
+		t.Errorf("dry run placed %d orders", len(placed))
+	}
+	if orders, _ := b.GetOrders(context.Background(), nil); len(orders) != 1 {
+		t.Errorf("dry run canceled orders, %d left", len(orders))
+	}
+	if lev := b.Leverage("BTC-USDT", "LONG"); lev != 0 {
+		t.Errorf("dry run set leverage to %d", lev)
+	}
+}
+
+func TestRun_UsageErrors(t *testing.T) {
+	for _, args := range [][]string{
+		{},
+		{"withdraw"},
+		{"order", "place", "-symbol", "BTC-USDT", "-side", "UP", "-size", "1"},
+		{"order", "cancel", "-symbol", "BTC-USDT"},
+		{"price"},
+	} {
+		if _, err := runWith(newFake(), args...); !errors.Is(err, errUsage) {
+			t.Errorf("run(%v) error = %v, want usage error", args, err)
+		}
+	}
+}
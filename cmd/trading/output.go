@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printTable(w io.Writer, header []string, rows [][]string) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// printDryRun shows the request a mutating command would have sent
+func printDryRun(e *env, operation string, request any) error {
+	if e.opts.json {
+		return printJSON(e.out, map[string]any{"dryRun": true, "operation": operation, "request": request})
+	}
+	fmt.Fprintf(e.out, "[dry-run] %s on %s:\n", operation, e.broker.Name())
+	return printJSON(e.out, request)
+}
+
+// num formats a number without trailing zeros
+func num(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
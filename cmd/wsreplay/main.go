@@ -0,0 +1,85 @@
+// Command wsreplay feeds stream frames recorded with bingx.WithFrameLog back
+// through the stream parser and prints what it produces, to reproduce
+// parsing bugs seen in production.
+//
+// Usage:
+//
+//	wsreplay [-dir D] [-prefix P] [-stream S] [-raw] [file ...]
+//
+// Without file arguments, every journal file in -dir is replayed oldest
+// first. -stream keeps only frames of one subscription, e.g. BTC-USDT@trade.
+// -raw prints the recorded frames instead of parsing them.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/trading-go/bingx"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/framelog"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run parses flags and replays the selected files to stdout
+func run(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("wsreplay", flag.ContinueOnError)
+	dir := fs.String("dir", ".", "directory holding the journal files")
+	prefix := fs.String("prefix", framelog.DefaultPrefix, "journal file name prefix")
+	stream := fs.String("stream", "", "replay only frames of this subscription")
+	raw := fs.Bool("raw", false, "print recorded frames instead of parsing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	files := fs.Args()
+	if len(files) == 0 {
+		var err error
+		if files, err = framelog.Files(*dir, *prefix); err != nil {
+			return err
+		}
+		if len(files) == 0 {
+			return fmt.Errorf("no %s-*.jsonl.gz files in %s", *prefix, *dir)
+		}
+	}
+
+	return framelog.ReadFiles(files, func(f framelog.Frame) error {
+		if *stream != "" && f.Stream != *stream {
+			return nil
+		}
+		if *raw {
+			fmt.Fprintf(stdout, "%s %s %s\n", f.Time.Format(time.RFC3339Nano), f.Stream, f.Data)
+			return nil
+		}
+		return replay(stdout, f)
+	})
+}
+
+// replay parses one frame and prints its trades. Parse failures are printed
+// with the frame, so the offending payload can be inspected, and replay
+// continues.
+func replay(stdout io.Writer, f framelog.Frame) error {
+	if !strings.HasSuffix(f.Stream, "@trade") {
+		return nil
+	}
+	err := bingx.ReplayTrades(f, func(t broker.Trade) {
+		fmt.Fprintf(stdout, "%s %s %s %v @ %v\n", t.Time.Format(time.RFC3339Nano), t.Symbol, t.Side, t.Size, t.Price)
+	})
+	var brokerErr *broker.BrokerError
+	if errors.As(err, &brokerErr) {
+		fmt.Fprintf(stdout, "%s %s error: %v\n", f.Time.Format(time.RFC3339Nano), f.Stream, err)
+		return nil
+	}
+	return err
+}
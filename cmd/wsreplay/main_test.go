@@ -0,0 +1,65 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/framelog"
+)
+
+func record(t *testing.T, frames ...framelog.Frame) string {
+	t.Helper()
+	dir := t.TempDir()
+	w, err := framelog.Open(framelog.Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	for _, f := range frames {
+		w.Record(f)
+	}
+	w.Close()
+	return dir
+}
+
+func TestRun(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	dir := record(t,
+		framelog.Frame{Time: at, Stream: "BTC-USDT@trade", Data: []byte(`{"code":0,"dataType":"BTC-USDT@trade","data":[{"T":1700000000000,"s":"BTC-USDT","m":true,"p":"43000.5","q":"0.25"}]}`)},
+		framelog.Frame{Time: at, Stream: "BTC-USDT@trade", Data: []byte(`{"code":0,"dataType":"BTC-USDT@trade","data":{"broken":true}}`)},
+		framelog.Frame{Time: at, Stream: "ETH-USDT@trade", Data: []byte(`{"code":0,"dataType":"ETH-USDT@trade","data":[{"T":1700000000000,"s":"ETH-USDT","m":false,"p":"2200","q":"1"}]}`)},
+	)
+
+	tests := []struct {
+		args    []string
+		want    []string
+		notWant string
+	}{
+		{[]string{"-dir", dir}, []string{"BTC-USDT SHORT 0.25 @ 43000.5", "PARSE_ERROR", "ETH-USDT LONG 1 @ 2200"}, ""},
+		{[]string{"-dir", dir, "-stream", "ETH-USDT@trade"}, []string{"ETH-USDT LONG"}, "BTC-USDT"},
+		{[]string{"-dir", dir, "-raw"}, []string{`{"broken":true}`}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args[2:], " "), func(t *testing.T) {
+			var out bytes.Buffer
+			if err := run(tt.args, &out); err != nil {
+				t.Fatalf("run() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output = %q, want to contain %q", out.String(), want)
+				}
+			}
+			if tt.notWant != "" && strings.Contains(out.String(), tt.notWant) {
+				t.Errorf("output = %q, want no %q", out.String(), tt.notWant)
+			}
+		})
+	}
+}
+
+func TestRun_NoFiles(t *testing.T) {
+	if err := run([]string{"-dir", t.TempDir()}, &bytes.Buffer{}); err == nil {
+		t.Error("run() error = nil, want error for an empty directory")
+	}
+}
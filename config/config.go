@@ -0,0 +1,312 @@
+// Package config loads broker credentials and settings from YAML, TOML,
+// JSON or the environment and opens the matching broker clients.
+//
+// A YAML file looks like:
+//
+//	default: main
+//	brokers:
+//	  main:
+//	    type: bingx
+//	    api_key: ${BINGX_API_KEY}
+//	    secret_key: enc:3q2+7w...
+//	    environment: testnet
+//	  coinm:
+//	    type: bingx
+//	    api_key: ${BINGX_API_KEY}
+//	    secret_key: ${BINGX_SECRET_KEY}
+//	    instrument: COIN-M
+//
+// Values may reference environment variables as ${NAME}; values prefixed
+// with "enc:" are decrypted with the key in TRADING_CONFIG_KEY (see Encrypt).
+// environment is production (default) or testnet; the older demo: true is
+// still accepted.
+// Keys other than the fields of BrokerConfig are kept in Options.
+//
+// Brokers are constructed with broker.Open, so the adapter for each type
+// must be imported (e.g. `import _ "github.com/agatticelli/trading-go/bingx"`).
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// EnvPrefix prefixes environment overrides applied by Load and LoadEnv.
+// TRADING_DEFAULT sets Config.Default and TRADING_<NAME>__<KEY> sets a key
+// of broker NAME, e.g. TRADING_MAIN__API_KEY.
+const EnvPrefix = "TRADING_"
+
+// ErrUnknownBroker is returned when a broker name is not configured or its
+// type is not registered
+var ErrUnknownBroker = broker.ErrUnknownBroker
+
+// Format is a configuration file syntax
+type Format string
+
+const (
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+	FormatJSON Format = "json"
+)
+
+// Config holds every configured broker
+type Config struct {
+	// Default names the broker OpenDefault opens
+	Default string
+	Brokers map[string]BrokerConfig
+}
+
+// BrokerConfig holds one broker's credentials and settings
+type BrokerConfig struct {
+	Name string
+	Type string // Registered adapter name, defaults to Name
+	broker.Config
+}
+
+// Load reads a configuration file, choosing the format from its extension,
+// and applies environment overrides
+func Load(path string) (*Config, error) {
+	format, err := formatFromPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	overlayEnv(tree, os.Environ())
+
+	return decode(tree, newResolver(os.LookupEnv))
+}
+
+// LoadEnv builds a configuration from environment variables alone
+func LoadEnv() (*Config, error) {
+	tree := map[string]any{}
+	overlayEnv(tree, os.Environ())
+	return decode(tree, newResolver(os.LookupEnv))
+}
+
+// Parse decodes configuration data without applying environment overrides.
+// ${NAME} references and encrypted values are still resolved.
+func Parse(data []byte, format Format) (*Config, error) {
+	tree, err := parse(data, format)
+	if err != nil {
+		return nil, err
+	}
+	return decode(tree, newResolver(os.LookupEnv))
+}
+
+// Open creates the broker configured under name
+func (c *Config) Open(name string) (broker.Broker, error) {
+	bc, ok := c.Brokers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not configured", ErrUnknownBroker, name)
+	}
+
+	b, err := broker.Open(bc.Type, bc.Config)
+	if err != nil {
+		return nil, fmt.Errorf("broker %q: %w", name, err)
+	}
+	return b, nil
+}
+
+// OpenDefault opens the Default broker, or the only broker when Default is
+// unset
+func (c *Config) OpenDefault() (broker.Broker, error) {
+	name := c.Default
+	if name == "" {
+		if len(c.Brokers) != 1 {
+			return nil, fmt.Errorf("%w: no default broker among %d configured", ErrUnknownBroker, len(c.Brokers))
+		}
+		for name = range c.Brokers {
+		}
+	}
+	return c.Open(name)
+}
+
+// Names returns the configured broker names in sorted order
+func (c *Config) Names() []string {
+	names := make([]string, 0, len(c.Brokers))
+	for name := range c.Brokers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func formatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	case ".toml":
+		return FormatTOML, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+	return "", fmt.Errorf("%s: unrecognized config file extension", path)
+}
+
+// overlayEnv applies EnvPrefix overrides from environ onto tree
+func overlayEnv(tree map[string]any, environ []string) {
+	for _, kv := range environ {
+		key, value, _ := strings.Cut(kv, "=")
+		rest, ok := strings.CutPrefix(key, EnvPrefix)
+		if !ok || key == KeyEnv {
+			continue
+		}
+
+		if rest == "DEFAULT" {
+			tree["default"] = value
+			continue
+		}
+
+		name, field, ok := strings.Cut(rest, "__")
+		if !ok || name == "" || field == "" {
+			continue
+		}
+		setPath(tree, []string{"brokers", strings.ToLower(name), strings.ToLower(field)}, value)
+	}
+}
+
+func decode(tree map[string]any, r *resolver) (*Config, error) {
+	cfg := &Config{Brokers: map[string]BrokerConfig{}}
+
+	for key, value := range tree {
+		switch key {
+		case "default":
+			s, ok := value.(string)
+			if !ok {
+				return nil, errors.New("default must be a string")
+			}
+			cfg.Default = s
+		case "brokers":
+			brokers, ok := value.(map[string]any)
+			if !ok {
+				return nil, errors.New("brokers must be a table")
+			}
+			for name, raw := range brokers {
+				fields, ok := raw.(map[string]any)
+				if !ok {
+					return nil, fmt.Errorf("brokers.%s must be a table", name)
+				}
+				bc, err := decodeBroker(name, fields, r)
+				if err != nil {
+					return nil, err
+				}
+				cfg.Brokers[name] = bc
+			}
+		default:
+			return nil, fmt.Errorf("unknown key %q", key)
+		}
+	}
+
+	if cfg.Default != "" {
+		if _, ok := cfg.Brokers[cfg.Default]; !ok {
+			return nil, fmt.Errorf("%w: default %q is not configured", ErrUnknownBroker, cfg.Default)
+		}
+	}
+
+	return cfg, nil
+}
+
+func decodeBroker(name string, fields map[string]any, r *resolver) (BrokerConfig, error) {
+	bc := BrokerConfig{Name: name, Type: name}
+	bc.Options = map[string]string{}
+
+	for key, raw := range fields {
+		s, ok := raw.(string)
+		if !ok {
+			return bc, fmt.Errorf("brokers.%s.%s must be a value, not a table", name, key)
+		}
+		value, err := r.resolve(s)
+		if err != nil {
+			return bc, fmt.Errorf("brokers.%s.%s: %w", name, key, err)
+		}
+
+		switch key {
+		case "type":
+			bc.Type = strings.ToLower(value)
+		case "api_key":
+			bc.APIKey = value
+		case "secret_key":
+			bc.SecretKey = value
+		case "passphrase":
+			bc.Passphrase = value
+		case "environment":
+			if bc.Environment, err = broker.ParseEnvironment(value); err != nil {
+				return bc, fmt.Errorf("brokers.%s.environment: %w", name, err)
+			}
+		case "demo":
+			if bc.Demo, err = strconv.ParseBool(value); err != nil {
+				return bc, fmt.Errorf("brokers.%s.demo: %w", name, err)
+			}
+		case "base_url":
+			bc.BaseURL = value
+		case "stream_url":
+			bc.StreamURL = value
+		default:
+			bc.Options[key] = value
+		}
+	}
+
+	return bc, nil
+}
+
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// resolver expands ${NAME} references and decrypts "enc:" values
+type resolver struct {
+	lookup func(string) (string, bool)
+	key    []byte
+}
+
+func newResolver(lookup func(string) (string, bool)) *resolver {
+	return &resolver{lookup: lookup}
+}
+
+func (r *resolver) resolve(s string) (string, error) {
+	var missing string
+	s = envRef.ReplaceAllStringFunc(s, func(ref string) string {
+		name := ref[2 : len(ref)-1]
+		value, ok := r.lookup(name)
+		if !ok && missing == "" {
+			missing = name
+		}
+		return value
+	})
+	if missing != "" {
+		return "", fmt.Errorf("environment variable %s is not set", missing)
+	}
+
+	if !strings.HasPrefix(s, encPrefix) {
+		return s, nil
+	}
+
+	if r.key == nil {
+		encoded, ok := r.lookup(KeyEnv)
+		if !ok {
+			return "", fmt.Errorf("encrypted value requires %s", KeyEnv)
+		}
+		key, err := ParseKey(encoded)
+		if err != nil {
+			return "", err
+		}
+		r.key = key
+	}
+
+	return Decrypt(r.key, s)
+}
@@ -0,0 +1,157 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/agatticelli/trading-go/bingx"
+	"github.com/agatticelli/trading-go/broker"
+)
+
+const yamlConfig = `
+# trading brokers
+default: main
+brokers:
+  main:
+    type: bingx
+    api_key: ${TEST_API_KEY}
+    secret_key: "s3cr#t"   # quoted so the hash is kept
+    demo: true
+  coinm:
+    type: BingX
+    api_key: key
+    secret_key: secret
+    instrument: coin-m
+`
+
+const tomlConfig = `
+default = "main"
+
+[brokers.main]
+type = "bingx"
+api_key = "${TEST_API_KEY}"
+secret_key = 's3cr#t'
+demo = true
+
+[brokers.coinm]
+type = "bingx"
+api_key = "key"
+secret_key = "secret"
+instrument = "coin-m"
+`
+
+const jsonConfig = `{
+  "default": "main",
+  "brokers": {
+    "main": {"type": "bingx", "api_key": "${TEST_API_KEY}", "secret_key": "s3cr#t", "demo": true},
+    "coinm": {"type": "bingx", "api_key": "key", "secret_key": "secret", "instrument": "coin-m"}
+  }
+}`
+
+func TestParse_Formats(t *testing.T) {
+	t.Setenv("TEST_API_KEY", "from-env")
+
+	for format, data := range map[Format]string{
+		FormatYAML: yamlConfig,
+		FormatTOML: tomlConfig,
+		FormatJSON: jsonConfig,
+	} {
+		t.Run(string(format), func(t *testing.T) {
+			cfg, err := Parse([]byte(data), format)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			main := cfg.Brokers["main"]
+			if cfg.Default != "main" || main.Type != "bingx" || main.APIKey != "from-env" || main.SecretKey != "s3cr#t" || !main.Demo {
+				t.Errorf("main = %+v (default %q)", main, cfg.Default)
+			}
+			if coinm := cfg.Brokers["coinm"]; coinm.Type != "bingx" || coinm.Options["instrument"] != "coin-m" {
+				t.Errorf("coinm = %+v", coinm)
+			}
+
+			b, err := cfg.Open("coinm")
+			if err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			if c, ok := b.(*bingx.Client); !ok || c.InstrumentType() != bingx.InstrumentCoinMargined {
+				t.Errorf("Open() = %T, want coin-M bingx client", b)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+	}{
+		{"missing env var", FormatYAML, "brokers:\n  a:\n    api_key: ${TEST_UNSET_VAR}\n"},
+		{"unknown key", FormatYAML, "timeout: 5\n"},
+		{"list", FormatYAML, "brokers:\n  - a\n"},
+		{"bad demo", FormatTOML, "[brokers.a]\ndemo = \"maybe\"\n"},
+		{"bad environment", FormatYAML, "brokers:\n  a:\n    environment: staging\n"},
+		{"undefined default", FormatJSON, `{"default": "b", "brokers": {"a": {}}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data), tt.format); err == nil {
+				t.Error("Parse() error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestLoad_EnvOverridesAndEncryption(t *testing.T) {
+	encodedKey, err := NewKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := ParseKey(encodedKey)
+	secret, err := Encrypt(key, "top-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "trading.yml")
+	data := "brokers:\n  main:\n    api_key: file-key\n    secret_key: " + secret + "\n"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(KeyEnv, encodedKey)
+	t.Setenv("TRADING_MAIN__API_KEY", "env-key")
+	t.Setenv("TRADING_MAIN__TYPE", "bingx")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if main := cfg.Brokers["main"]; main.APIKey != "env-key" || main.SecretKey != "top-secret" {
+		t.Errorf("main = %+v, want env api key and decrypted secret", main)
+	}
+	if _, err := cfg.OpenDefault(); err != nil {
+		t.Errorf("OpenDefault() error = %v", err)
+	}
+
+	other, _ := NewKey()
+	t.Setenv(KeyEnv, other)
+	if _, err := Load(path); !errors.Is(err, ErrDecrypt) {
+		t.Errorf("Load() with wrong key error = %v, want ErrDecrypt", err)
+	}
+}
+
+func TestOpen_Unknown(t *testing.T) {
+	cfg := &Config{Brokers: map[string]BrokerConfig{"x": {Name: "x", Type: "kraken", Config: broker.Config{APIKey: "k"}}}}
+
+	if _, err := cfg.Open("missing"); !errors.Is(err, ErrUnknownBroker) {
+		t.Errorf("Open(missing) error = %v, want ErrUnknownBroker", err)
+	}
+	if _, err := cfg.Open("x"); !errors.Is(err, ErrUnknownBroker) {
+		t.Errorf("Open(kraken) error = %v, want ErrUnknownBroker", err)
+	}
+}
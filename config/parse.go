@@ -0,0 +1,230 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// parse decodes data into a tree of nested tables with string values.
+// YAML and TOML support the subset needed for configuration: tables,
+// scalars and comments, but not lists or multi-line values.
+func parse(data []byte, format Format) (map[string]any, error) {
+	switch format {
+	case FormatYAML:
+		return parseYAML(string(data))
+	case FormatTOML:
+		return parseTOML(string(data))
+	case FormatJSON:
+		return parseJSON(data)
+	}
+	return nil, fmt.Errorf("unsupported config format %q", format)
+}
+
+func parseYAML(text string) (map[string]any, error) {
+	type frame struct {
+		indent int
+		table  map[string]any
+	}
+
+	root := map[string]any{}
+	stack := []frame{{indent: -1, table: root}}
+
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimRight(stripComment(line), " \t\r")
+		content := strings.TrimLeft(line, " ")
+		if content == "" || content == "---" {
+			continue
+		}
+		if strings.HasPrefix(content, "\t") {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", n+1)
+		}
+		if strings.HasPrefix(content, "- ") || content == "-" {
+			return nil, fmt.Errorf("line %d: lists are not supported", n+1)
+		}
+
+		key, value, ok := strings.Cut(content, ":")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key: value\"", n+1)
+		}
+
+		indent := len(line) - len(content)
+		for indent <= stack[len(stack)-1].indent {
+			stack = stack[:len(stack)-1]
+		}
+		parent := stack[len(stack)-1].table
+
+		key, err := unquote(strings.TrimSpace(key))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+		if _, dup := parent[key]; dup {
+			return nil, fmt.Errorf("line %d: duplicate key %q", n+1, key)
+		}
+
+		value = strings.TrimSpace(value)
+		if value == "" {
+			table := map[string]any{}
+			parent[key] = table
+			stack = append(stack, frame{indent: indent, table: table})
+			continue
+		}
+
+		if parent[key], err = unquote(value); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+	}
+
+	return root, nil
+}
+
+func parseTOML(text string) (map[string]any, error) {
+	root := map[string]any{}
+	table := root
+
+	for n, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(stripComment(line))
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") || strings.HasPrefix(line, "[[") {
+				return nil, fmt.Errorf("line %d: malformed table header", n+1)
+			}
+			path := splitDotted(line[1 : len(line)-1])
+			table = root
+			for _, part := range path {
+				next, ok := table[part].(map[string]any)
+				if !ok {
+					if _, exists := table[part]; exists {
+						return nil, fmt.Errorf("line %d: %q is already a value", n+1, part)
+					}
+					next = map[string]any{}
+					table[part] = next
+				}
+				table = next
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected \"key = value\"", n+1)
+		}
+
+		value = strings.TrimSpace(value)
+		if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+			return nil, fmt.Errorf("line %d: arrays and inline tables are not supported", n+1)
+		}
+		s, err := unquote(value)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+
+		if err := setPath(table, splitDotted(key), s); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n+1, err)
+		}
+	}
+
+	return root, nil
+}
+
+func parseJSON(data []byte) (map[string]any, error) {
+	var raw map[string]any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	tree, err := stringify(raw)
+	if err != nil {
+		return nil, err
+	}
+	return tree.(map[string]any), nil
+}
+
+// stringify converts JSON scalars to strings so every format yields the
+// same tree
+func stringify(v any) (any, error) {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, child := range v {
+			s, err := stringify(child)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			v[key] = s
+		}
+		return v, nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case nil:
+		return "", nil
+	}
+	return nil, fmt.Errorf("unsupported value %v", v)
+}
+
+// setPath stores value under a dotted path, creating intermediate tables
+func setPath(tree map[string]any, path []string, value any) error {
+	for _, part := range path[:len(path)-1] {
+		next, ok := tree[part].(map[string]any)
+		if !ok {
+			if _, exists := tree[part]; exists {
+				return fmt.Errorf("%q is already a value", part)
+			}
+			next = map[string]any{}
+			tree[part] = next
+		}
+		tree = next
+	}
+	tree[path[len(path)-1]] = value
+	return nil
+}
+
+func splitDotted(key string) []string {
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		parts[i] = strings.Trim(strings.TrimSpace(part), `"'`)
+	}
+	return parts
+}
+
+// stripComment removes a trailing # comment outside of quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
+
+func unquote(s string) (string, error) {
+	if len(s) >= 2 {
+		switch {
+		case s[0] == '"' && s[len(s)-1] == '"':
+			return strconv.Unquote(s)
+		case s[0] == '\'' && s[len(s)-1] == '\'':
+			return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+		}
+	}
+	if strings.HasPrefix(s, `"`) || strings.HasPrefix(s, "'") {
+		return "", fmt.Errorf("unterminated string %s", s)
+	}
+	return s, nil
+}
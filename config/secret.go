@@ -0,0 +1,90 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KeyEnv holds the base64 encoded 32-byte key for encrypted values
+const KeyEnv = "TRADING_CONFIG_KEY"
+
+const encPrefix = "enc:"
+
+// ErrDecrypt is returned when an encrypted value cannot be decrypted
+var ErrDecrypt = errors.New("cannot decrypt config value")
+
+// NewKey generates a random key, base64 encoded for KeyEnv
+func NewKey() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseKey decodes a base64 encoded 32-byte key
+func ParseKey(encoded string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", KeyEnv, err)
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("%s: key must be 32 bytes, got %d", KeyEnv, len(key))
+	}
+	return key, nil
+}
+
+// Encrypt seals plaintext with AES-256-GCM and returns an "enc:" value
+// suitable for a config file
+func Encrypt(key []byte, plaintext string) (string, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := gcm.Seal(nonce, nonce, []byte(plaintext), nil)
+	return encPrefix + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value produced by Encrypt
+func Decrypt(key []byte, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encPrefix)
+	if !ok {
+		return "", fmt.Errorf("%w: missing %q prefix", ErrDecrypt, encPrefix)
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < gcm.NonceSize() {
+		return "", fmt.Errorf("%w: malformed value", ErrDecrypt)
+	}
+
+	nonce, ciphertext := sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("%w: wrong key or corrupted value", ErrDecrypt)
+	}
+	return string(plaintext), nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,146 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// AWSSecretsManager reads credentials from an AWS Secrets Manager secret
+// whose SecretString is a JSON object with api_key and secret_key fields.
+// Requests are signed with Signature Version 4 using static AWS keys.
+type AWSSecretsManager struct {
+	SecretID        string
+	Region          string // Defaults to AWS_REGION
+	AccessKeyID     string // Defaults to AWS_ACCESS_KEY_ID
+	SecretAccessKey string // Defaults to AWS_SECRET_ACCESS_KEY
+	SessionToken    string // Defaults to AWS_SESSION_TOKEN
+	Endpoint        string // Defaults to https://secretsmanager.<region>.amazonaws.com
+	HTTPClient      *http.Client
+
+	now func() time.Time
+}
+
+// Retrieve fetches the current version of the secret
+func (a AWSSecretsManager) Retrieve(ctx context.Context) (Credentials, error) {
+	region := a.Region
+	if region == "" {
+		region = os.Getenv("AWS_REGION")
+	}
+	keyID, secret, token := a.AccessKeyID, a.SecretAccessKey, a.SessionToken
+	if keyID == "" {
+		keyID, secret, token = os.Getenv("AWS_ACCESS_KEY_ID"), os.Getenv("AWS_SECRET_ACCESS_KEY"), os.Getenv("AWS_SESSION_TOKEN")
+	}
+	if region == "" || keyID == "" || secret == "" {
+		return Credentials{}, fmt.Errorf("AWS region and access keys are required")
+	}
+
+	endpoint := a.Endpoint
+	if endpoint == "" {
+		endpoint = "https://secretsmanager." + region + ".amazonaws.com"
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": a.SecretID})
+	if err != nil {
+		return Credentials{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+"/", bytes.NewReader(payload))
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+	signV4(req, payload, keyID, secret, region, "secretsmanager", now().UTC())
+
+	body, err := do(a.HTTPClient, req)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("secrets manager %s: %w", a.SecretID, err)
+	}
+
+	var resp struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Credentials{}, fmt.Errorf("secrets manager %s: %w", a.SecretID, err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(resp.SecretString), &fields); err != nil {
+		return Credentials{}, fmt.Errorf("secrets manager %s: SecretString is not a JSON object: %w", a.SecretID, err)
+	}
+	return fromFields(fields)
+}
+
+// signV4 adds AWS Signature Version 4 headers to req
+func signV4(req *http.Request, payload []byte, keyID, secret, region, service string, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	names := []string{"host"}
+	for name := range req.Header {
+		if name = strings.ToLower(name); name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var headers strings.Builder
+	for _, name := range names {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonical := strings.Join([]string{req.Method, path, req.URL.RawQuery, headers.String(), signedHeaders, payloadHash}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonical))
+
+	key := hmacSHA256([]byte("AWS4"+secret), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		keyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,155 @@
+// Package credentials supplies exchange API keys from static values, the
+// environment, the OS keychain, HashiCorp Vault or AWS Secrets Manager.
+//
+// Clients that accept a Provider (e.g. bingx.WithCredentials) retrieve
+// credentials for every request, so wrapping a remote provider in a Cache
+// both avoids a round trip per request and rotates keys without restarting:
+// once the TTL expires, or after Refresh, the next request uses the new keys.
+package credentials
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned when a provider has no credentials
+var ErrNotFound = errors.New("credentials not found")
+
+// Credentials authenticate requests to an exchange
+type Credentials struct {
+	APIKey     string
+	SecretKey  string
+	Passphrase string // Only used by exchanges that require one
+}
+
+// Valid reports whether both the API key and secret key are set
+func (c Credentials) Valid() bool {
+	return c.APIKey != "" && c.SecretKey != ""
+}
+
+// Provider retrieves credentials
+type Provider interface {
+	Retrieve(ctx context.Context) (Credentials, error)
+}
+
+// Static is a Provider that always returns the same credentials
+type Static Credentials
+
+// Retrieve returns the static credentials
+func (s Static) Retrieve(ctx context.Context) (Credentials, error) {
+	return Credentials(s), nil
+}
+
+// Env reads credentials from environment variables on every call
+type Env struct {
+	APIKeyVar     string
+	SecretKeyVar  string
+	PassphraseVar string // Optional
+}
+
+// Retrieve reads the configured variables
+func (e Env) Retrieve(ctx context.Context) (Credentials, error) {
+	creds := Credentials{
+		APIKey:    os.Getenv(e.APIKeyVar),
+		SecretKey: os.Getenv(e.SecretKeyVar),
+	}
+	if e.PassphraseVar != "" {
+		creds.Passphrase = os.Getenv(e.PassphraseVar)
+	}
+	if !creds.Valid() {
+		return Credentials{}, fmt.Errorf("%w: %s and %s must be set", ErrNotFound, e.APIKeyVar, e.SecretKeyVar)
+	}
+	return creds, nil
+}
+
+// Cache wraps a Provider and reuses its credentials until the TTL expires.
+// If a refresh fails the previous credentials keep being served, and the
+// refresh is retried on the next call.
+type Cache struct {
+	provider Provider
+	ttl      time.Duration
+	now      func() time.Time
+
+	mu       sync.Mutex
+	creds    Credentials
+	fetched  bool
+	expires  time.Time
+	onRotate func(Credentials)
+}
+
+// NewCache caches p's credentials for ttl. A zero ttl caches until Refresh.
+func NewCache(p Provider, ttl time.Duration) *Cache {
+	return &Cache{provider: p, ttl: ttl, now: time.Now}
+}
+
+// OnRotate registers a callback invoked with the new credentials whenever a
+// refresh returns different credentials
+func (c *Cache) OnRotate(fn func(Credentials)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onRotate = fn
+}
+
+// Retrieve returns the cached credentials, refreshing them once expired
+func (c *Cache) Retrieve(ctx context.Context) (Credentials, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.fetched && (c.ttl == 0 || c.now().Before(c.expires)) {
+		return c.creds, nil
+	}
+
+	if err := c.refreshLocked(ctx); err != nil {
+		if c.fetched {
+			return c.creds, nil
+		}
+		return Credentials{}, err
+	}
+	return c.creds, nil
+}
+
+// Refresh fetches credentials from the provider immediately
+func (c *Cache) Refresh(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.refreshLocked(ctx)
+}
+
+func (c *Cache) refreshLocked(ctx context.Context) error {
+	creds, err := c.provider.Retrieve(ctx)
+	if err != nil {
+		return err
+	}
+
+	rotated := c.fetched && creds != c.creds
+	c.creds, c.fetched = creds, true
+	c.expires = c.now().Add(c.ttl)
+
+	if rotated && c.onRotate != nil {
+		c.onRotate(creds)
+	}
+	return nil
+}
+
+// fromFields maps secret fields (api_key, secret_key, passphrase) to
+// Credentials
+func fromFields(fields map[string]any) (Credentials, error) {
+	str := func(key string) string {
+		s, _ := fields[key].(string)
+		return s
+	}
+
+	creds := Credentials{
+		APIKey:     str("api_key"),
+		SecretKey:  str("secret_key"),
+		Passphrase: str("passphrase"),
+	}
+	if !creds.Valid() {
+		return Credentials{}, fmt.Errorf("%w: secret must contain api_key and secret_key", ErrNotFound)
+	}
+	return creds, nil
+}
@@ -0,0 +1,166 @@
+package credentials
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+type countingProvider struct {
+	creds []Credentials
+	err   error
+	calls int
+}
+
+func (p *countingProvider) Retrieve(ctx context.Context) (Credentials, error) {
+	p.calls++
+	if p.err != nil {
+		return Credentials{}, p.err
+	}
+	return p.creds[min(p.calls, len(p.creds))-1], nil
+}
+
+func TestEnv_Retrieve(t *testing.T) {
+	t.Setenv("TEST_KEY", "k")
+	t.Setenv("TEST_SECRET", "")
+
+	env := Env{APIKeyVar: "TEST_KEY", SecretKeyVar: "TEST_SECRET"}
+	if _, err := env.Retrieve(context.Background()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Retrieve() error = %v, want ErrNotFound", err)
+	}
+
+	t.Setenv("TEST_SECRET", "s")
+	if creds, err := env.Retrieve(context.Background()); err != nil || creds != (Credentials{APIKey: "k", SecretKey: "s"}) {
+		t.Errorf("Retrieve() = %+v, %v", creds, err)
+	}
+}
+
+func TestCache_RotatesAfterTTL(t *testing.T) {
+	ctx := context.Background()
+	p := &countingProvider{creds: []Credentials{{APIKey: "a", SecretKey: "1"}, {APIKey: "b", SecretKey: "2"}}}
+	c := NewCache(p, time.Minute)
+	now := time.Unix(0, 0)
+	c.now = func() time.Time { return now }
+
+	var rotated []Credentials
+	c.OnRotate(func(creds Credentials) { rotated = append(rotated, creds) })
+
+	for range 3 {
+		if creds, _ := c.Retrieve(ctx); creds.APIKey != "a" {
+			t.Fatalf("Retrieve() = %+v, want cached a", creds)
+		}
+	}
+	if p.calls != 1 {
+		t.Errorf("provider calls = %d, want 1", p.calls)
+	}
+
+	now = now.Add(time.Minute)
+	if creds, _ := c.Retrieve(ctx); creds.APIKey != "b" {
+		t.Errorf("Retrieve() after TTL = %+v, want b", creds)
+	}
+	if len(rotated) != 1 || rotated[0].APIKey != "b" {
+		t.Errorf("rotations = %+v, want [b]", rotated)
+	}
+
+	// Stale credentials are served while the provider is failing
+	p.err = errors.New("vault sealed")
+	now = now.Add(time.Minute)
+	if creds, err := c.Retrieve(ctx); err != nil || creds.APIKey != "b" {
+		t.Errorf("Retrieve() during outage = %+v, %v, want stale b", creds, err)
+	}
+	if err := c.Refresh(ctx); err == nil {
+		t.Error("Refresh() error = nil during outage")
+	}
+}
+
+func TestKeychain_Retrieve(t *testing.T) {
+	if runtime.GOOS != "darwin" && runtime.GOOS != "linux" {
+		t.Skip("keychain lookup not supported on " + runtime.GOOS)
+	}
+
+	k := Keychain{Service: "trading", Account: "bingx"}
+	k.run = func(ctx context.Context, name string, args ...string) ([]byte, error) {
+		if !strings.Contains(strings.Join(args, " "), "trading") {
+			t.Errorf("args = %v, want service", args)
+		}
+		return []byte(`{"api_key":"k","secret_key":"s"}` + "\n"), nil
+	}
+
+	if creds, err := k.Retrieve(context.Background()); err != nil || creds.APIKey != "k" || creds.SecretKey != "s" {
+		t.Errorf("Retrieve() = %+v, %v", creds, err)
+	}
+}
+
+func TestVault_Retrieve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "tok" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		if r.URL.Path != "/v1/kv/data/trading/bingx" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"data":{"data":{"api_key":"k","secret_key":"s"},"metadata":{"version":3}}}`))
+	}))
+	defer server.Close()
+
+	v := Vault{Address: server.URL, Token: "tok", Mount: "kv", Path: "trading/bingx"}
+	if creds, err := v.Retrieve(context.Background()); err != nil || creds.APIKey != "k" || creds.SecretKey != "s" {
+		t.Errorf("Retrieve() = %+v, %v", creds, err)
+	}
+
+	v.Path = "missing"
+	if _, err := v.Retrieve(context.Background()); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Retrieve(missing) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestAWSSecretsManager_Retrieve(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		auth := r.Header.Get("Authorization")
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/20240102/eu-west-1/secretsmanager/aws4_request") ||
+			!strings.Contains(auth, "SignedHeaders=content-type;host;x-amz-date;x-amz-target,") {
+			t.Errorf("headers = %v", r.Header)
+		}
+		if string(body) != `{"SecretId":"trading/bingx"}` {
+			t.Errorf("body = %s", body)
+		}
+		w.Write([]byte(`{"Name":"trading/bingx","SecretString":"{\"api_key\":\"k\",\"secret_key\":\"s\"}"}`))
+	}))
+	defer server.Close()
+
+	a := AWSSecretsManager{
+		SecretID:        "trading/bingx",
+		Region:          "eu-west-1",
+		AccessKeyID:     "AKID",
+		SecretAccessKey: "secret",
+		Endpoint:        server.URL,
+		now:             func() time.Time { return time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC) },
+	}
+	if creds, err := a.Retrieve(context.Background()); err != nil || creds.APIKey != "k" || creds.SecretKey != "s" {
+		t.Errorf("Retrieve() = %+v, %v", creds, err)
+	}
+}
+
+func TestSignV4_ReferenceVector(t *testing.T) {
+	// Example request from the AWS Signature Version 4 documentation
+	req, _ := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	signV4(req, nil, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "iam",
+		time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	want := "Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7"
+	if got := req.Header.Get("Authorization"); !strings.HasSuffix(got, want) {
+		t.Errorf("Authorization = %q, want %s", got, want)
+	}
+}
@@ -0,0 +1,55 @@
+package credentials
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// Keychain reads credentials stored in the OS keychain as a JSON object
+// ({"api_key": "...", "secret_key": "..."}) under Service and Account.
+// It uses the security tool on macOS and libsecret's secret-tool on Linux.
+type Keychain struct {
+	Service string
+	Account string
+
+	// run executes a command and returns its stdout; replaced in tests
+	run func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// Retrieve looks up the keychain item
+func (k Keychain) Retrieve(ctx context.Context) (Credentials, error) {
+	var name string
+	var args []string
+	switch runtime.GOOS {
+	case "darwin":
+		name, args = "security", []string{"find-generic-password", "-s", k.Service, "-a", k.Account, "-w"}
+	case "linux", "freebsd", "openbsd":
+		name, args = "secret-tool", []string{"lookup", "service", k.Service, "account", k.Account}
+	default:
+		return Credentials{}, fmt.Errorf("keychain is not supported on %s", runtime.GOOS)
+	}
+
+	run := k.run
+	if run == nil {
+		run = runCommand
+	}
+
+	out, err := run(ctx, name, args...)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("%w: keychain item %s/%s: %v", ErrNotFound, k.Service, k.Account, err)
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(bytes.TrimSpace(out), &fields); err != nil {
+		return Credentials{}, fmt.Errorf("keychain item %s/%s is not a JSON object: %w", k.Service, k.Account, err)
+	}
+	return fromFields(fields)
+}
+
+func runCommand(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).Output()
+}
@@ -0,0 +1,86 @@
+package credentials
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Vault reads credentials from a HashiCorp Vault KV version 2 secret with
+// api_key and secret_key fields
+type Vault struct {
+	Address    string // Defaults to VAULT_ADDR
+	Token      string // Defaults to VAULT_TOKEN
+	Mount      string // KV mount, defaults to "secret"
+	Path       string // Secret path within the mount
+	HTTPClient *http.Client
+}
+
+// Retrieve reads the latest version of the secret
+func (v Vault) Retrieve(ctx context.Context) (Credentials, error) {
+	address, token, mount := v.Address, v.Token, v.Mount
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if token == "" {
+		token = os.Getenv("VAULT_TOKEN")
+	}
+	if mount == "" {
+		mount = "secret"
+	}
+	if address == "" || token == "" {
+		return Credentials{}, fmt.Errorf("vault address and token are required")
+	}
+
+	url := strings.TrimSuffix(address, "/") + "/v1/" + strings.Trim(mount, "/") + "/data/" + strings.TrimPrefix(v.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return Credentials{}, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	body, err := do(v.HTTPClient, req)
+	if err != nil {
+		return Credentials{}, fmt.Errorf("vault %s: %w", v.Path, err)
+	}
+
+	var resp struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Credentials{}, fmt.Errorf("vault %s: %w", v.Path, err)
+	}
+	return fromFields(resp.Data.Data)
+}
+
+// do sends req and returns the body of a 200 response
+func do(client *http.Client, req *http.Request) ([]byte, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return body, nil
+}
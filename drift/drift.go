@@ -0,0 +1,271 @@
+// Package drift watches the local clock against the exchange clock. Signed
+// requests carry a local timestamp the exchange rejects once it falls
+// outside the receive window, so a drifting clock silently breaks every
+// order. The wrapped broker measures the drift from the server-time
+// endpoint and from stream event timestamps, reports it as a metric and
+// refuses new orders while it exceeds the limit.
+package drift
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/clock"
+	"github.com/agatticelli/trading-go/logging"
+	"github.com/agatticelli/trading-go/tsdb"
+)
+
+const (
+	// DefaultMaxDrift matches the 5s receive window exchanges apply by default
+	DefaultMaxDrift = 5 * time.Second
+	// DefaultInterval is the default time between server-time checks in Run
+	DefaultInterval = 30 * time.Second
+	// DefaultStreamWindow is the default number of stream samples the
+	// stream estimate is taken from
+	DefaultStreamWindow = 50
+)
+
+// MeasurementClockDrift is the tsdb measurement written for the drift.
+// Fields: drift_ms, latency_ms, exceeded.
+const MeasurementClockDrift = "clock_drift"
+
+// ErrClockDrift is returned for orders refused while the drift exceeds the
+// limit
+var ErrClockDrift = errors.New("drift: local clock drift exceeds the receive window")
+
+// Source is where a drift sample was measured
+type Source string
+
+const (
+	SourceServerTime Source = "SERVER_TIME" // Server-time endpoint, corrected for the round trip
+	SourceStream     Source = "STREAM"      // Stream event timestamps
+)
+
+// Sample is one drift measurement
+type Sample struct {
+	Source  Source
+	Drift   time.Duration // Server clock minus local clock
+	Latency time.Duration // Round trip of the server-time request
+	Time    time.Time
+}
+
+// State is whether the drift is within the limit
+type State string
+
+const (
+	StateOK       State = "OK"
+	StateExceeded State = "EXCEEDED" // Orders are refused
+)
+
+// Event describes a state change
+type Event struct {
+	State  State
+	Sample Sample // Sample that caused the change
+	Time   time.Time
+}
+
+// Handler receives state changes. Handlers run synchronously on the
+// goroutine that took the sample and should return quickly.
+type Handler func(ctx context.Context, e Event)
+
+// Config configures a Broker
+type Config struct {
+	// MaxDrift is the largest drift, either way, orders are sent with
+	// (default 5s). Set it to the receive window the client signs with.
+	MaxDrift time.Duration
+	// Interval between server-time checks in Run (default 30s)
+	Interval time.Duration
+	// StreamWindow is how many stream samples are kept (default 50).
+	// Stream timestamps arrive late by the network latency, so the
+	// estimate is the sample least delayed in the window.
+	StreamWindow int
+	Clock        clock.Clock
+	Logger       *slog.Logger
+}
+
+// Broker is a broker.Broker that refuses new orders while the local clock
+// drifts from the exchange clock past MaxDrift. Cancels and reads still
+// reach the exchange.
+type Broker struct {
+	broker.Broker
+	config Config
+	clock  clock.Clock
+	log    *slog.Logger
+
+	mu       sync.Mutex
+	state    State
+	last     Sample   // Latest estimate, from either source
+	stream   []Sample // Ring of recent stream samples
+	next     int      // Next ring slot
+	handlers []Handler
+}
+
+// Wrap makes b drift-aware
+func Wrap(b broker.Broker, config Config) *Broker {
+	if config.MaxDrift <= 0 {
+		config.MaxDrift = DefaultMaxDrift
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.StreamWindow <= 0 {
+		config.StreamWindow = DefaultStreamWindow
+	}
+	if config.Clock == nil {
+		config.Clock = clock.Real{}
+	}
+	return &Broker{
+		Broker: b,
+		config: config,
+		clock:  config.Clock,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "drift"),
+		state:  StateOK,
+	}
+}
+
+// OnChange registers a handler for state changes
+func (b *Broker) OnChange(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// State returns whether orders are currently refused
+func (b *Broker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Drift returns the latest drift estimate, zero before the first sample
+func (b *Broker) Drift() Sample {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.last
+}
+
+// Point returns the latest estimate as a tsdb point, for writing next to
+// the recorder's snapshots
+func (b *Broker) Point() tsdb.Point {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	exceeded := 0.0
+	if b.state == StateExceeded {
+		exceeded = 1
+	}
+	return tsdb.Point{
+		Measurement: MeasurementClockDrift,
+		Tags:        map[string]string{"broker": b.Broker.Name(), "source": string(b.last.Source)},
+		Fields: map[string]float64{
+			"drift_ms":   float64(b.last.Drift.Microseconds()) / 1000,
+			"latency_ms": float64(b.last.Latency.Microseconds()) / 1000,
+			"exceeded":   exceeded,
+		},
+		Time: b.last.Time,
+	}
+}
+
+// Check measures the drift through the status endpoint. Maintenance
+// answers carry no server time and are skipped.
+func (b *Broker) Check(ctx context.Context) error {
+	status, err := b.Broker.Status(ctx)
+	if err != nil {
+		return err
+	}
+	if status.Maintenance || status.ServerTime.IsZero() {
+		return nil
+	}
+	b.record(ctx, Sample{Source: SourceServerTime, Drift: status.ClockDrift, Latency: status.Latency, Time: b.clock.Now()})
+	return nil
+}
+
+// Observe takes a sample from the exchange timestamp of a stream event
+// received now, e.g. a trade's or depth update's Time
+func (b *Broker) Observe(ctx context.Context, serverTime time.Time) {
+	if serverTime.IsZero() {
+		return
+	}
+	now := b.clock.Now()
+	sample := Sample{Source: SourceStream, Drift: serverTime.Sub(now), Time: now}
+
+	b.mu.Lock()
+	if len(b.stream) < b.config.StreamWindow {
+		b.stream = append(b.stream, sample)
+	} else {
+		b.stream[b.next] = sample
+	}
+	b.next = (b.next + 1) % b.config.StreamWindow
+	// Latency only makes events look older, so the largest drift in the
+	// window is the least delayed estimate
+	for _, s := range b.stream {
+		if s.Drift > sample.Drift {
+			sample.Drift = s.Drift
+		}
+	}
+	b.mu.Unlock()
+
+	b.record(ctx, sample)
+}
+
+// Run checks the server time at the configured interval until the context
+// is canceled. Failed checks are logged and retried on the next tick.
+func (b *Broker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.Check(ctx); err != nil && ctx.Err() == nil {
+			b.log.Warn("clock drift check failed", logging.KeyError, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// record stores the estimate and notifies handlers of transitions
+func (b *Broker) record(ctx context.Context, sample Sample) {
+	state := StateOK
+	if sample.Drift.Abs() > b.config.MaxDrift {
+		state = StateExceeded
+	}
+
+	b.mu.Lock()
+	b.last = sample
+	if b.state == state {
+		b.mu.Unlock()
+		return
+	}
+	b.state = state
+	event := Event{State: state, Sample: sample, Time: sample.Time}
+	handlers := append([]Handler(nil), b.handlers...)
+	b.mu.Unlock()
+
+	if state == StateExceeded {
+		b.log.Error("clock drift exceeds the receive window, refusing orders", "drift", sample.Drift, "source", sample.Source)
+	} else {
+		b.log.Info("clock drift back within the receive window", "drift", sample.Drift, "source", sample.Source)
+	}
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+}
+
+// PlaceOrder refuses orders while the drift exceeds the limit
+func (b *Broker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	b.mu.Lock()
+	state, drift := b.state, b.last.Drift
+	b.mu.Unlock()
+	if state == StateExceeded {
+		return nil, fmt.Errorf("%w: %s drift, limit %s", ErrClockDrift, drift, b.config.MaxDrift)
+	}
+	return b.Broker.PlaceOrder(ctx, req)
+}
@@ -0,0 +1,84 @@
+package drift
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"github.com/agatticelli/trading-go/clock"
+)
+
+func TestBroker_Check(t *testing.T) {
+	tb := brokertest.New()
+	tb.SetPrice("BTC-USDT", 50000)
+	b := Wrap(tb, Config{MaxDrift: time.Second})
+	ctx := context.Background()
+	order := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1}
+
+	var events []Event
+	b.OnChange(func(ctx context.Context, e Event) { events = append(events, e) })
+
+	tb.SetStatus(broker.ExchangeStatus{ClockDrift: -3 * time.Second})
+	if err := b.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if b.State() != StateExceeded || len(events) != 1 || b.Drift().Drift != -3*time.Second {
+		t.Fatalf("state = %s, drift = %+v, events = %d; want exceeded at -3s", b.State(), b.Drift(), len(events))
+	}
+	if _, err := b.PlaceOrder(ctx, order); !errors.Is(err, ErrClockDrift) {
+		t.Errorf("PlaceOrder() while drifting: error = %v, want ErrClockDrift", err)
+	}
+	if len(tb.PlacedOrders()) != 0 {
+		t.Error("order reached the exchange while drifting")
+	}
+	if p := b.Point(); p.Fields["drift_ms"] != -3000 || p.Fields["exceeded"] != 1 || p.Tags["source"] != string(SourceServerTime) {
+		t.Errorf("Point() = %+v", p)
+	}
+
+	// Maintenance answers carry no server time
+	tb.SetStatus(broker.ExchangeStatus{Maintenance: true})
+	b.Check(ctx)
+	if b.State() != StateExceeded {
+		t.Error("maintenance status changed the state")
+	}
+
+	tb.SetStatus(broker.ExchangeStatus{ClockDrift: 200 * time.Millisecond})
+	b.Check(ctx)
+	if b.State() != StateOK || len(events) != 2 {
+		t.Fatalf("state = %s, events = %d; want recovered", b.State(), len(events))
+	}
+	if _, err := b.PlaceOrder(ctx, order); err != nil {
+		t.Errorf("PlaceOrder() after recovery: %v", err)
+	}
+}
+
+func TestBroker_Observe(t *testing.T) {
+	sim := clock.NewSim(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	b := Wrap(brokertest.New(), Config{MaxDrift: time.Second, StreamWindow: 3, Clock: sim})
+	ctx := context.Background()
+
+	// Events arrive 100-400ms after the exchange stamped them
+	for _, delay := range []time.Duration{300, 100, 400} {
+		b.Observe(ctx, sim.Now().Add(-delay*time.Millisecond))
+	}
+	if got := b.Drift(); got.Drift != -100*time.Millisecond || got.Source != SourceStream {
+		t.Errorf("Drift() = %+v, want the least delayed sample, -100ms", got)
+	}
+
+	// The local clock jumps 2s ahead: once the window rolls over, every
+	// event looks 2s old
+	sim.Advance(2 * time.Second)
+	for range 2 {
+		b.Observe(ctx, sim.Now().Add(-2100*time.Millisecond))
+	}
+	if b.State() != StateOK {
+		t.Error("exceeded while a fresh sample is still in the window")
+	}
+	b.Observe(ctx, sim.Now().Add(-2100*time.Millisecond))
+	if b.State() != StateExceeded || b.Drift().Drift != -2100*time.Millisecond {
+		t.Errorf("state = %s, drift = %v; want exceeded at -2.1s", b.State(), b.Drift().Drift)
+	}
+}
@@ -0,0 +1,109 @@
+// Package events supplies scheduled market-moving events, such as economic
+// releases and central bank decisions, to risk rules that keep strategies
+// out of the market around them (see risk.EventGuard).
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Impact is how much an event is expected to move markets
+type Impact int
+
+const (
+	ImpactLow Impact = iota + 1
+	ImpactMedium
+	ImpactHigh
+)
+
+func (i Impact) String() string {
+	switch i {
+	case ImpactLow:
+		return "LOW"
+	case ImpactMedium:
+		return "MEDIUM"
+	case ImpactHigh:
+		return "HIGH"
+	}
+	return "UNKNOWN"
+}
+
+// ParseImpact reads an impact like "High" or "medium"
+func ParseImpact(s string) (Impact, error) {
+	switch strings.ToUpper(s) {
+	case "LOW":
+		return ImpactLow, nil
+	case "MEDIUM":
+		return ImpactMedium, nil
+	case "HIGH":
+		return ImpactHigh, nil
+	}
+	return 0, fmt.Errorf("events: unknown impact %q", s)
+}
+
+// Event is a scheduled release
+type Event struct {
+	Title    string
+	Currency string // Currency or country code the event concerns, e.g. "USD"
+	Impact   Impact
+	Time     time.Time
+}
+
+// Provider supplies the events scheduled in a time range, e.g. from an
+// economic calendar service
+type Provider interface {
+	// Events returns the events at or after from and before to, in
+	// chronological order
+	Events(ctx context.Context, from, to time.Time) ([]Event, error)
+}
+
+// Static is a Provider of a fixed schedule, e.g. loaded from a file with
+// ReadCalendar
+type Static []Event
+
+// Events returns the scheduled events in [from, to)
+func (s Static) Events(ctx context.Context, from, to time.Time) ([]Event, error) {
+	var events []Event
+	for _, e := range s {
+		if !e.Time.Before(from) && e.Time.Before(to) {
+			events = append(events, e)
+		}
+	}
+	slices.SortStableFunc(events, func(a, b Event) int { return a.Time.Compare(b.Time) })
+	return events, nil
+}
+
+// calendarEntry is one event of a weekly calendar export
+type calendarEntry struct {
+	Title   string    `json:"title"`
+	Country string    `json:"country"`
+	Date    time.Time `json:"date"`
+	Impact  string    `json:"impact"`
+}
+
+// ReadCalendar reads a weekly economic calendar export, a JSON array of
+// {"title", "country", "date" (RFC 3339), "impact"} objects as published
+// by common forex calendars. Holidays and entries of unknown impact are
+// skipped.
+func ReadCalendar(r io.Reader) (Static, error) {
+	var entries []calendarEntry
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return nil, fmt.Errorf("events: reading calendar: %w", err)
+	}
+
+	schedule := make(Static, 0, len(entries))
+	for _, entry := range entries {
+		impact, err := ParseImpact(entry.Impact)
+		if err != nil {
+			continue
+		}
+		schedule = append(schedule, Event{Title: entry.Title, Currency: entry.Country, Impact: impact, Time: entry.Date})
+	}
+	return schedule, nil
+}
@@ -0,0 +1,42 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCalendar(t *testing.T) {
+	calendar := `[
+		{"title":"Non-Farm Employment Change","country":"USD","date":"2026-03-06T08:30:00-05:00","impact":"High","forecast":"160K"},
+		{"title":"Bank Holiday","country":"JPY","date":"2026-03-20T00:00:00+09:00","impact":"Holiday"},
+		{"title":"ECB Press Conference","country":"EUR","date":"2026-03-05T08:45:00-05:00","impact":"High"},
+		{"title":"Trade Balance","country":"CAD","date":"2026-03-06T08:30:00-05:00","impact":"Medium"}]`
+	schedule, err := ReadCalendar(strings.NewReader(calendar))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(schedule) != 3 || schedule[0].Impact != ImpactHigh || schedule[0].Currency != "USD" || schedule[2].Impact != ImpactMedium {
+		t.Fatalf("ReadCalendar() = %+v", schedule)
+	}
+
+	from := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	got, err := schedule.Events(context.Background(), from, from.AddDate(0, 0, 1))
+	if err != nil || len(got) != 2 || !got[0].Time.Equal(time.Date(2026, 3, 6, 13, 30, 0, 0, time.UTC)) {
+		t.Errorf("Events(Mar 6) = %+v, %v, want the two releases at 13:30 UTC", got, err)
+	}
+
+	if _, err := ReadCalendar(strings.NewReader(`{"title":"x"}`)); err == nil {
+		t.Error("ReadCalendar(object) succeeded")
+	}
+}
+
+func TestParseImpact(t *testing.T) {
+	if i, err := ParseImpact("medium"); err != nil || i != ImpactMedium || i.String() != "MEDIUM" {
+		t.Errorf("ParseImpact(medium) = %v, %v", i, err)
+	}
+	if _, err := ParseImpact("Holiday"); err == nil {
+		t.Error("ParseImpact(Holiday) succeeded")
+	}
+}
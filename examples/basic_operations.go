@@ -3,10 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
 
 	"github.com/agatticelli/trading-go/bingx"
 	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
 )
 
 // This example demonstrates basic broker operations:
@@ -35,9 +37,12 @@ func main() {
 		return
 	}
 
+	// Errors and client activity go to stderr with secrets redacted
+	logger := slog.New(logging.Redact(slog.NewTextHandler(os.Stderr, nil)))
+
 	// Create BingX client in demo mode
 	fmt.Println("Creating BingX client (demo mode)...")
-	client := bingx.NewClient(apiKey, secretKey, true)
+	client := bingx.NewClient(apiKey, secretKey, true, bingx.WithLogger(logger))
 	fmt.Printf("✅ Client created: %s\n", client.Name())
 	fmt.Printf("   Max Leverage: %dx\n", client.SupportedFeatures().MaxLeverage)
 	fmt.Printf("   Trailing Stops: %v\n", client.SupportedFeatures().TrailingStop)
@@ -49,7 +54,7 @@ func main() {
 	fmt.Println("📊 Fetching account balance...")
 	balance, err := client.GetBalance(ctx)
 	if err != nil {
-		fmt.Printf("❌ Error: %v\n", err)
+		logger.Error("fetching balance failed", logging.KeyError, err)
 		return
 	}
 
@@ -67,7 +72,7 @@ func main() {
 	fmt.Printf("📈 Fetching current price for %s...\n", symbol)
 	price, err := client.GetCurrentPrice(ctx, symbol)
 	if err != nil {
-		fmt.Printf("❌ Error: %v\n", err)
+		logger.Error("fetching price failed", logging.KeySymbol, symbol, logging.KeyError, err)
 	} else {
 		fmt.Printf("✅ Current Price: $%.2f\n", price)
 	}
@@ -77,7 +82,7 @@ func main() {
 	fmt.Println("📋 Fetching open positions...")
 	positions, err := client.GetPositions(ctx, nil)
 	if err != nil {
-		fmt.Printf("❌ Error: %v\n", err)
+		logger.Error("fetching positions failed", logging.KeyError, err)
 		return
 	}
 
@@ -103,7 +108,7 @@ func main() {
 	fmt.Println("📝 Fetching open orders...")
 	orders, err := client.GetOrders(ctx, &broker.OrderFilter{})
 	if err != nil {
-		fmt.Printf("❌ Error: %v\n", err)
+		logger.Error("fetching orders failed", logging.KeyError, err)
 		return
 	}
 
@@ -4,16 +4,23 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
+	"os"
 
-	"github.com/agatticelli/trading-go/broker"
 	"github.com/agatticelli/trading-go/bingx"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
 )
 
 // This example demonstrates robust error handling
 func main() {
 	fmt.Println("=== Error Handling Example ===\n")
 
-	client := bingx.NewClient("demo-key", "demo-secret", true)
+	// Log with secrets redacted; helpers below use the default logger
+	logger := slog.New(logging.Redact(slog.NewTextHandler(os.Stderr, nil)))
+	slog.SetDefault(logger)
+
+	client := bingx.NewClient("demo-key", "demo-secret", true, bingx.WithLogger(logger))
 	ctx := context.Background()
 
 	// Example 1: Handling typed errors
@@ -29,7 +36,7 @@ func main() {
 
 	result, err := client.PlaceOrder(ctx, order)
 	if err != nil {
-		handleOrderError(err)
+		handleOrderError(order, err)
 	} else {
 		fmt.Printf("✅ Order placed: %s\n", result.ID)
 	}
@@ -46,7 +53,7 @@ func main() {
 		if errors.Is(err, context.Canceled) {
 			fmt.Println("❌ Operation canceled by user")
 		} else {
-			fmt.Printf("❌ Error: %v\n", err)
+			logger.Error("fetching balance failed", logging.KeyError, err)
 		}
 	}
 	fmt.Println()
@@ -64,7 +71,7 @@ func main() {
 			fmt.Println("❌ API error occurred")
 			fmt.Println("   Check exchange status and try again")
 		default:
-			fmt.Printf("❌ Unexpected error: %v\n", err)
+			logger.Error("fetching price failed", logging.KeySymbol, "INVALID-SYMBOL", logging.KeyError, err)
 		}
 	}
 	fmt.Println()
@@ -81,7 +88,7 @@ func main() {
 	fmt.Println("   - Log errors with sufficient context")
 }
 
-func handleOrderError(err error) {
+func handleOrderError(order *broker.OrderRequest, err error) {
 	switch {
 	case errors.Is(err, broker.ErrInsufficientBalance):
 		fmt.Println("❌ Insufficient balance")
@@ -112,7 +119,7 @@ func handleOrderError(err error) {
 		fmt.Println("   Check exchange status and retry")
 
 	default:
-		fmt.Printf("❌ Unexpected error: %v\n", err)
+		slog.Error("placing order failed", logging.KeySymbol, order.Symbol, logging.KeyError, err)
 		fmt.Println("   Contact support if error persists")
 	}
 }
@@ -133,8 +140,7 @@ func demonstrateRetryPattern(client *bingx.Client) {
 		}
 
 		if !isRetryable(err) {
-			fmt.Println("❌ Non-retryable error")
-			fmt.Printf("Error: %v\n", err)
+			slog.Error("non-retryable error", "attempt", attempt, logging.KeyError, err)
 			return
 		}
 
@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"fmt"
+	"log/slog"
+	"os"
 
-	"github.com/agatticelli/trading-go/broker"
 	"github.com/agatticelli/trading-go/bingx"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
 )
 
 // This example demonstrates different order types
@@ -18,7 +21,8 @@ func main() {
 	// Example client setup
 	apiKey := "your-api-key"
 	secretKey := "your-secret-key"
-	client := bingx.NewClient(apiKey, secretKey, true) // Demo mode
+	logger := slog.New(logging.Redact(slog.NewTextHandler(os.Stderr, nil)))
+	client := bingx.NewClient(apiKey, secretKey, true, bingx.WithLogger(logger)) // Demo mode
 	ctx := context.Background()
 
 	// Example 1: Market Order
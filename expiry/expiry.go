@@ -0,0 +1,270 @@
+// Package expiry cancels resting orders that outlive their time to live.
+// BingX has no native good-till-date orders, so a Broker wrapper tracks the
+// orders placed through it and cancels those still open at their deadline:
+// after a TTL, at the close of the candle they were placed in, or at the
+// ExpireTime of GTD orders the exchange can't expire itself.
+package expiry
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/clock"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// DefaultInterval is how often Run checks deadlines by default
+const DefaultInterval = time.Second
+
+// Config configures a Broker. Without TTL or CandleClose only orders with
+// their own deadline (WithTTL, GTD) are watched.
+type Config struct {
+	// TTL cancels limit orders this long after they are placed
+	TTL time.Duration
+	// CandleClose cancels limit orders at the close of the candle of this
+	// interval they were placed in (e.g. time.Hour), candles being aligned
+	// to UTC. With TTL too, the earlier deadline wins.
+	CandleClose time.Duration
+	// Interval between checks in Run (default 1s)
+	Interval time.Duration
+	// Clock decides when deadlines pass (default: the system clock)
+	Clock  clock.Clock
+	Logger *slog.Logger
+}
+
+// Event reports an order canceled, or failed to cancel, at its deadline
+type Event struct {
+	Symbol   string
+	OrderID  string
+	PlacedAt time.Time
+	Deadline time.Time
+	Time     time.Time
+	Err      error // Cancel failure; the order is retried on the next check
+}
+
+// Handler receives expiry events
+type Handler func(Event)
+
+type ttlKey struct{}
+
+// WithTTL returns a context that makes PlaceOrder expire the order ttl
+// after it is placed, whatever its type, overriding the configured
+// deadlines. A negative ttl exempts the order.
+func WithTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, ttlKey{}, ttl)
+}
+
+// Broker wraps a broker.Broker and cancels the orders placed through it at
+// their deadline. Deadlines are checked by Check or Run.
+type Broker struct {
+	broker.Broker
+	config Config
+	log    *slog.Logger
+
+	mu       sync.Mutex
+	orders   map[string]*watched // By order ID
+	handlers []Handler
+}
+
+type watched struct {
+	symbol   string
+	placedAt time.Time
+	deadline time.Time
+}
+
+// Wrap returns b with order expiry
+func Wrap(b broker.Broker, config Config) *Broker {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Clock == nil {
+		config.Clock = clock.Real{}
+	}
+	return &Broker{
+		Broker: b,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "expiry"),
+		orders: make(map[string]*watched),
+	}
+}
+
+// OnExpire registers a handler for expiry events
+func (b *Broker) OnExpire(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// PlaceOrder places the order and watches it if it has a deadline. GTD
+// orders are sent as GTC and expired locally when the exchange doesn't
+// support GTD.
+func (b *Broker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	now := b.config.Clock.Now()
+	deadline := b.deadline(ctx, req, now)
+
+	if req.TimeInForce == broker.TimeInForceGTD && !b.Broker.SupportedFeatures().SupportsTimeInForce(broker.TimeInForceGTD) {
+		opts := broker.OrderOptionsFrom(ctx)
+		if opts.ExpireTime.IsZero() || !opts.ExpireTime.After(now) {
+			// Let the exchange reject it with the usual error
+			return b.Broker.PlaceOrder(ctx, req)
+		}
+		if deadline.IsZero() || opts.ExpireTime.Before(deadline) {
+			deadline = opts.ExpireTime
+		}
+		gtc := *req
+		gtc.TimeInForce = broker.TimeInForceGTC
+		opts.ExpireTime = time.Time{}
+		req, ctx = &gtc, broker.WithOrderOptions(ctx, opts)
+	}
+
+	order, err := b.Broker.PlaceOrder(ctx, req)
+	if err != nil || deadline.IsZero() || order == nil || final(order.Status) {
+		return order, err
+	}
+
+	b.mu.Lock()
+	b.orders[order.ID] = &watched{symbol: order.Symbol, placedAt: now, deadline: deadline}
+	b.mu.Unlock()
+	return order, nil
+}
+
+// deadline returns when an order placed now expires, zero for never
+func (b *Broker) deadline(ctx context.Context, req *broker.OrderRequest, now time.Time) time.Time {
+	if ttl, ok := ctx.Value(ttlKey{}).(time.Duration); ok {
+		if ttl < 0 {
+			return time.Time{}
+		}
+		return now.Add(ttl)
+	}
+	if req.Type != broker.OrderTypeLimit && req.Type != broker.OrderTypeTriggerLimit {
+		return time.Time{}
+	}
+
+	var deadline time.Time
+	if b.config.TTL > 0 {
+		deadline = now.Add(b.config.TTL)
+	}
+	if b.config.CandleClose > 0 {
+		candleEnd := now.Truncate(b.config.CandleClose).Add(b.config.CandleClose)
+		if deadline.IsZero() || candleEnd.Before(deadline) {
+			deadline = candleEnd
+		}
+	}
+	return deadline
+}
+
+// CancelOrder cancels the order and stops watching it
+func (b *Broker) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	err := b.Broker.CancelOrder(ctx, symbol, orderID)
+	if err == nil || errors.Is(err, broker.ErrOrderNotFound) {
+		b.forget(orderID)
+	}
+	return err
+}
+
+// CancelAllOrders cancels the symbol's orders (all symbols if empty) and
+// stops watching them
+func (b *Broker) CancelAllOrders(ctx context.Context, symbol string) error {
+	if err := b.Broker.CancelAllOrders(ctx, symbol); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for id, w := range b.orders {
+		if symbol == "" || w.symbol == symbol {
+			delete(b.orders, id)
+		}
+	}
+	return nil
+}
+
+func (b *Broker) forget(orderID string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.orders, orderID)
+}
+
+// Watched returns the number of orders waiting for their deadline
+func (b *Broker) Watched() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.orders)
+}
+
+// Check cancels the watched orders whose deadline has passed and returns
+// an event for each, earliest deadline first. Orders already filled or
+// canceled (the exchange no longer finds them) are dropped without an
+// event; other cancel failures are reported and retried on the next check.
+func (b *Broker) Check(ctx context.Context) []Event {
+	now := b.config.Clock.Now()
+
+	type dueOrder struct {
+		id string
+		watched
+	}
+	b.mu.Lock()
+	var due []dueOrder
+	for id, w := range b.orders {
+		if !w.deadline.After(now) {
+			due = append(due, dueOrder{id, *w})
+		}
+	}
+	b.mu.Unlock()
+	slices.SortFunc(due, func(x, y dueOrder) int {
+		return cmp.Or(x.deadline.Compare(y.deadline), cmp.Compare(x.id, y.id))
+	})
+
+	var events []Event
+	for _, w := range due {
+		err := b.Broker.CancelOrder(ctx, w.symbol, w.id)
+		if errors.Is(err, broker.ErrOrderNotFound) {
+			b.forget(w.id)
+			continue
+		}
+		if err == nil {
+			b.forget(w.id)
+			b.log.Info("order expired", logging.KeySymbol, w.symbol, logging.KeyOrderID, w.id, "deadline", w.deadline)
+		} else {
+			b.log.Error("expire order failed", logging.KeySymbol, w.symbol, logging.KeyOrderID, w.id, logging.KeyError, err)
+		}
+		events = append(events, Event{Symbol: w.symbol, OrderID: w.id, PlacedAt: w.placedAt, Deadline: w.deadline, Time: now, Err: err})
+	}
+
+	b.mu.Lock()
+	handlers := append([]Handler(nil), b.handlers...)
+	b.mu.Unlock()
+	for _, event := range events {
+		for _, h := range handlers {
+			h(event)
+		}
+	}
+	return events
+}
+
+// Run checks deadlines every interval until ctx is canceled
+func (b *Broker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			b.Check(ctx)
+		}
+	}
+}
+
+func final(status broker.OrderStatus) bool {
+	switch status {
+	case broker.OrderStatusFilled, broker.OrderStatusCanceled, broker.OrderStatusRejected, broker.OrderStatusExpired:
+		return true
+	}
+	return false
+}
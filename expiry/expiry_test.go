@@ -0,0 +1,141 @@
+package expiry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"github.com/agatticelli/trading-go/clock"
+)
+
+var start = time.Date(2024, 1, 1, 10, 20, 0, 0, time.UTC)
+
+func setup(config Config) (*Broker, *brokertest.Broker, *clock.Sim) {
+	sim := clock.NewSim(start)
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	config.Clock = sim
+	return Wrap(b, config), b, sim
+}
+
+func limit(price float64) *broker.OrderRequest {
+	return &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 0.1, Price: price}
+}
+
+func openOrders(t *testing.T, b broker.Broker) int {
+	t.Helper()
+	orders, err := b.GetOrders(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(orders)
+}
+
+func TestBroker_TTL(t *testing.T) {
+	ctx := context.Background()
+	w, b, sim := setup(Config{TTL: 5 * time.Minute})
+
+	var got []Event
+	w.OnExpire(func(e Event) { got = append(got, e) })
+
+	order, err := w.PlaceOrder(ctx, limit(49000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 0.1})
+	w.PlaceOrder(WithTTL(ctx, -1), limit(48000)) // Exempt
+	if w.Watched() != 1 {
+		t.Fatalf("Watched() = %d, want only the limit order with a TTL", w.Watched())
+	}
+
+	sim.Advance(4 * time.Minute)
+	if events := w.Check(ctx); len(events) != 0 {
+		t.Errorf("Check() before the deadline = %+v", events)
+	}
+
+	sim.Advance(time.Minute)
+	events := w.Check(ctx)
+	if len(events) != 1 || events[0].OrderID != order.ID || events[0].Err != nil || !events[0].Deadline.Equal(start.Add(5*time.Minute)) {
+		t.Fatalf("Check() = %+v, want the limit order canceled at its deadline", events)
+	}
+	if len(got) != 1 || w.Watched() != 0 || openOrders(t, b) != 1 {
+		t.Errorf("handler got %d events, %d watched, %d open; want 1, 0 and the exempt order", len(got), w.Watched(), openOrders(t, b))
+	}
+}
+
+func TestBroker_CandleClose(t *testing.T) {
+	ctx := context.Background()
+	w, _, sim := setup(Config{CandleClose: time.Hour, TTL: 2 * time.Hour})
+
+	w.PlaceOrder(ctx, limit(49000))
+	w.PlaceOrder(WithTTL(ctx, 10*time.Minute), limit(48000))
+
+	sim.Set(start.Add(10 * time.Minute))
+	if events := w.Check(ctx); len(events) != 1 {
+		t.Fatalf("Check() at the TTL override = %+v, want 1 event", events)
+	}
+	sim.Set(time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC))
+	if events := w.Check(ctx); len(events) != 1 || !events[0].Deadline.Equal(sim.Now()) {
+		t.Errorf("Check() at the candle close = %+v, want the other order", events)
+	}
+}
+
+func TestBroker_GTDEmulation(t *testing.T) {
+	ctx := context.Background()
+	w, b, sim := setup(Config{})
+	b.SetFeatures(broker.Features{TimeInForces: []broker.TimeInForce{broker.TimeInForceGTC}})
+
+	req := limit(49000)
+	req.TimeInForce = broker.TimeInForceGTD
+	gtd := broker.WithOrderOptions(ctx, broker.OrderOptions{ExpireTime: start.Add(time.Hour)})
+	if _, err := w.PlaceOrder(gtd, req); err != nil {
+		t.Fatalf("PlaceOrder(GTD) error = %v, want it sent as GTC", err)
+	}
+	if placed := b.PlacedOrders(); placed[0].TimeInForce != broker.TimeInForceGTC || req.TimeInForce != broker.TimeInForceGTD {
+		t.Errorf("sent %s, caller's request now %s; want GTC sent and the request untouched", placed[0].TimeInForce, req.TimeInForce)
+	}
+
+	sim.Advance(time.Hour)
+	if events := w.Check(ctx); len(events) != 1 || openOrders(t, b) != 0 {
+		t.Errorf("Check() at the expire time = %+v", events)
+	}
+
+	expired := broker.WithOrderOptions(ctx, broker.OrderOptions{ExpireTime: start})
+	if _, err := w.PlaceOrder(expired, req); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("PlaceOrder(past GTD) error = %v, want the exchange's rejection", err)
+	}
+}
+
+func TestBroker_Forget(t *testing.T) {
+	ctx := context.Background()
+	w, b, sim := setup(Config{TTL: time.Minute})
+
+	first, _ := w.PlaceOrder(ctx, limit(49000))
+	w.PlaceOrder(ctx, limit(48000))
+	third, _ := w.PlaceOrder(ctx, limit(47000))
+
+	if err := w.CancelOrder(ctx, "BTC-USDT", first.ID); err != nil || w.Watched() != 2 {
+		t.Fatalf("CancelOrder() error = %v, %d watched", err, w.Watched())
+	}
+
+	// Filled or canceled elsewhere: dropped silently
+	b.CancelOrder(ctx, "BTC-USDT", third.ID)
+	// A failing cancel is reported and retried
+	sim.Advance(time.Minute)
+	b.Err = broker.ErrRateLimited
+	if events := w.Check(ctx); len(events) != 2 || !errors.Is(events[0].Err, broker.ErrRateLimited) || w.Watched() != 2 {
+		t.Fatalf("Check() failing = %+v, %d watched", events, w.Watched())
+	}
+	b.Err = nil
+	if events := w.Check(ctx); len(events) != 1 || events[0].Err != nil || w.Watched() != 0 {
+		t.Errorf("Check() retry = %+v, %d watched", events, w.Watched())
+	}
+
+	w.PlaceOrder(ctx, limit(46000))
+	if err := w.CancelAllOrders(ctx, "BTC-USDT"); err != nil || w.Watched() != 0 {
+		t.Errorf("CancelAllOrders() error = %v, %d watched", err, w.Watched())
+	}
+}
@@ -0,0 +1,229 @@
+// Package framelog records raw WebSocket frames to rotating, gzip-compressed
+// files and reads them back, so production stream parsing bugs can be
+// reproduced by replaying exactly what the exchange sent.
+//
+// Each file holds one JSON object per frame. Files are named
+// <prefix>-<UTC start time>.jsonl.gz and sort chronologically.
+package framelog
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultPrefix is the default file name prefix
+	DefaultPrefix = "frames"
+	// DefaultMaxBytes is the default uncompressed size at which a file is
+	// rotated
+	DefaultMaxBytes = 64 << 20
+
+	suffix     = ".jsonl.gz"
+	timeLayout = "20060102T150405.000000000Z"
+)
+
+// Frame is a WebSocket frame as received
+type Frame struct {
+	Time   time.Time `json:"time"`
+	Stream string    `json:"stream"` // Subscription the frame arrived on, e.g. BTC-USDT@trade
+	Binary bool      `json:"binary,omitempty"`
+	Data   []byte    `json:"data"`
+}
+
+// Config configures a Writer
+type Config struct {
+	// Dir is the directory files are written to; it is created if missing
+	Dir string
+	// Prefix starts every file name (default "frames")
+	Prefix string
+	// MaxBytes is the uncompressed size at which a file is rotated
+	// (default 64 MiB)
+	MaxBytes int64
+	// MaxFiles is the number of files kept; the oldest are deleted on
+	// rotation (0 = keep all)
+	MaxFiles int
+}
+
+// Writer appends frames to the current file, rotating by size. It is safe
+// for concurrent use.
+type Writer struct {
+	config Config
+
+	mu      sync.Mutex
+	file    *os.File
+	gz      *gzip.Writer
+	written int64
+	now     func() time.Time
+}
+
+// Open creates a writer. The first file is created on the first frame.
+func Open(config Config) (*Writer, error) {
+	if config.Dir == "" {
+		return nil, errors.New("framelog: missing directory")
+	}
+	if config.Prefix == "" {
+		config.Prefix = DefaultPrefix
+	}
+	if config.MaxBytes <= 0 {
+		config.MaxBytes = DefaultMaxBytes
+	}
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("framelog: %w", err)
+	}
+	return &Writer{config: config, now: time.Now}, nil
+}
+
+// Record appends a frame
+func (w *Writer) Record(f Frame) error {
+	line, err := json.Marshal(f)
+	if err != nil {
+		return fmt.Errorf("framelog: %w", err)
+	}
+	line = append(line, '\n')
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.gz == nil || w.written >= w.config.MaxBytes {
+		if err := w.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := w.gz.Write(line)
+	w.written += int64(n)
+	if err != nil {
+		return fmt.Errorf("framelog: %w", err)
+	}
+	return nil
+}
+
+// Flush writes buffered frames to the current file, so they survive a crash
+func (w *Writer) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.gz == nil {
+		return nil
+	}
+	return w.gz.Flush()
+}
+
+// Close finishes the current file
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.closeFile()
+}
+
+// rotate closes the current file, starts a new one and deletes files beyond
+// MaxFiles. Callers must hold w.mu.
+func (w *Writer) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+
+	name := fmt.Sprintf("%s-%s%s", w.config.Prefix, w.now().UTC().Format(timeLayout), suffix)
+	file, err := os.OpenFile(filepath.Join(w.config.Dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("framelog: %w", err)
+	}
+	w.file = file
+	w.gz = gzip.NewWriter(file)
+	w.written = 0
+
+	if w.config.MaxFiles > 0 {
+		files, err := Files(w.config.Dir, w.config.Prefix)
+		if err != nil {
+			return err
+		}
+		for len(files) > w.config.MaxFiles {
+			os.Remove(files[0])
+			files = files[1:]
+		}
+	}
+	return nil
+}
+
+// closeFile finishes the current file, if any. Callers must hold w.mu.
+func (w *Writer) closeFile() error {
+	if w.gz == nil {
+		return nil
+	}
+	gzErr := w.gz.Close()
+	fileErr := w.file.Close()
+	w.gz, w.file = nil, nil
+	if err := errors.Join(gzErr, fileErr); err != nil {
+		return fmt.Errorf("framelog: %w", err)
+	}
+	return nil
+}
+
+// Files returns the paths of the frame files in dir with prefix ("" =
+// DefaultPrefix), oldest first
+func Files(dir, prefix string) ([]string, error) {
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("framelog: %w", err)
+	}
+
+	var files []string
+	for _, e := range entries {
+		if name := e.Name(); !e.IsDir() && strings.HasPrefix(name, prefix+"-") && strings.HasSuffix(name, suffix) {
+			files = append(files, filepath.Join(dir, name))
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+// Read calls fn for every frame in r, a file written by Writer, until fn
+// returns an error. A file cut short by a crash yields its flushed frames
+// and then io.ErrUnexpectedEOF.
+func Read(r io.Reader, fn func(Frame) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("framelog: %w", err)
+	}
+	defer gz.Close()
+
+	dec := json.NewDecoder(gz)
+	for {
+		var f Frame
+		if err := dec.Decode(&f); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("framelog: %w", err)
+		}
+		if err := fn(f); err != nil {
+			return err
+		}
+	}
+}
+
+// ReadFiles calls fn for every frame in the files, in order
+func ReadFiles(paths []string, fn func(Frame) error) error {
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("framelog: %w", err)
+		}
+		err = Read(file, fn)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+	}
+	return nil
+}
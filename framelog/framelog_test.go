@@ -0,0 +1,189 @@
+package framelog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// clock returns a time source advancing one second per call, so rotated
+// files get distinct names
+func clock() func() time.Time {
+	t := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	return func() time.Time {
+		t = t.Add(time.Second)
+		return t
+	}
+}
+
+func frame(i int) Frame {
+	return Frame{
+		Time:   time.Date(2024, 1, 2, 3, 4, 5, i, time.UTC),
+		Stream: "BTC-USDT@trade",
+		Binary: i%2 == 0,
+		Data:   []byte(fmt.Sprintf(`{"n":%d}`, i)),
+	}
+}
+
+func readAll(t *testing.T, dir string) []Frame {
+	t.Helper()
+	files, err := Files(dir, "")
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	var frames []Frame
+	if err := ReadFiles(files, func(f Frame) error {
+		frames = append(frames, f)
+		return nil
+	}); err != nil {
+		t.Fatalf("ReadFiles() error = %v", err)
+	}
+	return frames
+}
+
+func TestWriter_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	for i := range 3 {
+		if err := w.Record(frame(i)); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	frames := readAll(t, dir)
+	if len(frames) != 3 {
+		t.Fatalf("read %d frames, want 3", len(frames))
+	}
+	for i, f := range frames {
+		want := frame(i)
+		if !f.Time.Equal(want.Time) || f.Stream != want.Stream || f.Binary != want.Binary || !bytes.Equal(f.Data, want.Data) {
+			t.Errorf("frame %d = %+v, want %+v", i, f, want)
+		}
+	}
+}
+
+func TestWriter_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(Config{Dir: dir, MaxBytes: 1})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	w.now = clock()
+	for i := range 4 {
+		if err := w.Record(frame(i)); err != nil {
+			t.Fatalf("Record() error = %v", err)
+		}
+	}
+	w.Close()
+
+	files, _ := Files(dir, "")
+	if len(files) != 4 {
+		t.Fatalf("got %d files, want one per frame: %v", len(files), files)
+	}
+	frames := readAll(t, dir)
+	for i, f := range frames {
+		if string(f.Data) != string(frame(i).Data) {
+			t.Errorf("frame %d data = %s, want %s", i, f.Data, frame(i).Data)
+		}
+	}
+}
+
+func TestWriter_MaxFiles(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(Config{Dir: dir, MaxBytes: 1, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	w.now = clock()
+	for i := range 5 {
+		w.Record(frame(i))
+	}
+	w.Close()
+
+	frames := readAll(t, dir)
+	if len(frames) != 2 || string(frames[0].Data) != `{"n":3}` || string(frames[1].Data) != `{"n":4}` {
+		t.Errorf("kept frames = %+v, want the newest two", frames)
+	}
+}
+
+func TestWriter_Flush(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Open(Config{Dir: dir})
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer w.Close()
+	w.Record(frame(1))
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	// A file that was never closed still yields its flushed frames
+	files, _ := Files(dir, "")
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var frames []Frame
+	err = Read(bytes.NewReader(data), func(f Frame) error {
+		frames = append(frames, f)
+		return nil
+	})
+	if len(frames) != 1 {
+		t.Errorf("read %d frames, want 1", len(frames))
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Read() error = %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestFiles_Prefix(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"frames-2.jsonl.gz", "frames-1.jsonl.gz", "other-1.jsonl.gz", "frames-3.txt"} {
+		os.WriteFile(filepath.Join(dir, name), nil, 0o644)
+	}
+	files, err := Files(dir, "")
+	if err != nil {
+		t.Fatalf("Files() error = %v", err)
+	}
+	want := []string{filepath.Join(dir, "frames-1.jsonl.gz"), filepath.Join(dir, "frames-2.jsonl.gz")}
+	if fmt.Sprint(files) != fmt.Sprint(want) {
+		t.Errorf("Files() = %v, want %v", files, want)
+	}
+}
+
+func TestRead_StopsOnHandlerError(t *testing.T) {
+	dir := t.TempDir()
+	w, _ := Open(Config{Dir: dir})
+	w.Record(frame(1))
+	w.Record(frame(2))
+	w.Close()
+
+	files, _ := Files(dir, "")
+	stop := errors.New("stop")
+	calls := 0
+	err := ReadFiles(files, func(Frame) error {
+		calls++
+		return stop
+	})
+	if !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("ReadFiles() error = %v after %d calls, want %v after 1", err, calls, stop)
+	}
+}
+
+func TestOpen_MissingDir(t *testing.T) {
+	if _, err := Open(Config{}); err == nil {
+		t.Error("Open() error = nil, want error")
+	}
+}
@@ -0,0 +1,221 @@
+// Package funding helps run funding-rate carry trades: short the
+// contract paying the higher funding, hedge it with an equal notional long
+// on another venue (or spot), and collect the rate difference.
+package funding
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Leg is one side of a carry trade. Brokers that don't implement
+// broker.FundingRateProvider (e.g. spot) are treated as paying no funding.
+type Leg struct {
+	Broker broker.Broker
+	Symbol string
+}
+
+// RollbackTimeout bounds closing the first leg when the second fails to open
+const RollbackTimeout = 30 * time.Second
+
+// Config sets entry and exit thresholds on the annualized carry
+type Config struct {
+	EntryAPR float64 // Enter when carry reaches this APR, e.g. 0.15
+	ExitAPR  float64 // Exit when carry falls to this APR, e.g. 0.03
+}
+
+// Quote is a snapshot of both legs
+type Quote struct {
+	Time   time.Time
+	APRA   float64 // Annualized funding of leg A
+	APRB   float64 // Annualized funding of leg B
+	PriceA float64
+	PriceB float64
+
+	// ShortA is true when leg A pays more funding and should be shorted
+	ShortA bool
+	// CarryAPR is the annualized funding collected by the hedged pair
+	CarryAPR float64
+	// Basis is (PriceA - PriceB) / PriceB
+	Basis float64
+}
+
+// Signal is a trading decision derived from a Quote
+type Signal int
+
+const (
+	SignalHold Signal = iota
+	SignalEnter
+	SignalExit
+)
+
+func (s Signal) String() string {
+	switch s {
+	case SignalEnter:
+		return "ENTER"
+	case SignalExit:
+		return "EXIT"
+	}
+	return "HOLD"
+}
+
+// Arbitrage evaluates and executes a carry trade between two legs
+type Arbitrage struct {
+	a, b   Leg
+	config Config
+}
+
+// New creates a carry trade helper for legs a and b
+func New(a, b Leg, config Config) *Arbitrage {
+	return &Arbitrage{a: a, b: b, config: config}
+}
+
+// Quote fetches prices and funding rates for both legs
+func (x *Arbitrage) Quote(ctx context.Context) (*Quote, error) {
+	aprA, priceA, err := legState(ctx, x.a)
+	if err != nil {
+		return nil, fmt.Errorf("leg %s/%s: %w", x.a.Broker.Name(), x.a.Symbol, err)
+	}
+	aprB, priceB, err := legState(ctx, x.b)
+	if err != nil {
+		return nil, fmt.Errorf("leg %s/%s: %w", x.b.Broker.Name(), x.b.Symbol, err)
+	}
+
+	q := &Quote{
+		Time:     time.Now(),
+		APRA:     aprA,
+		APRB:     aprB,
+		PriceA:   priceA,
+		PriceB:   priceB,
+		ShortA:   aprA >= aprB,
+		CarryAPR: math.Abs(aprA - aprB),
+	}
+	if priceB != 0 {
+		q.Basis = (priceA - priceB) / priceB
+	}
+	return q, nil
+}
+
+// legState returns the annualized funding and current price of a leg
+func legState(ctx context.Context, leg Leg) (apr, price float64, err error) {
+	if provider, ok := leg.Broker.(broker.FundingRateProvider); ok {
+		rate, err := provider.GetFundingRate(ctx, leg.Symbol)
+		if err == nil {
+			apr = rate.APR()
+			price = rate.MarkPrice
+		} else if !errors.Is(err, broker.ErrNotSupported) {
+			return 0, 0, err
+		}
+	}
+
+	if price == 0 {
+		if price, err = leg.Broker.GetCurrentPrice(ctx, leg.Symbol); err != nil {
+			return 0, 0, err
+		}
+	}
+	return apr, price, nil
+}
+
+// Signal decides whether to enter, exit or hold given whether a trade is open
+func (x *Arbitrage) Signal(q *Quote, open bool) Signal {
+	if !open && q.CarryAPR >= x.config.EntryAPR {
+		return SignalEnter
+	}
+	if open && q.CarryAPR <= x.config.ExitAPR {
+		return SignalExit
+	}
+	return SignalHold
+}
+
+// HedgeSizes returns the leg sizes that put the same notional on each side,
+// leaving the pair price-neutral so only funding remains
+func HedgeSizes(notional, priceA, priceB float64) (sizeA, sizeB float64) {
+	if priceA <= 0 || priceB <= 0 {
+		return 0, 0
+	}
+	return notional / priceA, notional / priceB
+}
+
+// Execution records the orders of an open carry trade
+type Execution struct {
+	Short      Leg
+	Long       Leg
+	ShortOrder *broker.Order
+	LongOrder  *broker.Order
+}
+
+// ExecutionError reports a failed paired entry. When the second leg fails
+// the first is rolled back; RollbackErr is set if that also failed and a
+// naked position may remain.
+type ExecutionError struct {
+	Leg         Leg
+	Err         error
+	RollbackErr error
+}
+
+func (e *ExecutionError) Error() string {
+	msg := fmt.Sprintf("funding: %s order on %s failed: %v", e.Leg.Symbol, e.Leg.Broker.Name(), e.Err)
+	if e.RollbackErr != nil {
+		msg += fmt.Sprintf(" (rollback failed: %v)", e.RollbackErr)
+	}
+	return msg
+}
+
+func (e *ExecutionError) Unwrap() error {
+	return e.Err
+}
+
+// Open enters the trade at the given notional per leg with market orders:
+// the short leg first, then the long hedge. If the hedge fails the short is
+// closed again so no unhedged exposure is left behind, even when ctx was
+// canceled in between.
+func (x *Arbitrage) Open(ctx context.Context, q *Quote, notional float64) (*Execution, error) {
+	sizeA, sizeB := HedgeSizes(notional, q.PriceA, q.PriceB)
+
+	exec := &Execution{Short: x.a, Long: x.b}
+	shortSize, longSize := sizeA, sizeB
+	if !q.ShortA {
+		exec.Short, exec.Long = x.b, x.a
+		shortSize, longSize = sizeB, sizeA
+	}
+
+	var err error
+	exec.ShortOrder, err = exec.Short.Broker.PlaceOrder(ctx, marketOrder(exec.Short.Symbol, broker.SideShort, shortSize, false))
+	if err != nil {
+		return nil, &ExecutionError{Leg: exec.Short, Err: err}
+	}
+
+	exec.LongOrder, err = exec.Long.Broker.PlaceOrder(ctx, marketOrder(exec.Long.Symbol, broker.SideLong, longSize, false))
+	if err != nil {
+		// The hedge may have failed because ctx ended: roll back regardless
+		rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), RollbackTimeout)
+		defer cancel()
+		_, rollbackErr := exec.Short.Broker.PlaceOrder(rollbackCtx, marketOrder(exec.Short.Symbol, broker.SideLong, shortSize, true))
+		return nil, &ExecutionError{Leg: exec.Long, Err: err, RollbackErr: rollbackErr}
+	}
+
+	return exec, nil
+}
+
+// Close exits both legs with reduce-only market orders. Both legs are
+// attempted even if one fails.
+func (x *Arbitrage) Close(ctx context.Context, exec *Execution) error {
+	_, shortErr := exec.Short.Broker.PlaceOrder(ctx, marketOrder(exec.Short.Symbol, broker.SideLong, exec.ShortOrder.Size, true))
+	_, longErr := exec.Long.Broker.PlaceOrder(ctx, marketOrder(exec.Long.Symbol, broker.SideShort, exec.LongOrder.Size, true))
+	return errors.Join(shortErr, longErr)
+}
+
+func marketOrder(symbol string, side broker.Side, size float64, reduceOnly bool) *broker.OrderRequest {
+	return &broker.OrderRequest{
+		Symbol:     symbol,
+		Side:       side,
+		Type:       broker.OrderTypeMarket,
+		Size:       size,
+		ReduceOnly: reduceOnly,
+	}
+}
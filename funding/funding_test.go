@@ -0,0 +1,151 @@
+package funding
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// spotBroker hides the fake's funding support, like a spot venue
+type spotBroker struct {
+	broker.Broker
+}
+
+func newLegs() (*brokertest.Broker, *brokertest.Broker) {
+	perp := brokertest.New()
+	perp.SetPrice("BTC-USDT", 50000)
+	perp.SetFundingRate("BTC-USDT", 0.0003) // 32.85% APR
+
+	spot := brokertest.New()
+	spot.SetName("spot")
+	spot.SetPrice("BTC-USDT", 49900)
+	return perp, spot
+}
+
+func TestArbitrage_Quote(t *testing.T) {
+	perp, spot := newLegs()
+	x := New(Leg{perp, "BTC-USDT"}, Leg{spotBroker{spot}, "BTC-USDT"}, Config{EntryAPR: 0.2, ExitAPR: 0.05})
+
+	q, err := x.Quote(context.Background())
+	if err != nil {
+		t.Fatalf("Quote() error = %v", err)
+	}
+	if !q.ShortA {
+		t.Error("ShortA = false, want true (perp pays funding)")
+	}
+	if want := 0.0003 * 3 * 365; math.Abs(q.CarryAPR-want) > 1e-9 {
+		t.Errorf("CarryAPR = %v, want %v", q.CarryAPR, want)
+	}
+	if want := 100.0 / 49900; math.Abs(q.Basis-want) > 1e-12 {
+		t.Errorf("Basis = %v, want %v", q.Basis, want)
+	}
+
+	if got := x.Signal(q, false); got != SignalEnter {
+		t.Errorf("Signal(closed) = %v, want %v", got, SignalEnter)
+	}
+	if got := x.Signal(q, true); got != SignalHold {
+		t.Errorf("Signal(open) = %v, want %v", got, SignalHold)
+	}
+	q.CarryAPR = 0.01
+	if got := x.Signal(q, true); got != SignalExit {
+		t.Errorf("Signal(open, low carry) = %v, want %v", got, SignalExit)
+	}
+}
+
+func TestHedgeSizes(t *testing.T) {
+	a, b := HedgeSizes(10000, 50000, 40000)
+	if a != 0.2 || b != 0.25 {
+		t.Errorf("HedgeSizes() = %v, %v, want 0.2, 0.25", a, b)
+	}
+	if a, b := HedgeSizes(10000, 0, 40000); a != 0 || b != 0 {
+		t.Errorf("HedgeSizes() with zero price = %v, %v, want 0, 0", a, b)
+	}
+}
+
+func TestArbitrage_OpenAndClose(t *testing.T) {
+	perp, spot := newLegs()
+	x := New(Leg{perp, "BTC-USDT"}, Leg{spot, "BTC-USDT"}, Config{})
+
+	q, _ := x.Quote(context.Background())
+	exec, err := x.Open(context.Background(), q, 10000)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+
+	short, _ := perp.GetPosition(context.Background(), "BTC-USDT")
+	long, _ := spot.GetPosition(context.Background(), "BTC-USDT")
+	if short == nil || short.Side != broker.SideShort || short.Size != 0.2 {
+		t.Errorf("perp position = %+v, want SHORT 0.2", short)
+	}
+	if long == nil || long.Side != broker.SideLong || math.Abs(long.Size*49900-10000) > 1e-6 {
+		t.Errorf("spot position = %+v, want LONG 10000 notional", long)
+	}
+
+	if err := x.Close(context.Background(), exec); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	for _, b := range []*brokertest.Broker{perp, spot} {
+		if positions, _ := b.GetPositions(context.Background(), nil); len(positions) != 0 {
+			t.Errorf("%s positions after Close() = %d, want 0", b.Name(), len(positions))
+		}
+	}
+}
+
+func TestArbitrage_OpenRollsBack(t *testing.T) {
+	perp, spot := newLegs()
+	x := New(Leg{perp, "BTC-USDT"}, Leg{spot, "BTC-USDT"}, Config{})
+	q, _ := x.Quote(context.Background())
+
+	spot.Err = broker.ErrInsufficientBalance
+	_, err := x.Open(context.Background(), q, 10000)
+
+	var execErr *ExecutionError
+	if !errors.As(err, &execErr) || !errors.Is(err, broker.ErrInsufficientBalance) {
+		t.Fatalf("Open() error = %v, want ExecutionError wrapping ErrInsufficientBalance", err)
+	}
+	if execErr.RollbackErr != nil {
+		t.Errorf("RollbackErr = %v, want nil", execErr.RollbackErr)
+	}
+	if positions, _ := perp.GetPositions(context.Background(), nil); len(positions) != 0 {
+		t.Errorf("perp positions after rollback = %d, want 0", len(positions))
+	}
+}
+
+// canceling fails orders once ctx is done, like an HTTP client, and cancels
+// after placing an order when cancel is set
+type canceling struct {
+	broker.Broker
+	cancel context.CancelFunc
+}
+
+func (c canceling) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	order, err := c.Broker.PlaceOrder(ctx, req)
+	if c.cancel != nil {
+		c.cancel()
+	}
+	return order, err
+}
+
+func TestArbitrage_OpenRollsBackAfterCancel(t *testing.T) {
+	perp, spot := newLegs()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	x := New(Leg{canceling{perp, cancel}, "BTC-USDT"}, Leg{canceling{Broker: spot}, "BTC-USDT"}, Config{})
+	q, _ := x.Quote(context.Background())
+
+	_, err := x.Open(ctx, q, 10000)
+	var execErr *ExecutionError
+	if !errors.As(err, &execErr) || !errors.Is(err, context.Canceled) || execErr.RollbackErr != nil {
+		t.Fatalf("Open() error = %v, want the canceled hedge rolled back", err)
+	}
+	if positions, _ := perp.GetPositions(context.Background(), nil); len(positions) != 0 {
+		t.Errorf("perp positions after rollback = %d, want 0", len(positions))
+	}
+}
@@ -0,0 +1,209 @@
+// Package fx converts amounts between assets at exchange prices, so
+// balances and PnL settled in USDT, USDC and coin-margined assets can be
+// added up in one reporting currency.
+package fx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// DefaultTTL is the default time a fetched price is reused
+const DefaultTTL = 10 * time.Second
+
+// ErrNoRate is returned when no ticker connects two assets
+var ErrNoRate = errors.New("fx: no conversion rate")
+
+// Config configures a Converter
+type Config struct {
+	// Quote is the reporting currency, e.g. "USDT"
+	Quote string
+	// Pivots are the assets tried as an intermediate step when no ticker
+	// pairs two assets directly (default Quote and "USDT")
+	Pivots []string
+	// Par lists assets valued 1:1 with each other when no ticker pairs
+	// them, e.g. {"USDT", "USDC"}
+	Par []string
+	// Symbol builds the ticker symbol of a pair (default "BASE-QUOTE")
+	Symbol func(base, quote string) string
+	// TTL is how long a fetched price is reused (default 10s)
+	TTL time.Duration
+}
+
+// Converter converts between assets using a broker's tickers
+type Converter struct {
+	broker broker.Broker
+	config Config
+
+	mu     sync.Mutex
+	prices map[string]price
+	now    func() time.Time
+}
+
+type price struct {
+	value   float64
+	ok      bool // False when the symbol has no ticker
+	fetched time.Time
+}
+
+// New creates a converter reading prices from b
+func New(b broker.Broker, config Config) *Converter {
+	if config.Symbol == nil {
+		config.Symbol = func(base, quote string) string { return base + "-" + quote }
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultTTL
+	}
+	if len(config.Pivots) == 0 {
+		config.Pivots = []string{config.Quote, "USDT"}
+	}
+	return &Converter{
+		broker: b,
+		config: config,
+		prices: make(map[string]price),
+		now:    time.Now,
+	}
+}
+
+// Rate returns how much of to one unit of from is worth. It tries a direct
+// ticker, the inverse ticker, the par list and then one pivot asset.
+func (c *Converter) Rate(ctx context.Context, from, to string) (float64, error) {
+	if rate, ok, err := c.direct(ctx, from, to); err != nil || ok {
+		return rate, err
+	}
+	for _, pivot := range c.config.Pivots {
+		if pivot == "" || pivot == from || pivot == to {
+			continue
+		}
+		first, ok, err := c.direct(ctx, from, pivot)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+		second, ok, err := c.direct(ctx, pivot, to)
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			return first * second, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %s to %s", ErrNoRate, from, to)
+}
+
+// Convert returns amount of from expressed in to
+func (c *Converter) Convert(ctx context.Context, amount float64, from, to string) (float64, error) {
+	if amount == 0 {
+		return 0, nil
+	}
+	rate, err := c.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	return amount * rate, nil
+}
+
+// Rates returns the rate of each asset to the reporting currency, e.g. for
+// analytics.Normalize
+func (c *Converter) Rates(ctx context.Context, assets ...string) (map[string]float64, error) {
+	rates := make(map[string]float64, len(assets))
+	for _, asset := range assets {
+		rate, err := c.Rate(ctx, asset, c.config.Quote)
+		if err != nil {
+			return nil, err
+		}
+		rates[asset] = rate
+	}
+	return rates, nil
+}
+
+// Balance returns b expressed in the reporting currency
+func (c *Converter) Balance(ctx context.Context, b *broker.Balance) (*broker.Balance, error) {
+	rate, err := c.Rate(ctx, b.Asset, c.config.Quote)
+	if err != nil {
+		return nil, err
+	}
+	return &broker.Balance{
+		Asset:         c.config.Quote,
+		Total:         b.Total * rate,
+		Available:     b.Available * rate,
+		InUse:         b.InUse * rate,
+		UnrealizedPnL: b.UnrealizedPnL * rate,
+		RealizedPnL:   b.RealizedPnL * rate,
+		Timestamp:     b.Timestamp,
+	}, nil
+}
+
+// Total adds balances of any assets up in the reporting currency, e.g. the
+// USDT-margined and coin-margined accounts for risk.Estimator
+func (c *Converter) Total(ctx context.Context, balances []*broker.Balance) (*broker.Balance, error) {
+	total := &broker.Balance{Asset: c.config.Quote}
+	for _, b := range balances {
+		converted, err := c.Balance(ctx, b)
+		if err != nil {
+			return nil, err
+		}
+		total.Total += converted.Total
+		total.Available += converted.Available
+		total.InUse += converted.InUse
+		total.UnrealizedPnL += converted.UnrealizedPnL
+		total.RealizedPnL += converted.RealizedPnL
+		if converted.Timestamp.After(total.Timestamp) {
+			total.Timestamp = converted.Timestamp
+		}
+	}
+	return total, nil
+}
+
+// direct returns the rate between two assets without a pivot
+func (c *Converter) direct(ctx context.Context, from, to string) (float64, bool, error) {
+	if from == to {
+		return 1, true, nil
+	}
+	if p, ok, err := c.price(ctx, c.config.Symbol(from, to)); err != nil || ok {
+		return p, ok, err
+	}
+	if p, ok, err := c.price(ctx, c.config.Symbol(to, from)); err != nil || ok {
+		return 1 / p, ok, err
+	}
+	if slices.Contains(c.config.Par, from) && slices.Contains(c.config.Par, to) {
+		return 1, true, nil
+	}
+	return 0, false, nil
+}
+
+// price returns the cached or fetched price of symbol. Symbols the broker
+// doesn't list (ErrInvalidSymbol) report ok=false and are cached as such.
+func (c *Converter) price(ctx context.Context, symbol string) (float64, bool, error) {
+	c.mu.Lock()
+	p, cached := c.prices[symbol]
+	c.mu.Unlock()
+	if cached && c.now().Sub(p.fetched) < c.config.TTL {
+		return p.value, p.ok, nil
+	}
+
+	value, err := c.broker.GetCurrentPrice(ctx, symbol)
+	switch {
+	case errors.Is(err, broker.ErrInvalidSymbol):
+		p = price{fetched: c.now()}
+	case err != nil:
+		return 0, false, err
+	case value <= 0:
+		p = price{fetched: c.now()}
+	default:
+		p = price{value: value, ok: true, fetched: c.now()}
+	}
+
+	c.mu.Lock()
+	c.prices[symbol] = p
+	c.mu.Unlock()
+	return p.value, p.ok, nil
+}
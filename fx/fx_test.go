@@ -0,0 +1,86 @@
+package fx
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/agatticelli/trading-go/bingx"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestConverter_Rate(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetPrice("ETH-USDT", 2500)
+	c := New(b, Config{Quote: "USDC", Par: []string{"USDT", "USDC"}})
+	ctx := context.Background()
+
+	tests := []struct {
+		from, to string
+		want     float64
+	}{
+		{"USDC", "USDC", 1},
+		{"BTC", "USDT", 50000},
+		{"USDT", "BTC", 1.0 / 50000},
+		{"USDT", "USDC", 1},    // Par
+		{"BTC", "USDC", 50000}, // Through the USDT pivot
+		{"ETH", "BTC", 0.05},
+	}
+	for _, tt := range tests {
+		got, err := c.Rate(ctx, tt.from, tt.to)
+		if err != nil || math.Abs(got-tt.want) > 1e-12 {
+			t.Errorf("Rate(%s, %s) = %v, %v, want %v", tt.from, tt.to, got, err, tt.want)
+		}
+	}
+
+	if _, err := c.Rate(ctx, "DOGE", "USDC"); !errors.Is(err, ErrNoRate) {
+		t.Errorf("Rate(DOGE) error = %v, want ErrNoRate", err)
+	}
+
+	b.Err = broker.ErrRateLimited
+	if _, err := c.Rate(ctx, "BTC", "USDT"); err != nil {
+		t.Errorf("cached Rate() error = %v", err)
+	}
+	if _, err := c.Rate(ctx, "SOL", "USDT"); !errors.Is(err, broker.ErrRateLimited) {
+		t.Errorf("Rate() error = %v, want the broker error", err)
+	}
+}
+
+func TestConverter_RateBingX(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("symbol") != "BTC-USDT" {
+			w.Write([]byte(`{"code":109400,"msg":"symbol not exist","timestamp":1760500000000}`))
+			return
+		}
+		w.Write([]byte(`{"code":0,"msg":"","data":{"symbol":"BTC-USDT","price":"50000","time":1760500000000}}`))
+	}))
+	defer server.Close()
+
+	c := New(bingx.NewClient("key", "secret", false, bingx.WithBaseURL(server.URL)), Config{Quote: "USDT"})
+	got, err := c.Rate(context.Background(), "USDT", "BTC")
+	if err != nil || math.Abs(got-1.0/50000) > 1e-12 {
+		t.Errorf("Rate(USDT, BTC) = %v, %v, want the inverse of BTC-USDT past the unlisted USDT-BTC", got, err)
+	}
+}
+
+func TestConverter_Total(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	c := New(b, Config{Quote: "USDT"})
+
+	total, err := c.Total(context.Background(), []*broker.Balance{
+		{Asset: "USDT", Total: 1000, Available: 800, InUse: 200},
+		{Asset: "BTC", Total: 0.1, Available: 0.1, UnrealizedPnL: -0.01},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total.Asset != "USDT" || total.Total != 6000 || total.Available != 5800 || total.UnrealizedPnL != -500 {
+		t.Errorf("Total() = %+v", total)
+	}
+}
@@ -0,0 +1,229 @@
+// Package gateway exposes a broker.Broker over an authenticated JSON REST
+// API so non-Go tools (dashboards, notebooks) can drive the same clients.
+//
+// Routes:
+//
+//	GET    /v1/balance
+//	GET    /v1/positions[?symbol=]
+//	GET    /v1/orders[?symbol=]
+//	POST   /v1/orders
+//	DELETE /v1/orders/{symbol}
+//	DELETE /v1/orders/{symbol}/{id}
+//	GET    /v1/price/{symbol}
+//	GET    /v1/status
+//	GET    /v1/stream (Server-Sent Events)
+//
+// /v1/stream pushes the live account state (balance, positions, open
+// orders, PnL and the fills passed to RecordFill) to dashboards. One poller
+// serves every connected client, and browsers' EventSource may pass the
+// token as ?token= since it cannot set headers.
+package gateway
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// MaxBodySize bounds accepted request bodies
+const MaxBodySize = 64 << 10
+
+// Config configures the gateway
+type Config struct {
+	// Token is required as "Authorization: Bearer <token>" on every request
+	Token string
+	// ReadOnly rejects order placement and cancellation
+	ReadOnly bool
+	// StreamInterval between state events on /v1/stream (default 2s)
+	StreamInterval time.Duration
+	// RecentFills is how many fills state events carry (default 50)
+	RecentFills int
+}
+
+// Server is an http.Handler serving the REST API for one broker
+type Server struct {
+	broker broker.Broker
+	config Config
+	mux    *http.ServeMux
+	hub    hub
+}
+
+// New creates a gateway for b. Mount it with http.ListenAndServe or under
+// a prefix with http.StripPrefix.
+func New(b broker.Broker, config Config) *Server {
+	if config.StreamInterval <= 0 {
+		config.StreamInterval = DefaultStreamInterval
+	}
+	if config.RecentFills <= 0 {
+		config.RecentFills = DefaultRecentFills
+	}
+	s := &Server{broker: b, config: config, mux: http.NewServeMux()}
+
+	s.mux.HandleFunc("GET /v1/balance", s.getBalance)
+	s.mux.HandleFunc("GET /v1/positions", s.getPositions)
+	s.mux.HandleFunc("GET /v1/orders", s.getOrders)
+	s.mux.HandleFunc("POST /v1/orders", s.placeOrder)
+	s.mux.HandleFunc("DELETE /v1/orders/{symbol}", s.cancelAllOrders)
+	s.mux.HandleFunc("DELETE /v1/orders/{symbol}/{id}", s.cancelOrder)
+	s.mux.HandleFunc("GET /v1/price/{symbol}", s.getPrice)
+	s.mux.HandleFunc("GET /v1/status", s.getStatus)
+	s.mux.HandleFunc("GET /v1/stream", s.stream)
+
+	return s
+}
+
+// ServeHTTP authenticates the request and dispatches it
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		token, ok = streamToken(r)
+	}
+	if s.config.Token == "" || !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.config.Token)) != 1 {
+		writeError(w, http.StatusUnauthorized, "unauthorized", "")
+		return
+	}
+	if s.config.ReadOnly && r.Method != http.MethodGet {
+		writeError(w, http.StatusForbidden, "gateway is read-only", "")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) getBalance(w http.ResponseWriter, r *http.Request) {
+	balance, err := s.broker.GetBalance(r.Context())
+	if err != nil {
+		writeBrokerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, toBalanceJSON(balance))
+}
+
+func (s *Server) getPositions(w http.ResponseWriter, r *http.Request) {
+	positions, err := s.broker.GetPositions(r.Context(), &broker.PositionFilter{Symbol: r.URL.Query().Get("symbol")})
+	if err != nil {
+		writeBrokerError(w, err)
+		return
+	}
+
+	out := make([]positionJSON, 0, len(positions))
+	for _, p := range positions {
+		out = append(out, toPositionJSON(p))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) getOrders(w http.ResponseWriter, r *http.Request) {
+	orders, err := s.broker.GetOrders(r.Context(), &broker.OrderFilter{Symbol: r.URL.Query().Get("symbol")})
+	if err != nil {
+		writeBrokerError(w, err)
+		return
+	}
+
+	out := make([]orderJSON, 0, len(orders))
+	for _, o := range orders {
+		out = append(out, toOrderJSON(o))
+	}
+	writeJSON(w, http.StatusOK, out)
+}
+
+func (s *Server) placeOrder(w http.ResponseWriter, r *http.Request) {
+	var req orderRequestJSON
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid order: "+err.Error(), "")
+		return
+	}
+
+	order, err := s.broker.PlaceOrder(r.Context(), req.toBroker())
+	if err != nil {
+		writeBrokerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, toOrderJSON(order))
+}
+
+func (s *Server) cancelOrder(w http.ResponseWriter, r *http.Request) {
+	if err := s.broker.CancelOrder(r.Context(), r.PathValue("symbol"), r.PathValue("id")); err != nil {
+		writeBrokerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) cancelAllOrders(w http.ResponseWriter, r *http.Request) {
+	if err := s.broker.CancelAllOrders(r.Context(), r.PathValue("symbol")); err != nil {
+		writeBrokerError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getPrice(w http.ResponseWriter, r *http.Request) {
+	symbol := r.PathValue("symbol")
+	price, err := s.broker.GetCurrentPrice(r.Context(), symbol)
+	if err != nil {
+		writeBrokerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{"symbol": symbol, "price": price})
+}
+
+func (s *Server) getStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := s.broker.Status(r.Context())
+	if err != nil {
+		writeBrokerError(w, err)
+		return
+	}
+
+	code := http.StatusOK
+	if status.Maintenance {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, toStatusJSON(status))
+}
+
+// writeBrokerError maps broker errors to HTTP status codes
+func writeBrokerError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, broker.ErrInvalidSymbol), errors.Is(err, broker.ErrInvalidPrice),
+		errors.Is(err, broker.ErrInvalidQuantity), errors.Is(err, broker.ErrLeverageTooHigh):
+		status = http.StatusBadRequest
+	case errors.Is(err, broker.ErrOrderNotFound), errors.Is(err, broker.ErrPositionNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, broker.ErrInsufficientBalance):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, broker.ErrRateLimited):
+		status = http.StatusTooManyRequests
+	case errors.Is(err, broker.ErrNotSupported):
+		status = http.StatusNotImplemented
+	}
+
+	var code string
+	var brokerErr *broker.BrokerError
+	if errors.As(err, &brokerErr) {
+		code = brokerErr.Code
+	}
+	if wait, ok := broker.RetryAfter(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(wait.Seconds()))))
+	}
+	writeError(w, status, err.Error(), code)
+}
+
+func writeError(w http.ResponseWriter, status int, message, code string) {
+	writeJSON(w, status, errorJSON{Error: message, Code: code})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
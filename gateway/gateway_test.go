@@ -0,0 +1,200 @@
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/analytics"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newTestGateway(config Config) (*brokertest.Broker, *Server) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetBalance(broker.Balance{Asset: "USDT", Total: 1000, Available: 800})
+	b.AddOrder(broker.Order{ID: "7", Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 45000})
+	config.Token = "t0ken"
+	return b, New(b, config)
+}
+
+func do(s *Server, method, path, body, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestServer_Routes(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"Balance", "GET", "/v1/balance", "", http.StatusOK, `"available":800`},
+		{"Price", "GET", "/v1/price/BTC-USDT", "", http.StatusOK, `"price":50000`},
+		{"Unknown symbol", "GET", "/v1/price/NOPE", "", http.StatusBadRequest, `invalid symbol`},
+		{"Orders", "GET", "/v1/orders?symbol=BTC-USDT", "", http.StatusOK, `"id":"7"`},
+		{"Place order", "POST", "/v1/orders", `{"symbol":"BTC-USDT","side":"LONG","type":"MARKET","size":0.1}`, http.StatusCreated, `"status":"FILLED"`},
+		{"Place order with unknown field", "POST", "/v1/orders", `{"symbol":"BTC-USDT","qty":1}`, http.StatusBadRequest, `invalid order`},
+		{"Positions", "GET", "/v1/positions", "", http.StatusOK, `[]`},
+		{"Cancel order", "DELETE", "/v1/orders/BTC-USDT/7", "", http.StatusNoContent, ``},
+		{"Cancel missing order", "DELETE", "/v1/orders/BTC-USDT/99", "", http.StatusNotFound, `order not found`},
+		{"Cancel all", "DELETE", "/v1/orders/BTC-USDT", "", http.StatusNoContent, ``},
+		{"Status", "GET", "/v1/status", "", http.StatusOK, `"maintenance":false`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, s := newTestGateway(Config{})
+			rec := do(s, tt.method, tt.path, tt.body, "t0ken")
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Errorf("body = %s, want to contain %s", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestServer_PlaceOrderWithBracket(t *testing.T) {
+	b, s := newTestGateway(Config{})
+	rec := do(s, "POST", "/v1/orders", `{"symbol":"BTC-USDT","side":"LONG","type":"LIMIT","size":1,"price":49000,
+		"stopLoss":{"triggerPrice":48000},"takeProfit":{"triggerPrice":52000,"workingType":"MARK_PRICE"}}`, "t0ken")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d (%s)", rec.Code, http.StatusCreated, rec.Body)
+	}
+
+	var order orderJSON
+	if err := json.Unmarshal(rec.Body.Bytes(), &order); err != nil {
+		t.Fatalf("invalid response: %v", err)
+	}
+	if order.Price != 49000 || order.Status != string(broker.OrderStatusNew) {
+		t.Errorf("order = %+v, want resting limit at 49000", order)
+	}
+
+	placed := b.PlacedOrders()
+	if len(placed) != 1 || placed[0].StopLoss == nil || placed[0].TakeProfit.TriggerPrice != 52000 {
+		t.Errorf("placed = %+v, want bracket order", placed)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	_, s := newTestGateway(Config{})
+
+	for _, token := range []string{"", "wrong"} {
+		if rec := do(s, "GET", "/v1/balance", "", token); rec.Code != http.StatusUnauthorized {
+			t.Errorf("token %q status = %d, want %d", token, rec.Code, http.StatusUnauthorized)
+		}
+	}
+}
+
+func TestServer_ReadOnly(t *testing.T) {
+	b, s := newTestGateway(Config{ReadOnly: true})
+
+	if rec := do(s, "GET", "/v1/balance", "", "t0ken"); rec.Code != http.StatusOK {
+		t.Errorf("GET status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	if rec := do(s, "DELETE", "/v1/orders/BTC-USDT", "", "t0ken"); rec.Code != http.StatusForbidden {
+		t.Errorf("DELETE status = %d, want %d", rec.Code, http.StatusForbidden)
+	}
+	if orders, _ := b.GetOrders(context.Background(), nil); len(orders) != 1 {
+		t.Errorf("orders = %d, want 1 (nothing canceled)", len(orders))
+	}
+}
+
+func TestServer_RateLimited(t *testing.T) {
+	b, s := newTestGateway(Config{})
+	limited := broker.NewBrokerError("bingx", "RATE_LIMITED", "HTTP 429: Too Many Requests", broker.ErrRateLimited)
+	limited.RetryAfter = 1500 * time.Millisecond
+	b.Err = limited
+
+	rec := do(s, "GET", "/v1/balance", "", "t0ken")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+}
+
+func TestServer_Stream(t *testing.T) {
+	b, s := newTestGateway(Config{StreamInterval: 10 * time.Millisecond})
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.1, EntryPrice: 48000, UnrealizedPnL: 200})
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	// EventSource cannot set headers, so the stream also accepts ?token=
+	req, _ := http.NewRequestWithContext(ctx, "GET", srv.URL+"/v1/stream?token=t0ken", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); resp.StatusCode != http.StatusOK || ct != "text/event-stream" {
+		t.Fatalf("status = %d, content type %q", resp.StatusCode, ct)
+	}
+
+	lines := bufio.NewScanner(resp.Body)
+	next := func() (name, data string) {
+		for lines.Scan() {
+			line := lines.Text()
+			switch {
+			case strings.HasPrefix(line, "event: "):
+				name = strings.TrimPrefix(line, "event: ")
+			case strings.HasPrefix(line, "data: "):
+				data = strings.TrimPrefix(line, "data: ")
+			case line == "" && name != "":
+				return name, data
+			}
+		}
+		t.Fatalf("stream ended: %v", lines.Err())
+		return "", ""
+	}
+
+	name, data := next()
+	var state stateJSON
+	if err := json.Unmarshal([]byte(data), &state); name != "state" || err != nil {
+		t.Fatalf("first event = %s %s, want state", name, data)
+	}
+	if state.Balance == nil || state.Balance.Available != 800 || len(state.Positions) != 1 || len(state.Orders) != 1 || state.PnL.Unrealized != 200 {
+		t.Errorf("state = %+v", state)
+	}
+
+	s.RecordFill(analytics.Fill{Symbol: "BTC-USDT", Side: broker.SideLong, Price: 50000, Size: 0.1})
+	for {
+		name, data := next()
+		if name == "fill" {
+			if !strings.Contains(data, `"price":50000`) {
+				t.Errorf("fill event = %s", data)
+			}
+			break
+		}
+	}
+	if name, data := next(); name == "state" && !strings.Contains(data, `"fills":[{`) {
+		t.Errorf("state after fill = %s, want the recorded fill", data)
+	}
+}
+
+func TestServer_StreamTokenOnlyForStream(t *testing.T) {
+	_, s := newTestGateway(Config{})
+	if rec := do(s, "GET", "/v1/balance?token=t0ken", "", ""); rec.Code != http.StatusUnauthorized {
+		t.Errorf("query token on /v1/balance status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
@@ -0,0 +1,220 @@
+package gateway
+
+import (
+	"time"
+
+	"github.com/agatticelli/trading-go/analytics"
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Wire types keep the API stable independently of the shared Go types
+
+type errorJSON struct {
+	Error string `json:"error"`
+	Code  string `json:"code,omitempty"`
+}
+
+type balanceJSON struct {
+	Asset         string    `json:"asset"`
+	Total         float64   `json:"total"`
+	Available     float64   `json:"available"`
+	InUse         float64   `json:"inUse"`
+	UnrealizedPnL float64   `json:"unrealizedPnl"`
+	RealizedPnL   float64   `json:"realizedPnl"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+func toBalanceJSON(b *broker.Balance) balanceJSON {
+	return balanceJSON{
+		Asset:         b.Asset,
+		Total:         b.Total,
+		Available:     b.Available,
+		InUse:         b.InUse,
+		UnrealizedPnL: b.UnrealizedPnL,
+		RealizedPnL:   b.RealizedPnL,
+		Timestamp:     b.Timestamp,
+	}
+}
+
+type positionJSON struct {
+	Symbol            string    `json:"symbol"`
+	Side              string    `json:"side"`
+	Size              float64   `json:"size"`
+	EntryPrice        float64   `json:"entryPrice"`
+	MarkPrice         float64   `json:"markPrice"`
+	LiquidationPrice  float64   `json:"liquidationPrice"`
+	Leverage          int       `json:"leverage"`
+	UnrealizedPnL     float64   `json:"unrealizedPnl"`
+	RealizedPnL       float64   `json:"realizedPnl"`
+	Margin            float64   `json:"margin"`
+	MaintenanceMargin float64   `json:"maintenanceMargin"`
+	Timestamp         time.Time `json:"timestamp"`
+}
+
+func toPositionJSON(p *broker.Position) positionJSON {
+	return positionJSON{
+		Symbol:            p.Symbol,
+		Side:              string(p.Side),
+		Size:              p.Size,
+		EntryPrice:        p.EntryPrice,
+		MarkPrice:         p.MarkPrice,
+		LiquidationPrice:  p.LiquidationPrice,
+		Leverage:          p.Leverage,
+		UnrealizedPnL:     p.UnrealizedPnL,
+		RealizedPnL:       p.RealizedPnL,
+		Margin:            p.Margin,
+		MaintenanceMargin: p.MaintenanceMargin,
+		Timestamp:         p.Timestamp,
+	}
+}
+
+type orderJSON struct {
+	ID            string    `json:"id"`
+	ClientOrderID string    `json:"clientOrderId,omitempty"`
+	Symbol        string    `json:"symbol"`
+	Side          string    `json:"side"`
+	Type          string    `json:"type"`
+	Status        string    `json:"status"`
+	Size          float64   `json:"size"`
+	Price         float64   `json:"price,omitempty"`
+	StopPrice     float64   `json:"stopPrice,omitempty"`
+	FilledSize    float64   `json:"filledSize"`
+	AveragePrice  float64   `json:"averagePrice,omitempty"`
+	ReduceOnly    bool      `json:"reduceOnly"`
+	TimeInForce   string    `json:"timeInForce,omitempty"`
+	CreatedAt     time.Time `json:"createdAt"`
+	UpdatedAt     time.Time `json:"updatedAt"`
+}
+
+func toOrderJSON(o *broker.Order) orderJSON {
+	return orderJSON{
+		ID:            o.ID,
+		ClientOrderID: o.ClientOrderID,
+		Symbol:        o.Symbol,
+		Side:          string(o.Side),
+		Type:          string(o.Type),
+		Status:        string(o.Status),
+		Size:          o.Size,
+		Price:         o.Price,
+		StopPrice:     o.StopPrice,
+		FilledSize:    o.FilledSize,
+		AveragePrice:  o.AveragePrice,
+		ReduceOnly:    o.ReduceOnly,
+		TimeInForce:   string(o.TimeInForce),
+		CreatedAt:     o.CreatedAt,
+		UpdatedAt:     o.UpdatedAt,
+	}
+}
+
+type triggerJSON struct {
+	TriggerPrice float64 `json:"triggerPrice"`
+	OrderPrice   float64 `json:"orderPrice,omitempty"`
+	WorkingType  string  `json:"workingType,omitempty"`
+}
+
+type trailingJSON struct {
+	ActivationPrice float64 `json:"activationPrice"`
+	CallbackRate    float64 `json:"callbackRate"`
+}
+
+type orderRequestJSON struct {
+	Symbol      string        `json:"symbol"`
+	Side        string        `json:"side"`
+	Type        string        `json:"type"`
+	Size        float64       `json:"size"`
+	Price       float64       `json:"price,omitempty"`
+	StopPrice   float64       `json:"stopPrice,omitempty"`
+	TimeInForce string        `json:"timeInForce,omitempty"`
+	ReduceOnly  bool          `json:"reduceOnly,omitempty"`
+	StopLoss    *triggerJSON  `json:"stopLoss,omitempty"`
+	TakeProfit  *triggerJSON  `json:"takeProfit,omitempty"`
+	Trailing    *trailingJSON `json:"trailing,omitempty"`
+}
+
+func (r *orderRequestJSON) toBroker() *broker.OrderRequest {
+	req := &broker.OrderRequest{
+		Symbol:      r.Symbol,
+		Side:        broker.Side(r.Side),
+		Type:        broker.OrderType(r.Type),
+		Size:        r.Size,
+		Price:       r.Price,
+		StopPrice:   r.StopPrice,
+		TimeInForce: broker.TimeInForce(r.TimeInForce),
+		ReduceOnly:  r.ReduceOnly,
+	}
+	if r.StopLoss != nil {
+		req.StopLoss = &broker.StopLossConfig{
+			TriggerPrice: r.StopLoss.TriggerPrice,
+			OrderPrice:   r.StopLoss.OrderPrice,
+			WorkingType:  broker.WorkingType(r.StopLoss.WorkingType),
+		}
+	}
+	if r.TakeProfit != nil {
+		req.TakeProfit = &broker.TakeProfitConfig{
+			TriggerPrice: r.TakeProfit.TriggerPrice,
+			OrderPrice:   r.TakeProfit.OrderPrice,
+			WorkingType:  broker.WorkingType(r.TakeProfit.WorkingType),
+		}
+	}
+	if r.Trailing != nil {
+		req.Trailing = &broker.TrailingConfig{
+			ActivationPrice: r.Trailing.ActivationPrice,
+			CallbackRate:    r.Trailing.CallbackRate,
+		}
+	}
+	return req
+}
+
+type statusJSON struct {
+	LatencyMs    int64     `json:"latencyMs"`
+	ServerTime   time.Time `json:"serverTime"`
+	ClockDriftMs int64     `json:"clockDriftMs"`
+	Maintenance  bool      `json:"maintenance"`
+	CheckedAt    time.Time `json:"checkedAt"`
+}
+
+func toStatusJSON(s *broker.ExchangeStatus) statusJSON {
+	return statusJSON{
+		LatencyMs:    s.Latency.Milliseconds(),
+		ServerTime:   s.ServerTime,
+		ClockDriftMs: s.ClockDrift.Milliseconds(),
+		Maintenance:  s.Maintenance,
+		CheckedAt:    s.CheckedAt,
+	}
+}
+
+// stateJSON is the live state sent on the stream
+type stateJSON struct {
+	Time      time.Time      `json:"time"`
+	Balance   *balanceJSON   `json:"balance,omitempty"`
+	Positions []positionJSON `json:"positions"`
+	Orders    []orderJSON    `json:"orders"`
+	Fills     []fillJSON     `json:"fills"`
+	PnL       pnlJSON        `json:"pnl"`
+	Errors    []string       `json:"errors,omitempty"` // Parts that could not be fetched
+}
+
+type pnlJSON struct {
+	Unrealized float64 `json:"unrealized"`
+	Realized   float64 `json:"realized"`
+}
+
+type fillJSON struct {
+	Symbol string    `json:"symbol"`
+	Side   string    `json:"side"`
+	Price  float64   `json:"price"`
+	Size   float64   `json:"size"`
+	Fee    float64   `json:"fee"`
+	Time   time.Time `json:"time"`
+}
+
+func toFillJSON(f analytics.Fill) fillJSON {
+	return fillJSON{
+		Symbol: f.Symbol,
+		Side:   string(f.Side),
+		Price:  f.Price,
+		Size:   f.Size,
+		Fee:    f.Fee,
+		Time:   f.Time,
+	}
+}
@@ -0,0 +1,192 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/analytics"
+)
+
+// Stream defaults
+const (
+	DefaultStreamInterval = 2 * time.Second
+	DefaultRecentFills    = 50
+)
+
+// event is one Server-Sent Event
+type event struct {
+	name string
+	data []byte
+}
+
+// hub polls the broker while at least one stream is connected and fans the
+// state out, so any number of dashboards cost one set of exchange requests
+type hub struct {
+	mu     sync.Mutex
+	subs   map[chan event]struct{}
+	latest *event
+	cancel context.CancelFunc
+	fills  []fillJSON // Most recent last
+}
+
+// RecordFill adds an execution to the recent fills sent on /v1/stream and
+// pushes it to connected clients immediately
+func (s *Server) RecordFill(f analytics.Fill) {
+	fill := toFillJSON(f)
+	data, _ := json.Marshal(fill)
+
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.fills = append(s.hub.fills, fill)
+	if excess := len(s.hub.fills) - s.config.RecentFills; excess > 0 {
+		s.hub.fills = append([]fillJSON(nil), s.hub.fills[excess:]...)
+	}
+	s.hub.publish(event{name: "fill", data: data})
+}
+
+// stream serves live state as Server-Sent Events: a "state" event every
+// StreamInterval and a "fill" event for every recorded fill
+func (s *Server) stream(w http.ResponseWriter, r *http.Request) {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	events := s.subscribe()
+	defer s.unsubscribe(events)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e := <-events:
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.name, e.data); err != nil {
+				return
+			}
+			if err := rc.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// subscribe registers a stream, starting the poller for the first one
+func (s *Server) subscribe() chan event {
+	ch := make(chan event, 16)
+
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if s.hub.subs == nil {
+		s.hub.subs = make(map[chan event]struct{})
+	}
+	s.hub.subs[ch] = struct{}{}
+	if s.hub.latest != nil {
+		ch <- *s.hub.latest
+	}
+	if s.hub.cancel == nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		s.hub.cancel = cancel
+		go s.poll(ctx)
+	}
+	return ch
+}
+
+// unsubscribe removes a stream, stopping the poller after the last one
+func (s *Server) unsubscribe(ch chan event) {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	delete(s.hub.subs, ch)
+	if len(s.hub.subs) == 0 && s.hub.cancel != nil {
+		s.hub.cancel()
+		s.hub.cancel = nil
+		s.hub.latest = nil
+	}
+}
+
+// poll publishes the state every StreamInterval until ctx is canceled
+func (s *Server) poll(ctx context.Context) {
+	ticker := time.NewTicker(s.config.StreamInterval)
+	defer ticker.Stop()
+
+	for {
+		data, _ := json.Marshal(s.snapshot(ctx))
+		e := event{name: "state", data: data}
+
+		s.hub.mu.Lock()
+		if ctx.Err() == nil {
+			s.hub.latest = &e
+			s.hub.publish(e)
+		}
+		s.hub.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// publish sends e to every stream. Streams too slow to keep up miss events;
+// the next state event supersedes the missed ones. Callers must hold h.mu.
+func (h *hub) publish(e event) {
+	for ch := range h.subs {
+		select {
+		case ch <- e:
+		default:
+		}
+	}
+}
+
+// snapshot fetches the current state. Failed parts are reported in Errors
+// and left empty so the rest still reaches the dashboard.
+func (s *Server) snapshot(ctx context.Context) stateJSON {
+	state := stateJSON{Time: time.Now(), Positions: []positionJSON{}, Orders: []orderJSON{}}
+
+	if balance, err := s.broker.GetBalance(ctx); err != nil {
+		state.Errors = append(state.Errors, "balance: "+err.Error())
+	} else {
+		b := toBalanceJSON(balance)
+		state.Balance = &b
+	}
+
+	if positions, err := s.broker.GetPositions(ctx, nil); err != nil {
+		state.Errors = append(state.Errors, "positions: "+err.Error())
+	} else {
+		for _, p := range positions {
+			state.Positions = append(state.Positions, toPositionJSON(p))
+			state.PnL.Unrealized += p.UnrealizedPnL
+			state.PnL.Realized += p.RealizedPnL
+		}
+	}
+
+	if orders, err := s.broker.GetOrders(ctx, nil); err != nil {
+		state.Errors = append(state.Errors, "orders: "+err.Error())
+	} else {
+		for _, o := range orders {
+			state.Orders = append(state.Orders, toOrderJSON(o))
+		}
+	}
+
+	s.hub.mu.Lock()
+	state.Fills = append([]fillJSON{}, s.hub.fills...)
+	s.hub.mu.Unlock()
+	return state
+}
+
+// streamToken lets browsers' EventSource, which cannot set headers,
+// authenticate /v1/stream with ?token=
+func streamToken(r *http.Request) (string, bool) {
+	if r.Method != http.MethodGet || r.URL.Path != "/v1/stream" {
+		return "", false
+	}
+	token := r.URL.Query().Get("token")
+	return token, token != ""
+}
@@ -2,6 +2,17 @@ module github.com/agatticelli/trading-go
 
 go 1.25.1
 
-require github.com/agatticelli/trading-common-types v0.1.0
+require (
+	github.com/agatticelli/trading-common-types v0.1.0
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20250115164207-1a7da9e5054f
+	google.golang.org/grpc v1.71.0
+	google.golang.org/protobuf v1.36.12
+)
+
+require (
+	golang.org/x/net v0.34.0 // indirect
+	golang.org/x/sys v0.29.0 // indirect
+	golang.org/x/text v0.21.0 // indirect
+)
 
 replace github.com/agatticelli/trading-common-types => ../trading-common-types
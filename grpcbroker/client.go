@@ -0,0 +1,210 @@
+package grpcbroker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	tradingv1 "github.com/agatticelli/trading-go/proto/trading/v1"
+	"google.golang.org/grpc"
+)
+
+// Client implements broker.Broker and broker.TradeStreamer over a
+// BrokerService connection
+type Client struct {
+	rpc      tradingv1.BrokerServiceClient
+	name     string
+	features broker.Features
+}
+
+// NewClient creates a client on conn, e.g. from grpc.NewClient, reading
+// the remote broker's name and features once
+func NewClient(ctx context.Context, conn grpc.ClientConnInterface) (*Client, error) {
+	rpc := tradingv1.NewBrokerServiceClient(conn)
+	info, err := rpc.GetInfo(ctx, &tradingv1.GetInfoRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return &Client{
+		rpc:  rpc,
+		name: info.GetName(),
+		features: broker.Features{
+			TrailingStop:     info.GetTrailingStop(),
+			MultipleTP:       info.GetMultipleTp(),
+			BracketOrders:    info.GetBracketOrders(),
+			MaxLeverage:      int(info.GetMaxLeverage()),
+			ReduceOnlyOrders: info.GetReduceOnlyOrders(),
+		},
+	}, nil
+}
+
+func (c *Client) GetBalance(ctx context.Context) (*broker.Balance, error) {
+	balance, err := c.rpc.GetBalance(ctx, &tradingv1.GetBalanceRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromProtoBalance(balance), nil
+}
+
+func (c *Client) GetPositions(ctx context.Context, filter *broker.PositionFilter) ([]*broker.Position, error) {
+	req := &tradingv1.GetPositionsRequest{}
+	if filter != nil {
+		req.Symbol = filter.Symbol
+		if filter.Side != nil {
+			req.Side = toProtoSide(*filter.Side)
+		}
+	}
+	resp, err := c.rpc.GetPositions(ctx, req)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromProtoPositions(resp), nil
+}
+
+func (c *Client) GetPosition(ctx context.Context, symbol string) (*broker.Position, error) {
+	position, err := c.rpc.GetPosition(ctx, &tradingv1.GetPositionRequest{Symbol: symbol})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromProtoPosition(position), nil
+}
+
+// PlaceOrder places order on the remote broker, with the options attached
+// to ctx by broker.WithOrderOptions
+func (c *Client) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	req := toProtoOrderRequest(order, broker.OrderOptionsFrom(ctx))
+	placed, err := c.rpc.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	return fromProtoOrder(placed), nil
+}
+
+func (c *Client) GetOrders(ctx context.Context, filter *broker.OrderFilter) ([]*broker.Order, error) {
+	req := &tradingv1.GetOrdersRequest{}
+	if filter != nil {
+		req.Symbol = filter.Symbol
+		if filter.Status != nil {
+			req.Status = string(*filter.Status)
+		}
+	}
+	resp, err := c.rpc.GetOrders(ctx, req)
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	orders := make([]*broker.Order, 0, len(resp.GetOrders()))
+	for _, o := range resp.GetOrders() {
+		orders = append(orders, fromProtoOrder(o))
+	}
+	return orders, nil
+}
+
+func (c *Client) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	_, err := c.rpc.CancelOrder(ctx, &tradingv1.CancelOrderRequest{Symbol: symbol, OrderId: orderID})
+	if err != nil {
+		return fromStatus(err)
+	}
+	return nil
+}
+
+func (c *Client) CancelAllOrders(ctx context.Context, symbol string) error {
+	_, err := c.rpc.CancelAllOrders(ctx, &tradingv1.CancelAllOrdersRequest{Symbol: symbol})
+	if err != nil {
+		return fromStatus(err)
+	}
+	return nil
+}
+
+func (c *Client) GetCurrentPrice(ctx context.Context, symbol string) (float64, error) {
+	price, err := c.rpc.GetCurrentPrice(ctx, &tradingv1.GetCurrentPriceRequest{Symbol: symbol})
+	if err != nil {
+		return 0, fromStatus(err)
+	}
+	return price.GetPrice(), nil
+}
+
+func (c *Client) SetLeverage(ctx context.Context, symbol string, side string, leverage int) error {
+	_, err := c.rpc.SetLeverage(ctx, &tradingv1.SetLeverageRequest{Symbol: symbol, Side: side, Leverage: int32(leverage)})
+	if err != nil {
+		return fromStatus(err)
+	}
+	return nil
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	if _, err := c.rpc.Ping(ctx, &tradingv1.PingRequest{}); err != nil {
+		return fromStatus(err)
+	}
+	return nil
+}
+
+// Status returns the remote broker's status. Latency is the round trip
+// from this client, which includes the server's own request.
+func (c *Client) Status(ctx context.Context) (*broker.ExchangeStatus, error) {
+	start := time.Now()
+	resp, err := c.rpc.GetStatus(ctx, &tradingv1.GetStatusRequest{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	status := fromProtoStatus(resp)
+	status.Latency = time.Since(start)
+	return status, nil
+}
+
+// Name returns the remote broker's name
+func (c *Client) Name() string {
+	return c.name
+}
+
+// SupportedFeatures returns the remote broker's features
+func (c *Client) SupportedFeatures() broker.Features {
+	return c.features
+}
+
+// StreamTrades streams the remote broker's trades on symbol. It returns
+// broker.ErrNotSupported (wrapped) when the remote broker has no trade
+// feed, and nil when the server ends the stream.
+func (c *Client) StreamTrades(ctx context.Context, symbol string, handler func(broker.Trade)) error {
+	stream, err := c.rpc.StreamTrades(ctx, &tradingv1.StreamTradesRequest{Symbol: symbol})
+	if err != nil {
+		return fromStatus(err)
+	}
+	for {
+		trade, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fromStatus(err)
+		}
+		handler(fromProtoTrade(trade))
+	}
+}
+
+// WatchPositions calls handler with the remote broker's positions in
+// symbol (empty for all) on the first poll and whenever they change, polled
+// by the server every interval (0 for its default), until the context is
+// canceled or the stream fails
+func (c *Client) WatchPositions(ctx context.Context, symbol string, interval time.Duration, handler func([]*broker.Position)) error {
+	stream, err := c.rpc.WatchPositions(ctx, &tradingv1.WatchPositionsRequest{Symbol: symbol, IntervalMillis: interval.Milliseconds()})
+	if err != nil {
+		return fromStatus(err)
+	}
+	for {
+		snapshot, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fromStatus(err)
+		}
+		handler(fromProtoPositions(snapshot))
+	}
+}
+
+var (
+	_ broker.Broker        = (*Client)(nil)
+	_ broker.TradeStreamer = (*Client)(nil)
+)
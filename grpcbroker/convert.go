@@ -0,0 +1,249 @@
+package grpcbroker
+
+import (
+	"cmp"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	tradingv1 "github.com/agatticelli/trading-go/proto/trading/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoSide(side broker.Side) tradingv1.Side {
+	switch side {
+	case broker.SideLong:
+		return tradingv1.Side_SIDE_LONG
+	case broker.SideShort:
+		return tradingv1.Side_SIDE_SHORT
+	}
+	return tradingv1.Side_SIDE_UNSPECIFIED
+}
+
+func fromProtoSide(side tradingv1.Side) broker.Side {
+	switch side {
+	case tradingv1.Side_SIDE_LONG:
+		return broker.SideLong
+	case tradingv1.Side_SIDE_SHORT:
+		return broker.SideShort
+	}
+	return ""
+}
+
+// toTimestamp converts t, leaving zero times unset
+func toTimestamp(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromTimestamp converts ts, returning the zero time when unset
+func fromTimestamp(ts *timestamppb.Timestamp) time.Time {
+	if ts == nil {
+		return time.Time{}
+	}
+	return ts.AsTime()
+}
+
+func toProtoBalance(b *broker.Balance) *tradingv1.Balance {
+	return &tradingv1.Balance{
+		Asset:         b.Asset,
+		Total:         b.Total,
+		Available:     b.Available,
+		InUse:         b.InUse,
+		UnrealizedPnl: b.UnrealizedPnL,
+		RealizedPnl:   b.RealizedPnL,
+		Timestamp:     toTimestamp(b.Timestamp),
+	}
+}
+
+func fromProtoBalance(b *tradingv1.Balance) *broker.Balance {
+	return &broker.Balance{
+		Asset:         b.GetAsset(),
+		Total:         b.GetTotal(),
+		Available:     b.GetAvailable(),
+		InUse:         b.GetInUse(),
+		UnrealizedPnL: b.GetUnrealizedPnl(),
+		RealizedPnL:   b.GetRealizedPnl(),
+		Timestamp:     fromTimestamp(b.GetTimestamp()),
+	}
+}
+
+func toProtoPosition(p *broker.Position) *tradingv1.Position {
+	return &tradingv1.Position{
+		Symbol:            p.Symbol,
+		Side:              toProtoSide(p.Side),
+		Size:              p.Size,
+		EntryPrice:        p.EntryPrice,
+		MarkPrice:         p.MarkPrice,
+		LiquidationPrice:  p.LiquidationPrice,
+		Leverage:          int32(p.Leverage),
+		UnrealizedPnl:     p.UnrealizedPnL,
+		RealizedPnl:       p.RealizedPnL,
+		Margin:            p.Margin,
+		MaintenanceMargin: p.MaintenanceMargin,
+		Timestamp:         toTimestamp(p.Timestamp),
+	}
+}
+
+func fromProtoPosition(p *tradingv1.Position) *broker.Position {
+	return &broker.Position{
+		Symbol:            p.GetSymbol(),
+		Side:              fromProtoSide(p.GetSide()),
+		Size:              p.GetSize(),
+		EntryPrice:        p.GetEntryPrice(),
+		MarkPrice:         p.GetMarkPrice(),
+		LiquidationPrice:  p.GetLiquidationPrice(),
+		Leverage:          int(p.GetLeverage()),
+		UnrealizedPnL:     p.GetUnrealizedPnl(),
+		RealizedPnL:       p.GetRealizedPnl(),
+		Margin:            p.GetMargin(),
+		MaintenanceMargin: p.GetMaintenanceMargin(),
+		Timestamp:         fromTimestamp(p.GetTimestamp()),
+	}
+}
+
+func toProtoPositions(positions []*broker.Position) *tradingv1.GetPositionsResponse {
+	resp := &tradingv1.GetPositionsResponse{Positions: make([]*tradingv1.Position, 0, len(positions))}
+	for _, p := range positions {
+		resp.Positions = append(resp.Positions, toProtoPosition(p))
+	}
+	return resp
+}
+
+func fromProtoPositions(resp *tradingv1.GetPositionsResponse) []*broker.Position {
+	positions := make([]*broker.Position, 0, len(resp.GetPositions()))
+	for _, p := range resp.GetPositions() {
+		positions = append(positions, fromProtoPosition(p))
+	}
+	return positions
+}
+
+func toProtoOrder(o *broker.Order) *tradingv1.Order {
+	return &tradingv1.Order{
+		Id:            o.ID,
+		ClientOrderId: o.ClientOrderID,
+		Symbol:        o.Symbol,
+		Side:          toProtoSide(o.Side),
+		Type:          string(o.Type),
+		Status:        string(o.Status),
+		Size:          o.Size,
+		Price:         o.Price,
+		StopPrice:     o.StopPrice,
+		FilledSize:    o.FilledSize,
+		AveragePrice:  o.AveragePrice,
+		ReduceOnly:    o.ReduceOnly,
+		TimeInForce:   string(o.TimeInForce),
+		CreatedAt:     toTimestamp(o.CreatedAt),
+		UpdatedAt:     toTimestamp(o.UpdatedAt),
+	}
+}
+
+func fromProtoOrder(o *tradingv1.Order) *broker.Order {
+	return &broker.Order{
+		ID:            o.GetId(),
+		ClientOrderID: o.GetClientOrderId(),
+		Symbol:        o.GetSymbol(),
+		Side:          fromProtoSide(o.GetSide()),
+		Type:          broker.OrderType(o.GetType()),
+		Status:        broker.OrderStatus(o.GetStatus()),
+		Size:          o.GetSize(),
+		Price:         o.GetPrice(),
+		StopPrice:     o.GetStopPrice(),
+		FilledSize:    o.GetFilledSize(),
+		AveragePrice:  o.GetAveragePrice(),
+		ReduceOnly:    o.GetReduceOnly(),
+		TimeInForce:   broker.TimeInForce(o.GetTimeInForce()),
+		CreatedAt:     fromTimestamp(o.GetCreatedAt()),
+		UpdatedAt:     fromTimestamp(o.GetUpdatedAt()),
+	}
+}
+
+// toProtoOrderRequest converts an order and its options. The request's
+// client order ID wins over the options', as with the brokers.
+func toProtoOrderRequest(r *broker.OrderRequest, opts broker.OrderOptions) *tradingv1.PlaceOrderRequest {
+	req := &tradingv1.PlaceOrderRequest{
+		Symbol:              r.Symbol,
+		Side:                toProtoSide(r.Side),
+		Type:                string(r.Type),
+		Size:                r.Size,
+		Price:               r.Price,
+		StopPrice:           r.StopPrice,
+		TimeInForce:         string(r.TimeInForce),
+		ReduceOnly:          r.ReduceOnly,
+		ClientOrderId:       cmp.Or(r.ClientOrderID, opts.ClientOrderID),
+		WorkingType:         string(opts.WorkingType),
+		PriceProtect:        opts.PriceProtect,
+		ExpireTime:          toTimestamp(opts.ExpireTime),
+		SelfTradePrevention: string(opts.SelfTradePrevention),
+	}
+	if r.StopLoss != nil {
+		req.StopLoss = &tradingv1.TriggerConfig{TriggerPrice: r.StopLoss.TriggerPrice, OrderPrice: r.StopLoss.OrderPrice, WorkingType: string(r.StopLoss.WorkingType)}
+	}
+	if r.TakeProfit != nil {
+		req.TakeProfit = &tradingv1.TriggerConfig{TriggerPrice: r.TakeProfit.TriggerPrice, OrderPrice: r.TakeProfit.OrderPrice, WorkingType: string(r.TakeProfit.WorkingType)}
+	}
+	if r.Trailing != nil {
+		req.Trailing = &tradingv1.TrailingConfig{ActivationPrice: r.Trailing.ActivationPrice, CallbackRate: r.Trailing.CallbackRate, WorkingType: string(r.Trailing.WorkingType)}
+	}
+	return req
+}
+
+func fromProtoOrderRequest(r *tradingv1.PlaceOrderRequest) (*broker.OrderRequest, broker.OrderOptions) {
+	req := &broker.OrderRequest{
+		Symbol:        r.GetSymbol(),
+		Side:          fromProtoSide(r.GetSide()),
+		Type:          broker.OrderType(r.GetType()),
+		Size:          r.GetSize(),
+		Price:         r.GetPrice(),
+		StopPrice:     r.GetStopPrice(),
+		TimeInForce:   broker.TimeInForce(r.GetTimeInForce()),
+		ReduceOnly:    r.GetReduceOnly(),
+		ClientOrderID: r.GetClientOrderId(),
+	}
+	if sl := r.GetStopLoss(); sl != nil {
+		req.StopLoss = &broker.StopLossConfig{TriggerPrice: sl.GetTriggerPrice(), OrderPrice: sl.GetOrderPrice(), WorkingType: broker.WorkingType(sl.GetWorkingType())}
+	}
+	if tp := r.GetTakeProfit(); tp != nil {
+		req.TakeProfit = &broker.TakeProfitConfig{TriggerPrice: tp.GetTriggerPrice(), OrderPrice: tp.GetOrderPrice(), WorkingType: broker.WorkingType(tp.GetWorkingType())}
+	}
+	if tr := r.GetTrailing(); tr != nil {
+		req.Trailing = &broker.TrailingConfig{ActivationPrice: tr.GetActivationPrice(), CallbackRate: tr.GetCallbackRate(), WorkingType: broker.WorkingType(tr.GetWorkingType())}
+	}
+	opts := broker.OrderOptions{
+		WorkingType:         broker.WorkingType(r.GetWorkingType()),
+		PriceProtect:        r.GetPriceProtect(),
+		ExpireTime:          fromTimestamp(r.GetExpireTime()),
+		SelfTradePrevention: broker.STPMode(r.GetSelfTradePrevention()),
+		ClientOrderID:       r.GetClientOrderId(),
+	}
+	return req, opts
+}
+
+func toProtoStatus(s *broker.ExchangeStatus) *tradingv1.ExchangeStatus {
+	return &tradingv1.ExchangeStatus{
+		LatencyMicros:    s.Latency.Microseconds(),
+		ServerTime:       toTimestamp(s.ServerTime),
+		ClockDriftMicros: s.ClockDrift.Microseconds(),
+		Maintenance:      s.Maintenance,
+		CheckedAt:        toTimestamp(s.CheckedAt),
+	}
+}
+
+func fromProtoStatus(s *tradingv1.ExchangeStatus) *broker.ExchangeStatus {
+	return &broker.ExchangeStatus{
+		Latency:     time.Duration(s.GetLatencyMicros()) * time.Microsecond,
+		ServerTime:  fromTimestamp(s.GetServerTime()),
+		ClockDrift:  time.Duration(s.GetClockDriftMicros()) * time.Microsecond,
+		Maintenance: s.GetMaintenance(),
+		CheckedAt:   fromTimestamp(s.GetCheckedAt()),
+	}
+}
+
+func toProtoTrade(t broker.Trade) *tradingv1.Trade {
+	return &tradingv1.Trade{Symbol: t.Symbol, Id: t.ID, Price: t.Price, Size: t.Size, Side: toProtoSide(t.Side), Time: toTimestamp(t.Time)}
+}
+
+func fromProtoTrade(t *tradingv1.Trade) broker.Trade {
+	return broker.Trade{Symbol: t.GetSymbol(), ID: t.GetId(), Price: t.GetPrice(), Size: t.GetSize(), Side: fromProtoSide(t.GetSide()), Time: fromTimestamp(t.GetTime())}
+}
@@ -0,0 +1,123 @@
+package grpcbroker
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// errorDomain identifies the ErrorInfo details of broker errors
+const errorDomain = "trading.v1"
+
+// sentinels maps the ErrorInfo reasons sent for broker errors to the
+// errors the client reports, and their status codes
+var sentinels = []struct {
+	reason string
+	err    error
+	code   codes.Code
+}{
+	{"INVALID_SYMBOL", broker.ErrInvalidSymbol, codes.InvalidArgument},
+	{"INVALID_PRICE", broker.ErrInvalidPrice, codes.InvalidArgument},
+	{"INVALID_QUANTITY", broker.ErrInvalidQuantity, codes.InvalidArgument},
+	{"LEVERAGE_TOO_HIGH", broker.ErrLeverageTooHigh, codes.InvalidArgument},
+	{"INVALID_EXPIRY", broker.ErrInvalidExpiry, codes.InvalidArgument},
+	{"INSUFFICIENT_BALANCE", broker.ErrInsufficientBalance, codes.FailedPrecondition},
+	{"POSITION_NOT_FOUND", broker.ErrPositionNotFound, codes.NotFound},
+	{"ORDER_NOT_FOUND", broker.ErrOrderNotFound, codes.NotFound},
+	{"AUTH_FAILED", broker.ErrAuthFailed, codes.Unauthenticated},
+	{"RATE_LIMITED", broker.ErrRateLimited, codes.ResourceExhausted},
+	{"NOT_SUPPORTED", broker.ErrNotSupported, codes.Unimplemented},
+	{"SHUTTING_DOWN", broker.ErrShuttingDown, codes.Unavailable},
+	{"MAINTENANCE", broker.ErrMaintenance, codes.Unavailable},
+	{"KILL_SWITCH", broker.ErrKillSwitch, codes.FailedPrecondition},
+	{"API_ERROR", broker.ErrAPIError, codes.Unknown},
+}
+
+// toStatus converts a broker error to a gRPC status error. The sentinel,
+// exchange error code and retry-after travel in an ErrorInfo detail so the
+// client can rebuild an error matching errors.Is and broker.RetryAfter.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+
+	info := &errdetails.ErrorInfo{Domain: errorDomain, Metadata: map[string]string{}}
+	code := codes.Unknown
+	for _, s := range sentinels {
+		if errors.Is(err, s.err) {
+			info.Reason, code = s.reason, s.code
+			break
+		}
+	}
+	var brokerErr *broker.BrokerError
+	if errors.As(err, &brokerErr) {
+		info.Metadata["broker"] = brokerErr.Broker
+		info.Metadata["code"] = brokerErr.Code
+		info.Metadata["message"] = brokerErr.Message
+	}
+	if wait, ok := broker.RetryAfter(err); ok {
+		info.Metadata["retryAfterMillis"] = strconv.FormatInt(wait.Milliseconds(), 10)
+	}
+
+	st, detailErr := status.New(code, err.Error()).WithDetails(info)
+	if detailErr != nil {
+		return status.Error(code, err.Error())
+	}
+	return st.Err()
+}
+
+// fromStatus converts a gRPC error back to a *broker.BrokerError wrapping
+// the sentinel the server reported
+func fromStatus(err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	switch st.Code() {
+	case codes.Canceled:
+		return context.Canceled
+	case codes.DeadlineExceeded:
+		return context.DeadlineExceeded
+	}
+
+	brokerErr := &broker.BrokerError{Broker: "grpc", Code: st.Code().String(), Message: st.Message()}
+	for _, d := range st.Details() {
+		info, ok := d.(*errdetails.ErrorInfo)
+		if !ok || info.GetDomain() != errorDomain {
+			continue
+		}
+		for _, s := range sentinels {
+			if s.reason == info.GetReason() {
+				brokerErr.Err = s.err
+			}
+		}
+		if name := info.GetMetadata()["broker"]; name != "" {
+			brokerErr.Broker = name
+		}
+		if code := info.GetMetadata()["code"]; code != "" {
+			brokerErr.Code = code
+		}
+		if message := info.GetMetadata()["message"]; message != "" {
+			brokerErr.Message = message
+		}
+		if ms, err := strconv.ParseInt(info.GetMetadata()["retryAfterMillis"], 10, 64); err == nil {
+			brokerErr.RetryAfter = time.Duration(ms) * time.Millisecond
+		}
+	}
+	if brokerErr.Err == nil && st.Code() == codes.Unavailable {
+		brokerErr.Err = broker.ErrAPIError
+	}
+	return brokerErr
+}
@@ -0,0 +1,127 @@
+package grpcbroker
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// serve serves b over an in-memory connection and returns a client for it
+func serve(t *testing.T, b broker.Broker) *Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	s := grpc.NewServer()
+	Register(s, b)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	c, err := NewClient(t.Context(), conn)
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
+	return c
+}
+
+func TestClient_Conformance(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 43000)
+	b.SetBalance(broker.Balance{Asset: "USDT", Total: 1000, Available: 1000})
+	b.SetPosition(broker.Position{Symbol: "ETH-USDT", Side: broker.SideShort, Size: 2, EntryPrice: 2200})
+
+	c := serve(t, b)
+	if c.Name() != b.Name() {
+		t.Errorf("Name() = %q, want %q", c.Name(), b.Name())
+	}
+	brokertest.Conformance{RoundTrip: true}.Run(t, c)
+}
+
+func TestClient_Errors(t *testing.T) {
+	b := brokertest.New()
+	c := serve(t, b)
+	ctx := t.Context()
+
+	if err := c.CancelOrder(ctx, "BTC-USDT", "missing"); !errors.Is(err, broker.ErrOrderNotFound) {
+		t.Errorf("CancelOrder(missing) error = %v, want ErrOrderNotFound", err)
+	}
+
+	limited := broker.NewBrokerError("bingx", "100410", "rate limit", broker.ErrRateLimited)
+	limited.RetryAfter = 2 * time.Second
+	b.Err = limited
+	_, err := c.GetBalance(ctx)
+	var brokerErr *broker.BrokerError
+	if !errors.Is(err, broker.ErrRateLimited) || !errors.As(err, &brokerErr) || brokerErr.Broker != "bingx" || brokerErr.Code != "100410" {
+		t.Fatalf("GetBalance() error = %v, want the bingx rate limit error", err)
+	}
+	if wait, ok := broker.RetryAfter(err); !ok || wait != 2*time.Second {
+		t.Errorf("RetryAfter() = %v, %v, want 2s", wait, ok)
+	}
+	if err.Error() != limited.Error() {
+		t.Errorf("Error() = %q, want %q", err.Error(), limited.Error())
+	}
+
+	b.Err = nil
+	if err := c.StreamTrades(ctx, "BTC-USDT", func(broker.Trade) {}); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("StreamTrades() error = %v, want ErrNotSupported from a broker without a trade feed", err)
+	}
+}
+
+func TestClient_OrderOptions(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	c := serve(t, b)
+
+	ctx := broker.WithOrderOptions(t.Context(), broker.OrderOptions{ClientOrderID: "trend-1"})
+	order, err := c.PlaceOrder(ctx, &broker.OrderRequest{
+		Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 0.1, Price: 45000,
+		StopLoss: &broker.StopLossConfig{TriggerPrice: 44000},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.ClientOrderID != "trend-1" || order.Price != 45000 || order.Side != broker.SideLong {
+		t.Errorf("order = %+v, want the limit order with client ID trend-1", order)
+	}
+	if placed := b.PlacedOrders(); len(placed) != 1 || placed[0].StopLoss == nil || placed[0].StopLoss.TriggerPrice != 44000 {
+		t.Errorf("placed = %+v, want the stop loss forwarded", placed)
+	}
+}
+
+func TestClient_WatchPositions(t *testing.T) {
+	b := brokertest.New()
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, EntryPrice: 50000})
+	c := serve(t, b)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	snapshots := make(chan []*broker.Position, 4)
+	go c.WatchPositions(ctx, "BTC-USDT", MinWatchInterval, func(p []*broker.Position) { snapshots <- p })
+
+	if first := <-snapshots; len(first) != 1 || first[0].Size != 1 {
+		t.Fatalf("first snapshot = %+v, want the 1 BTC long", first)
+	}
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 2, EntryPrice: 50000})
+	select {
+	case next := <-snapshots:
+		if len(next) != 1 || next[0].Size != 2 {
+			t.Errorf("snapshot after the change = %+v, want 2 BTC", next)
+		}
+	case <-ctx.Done():
+		t.Fatal("no snapshot after the position changed")
+	}
+}
@@ -0,0 +1,239 @@
+// Package grpcbroker serves a broker.Broker over gRPC (trading.v1
+// BrokerService, see proto/trading/v1) and provides a client implementing
+// broker.Broker on top of it, so strategies can run in a separate process,
+// or language, from the exchange connectivity layer.
+//
+// Broker errors keep their sentinel across the wire: the client returns a
+// *broker.BrokerError that matches errors.Is(err, broker.ErrOrderNotFound)
+// and friends, with the exchange's code and retry-after.
+package grpcbroker
+
+import (
+	"context"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	tradingv1 "github.com/agatticelli/trading-go/proto/trading/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// DefaultWatchInterval is how often WatchPositions polls by default
+	DefaultWatchInterval = time.Second
+	// MinWatchInterval bounds how often clients can make WatchPositions poll
+	MinWatchInterval = 100 * time.Millisecond
+)
+
+// Server implements tradingv1.BrokerServiceServer by forwarding each RPC to
+// a broker.Broker. Authentication is left to interceptors.
+type Server struct {
+	tradingv1.UnimplementedBrokerServiceServer
+	broker broker.Broker
+}
+
+// NewServer creates a server for b
+func NewServer(b broker.Broker) *Server {
+	return &Server{broker: b}
+}
+
+// Register registers a server for b on s
+func Register(s grpc.ServiceRegistrar, b broker.Broker) {
+	tradingv1.RegisterBrokerServiceServer(s, NewServer(b))
+}
+
+func (s *Server) GetBalance(ctx context.Context, req *tradingv1.GetBalanceRequest) (*tradingv1.Balance, error) {
+	balance, err := s.broker.GetBalance(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoBalance(balance), nil
+}
+
+func (s *Server) GetPositions(ctx context.Context, req *tradingv1.GetPositionsRequest) (*tradingv1.GetPositionsResponse, error) {
+	positions, err := s.broker.GetPositions(ctx, positionFilter(req.GetSymbol(), req.GetSide()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoPositions(positions), nil
+}
+
+func (s *Server) GetPosition(ctx context.Context, req *tradingv1.GetPositionRequest) (*tradingv1.Position, error) {
+	position, err := s.broker.GetPosition(ctx, req.GetSymbol())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoPosition(position), nil
+}
+
+// PlaceOrder places the order with the request's broker.OrderOptions
+// attached to the context
+func (s *Server) PlaceOrder(ctx context.Context, req *tradingv1.PlaceOrderRequest) (*tradingv1.Order, error) {
+	order, opts := fromProtoOrderRequest(req)
+	placed, err := s.broker.PlaceOrder(broker.WithOrderOptions(ctx, opts), order)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoOrder(placed), nil
+}
+
+func (s *Server) GetOrders(ctx context.Context, req *tradingv1.GetOrdersRequest) (*tradingv1.GetOrdersResponse, error) {
+	filter := &broker.OrderFilter{Symbol: req.GetSymbol()}
+	if req.GetStatus() != "" {
+		status := broker.OrderStatus(req.GetStatus())
+		filter.Status = &status
+	}
+	orders, err := s.broker.GetOrders(ctx, filter)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &tradingv1.GetOrdersResponse{Orders: make([]*tradingv1.Order, 0, len(orders))}
+	for _, o := range orders {
+		resp.Orders = append(resp.Orders, toProtoOrder(o))
+	}
+	return resp, nil
+}
+
+func (s *Server) CancelOrder(ctx context.Context, req *tradingv1.CancelOrderRequest) (*tradingv1.CancelOrderResponse, error) {
+	if err := s.broker.CancelOrder(ctx, req.GetSymbol(), req.GetOrderId()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &tradingv1.CancelOrderResponse{}, nil
+}
+
+func (s *Server) CancelAllOrders(ctx context.Context, req *tradingv1.CancelAllOrdersRequest) (*tradingv1.CancelAllOrdersResponse, error) {
+	if err := s.broker.CancelAllOrders(ctx, req.GetSymbol()); err != nil {
+		return nil, toStatus(err)
+	}
+	return &tradingv1.CancelAllOrdersResponse{}, nil
+}
+
+func (s *Server) GetCurrentPrice(ctx context.Context, req *tradingv1.GetCurrentPriceRequest) (*tradingv1.Price, error) {
+	price, err := s.broker.GetCurrentPrice(ctx, req.GetSymbol())
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &tradingv1.Price{Symbol: req.GetSymbol(), Price: price}, nil
+}
+
+func (s *Server) SetLeverage(ctx context.Context, req *tradingv1.SetLeverageRequest) (*tradingv1.SetLeverageResponse, error) {
+	if err := s.broker.SetLeverage(ctx, req.GetSymbol(), req.GetSide(), int(req.GetLeverage())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &tradingv1.SetLeverageResponse{}, nil
+}
+
+func (s *Server) Ping(ctx context.Context, req *tradingv1.PingRequest) (*tradingv1.PingResponse, error) {
+	if err := s.broker.Ping(ctx); err != nil {
+		return nil, toStatus(err)
+	}
+	return &tradingv1.PingResponse{}, nil
+}
+
+func (s *Server) GetStatus(ctx context.Context, req *tradingv1.GetStatusRequest) (*tradingv1.ExchangeStatus, error) {
+	st, err := s.broker.Status(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return toProtoStatus(st), nil
+}
+
+func (s *Server) GetInfo(ctx context.Context, req *tradingv1.GetInfoRequest) (*tradingv1.BrokerInfo, error) {
+	features := s.broker.SupportedFeatures()
+	return &tradingv1.BrokerInfo{
+		Name:             s.broker.Name(),
+		TrailingStop:     features.TrailingStop,
+		MultipleTp:       features.MultipleTP,
+		BracketOrders:    features.BracketOrders,
+		MaxLeverage:      int32(features.MaxLeverage),
+		ReduceOnlyOrders: features.ReduceOnlyOrders,
+	}, nil
+}
+
+// StreamTrades forwards the broker's trade feed; it fails with
+// codes.Unimplemented when the broker isn't a broker.TradeStreamer
+func (s *Server) StreamTrades(req *tradingv1.StreamTradesRequest, stream grpc.ServerStreamingServer[tradingv1.Trade]) error {
+	streamer, ok := s.broker.(broker.TradeStreamer)
+	if !ok {
+		return toStatus(broker.ErrNotSupported)
+	}
+
+	ctx, cancel := context.WithCancel(stream.Context())
+	defer cancel()
+	var sendErr error
+	err := streamer.StreamTrades(ctx, req.GetSymbol(), func(t broker.Trade) {
+		if sendErr != nil {
+			return
+		}
+		if sendErr = stream.Send(toProtoTrade(t)); sendErr != nil {
+			cancel()
+		}
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	return toStatus(err)
+}
+
+// WatchPositions polls the broker's positions at the requested interval
+// (default 1s, at least 100ms) and sends a snapshot on the first poll and
+// whenever the positions change
+func (s *Server) WatchPositions(req *tradingv1.WatchPositionsRequest, stream grpc.ServerStreamingServer[tradingv1.GetPositionsResponse]) error {
+	interval := time.Duration(req.GetIntervalMillis()) * time.Millisecond
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	interval = max(interval, MinWatchInterval)
+
+	ctx := stream.Context()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var last *tradingv1.GetPositionsResponse
+	for {
+		positions, err := s.broker.GetPositions(ctx, positionFilter(req.GetSymbol(), tradingv1.Side_SIDE_UNSPECIFIED))
+		if err != nil {
+			return toStatus(err)
+		}
+		snapshot := toProtoPositions(positions)
+		if last == nil || !samePositions(last, snapshot) {
+			if err := stream.Send(snapshot); err != nil {
+				return err
+			}
+			last = snapshot
+		}
+
+		select {
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// samePositions compares snapshots ignoring their timestamps, which some
+// brokers stamp with the time of the request
+func samePositions(a, b *tradingv1.GetPositionsResponse) bool {
+	if len(a.GetPositions()) != len(b.GetPositions()) {
+		return false
+	}
+	for i, p := range a.GetPositions() {
+		q := b.GetPositions()[i]
+		if p.GetSymbol() != q.GetSymbol() || p.GetSide() != q.GetSide() || p.GetSize() != q.GetSize() ||
+			p.GetEntryPrice() != q.GetEntryPrice() || p.GetMarkPrice() != q.GetMarkPrice() ||
+			p.GetUnrealizedPnl() != q.GetUnrealizedPnl() || p.GetLeverage() != q.GetLeverage() {
+			return false
+		}
+	}
+	return true
+}
+
+func positionFilter(symbol string, side tradingv1.Side) *broker.PositionFilter {
+	filter := &broker.PositionFilter{Symbol: symbol}
+	if s := fromProtoSide(side); s != "" {
+		filter.Side = &s
+	}
+	return filter
+}
+
+var _ tradingv1.BrokerServiceServer = (*Server)(nil)
@@ -0,0 +1,323 @@
+// Package guard protects the exchange (and the account's fee budget) from
+// runaway strategies. It wraps a broker.Broker and rejects orders that
+// repeat an identical order within a window, exceed a per-symbol order
+// rate or notional cap, are priced far from the market, or target a symbol
+// outside the configured allow and deny lists.
+package guard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+var (
+	// ErrDuplicateOrder is matched by errors.Is for orders identical to one
+	// placed within the duplicate window
+	ErrDuplicateOrder = errors.New("duplicate order")
+	// ErrThrottled is matched by errors.Is for orders over the per-symbol rate
+	ErrThrottled = errors.New("order rate exceeded")
+	// ErrSymbolNotAllowed is matched by errors.Is for orders on a symbol
+	// missing from AllowSymbols or listed in DenySymbols
+	ErrSymbolNotAllowed = errors.New("symbol not allowed")
+	// ErrNotionalExceeded is matched by errors.Is for orders over MaxNotional
+	ErrNotionalExceeded = errors.New("order notional exceeded")
+	// ErrPriceOutOfBand is matched by errors.Is for order prices outside
+	// PriceBand
+	ErrPriceOutOfBand = errors.New("order price out of band")
+)
+
+// RejectError reports an order rejected by the guard before reaching the
+// exchange
+type RejectError struct {
+	Symbol     string
+	Err        error         // Matches one of the package's Err values
+	RetryAfter time.Duration // When the same order would be accepted (0 = never)
+}
+
+func (e *RejectError) Error() string {
+	if e.RetryAfter <= 0 {
+		return fmt.Sprintf("order for %s rejected: %v", e.Symbol, e.Err)
+	}
+	return fmt.Sprintf("order for %s rejected: %v (retry after %v)", e.Symbol, e.Err, e.RetryAfter)
+}
+
+func (e *RejectError) Unwrap() error {
+	return e.Err
+}
+
+// Config configures the guard. Zero values disable the corresponding check.
+type Config struct {
+	// DuplicateWindow rejects an order identical to one placed successfully
+	// less than this long ago. Orders carrying different client order IDs
+	// (broker.WithOrderOptions) are never duplicates of each other.
+	DuplicateWindow time.Duration
+	// MaxOrdersPerSecond limits order attempts per symbol in any one-second
+	// window
+	MaxOrdersPerSecond int
+	// Symbols overrides MaxOrdersPerSecond per symbol
+	Symbols map[string]int
+	// AllowSymbols, when not empty, rejects orders on any other symbol
+	AllowSymbols []string
+	// DenySymbols rejects orders on these symbols, even when allowed
+	DenySymbols []string
+	// MaxNotional rejects orders whose size times price exceeds it. Orders
+	// without a price are valued at the mark price; reduce-only orders are
+	// exempt.
+	MaxNotional float64
+	// PriceBand rejects limit and trigger prices further than this fraction
+	// from the mark price (0.1 = ±10%). Contexts from AllowFarPrices skip it.
+	PriceBand float64
+}
+
+// Broker wraps a broker.Broker and applies the guard to PlaceOrder. All
+// other operations pass through unchanged.
+type Broker struct {
+	broker.Broker
+	config Config
+	now    func() time.Time
+	allow  map[string]bool // nil when every symbol is allowed
+	deny   map[string]bool
+
+	mu       sync.Mutex
+	recent   map[orderKey]time.Time // Identical orders, by when they were placed
+	pending  map[orderKey]bool      // Identical orders in flight
+	attempts map[string][]time.Time // Order attempts per symbol, oldest first
+}
+
+// orderKey identifies identical orders. Optional configs are copied by
+// value so orders with equal TP/SL settings compare equal; orders with
+// different client order IDs are distinct even if otherwise identical.
+type orderKey struct {
+	clientID    string
+	symbol      string
+	side        broker.Side
+	typ         broker.OrderType
+	size        float64
+	price       float64
+	stopPrice   float64
+	timeInForce broker.TimeInForce
+	reduceOnly  bool
+	stopLoss    broker.StopLossConfig
+	takeProfit  broker.TakeProfitConfig
+	trailing    broker.TrailingConfig
+}
+
+func keyOf(ctx context.Context, req *broker.OrderRequest) orderKey {
+	k := orderKey{
+		clientID:    broker.ClientOrderIDOf(ctx, req),
+		symbol:      req.Symbol,
+		side:        req.Side,
+		typ:         req.Type,
+		size:        req.Size,
+		price:       req.Price,
+		stopPrice:   req.StopPrice,
+		timeInForce: req.TimeInForce,
+		reduceOnly:  req.ReduceOnly,
+	}
+	if req.StopLoss != nil {
+		k.stopLoss = *req.StopLoss
+	}
+	if req.TakeProfit != nil {
+		k.takeProfit = *req.TakeProfit
+	}
+	if req.Trailing != nil {
+		k.trailing = *req.Trailing
+	}
+	return k
+}
+
+// Wrap returns b guarded by the configured checks
+func Wrap(b broker.Broker, config Config) *Broker {
+	g := &Broker{
+		Broker:   b,
+		config:   config,
+		now:      time.Now,
+		deny:     make(map[string]bool),
+		recent:   make(map[orderKey]time.Time),
+		pending:  make(map[orderKey]bool),
+		attempts: make(map[string][]time.Time),
+	}
+	if len(config.AllowSymbols) > 0 {
+		g.allow = make(map[string]bool)
+		for _, symbol := range config.AllowSymbols {
+			g.allow[symbol] = true
+		}
+	}
+	for _, symbol := range config.DenySymbols {
+		g.deny[symbol] = true
+	}
+	return g
+}
+
+// Allowed reports whether the symbol lists permit orders on symbol
+func (b *Broker) Allowed(symbol string) bool {
+	if b.deny[symbol] {
+		return false
+	}
+	return b.allow == nil || b.allow[symbol]
+}
+
+// PlaceOrder places the order unless its symbol is not allowed, it exceeds
+// the notional cap or price band, duplicates a recent one or exceeds the
+// symbol's order rate, in which case it fails with a *RejectError. Failed
+// orders do not count as duplicates, so they can be retried at once.
+func (b *Broker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	if !b.Allowed(req.Symbol) {
+		return nil, &RejectError{Symbol: req.Symbol, Err: ErrSymbolNotAllowed}
+	}
+	if err := b.checkPrices(ctx, req); err != nil {
+		return nil, err
+	}
+
+	key := keyOf(ctx, req)
+	if err := b.admit(key); err != nil {
+		return nil, err
+	}
+
+	order, err := b.Broker.PlaceOrder(ctx, req)
+
+	b.mu.Lock()
+	delete(b.pending, key)
+	if err == nil && b.config.DuplicateWindow > 0 {
+		b.recent[key] = b.now()
+	}
+	b.mu.Unlock()
+	return order, err
+}
+
+// checkPrices rejects orders over MaxNotional or priced outside PriceBand.
+// The mark price is fetched at most once, and only when a check needs it.
+func (b *Broker) checkPrices(ctx context.Context, req *broker.OrderRequest) error {
+	checkNotional := b.config.MaxNotional > 0 && !req.ReduceOnly
+	checkBand := b.config.PriceBand > 0 && !farPricesAllowed(ctx) && (req.Price > 0 || req.StopPrice > 0)
+	if !checkNotional && !checkBand {
+		return nil
+	}
+
+	var mark float64
+	if checkBand || req.Price <= 0 && req.StopPrice <= 0 {
+		var err error
+		if mark, err = b.markPrice(ctx, req.Symbol); err != nil {
+			return fmt.Errorf("pricing order for guard checks: %w", err)
+		}
+	}
+
+	if checkBand {
+		for _, price := range []float64{req.Price, req.StopPrice} {
+			if price > 0 && math.Abs(price-mark) > mark*b.config.PriceBand {
+				return &RejectError{
+					Symbol: req.Symbol,
+					Err: fmt.Errorf("%w: %v is %.1f%% from mark price %v", ErrPriceOutOfBand,
+						price, 100*math.Abs(price-mark)/mark, mark),
+				}
+			}
+		}
+	}
+
+	if checkNotional {
+		price := req.Price
+		if price <= 0 {
+			price = req.StopPrice
+		}
+		if price <= 0 {
+			price = mark
+		}
+		if notional := math.Abs(req.Size) * price; notional > b.config.MaxNotional {
+			return &RejectError{
+				Symbol: req.Symbol,
+				Err:    fmt.Errorf("%w: %.2f over %.2f", ErrNotionalExceeded, notional, b.config.MaxNotional),
+			}
+		}
+	}
+	return nil
+}
+
+type farPricesKey struct{}
+
+// AllowFarPrices returns a context that exempts orders placed with it from
+// the PriceBand check, for intentionally distant orders
+func AllowFarPrices(ctx context.Context) context.Context {
+	return context.WithValue(ctx, farPricesKey{}, true)
+}
+
+func farPricesAllowed(ctx context.Context) bool {
+	allowed, _ := ctx.Value(farPricesKey{}).(bool)
+	return allowed
+}
+
+// markPrice returns the symbol's mark price, or the last price for brokers
+// that don't report one
+func (b *Broker) markPrice(ctx context.Context, symbol string) (float64, error) {
+	if p, ok := b.Broker.(broker.FundingRateProvider); ok {
+		if rate, err := p.GetFundingRate(ctx, symbol); err == nil && rate.MarkPrice > 0 {
+			return rate.MarkPrice, nil
+		}
+	}
+	return b.Broker.GetCurrentPrice(ctx, symbol)
+}
+
+// admit checks an order against both limits and records the attempt
+func (b *Broker) admit(key orderKey) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	b.expire(now)
+
+	if window := b.config.DuplicateWindow; window > 0 {
+		if b.pending[key] {
+			return &RejectError{Symbol: key.symbol, Err: ErrDuplicateOrder, RetryAfter: window}
+		}
+		if placed, ok := b.recent[key]; ok {
+			return &RejectError{Symbol: key.symbol, Err: ErrDuplicateOrder, RetryAfter: placed.Add(window).Sub(now)}
+		}
+		b.pending[key] = true
+	}
+
+	if limit := b.limit(key.symbol); limit > 0 {
+		attempts := b.attempts[key.symbol]
+		if len(attempts) >= limit {
+			delete(b.pending, key)
+			return &RejectError{Symbol: key.symbol, Err: ErrThrottled, RetryAfter: attempts[0].Add(time.Second).Sub(now)}
+		}
+		b.attempts[key.symbol] = append(attempts, now)
+	}
+	return nil
+}
+
+// limit returns the order rate that applies to a symbol (0 = unlimited)
+func (b *Broker) limit(symbol string) int {
+	if n, ok := b.config.Symbols[symbol]; ok {
+		return n
+	}
+	return b.config.MaxOrdersPerSecond
+}
+
+// expire drops duplicates and attempts that no longer count. Callers must
+// hold b.mu.
+func (b *Broker) expire(now time.Time) {
+	for key, placed := range b.recent {
+		if !now.Before(placed.Add(b.config.DuplicateWindow)) {
+			delete(b.recent, key)
+		}
+	}
+
+	cutoff := now.Add(-time.Second)
+	for symbol, attempts := range b.attempts {
+		i := 0
+		for i < len(attempts) && !attempts[i].After(cutoff) {
+			i++
+		}
+		if i == len(attempts) {
+			delete(b.attempts, symbol)
+		} else {
+			b.attempts[symbol] = attempts[i:]
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newGuard(config Config) (*Broker, *brokertest.Broker, *time.Time) {
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	inner.SetPrice("ETH-USDT", 3000)
+
+	now := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+	g := Wrap(inner, config)
+	g.now = func() time.Time { return now }
+	return g, inner, &now
+}
+
+func limit(symbol string, price float64) *broker.OrderRequest {
+	return &broker.OrderRequest{Symbol: symbol, Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 0.1, Price: price}
+}
+
+func TestBroker_Duplicate(t *testing.T) {
+	g, inner, now := newGuard(Config{DuplicateWindow: 5 * time.Second})
+	ctx := context.Background()
+
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 49000)); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := g.PlaceOrder(ctx, limit("BTC-USDT", 49000))
+	var rejectErr *RejectError
+	if !errors.Is(err, ErrDuplicateOrder) || !errors.As(err, &rejectErr) || rejectErr.RetryAfter != 5*time.Second {
+		t.Fatalf("identical order error = %v, want ErrDuplicateOrder with 5s retry", err)
+	}
+
+	// A different price is a different order
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 48000)); err != nil {
+		t.Errorf("different order rejected: %v", err)
+	}
+
+	*now = now.Add(5 * time.Second)
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 49000)); err != nil {
+		t.Errorf("identical order after window rejected: %v", err)
+	}
+	if got := len(inner.PlacedOrders()); got != 3 {
+		t.Errorf("orders reaching the broker = %d, want 3", got)
+	}
+}
+
+func TestBroker_DuplicateClientOrderID(t *testing.T) {
+	g, inner, _ := newGuard(Config{DuplicateWindow: 5 * time.Second})
+	ctx := context.Background()
+
+	first := broker.WithOrderOptions(ctx, broker.OrderOptions{ClientOrderID: "grid-1"})
+	if _, err := g.PlaceOrder(first, limit("BTC-USDT", 49000)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The same order under another client ID is a separate order
+	second := broker.WithOrderOptions(ctx, broker.OrderOptions{ClientOrderID: "grid-2"})
+	if _, err := g.PlaceOrder(second, limit("BTC-USDT", 49000)); err != nil {
+		t.Errorf("order with a different client ID rejected: %v", err)
+	}
+	if _, err := g.PlaceOrder(first, limit("BTC-USDT", 49000)); !errors.Is(err, ErrDuplicateOrder) {
+		t.Errorf("repeated client ID error = %v, want ErrDuplicateOrder", err)
+	}
+	if got := len(inner.PlacedOrders()); got != 2 {
+		t.Errorf("orders reaching the broker = %d, want 2", got)
+	}
+
+	// The request's own client ID takes precedence over the context's
+	req := limit("BTC-USDT", 49000)
+	req.ClientOrderID = "grid-3"
+	if _, err := g.PlaceOrder(first, req); err != nil {
+		t.Errorf("order with its own client ID rejected: %v", err)
+	}
+	if _, err := g.PlaceOrder(ctx, req); !errors.Is(err, ErrDuplicateOrder) {
+		t.Errorf("repeated request client ID error = %v, want ErrDuplicateOrder", err)
+	}
+}
+
+func TestBroker_FailedOrderNotDuplicate(t *testing.T) {
+	g, inner, _ := newGuard(Config{DuplicateWindow: time.Minute})
+	ctx := context.Background()
+
+	inner.Err = broker.ErrRateLimited
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 49000)); !errors.Is(err, broker.ErrRateLimited) {
+		t.Fatalf("err = %v, want the broker's error", err)
+	}
+
+	inner.Err = nil
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 49000)); err != nil {
+		t.Errorf("retry of failed order rejected: %v", err)
+	}
+}
+
+func TestBroker_Throttle(t *testing.T) {
+	g, _, now := newGuard(Config{MaxOrdersPerSecond: 2, Symbols: map[string]int{"ETH-USDT": 0}})
+	ctx := context.Background()
+
+	for i := range 2 {
+		if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 49000-float64(i))); err != nil {
+			t.Fatal(err)
+		}
+		*now = now.Add(300 * time.Millisecond)
+	}
+
+	_, err := g.PlaceOrder(ctx, limit("BTC-USDT", 48000))
+	var rejectErr *RejectError
+	if !errors.Is(err, ErrThrottled) || !errors.As(err, &rejectErr) || rejectErr.RetryAfter != 400*time.Millisecond {
+		t.Fatalf("third order error = %v, want ErrThrottled with 400ms retry", err)
+	}
+
+	// Other symbols have their own budget; ETH is unlimited
+	for i := range 5 {
+		if _, err := g.PlaceOrder(ctx, limit("ETH-USDT", 3000-float64(i))); err != nil {
+			t.Fatalf("ETH order %d: %v", i, err)
+		}
+	}
+
+	// The oldest attempt leaves the one-second window
+	*now = now.Add(400 * time.Millisecond)
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 48000)); err != nil {
+		t.Errorf("order after window slid rejected: %v", err)
+	}
+}
+
+func TestBroker_SymbolLists(t *testing.T) {
+	g, inner, _ := newGuard(Config{
+		AllowSymbols: []string{"BTC-USDT", "ETH-USDT"},
+		DenySymbols:  []string{"ETH-USDT"},
+	})
+	ctx := context.Background()
+
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 49000)); err != nil {
+		t.Fatalf("allowed symbol rejected: %v", err)
+	}
+	for _, symbol := range []string{"ETH-USDT", "SOL-USDT"} {
+		_, err := g.PlaceOrder(ctx, limit(symbol, 100))
+		var rejectErr *RejectError
+		if !errors.Is(err, ErrSymbolNotAllowed) || !errors.As(err, &rejectErr) || rejectErr.Symbol != symbol {
+			t.Errorf("%s order error = %v, want ErrSymbolNotAllowed", symbol, err)
+		}
+	}
+	if got := len(inner.PlacedOrders()); got != 1 {
+		t.Errorf("orders reaching the broker = %d, want 1", got)
+	}
+
+	// Without an allow list only denied symbols are rejected
+	g, _, _ = newGuard(Config{DenySymbols: []string{"ETH-USDT"}})
+	if !g.Allowed("SOL-USDT") || g.Allowed("ETH-USDT") {
+		t.Error("deny list alone should reject only ETH-USDT")
+	}
+}
+
+func TestBroker_MaxNotional(t *testing.T) {
+	g, inner, _ := newGuard(Config{MaxNotional: 10000})
+	ctx := context.Background()
+
+	// 0.1 BTC at 49000 is 4900
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 49000)); err != nil {
+		t.Fatalf("order under cap rejected: %v", err)
+	}
+
+	big := limit("BTC-USDT", 49000)
+	big.Size = 1
+	if _, err := g.PlaceOrder(ctx, big); !errors.Is(err, ErrNotionalExceeded) {
+		t.Errorf("limit order error = %v, want ErrNotionalExceeded", err)
+	}
+
+	// Market orders are valued at the mark price: 0.3 * 50000
+	market := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 0.3}
+	if _, err := g.PlaceOrder(ctx, market); !errors.Is(err, ErrNotionalExceeded) {
+		t.Errorf("market order error = %v, want ErrNotionalExceeded", err)
+	}
+
+	// Reduce-only orders can't add exposure
+	big.ReduceOnly = true
+	if _, err := g.PlaceOrder(ctx, big); err != nil {
+		t.Errorf("reduce-only order rejected: %v", err)
+	}
+	if got := len(inner.PlacedOrders()); got != 2 {
+		t.Errorf("orders reaching the broker = %d, want 2", got)
+	}
+}
+
+func TestBroker_PriceBand(t *testing.T) {
+	g, inner, _ := newGuard(Config{PriceBand: 0.1})
+	ctx := context.Background()
+
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 46000)); err != nil {
+		t.Fatalf("price within band rejected: %v", err)
+	}
+	// A dropped digit
+	if _, err := g.PlaceOrder(ctx, limit("BTC-USDT", 4600)); !errors.Is(err, ErrPriceOutOfBand) {
+		t.Errorf("limit error = %v, want ErrPriceOutOfBand", err)
+	}
+	stop := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeStopMarket, Size: 0.1, StopPrice: 56000}
+	if _, err := g.PlaceOrder(ctx, stop); !errors.Is(err, ErrPriceOutOfBand) {
+		t.Errorf("stop error = %v, want ErrPriceOutOfBand", err)
+	}
+
+	if _, err := g.PlaceOrder(AllowFarPrices(ctx), limit("BTC-USDT", 4600)); err != nil {
+		t.Errorf("overridden far order rejected: %v", err)
+	}
+	if got := len(inner.PlacedOrders()); got != 2 {
+		t.Errorf("orders reaching the broker = %d, want 2", got)
+	}
+}
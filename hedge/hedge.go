@@ -0,0 +1,167 @@
+// Package hedge keeps a spot holding delta-neutral with an offsetting perp
+// short of matching size, rebalancing when the two drift apart.
+package hedge
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+const (
+	// DefaultThreshold is the default delta, as a fraction of the spot
+	// holding, tolerated before rebalancing
+	DefaultThreshold = 0.02
+	// DefaultInterval is the default time between rebalance checks in Run
+	DefaultInterval = 30 * time.Second
+)
+
+// SpotFunc returns the spot quantity held of the hedged asset, from a spot
+// API or any external record
+type SpotFunc func(ctx context.Context) (float64, error)
+
+// Fixed returns a SpotFunc for a holding that doesn't change
+func Fixed(quantity float64) SpotFunc {
+	return func(context.Context) (float64, error) { return quantity, nil }
+}
+
+// Config configures a Hedger
+type Config struct {
+	// Symbol is the perp hedging the holding, e.g. "BTC-USDT"
+	Symbol string
+	// Spot reports the holding to hedge
+	Spot SpotFunc
+	// Threshold is the delta, as a fraction of the spot holding, tolerated
+	// before rebalancing (default 2%)
+	Threshold float64
+	// MinSize is the smallest order the perp accepts; smaller corrections
+	// are skipped
+	MinSize float64
+	// Interval between rebalance checks in Run (default 30s)
+	Interval time.Duration
+	// Logger receives rebalance orders and failures in Run (default: discard)
+	Logger *slog.Logger
+}
+
+// Status is the hedge position at one point in time
+type Status struct {
+	Spot  float64 // Spot quantity held
+	Short float64 // Perp short size
+	Price float64 // Perp price
+}
+
+// Delta returns the unhedged quantity: positive when the short is too
+// small, negative when it is too large
+func (s Status) Delta() float64 {
+	return s.Spot - s.Short
+}
+
+// DeltaNotional returns the unhedged value in quote currency
+func (s Status) DeltaNotional() float64 {
+	return s.Delta() * s.Price
+}
+
+// Hedger opens and maintains the perp short hedging a spot holding
+type Hedger struct {
+	broker broker.Broker
+	config Config
+	log    *slog.Logger
+}
+
+// New creates a hedger trading the perp on b
+func New(b broker.Broker, config Config) *Hedger {
+	if config.Threshold <= 0 {
+		config.Threshold = DefaultThreshold
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Hedger{
+		broker: b,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "hedge"),
+	}
+}
+
+// Status returns the current spot holding, perp short and price
+func (h *Hedger) Status(ctx context.Context) (Status, error) {
+	spot, err := h.config.Spot(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	price, err := h.broker.GetCurrentPrice(ctx, h.config.Symbol)
+	if err != nil {
+		return Status{}, err
+	}
+
+	short := broker.SideShort
+	positions, err := h.broker.GetPositions(ctx, &broker.PositionFilter{Symbol: h.config.Symbol, Side: &short})
+	if err != nil && !errors.Is(err, broker.ErrPositionNotFound) {
+		return Status{}, err
+	}
+	status := Status{Spot: spot, Price: price}
+	for _, p := range positions {
+		status.Short += p.Size
+	}
+	return status, nil
+}
+
+// Rebalance sizes the short to the holding when the delta exceeds the
+// threshold: it sells the missing quantity, or buys back the excess with a
+// reduce-only order. It returns the status before rebalancing and the order
+// placed, nil when none was needed.
+func (h *Hedger) Rebalance(ctx context.Context) (Status, *broker.Order, error) {
+	status, err := h.Status(ctx)
+	if err != nil {
+		return status, nil, err
+	}
+
+	delta := status.Delta()
+	if math.Abs(delta) <= h.config.Threshold*status.Spot || math.Abs(delta) < h.config.MinSize {
+		return status, nil, nil
+	}
+
+	req := &broker.OrderRequest{
+		Symbol: h.config.Symbol,
+		Side:   broker.SideShort,
+		Type:   broker.OrderTypeMarket,
+		Size:   delta,
+	}
+	if delta < 0 {
+		req.Side = broker.SideLong
+		req.Size = -delta
+		req.ReduceOnly = true
+	}
+
+	order, err := h.broker.PlaceOrder(ctx, req)
+	if err != nil {
+		return status, nil, err
+	}
+	h.log.Info("hedge rebalanced", logging.KeySymbol, h.config.Symbol, logging.KeyOrderID, order.ID,
+		"spot", status.Spot, "short", status.Short, "delta", delta)
+	return status, order, nil
+}
+
+// Run rebalances at the configured interval until the context is canceled.
+// Failures are logged and retried on the next check.
+func (h *Hedger) Run(ctx context.Context) error {
+	ticker := time.NewTicker(h.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, _, err := h.Rebalance(ctx); err != nil {
+			h.log.Error("hedge rebalance failed", logging.KeySymbol, h.config.Symbol, logging.KeyError, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
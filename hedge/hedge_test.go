@@ -0,0 +1,57 @@
+package hedge
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestHedger_Rebalance(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	ctx := context.Background()
+
+	spot := 1.0
+	h := New(b, Config{Symbol: "BTC-USDT", Spot: func(context.Context) (float64, error) { return spot, nil }, MinSize: 0.001})
+
+	steps := []struct {
+		name      string
+		spot      float64
+		wantOrder bool
+		wantShort float64
+	}{
+		{"Opens the hedge", 1, true, 1},
+		{"Within threshold", 1.015, false, 1},
+		{"Holding grew", 1.5, true, 1.5},
+		{"Holding shrank", 0.5, true, 0.5},
+	}
+	for _, s := range steps {
+		spot = s.spot
+		_, order, err := h.Rebalance(ctx)
+		if err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if (order != nil) != s.wantOrder {
+			t.Errorf("%s: order = %+v", s.name, order)
+		}
+		status, _ := h.Status(ctx)
+		if status.Short != s.wantShort {
+			t.Errorf("%s: short = %v, want %v", s.name, status.Short, s.wantShort)
+		}
+	}
+
+	placed := b.PlacedOrders()
+	last := placed[len(placed)-1]
+	if last.Side != broker.SideLong || !last.ReduceOnly || last.Size != 1 {
+		t.Errorf("buy-back order = %+v, want reduce-only long of 1", last)
+	}
+}
+
+func TestStatus_Delta(t *testing.T) {
+	s := Status{Spot: 2, Short: 1.5, Price: 100}
+	if s.Delta() != 0.5 || s.DeltaNotional() != 50 {
+		t.Errorf("Delta() = %v, DeltaNotional() = %v", s.Delta(), s.DeltaNotional())
+	}
+}
@@ -0,0 +1,183 @@
+// Package history checks stored candles for holes and fills them, so
+// backtests don't silently run over missing data.
+//
+// Candles come from any store; missing ranges are fetched through a Fetcher,
+// typically a thin wrapper around an exchange's kline endpoint.
+package history
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Fetcher loads the candles of symbol opening in [start, end)
+type Fetcher func(ctx context.Context, symbol, interval string, start, end time.Time) ([]broker.Kline, error)
+
+// Gap is a run of missing candles
+type Gap struct {
+	Start   time.Time // Open time of the first missing candle
+	End     time.Time // Open time of the next present candle
+	Missing int
+}
+
+// Report describes the integrity of a candle series
+type Report struct {
+	Symbol     string
+	Interval   string
+	First      time.Time // Open time of the first candle
+	Last       time.Time // Open time of the last candle
+	Count      int       // Distinct candles
+	Expected   int       // Candles between First and Last inclusive
+	Duplicates int       // Candles sharing an open time with an earlier one
+	Misaligned int       // Candles whose open time is off the interval grid
+	Invalid    int       // Candles with inconsistent OHLC values
+	Gaps       []Gap
+}
+
+// Missing returns the number of candles in gaps
+func (r Report) Missing() int {
+	n := 0
+	for _, g := range r.Gaps {
+		n += g.Missing
+	}
+	return n
+}
+
+// OK reports whether the series has no gaps, duplicates or bad candles
+func (r Report) OK() bool {
+	return len(r.Gaps) == 0 && r.Duplicates == 0 && r.Misaligned == 0 && r.Invalid == 0
+}
+
+func (r Report) String() string {
+	if r.Count == 0 {
+		return fmt.Sprintf("%s %s: no candles", r.Symbol, r.Interval)
+	}
+	return fmt.Sprintf("%s %s %s..%s: %d/%d candles, %d gaps (%d missing), %d duplicates, %d misaligned, %d invalid",
+		r.Symbol, r.Interval, r.First.UTC().Format(time.RFC3339), r.Last.UTC().Format(time.RFC3339),
+		r.Count, r.Expected, len(r.Gaps), r.Missing(), r.Duplicates, r.Misaligned, r.Invalid)
+}
+
+// ParseInterval converts an exchange interval such as "1m", "4h", "1d" or
+// "1w" to a duration
+func ParseInterval(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, fmt.Errorf("history: invalid interval %q", interval)
+	}
+	n, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("history: invalid interval %q", interval)
+	}
+
+	unit := map[byte]time.Duration{
+		'm': time.Minute,
+		'h': time.Hour,
+		'd': 24 * time.Hour,
+		'w': 7 * 24 * time.Hour,
+	}[interval[len(interval)-1]]
+	if unit == 0 {
+		return 0, fmt.Errorf("history: invalid interval %q", interval)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// Check reports the integrity of candles of one symbol and interval, in any
+// order. Symbol and interval are taken from the first candle.
+func Check(klines []broker.Kline) (Report, error) {
+	if len(klines) == 0 {
+		return Report{}, nil
+	}
+	report := Report{Symbol: klines[0].Symbol, Interval: klines[0].Interval}
+	step, err := ParseInterval(report.Interval)
+	if err != nil {
+		return report, err
+	}
+
+	sorted := sortByOpenTime(klines)
+	report.First = sorted[0].OpenTime
+	report.Last = sorted[len(sorted)-1].OpenTime
+	report.Expected = int(report.Last.Sub(report.First)/step) + 1
+
+	for i, k := range sorted {
+		if i > 0 && k.OpenTime.Equal(sorted[i-1].OpenTime) {
+			report.Duplicates++
+			continue
+		}
+		report.Count++
+		if k.OpenTime.Sub(report.First)%step != 0 {
+			report.Misaligned++
+		}
+		if !valid(k) {
+			report.Invalid++
+		}
+		if i > 0 {
+			if gap, ok := gapBetween(sorted[i-1].OpenTime, k.OpenTime, step); ok {
+				report.Gaps = append(report.Gaps, gap)
+			}
+		}
+	}
+	return report, nil
+}
+
+// Backfill fetches the candles missing from klines and returns the merged
+// series sorted by open time without duplicates, with the report of the
+// result. Gaps the fetcher cannot fill (exchange outages) remain in the
+// report.
+func Backfill(ctx context.Context, klines []broker.Kline, fetch Fetcher) ([]broker.Kline, Report, error) {
+	report, err := Check(klines)
+	if err != nil || report.Count == 0 {
+		return klines, report, err
+	}
+
+	merged := slices.Clone(klines)
+	for _, gap := range report.Gaps {
+		fetched, err := fetch(ctx, report.Symbol, report.Interval, gap.Start, gap.End)
+		if err != nil {
+			return nil, report, fmt.Errorf("history: backfill %s %s from %s: %w",
+				report.Symbol, report.Interval, gap.Start.UTC().Format(time.RFC3339), err)
+		}
+		for _, k := range fetched {
+			if !k.OpenTime.Before(gap.Start) && k.OpenTime.Before(gap.End) {
+				merged = append(merged, k)
+			}
+		}
+	}
+
+	merged = dedupe(sortByOpenTime(merged))
+	report, err = Check(merged)
+	return merged, report, err
+}
+
+// gapBetween returns the candles missing between two consecutive open times
+func gapBetween(prev, next time.Time, step time.Duration) (Gap, bool) {
+	missing := int(next.Sub(prev)/step) - 1
+	if missing <= 0 {
+		return Gap{}, false
+	}
+	return Gap{Start: prev.Add(step), End: next, Missing: missing}, true
+}
+
+// valid reports whether a candle's prices are consistent
+func valid(k broker.Kline) bool {
+	return k.Low > 0 && k.Low <= k.High &&
+		k.Open >= k.Low && k.Open <= k.High &&
+		k.Close >= k.Low && k.Close <= k.High &&
+		k.Volume >= 0
+}
+
+// sortByOpenTime returns a copy of klines sorted by open time (stable, so
+// the first of several duplicates is the one stored first)
+func sortByOpenTime(klines []broker.Kline) []broker.Kline {
+	sorted := slices.Clone(klines)
+	slices.SortStableFunc(sorted, func(a, b broker.Kline) int { return a.OpenTime.Compare(b.OpenTime) })
+	return sorted
+}
+
+// dedupe drops sorted candles sharing an open time with an earlier one
+func dedupe(sorted []broker.Kline) []broker.Kline {
+	return slices.CompactFunc(sorted, func(a, b broker.Kline) bool { return a.OpenTime.Equal(b.OpenTime) })
+}
@@ -0,0 +1,89 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+var t0 = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// candles returns 1m candles at the given minute offsets from t0
+func candles(minutes ...int) []broker.Kline {
+	klines := make([]broker.Kline, len(minutes))
+	for i, m := range minutes {
+		open := t0.Add(time.Duration(m) * time.Minute)
+		klines[i] = broker.Kline{Symbol: "BTC-USDT", Interval: "1m", OpenTime: open, CloseTime: open.Add(time.Minute),
+			Open: 100, High: 101, Low: 99, Close: 100, Volume: 1, Closed: true}
+	}
+	return klines
+}
+
+func TestCheck(t *testing.T) {
+	klines := candles(0, 1, 4, 2, 2, 8)
+	klines[0].High = 98 // Below Low
+
+	report, err := Check(klines)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count != 5 || report.Expected != 9 || report.Duplicates != 1 || report.Invalid != 1 {
+		t.Errorf("report = %+v", report)
+	}
+	want := []Gap{
+		{Start: t0.Add(3 * time.Minute), End: t0.Add(4 * time.Minute), Missing: 1},
+		{Start: t0.Add(5 * time.Minute), End: t0.Add(8 * time.Minute), Missing: 3},
+	}
+	if len(report.Gaps) != 2 || report.Gaps[0] != want[0] || report.Gaps[1] != want[1] {
+		t.Errorf("gaps = %+v, want %+v", report.Gaps, want)
+	}
+	if report.Missing() != 4 || report.OK() {
+		t.Errorf("Missing() = %d, OK() = %v", report.Missing(), report.OK())
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	var calls [][2]time.Time
+	fetch := func(ctx context.Context, symbol, interval string, start, end time.Time) ([]broker.Kline, error) {
+		calls = append(calls, [2]time.Time{start, end})
+		// The exchange was down at minute 6: that candle never existed
+		return candles(2, 3, 5, 7), nil
+	}
+
+	merged, report, err := Backfill(context.Background(), candles(0, 1, 2, 4, 8), fetch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 {
+		t.Errorf("fetches = %v, want one per gap", calls)
+	}
+	if len(merged) != 8 || report.Count != 8 || report.Duplicates != 0 {
+		t.Errorf("merged %d candles, report %+v", len(merged), report)
+	}
+	if len(report.Gaps) != 1 || !report.Gaps[0].Start.Equal(t0.Add(6*time.Minute)) {
+		t.Errorf("remaining gaps = %+v, want the outage at minute 6", report.Gaps)
+	}
+
+	failing := func(context.Context, string, string, time.Time, time.Time) ([]broker.Kline, error) {
+		return nil, broker.ErrRateLimited
+	}
+	if _, _, err := Backfill(context.Background(), candles(0, 2), failing); !errors.Is(err, broker.ErrRateLimited) {
+		t.Errorf("Backfill() error = %v, want fetcher error", err)
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	for input, want := range map[string]time.Duration{"1m": time.Minute, "15m": 15 * time.Minute, "4h": 4 * time.Hour, "1d": 24 * time.Hour, "1w": 7 * 24 * time.Hour} {
+		if got, err := ParseInterval(input); err != nil || got != want {
+			t.Errorf("ParseInterval(%q) = %v, %v, want %v", input, got, err, want)
+		}
+	}
+	for _, input := range []string{"", "m", "0m", "1M", "1y"} {
+		if _, err := ParseInterval(input); err == nil {
+			t.Errorf("ParseInterval(%q) succeeded", input)
+		}
+	}
+}
@@ -0,0 +1,370 @@
+// Package indicators implements streaming technical indicators. Each
+// indicator keeps only the state it needs and is updated one value or
+// candle at a time, so live kline feeds and backtests share the same code
+// without recomputing full series on every tick.
+package indicators
+
+import (
+	"math"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Indicator consumes closed candles one at a time
+type Indicator interface {
+	// Add updates the indicator with a closed candle
+	Add(k broker.Kline)
+	// Ready reports whether enough data has been seen to produce values
+	Ready() bool
+}
+
+// Feed adds a candle to every indicator. Candles that are still forming
+// are ignored so streaming updates don't count the same period twice.
+func Feed(k broker.Kline, inds ...Indicator) {
+	if !k.Closed {
+		return
+	}
+	for _, ind := range inds {
+		ind.Add(k)
+	}
+}
+
+// window is a fixed-size ring buffer keeping a running sum and sum of squares
+type window struct {
+	values []float64
+	next   int
+	full   bool
+	sum    float64
+	sumSq  float64
+}
+
+func newWindow(size int) *window {
+	if size < 1 {
+		size = 1
+	}
+	return &window{values: make([]float64, size)}
+}
+
+func (w *window) push(v float64) {
+	old := w.values[w.next]
+	if w.full {
+		w.sum -= old
+		w.sumSq -= old * old
+	}
+	w.values[w.next] = v
+	w.sum += v
+	w.sumSq += v * v
+
+	w.next++
+	if w.next == len(w.values) {
+		w.next = 0
+		w.full = true
+	}
+}
+
+func (w *window) mean() float64 {
+	return w.sum / float64(len(w.values))
+}
+
+// stddev returns the population standard deviation of the window
+func (w *window) stddev() float64 {
+	n := float64(len(w.values))
+	mean := w.sum / n
+	variance := w.sumSq/n - mean*mean
+	if variance < 0 {
+		variance = 0 // Rounding error on flat series
+	}
+	return math.Sqrt(variance)
+}
+
+// SMA is a simple moving average
+type SMA struct {
+	w *window
+}
+
+// NewSMA creates a simple moving average over period values
+func NewSMA(period int) *SMA {
+	return &SMA{w: newWindow(period)}
+}
+
+// Update adds a value and returns the current average
+func (s *SMA) Update(v float64) float64 {
+	s.w.push(v)
+	return s.Value()
+}
+
+// Add updates the average with a candle close
+func (s *SMA) Add(k broker.Kline) { s.Update(k.Close) }
+
+// Ready reports whether period values have been seen
+func (s *SMA) Ready() bool { return s.w.full }
+
+// Value returns the current average (0 until ready)
+func (s *SMA) Value() float64 {
+	if !s.w.full {
+		return 0
+	}
+	return s.w.mean()
+}
+
+// EMA is an exponential moving average, seeded with the SMA of its first
+// period values
+type EMA struct {
+	period int
+	alpha  float64
+	count  int
+	sum    float64
+	value  float64
+}
+
+// NewEMA creates an exponential moving average with smoothing 2/(period+1)
+func NewEMA(period int) *EMA {
+	if period < 1 {
+		period = 1
+	}
+	return &EMA{period: period, alpha: 2 / float64(period+1)}
+}
+
+// Update adds a value and returns the current average
+func (e *EMA) Update(v float64) float64 {
+	e.count++
+	if e.count <= e.period {
+		e.sum += v
+		if e.count == e.period {
+			e.value = e.sum / float64(e.period)
+		}
+		return e.Value()
+	}
+	e.value += e.alpha * (v - e.value)
+	return e.value
+}
+
+// Add updates the average with a candle close
+func (e *EMA) Add(k broker.Kline) { e.Update(k.Close) }
+
+// Ready reports whether period values have been seen
+func (e *EMA) Ready() bool { return e.count >= e.period }
+
+// Value returns the current average (0 until ready)
+func (e *EMA) Value() float64 {
+	if !e.Ready() {
+		return 0
+	}
+	return e.value
+}
+
+// wilder is Wilder's smoothed average (an EMA with alpha 1/period)
+type wilder struct {
+	period int
+	count  int
+	value  float64
+}
+
+func (w *wilder) update(v float64) {
+	w.count++
+	if w.count <= w.period {
+		w.value += (v - w.value) / float64(w.count) // Running mean for the seed
+		return
+	}
+	w.value += (v - w.value) / float64(w.period)
+}
+
+func (w *wilder) ready() bool { return w.count >= w.period }
+
+// RSI is Wilder's relative strength index
+type RSI struct {
+	gain, loss wilder
+	prev       float64
+	started    bool
+}
+
+// NewRSI creates a relative strength index over period changes
+func NewRSI(period int) *RSI {
+	if period < 1 {
+		period = 1
+	}
+	return &RSI{gain: wilder{period: period}, loss: wilder{period: period}}
+}
+
+// Update adds a value and returns the current RSI
+func (r *RSI) Update(v float64) float64 {
+	if !r.started {
+		r.prev, r.started = v, true
+		return 0
+	}
+	change := v - r.prev
+	r.prev = v
+	r.gain.update(math.Max(change, 0))
+	r.loss.update(math.Max(-change, 0))
+	return r.Value()
+}
+
+// Add updates the RSI with a candle close
+func (r *RSI) Add(k broker.Kline) { r.Update(k.Close) }
+
+// Ready reports whether period changes have been seen
+func (r *RSI) Ready() bool { return r.gain.ready() }
+
+// Value returns the RSI between 0 and 100 (0 until ready)
+func (r *RSI) Value() float64 {
+	if !r.Ready() {
+		return 0
+	}
+	if r.loss.value == 0 {
+		if r.gain.value == 0 {
+			return 50 // Flat series
+		}
+		return 100
+	}
+	rs := r.gain.value / r.loss.value
+	return 100 - 100/(1+rs)
+}
+
+// MACD is the moving average convergence/divergence oscillator
+type MACD struct {
+	fast, slow, signal *EMA
+	macd               float64
+}
+
+// NewMACD creates a MACD with the given periods (commonly 12, 26, 9)
+func NewMACD(fast, slow, signal int) *MACD {
+	return &MACD{fast: NewEMA(fast), slow: NewEMA(slow), signal: NewEMA(signal)}
+}
+
+// Update adds a value and returns the MACD line, signal line and histogram
+func (m *MACD) Update(v float64) (macd, signal, histogram float64) {
+	m.fast.Update(v)
+	m.slow.Update(v)
+	if m.fast.Ready() && m.slow.Ready() {
+		m.macd = m.fast.Value() - m.slow.Value()
+		m.signal.Update(m.macd)
+	}
+	return m.Value()
+}
+
+// Add updates the MACD with a candle close
+func (m *MACD) Add(k broker.Kline) { m.Update(k.Close) }
+
+// Ready reports whether the signal line has enough data
+func (m *MACD) Ready() bool { return m.signal.Ready() }
+
+// Value returns the MACD line, signal line and histogram (zeros until ready)
+func (m *MACD) Value() (macd, signal, histogram float64) {
+	if !m.Ready() {
+		return 0, 0, 0
+	}
+	signal = m.signal.Value()
+	return m.macd, signal, m.macd - signal
+}
+
+// ATR is Wilder's average true range
+type ATR struct {
+	avg       wilder
+	prevClose float64
+	started   bool
+}
+
+// NewATR creates an average true range over period candles
+func NewATR(period int) *ATR {
+	if period < 1 {
+		period = 1
+	}
+	return &ATR{avg: wilder{period: period}}
+}
+
+// Update adds a candle's high, low and close and returns the current ATR
+func (a *ATR) Update(high, low, close float64) float64 {
+	tr := high - low
+	if a.started {
+		tr = math.Max(tr, math.Max(math.Abs(high-a.prevClose), math.Abs(low-a.prevClose)))
+	}
+	a.prevClose, a.started = close, true
+	a.avg.update(tr)
+	return a.Value()
+}
+
+// Add updates the ATR with a candle
+func (a *ATR) Add(k broker.Kline) { a.Update(k.High, k.Low, k.Close) }
+
+// Ready reports whether period candles have been seen
+func (a *ATR) Ready() bool { return a.avg.ready() }
+
+// Value returns the current ATR (0 until ready)
+func (a *ATR) Value() float64 {
+	if !a.Ready() {
+		return 0
+	}
+	return a.avg.value
+}
+
+// Bollinger is a set of Bollinger Bands
+type Bollinger struct {
+	w *window
+	k float64
+}
+
+// NewBollinger creates bands k standard deviations around a period SMA
+// (commonly 20, 2)
+func NewBollinger(period int, k float64) *Bollinger {
+	return &Bollinger{w: newWindow(period), k: k}
+}
+
+// Update adds a value and returns the middle, upper and lower bands
+func (b *Bollinger) Update(v float64) (middle, upper, lower float64) {
+	b.w.push(v)
+	return b.Value()
+}
+
+// Add updates the bands with a candle close
+func (b *Bollinger) Add(k broker.Kline) { b.Update(k.Close) }
+
+// Ready reports whether period values have been seen
+func (b *Bollinger) Ready() bool { return b.w.full }
+
+// Value returns the middle, upper and lower bands (zeros until ready)
+func (b *Bollinger) Value() (middle, upper, lower float64) {
+	if !b.w.full {
+		return 0, 0, 0
+	}
+	middle = b.w.mean()
+	band := b.k * b.w.stddev()
+	return middle, middle + band, middle - band
+}
+
+// VWAP is the volume-weighted average price since the last Reset,
+// using each candle's typical price (high+low+close)/3
+type VWAP struct {
+	pv     float64
+	volume float64
+}
+
+// NewVWAP creates an empty VWAP
+func NewVWAP() *VWAP {
+	return &VWAP{}
+}
+
+// Update adds a price traded at volume and returns the current VWAP
+func (v *VWAP) Update(price, volume float64) float64 {
+	v.pv += price * volume
+	v.volume += volume
+	return v.Value()
+}
+
+// Add updates the VWAP with a candle's typical price and volume
+func (v *VWAP) Add(k broker.Kline) { v.Update((k.High+k.Low+k.Close)/3, k.Volume) }
+
+// Ready reports whether any volume has been seen
+func (v *VWAP) Ready() bool { return v.volume > 0 }
+
+// Value returns the current VWAP (0 until ready)
+func (v *VWAP) Value() float64 {
+	if v.volume == 0 {
+		return 0
+	}
+	return v.pv / v.volume
+}
+
+// Reset starts a new session, e.g. at the daily open
+func (v *VWAP) Reset() {
+	v.pv, v.volume = 0, 0
+}
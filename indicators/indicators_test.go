@@ -0,0 +1,179 @@
+package indicators
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func series(n int) []float64 {
+	r := rand.New(rand.NewSource(1))
+	values := make([]float64, n)
+	price := 100.0
+	for i := range values {
+		price += r.Float64()*2 - 1
+		values[i] = price
+	}
+	return values
+}
+
+// naiveEMA recomputes an SMA-seeded EMA over the full series
+func naiveEMA(values []float64, period int) float64 {
+	sum := 0.0
+	for _, v := range values[:period] {
+		sum += v
+	}
+	ema := sum / float64(period)
+	alpha := 2 / float64(period+1)
+	for _, v := range values[period:] {
+		ema = alpha*v + (1-alpha)*ema
+	}
+	return ema
+}
+
+func TestSMA_MatchesFullRecompute(t *testing.T) {
+	values := series(200)
+	sma := NewSMA(20)
+
+	for i, v := range values {
+		got := sma.Update(v)
+		if i < 19 {
+			if sma.Ready() || got != 0 {
+				t.Fatalf("SMA ready after %d values", i+1)
+			}
+			continue
+		}
+		sum := 0.0
+		for _, w := range values[i-19 : i+1] {
+			sum += w
+		}
+		if want := sum / 20; !approx(got, want) {
+			t.Fatalf("SMA at %d = %v, want %v", i, got, want)
+		}
+	}
+}
+
+func TestEMA(t *testing.T) {
+	values := series(100)
+	ema := NewEMA(10)
+	for _, v := range values {
+		ema.Update(v)
+	}
+	if want := naiveEMA(values, 10); !approx(ema.Value(), want) {
+		t.Errorf("EMA = %v, want %v", ema.Value(), want)
+	}
+}
+
+func TestRSI(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		want   float64
+	}{
+		{"Only gains", []float64{1, 2, 3, 4, 5}, 100},
+		{"Only losses", []float64{5, 4, 3, 2, 1}, 0},
+		{"Flat", []float64{3, 3, 3, 3, 3}, 50},
+		{"Equal gains and losses", []float64{1, 2, 1, 2, 1}, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rsi := NewRSI(4)
+			for _, v := range tt.values {
+				rsi.Update(v)
+			}
+			if !rsi.Ready() {
+				t.Fatal("RSI not ready after period changes")
+			}
+			if !approx(rsi.Value(), tt.want) {
+				t.Errorf("RSI = %v, want %v", rsi.Value(), tt.want)
+			}
+		})
+	}
+}
+
+func TestMACD(t *testing.T) {
+	values := series(120)
+	macd := NewMACD(12, 26, 9)
+
+	var lines []float64
+	for i, v := range values {
+		macd.Update(v)
+		if i >= 25 {
+			lines = append(lines, naiveEMA(values[:i+1], 12)-naiveEMA(values[:i+1], 26))
+		}
+	}
+
+	gotMACD, gotSignal, gotHist := macd.Value()
+	wantMACD := lines[len(lines)-1]
+	wantSignal := naiveEMA(lines, 9)
+	if !approx(gotMACD, wantMACD) || !approx(gotSignal, wantSignal) || !approx(gotHist, wantMACD-wantSignal) {
+		t.Errorf("MACD = %v/%v/%v, want %v/%v/%v", gotMACD, gotSignal, gotHist, wantMACD, wantSignal, wantMACD-wantSignal)
+	}
+}
+
+func TestATR(t *testing.T) {
+	atr := NewATR(3)
+	klines := []broker.Kline{
+		{High: 11, Low: 9, Close: 10, Closed: true},  // TR 2
+		{High: 12, Low: 10, Close: 11, Closed: true}, // TR 2
+		{High: 16, Low: 12, Close: 15, Closed: true}, // TR 5 (gap from 11)
+		{High: 15, Low: 14, Close: 14, Closed: true}, // TR 1
+	}
+
+	for i, k := range klines {
+		Feed(k, atr)
+		if i == 2 && !approx(atr.Value(), 3) {
+			t.Errorf("seed ATR = %v, want 3", atr.Value())
+		}
+	}
+	// Wilder smoothing: (3*2 + 1) / 3
+	if want := 7.0 / 3; !approx(atr.Value(), want) {
+		t.Errorf("ATR = %v, want %v", atr.Value(), want)
+	}
+}
+
+func TestBollinger(t *testing.T) {
+	b := NewBollinger(4, 2)
+	for _, v := range []float64{2, 4, 4, 4, 5, 5, 7, 9} {
+		b.Update(v)
+	}
+
+	// Last four: 5 5 7 9 -> mean 6.5, population stddev ~1.6583
+	middle, upper, lower := b.Value()
+	sd := math.Sqrt(((1.5*1.5)*2 + 0.25 + 6.25) / 4)
+	if !approx(middle, 6.5) || !approx(upper, 6.5+2*sd) || !approx(lower, 6.5-2*sd) {
+		t.Errorf("Bollinger = %v/%v/%v, want 6.5/%v/%v", middle, upper, lower, 6.5+2*sd, 6.5-2*sd)
+	}
+}
+
+func TestVWAP(t *testing.T) {
+	v := NewVWAP()
+	v.Add(broker.Kline{High: 12, Low: 9, Close: 9, Volume: 1}) // Typical 10
+	v.Add(broker.Kline{High: 21, Low: 19, Close: 20, Volume: 3})
+
+	if want := (10.0 + 60) / 4; !approx(v.Value(), want) {
+		t.Errorf("VWAP = %v, want %v", v.Value(), want)
+	}
+
+	v.Reset()
+	if v.Ready() || v.Value() != 0 {
+		t.Errorf("VWAP after Reset = %v, want 0", v.Value())
+	}
+}
+
+func TestFeed_SkipsOpenCandles(t *testing.T) {
+	sma := NewSMA(1)
+	Feed(broker.Kline{Close: 5, Closed: true}, sma)
+	Feed(broker.Kline{Close: 9}, sma)
+
+	if sma.Value() != 5 {
+		t.Errorf("SMA = %v, want 5 (open candle ignored)", sma.Value())
+	}
+}
@@ -0,0 +1,373 @@
+// Package discord posts fills and alerts to Discord channels as rich embeds
+// through channel webhooks, and answers read-only slash commands (/balance,
+// /positions, /orders) through an interactions endpoint.
+//
+// Slash commands need an application whose Interactions Endpoint URL points
+// at Handler, with the commands registered as CHAT_INPUT commands named as
+// in Commands.
+package discord
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/trading-go/analytics"
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// MaxBodySize bounds accepted interaction payloads
+const MaxBodySize = 64 << 10
+
+// maxFields is the most fields Discord accepts in one embed
+const maxFields = 25
+
+// Commands are the slash commands Handler answers
+var Commands = []string{"balance", "positions", "orders"}
+
+// ErrWebhook is matched by errors.Is for failed webhook posts
+var ErrWebhook = errors.New("discord: webhook error")
+
+// Kind is a kind of notification
+type Kind string
+
+const (
+	KindFill  Kind = "FILL"
+	KindAlert Kind = "ALERT"
+)
+
+// Level is the severity of an alert, shown as the embed color
+type Level int
+
+const (
+	LevelInfo Level = iota
+	LevelWarning
+	LevelCritical
+)
+
+// Embed colors
+const (
+	colorLong     = 0x16a34a
+	colorShort    = 0xdc2626
+	colorInfo     = 0x2563eb
+	colorWarning  = 0xf59e0b
+	colorCritical = 0xb91c1c
+)
+
+// Channel configures one Discord channel
+type Channel struct {
+	// WebhookURL posts notifications to the channel (empty = no notifications)
+	WebhookURL string
+	// Kinds selects the notifications posted (empty = all)
+	Kinds []Kind
+	// Commands enables slash commands in the channel
+	Commands bool
+}
+
+// Config configures the integration
+type Config struct {
+	// Channels by Discord channel ID
+	Channels map[string]Channel
+	// PublicKey is the application's hex-encoded public key, used to verify
+	// interactions
+	PublicKey string
+	// HTTPClient posts to webhooks (default http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// Discord sends notifications and serves slash commands for a broker
+type Discord struct {
+	broker    broker.Broker
+	config    Config
+	publicKey ed25519.PublicKey
+}
+
+// New creates the integration for b
+func New(b broker.Broker, config Config) (*Discord, error) {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	d := &Discord{broker: b, config: config}
+	if config.PublicKey != "" {
+		key, err := hex.DecodeString(config.PublicKey)
+		if err != nil || len(key) != ed25519.PublicKeySize {
+			return nil, errors.New("discord: invalid public key")
+		}
+		d.publicKey = key
+	}
+	return d, nil
+}
+
+// Embed is a Discord rich embed
+type Embed struct {
+	Title       string       `json:"title,omitempty"`
+	Description string       `json:"description,omitempty"`
+	Color       int          `json:"color,omitempty"`
+	Fields      []EmbedField `json:"fields,omitempty"`
+	Timestamp   string       `json:"timestamp,omitempty"`
+}
+
+// EmbedField is a name/value pair shown in an embed
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// NotifyFill posts a fill to the channels subscribed to KindFill
+func (d *Discord) NotifyFill(ctx context.Context, f analytics.Fill) error {
+	color, action := colorLong, "Bought"
+	if f.Side == broker.SideShort {
+		color, action = colorShort, "Sold"
+	}
+	return d.Notify(ctx, KindFill, Embed{
+		Title: fmt.Sprintf("%s %g %s", action, f.Size, f.Symbol),
+		Color: color,
+		Fields: []EmbedField{
+			{Name: "Price", Value: fmt.Sprintf("%g", f.Price), Inline: true},
+			{Name: "Notional", Value: fmt.Sprintf("%.2f", f.Price*f.Size), Inline: true},
+			{Name: "Fee", Value: fmt.Sprintf("%.4f", f.Fee), Inline: true},
+		},
+		Timestamp: timestamp(f.Time),
+	})
+}
+
+// Alert posts an alert to the channels subscribed to KindAlert
+func (d *Discord) Alert(ctx context.Context, level Level, title, message string) error {
+	color := colorInfo
+	switch level {
+	case LevelWarning:
+		color = colorWarning
+	case LevelCritical:
+		color = colorCritical
+	}
+	return d.Notify(ctx, KindAlert, Embed{Title: title, Description: message, Color: color, Timestamp: timestamp(time.Now())})
+}
+
+// Notify posts embeds to every channel subscribed to kind
+func (d *Discord) Notify(ctx context.Context, kind Kind, embeds ...Embed) error {
+	var errs []error
+	for id, ch := range d.config.Channels {
+		if ch.WebhookURL == "" || (len(ch.Kinds) > 0 && !slices.Contains(ch.Kinds, kind)) {
+			continue
+		}
+		if err := d.post(ctx, ch.WebhookURL, embeds); err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post sends embeds to a webhook
+func (d *Discord) post(ctx context.Context, webhookURL string, embeds []Embed) error {
+	body, err := json.Marshal(map[string]any{"embeds": embeds})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := d.config.HTTPClient.Do(req)
+	if err != nil {
+		// The webhook URL embeds its token; keep it out of error messages
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("%w: %v", ErrWebhook, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%w: HTTP %d: %s", ErrWebhook, resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
+
+// Interaction types and response types of the Discord API
+const (
+	interactionPing    = 1
+	interactionCommand = 2
+
+	responsePong    = 1
+	responseMessage = 4
+
+	flagEphemeral = 1 << 6
+)
+
+type interaction struct {
+	Type      int    `json:"type"`
+	ChannelID string `json:"channel_id"`
+	Data      struct {
+		Name string `json:"name"`
+	} `json:"data"`
+}
+
+type interactionResponse struct {
+	Type int                      `json:"type"`
+	Data *interactionResponseData `json:"data,omitempty"`
+}
+
+type interactionResponseData struct {
+	Content string  `json:"content,omitempty"`
+	Embeds  []Embed `json:"embeds,omitempty"`
+	Flags   int     `json:"flags,omitempty"`
+}
+
+// Handler returns the interactions endpoint. Requests must carry a valid
+// signature for Config.PublicKey; commands are answered only in channels
+// with Commands enabled.
+func (d *Discord) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize))
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if !d.verify(r.Header, body) {
+			http.Error(w, "invalid request signature", http.StatusUnauthorized)
+			return
+		}
+
+		var in interaction
+		if err := json.Unmarshal(body, &in); err != nil {
+			http.Error(w, "invalid JSON", http.StatusBadRequest)
+			return
+		}
+
+		switch in.Type {
+		case interactionPing:
+			writeJSON(w, interactionResponse{Type: responsePong})
+		case interactionCommand:
+			writeJSON(w, interactionResponse{Type: responseMessage, Data: d.command(r.Context(), in)})
+		default:
+			http.Error(w, "unsupported interaction", http.StatusBadRequest)
+		}
+	})
+}
+
+// verify checks the Ed25519 signature Discord puts on interactions
+func (d *Discord) verify(h http.Header, body []byte) bool {
+	if d.publicKey == nil {
+		return false
+	}
+	sig, err := hex.DecodeString(h.Get("X-Signature-Ed25519"))
+	if err != nil || len(sig) != ed25519.SignatureSize {
+		return false
+	}
+	msg := append([]byte(h.Get("X-Signature-Timestamp")), body...)
+	return ed25519.Verify(d.publicKey, msg, sig)
+}
+
+// command answers a slash command
+func (d *Discord) command(ctx context.Context, in interaction) *interactionResponseData {
+	if ch, ok := d.config.Channels[in.ChannelID]; !ok || !ch.Commands {
+		return &interactionResponseData{Content: "Commands are not enabled in this channel.", Flags: flagEphemeral}
+	}
+
+	var embed Embed
+	var err error
+	switch in.Data.Name {
+	case "balance":
+		embed, err = d.balance(ctx)
+	case "positions":
+		embed, err = d.positions(ctx)
+	case "orders":
+		embed, err = d.orders(ctx)
+	default:
+		return &interactionResponseData{Content: "Unknown command /" + in.Data.Name, Flags: flagEphemeral}
+	}
+	if err != nil {
+		return &interactionResponseData{Content: "Error: " + err.Error(), Flags: flagEphemeral}
+	}
+	return &interactionResponseData{Embeds: []Embed{embed}}
+}
+
+func (d *Discord) balance(ctx context.Context) (Embed, error) {
+	balance, err := d.broker.GetBalance(ctx)
+	if err != nil {
+		return Embed{}, err
+	}
+	return Embed{
+		Title: "Balance",
+		Color: colorInfo,
+		Fields: []EmbedField{
+			{Name: "Total", Value: fmt.Sprintf("%.2f %s", balance.Total, balance.Asset), Inline: true},
+			{Name: "Available", Value: fmt.Sprintf("%.2f", balance.Available), Inline: true},
+			{Name: "Unrealized PnL", Value: fmt.Sprintf("%+.2f", balance.UnrealizedPnL), Inline: true},
+		},
+	}, nil
+}
+
+func (d *Discord) positions(ctx context.Context) (Embed, error) {
+	positions, err := d.broker.GetPositions(ctx, nil)
+	if err != nil {
+		return Embed{}, err
+	}
+	embed := Embed{Title: "Positions", Color: colorInfo}
+	if len(positions) == 0 {
+		embed.Description = "No open positions."
+	}
+	for _, p := range positions {
+		if len(embed.Fields) == maxFields {
+			embed.Description = fmt.Sprintf("Showing %d of %d positions.", maxFields, len(positions))
+			break
+		}
+		embed.Fields = append(embed.Fields, EmbedField{
+			Name:  fmt.Sprintf("%s %s", p.Symbol, p.Side),
+			Value: fmt.Sprintf("%g @ %g\nPnL %+.2f", p.Size, p.EntryPrice, p.UnrealizedPnL),
+		})
+	}
+	return embed, nil
+}
+
+func (d *Discord) orders(ctx context.Context) (Embed, error) {
+	orders, err := d.broker.GetOrders(ctx, nil)
+	if err != nil {
+		return Embed{}, err
+	}
+	embed := Embed{Title: "Open Orders", Color: colorInfo}
+	if len(orders) == 0 {
+		embed.Description = "No open orders."
+	}
+	for _, o := range orders {
+		if len(embed.Fields) == maxFields {
+			embed.Description = fmt.Sprintf("Showing %d of %d orders.", maxFields, len(orders))
+			break
+		}
+		embed.Fields = append(embed.Fields, EmbedField{
+			Name:  fmt.Sprintf("%s %s %s", o.Symbol, o.Side, o.Type),
+			Value: fmt.Sprintf("%g @ %g (%s)", o.Size, o.Price, o.Status),
+		})
+	}
+	return embed, nil
+}
+
+func timestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,138 @@
+package discord
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/agatticelli/trading-go/analytics"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// webhook records posted embeds per path
+type webhook struct {
+	mu    sync.Mutex
+	posts map[string][]Embed
+}
+
+func (h *webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/broken" {
+		http.Error(w, `{"message": "Unknown Webhook"}`, http.StatusNotFound)
+		return
+	}
+	var body struct {
+		Embeds []Embed `json:"embeds"`
+	}
+	json.NewDecoder(r.Body).Decode(&body)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.posts[r.URL.Path] = append(h.posts[r.URL.Path], body.Embeds...)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func TestDiscord_Notify(t *testing.T) {
+	hook := &webhook{posts: make(map[string][]Embed)}
+	srv := httptest.NewServer(hook)
+	defer srv.Close()
+
+	d, err := New(brokertest.New(), Config{Channels: map[string]Channel{
+		"fills":  {WebhookURL: srv.URL + "/fills", Kinds: []Kind{KindFill}},
+		"alerts": {WebhookURL: srv.URL + "/alerts", Kinds: []Kind{KindAlert}},
+		"all":    {WebhookURL: srv.URL + "/all"},
+		"quiet":  {Commands: true},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := d.NotifyFill(ctx, analytics.Fill{Symbol: "BTC-USDT", Side: broker.SideShort, Price: 50000, Size: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Alert(ctx, LevelCritical, "Kill switch", "Trading halted"); err != nil {
+		t.Fatal(err)
+	}
+
+	fills := hook.posts["/fills"]
+	if len(fills) != 1 || fills[0].Title != "Sold 0.1 BTC-USDT" || fills[0].Color != colorShort || fills[0].Fields[1].Value != "5000.00" {
+		t.Errorf("fill embeds = %+v", fills)
+	}
+	if alerts := hook.posts["/alerts"]; len(alerts) != 1 || alerts[0].Color != colorCritical {
+		t.Errorf("alert embeds = %+v", alerts)
+	}
+	if all := hook.posts["/all"]; len(all) != 2 {
+		t.Errorf("unfiltered channel got %d embeds, want 2", len(all))
+	}
+
+	d.config.Channels["broken"] = Channel{WebhookURL: srv.URL + "/broken"}
+	if err := d.Alert(ctx, LevelInfo, "t", "m"); !errors.Is(err, ErrWebhook) || !strings.Contains(err.Error(), "channel broken") {
+		t.Errorf("Alert() error = %v, want ErrWebhook for the broken channel", err)
+	}
+}
+
+func TestDiscord_Handler(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.1, EntryPrice: 48000})
+
+	d, err := New(b, Config{
+		PublicKey: hex.EncodeToString(pub),
+		Channels: map[string]Channel{
+			"ops":   {Commands: true},
+			"fills": {WebhookURL: "http://unused"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := d.Handler()
+
+	send := func(body string, key ed25519.PrivateKey) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/interactions", strings.NewReader(body))
+		req.Header.Set("X-Signature-Timestamp", "1700000000")
+		req.Header.Set("X-Signature-Ed25519", hex.EncodeToString(ed25519.Sign(key, []byte("1700000000"+body))))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send(`{"type":1}`, priv); rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != `{"type":1}` {
+		t.Errorf("ping = %d %s, want pong", rec.Code, rec.Body)
+	}
+
+	_, otherKey, _ := ed25519.GenerateKey(nil)
+	if rec := send(`{"type":1}`, otherKey); rec.Code != http.StatusUnauthorized {
+		t.Errorf("forged request status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+
+	var resp interactionResponse
+	rec := send(`{"type":2,"channel_id":"ops","data":{"name":"positions"}}`, priv)
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil || resp.Type != responseMessage || len(resp.Data.Embeds) != 1 {
+		t.Fatalf("/positions = %s", rec.Body)
+	}
+	if f := resp.Data.Embeds[0].Fields; len(f) != 1 || f[0].Name != "BTC-USDT LONG" {
+		t.Errorf("/positions fields = %+v", f)
+	}
+
+	var refused interactionResponse
+	rec = send(`{"type":2,"channel_id":"fills","data":{"name":"balance"}}`, priv)
+	if err := json.Unmarshal(rec.Body.Bytes(), &refused); err != nil || refused.Data.Flags != flagEphemeral || len(refused.Data.Embeds) != 0 {
+		t.Errorf("command in channel without commands = %s, want ephemeral refusal", rec.Body)
+	}
+}
+
+func TestNew_InvalidPublicKey(t *testing.T) {
+	if _, err := New(brokertest.New(), Config{PublicKey: "abc"}); err == nil {
+		t.Error("New() accepted an invalid public key")
+	}
+}
@@ -0,0 +1,305 @@
+// Package telegram connects a broker.Broker to a Telegram bot: it sends
+// notifications to allowed chats and answers commands from them.
+//
+// Commands:
+//
+//	/balance          Account balance
+//	/positions        Open positions
+//	/close SYMBOL     Close every position on SYMBOL (asks for confirmation)
+//	/cancelall [SYM]  Cancel open orders (asks for confirmation)
+//	/confirm          Run the pending destructive command
+//	/abort            Drop the pending destructive command
+//
+// Messages from chats outside Config.AllowedChats are ignored.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Defaults
+const (
+	DefaultAPIURL         = "https://api.telegram.org"
+	DefaultPollTimeout    = 30 * time.Second
+	DefaultConfirmTimeout = time.Minute
+)
+
+// ErrAPI is matched by errors.Is for failed Bot API calls
+var ErrAPI = errors.New("telegram: API error")
+
+// Config configures the bot
+type Config struct {
+	// Token is the bot token from @BotFather
+	Token string
+	// AllowedChats are the chat IDs that receive notifications and may send
+	// commands
+	AllowedChats []int64
+	// APIURL overrides the Bot API base URL (default https://api.telegram.org)
+	APIURL string
+	// HTTPClient sends Bot API requests (default http.DefaultClient)
+	HTTPClient *http.Client
+	// PollTimeout is the long-polling timeout of getUpdates (default 30s)
+	PollTimeout time.Duration
+	// ConfirmTimeout is how long a destructive command waits for /confirm
+	// (default 1m)
+	ConfirmTimeout time.Duration
+}
+
+// Bot is a Telegram bot wired to a broker
+type Bot struct {
+	broker broker.Broker
+	config Config
+	now    func() time.Time
+
+	mu      sync.Mutex
+	pending map[int64]*pendingAction // Destructive command awaiting /confirm, per chat
+}
+
+// pendingAction is a destructive command awaiting confirmation
+type pendingAction struct {
+	description string
+	run         func(ctx context.Context) (string, error)
+	expires     time.Time
+}
+
+// New creates a bot for b
+func New(b broker.Broker, config Config) *Bot {
+	if config.APIURL == "" {
+		config.APIURL = DefaultAPIURL
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	if config.PollTimeout <= 0 {
+		config.PollTimeout = DefaultPollTimeout
+	}
+	if config.ConfirmTimeout <= 0 {
+		config.ConfirmTimeout = DefaultConfirmTimeout
+	}
+	return &Bot{broker: b, config: config, now: time.Now, pending: make(map[int64]*pendingAction)}
+}
+
+// Notify sends text to every allowed chat
+func (b *Bot) Notify(ctx context.Context, text string) error {
+	var errs []error
+	for _, chatID := range b.config.AllowedChats {
+		if err := b.Send(ctx, chatID, text); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Send sends text to one chat
+func (b *Bot) Send(ctx context.Context, chatID int64, text string) error {
+	return b.call(ctx, "sendMessage", map[string]any{"chat_id": chatID, "text": text}, nil)
+}
+
+// Run long-polls for messages and answers commands until the context is
+// canceled. API errors are returned immediately.
+func (b *Bot) Run(ctx context.Context) error {
+	offset := 0
+	for {
+		var updates []update
+		params := map[string]any{
+			"offset":          offset,
+			"timeout":         int(b.config.PollTimeout.Seconds()),
+			"allowed_updates": []string{"message"},
+		}
+		if err := b.call(ctx, "getUpdates", params, &updates); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+
+		for _, u := range updates {
+			offset = u.UpdateID + 1
+			if u.Message == nil || !b.allowed(u.Message.Chat.ID) {
+				continue
+			}
+			reply := b.Handle(ctx, u.Message.Chat.ID, u.Message.Text)
+			if reply == "" {
+				continue
+			}
+			if err := b.Send(ctx, u.Message.Chat.ID, reply); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Handle runs a command from chatID and returns the reply. It is exported
+// for bots that receive updates through a webhook instead of Run; callers
+// must check the chat is allowed.
+func (b *Bot) Handle(ctx context.Context, chatID int64, text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return ""
+	}
+	// Commands addressed in groups look like /balance@MyBot
+	command, _, _ := strings.Cut(strings.ToLower(fields[0]), "@")
+	args := fields[1:]
+
+	switch command {
+	case "/balance":
+		return b.balance(ctx)
+	case "/positions":
+		return b.positions(ctx)
+	case "/close":
+		if len(args) != 1 {
+			return "Usage: /close SYMBOL"
+		}
+		symbol := strings.ToUpper(args[0])
+		return b.ask(chatID, "close every "+symbol+" position", func(ctx context.Context) (string, error) {
+			orders, err := broker.FlattenPosition(ctx, b.broker, symbol)
+			if len(orders) == 0 && err == nil {
+				return "No open " + symbol + " position.", nil
+			}
+			return fmt.Sprintf("Placed %d closing order(s) for %s.", len(orders), symbol), err
+		})
+	case "/cancelall":
+		symbol, scope := "", "every symbol"
+		if len(args) > 0 {
+			symbol = strings.ToUpper(args[0])
+			scope = symbol
+		}
+		return b.ask(chatID, "cancel all open orders on "+scope, func(ctx context.Context) (string, error) {
+			return "Canceled open orders on " + scope + ".", b.broker.CancelAllOrders(ctx, symbol)
+		})
+	case "/confirm":
+		return b.confirm(ctx, chatID)
+	case "/abort":
+		b.mu.Lock()
+		delete(b.pending, chatID)
+		b.mu.Unlock()
+		return "Aborted."
+	case "/start", "/help":
+		return "Commands: /balance, /positions, /close SYMBOL, /cancelall [SYMBOL]"
+	default:
+		return "Unknown command " + command + ". Try /help."
+	}
+}
+
+// ask stores a destructive action until it is confirmed
+func (b *Bot) ask(chatID int64, description string, run func(ctx context.Context) (string, error)) string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.pending[chatID] = &pendingAction{description: description, run: run, expires: b.now().Add(b.config.ConfirmTimeout)}
+	return fmt.Sprintf("About to %s. Send /confirm within %v to proceed or /abort.", description, b.config.ConfirmTimeout)
+}
+
+// confirm runs the chat's pending action if it has not expired
+func (b *Bot) confirm(ctx context.Context, chatID int64) string {
+	b.mu.Lock()
+	action := b.pending[chatID]
+	delete(b.pending, chatID)
+	b.mu.Unlock()
+
+	if action == nil {
+		return "Nothing to confirm."
+	}
+	if b.now().After(action.expires) {
+		return "Confirmation expired; send the command again."
+	}
+
+	reply, err := action.run(ctx)
+	if err != nil {
+		return fmt.Sprintf("Failed to %s: %v", action.description, err)
+	}
+	return reply
+}
+
+func (b *Bot) balance(ctx context.Context) string {
+	balance, err := b.broker.GetBalance(ctx)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	return fmt.Sprintf("Balance: %.2f %s\nAvailable: %.2f\nIn use: %.2f\nUnrealized PnL: %+.2f",
+		balance.Total, balance.Asset, balance.Available, balance.InUse, balance.UnrealizedPnL)
+}
+
+func (b *Bot) positions(ctx context.Context) string {
+	positions, err := b.broker.GetPositions(ctx, nil)
+	if err != nil {
+		return "Error: " + err.Error()
+	}
+	if len(positions) == 0 {
+		return "No open positions."
+	}
+
+	var sb strings.Builder
+	for _, p := range positions {
+		fmt.Fprintf(&sb, "%s %s %g @ %g (PnL %+.2f)\n", p.Symbol, p.Side, p.Size, p.EntryPrice, p.UnrealizedPnL)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+func (b *Bot) allowed(chatID int64) bool {
+	return slices.Contains(b.config.AllowedChats, chatID)
+}
+
+// update is a Bot API update; only messages are requested
+type update struct {
+	UpdateID int `json:"update_id"`
+	Message  *struct {
+		Text string `json:"text"`
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+	} `json:"message"`
+}
+
+// call invokes a Bot API method and decodes its result into out (if non-nil)
+func (b *Bot) call(ctx context.Context, method string, params map[string]any, out any) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	endpoint := b.config.APIURL + "/bot" + url.PathEscape(b.config.Token) + "/" + method
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := b.config.HTTPClient.Do(req)
+	if err != nil {
+		// The URL embeds the token; keep it out of error messages
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram: %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		OK          bool            `json:"ok"`
+		Result      json.RawMessage `json:"result"`
+		Description string          `json:"description"`
+		ErrorCode   int             `json:"error_code"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("%w: %s: HTTP %d", ErrAPI, method, resp.StatusCode)
+	}
+	if !response.OK {
+		return fmt.Errorf("%w: %s: %s (code %d)", ErrAPI, method, response.Description, response.ErrorCode)
+	}
+	if out != nil {
+		return json.Unmarshal(response.Result, out)
+	}
+	return nil
+}
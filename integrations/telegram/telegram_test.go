@@ -0,0 +1,154 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newTestBot() (*Bot, *brokertest.Broker) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetBalance(broker.Balance{Asset: "USDT", Total: 1000, Available: 800})
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.1, EntryPrice: 48000})
+	b.AddOrder(broker.Order{ID: "1", Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 45000})
+	return New(b, Config{Token: "tok", AllowedChats: []int64{42}}), b
+}
+
+func TestBot_Queries(t *testing.T) {
+	bot, _ := newTestBot()
+	ctx := context.Background()
+
+	if reply := bot.Handle(ctx, 42, "/balance"); !strings.Contains(reply, "Balance: 1000.00 USDT") {
+		t.Errorf("/balance = %q", reply)
+	}
+	if reply := bot.Handle(ctx, 42, "/positions@TradingBot"); !strings.Contains(reply, "BTC-USDT LONG 0.1 @ 48000") {
+		t.Errorf("/positions = %q", reply)
+	}
+	if reply := bot.Handle(ctx, 42, "hello"); reply != "" {
+		t.Errorf("plain text reply = %q, want none", reply)
+	}
+}
+
+func TestBot_Confirmation(t *testing.T) {
+	bot, b := newTestBot()
+	now := time.Date(2024, 1, 5, 12, 0, 0, 0, time.UTC)
+	bot.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if reply := bot.Handle(ctx, 42, "/close btc-usdt"); !strings.Contains(reply, "/confirm") {
+		t.Fatalf("/close = %q, want a confirmation prompt", reply)
+	}
+	if positions, _ := b.GetPositions(ctx, nil); len(positions) != 1 {
+		t.Fatal("position closed before confirmation")
+	}
+	if reply := bot.Handle(ctx, 42, "/confirm"); !strings.Contains(reply, "Placed 1 closing order") {
+		t.Errorf("/confirm = %q", reply)
+	}
+	if positions, _ := b.GetPositions(ctx, nil); len(positions) != 0 {
+		t.Errorf("positions after /confirm = %d, want 0", len(positions))
+	}
+
+	// Expired confirmations do nothing
+	bot.Handle(ctx, 42, "/cancelall")
+	now = now.Add(2 * time.Minute)
+	if reply := bot.Handle(ctx, 42, "/confirm"); !strings.Contains(reply, "expired") {
+		t.Errorf("late /confirm = %q", reply)
+	}
+	if orders, _ := b.GetOrders(ctx, nil); len(orders) != 1 {
+		t.Error("orders canceled by an expired confirmation")
+	}
+
+	bot.Handle(ctx, 42, "/cancelall BTC-USDT")
+	bot.Handle(ctx, 42, "/abort")
+	if reply := bot.Handle(ctx, 42, "/confirm"); reply != "Nothing to confirm." {
+		t.Errorf("/confirm after /abort = %q", reply)
+	}
+}
+
+// fakeAPI serves getUpdates from a queue and records sent messages
+type fakeAPI struct {
+	mu      sync.Mutex
+	updates []string
+	sent    []map[string]any
+}
+
+func (f *fakeAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var params map[string]any
+	json.NewDecoder(r.Body).Decode(&params)
+	switch {
+	case strings.HasSuffix(r.URL.Path, "/getUpdates"):
+		result := "[]"
+		if len(f.updates) > 0 {
+			result, f.updates = f.updates[0], f.updates[1:]
+		}
+		w.Write([]byte(`{"ok":true,"result":` + result + `}`))
+	case strings.HasSuffix(r.URL.Path, "/sendMessage"):
+		f.sent = append(f.sent, params)
+		w.Write([]byte(`{"ok":true,"result":{}}`))
+	default:
+		w.Write([]byte(`{"ok":false,"error_code":404,"description":"Not Found"}`))
+	}
+}
+
+func (f *fakeAPI) messages() []map[string]any {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]map[string]any(nil), f.sent...)
+}
+
+func TestBot_Run(t *testing.T) {
+	api := &fakeAPI{updates: []string{
+		`[{"update_id":1,"message":{"text":"/balance","chat":{"id":7}}},
+		  {"update_id":2,"message":{"text":"/balance","chat":{"id":42}}}]`,
+	}}
+	srv := httptest.NewServer(api)
+	defer srv.Close()
+
+	bot, _ := newTestBot()
+	bot.config.APIURL = srv.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- bot.Run(ctx) }()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(api.messages()) == 0 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want context.Canceled", err)
+	}
+
+	sent := api.messages()
+	if len(sent) != 1 || sent[0]["chat_id"] != float64(42) || !strings.Contains(sent[0]["text"].(string), "Balance") {
+		t.Errorf("sent = %v, want one balance reply to the allowed chat only", sent)
+	}
+}
+
+func TestBot_APIError(t *testing.T) {
+	srv := httptest.NewServer(&fakeAPI{})
+	defer srv.Close()
+
+	bot, _ := newTestBot()
+	bot.config.APIURL = srv.URL
+	if err := bot.call(context.Background(), "nope", nil, nil); !errors.Is(err, ErrAPI) || !strings.Contains(err.Error(), "Not Found") {
+		t.Errorf("call() error = %v, want ErrAPI with description", err)
+	}
+	if err := bot.Notify(context.Background(), "filled"); err != nil {
+		t.Errorf("Notify() = %v", err)
+	}
+}
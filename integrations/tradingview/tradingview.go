@@ -0,0 +1,206 @@
+// Package tradingview turns TradingView alert webhooks into broker orders.
+//
+// Configure the alert message as JSON, for example:
+//
+//	{
+//	  "secret": "my-shared-secret",
+//	  "symbol": "{{ticker}}",
+//	  "action": "buy",
+//	  "quantity": 0.01,
+//	  "stop_loss": 41000,
+//	  "take_profit": 46000
+//	}
+package tradingview
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// MaxBodySize bounds accepted alert payloads
+const MaxBodySize = 64 << 10
+
+// Alert actions
+const (
+	ActionBuy        = "buy"
+	ActionSell       = "sell"
+	ActionCloseLong  = "close_long"
+	ActionCloseShort = "close_short"
+)
+
+// Errors returned while validating alerts
+var (
+	ErrInvalidSecret = errors.New("tradingview: invalid secret")
+	ErrInvalidAlert  = errors.New("tradingview: invalid alert")
+)
+
+// Alert is the JSON payload of a TradingView webhook
+type Alert struct {
+	Secret     string  `json:"secret"`
+	Symbol     string  `json:"symbol"`
+	Action     string  `json:"action"`     // buy/long, sell/short, close_long, close_short
+	OrderType  string  `json:"order_type"` // market (default) or limit
+	Quantity   float64 `json:"quantity"`
+	Price      float64 `json:"price"` // Required for limit orders
+	StopLoss   float64 `json:"stop_loss"`
+	TakeProfit float64 `json:"take_profit"`
+}
+
+// OrderRequest translates the alert into a broker order
+func (a *Alert) OrderRequest() (*broker.OrderRequest, error) {
+	if a.Symbol == "" {
+		return nil, fmt.Errorf("%w: missing symbol", ErrInvalidAlert)
+	}
+	if a.Quantity <= 0 {
+		return nil, fmt.Errorf("%w: quantity must be positive", ErrInvalidAlert)
+	}
+
+	req := &broker.OrderRequest{
+		Symbol: a.Symbol,
+		Type:   broker.OrderTypeMarket,
+		Size:   a.Quantity,
+	}
+
+	switch strings.ToLower(a.Action) {
+	case ActionBuy, "long":
+		req.Side = broker.SideLong
+	case ActionSell, "short":
+		req.Side = broker.SideShort
+	case ActionCloseLong:
+		req.Side, req.ReduceOnly = broker.SideShort, true
+	case ActionCloseShort:
+		req.Side, req.ReduceOnly = broker.SideLong, true
+	default:
+		return nil, fmt.Errorf("%w: unknown action %q", ErrInvalidAlert, a.Action)
+	}
+
+	switch strings.ToLower(a.OrderType) {
+	case "", "market":
+	case "limit":
+		if a.Price <= 0 {
+			return nil, fmt.Errorf("%w: limit order without price", ErrInvalidAlert)
+		}
+		req.Type = broker.OrderTypeLimit
+		req.Price = a.Price
+		req.TimeInForce = broker.TimeInForceGTC
+	default:
+		return nil, fmt.Errorf("%w: unknown order type %q", ErrInvalidAlert, a.OrderType)
+	}
+
+	if a.StopLoss > 0 {
+		req.StopLoss = &broker.StopLossConfig{TriggerPrice: a.StopLoss}
+	}
+	if a.TakeProfit > 0 {
+		req.TakeProfit = &broker.TakeProfitConfig{TriggerPrice: a.TakeProfit}
+	}
+
+	return req, nil
+}
+
+// Config configures the webhook handler
+type Config struct {
+	// Secret must match the alert's "secret" field
+	Secret string
+	// SymbolMapper converts TradingView tickers (e.g. "BTCUSDT.P") to broker
+	// symbols. Defaults to passing them through.
+	SymbolMapper func(string) string
+	// OnOrder is called after every submission attempt, e.g. for logging
+	OnOrder func(alert Alert, order *broker.Order, err error)
+}
+
+// Handler is an http.Handler that places orders from TradingView alerts
+type Handler struct {
+	broker broker.Broker
+	config Config
+}
+
+// NewHandler creates a webhook handler submitting orders to b
+func NewHandler(b broker.Broker, config Config) *Handler {
+	return &Handler{broker: b, config: config}
+}
+
+// ServeHTTP validates the alert and places the order. It responds 401 for
+// a bad secret, 400 for malformed alerts and 502 when the broker rejects
+// the order.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeJSON(w, http.StatusMethodNotAllowed, map[string]string{"error": "method not allowed"})
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "failed to read body"})
+		return
+	}
+
+	var alert Alert
+	if err := json.Unmarshal(body, &alert); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid JSON"})
+		return
+	}
+
+	if h.config.Secret == "" || subtle.ConstantTimeCompare([]byte(alert.Secret), []byte(h.config.Secret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": ErrInvalidSecret.Error()})
+		return
+	}
+	alert.Secret = ""
+
+	if h.config.SymbolMapper != nil {
+		alert.Symbol = h.config.SymbolMapper(alert.Symbol)
+	}
+
+	req, err := alert.OrderRequest()
+	if err != nil {
+		h.notify(alert, nil, err)
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": err.Error()})
+		return
+	}
+
+	order, err := h.broker.PlaceOrder(r.Context(), req)
+	h.notify(alert, order, err)
+	if err != nil {
+		writeJSON(w, http.StatusBadGateway, map[string]string{"error": err.Error()})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"orderId": order.ID, "status": string(order.Status)})
+}
+
+func (h *Handler) notify(alert Alert, order *broker.Order, err error) {
+	if h.config.OnOrder != nil {
+		h.config.OnOrder(alert, order, err)
+	}
+}
+
+// SymbolFromTicker converts common TradingView tickers such as "BTCUSDT" or
+// "BINANCE:BTCUSDT.P" to dash-separated symbols like "BTC-USDT"
+func SymbolFromTicker(ticker string) string {
+	if i := strings.LastIndex(ticker, ":"); i >= 0 {
+		ticker = ticker[i+1:]
+	}
+	ticker = strings.TrimSuffix(strings.ToUpper(ticker), ".P")
+	if strings.Contains(ticker, "-") {
+		return ticker
+	}
+
+	for _, quote := range []string{"USDT", "USDC", "USD"} {
+		if base, ok := strings.CutSuffix(ticker, quote); ok && base != "" {
+			return base + "-" + quote
+		}
+	}
+	return ticker
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
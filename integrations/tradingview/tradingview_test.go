@@ -0,0 +1,148 @@
+package tradingview
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestAlert_OrderRequest(t *testing.T) {
+	tests := []struct {
+		name           string
+		alert          Alert
+		wantSide       broker.Side
+		wantType       broker.OrderType
+		wantReduceOnly bool
+		wantErr        bool
+	}{
+		{"Buy", Alert{Symbol: "BTC-USDT", Action: "buy", Quantity: 1}, broker.SideLong, broker.OrderTypeMarket, false, false},
+		{"Short alias", Alert{Symbol: "BTC-USDT", Action: "SHORT", Quantity: 1}, broker.SideShort, broker.OrderTypeMarket, false, false},
+		{"Close long", Alert{Symbol: "BTC-USDT", Action: "close_long", Quantity: 1}, broker.SideShort, broker.OrderTypeMarket, true, false},
+		{"Close short", Alert{Symbol: "BTC-USDT", Action: "close_short", Quantity: 1}, broker.SideLong, broker.OrderTypeMarket, true, false},
+		{"Limit", Alert{Symbol: "BTC-USDT", Action: "buy", OrderType: "limit", Price: 100, Quantity: 1}, broker.SideLong, broker.OrderTypeLimit, false, false},
+		{"Limit without price", Alert{Symbol: "BTC-USDT", Action: "buy", OrderType: "limit", Quantity: 1}, "", "", false, true},
+		{"Unknown action", Alert{Symbol: "BTC-USDT", Action: "hodl", Quantity: 1}, "", "", false, true},
+		{"Missing quantity", Alert{Symbol: "BTC-USDT", Action: "buy"}, "", "", false, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := tt.alert.OrderRequest()
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidAlert) {
+					t.Errorf("OrderRequest() error = %v, want ErrInvalidAlert", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("OrderRequest() error = %v", err)
+			}
+			if req.Side != tt.wantSide || req.Type != tt.wantType || req.ReduceOnly != tt.wantReduceOnly {
+				t.Errorf("OrderRequest() = %s %s reduceOnly=%v, want %s %s reduceOnly=%v",
+					req.Side, req.Type, req.ReduceOnly, tt.wantSide, tt.wantType, tt.wantReduceOnly)
+			}
+		})
+	}
+}
+
+func TestSymbolFromTicker(t *testing.T) {
+	tests := map[string]string{
+		"BTCUSDT":           "BTC-USDT",
+		"BINANCE:ETHUSDT.P": "ETH-USDT",
+		"solusdc":           "SOL-USDC",
+		"BTC-USDT":          "BTC-USDT",
+	}
+	for ticker, want := range tests {
+		if got := SymbolFromTicker(ticker); got != want {
+			t.Errorf("SymbolFromTicker(%q) = %q, want %q", ticker, got, want)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		body       string
+		brokerErr  error
+		wantStatus int
+		wantPlaced int
+	}{
+		{
+			name:       "Valid alert",
+			body:       `{"secret":"s3cret","symbol":"BTCUSDT.P","action":"buy","quantity":0.5,"stop_loss":90,"take_profit":120}`,
+			wantStatus: http.StatusOK,
+			wantPlaced: 1,
+		},
+		{
+			name:       "Wrong secret",
+			body:       `{"secret":"guess","symbol":"BTCUSDT","action":"buy","quantity":0.5}`,
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "Malformed JSON",
+			body:       `buy BTCUSDT`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Invalid alert",
+			body:       `{"secret":"s3cret","symbol":"BTCUSDT","action":"hold","quantity":0.5}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Broker rejects",
+			body:       `{"secret":"s3cret","symbol":"BTCUSDT","action":"buy","quantity":0.5}`,
+			brokerErr:  broker.ErrInsufficientBalance,
+			wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:       "GET not allowed",
+			method:     http.MethodGet,
+			wantStatus: http.StatusMethodNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := brokertest.New()
+			b.SetPrice("BTC-USDT", 100)
+			b.Err = tt.brokerErr
+
+			var notified int
+			h := NewHandler(b, Config{
+				Secret:       "s3cret",
+				SymbolMapper: SymbolFromTicker,
+				OnOrder:      func(Alert, *broker.Order, error) { notified++ },
+			})
+
+			method := tt.method
+			if method == "" {
+				method = http.MethodPost
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, httptest.NewRequest(method, "/webhook", strings.NewReader(tt.body)))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body)
+			}
+
+			placed := b.PlacedOrders()
+			if len(placed) != tt.wantPlaced {
+				t.Fatalf("placed %d orders, want %d", len(placed), tt.wantPlaced)
+			}
+			if tt.wantPlaced > 0 {
+				if placed[0].Symbol != "BTC-USDT" || placed[0].StopLoss == nil || placed[0].TakeProfit == nil {
+					t.Errorf("order = %+v, want BTC-USDT with SL/TP", placed[0])
+				}
+				if notified != 1 {
+					t.Errorf("OnOrder called %d times, want 1", notified)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+// Package adk is the adapter development kit: the exchange-independent
+// pieces every broker adapter needs, so a new adapter is mostly mapping
+// code. It covers parameter encoding and request signing, a transport with
+// retries and client-side rate limiting, HTTP and code/msg envelope error
+// mapping to broker errors, and websocket reconnection. Run
+// brokertest.RunConformance against the finished adapter.
+package adk
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/credentials"
+	"github.com/agatticelli/trading-go/signing"
+)
+
+// EncodeParams canonicalizes request parameters. Parameters are sorted by
+// name; payload joins the raw name=value pairs with '&' for signing, and
+// encoded is the same pairs percent-encoded for a query string or form
+// body. Spaces become %20 rather than '+', and a literal '+' becomes %2B,
+// so no value can decode differently from what was signed.
+//
+// Every signed request goes through here, so it builds both strings in a
+// pooled buffer and allocates little beyond the results.
+func EncodeParams(params map[string]string) (payload, encoded string) {
+	e := encoders.Get().(*encoder)
+	defer encoders.Put(e)
+	defer clear(e.keys) // Don't pin the caller's strings in the pool
+
+	e.keys = appendSortedKeys(e.keys[:0], params)
+	e.buf = e.buf[:0]
+	for i, key := range e.keys {
+		if i > 0 {
+			e.buf = append(e.buf, '&')
+		}
+		e.buf = append(e.buf, key...)
+		e.buf = append(e.buf, '=')
+		e.buf = append(e.buf, params[key]...)
+	}
+	payload = string(e.buf)
+
+	e.buf = e.buf[:0]
+	for i, key := range e.keys {
+		if i > 0 {
+			e.buf = append(e.buf, '&')
+		}
+		e.buf = AppendEscaped(e.buf, key)
+		e.buf = append(e.buf, '=')
+		e.buf = AppendEscaped(e.buf, params[key])
+	}
+	return payload, string(e.buf)
+}
+
+// encoder is the scratch space of one EncodeParams call
+type encoder struct {
+	keys []string
+	buf  []byte
+}
+
+var encoders = sync.Pool{New: func() any { return new(encoder) }}
+
+// EscapeParam percent-encodes a parameter name or value
+func EscapeParam(s string) string {
+	for i := 0; i < len(s); i++ {
+		if !unreserved(s[i]) {
+			return string(AppendEscaped(make([]byte, 0, len(s)+16), s))
+		}
+	}
+	return s
+}
+
+// AppendEscaped appends s percent-encoded like EscapeParam to dst
+func AppendEscaped(dst []byte, s string) []byte {
+	const hex = "0123456789ABCDEF"
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; unreserved(c) {
+			dst = append(dst, c)
+		} else {
+			dst = append(dst, '%', hex[c>>4], hex[c&0x0F])
+		}
+	}
+	return dst
+}
+
+// unreserved reports whether url.QueryEscape leaves c as is
+func unreserved(c byte) bool {
+	return 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' ||
+		c == '-' || c == '_' || c == '.' || c == '~'
+}
+
+// SortedKeys returns the parameter names in ascending order
+func SortedKeys(params map[string]string) []string {
+	return appendSortedKeys(make([]string, 0, len(params)), params)
+}
+
+// appendSortedKeys appends the parameter names to keys and sorts them
+func appendSortedKeys(keys []string, params map[string]string) []string {
+	for k := range params {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// Sign signs payload with the secret, reporting failures as a SIGN_ERROR
+// matching broker.ErrAuthFailed
+func Sign(exchange string, s signing.Signer, secret string, payload []byte) (string, error) {
+	signature, err := s.Sign(secret, payload)
+	if err != nil {
+		return "", broker.NewBrokerError(exchange, "SIGN_ERROR", "Failed to sign request", errors.Join(broker.ErrAuthFailed, err))
+	}
+	return signature, nil
+}
+
+// Credentials retrieves the API keys for one request, reporting failures
+// as a CREDENTIALS_ERROR matching broker.ErrAuthFailed
+func Credentials(ctx context.Context, exchange string, p credentials.Provider) (credentials.Credentials, error) {
+	creds, err := p.Retrieve(ctx)
+	if err != nil {
+		return creds, broker.NewBrokerError(exchange, "CREDENTIALS_ERROR", "Failed to retrieve API credentials", errors.Join(broker.ErrAuthFailed, err))
+	}
+	return creds, nil
+}
+
+// Sleep waits for d or until ctx ends
+func Sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,76 @@
+package adk
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Envelope names the fields of an exchange's response wrapper, e.g.
+// {"code":0,"msg":"","data":{...}} or {"retCode":0,"retMsg":"OK","result":{...}}
+type Envelope struct {
+	Code string // Field holding the result code
+	Msg  string // Field holding the error message
+	Data string // Field holding the payload ("" for the whole body)
+	// Success is the code of successful responses, compared as text so
+	// exchanges sending 0 and "0" work alike. Responses without a code
+	// field are successful too.
+	Success string
+}
+
+// Decode unmarshals a response body wrapped in env, returning its payload
+// or the API error it reports as a BrokerError with code API_<code>. The
+// code is checked before the payload is parsed: error responses often carry
+// an empty object or array in place of the usual data. what names the
+// response in PARSE_ERROR messages. With no Data field, a body that is a
+// JSON array is the payload itself: some endpoints return bare record lists
+// on success and the envelope only on failure.
+func Decode[T any](exchange string, env Envelope, body []byte, what string) (T, error) {
+	var zero T
+	msg := "Failed to parse response"
+	if what != "" {
+		msg = "Failed to parse " + what + " response"
+	}
+
+	if trimmed := bytes.TrimSpace(body); env.Data == "" && len(trimmed) > 0 && trimmed[0] == '[' {
+		var v T
+		if err := json.Unmarshal(trimmed, &v); err != nil {
+			return zero, broker.NewBrokerError(exchange, "PARSE_ERROR", msg, err)
+		}
+		return v, nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return zero, broker.NewBrokerError(exchange, "PARSE_ERROR", msg, err)
+	}
+	if code, ok := fields[env.Code]; ok {
+		if c := strings.Trim(string(code), `"`); c != env.Success {
+			return zero, broker.NewBrokerError(exchange, "API_"+c, text(fields[env.Msg]), nil)
+		}
+	}
+
+	data := json.RawMessage(body)
+	if env.Data != "" {
+		data = fields[env.Data]
+	}
+	if len(data) == 0 {
+		return zero, nil
+	}
+	var v T
+	if err := json.Unmarshal(data, &v); err != nil {
+		return zero, broker.NewBrokerError(exchange, "PARSE_ERROR", msg, err)
+	}
+	return v, nil
+}
+
+// text returns a JSON string's value, or the raw JSON of anything else
+func text(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	return string(raw)
+}
@@ -0,0 +1,73 @@
+package adk
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestDecode(t *testing.T) {
+	type price struct {
+		Price string `json:"price"`
+	}
+	numeric := Envelope{Code: "code", Msg: "msg", Data: "data", Success: "0"}
+	textual := Envelope{Code: "retCode", Msg: "retMsg", Data: "result", Success: "0"}
+	bare := Envelope{Code: "code", Msg: "msg", Success: "0"}
+
+	tests := []struct {
+		name     string
+		env      Envelope
+		body     string
+		want     string
+		wantCode string
+		wantMsg  string
+	}{
+		{name: "success", env: numeric, body: `{"code":0,"msg":"","data":{"price":"100.5"}}`, want: "100.5"},
+		{name: "quoted code", env: textual, body: `{"retCode":"0","retMsg":"OK","result":{"price":"7"}}`, want: "7"},
+		{name: "no data", env: numeric, body: `{"code":0,"msg":""}`},
+		{name: "unwrapped success", env: bare, body: `{"price":"3"}`, want: "3"},
+		{name: "api error", env: numeric, body: `{"code":101204,"msg":"Insufficient margin","data":[]}`,
+			wantCode: "API_101204", wantMsg: "Insufficient margin"},
+		{name: "quoted api error", env: textual, body: `{"retCode":"10001","retMsg":"params error","result":{}}`,
+			wantCode: "API_10001", wantMsg: "params error"},
+		{name: "mismatched data", env: numeric, body: `{"code":0,"msg":"","data":[]}`,
+			wantCode: "PARSE_ERROR", wantMsg: "Failed to parse price response"},
+		{name: "not json", env: numeric, body: `Bad Gateway`,
+			wantCode: "PARSE_ERROR", wantMsg: "Failed to parse price response"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode[price]("test", tt.env, []byte(tt.body), "price")
+			if tt.wantCode == "" {
+				if err != nil || got.Price != tt.want {
+					t.Fatalf("Decode() = %+v, %v, want price %q", got, err, tt.want)
+				}
+				return
+			}
+			var brokerErr *broker.BrokerError
+			if !errors.As(err, &brokerErr) || brokerErr.Code != tt.wantCode || brokerErr.Message != tt.wantMsg {
+				t.Fatalf("Decode() error = %v, want %s %q", err, tt.wantCode, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestDecode_UnwrappedArray(t *testing.T) {
+	type price struct {
+		Price string `json:"price"`
+	}
+	bare := Envelope{Code: "code", Msg: "msg", Success: "0"}
+
+	got, err := Decode[[]price]("test", bare, []byte(` [{"price":"4"},{"price":"5"}]`), "prices")
+	if err != nil || len(got) != 2 || got[1].Price != "5" {
+		t.Fatalf("Decode() = %+v, %v, want both prices", got, err)
+	}
+
+	_, err = Decode[[]price]("test", bare, []byte(`{"code":100001,"msg":"signature mismatch"}`), "prices")
+	var brokerErr *broker.BrokerError
+	if !errors.As(err, &brokerErr) || brokerErr.Code != "API_100001" {
+		t.Errorf("Decode() error = %v, want API_100001", err)
+	}
+}
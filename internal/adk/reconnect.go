@@ -0,0 +1,93 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// ReconnectPolicy configures Reconnect
+type ReconnectPolicy struct {
+	BaseDelay time.Duration // First wait after a disconnect (default 1s)
+	MaxDelay  time.Duration // Longest wait between attempts (default 1m)
+	// StableAfter is how long a session must last to reset the backoff
+	// (default 1m)
+	StableAfter time.Duration
+	// MaxFailures gives up after this many consecutive short sessions
+	// (default: never)
+	MaxFailures int
+	Logger      *slog.Logger
+}
+
+// permanentError marks an error that reconnecting won't fix
+type permanentError struct{ err error }
+
+func (e permanentError) Error() string { return e.err.Error() }
+func (e permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so Reconnect returns it instead of reconnecting,
+// e.g. for a rejected subscription
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return permanentError{err}
+}
+
+// Reconnect runs a stream session with connect, reconnecting with
+// exponential backoff whenever it ends, until ctx is canceled. connect
+// should dial, subscribe and read until the connection fails. Reconnect
+// returns ctx's error, the error of a session that failed with a
+// Permanent error or broker.ErrAuthFailed, or the last error once
+// MaxFailures consecutive sessions ended before StableAfter.
+func Reconnect(ctx context.Context, p ReconnectPolicy, connect func(ctx context.Context) error) error {
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = time.Second
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = time.Minute
+	}
+	if p.StableAfter <= 0 {
+		p.StableAfter = time.Minute
+	}
+	log := logging.OrDiscard(p.Logger)
+
+	failures := 0
+	for {
+		start := time.Now()
+		err := connect(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		var permanent permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if errors.Is(err, broker.ErrAuthFailed) {
+			return err
+		}
+
+		if time.Since(start) >= p.StableAfter {
+			failures = 0
+		}
+		failures++
+		if p.MaxFailures > 0 && failures >= p.MaxFailures {
+			log.Error("stream giving up", "failures", failures, logging.KeyError, err)
+			return err
+		}
+
+		delay := p.BaseDelay
+		for i := 1; i < failures && delay < p.MaxDelay; i++ {
+			delay *= 2
+		}
+		delay = min(delay, p.MaxDelay)
+		log.Warn("stream reconnecting", "failures", failures, "delay", delay, logging.KeyError, err)
+		if err := Sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+}
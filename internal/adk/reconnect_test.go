@@ -0,0 +1,59 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestReconnect(t *testing.T) {
+	policy := ReconnectPolicy{BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+	dropped := errors.New("connection reset")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	sessions := 0
+	err := Reconnect(ctx, policy, func(ctx context.Context) error {
+		sessions++
+		if sessions == 3 {
+			cancel()
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		return dropped
+	})
+	if !errors.Is(err, context.Canceled) || sessions != 3 {
+		t.Errorf("Reconnect() = %v after %d sessions, want context.Canceled after 3", err, sessions)
+	}
+
+	rejected := errors.New("unknown channel")
+	sessions = 0
+	err = Reconnect(context.Background(), policy, func(ctx context.Context) error {
+		sessions++
+		return Permanent(rejected)
+	})
+	if err != rejected || sessions != 1 {
+		t.Errorf("Reconnect(permanent) = %v after %d sessions, want the unwrapped error at once", err, sessions)
+	}
+
+	sessions = 0
+	err = Reconnect(context.Background(), policy, func(ctx context.Context) error {
+		sessions++
+		return broker.ErrAuthFailed
+	})
+	if !errors.Is(err, broker.ErrAuthFailed) || sessions != 1 {
+		t.Errorf("Reconnect(auth failure) = %v after %d sessions, want no retry", err, sessions)
+	}
+
+	policy.MaxFailures = 4
+	sessions = 0
+	err = Reconnect(context.Background(), policy, func(ctx context.Context) error {
+		sessions++
+		return dropped
+	})
+	if err != dropped || sessions != 4 {
+		t.Errorf("Reconnect(MaxFailures 4) = %v after %d sessions, want giving up after 4", err, sessions)
+	}
+}
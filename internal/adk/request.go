@@ -0,0 +1,44 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// PrepareRequest adds the headers and request ID attached to the request's
+// context (broker.WithHeaders, broker.WithRequestID). Headers the adapter
+// already set are left alone.
+func PrepareRequest(req *http.Request) {
+	for name, values := range broker.HeadersFrom(req.Context()) {
+		if _, ok := req.Header[name]; !ok {
+			req.Header[name] = values
+		}
+	}
+	if id := broker.RequestIDFrom(req.Context()); id != "" && req.Header.Get(broker.HeaderRequestID) == "" {
+		req.Header.Set(broker.HeaderRequestID, id)
+	}
+}
+
+// Annotate sets the request ID attached to ctx on the BrokerError in err's
+// chain, unless it already has one
+func Annotate(ctx context.Context, err error) error {
+	id := broker.RequestIDFrom(ctx)
+	var be *broker.BrokerError
+	if id != "" && errors.As(err, &be) && be.RequestID == "" {
+		be.RequestID = id
+	}
+	return err
+}
+
+// RequestLogger adds the request ID attached to ctx to log
+func RequestLogger(ctx context.Context, log *slog.Logger) *slog.Logger {
+	if id := broker.RequestIDFrom(ctx); id != "" {
+		return log.With(logging.KeyRequestID, id)
+	}
+	return log
+}
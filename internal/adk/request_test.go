@@ -0,0 +1,44 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestPrepareRequest(t *testing.T) {
+	ctx := broker.WithRequestID(context.Background(), "req-7")
+	ctx = broker.WithHeaders(ctx, http.Header{"X-Trace": {"abc"}, "Content-Type": {"text/plain"}})
+	req, _ := http.NewRequestWithContext(ctx, http.MethodPost, "http://example.com", nil)
+	req.Header.Set("Content-Type", "application/json")
+
+	PrepareRequest(req)
+	if req.Header.Get("X-Trace") != "abc" || req.Header.Get(broker.HeaderRequestID) != "req-7" {
+		t.Errorf("headers = %v, want the context's trace and request ID", req.Header)
+	}
+	if req.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q, want the adapter's own header kept", req.Header.Get("Content-Type"))
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	ctx := broker.WithRequestID(context.Background(), "req-7")
+
+	err := Annotate(ctx, broker.NewBrokerError("test", "API_1", "bad", nil))
+	var be *broker.BrokerError
+	if !errors.As(err, &be) || be.RequestID != "req-7" {
+		t.Errorf("Annotate() = %v, want request ID req-7", err)
+	}
+
+	tagged := broker.NewBrokerError("test", "API_1", "bad", nil)
+	tagged.RequestID = "earlier"
+	if Annotate(ctx, tagged); tagged.RequestID != "earlier" {
+		t.Errorf("Annotate() replaced RequestID with %q", tagged.RequestID)
+	}
+	if Annotate(ctx, nil) != nil {
+		t.Error("Annotate(nil) != nil")
+	}
+}
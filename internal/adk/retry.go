@@ -0,0 +1,113 @@
+package adk
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// RetryPolicy configures retries of rate-limited and transient failures
+type RetryPolicy struct {
+	MaxAttempts int           // Total attempts including the first (default 3)
+	BaseDelay   time.Duration // First backoff when the exchange gives no hint (default 500ms)
+	MaxDelay    time.Duration // Longest wait before giving up (default 1m)
+}
+
+// WithDefaults fills in the zero fields
+func (p RetryPolicy) WithDefaults() RetryPolicy {
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = 3
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = 500 * time.Millisecond
+	}
+	if p.MaxDelay <= 0 {
+		p.MaxDelay = time.Minute
+	}
+	return p
+}
+
+// Backoff returns the exponential wait before retry attempt+1
+func (p RetryPolicy) Backoff(attempt int) time.Duration {
+	return min(p.BaseDelay<<(attempt-1), p.MaxDelay)
+}
+
+// Retry calls send until it succeeds, fails with an error Retryable
+// doesn't allow for a request classified s, or p runs out of attempts. It
+// waits as long as the error's RetryAfter asks and backs off exponentially
+// without a hint, giving up when the wait exceeds MaxDelay. A nil p sends
+// once.
+func Retry[T any](ctx context.Context, p *RetryPolicy, s Safety, send func() (T, error)) (T, error) {
+	if p == nil {
+		return send()
+	}
+	policy := p.WithDefaults()
+	for attempt := 1; ; attempt++ {
+		v, err := send()
+		if !Retryable(err, s) || attempt >= policy.MaxAttempts {
+			return v, err
+		}
+
+		delay, ok := broker.RetryAfter(err)
+		if !ok {
+			delay = policy.Backoff(attempt)
+		}
+		if delay > policy.MaxDelay {
+			return v, err
+		}
+		if err := Sleep(ctx, delay); err != nil {
+			return v, err
+		}
+	}
+}
+
+// Limiter is a token bucket pacing requests on the client side, so
+// adapters stay under documented limits instead of learning them from 429s.
+// It is safe for concurrent use.
+type Limiter struct {
+	interval time.Duration // Time to earn one token
+	burst    float64
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewLimiter allows n requests per window, in bursts of up to n
+func NewLimiter(n int, per time.Duration) *Limiter {
+	return &Limiter{
+		interval: per / time.Duration(n),
+		burst:    float64(n),
+		tokens:   float64(n),
+		last:     time.Now(),
+	}
+}
+
+// Wait blocks until a request may be sent or ctx ends. A nil limiter
+// never waits.
+func (l *Limiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	now := time.Now()
+	l.tokens = min(l.burst, l.tokens+float64(now.Sub(l.last))/float64(l.interval))
+	l.last = now
+	l.tokens-- // Reserve the token, going into debt if none is left
+	wait := time.Duration(-l.tokens * float64(l.interval))
+	l.mu.Unlock()
+
+	if wait <= 0 {
+		return nil
+	}
+	if err := Sleep(ctx, wait); err != nil {
+		l.mu.Lock()
+		l.tokens++ // Return the unused reservation
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
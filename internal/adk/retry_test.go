@@ -0,0 +1,140 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestRetry(t *testing.T) {
+	limited := func(wait time.Duration) error {
+		err := broker.NewBrokerError("test", "RATE_LIMITED", "slow down", broker.ErrRateLimited)
+		err.RetryAfter = wait
+		return err
+	}
+	policy := &RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond}
+
+	calls := 0
+	got, err := Retry(context.Background(), policy, Unsafe, func() (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, limited(0)
+		}
+		return 42, nil
+	})
+	if err != nil || got != 42 || calls != 3 {
+		t.Errorf("Retry() = %d, %v after %d calls, want 42 after 3", got, err, calls)
+	}
+
+	calls = 0
+	_, err = Retry(context.Background(), policy, Unsafe, func() (int, error) {
+		calls++
+		return 0, broker.ErrInsufficientBalance
+	})
+	if !errors.Is(err, broker.ErrInsufficientBalance) || calls != 1 {
+		t.Errorf("Retry(other error) = %v after %d calls, want no retry", err, calls)
+	}
+
+	calls = 0
+	_, err = Retry(context.Background(), policy, Unsafe, func() (int, error) {
+		calls++
+		return 0, limited(time.Hour)
+	})
+	if !errors.Is(err, broker.ErrRateLimited) || calls != 1 {
+		t.Errorf("Retry(long wait) = %v after %d calls, want giving up at once", err, calls)
+	}
+
+	calls = 0
+	_, err = Retry(context.Background(), nil, Unsafe, func() (int, error) {
+		calls++
+		return 0, limited(0)
+	})
+	if !errors.Is(err, broker.ErrRateLimited) || calls != 1 {
+		t.Errorf("Retry(nil policy) = %v after %d calls, want one attempt", err, calls)
+	}
+
+	dropped := TransientError("test", "REQUEST_FAILED", "HTTP request failed", errors.New("connection reset"))
+	for _, tt := range []struct {
+		safety    Safety
+		wantCalls int
+	}{{ReadOnly, 3}, {Idempotent, 3}, {Unsafe, 1}} {
+		calls = 0
+		_, err = Retry(context.Background(), policy, tt.safety, func() (int, error) {
+			calls++
+			return 0, dropped
+		})
+		if !errors.Is(err, ErrTransient) || calls != tt.wantCalls {
+			t.Errorf("Retry(%s, transient) = %v after %d calls, want %d", tt.safety, err, calls, tt.wantCalls)
+		}
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(2, 100*time.Millisecond)
+	ctx := context.Background()
+
+	start := time.Now()
+	for range 3 {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 40*time.Millisecond {
+		t.Errorf("3 requests at 2 per 100ms took %v, want the third paced", elapsed)
+	}
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := l.Wait(canceled); !errors.Is(err, context.Canceled) {
+		t.Errorf("Wait(canceled) = %v, want context.Canceled", err)
+	}
+
+	var unlimited *Limiter
+	if err := unlimited.Wait(ctx); err != nil {
+		t.Errorf("nil Limiter Wait() = %v", err)
+	}
+}
+
+func TestTransport_Do(t *testing.T) {
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hits.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer server.Close()
+
+	tr := &Transport{
+		Exchange: "test",
+		Retry:    &RetryPolicy{BaseDelay: time.Millisecond},
+		Limiter:  NewLimiter(10, time.Second),
+	}
+	builds := 0
+	body, err := tr.Do(context.Background(), ReadOnly, func(ctx context.Context) (*http.Request, error) {
+		builds++
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/ping", nil)
+	})
+	if err != nil || string(body) != `{"code":0}` {
+		t.Fatalf("Do() = %s, %v", body, err)
+	}
+	if builds != 2 {
+		t.Errorf("request built %d times, want once per attempt", builds)
+	}
+
+	tr.Retry = nil
+	hits.Store(0)
+	_, err = tr.Do(context.Background(), ReadOnly, func(ctx context.Context) (*http.Request, error) {
+		return http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/ping", nil)
+	})
+	if !errors.Is(err, broker.ErrRateLimited) {
+		t.Errorf("Do() without retries = %v, want ErrRateLimited", err)
+	}
+}
@@ -0,0 +1,67 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Safety classifies what sending a request again does when the outcome of
+// an earlier attempt is unknown: the connection dropped or a gateway error
+// came back after the exchange may already have acted on it
+type Safety int
+
+const (
+	// Unsafe requests may have taken effect (placing an order without a
+	// client order ID). They are only retried when the exchange rejected
+	// them before acting, as it does when rate limiting.
+	Unsafe Safety = iota
+	// Idempotent requests have the same effect however often they are sent
+	Idempotent
+	// ReadOnly requests have no effect
+	ReadOnly
+)
+
+// String returns the classification's name
+func (s Safety) String() string {
+	switch s {
+	case Idempotent:
+		return "idempotent"
+	case ReadOnly:
+		return "read-only"
+	}
+	return "unsafe"
+}
+
+// Classify classifies a request by method: GET and HEAD are read-only, PUT
+// and DELETE idempotent, anything else unsafe. key names the exchange's
+// idempotency parameter (a client order ID); when params carry it, an
+// otherwise unsafe request is idempotent because the exchange rejects the
+// duplicate instead of executing it twice.
+func Classify(method string, params map[string]string, key string) Safety {
+	switch method {
+	case http.MethodGet, http.MethodHead:
+		return ReadOnly
+	case http.MethodPut, http.MethodDelete:
+		return Idempotent
+	}
+	if key != "" && params[key] != "" {
+		return Idempotent
+	}
+	return Unsafe
+}
+
+// Retryable reports whether a request classified s that failed with err may
+// be sent again. Rate-limited requests always may; transient failures only
+// when the request is idempotent or read-only.
+func Retryable(err error, s Safety) bool {
+	switch {
+	case err == nil, errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return false
+	case errors.Is(err, broker.ErrRateLimited):
+		return true
+	}
+	return s != Unsafe && errors.Is(err, ErrTransient)
+}
@@ -0,0 +1,60 @@
+package adk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		method string
+		params map[string]string
+		want   Safety
+	}{
+		{http.MethodGet, nil, ReadOnly},
+		{http.MethodDelete, map[string]string{"orderId": "1"}, Idempotent},
+		{http.MethodPost, map[string]string{"symbol": "BTC-USDT"}, Unsafe},
+		{http.MethodPost, map[string]string{"symbol": "BTC-USDT", "clientOrderID": ""}, Unsafe},
+		{http.MethodPost, map[string]string{"symbol": "BTC-USDT", "clientOrderID": "abc"}, Idempotent},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.method, tt.params, "clientOrderID"); got != tt.want {
+			t.Errorf("Classify(%s, %v) = %s, want %s", tt.method, tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	limited := broker.NewBrokerError("test", "RATE_LIMITED", "slow down", broker.ErrRateLimited)
+	dropped := TransientError("test", "REQUEST_FAILED", "HTTP request failed", errors.New("connection reset"))
+	gateway := broker.NewBrokerError("test", "HTTP_ERROR", "HTTP 502", ErrServerError)
+	canceled := TransientError("test", "REQUEST_FAILED", "HTTP request failed", fmt.Errorf("get: %w", context.Canceled))
+
+	tests := []struct {
+		name   string
+		err    error
+		safety Safety
+		want   bool
+	}{
+		{"success", nil, ReadOnly, false},
+		{"rate limited unsafe", limited, Unsafe, true},
+		{"dropped read", dropped, ReadOnly, true},
+		{"dropped idempotent", dropped, Idempotent, true},
+		{"dropped unsafe", dropped, Unsafe, false},
+		{"gateway read", gateway, ReadOnly, true},
+		{"gateway unsafe", gateway, Unsafe, false},
+		{"canceled", canceled, ReadOnly, false},
+		{"api error", broker.ErrInsufficientBalance, ReadOnly, false},
+		{"maintenance", broker.NewBrokerError("test", "HTTP_ERROR", "HTTP 503", ErrMaintenance), ReadOnly, false},
+	}
+	for _, tt := range tests {
+		if got := Retryable(tt.err, tt.safety); got != tt.want {
+			t.Errorf("%s: Retryable(%v, %s) = %v, want %v", tt.name, tt.err, tt.safety, got, tt.want)
+		}
+	}
+}
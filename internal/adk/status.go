@@ -0,0 +1,121 @@
+package adk
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// maxErrorText caps how much of a non-JSON body is kept in error messages
+const maxErrorText = 200
+
+var htmlTitle = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// ErrMaintenance marks responses showing the API is down for maintenance:
+// HTTP 503 and maintenance pages served by edge proxies
+var ErrMaintenance = errors.Join(broker.ErrAPIError, broker.ErrMaintenance)
+
+// ErrTransient marks failures that leave a request's outcome unknown but
+// may not recur: network errors, unreadable responses and gateway errors.
+// Retryable decides whether such a request may be sent again.
+var ErrTransient = errors.New("transient failure")
+
+// ErrServerError marks HTTP 500, 502 and 504 responses
+var ErrServerError = errors.Join(broker.ErrAPIError, ErrTransient)
+
+// TransientError wraps a failure to send a request or read its response
+func TransientError(exchange, code, message string, err error) *broker.BrokerError {
+	return broker.NewBrokerError(exchange, code, message, errors.Join(ErrTransient, err))
+}
+
+// serverError reports whether status is a gateway or server error worth
+// retrying; 503 means maintenance, which outlasts any backoff
+func serverError(status int) bool {
+	switch status {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// StatusError converts an unusable response into a BrokerError: any
+// non-200 status, or a 200 whose body is an HTML page. HTTP 429 matches
+// broker.ErrRateLimited and carries the Retry-After wait; 503 and HTML
+// pages match broker.ErrMaintenance; 500, 502 and 504 match ErrTransient.
+// It returns nil for a usable response.
+func StatusError(exchange string, resp *http.Response, body []byte) error {
+	isHTML := IsHTML(resp, body)
+	if resp.StatusCode == http.StatusOK && !isHTML {
+		return nil
+	}
+
+	code, sentinel := "HTTP_ERROR", broker.ErrAPIError
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		code, sentinel = "RATE_LIMITED", broker.ErrRateLimited
+	case resp.StatusCode == http.StatusServiceUnavailable:
+		sentinel = ErrMaintenance
+	case serverError(resp.StatusCode):
+		sentinel = ErrServerError
+	case resp.StatusCode == http.StatusOK:
+		code, sentinel = "HTML_RESPONSE", ErrMaintenance
+	}
+
+	err := broker.NewBrokerError(exchange, code,
+		fmt.Sprintf("HTTP %d: %s", resp.StatusCode, ErrorText(resp.StatusCode, body, isHTML)), sentinel)
+	err.RetryAfter = ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return err
+}
+
+// IsHTML reports whether the response carries an HTML page rather than
+// JSON, by content type or, when that is missing, by sniffing
+func IsHTML(resp *http.Response, body []byte) bool {
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		return mediaType == "text/html"
+	}
+	return bytes.HasPrefix(bytes.TrimSpace(body), []byte("<"))
+}
+
+// ErrorText summarizes an error body: the page title of HTML, the
+// truncated text otherwise, or the status text of an empty body
+func ErrorText(status int, body []byte, isHTML bool) string {
+	if isHTML {
+		if m := htmlTitle.FindSubmatch(body); m != nil {
+			return strings.Join(strings.Fields(html.UnescapeString(string(m[1]))), " ")
+		}
+		return "HTML response"
+	}
+
+	text := strings.TrimSpace(string(body))
+	if text == "" {
+		return http.StatusText(status)
+	}
+	if len(text) > maxErrorText {
+		text = text[:maxErrorText] + "..."
+	}
+	return text
+}
+
+// ParseRetryAfter reads a Retry-After header given in seconds or as an
+// HTTP date, returning zero when absent or invalid
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,91 @@
+package adk
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestStatusError(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		contentType    string
+		retryAfter     string
+		body           string
+		wantCode       string
+		wantErr        error
+		wantMessage    string
+		wantRetryAfter time.Duration
+	}{
+		{name: "ok", status: http.StatusOK, contentType: "application/json", body: `{"code":0}`},
+		{name: "rate limited", status: http.StatusTooManyRequests, retryAfter: "7", body: `{"code":-1003}`,
+			wantCode: "RATE_LIMITED", wantErr: broker.ErrRateLimited, wantMessage: `HTTP 429: {"code":-1003}`, wantRetryAfter: 7 * time.Second},
+		{name: "unavailable", status: http.StatusServiceUnavailable,
+			wantCode: "HTTP_ERROR", wantErr: broker.ErrMaintenance, wantMessage: "HTTP 503: Service Unavailable"},
+		{name: "maintenance page", status: http.StatusOK, contentType: "text/html",
+			body:     "<html><head><title>Upgrade  in &amp; progress</title></head></html>",
+			wantCode: "HTML_RESPONSE", wantErr: broker.ErrMaintenance, wantMessage: "HTTP 200: Upgrade in & progress"},
+		{name: "sniffed html", status: http.StatusBadGateway, body: "<html>bad gateway</html>",
+			wantCode: "HTTP_ERROR", wantErr: broker.ErrAPIError, wantMessage: "HTTP 502: HTML response"},
+		{name: "gateway timeout", status: http.StatusGatewayTimeout,
+			wantCode: "HTTP_ERROR", wantErr: ErrTransient, wantMessage: "HTTP 504: Gateway Timeout"},
+		{name: "not found", status: http.StatusNotFound, body: "no such endpoint",
+			wantCode: "HTTP_ERROR", wantErr: broker.ErrAPIError, wantMessage: "HTTP 404: no such endpoint"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.contentType != "" {
+				resp.Header.Set("Content-Type", tt.contentType)
+			}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+
+			err := StatusError("test", resp, []byte(tt.body))
+			if tt.wantCode == "" {
+				if err != nil {
+					t.Fatalf("StatusError() = %v, want nil", err)
+				}
+				return
+			}
+			var brokerErr *broker.BrokerError
+			if !errors.As(err, &brokerErr) {
+				t.Fatalf("StatusError() = %v, want *broker.BrokerError", err)
+			}
+			if brokerErr.Code != tt.wantCode || brokerErr.Message != tt.wantMessage || !errors.Is(err, tt.wantErr) {
+				t.Errorf("StatusError() = %s %q (%v), want %s %q matching %v", brokerErr.Code, brokerErr.Message, err, tt.wantCode, tt.wantMessage, tt.wantErr)
+			}
+			if brokerErr.RetryAfter != tt.wantRetryAfter {
+				t.Errorf("RetryAfter = %v, want %v", brokerErr.RetryAfter, tt.wantRetryAfter)
+			}
+		})
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 10, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value string
+		want  time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"0", 0},
+		{"soon", 0},
+		{"Wed, 15 Oct 2025 12:01:30 GMT", 90 * time.Second},
+		{"Wed, 15 Oct 2025 11:00:00 GMT", 0},
+	}
+
+	for _, tt := range tests {
+		if got := ParseRetryAfter(tt.value, now); got != tt.want {
+			t.Errorf("ParseRetryAfter(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
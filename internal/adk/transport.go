@@ -0,0 +1,77 @@
+package adk
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// Transport sends an adapter's HTTP requests: it paces them with Limiter,
+// retries the ones Retryable allows per Retry and maps unusable responses to
+// broker errors with StatusError. API errors inside successful responses
+// are left to Decode.
+type Transport struct {
+	Exchange   string       // Names the exchange in errors
+	HTTPClient *http.Client // Default: 30s timeout
+	Retry      *RetryPolicy // Nil sends once
+	Limiter    *Limiter     // Nil doesn't pace requests
+	Logger     *slog.Logger
+}
+
+// defaultClient is used by Transports without an HTTPClient
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Do sends the request built by build and returns the body of a usable
+// response. build runs once per attempt so each retry is signed afresh
+// (signatures usually cover a timestamp). safety says whether the request
+// may be repeated after a transient failure. Headers and the request ID
+// attached to ctx are added to the request and the ID to errors.
+func (t *Transport) Do(ctx context.Context, safety Safety, build func(ctx context.Context) (*http.Request, error)) ([]byte, error) {
+	body, err := Retry(ctx, t.Retry, safety, func() ([]byte, error) {
+		if err := t.Limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		req, err := build(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return t.send(req)
+	})
+	return body, Annotate(ctx, err)
+}
+
+// send executes one request
+func (t *Transport) send(req *http.Request) ([]byte, error) {
+	client := t.HTTPClient
+	if client == nil {
+		client = defaultClient
+	}
+	PrepareRequest(req)
+	// The URL path is logged, never the signed query
+	log := RequestLogger(req.Context(), logging.OrDiscard(t.Logger)).With("method", req.Method, "path", req.URL.Path)
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		log.Warn("request failed", logging.KeyError, err, "duration", time.Since(start))
+		return nil, TransientError(t.Exchange, "REQUEST_FAILED", "HTTP request failed", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		log.Warn("reading response failed", "status", resp.StatusCode, logging.KeyError, err)
+		return nil, TransientError(t.Exchange, "READ_FAILED", "Failed to read response", err)
+	}
+	log.Debug("request", "status", resp.StatusCode, "duration", time.Since(start), "bytes", len(body))
+
+	if err := StatusError(t.Exchange, resp, body); err != nil {
+		log.Warn("request rejected", "status", resp.StatusCode, logging.KeyError, err)
+		return nil, err
+	}
+	return body, nil
+}
@@ -0,0 +1,69 @@
+// Package inflight counts in-flight operations so shutdown can refuse new
+// work and wait for running work to finish.
+package inflight
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracker counts operations between Begin and End. The zero value is ready
+// to use.
+type Tracker struct {
+	mu     sync.Mutex
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// Begin registers an operation, returning false once the tracker is closed
+func (t *Tracker) Begin() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return false
+	}
+	t.wg.Add(1)
+	return true
+}
+
+// End marks an operation started by Begin as finished
+func (t *Tracker) End() {
+	t.wg.Done()
+}
+
+// Close stops accepting new operations. It reports whether this call closed
+// the tracker.
+func (t *Tracker) Close() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed {
+		return false
+	}
+	t.closed = true
+	return true
+}
+
+// Closed reports whether Close has been called
+func (t *Tracker) Closed() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.closed
+}
+
+// Wait blocks until every operation has ended or ctx is done
+func (t *Tracker) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		t.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,33 @@
+package inflight
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	var tr Tracker
+	if !tr.Begin() {
+		t.Fatal("Begin() = false on open tracker")
+	}
+
+	if !tr.Close() || tr.Close() {
+		t.Error("Close() should report true only the first time")
+	}
+	if tr.Begin() {
+		t.Error("Begin() = true after Close")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := tr.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want deadline while operation runs", err)
+	}
+
+	tr.End()
+	if err := tr.Wait(context.Background()); err != nil {
+		t.Errorf("Wait() error = %v after End", err)
+	}
+}
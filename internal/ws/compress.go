@@ -0,0 +1,119 @@
+package ws
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"io"
+	"sync"
+	"unicode/utf8"
+)
+
+// ErrTooLarge is returned when a message inflates beyond MaxMessageSize
+var ErrTooLarge = errors.New("websocket: decompressed message too large")
+
+// inflater holds the reusable decoders of one decompression
+type inflater struct {
+	src bytes.Reader
+	gz  *gzip.Reader
+	zl  io.ReadCloser
+	raw io.ReadCloser
+}
+
+// inflaters keeps decoders between messages: allocating a gzip or flate
+// reader per message costs more than inflating a typical push
+var inflaters = sync.Pool{New: func() any { return new(inflater) }}
+
+// Decompress appends the content of a compressed message to dst and returns
+// the extended slice. Exchanges compress pushes themselves instead of
+// negotiating permessage-deflate, as gzip (BingX), zlib or raw deflate; the
+// format is detected from the payload, and plain text is appended as is.
+// Passing the previous result's [:0] as dst lets a stream reuse one buffer.
+func Decompress(dst, data []byte) ([]byte, error) {
+	if plainText(data) {
+		return append(dst, data...), nil
+	}
+
+	f := inflaters.Get().(*inflater)
+	defer inflaters.Put(f)
+	f.src.Reset(data)
+
+	var r io.Reader
+	switch {
+	case isGzip(data):
+		if f.gz == nil {
+			gz, err := gzip.NewReader(&f.src)
+			if err != nil {
+				return dst, err
+			}
+			f.gz = gz
+		} else if err := f.gz.Reset(&f.src); err != nil {
+			return dst, err
+		}
+		r = f.gz
+	case isZlib(data):
+		if f.zl == nil {
+			zl, err := zlib.NewReader(&f.src)
+			if err != nil {
+				return dst, err
+			}
+			f.zl = zl
+		} else if err := f.zl.(zlib.Resetter).Reset(&f.src, nil); err != nil {
+			return dst, err
+		}
+		r = f.zl
+	default:
+		if f.raw == nil {
+			f.raw = flate.NewReader(&f.src)
+		} else if err := f.raw.(flate.Resetter).Reset(&f.src, nil); err != nil {
+			return dst, err
+		}
+		r = f.raw
+	}
+	return appendAll(dst, r)
+}
+
+// appendAll reads r to the end into dst, up to MaxMessageSize bytes
+func appendAll(dst []byte, r io.Reader) ([]byte, error) {
+	start := len(dst)
+	for {
+		if len(dst) == cap(dst) {
+			dst = append(dst, 0)[:len(dst)]
+		}
+		n, err := r.Read(dst[len(dst):cap(dst)])
+		dst = dst[:len(dst)+n]
+		if len(dst)-start > MaxMessageSize {
+			return dst[:start], ErrTooLarge
+		}
+		if err == io.EOF {
+			return dst, nil
+		}
+		if err != nil {
+			return dst[:start], err
+		}
+	}
+}
+
+// isGzip checks for the gzip magic number
+func isGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
+
+// isZlib checks for a zlib header: deflate method and a valid check value
+func isZlib(data []byte) bool {
+	return len(data) >= 2 && data[0]&0x0f == 8 && (uint16(data[0])<<8|uint16(data[1]))%31 == 0
+}
+
+// plainText reports whether data is uncompressed: valid UTF-8 without
+// control characters other than whitespace, which compressed data
+// practically never is
+func plainText(data []byte) bool {
+	for _, b := range data {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' || b == 0x7f {
+			return false
+		}
+	}
+	return utf8.Valid(data)
+}
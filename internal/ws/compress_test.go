@@ -0,0 +1,103 @@
+package ws
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+func compress(t testing.TB, format string, text []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch format {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "zlib":
+		w = zlib.NewWriter(&buf)
+	case "deflate":
+		w, _ = flate.NewWriter(&buf, flate.DefaultCompression)
+	default:
+		return text
+	}
+	w.Write(text)
+	w.Close()
+	return buf.Bytes()
+}
+
+// depthPush builds a 100-level depth push, the largest message on exchange
+// market streams
+func depthPush() []byte {
+	var b strings.Builder
+	b.WriteString(`{"code":0,"dataType":"BTC-USDT@depth100@500ms","data":{"T":1760500000000,"bids":[`)
+	for i := range 100 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `["%.1f","%.4f"]`, 65000-float64(i)*0.1, 0.1+float64(i)/1000)
+	}
+	b.WriteString(`],"asks":[`)
+	for i := range 100 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		fmt.Fprintf(&b, `["%.1f","%.4f"]`, 65000.1+float64(i)*0.1, 0.2+float64(i)/1000)
+	}
+	b.WriteString(`]}}`)
+	return []byte(b.String())
+}
+
+func TestDecompress(t *testing.T) {
+	text := depthPush()
+	for _, format := range []string{"gzip", "zlib", "deflate", "plain"} {
+		t.Run(format, func(t *testing.T) {
+			data := compress(t, format, text)
+			// Twice, so the second run goes through pooled decoders
+			for range 2 {
+				got, err := Decompress([]byte("prefix:"), data)
+				if err != nil {
+					t.Fatalf("Decompress() error = %v", err)
+				}
+				if !bytes.Equal(got, append([]byte("prefix:"), text...)) {
+					t.Fatalf("Decompress() = %.60q..., want the push after the prefix", got)
+				}
+			}
+		})
+	}
+
+	if got, err := Decompress(nil, []byte("Ping")); err != nil || string(got) != "Ping" {
+		t.Errorf("Decompress(Ping) = %q, %v", got, err)
+	}
+	if _, err := Decompress(nil, []byte{0x1f, 0x8b, 0x00, 0xff}); err == nil {
+		t.Error("Decompress(corrupt gzip) succeeded")
+	}
+
+	bomb := compress(t, "gzip", make([]byte, MaxMessageSize+1))
+	if _, err := Decompress(nil, bomb); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("Decompress(oversized) error = %v, want ErrTooLarge", err)
+	}
+}
+
+func BenchmarkDecompress(b *testing.B) {
+	text := depthPush()
+	for _, format := range []string{"gzip", "zlib", "deflate", "plain"} {
+		data := compress(b, format, text)
+		b.Run(format, func(b *testing.B) {
+			b.SetBytes(int64(len(text)))
+			b.ReportAllocs()
+			var buf []byte
+			for b.Loop() {
+				var err error
+				if buf, err = Decompress(buf[:0], data); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
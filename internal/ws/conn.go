@@ -0,0 +1,236 @@
+// Package ws is a minimal RFC 6455 WebSocket implementation on top of the
+// standard library, covering what exchange streams need: client dialing,
+// server upgrades for tests and local services, message fragmentation and
+// ping/pong/close control frames.
+package ws
+
+import (
+	"bufio"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// Message opcodes
+const (
+	OpContinuation = 0x0
+	OpText         = 0x1
+	OpBinary       = 0x2
+	OpClose        = 0x8
+	OpPing         = 0x9
+	OpPong         = 0xA
+)
+
+// MaxMessageSize bounds a single reassembled message
+const MaxMessageSize = 16 << 20
+
+// ErrClosed is returned by ReadMessage after the peer closes the connection
+var ErrClosed = errors.New("websocket: connection closed")
+
+const acceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// Conn is a WebSocket connection. ReadMessage must be called from a single
+// goroutine; WriteMessage and Close are safe for concurrent use.
+type Conn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	client bool // Clients mask outgoing frames
+
+	writeMu sync.Mutex
+	closed  bool
+}
+
+func newConn(c net.Conn, br *bufio.Reader, client bool) *Conn {
+	if br == nil {
+		br = bufio.NewReader(c)
+	}
+	return &Conn{conn: c, br: br, client: client}
+}
+
+// ReadMessage returns the next data message, answering pings and
+// reassembling fragments along the way
+func (c *Conn) ReadMessage() (opcode int, data []byte, err error) {
+	var message []byte
+	messageOp := -1
+
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch op {
+		case OpPing:
+			if err := c.WriteMessage(OpPong, payload); err != nil {
+				return 0, nil, err
+			}
+			continue
+		case OpPong:
+			continue
+		case OpClose:
+			c.writeFrame(OpClose, payload)
+			c.conn.Close()
+			return 0, nil, ErrClosed
+		case OpContinuation:
+			if messageOp < 0 {
+				return 0, nil, errors.New("websocket: unexpected continuation frame")
+			}
+		default:
+			if messageOp >= 0 {
+				return 0, nil, errors.New("websocket: new message before previous finished")
+			}
+			messageOp = op
+		}
+
+		if len(message)+len(payload) > MaxMessageSize {
+			return 0, nil, errors.New("websocket: message too large")
+		}
+		message = append(message, payload...)
+		if fin {
+			return messageOp, message, nil
+		}
+	}
+}
+
+// readFrame reads a single frame, unmasking its payload
+func (c *Conn) readFrame() (fin bool, opcode int, payload []byte, err error) {
+	var header [2]byte
+	if _, err := io.ReadFull(c.br, header[:]); err != nil {
+		return false, 0, nil, err
+	}
+
+	fin = header[0]&0x80 != 0
+	opcode = int(header[0] & 0x0F)
+	masked := header[1]&0x80 != 0
+
+	length := uint64(header[1] & 0x7F)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.br, ext[:]); err != nil {
+			return false, 0, nil, err
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > MaxMessageSize {
+		return false, 0, nil, errors.New("websocket: frame too large")
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.br, mask[:]); err != nil {
+			return false, 0, nil, err
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.br, payload); err != nil {
+		return false, 0, nil, err
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// WriteMessage sends a single unfragmented message
+func (c *Conn) WriteMessage(opcode int, data []byte) error {
+	return c.writeFrame(opcode, data)
+}
+
+func (c *Conn) writeFrame(opcode int, data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closed {
+		return ErrClosed
+	}
+
+	frame := make([]byte, 0, len(data)+14)
+	frame = append(frame, 0x80|byte(opcode))
+
+	var maskBit byte
+	if c.client {
+		maskBit = 0x80
+	}
+	switch n := len(data); {
+	case n < 126:
+		frame = append(frame, maskBit|byte(n))
+	case n <= 0xFFFF:
+		frame = append(frame, maskBit|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, maskBit|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	if c.client {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		frame = append(frame, mask[:]...)
+		start := len(frame)
+		frame = append(frame, data...)
+		for i := range data {
+			frame[start+i] ^= mask[i%4]
+		}
+	} else {
+		frame = append(frame, data...)
+	}
+
+	_, err := c.conn.Write(frame)
+	return err
+}
+
+// SetReadDeadline sets the deadline for the next ReadMessage
+func (c *Conn) SetReadDeadline(t time.Time) error {
+	return c.conn.SetReadDeadline(t)
+}
+
+// Close sends a close frame and closes the underlying connection
+func (c *Conn) Close() error {
+	// Don't block on a peer that has stopped reading
+	c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.writeFrame(OpClose, []byte{0x03, 0xE8}) // 1000 normal closure
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if c.closed {
+		return nil
+	}
+	c.closed = true
+	return c.conn.Close()
+}
+
+// acceptKey computes the Sec-WebSocket-Accept value for a handshake key
+func acceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + acceptGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// newKey returns a random Sec-WebSocket-Key
+func newKey() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("websocket: generate key: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(b[:]), nil
+}
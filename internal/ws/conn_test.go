@@ -0,0 +1,107 @@
+package ws
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newEchoServer(t *testing.T) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.WriteMessage(OpPing, []byte("hello?"))
+		for {
+			op, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			if string(data) == "bye" {
+				return
+			}
+			conn.WriteMessage(op, data)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return "ws" + strings.TrimPrefix(server.URL, "http")
+}
+
+func TestConn_Echo(t *testing.T) {
+	conn, err := Dial(context.Background(), newEchoServer(t), nil)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+
+	messages := [][]byte{
+		[]byte("short"),
+		bytes.Repeat([]byte("m"), 300),   // 16-bit length
+		bytes.Repeat([]byte("L"), 70000), // 64-bit length
+	}
+	for _, msg := range messages {
+		if err := conn.WriteMessage(OpText, msg); err != nil {
+			t.Fatalf("WriteMessage() error = %v", err)
+		}
+		op, got, err := conn.ReadMessage()
+		if err != nil {
+			t.Fatalf("ReadMessage() error = %v", err)
+		}
+		if op != OpText || !bytes.Equal(got, msg) {
+			t.Errorf("echo of %d bytes = op %d, %d bytes", len(msg), op, len(got))
+		}
+	}
+
+	conn.WriteMessage(OpText, []byte("bye"))
+	if _, _, err := conn.ReadMessage(); !errors.Is(err, ErrClosed) {
+		t.Errorf("ReadMessage() after server close error = %v, want %v", err, ErrClosed)
+	}
+}
+
+func TestConn_Fragments(t *testing.T) {
+	c1, c2 := net.Pipe()
+	client := newConn(c1, nil, true)
+	server := newConn(c2, nil, false)
+	defer c1.Close()
+	defer c2.Close()
+
+	pong := make(chan int, 1)
+	go func() {
+		// Two fragments with a ping interleaved
+		c2.Write([]byte{0x01, 0x03, 'a', 'b', 'c'})
+		c2.Write([]byte{0x89, 0x00})
+		_, op, _, _ := server.readFrame()
+		pong <- op
+		c2.Write([]byte{0x80, 0x02, 'd', 'e'})
+	}()
+
+	op, data, err := client.ReadMessage()
+	if err != nil {
+		t.Fatalf("ReadMessage() error = %v", err)
+	}
+	if op != OpText || string(data) != "abcde" {
+		t.Errorf("ReadMessage() = %d %q, want %d %q", op, data, OpText, "abcde")
+	}
+	if got := <-pong; got != OpPong {
+		t.Errorf("server got opcode %d, want pong", got)
+	}
+}
+
+func TestUpgrade_RejectsPlainRequests(t *testing.T) {
+	rec := httptest.NewRecorder()
+	if _, err := Upgrade(rec, httptest.NewRequest("GET", "/", nil)); err == nil {
+		t.Error("Upgrade() error = nil, want error")
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
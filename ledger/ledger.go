@@ -0,0 +1,328 @@
+// Package ledger attributes fills to strategies so several strategies can
+// share one account and still report independently. The exchange nets
+// every strategy's orders into one position per symbol; the PositionLedger
+// keeps a separate position per strategy and symbol, with its own average
+// entry, realized PnL and fees, fed by each partial fill as it happens.
+package ledger
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/analytics"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/ordertrack"
+)
+
+// Position is a strategy's position in a symbol
+type Position struct {
+	Strategy    string    `json:"strategy"`
+	Symbol      string    `json:"symbol"`
+	Size        float64   `json:"size"` // Positive when long, negative when short
+	AvgEntry    float64   `json:"avgEntry"`
+	RealizedPnL float64   `json:"realizedPnl"` // Before fees
+	Fees        float64   `json:"fees"`
+	Fills       int       `json:"fills"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Side returns the side of the position, or "" when flat
+func (p Position) Side() broker.Side {
+	switch {
+	case p.Size > 0:
+		return broker.SideLong
+	case p.Size < 0:
+		return broker.SideShort
+	default:
+		return ""
+	}
+}
+
+// UnrealizedPnL values the open size at mark
+func (p Position) UnrealizedPnL(mark float64) float64 {
+	return (mark - p.AvgEntry) * p.Size
+}
+
+// Summary totals a strategy's positions
+type Summary struct {
+	Strategy    string  `json:"strategy"`
+	RealizedPnL float64 `json:"realizedPnl"`
+	Fees        float64 `json:"fees"`
+	NetPnL      float64 `json:"netPnl"` // RealizedPnL - Fees
+	Open        int     `json:"open"`   // Positions with open size
+}
+
+// Config configures a PositionLedger
+type Config struct {
+	// FeeRate estimates the fee of order fills, as a fraction of notional;
+	// orders don't report their fees. Fills applied with ApplyFill carry
+	// their own.
+	FeeRate float64
+}
+
+// maxFinished is how many final tagged orders are remembered
+const maxFinished = 1024
+
+// PositionLedger tracks positions per strategy. Orders are attributed to a
+// strategy when placed through Broker, with Assign or by their order tag;
+// their fills are applied from order updates (ApplyOrder, Track) or
+// directly (ApplyFill).
+type PositionLedger struct {
+	config Config
+
+	mu        sync.Mutex
+	orders    map[string]*assigned // By order ID
+	finished  map[string]bool      // Recently final tagged orders, so repeats aren't reattributed
+	finishedQ []string             // finished in insertion order, oldest first
+	positions map[key]*Position
+	now       func() time.Time
+}
+
+type key struct {
+	strategy string
+	symbol   string
+}
+
+// assigned is an order attributed to a strategy and how much of it has
+// been applied
+type assigned struct {
+	strategy string
+	filled   float64
+	value    float64 // Filled size times average price applied so far
+}
+
+// NewPositionLedger creates an empty ledger
+func NewPositionLedger(config Config) *PositionLedger {
+	return &PositionLedger{
+		config:    config,
+		orders:    make(map[string]*assigned),
+		finished:  make(map[string]bool),
+		positions: make(map[key]*Position),
+		now:       time.Now,
+	}
+}
+
+// Assign attributes an order to a strategy. Fills already applied to the
+// order are kept.
+func (l *PositionLedger) Assign(orderID, strategy string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if a, ok := l.orders[orderID]; ok {
+		a.strategy = strategy
+		return
+	}
+	l.orders[orderID] = &assigned{strategy: strategy}
+}
+
+// ApplyOrder applies the part of an assigned order filled since its last
+// update and reports whether there was any. A reduce-only order trades
+// against the position leg in its Side, as exchanges report it. Orders never assigned are
+// attributed to the tag in their client order ID (see broker.OrderTag), so
+// attribution survives restarts. Updates may repeat or arrive out of order;
+// only growth of the filled size counts. Orders are forgotten once final.
+func (l *PositionLedger) ApplyOrder(order broker.Order) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	a, ok := l.orders[order.ID]
+	if !ok {
+		tag := broker.OrderTag(&order)
+		if tag == "" || l.finished[order.ID] {
+			return false
+		}
+		a = &assigned{strategy: tag}
+		l.orders[order.ID] = a
+	}
+	if final(order.Status) {
+		defer l.finish(order)
+	}
+	size := order.FilledSize - a.filled
+	if size <= 0 {
+		return false
+	}
+
+	avg := order.AveragePrice
+	if avg <= 0 {
+		avg = order.Price
+	}
+	value := avg * order.FilledSize
+	price := (value - a.value) / size
+	a.filled, a.value = order.FilledSize, value
+
+	at := order.UpdatedAt
+	if at.IsZero() {
+		at = l.now()
+	}
+	l.apply(a.strategy, order.Symbol, direction(order), price, size, price*size*l.config.FeeRate, at)
+	return true
+}
+
+// direction returns the side an order trades. Orders report the position
+// leg they apply to, so a reduce-only order trades against its leg.
+func direction(order broker.Order) broker.Side {
+	if !order.ReduceOnly {
+		return order.Side
+	}
+	if order.Side == broker.SideLong {
+		return broker.SideShort
+	}
+	return broker.SideLong
+}
+
+// finish forgets a final order, remembering tagged ones for a while so
+// repeated updates aren't attributed again. Callers must hold l.mu.
+func (l *PositionLedger) finish(order broker.Order) {
+	delete(l.orders, order.ID)
+	if broker.OrderTag(&order) == "" {
+		return
+	}
+	l.finished[order.ID] = true
+	l.finishedQ = append(l.finishedQ, order.ID)
+	if len(l.finishedQ) > maxFinished {
+		delete(l.finished, l.finishedQ[0])
+		l.finishedQ = l.finishedQ[1:]
+	}
+}
+
+// ApplyFill applies an execution to a strategy directly, e.g. from the
+// exchange's trade history
+func (l *PositionLedger) ApplyFill(strategy string, f analytics.Fill) {
+	if f.Size <= 0 {
+		return
+	}
+	at := f.Time
+	if at.IsZero() {
+		at = l.now()
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.apply(strategy, f.Symbol, f.Side, f.Price, f.Size, f.Fee, at)
+}
+
+// Track applies order events; register it with ordertrack.Tracker.OnChange
+func (l *PositionLedger) Track(ctx context.Context, e ordertrack.Event) {
+	l.ApplyOrder(e.Order)
+}
+
+// apply nets a fill into the strategy's position. A fill that flips the
+// position closes it and opens the remainder at the fill price. Callers
+// must hold l.mu.
+func (l *PositionLedger) apply(strategy, symbol string, side broker.Side, price, size, fee float64, at time.Time) {
+	k := key{strategy, symbol}
+	p, ok := l.positions[k]
+	if !ok {
+		p = &Position{Strategy: strategy, Symbol: symbol}
+		l.positions[k] = p
+	}
+
+	signed := size
+	if side == broker.SideShort {
+		signed = -size
+	}
+	if p.Size != 0 && (p.Size > 0) != (signed > 0) {
+		closed := min(size, abs(p.Size))
+		if p.Size > 0 {
+			p.RealizedPnL += (price - p.AvgEntry) * closed
+			p.Size -= closed
+			signed += closed
+		} else {
+			p.RealizedPnL += (p.AvgEntry - price) * closed
+			p.Size += closed
+			signed -= closed
+		}
+		if p.Size == 0 {
+			p.AvgEntry = 0
+		}
+	}
+	if signed != 0 {
+		p.AvgEntry = (p.AvgEntry*abs(p.Size) + price*abs(signed)) / (abs(p.Size) + abs(signed))
+		p.Size += signed
+	}
+	p.Fees += fee
+	p.Fills++
+	p.UpdatedAt = at
+}
+
+// Position returns a strategy's position in a symbol
+func (l *PositionLedger) Position(strategy, symbol string) (Position, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	p, ok := l.positions[key{strategy, symbol}]
+	if !ok {
+		return Position{}, false
+	}
+	return *p, true
+}
+
+// Positions returns a strategy's positions, or every strategy's when
+// strategy is empty, sorted by strategy and symbol. Flat positions are
+// included for their realized PnL.
+func (l *PositionLedger) Positions(strategy string) []Position {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var positions []Position
+	for k, p := range l.positions {
+		if strategy == "" || k.strategy == strategy {
+			positions = append(positions, *p)
+		}
+	}
+	slices.SortFunc(positions, func(a, b Position) int {
+		return cmp.Or(cmp.Compare(a.Strategy, b.Strategy), cmp.Compare(a.Symbol, b.Symbol))
+	})
+	return positions
+}
+
+// Summary totals a strategy's realized PnL and fees
+func (l *PositionLedger) Summary(strategy string) Summary {
+	s := Summary{Strategy: strategy}
+	for _, p := range l.Positions(strategy) {
+		s.RealizedPnL += p.RealizedPnL
+		s.Fees += p.Fees
+		if p.Size != 0 {
+			s.Open++
+		}
+	}
+	s.NetPnL = s.RealizedPnL - s.Fees
+	return s
+}
+
+// Broker returns b with every order placed through it attributed to
+// strategy. Fills reported by PlaceOrder itself, such as market orders,
+// are applied at once; later fills need ApplyOrder or Track.
+func (l *PositionLedger) Broker(b broker.Broker, strategy string) broker.Broker {
+	return &strategyBroker{Broker: b, ledger: l, strategy: strategy}
+}
+
+type strategyBroker struct {
+	broker.Broker
+	ledger   *PositionLedger
+	strategy string
+}
+
+func (b *strategyBroker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	order, err := b.Broker.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	b.ledger.Assign(order.ID, b.strategy)
+	b.ledger.ApplyOrder(*order)
+	return order, nil
+}
+
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func final(status broker.OrderStatus) bool {
+	switch status {
+	case broker.OrderStatusFilled, broker.OrderStatusCanceled, broker.OrderStatusRejected, broker.OrderStatusExpired:
+		return true
+	}
+	return false
+}
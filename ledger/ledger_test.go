@@ -0,0 +1,159 @@
+package ledger
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/analytics"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestPositionLedger_PartialFills(t *testing.T) {
+	l := NewPositionLedger(Config{FeeRate: 0.001})
+	l.Assign("1", "trend")
+
+	order := broker.Order{ID: "1", Symbol: "BTC-USDT", Side: broker.SideLong, Status: broker.OrderStatusPartiallyFilled, Size: 1}
+	order.FilledSize, order.AveragePrice = 0.4, 100
+	if !l.ApplyOrder(order) {
+		t.Fatal("ApplyOrder() = false for the first partial fill")
+	}
+	if l.ApplyOrder(order) {
+		t.Error("ApplyOrder() = true for a repeated update")
+	}
+
+	// 0.6 more at 110: average of the order is (40 + 66) / 1 = 106
+	order.Status, order.FilledSize, order.AveragePrice = broker.OrderStatusFilled, 1, 106
+	l.ApplyOrder(order)
+
+	p, _ := l.Position("trend", "BTC-USDT")
+	if p.Size != 1 || !near(p.AvgEntry, 106) || p.Fills != 2 || !near(p.Fees, 0.106) {
+		t.Errorf("position = %+v, want 1 at 106 over 2 fills with 0.106 fees", p)
+	}
+	if l.ApplyOrder(order) {
+		t.Error("ApplyOrder() = true for a final order already applied")
+	}
+}
+
+func TestPositionLedger_Strategies(t *testing.T) {
+	l := NewPositionLedger(Config{})
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	fill := func(strategy string, side broker.Side, price, size, fee float64) {
+		l.ApplyFill(strategy, analytics.Fill{Symbol: "BTC-USDT", Side: side, Price: price, Size: size, Fee: fee, Time: at})
+	}
+
+	// The account nets these to flat; each strategy keeps its own position
+	fill("trend", broker.SideLong, 100, 2, 0.2)
+	fill("revert", broker.SideShort, 100, 2, 0.2)
+	fill("trend", broker.SideShort, 110, 1, 0.1) // Trend takes 10 on half
+	fill("revert", broker.SideLong, 110, 3, 0.3) // Revert loses 20 and flips long 1 at 110
+
+	trend, _ := l.Position("trend", "BTC-USDT")
+	if trend.Size != 1 || trend.AvgEntry != 100 || trend.RealizedPnL != 10 {
+		t.Errorf("trend = %+v, want 1 long at 100 with 10 realized", trend)
+	}
+	revert, _ := l.Position("revert", "BTC-USDT")
+	if revert.Size != 1 || revert.AvgEntry != 110 || revert.RealizedPnL != -20 || revert.Side() != broker.SideLong {
+		t.Errorf("revert = %+v, want flipped to 1 long at 110 with -20 realized", revert)
+	}
+	if got := revert.UnrealizedPnL(120); got != 10 {
+		t.Errorf("UnrealizedPnL(120) = %v, want 10", got)
+	}
+
+	if s := l.Summary("trend"); !near(s.NetPnL, 9.7) || s.Open != 1 {
+		t.Errorf("Summary(trend) = %+v, want net 9.7 with 1 open", s)
+	}
+	if all := l.Positions(""); len(all) != 2 || all[0].Strategy != "revert" {
+		t.Errorf("Positions() = %+v, want both sorted by strategy", all)
+	}
+}
+
+func TestPositionLedger_Broker(t *testing.T) {
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	l := NewPositionLedger(Config{})
+	ctx := context.Background()
+
+	trend := l.Broker(inner, "trend")
+	if _, err := trend.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 0.1}); err != nil {
+		t.Fatal(err)
+	}
+	limit, err := trend.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 0.1, Price: 49000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p, _ := l.Position("trend", "BTC-USDT")
+	if p.Size != 0.1 || p.AvgEntry != 50000 {
+		t.Errorf("position after the market order = %+v, want 0.1 at 50000", p)
+	}
+
+	// The limit order fills later, e.g. as reported by an ordertrack.Tracker
+	filled := *limit
+	filled.Status, filled.FilledSize, filled.AveragePrice = broker.OrderStatusFilled, 0.1, 49000
+	if !l.ApplyOrder(filled) {
+		t.Fatal("ApplyOrder() = false for the resting order's fill")
+	}
+	if p, _ := l.Position("trend", "BTC-USDT"); !near(p.Size, 0.2) || !near(p.AvgEntry, 49500) {
+		t.Errorf("position = %+v, want 0.2 at 49500", p)
+	}
+	if l.ApplyOrder(broker.Order{ID: "other", Symbol: "BTC-USDT", FilledSize: 1, AveragePrice: 1}) {
+		t.Error("ApplyOrder() = true for an order no strategy placed")
+	}
+}
+
+func TestPositionLedger_ReduceOnlyLeg(t *testing.T) {
+	l := NewPositionLedger(Config{})
+	l.Assign("open", "trend")
+	l.Assign("close", "trend")
+
+	// Shaped like bingx orders: Side is the position leg, so the close of
+	// the long (SELL on the LONG leg) reports LONG and reduce-only
+	l.ApplyOrder(broker.Order{ID: "open", Symbol: "BTC-USDT", Side: broker.SideLong, Status: broker.OrderStatusFilled, Size: 2, FilledSize: 2, AveragePrice: 100})
+	l.ApplyOrder(broker.Order{ID: "close", Symbol: "BTC-USDT", Side: broker.SideLong, ReduceOnly: true, Status: broker.OrderStatusFilled, Size: 2, FilledSize: 2, AveragePrice: 110})
+
+	p, _ := l.Position("trend", "BTC-USDT")
+	if p.Size != 0 || p.AvgEntry != 0 || p.RealizedPnL != 20 {
+		t.Errorf("position = %+v, want flat with 20 realized", p)
+	}
+
+	// Through a broker, closing reduce-only with a SHORT request
+	inner := brokertest.New()
+	inner.SetPrice("ETH-USDT", 2000)
+	ctx := context.Background()
+	b := l.Broker(inner, "revert")
+	if _, err := b.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "ETH-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1}); err != nil {
+		t.Fatal(err)
+	}
+	inner.SetPrice("ETH-USDT", 1900)
+	if _, err := b.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "ETH-USDT", Side: broker.SideShort, Type: broker.OrderTypeMarket, Size: 1, ReduceOnly: true}); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := l.Position("revert", "ETH-USDT"); p.Size != 0 || p.RealizedPnL != -100 {
+		t.Errorf("position = %+v, want flat with -100 realized", p)
+	}
+}
+
+func TestPositionLedger_OrderTag(t *testing.T) {
+	l := NewPositionLedger(Config{})
+	id, _ := broker.NewClientOrderID("breakout")
+
+	// A fill reported after a restart: nothing was assigned
+	order := broker.Order{ID: "7", ClientOrderID: id, Symbol: "BTC-USDT", Side: broker.SideLong,
+		Status: broker.OrderStatusFilled, FilledSize: 0.5, AveragePrice: 50000}
+	if !l.ApplyOrder(order) {
+		t.Fatal("ApplyOrder() = false for a tagged order")
+	}
+	if l.ApplyOrder(order) {
+		t.Error("ApplyOrder() = true for a repeated final update")
+	}
+	if p, _ := l.Position("breakout", "BTC-USDT"); p.Size != 0.5 {
+		t.Errorf("position = %+v, want 0.5 attributed to the tag", p)
+	}
+}
@@ -0,0 +1,233 @@
+// Package listing watches the listing status of traded contracts and
+// reports when one moves to reduce-only, halted or delisting, optionally
+// flattening the positions held in it before the exchange does.
+package listing
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// DefaultInterval is the default time between status checks in Run
+const DefaultInterval = 5 * time.Minute
+
+// Policy decides what happens to positions in an affected symbol
+type Policy string
+
+const (
+	PolicyNotify  Policy = "NOTIFY"  // Report the change only
+	PolicyFlatten Policy = "FLATTEN" // Close the symbol's positions
+)
+
+// Event describes a watched symbol entering a restricted status
+type Event struct {
+	Symbol     string
+	Previous   broker.SymbolStatus // Empty on the first check
+	Status     broker.SymbolStatus // SymbolHalted when the symbol is no longer listed
+	DelistTime time.Time
+	Time       time.Time
+	Flattened  []*broker.Order // Orders closing the positions (PolicyFlatten)
+	Err        error           // Flatten failure, if any
+}
+
+// Handler receives events. Handlers run synchronously on the goroutine
+// that ran the check and should return quickly.
+type Handler func(ctx context.Context, e Event)
+
+// Config configures a Watcher
+type Config struct {
+	// Symbols are the symbols watched (default: those with open positions
+	// at each check)
+	Symbols []string
+	// Interval between status checks in Run (default 5m)
+	Interval time.Duration
+	// Policy for positions in affected symbols (default PolicyNotify)
+	Policy Policy
+	// Logger receives status changes and failures (default: discard)
+	Logger *slog.Logger
+}
+
+// Watcher refreshes symbol statuses from a broker.SymbolLister
+type Watcher struct {
+	broker broker.Broker
+	config Config
+	log    *slog.Logger
+
+	mu       sync.Mutex
+	statuses map[string]broker.SymbolStatus
+	handlers []Handler
+	now      func() time.Time
+}
+
+// New creates a watcher for the symbols traded on b, which must implement
+// broker.SymbolLister for checks to succeed
+func New(b broker.Broker, config Config) *Watcher {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Policy == "" {
+		config.Policy = PolicyNotify
+	}
+	return &Watcher{
+		broker:   b,
+		config:   config,
+		log:      logging.Component(logging.OrDiscard(config.Logger), "listing"),
+		statuses: make(map[string]broker.SymbolStatus),
+		now:      time.Now,
+	}
+}
+
+// OnChange registers a handler for restricted-status events
+func (w *Watcher) OnChange(h Handler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, h)
+}
+
+// Status returns the last known status of a watched symbol
+func (w *Watcher) Status(symbol string) (broker.SymbolStatus, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	status, ok := w.statuses[symbol]
+	return status, ok
+}
+
+// Check refreshes the statuses of the watched symbols and returns an event
+// for each that entered a restricted status since the previous check,
+// applying the policy first. It returns broker.ErrNotSupported if the
+// broker can't list symbols.
+func (w *Watcher) Check(ctx context.Context) ([]Event, error) {
+	lister, ok := w.broker.(broker.SymbolLister)
+	if !ok {
+		return nil, broker.ErrNotSupported
+	}
+
+	symbols, err := w.watched(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbols) == 0 {
+		return nil, nil
+	}
+
+	listed, err := lister.ListSymbols(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	infos := make(map[string]broker.SymbolInfo, len(listed))
+	for _, info := range listed {
+		infos[info.Symbol] = info
+	}
+
+	var events []Event
+	for _, symbol := range symbols {
+		info, ok := infos[symbol]
+		if !ok {
+			info = broker.SymbolInfo{Symbol: symbol, Status: broker.SymbolHalted}
+		}
+
+		w.mu.Lock()
+		previous := w.statuses[symbol]
+		w.statuses[symbol] = info.Status
+		w.mu.Unlock()
+
+		if info.Status == previous || !restricted(info.Status) {
+			continue
+		}
+		event := w.handle(ctx, info, previous)
+		if event.Err != nil {
+			// Retry the policy on the next check
+			w.mu.Lock()
+			w.statuses[symbol] = previous
+			w.mu.Unlock()
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+// Run checks at the configured interval until the context is canceled.
+// Failed checks are logged and retried on the next tick.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := w.Check(ctx); err != nil {
+			w.log.Error("listing check failed", logging.KeyError, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// watched returns the configured symbols, or those with open positions
+func (w *Watcher) watched(ctx context.Context) ([]string, error) {
+	if len(w.config.Symbols) > 0 {
+		return w.config.Symbols, nil
+	}
+
+	positions, err := w.broker.GetPositions(ctx, nil)
+	if err != nil && !errors.Is(err, broker.ErrPositionNotFound) {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	var symbols []string
+	for _, p := range positions {
+		if !seen[p.Symbol] {
+			seen[p.Symbol] = true
+			symbols = append(symbols, p.Symbol)
+		}
+	}
+	return symbols, nil
+}
+
+// handle applies the policy to a symbol that entered a restricted status
+// and notifies handlers
+func (w *Watcher) handle(ctx context.Context, info broker.SymbolInfo, previous broker.SymbolStatus) Event {
+	event := Event{
+		Symbol:     info.Symbol,
+		Previous:   previous,
+		Status:     info.Status,
+		DelistTime: info.DelistTime,
+		Time:       w.now(),
+	}
+	w.log.Warn("symbol status changed", logging.KeySymbol, info.Symbol, "status", info.Status, "previous", previous)
+
+	if w.config.Policy == PolicyFlatten {
+		event.Flattened, event.Err = broker.FlattenPosition(ctx, w.broker, info.Symbol)
+		if errors.Is(event.Err, broker.ErrPositionNotFound) {
+			event.Err = nil // Nothing to close
+		}
+		if event.Err != nil {
+			w.log.Error("flatten failed", logging.KeySymbol, info.Symbol, logging.KeyError, event.Err)
+		}
+	}
+
+	w.mu.Lock()
+	handlers := w.handlers
+	w.mu.Unlock()
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+	return event
+}
+
+// restricted reports whether positions in a symbol with status are at risk
+func restricted(status broker.SymbolStatus) bool {
+	switch status {
+	case broker.SymbolReduceOnly, broker.SymbolHalted, broker.SymbolDelisting:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,110 @@
+package listing
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newBroker() *brokertest.Broker {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetPrice("OLD-USDT", 1)
+	b.SetPosition(broker.Position{Symbol: "OLD-USDT", Side: broker.SideLong, Size: 100, EntryPrice: 1})
+	b.SetSymbols(
+		broker.SymbolInfo{Symbol: "BTC-USDT", Status: broker.SymbolTrading},
+		broker.SymbolInfo{Symbol: "OLD-USDT", Status: broker.SymbolTrading},
+	)
+	return b
+}
+
+func TestWatcher_Notify(t *testing.T) {
+	b := newBroker()
+	w := New(b, Config{Symbols: []string{"BTC-USDT", "OLD-USDT"}})
+	ctx := context.Background()
+
+	var events []Event
+	w.OnChange(func(ctx context.Context, e Event) { events = append(events, e) })
+
+	if _, err := w.Check(ctx); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(events) != 0 {
+		t.Fatalf("events = %+v, want none while trading", events)
+	}
+
+	delist := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	b.SetSymbols(
+		broker.SymbolInfo{Symbol: "BTC-USDT", Status: broker.SymbolTrading},
+		broker.SymbolInfo{Symbol: "OLD-USDT", Status: broker.SymbolDelisting, DelistTime: delist},
+	)
+	for range 2 {
+		if _, err := w.Check(ctx); err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("events = %+v, want one delisting event", events)
+	}
+	e := events[0]
+	if e.Symbol != "OLD-USDT" || e.Previous != broker.SymbolTrading || e.Status != broker.SymbolDelisting || !e.DelistTime.Equal(delist) {
+		t.Errorf("event = %+v", e)
+	}
+	if len(b.PlacedOrders()) != 0 {
+		t.Errorf("placed %d orders, want none with PolicyNotify", len(b.PlacedOrders()))
+	}
+	if status, _ := w.Status("OLD-USDT"); status != broker.SymbolDelisting {
+		t.Errorf("Status() = %s, want DELISTING", status)
+	}
+}
+
+func TestWatcher_Flatten(t *testing.T) {
+	b := newBroker()
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 1, EntryPrice: 50000})
+	b.SetSymbols(
+		broker.SymbolInfo{Symbol: "BTC-USDT", Status: broker.SymbolTrading},
+		broker.SymbolInfo{Symbol: "OLD-USDT", Status: broker.SymbolReduceOnly},
+	)
+	w := New(b, Config{Policy: PolicyFlatten})
+
+	events, err := w.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Symbol != "OLD-USDT" || events[0].Err != nil || len(events[0].Flattened) != 1 {
+		t.Fatalf("events = %+v, want OLD-USDT flattened", events)
+	}
+
+	placed := b.PlacedOrders()
+	if len(placed) != 1 || placed[0].Symbol != "OLD-USDT" || !placed[0].ReduceOnly || placed[0].Side != broker.SideShort {
+		t.Errorf("placed = %+v, want one reduce-only SHORT closing OLD-USDT", placed)
+	}
+}
+
+func TestWatcher_Unlisted(t *testing.T) {
+	b := newBroker()
+	b.SetSymbols(broker.SymbolInfo{Symbol: "BTC-USDT", Status: broker.SymbolTrading})
+	w := New(b, Config{})
+
+	events, err := w.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(events) != 1 || events[0].Symbol != "OLD-USDT" || events[0].Status != broker.SymbolHalted {
+		t.Errorf("events = %+v, want OLD-USDT halted", events)
+	}
+}
+
+type plainBroker struct{ broker.Broker }
+
+func TestWatcher_NotSupported(t *testing.T) {
+	w := New(plainBroker{newBroker()}, Config{})
+	if _, err := w.Check(context.Background()); !errors.Is(err, broker.ErrNotSupported) {
+		t.Errorf("Check() error = %v, want %v", err, broker.ErrNotSupported)
+	}
+}
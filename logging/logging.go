@@ -0,0 +1,179 @@
+// Package logging holds the log/slog conventions shared by the brokers and
+// components of this module: attribute keys, a per-component level filter and
+// a handler that redacts secrets before they reach any output.
+//
+// Logging is silent by default. Pass a logger to a component (for example
+// bingx.WithLogger) to enable it:
+//
+//	handler := logging.Redact(slog.NewJSONHandler(os.Stderr, nil))
+//	handler = logging.Filter(handler, logging.Levels{
+//		Default:    slog.LevelInfo,
+//		Components: map[string]slog.Level{logging.ComponentTransport: slog.LevelDebug},
+//	})
+//	client := bingx.NewClient(key, secret, false, bingx.WithLogger(slog.New(handler)))
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// Attribute keys used consistently across packages
+const (
+	KeyBroker    = "broker"
+	KeyComponent = "component"
+	KeySymbol    = "symbol"
+	KeyOrderID   = "order_id"
+	KeyRequestID = "request_id"
+	KeyError     = "err"
+)
+
+// Component names
+const (
+	ComponentTransport = "transport"
+	ComponentStreamer  = "streamer"
+	ComponentStrategy  = "strategy"
+)
+
+// Redacted replaces secret values
+const Redacted = "[REDACTED]"
+
+// Discard returns a logger that drops every record
+func Discard() *slog.Logger {
+	return slog.New(slog.DiscardHandler)
+}
+
+// OrDiscard returns l, or a discarding logger when l is nil
+func OrDiscard(l *slog.Logger) *slog.Logger {
+	if l == nil {
+		return Discard()
+	}
+	return l
+}
+
+// Component returns l tagged with the component attribute
+func Component(l *slog.Logger, name string) *slog.Logger {
+	return OrDiscard(l).With(KeyComponent, name)
+}
+
+// secretKeys are substrings of attribute keys whose values are never logged
+var secretKeys = []string{"secret", "apikey", "api_key", "signature", "token", "password", "passphrase", "authorization", "private"}
+
+// secretParams matches secret query parameters inside logged URLs and strings
+var secretParams = regexp.MustCompile(`(?i)((?:signature|apikey|api_key|token|secret)=)[^&\s"]*`)
+
+// IsSecretKey reports whether values logged under key are redacted
+func IsSecretKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, s := range secretKeys {
+		if strings.Contains(key, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactString masks secret query parameters in s
+func RedactString(s string) string {
+	return secretParams.ReplaceAllString(s, "${1}"+Redacted)
+}
+
+// Redact wraps h so values under secret-like keys are replaced with
+// Redacted and secret query parameters are masked in string values
+func Redact(h slog.Handler) slog.Handler {
+	return redactHandler{h}
+}
+
+type redactHandler struct {
+	slog.Handler
+}
+
+func (h redactHandler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, RedactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(redactAttr(a))
+		return true
+	})
+	return h.Handler.Handle(ctx, out)
+}
+
+func (h redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = redactAttr(a)
+	}
+	return redactHandler{h.Handler.WithAttrs(redacted)}
+}
+
+func (h redactHandler) WithGroup(name string) slog.Handler {
+	return redactHandler{h.Handler.WithGroup(name)}
+}
+
+func redactAttr(a slog.Attr) slog.Attr {
+	if IsSecretKey(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindGroup:
+		group := v.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, g := range group {
+			redacted[i] = redactAttr(g)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		return slog.String(a.Key, RedactString(v.String()))
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, RedactString(err.Error()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+// Levels sets minimum levels per component
+type Levels struct {
+	// Default applies to records without a component or with an unlisted one
+	Default slog.Level
+	// Components overrides Default by component name
+	Components map[string]slog.Level
+}
+
+// Filter wraps h so records below the level of their component are dropped.
+// The component is taken from the KeyComponent attribute added through
+// With or Component.
+func Filter(h slog.Handler, levels Levels) slog.Handler {
+	return filterHandler{Handler: h, levels: levels, min: levels.Default}
+}
+
+type filterHandler struct {
+	slog.Handler
+	levels Levels
+	min    slog.Level
+}
+
+func (h filterHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.min && h.Handler.Enabled(ctx, level)
+}
+
+func (h filterHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	min := h.min
+	for _, a := range attrs {
+		if a.Key != KeyComponent {
+			continue
+		}
+		if level, ok := h.levels.Components[a.Value.String()]; ok {
+			min = level
+		} else {
+			min = h.levels.Default
+		}
+	}
+	return filterHandler{Handler: h.Handler.WithAttrs(attrs), levels: h.levels, min: min}
+}
+
+func (h filterHandler) WithGroup(name string) slog.Handler {
+	return filterHandler{Handler: h.Handler.WithGroup(name), levels: h.levels, min: h.min}
+}
@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(Redact(slog.NewTextHandler(&buf, nil))).With("api_key", "AK123")
+
+	logger.Info("request",
+		"url", "https://api.example.com/order?symbol=BTC&signature=abcdef&timestamp=1",
+		slog.Group("creds", "secretKey", "SK456"),
+		KeyError, errors.New("GET /x?apiKey=AK123 failed"),
+		KeySymbol, "BTC-USDT",
+	)
+
+	out := buf.String()
+	for _, secret := range []string{"AK123", "SK456", "abcdef"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("output leaks %q: %s", secret, out)
+		}
+	}
+	if !strings.Contains(out, "symbol=BTC-USDT") || !strings.Contains(out, "timestamp=1") {
+		t.Errorf("output lost non-secret values: %s", out)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	var buf bytes.Buffer
+	base := slog.New(Filter(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}), Levels{
+		Default:    slog.LevelWarn,
+		Components: map[string]slog.Level{ComponentTransport: slog.LevelDebug},
+	}))
+
+	base.Info("dropped default")
+	Component(base, ComponentStreamer).Info("dropped streamer")
+	Component(base, ComponentTransport).Debug("kept transport")
+	Component(base, ComponentStreamer).Warn("kept streamer")
+
+	out := buf.String()
+	if strings.Contains(out, "dropped") {
+		t.Errorf("records below their component level were logged: %s", out)
+	}
+	if !strings.Contains(out, "kept transport") || !strings.Contains(out, "kept streamer") {
+		t.Errorf("records at their component level were dropped: %s", out)
+	}
+}
+
+func TestComponent_NilLogger(t *testing.T) {
+	Component(nil, ComponentTransport).Error("nowhere")
+}
@@ -0,0 +1,243 @@
+// Package maintenance pauses trading while an exchange is down for
+// maintenance. The wrapped broker turns degraded when a request fails with
+// broker.ErrMaintenance or the status endpoint reports maintenance; while
+// degraded, requests fail fast instead of reaching the exchange, and Run
+// resumes the broker as soon as the status endpoint reports it healthy.
+// Requests that reduce risk (cancels and reduce-only orders) are never held
+// back, so a false maintenance signal can't keep positions exposed.
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// DefaultInterval is the default time between status checks in Run
+const DefaultInterval = 30 * time.Second
+
+// State is the availability of the wrapped broker
+type State string
+
+const (
+	StateAvailable State = "AVAILABLE"
+	StateDegraded  State = "DEGRADED" // Exchange under maintenance
+)
+
+// Event describes a state change
+type Event struct {
+	State State
+	Time  time.Time
+	Err   error // Error that revealed the maintenance (nil when detected by Run)
+}
+
+// Handler receives state changes. Handlers run synchronously on the
+// goroutine that detected the change and should return quickly.
+type Handler func(ctx context.Context, e Event)
+
+// Config configures a Broker
+type Config struct {
+	// Interval between status checks in Run (default 30s)
+	Interval time.Duration
+}
+
+// Broker is a broker.Broker that stops sending requests while the exchange
+// is under maintenance. Status, Ping, Name, SupportedFeatures, cancels and
+// reduce-only orders always reach the wrapped broker.
+type Broker struct {
+	broker.Broker
+	config Config
+
+	mu       sync.Mutex
+	state    State
+	since    time.Time
+	resumed  chan struct{} // Closed when the broker leaves the degraded state
+	handlers []Handler
+	now      func() time.Time
+}
+
+// Wrap makes b maintenance-aware
+func Wrap(b broker.Broker, config Config) *Broker {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Broker{
+		Broker: b,
+		config: config,
+		state:  StateAvailable,
+		now:    time.Now,
+	}
+}
+
+// OnChange registers a handler for state changes
+func (b *Broker) OnChange(h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers = append(b.handlers, h)
+}
+
+// State returns the current state and when it was entered
+func (b *Broker) State() (State, time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state, b.since
+}
+
+// Wait blocks while the broker is degraded. Strategy loops call it before
+// each cycle to pause during maintenance.
+func (b *Broker) Wait(ctx context.Context) error {
+	b.mu.Lock()
+	resumed := b.resumed
+	b.mu.Unlock()
+	if resumed == nil {
+		return nil
+	}
+
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Check queries the status endpoint and updates the state: maintenance
+// degrades the broker, a healthy answer resumes it. Other failures leave the
+// state unchanged and are returned.
+func (b *Broker) Check(ctx context.Context) error {
+	status, err := b.Broker.Status(ctx)
+	switch {
+	case errors.Is(err, broker.ErrMaintenance):
+		b.set(ctx, StateDegraded, err)
+	case err != nil:
+		return err
+	case status.Maintenance:
+		b.set(ctx, StateDegraded, nil)
+	default:
+		b.set(ctx, StateAvailable, nil)
+	}
+	return nil
+}
+
+// Run checks the status at the configured interval until the context is
+// canceled. Failed checks are retried on the next tick.
+func (b *Broker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(b.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		b.Check(ctx)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// set changes the state and notifies handlers of transitions
+func (b *Broker) set(ctx context.Context, state State, cause error) {
+	b.mu.Lock()
+	if b.state == state {
+		b.mu.Unlock()
+		return
+	}
+	b.state = state
+	b.since = b.now()
+	if state == StateDegraded {
+		b.resumed = make(chan struct{})
+	} else {
+		close(b.resumed)
+		b.resumed = nil
+	}
+	event := Event{State: state, Time: b.since, Err: cause}
+	handlers := b.handlers
+	b.mu.Unlock()
+
+	for _, h := range handlers {
+		h(ctx, event)
+	}
+}
+
+// call runs fn unless the broker is degraded, and degrades it when fn fails
+// with a maintenance error
+func call[T any](ctx context.Context, b *Broker, fn func() (T, error)) (T, error) {
+	var zero T
+	if state, _ := b.State(); state == StateDegraded {
+		return zero, broker.ErrMaintenance
+	}
+	return pass(ctx, b, fn)
+}
+
+// pass runs fn even while the broker is degraded. A maintenance error
+// degrades the broker and a success resumes it: the exchange answered.
+func pass[T any](ctx context.Context, b *Broker, fn func() (T, error)) (T, error) {
+	v, err := fn()
+	switch {
+	case errors.Is(err, broker.ErrMaintenance):
+		b.set(ctx, StateDegraded, err)
+	case err == nil:
+		b.set(ctx, StateAvailable, nil)
+	}
+	return v, err
+}
+
+// exec is call for operations without a result
+func exec(ctx context.Context, b *Broker, fn func() error) error {
+	_, err := call(ctx, b, func() (struct{}, error) { return struct{}{}, fn() })
+	return err
+}
+
+// execPass is pass for operations without a result
+func execPass(ctx context.Context, b *Broker, fn func() error) error {
+	_, err := pass(ctx, b, func() (struct{}, error) { return struct{}{}, fn() })
+	return err
+}
+
+func (b *Broker) GetBalance(ctx context.Context) (*broker.Balance, error) {
+	return call(ctx, b, func() (*broker.Balance, error) { return b.Broker.GetBalance(ctx) })
+}
+
+func (b *Broker) GetPositions(ctx context.Context, filter *broker.PositionFilter) ([]*broker.Position, error) {
+	return call(ctx, b, func() ([]*broker.Position, error) { return b.Broker.GetPositions(ctx, filter) })
+}
+
+func (b *Broker) GetPosition(ctx context.Context, symbol string) (*broker.Position, error) {
+	return call(ctx, b, func() (*broker.Position, error) { return b.Broker.GetPosition(ctx, symbol) })
+}
+
+// PlaceOrder places the order unless the broker is degraded; reduce-only
+// orders are placed regardless
+func (b *Broker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	place := func() (*broker.Order, error) { return b.Broker.PlaceOrder(ctx, req) }
+	if req.ReduceOnly {
+		return pass(ctx, b, place)
+	}
+	return call(ctx, b, place)
+}
+
+func (b *Broker) GetOrders(ctx context.Context, filter *broker.OrderFilter) ([]*broker.Order, error) {
+	return call(ctx, b, func() ([]*broker.Order, error) { return b.Broker.GetOrders(ctx, filter) })
+}
+
+// CancelOrder cancels the order, even while the broker is degraded
+func (b *Broker) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	return execPass(ctx, b, func() error { return b.Broker.CancelOrder(ctx, symbol, orderID) })
+}
+
+// CancelAllOrders cancels the orders, even while the broker is degraded
+func (b *Broker) CancelAllOrders(ctx context.Context, symbol string) error {
+	return execPass(ctx, b, func() error { return b.Broker.CancelAllOrders(ctx, symbol) })
+}
+
+func (b *Broker) GetCurrentPrice(ctx context.Context, symbol string) (float64, error) {
+	return call(ctx, b, func() (float64, error) { return b.Broker.GetCurrentPrice(ctx, symbol) })
+}
+
+func (b *Broker) SetLeverage(ctx context.Context, symbol string, side string, leverage int) error {
+	return exec(ctx, b, func() error { return b.Broker.SetLeverage(ctx, symbol, side, leverage) })
+}
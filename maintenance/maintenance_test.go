@@ -0,0 +1,101 @@
+package maintenance
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestBroker_MaintenanceCycle(t *testing.T) {
+	tb := brokertest.New()
+	tb.SetPrice("BTC-USDT", 50000)
+	b := Wrap(tb, Config{})
+	ctx := context.Background()
+
+	var events []Event
+	b.OnChange(func(ctx context.Context, e Event) { events = append(events, e) })
+
+	// A request reveals the maintenance
+	tb.Err = broker.NewBrokerError("test", "HTTP_ERROR", "HTTP 503", broker.ErrMaintenance)
+	if _, err := b.GetCurrentPrice(ctx, "BTC-USDT"); !errors.Is(err, broker.ErrMaintenance) {
+		t.Fatalf("GetCurrentPrice() error = %v", err)
+	}
+	if state, _ := b.State(); state != StateDegraded || len(events) != 1 || events[0].Err == nil {
+		t.Fatalf("state = %s, events = %+v", state, events)
+	}
+
+	// Degraded: requests fail fast without reaching the exchange
+	tb.Err = nil
+	if _, err := b.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1}); !errors.Is(err, broker.ErrMaintenance) {
+		t.Errorf("PlaceOrder() while degraded: error = %v", err)
+	}
+	if len(tb.PlacedOrders()) != 0 {
+		t.Error("order reached the exchange while degraded")
+	}
+
+	// Risk-reducing requests still go through, and their failure keeps the
+	// broker degraded
+	tb.Err = broker.NewBrokerError("test", "HTML_RESPONSE", "HTTP 200: maintenance page", broker.ErrMaintenance)
+	if err := b.CancelAllOrders(ctx, "BTC-USDT"); !errors.Is(err, broker.ErrMaintenance) {
+		t.Errorf("CancelAllOrders() while degraded: error = %v, want the exchange's error", err)
+	}
+	tb.Err = nil
+	if state, _ := b.State(); state != StateDegraded {
+		t.Errorf("state after a failed cancel = %s, want degraded", state)
+	}
+
+	waitCtx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	if err := b.Wait(waitCtx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() while degraded = %v, want deadline exceeded", err)
+	}
+
+	// The status endpoint still reports maintenance, then recovers
+	tb.SetStatus(broker.ExchangeStatus{Maintenance: true})
+	if err := b.Check(ctx); err != nil || len(events) != 1 {
+		t.Fatalf("Check() = %v, events = %d", err, len(events))
+	}
+
+	waited := make(chan error)
+	go func() { waited <- b.Wait(ctx) }()
+	tb.SetStatus(broker.ExchangeStatus{})
+	if err := b.Check(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-waited; err != nil {
+		t.Errorf("Wait() = %v", err)
+	}
+	if len(events) != 2 || events[1].State != StateAvailable {
+		t.Errorf("events = %+v", events)
+	}
+	if _, err := b.GetCurrentPrice(ctx, "BTC-USDT"); err != nil {
+		t.Errorf("GetCurrentPrice() after resume: %v", err)
+	}
+}
+
+func TestBroker_ReduceOnlyWhileDegraded(t *testing.T) {
+	tb := brokertest.New()
+	tb.SetPrice("BTC-USDT", 50000)
+	tb.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, EntryPrice: 50000})
+	b := Wrap(tb, Config{})
+	ctx := context.Background()
+
+	// A single 503 degrades the broker
+	tb.Err = broker.NewBrokerError("test", "HTTP_ERROR", "HTTP 503", broker.ErrMaintenance)
+	b.GetBalance(ctx)
+	tb.Err = nil
+
+	if _, err := b.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeMarket, Size: 1, ReduceOnly: true}); err != nil {
+		t.Fatalf("reduce-only PlaceOrder() while degraded: %v", err)
+	}
+	if len(tb.PlacedOrders()) != 1 {
+		t.Error("reduce-only order didn't reach the exchange")
+	}
+	if state, _ := b.State(); state != StateAvailable {
+		t.Errorf("state after the exchange answered = %s, want available", state)
+	}
+}
@@ -0,0 +1,251 @@
+// Package monitor watches open positions and raises warnings when they
+// drift toward liquidation, so deleveraging or alerting logic can react.
+package monitor
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Default configuration values
+const (
+	DefaultInterval            = 5 * time.Second
+	DefaultLiquidationDistance = 0.05 // Warn within 5% of liquidation
+)
+
+// WarningKind identifies the condition that raised a warning
+type WarningKind string
+
+const (
+	// WarningLiquidationDistance fires when mark price is within the
+	// configured distance of the liquidation price
+	WarningLiquidationDistance WarningKind = "LIQUIDATION_DISTANCE"
+	// WarningMarginRatio fires when the margin ratio reaches a threshold
+	WarningMarginRatio WarningKind = "MARGIN_RATIO"
+)
+
+// Warning describes a position at risk
+type Warning struct {
+	Kind     WarningKind
+	Position broker.Position
+
+	// LiquidationDistance is |mark - liquidation| / mark (0 = at liquidation)
+	LiquidationDistance float64
+	// MarginRatio is maintenance margin / (margin + unrealized PnL)
+	MarginRatio float64
+	// Threshold is the configured level that was breached
+	Threshold float64
+	// Repeated is true when the same level was already reported on the
+	// previous check, letting handlers act only on new breaches
+	Repeated bool
+
+	Time time.Time
+}
+
+// Handler receives warnings. Handlers run synchronously on the monitor
+// goroutine and should return quickly.
+type Handler func(ctx context.Context, w Warning)
+
+// Config controls when warnings are raised
+type Config struct {
+	// Interval between position polls (default 5s)
+	Interval time.Duration
+	// LiquidationDistance warns when mark price is within this fraction of
+	// the liquidation price (default 0.05)
+	LiquidationDistance float64
+	// MarginRatioThresholds warns when the margin ratio reaches any of these
+	// levels, e.g. []float64{0.5, 0.8}. The highest breached level is reported.
+	MarginRatioThresholds []float64
+	// Symbols limits monitoring to these symbols (empty = all)
+	Symbols []string
+}
+
+// Monitor tracks open positions by polling a broker or by receiving
+// position snapshots from a stream
+type Monitor struct {
+	broker broker.Broker
+	config Config
+
+	mu       sync.Mutex
+	handlers []Handler
+	levels   map[levelKey]float64 // last breached threshold per position and kind
+	now      func() time.Time
+}
+
+type levelKey struct {
+	symbol string
+	side   broker.Side
+	kind   WarningKind
+}
+
+// New creates a position monitor for the given broker
+func New(b broker.Broker, config Config) *Monitor {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.LiquidationDistance <= 0 {
+		config.LiquidationDistance = DefaultLiquidationDistance
+	}
+	thresholds := append([]float64(nil), config.MarginRatioThresholds...)
+	sort.Float64s(thresholds)
+	config.MarginRatioThresholds = thresholds
+
+	return &Monitor{
+		broker: b,
+		config: config,
+		levels: make(map[levelKey]float64),
+		now:    time.Now,
+	}
+}
+
+// OnWarning registers a handler for warnings
+func (m *Monitor) OnWarning(h Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, h)
+}
+
+// Run polls positions at the configured interval until the context is
+// canceled. Poll errors are returned immediately.
+func (m *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches positions once, evaluates them and dispatches warnings
+func (m *Monitor) Check(ctx context.Context) ([]Warning, error) {
+	positions, err := m.broker.GetPositions(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return m.Update(ctx, positions), nil
+}
+
+// Update evaluates a full position snapshot, e.g. from a user data stream,
+// and dispatches warnings. Positions missing from the snapshot are
+// considered closed.
+func (m *Monitor) Update(ctx context.Context, positions []*broker.Position) []Warning {
+	m.mu.Lock()
+	now := m.now()
+	seen := make(map[levelKey]bool)
+	var warnings []Warning
+
+	for _, pos := range positions {
+		if pos == nil || pos.Size == 0 || !m.watching(pos.Symbol) {
+			continue
+		}
+
+		distance := LiquidationDistance(pos)
+		ratio := MarginRatio(pos)
+
+		if distance <= m.config.LiquidationDistance && pos.LiquidationPrice > 0 {
+			key := levelKey{pos.Symbol, pos.Side, WarningLiquidationDistance}
+			seen[key] = true
+			warnings = append(warnings, m.warning(key, m.config.LiquidationDistance, *pos, distance, ratio, now))
+		}
+
+		if level, ok := breached(m.config.MarginRatioThresholds, ratio); ok {
+			key := levelKey{pos.Symbol, pos.Side, WarningMarginRatio}
+			seen[key] = true
+			warnings = append(warnings, m.warning(key, level, *pos, distance, ratio, now))
+		}
+	}
+
+	// Re-arm levels for positions that recovered or closed
+	for key := range m.levels {
+		if !seen[key] {
+			delete(m.levels, key)
+		}
+	}
+
+	handlers := append([]Handler(nil), m.handlers...)
+	m.mu.Unlock()
+
+	for _, w := range warnings {
+		for _, h := range handlers {
+			h(ctx, w)
+		}
+	}
+
+	return warnings
+}
+
+// warning builds a warning and records its level. Callers must hold m.mu.
+func (m *Monitor) warning(key levelKey, threshold float64, pos broker.Position, distance, ratio float64, now time.Time) Warning {
+	prev, ok := m.levels[key]
+	m.levels[key] = threshold
+
+	return Warning{
+		Kind:                key.kind,
+		Position:            pos,
+		LiquidationDistance: distance,
+		MarginRatio:         ratio,
+		Threshold:           threshold,
+		Repeated:            ok && prev == threshold,
+		Time:                now,
+	}
+}
+
+// watching reports whether a symbol is monitored
+func (m *Monitor) watching(symbol string) bool {
+	if len(m.config.Symbols) == 0 {
+		return true
+	}
+	for _, s := range m.config.Symbols {
+		if s == symbol {
+			return true
+		}
+	}
+	return false
+}
+
+// breached returns the highest threshold the ratio has reached
+func breached(thresholds []float64, ratio float64) (float64, bool) {
+	for i := len(thresholds) - 1; i >= 0; i-- {
+		if ratio >= thresholds[i] {
+			return thresholds[i], true
+		}
+	}
+	return 0, false
+}
+
+// LiquidationDistance returns how far mark price is from the liquidation
+// price as a fraction of mark price. Positions without a liquidation price
+// (e.g. fully collateralized) return +Inf.
+func LiquidationDistance(pos *broker.Position) float64 {
+	if pos.LiquidationPrice <= 0 || pos.MarkPrice <= 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(pos.MarkPrice-pos.LiquidationPrice) / pos.MarkPrice
+}
+
+// MarginRatio returns maintenance margin over position equity (margin plus
+// unrealized PnL). A ratio of 1 or more means the position is liquidatable.
+// Positions without margin data return 0.
+func MarginRatio(pos *broker.Position) float64 {
+	if pos.MaintenanceMargin <= 0 {
+		return 0
+	}
+	equity := pos.Margin + pos.UnrealizedPnL
+	if equity <= 0 {
+		return math.Inf(1)
+	}
+	return pos.MaintenanceMargin / equity
+}
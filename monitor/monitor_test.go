@@ -0,0 +1,142 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestLiquidationDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  broker.Position
+		want float64
+	}{
+		{"Long 10% away", broker.Position{Side: broker.SideLong, MarkPrice: 100, LiquidationPrice: 90}, 0.1},
+		{"Short 5% away", broker.Position{Side: broker.SideShort, MarkPrice: 100, LiquidationPrice: 105}, 0.05},
+		{"No liquidation price", broker.Position{MarkPrice: 100}, math.Inf(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := LiquidationDistance(&tt.pos)
+			if got != tt.want && math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("LiquidationDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMarginRatio(t *testing.T) {
+	tests := []struct {
+		name string
+		pos  broker.Position
+		want float64
+	}{
+		{"Healthy", broker.Position{Margin: 100, MaintenanceMargin: 10}, 0.1},
+		{"Losing", broker.Position{Margin: 100, UnrealizedPnL: -80, MaintenanceMargin: 10}, 0.5},
+		{"No margin data", broker.Position{Margin: 100}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MarginRatio(&tt.pos); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("MarginRatio() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMonitor_Check(t *testing.T) {
+	b := brokertest.New()
+	b.SetPosition(broker.Position{
+		Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1,
+		MarkPrice: 100, LiquidationPrice: 97,
+		Margin: 10, UnrealizedPnL: -2, MaintenanceMargin: 6,
+	})
+	b.SetPosition(broker.Position{
+		Symbol: "ETH-USDT", Side: broker.SideLong, Size: 1,
+		MarkPrice: 100, LiquidationPrice: 50,
+	})
+
+	m := New(b, Config{LiquidationDistance: 0.05, MarginRatioThresholds: []float64{0.8, 0.5}})
+
+	var received []Warning
+	m.OnWarning(func(ctx context.Context, w Warning) {
+		received = append(received, w)
+	})
+
+	warnings, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if len(warnings) != 2 || len(received) != 2 {
+		t.Fatalf("got %d warnings (%d handled), want 2", len(warnings), len(received))
+	}
+
+	for _, w := range warnings {
+		if w.Position.Symbol != "BTC-USDT" {
+			t.Errorf("warning for %s, want BTC-USDT", w.Position.Symbol)
+		}
+		if w.Repeated {
+			t.Errorf("%s warning Repeated = true on first check", w.Kind)
+		}
+		if w.Kind == WarningMarginRatio && w.Threshold != 0.5 {
+			t.Errorf("margin ratio threshold = %v, want 0.5", w.Threshold)
+		}
+	}
+
+	// Same state again is reported as repeated
+	warnings, _ = m.Check(context.Background())
+	for _, w := range warnings {
+		if !w.Repeated {
+			t.Errorf("%s warning Repeated = false on second check", w.Kind)
+		}
+	}
+
+	// Recovery re-arms the warning
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, MarkPrice: 100, LiquidationPrice: 80})
+	if warnings, _ = m.Check(context.Background()); len(warnings) != 0 {
+		t.Fatalf("got %d warnings after recovery, want 0", len(warnings))
+	}
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, MarkPrice: 100, LiquidationPrice: 98})
+	warnings, _ = m.Check(context.Background())
+	if len(warnings) != 1 || warnings[0].Repeated {
+		t.Errorf("got %+v after re-entering, want one new warning", warnings)
+	}
+}
+
+func TestMonitor_Symbols(t *testing.T) {
+	m := New(nil, Config{Symbols: []string{"ETH-USDT"}})
+
+	warnings := m.Update(context.Background(), []*broker.Position{
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, MarkPrice: 100, LiquidationPrice: 99},
+		{Symbol: "ETH-USDT", Side: broker.SideShort, Size: 1, MarkPrice: 100, LiquidationPrice: 101},
+	})
+	if len(warnings) != 1 || warnings[0].Position.Symbol != "ETH-USDT" {
+		t.Errorf("Update() = %+v, want one ETH-USDT warning", warnings)
+	}
+}
+
+func TestMonitor_Run(t *testing.T) {
+	b := brokertest.New()
+	wantErr := errors.New("boom")
+	b.Err = wantErr
+
+	m := New(b, Config{Interval: time.Millisecond})
+	if err := m.Run(context.Background()); !errors.Is(err, wantErr) {
+		t.Errorf("Run() error = %v, want %v", err, wantErr)
+	}
+
+	b.Err = nil
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if err := m.Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
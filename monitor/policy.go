@@ -0,0 +1,213 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// DefaultCooldown is the minimum time between policy actions on one position
+const DefaultCooldown = 30 * time.Second
+
+// Action is a deleveraging step taken by a Policy
+type Action string
+
+const (
+	// ActionReduce closes a fraction of the position with a reduce-only market order
+	ActionReduce Action = "REDUCE"
+	// ActionAddMargin tops up isolated margin on the position
+	ActionAddMargin Action = "ADD_MARGIN"
+)
+
+// Rule triggers an action when liquidation distance falls to or below Distance
+type Rule struct {
+	Distance float64
+	Action   Action
+
+	// ReduceFraction is the fraction of the position to close (ActionReduce)
+	ReduceFraction float64
+	// MarginAmount is the margin to add in quote currency (ActionAddMargin)
+	MarginAmount float64
+}
+
+// PolicyConfig configures automatic deleveraging
+type PolicyConfig struct {
+	// Rules are matched tightest-first: the rule with the smallest Distance
+	// that the position has breached wins
+	Rules []Rule
+	// DryRun audits the actions that would be taken without placing them
+	DryRun bool
+	// Cooldown between actions on the same position (default 30s), giving
+	// the exchange time to reflect the previous action
+	Cooldown time.Duration
+	// Audit receives a record for every action taken or attempted
+	Audit AuditFunc
+}
+
+// AuditRecord describes a single policy action
+type AuditRecord struct {
+	Time                time.Time   `json:"time"`
+	Symbol              string      `json:"symbol"`
+	Side                broker.Side `json:"side"`
+	Action              Action      `json:"action"`
+	LiquidationDistance float64     `json:"liquidationDistance"`
+	RuleDistance        float64     `json:"ruleDistance"`
+	Size                float64     `json:"size,omitempty"`   // Order size for ActionReduce
+	Amount              float64     `json:"amount,omitempty"` // Margin for ActionAddMargin
+	DryRun              bool        `json:"dryRun"`
+	OrderID             string      `json:"orderId,omitempty"`
+	Error               string      `json:"error,omitempty"`
+}
+
+// AuditFunc receives policy audit records
+type AuditFunc func(AuditRecord)
+
+// JSONAudit returns an AuditFunc that writes one JSON object per line to w
+func JSONAudit(w io.Writer) AuditFunc {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(r AuditRecord) {
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(r)
+	}
+}
+
+// Policy reduces positions or adds margin in response to liquidation
+// warnings. Attach it to a Monitor with m.OnWarning(policy.Handle); the
+// monitor's LiquidationDistance must be at least the widest rule distance.
+type Policy struct {
+	broker broker.Broker
+	config PolicyConfig
+
+	mu   sync.Mutex
+	last map[positionKey]time.Time
+	now  func() time.Time
+}
+
+type positionKey struct {
+	symbol string
+	side   broker.Side
+}
+
+// NewPolicy creates a deleveraging policy acting on the given broker
+func NewPolicy(b broker.Broker, config PolicyConfig) *Policy {
+	if config.Cooldown <= 0 {
+		config.Cooldown = DefaultCooldown
+	}
+	rules := append([]Rule(nil), config.Rules...)
+	sort.Slice(rules, func(i, j int) bool { return rules[i].Distance < rules[j].Distance })
+	config.Rules = rules
+
+	return &Policy{
+		broker: b,
+		config: config,
+		last:   make(map[positionKey]time.Time),
+		now:    time.Now,
+	}
+}
+
+// Handle applies the matching rule to a liquidation-distance warning. It
+// satisfies Handler.
+func (p *Policy) Handle(ctx context.Context, w Warning) {
+	if w.Kind != WarningLiquidationDistance {
+		return
+	}
+
+	rule, ok := p.match(w.LiquidationDistance)
+	if !ok {
+		return
+	}
+
+	key := positionKey{w.Position.Symbol, w.Position.Side}
+	now := p.now()
+
+	p.mu.Lock()
+	if last, ok := p.last[key]; ok && now.Sub(last) < p.config.Cooldown {
+		p.mu.Unlock()
+		return
+	}
+	p.last[key] = now
+	p.mu.Unlock()
+
+	record := AuditRecord{
+		Time:                now,
+		Symbol:              w.Position.Symbol,
+		Side:                w.Position.Side,
+		Action:              rule.Action,
+		LiquidationDistance: w.LiquidationDistance,
+		RuleDistance:        rule.Distance,
+		DryRun:              p.config.DryRun,
+	}
+
+	var err error
+	switch rule.Action {
+	case ActionReduce:
+		record.Size = w.Position.Size * rule.ReduceFraction
+		if !p.config.DryRun {
+			record.OrderID, err = p.reduce(ctx, w.Position, record.Size)
+		}
+	case ActionAddMargin:
+		record.Amount = rule.MarginAmount
+		if !p.config.DryRun {
+			err = p.addMargin(ctx, w.Position, rule.MarginAmount)
+		}
+	default:
+		err = broker.ErrNotSupported
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	if p.config.Audit != nil {
+		p.config.Audit(record)
+	}
+}
+
+// match returns the tightest rule the distance has breached
+func (p *Policy) match(distance float64) (Rule, bool) {
+	for _, rule := range p.config.Rules {
+		if distance <= rule.Distance {
+			return rule, true
+		}
+	}
+	return Rule{}, false
+}
+
+// reduce closes part of a position with a reduce-only market order
+func (p *Policy) reduce(ctx context.Context, pos broker.Position, size float64) (string, error) {
+	if size <= 0 {
+		return "", broker.ErrInvalidQuantity
+	}
+
+	side := broker.SideShort
+	if pos.Side == broker.SideShort {
+		side = broker.SideLong
+	}
+
+	order, err := p.broker.PlaceOrder(ctx, &broker.OrderRequest{
+		Symbol:     pos.Symbol,
+		Side:       side,
+		Type:       broker.OrderTypeMarket,
+		Size:       size,
+		ReduceOnly: true,
+	})
+	if err != nil {
+		return "", err
+	}
+	return order.ID, nil
+}
+
+// addMargin tops up isolated margin when the broker supports it
+func (p *Policy) addMargin(ctx context.Context, pos broker.Position, amount float64) error {
+	adjuster, ok := p.broker.(broker.MarginAdjuster)
+	if !ok {
+		return broker.ErrNotSupported
+	}
+	return adjuster.AdjustMargin(ctx, pos.Symbol, pos.Side, amount)
+}
@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newAtRiskBroker(liquidationPrice float64) *brokertest.Broker {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 100)
+	b.SetPosition(broker.Position{
+		Symbol: "BTC-USDT", Side: broker.SideLong, Size: 2,
+		EntryPrice: 100, MarkPrice: 100, LiquidationPrice: liquidationPrice, Margin: 10,
+	})
+	return b
+}
+
+func TestPolicy_Rules(t *testing.T) {
+	rules := []Rule{
+		{Distance: 0.05, Action: ActionAddMargin, MarginAmount: 25},
+		{Distance: 0.02, Action: ActionReduce, ReduceFraction: 0.5},
+	}
+
+	tests := []struct {
+		name       string
+		liqPrice   float64
+		wantAction Action
+		wantSize   float64
+		wantMargin float64
+	}{
+		{"Wide breach adds margin", 96, ActionAddMargin, 2, 35},
+		{"Tight breach reduces", 99, ActionReduce, 1, 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newAtRiskBroker(tt.liqPrice)
+
+			var records []AuditRecord
+			p := NewPolicy(b, PolicyConfig{Rules: rules, Audit: func(r AuditRecord) { records = append(records, r) }})
+
+			m := New(b, Config{LiquidationDistance: 0.05})
+			m.OnWarning(p.Handle)
+			if _, err := m.Check(context.Background()); err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+
+			if len(records) != 1 {
+				t.Fatalf("got %d audit records, want 1", len(records))
+			}
+			if records[0].Action != tt.wantAction || records[0].Error != "" {
+				t.Errorf("audit = %+v, want action %s without error", records[0], tt.wantAction)
+			}
+
+			pos, err := b.GetPosition(context.Background(), "BTC-USDT")
+			if err != nil {
+				t.Fatalf("GetPosition() error = %v", err)
+			}
+			if pos.Size != tt.wantSize {
+				t.Errorf("Size = %v, want %v", pos.Size, tt.wantSize)
+			}
+			if pos.Margin != tt.wantMargin {
+				t.Errorf("Margin = %v, want %v", pos.Margin, tt.wantMargin)
+			}
+		})
+	}
+}
+
+func TestPolicy_DryRun(t *testing.T) {
+	b := newAtRiskBroker(99)
+
+	var buf bytes.Buffer
+	p := NewPolicy(b, PolicyConfig{
+		Rules:  []Rule{{Distance: 0.05, Action: ActionReduce, ReduceFraction: 1}},
+		DryRun: true,
+		Audit:  JSONAudit(&buf),
+	})
+
+	m := New(b, Config{})
+	m.OnWarning(p.Handle)
+	m.Check(context.Background())
+
+	if placed := b.PlacedOrders(); len(placed) != 0 {
+		t.Errorf("dry run placed %d orders, want 0", len(placed))
+	}
+
+	var record AuditRecord
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("audit log %q is not JSON: %v", buf.String(), err)
+	}
+	if !record.DryRun || record.Size != 2 || record.Action != ActionReduce {
+		t.Errorf("audit = %+v, want dry-run reduce of 2", record)
+	}
+}
+
+func TestPolicy_Cooldown(t *testing.T) {
+	b := newAtRiskBroker(99)
+
+	now := time.Unix(0, 0)
+	p := NewPolicy(b, PolicyConfig{
+		Rules:    []Rule{{Distance: 0.05, Action: ActionReduce, ReduceFraction: 0.25}},
+		Cooldown: time.Minute,
+	})
+	p.now = func() time.Time { return now }
+
+	m := New(b, Config{})
+	m.OnWarning(p.Handle)
+
+	m.Check(context.Background())
+	m.Check(context.Background())
+	if placed := b.PlacedOrders(); len(placed) != 1 {
+		t.Fatalf("placed %d orders within cooldown, want 1", len(placed))
+	}
+
+	now = now.Add(time.Minute)
+	m.Check(context.Background())
+	placed := b.PlacedOrders()
+	if len(placed) != 2 {
+		t.Fatalf("placed %d orders after cooldown, want 2", len(placed))
+	}
+	if !placed[1].ReduceOnly || placed[1].Side != broker.SideShort {
+		t.Errorf("order = %+v, want reduce-only SHORT", placed[1])
+	}
+}
+
+func TestPolicy_AddMarginUnsupported(t *testing.T) {
+	var records []AuditRecord
+	p := NewPolicy(struct{ broker.Broker }{brokertest.New()}, PolicyConfig{
+		Rules: []Rule{{Distance: 0.05, Action: ActionAddMargin, MarginAmount: 10}},
+		Audit: func(r AuditRecord) { records = append(records, r) },
+	})
+
+	p.Handle(context.Background(), Warning{
+		Kind:                WarningLiquidationDistance,
+		Position:            broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong},
+		LiquidationDistance: 0.01,
+	})
+
+	if len(records) != 1 || records[0].Error != broker.ErrNotSupported.Error() {
+		t.Errorf("audit = %+v, want ErrNotSupported", records)
+	}
+}
@@ -0,0 +1,142 @@
+// Package orderflow derives order flow statistics (cumulative volume delta,
+// buy/sell imbalance and large prints) from a public trade stream
+package orderflow
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// DefaultInterval is the default snapshot period
+const DefaultInterval = time.Second
+
+// Config controls snapshot timing and large-trade detection
+type Config struct {
+	// Interval between snapshots emitted by Run (default 1s)
+	Interval time.Duration
+	// LargeTradeNotional flags trades at or above this quote value (0 = disabled)
+	LargeTradeNotional float64
+	// OnLargeTrade is called as soon as a large trade arrives
+	OnLargeTrade func(broker.Trade)
+}
+
+// Snapshot summarizes order flow since the previous snapshot
+type Snapshot struct {
+	Symbol string
+	Start  time.Time
+	End    time.Time
+
+	Trades     int
+	BuyVolume  float64 // Taker buy size
+	SellVolume float64 // Taker sell size
+
+	// Delta is BuyVolume - SellVolume for this interval
+	Delta float64
+	// CVD is the cumulative volume delta since the tracker started
+	CVD float64
+	// Imbalance is Delta / (BuyVolume + SellVolume), from -1 (all sells) to 1 (all buys)
+	Imbalance float64
+
+	LargeTrades []broker.Trade
+}
+
+// Tracker accumulates trades for a single symbol. It is safe for
+// concurrent use.
+type Tracker struct {
+	symbol string
+	config Config
+
+	mu      sync.Mutex
+	current Snapshot
+	cvd     float64
+	now     func() time.Time
+}
+
+// NewTracker creates an order flow tracker for a symbol
+func NewTracker(symbol string, config Config) *Tracker {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	t := &Tracker{symbol: symbol, config: config, now: time.Now}
+	t.current = Snapshot{Symbol: symbol, Start: t.now()}
+	return t
+}
+
+// Add records a trade. Trades for other symbols are ignored.
+func (t *Tracker) Add(trade broker.Trade) {
+	if trade.Symbol != "" && trade.Symbol != t.symbol {
+		return
+	}
+
+	large := t.config.LargeTradeNotional > 0 && trade.Notional() >= t.config.LargeTradeNotional
+
+	t.mu.Lock()
+	t.current.Trades++
+	if trade.Side == broker.SideShort {
+		t.current.SellVolume += trade.Size
+		t.cvd -= trade.Size
+	} else {
+		t.current.BuyVolume += trade.Size
+		t.cvd += trade.Size
+	}
+	if large {
+		t.current.LargeTrades = append(t.current.LargeTrades, trade)
+	}
+	t.mu.Unlock()
+
+	if large && t.config.OnLargeTrade != nil {
+		t.config.OnLargeTrade(trade)
+	}
+}
+
+// CVD returns the cumulative volume delta so far
+func (t *Tracker) CVD() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.cvd
+}
+
+// Snapshot closes the current interval and returns its statistics
+func (t *Tracker) Snapshot() Snapshot {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	s := t.current
+	s.End = now
+	s.Delta = s.BuyVolume - s.SellVolume
+	s.CVD = t.cvd
+	if total := s.BuyVolume + s.SellVolume; total > 0 {
+		s.Imbalance = s.Delta / total
+	}
+
+	t.current = Snapshot{Symbol: t.symbol, Start: now}
+	return s
+}
+
+// Run streams trades from s into the tracker and calls fn with a snapshot
+// every interval until the context is canceled or the stream fails
+func (t *Tracker) Run(ctx context.Context, s broker.TradeStreamer, fn func(Snapshot)) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errc := make(chan error, 1)
+	go func() {
+		errc <- s.StreamTrades(ctx, t.symbol, t.Add)
+	}()
+
+	ticker := time.NewTicker(t.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-errc:
+			return err
+		case <-ticker.C:
+			fn(t.Snapshot())
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package orderflow
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestTracker_Snapshot(t *testing.T) {
+	var large []broker.Trade
+	tracker := NewTracker("BTC-USDT", Config{
+		LargeTradeNotional: 10000,
+		OnLargeTrade:       func(tr broker.Trade) { large = append(large, tr) },
+	})
+
+	for _, tr := range []broker.Trade{
+		{Symbol: "BTC-USDT", Price: 100, Size: 3, Side: broker.SideLong},
+		{Symbol: "BTC-USDT", Price: 100, Size: 1, Side: broker.SideShort},
+		{Symbol: "BTC-USDT", Price: 100, Size: 150, Side: broker.SideShort}, // Large
+		{Symbol: "ETH-USDT", Price: 10, Size: 99, Side: broker.SideLong},    // Other symbol
+	} {
+		tracker.Add(tr)
+	}
+
+	s := tracker.Snapshot()
+	if s.Trades != 3 || s.BuyVolume != 3 || s.SellVolume != 151 {
+		t.Errorf("Snapshot() trades/buy/sell = %d/%v/%v, want 3/3/151", s.Trades, s.BuyVolume, s.SellVolume)
+	}
+	if s.Delta != -148 || s.CVD != -148 {
+		t.Errorf("Delta/CVD = %v/%v, want -148/-148", s.Delta, s.CVD)
+	}
+	if want := -148.0 / 154; s.Imbalance != want {
+		t.Errorf("Imbalance = %v, want %v", s.Imbalance, want)
+	}
+	if len(s.LargeTrades) != 1 || len(large) != 1 {
+		t.Errorf("large trades = %d (callback %d), want 1", len(s.LargeTrades), len(large))
+	}
+
+	// Next interval starts empty but CVD carries over
+	tracker.Add(broker.Trade{Symbol: "BTC-USDT", Price: 100, Size: 8, Side: broker.SideLong})
+	s = tracker.Snapshot()
+	if s.Trades != 1 || s.Delta != 8 || s.CVD != -140 || s.Imbalance != 1 {
+		t.Errorf("second Snapshot() = %+v, want 1 trade, delta 8, CVD -140, imbalance 1", s)
+	}
+}
+
+type fakeStream struct {
+	trades []broker.Trade
+}
+
+func (f *fakeStream) StreamTrades(ctx context.Context, symbol string, handler func(broker.Trade)) error {
+	for _, tr := range f.trades {
+		handler(tr)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestTracker_Run(t *testing.T) {
+	stream := &fakeStream{trades: []broker.Trade{
+		{Symbol: "BTC-USDT", Price: 100, Size: 2, Side: broker.SideLong},
+	}}
+	tracker := NewTracker("BTC-USDT", Config{Interval: time.Millisecond})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var cvd float64
+	err := tracker.Run(ctx, stream, func(s Snapshot) {
+		cvd = s.CVD
+		if s.CVD != 0 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+	if cvd != 2 {
+		t.Errorf("CVD = %v, want 2", cvd)
+	}
+}
@@ -0,0 +1,118 @@
+package ordertrack
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// ErrNotStarted is returned by OrderBookkeeper queries before the first
+// open-order snapshot
+var ErrNotStarted = errors.New("ordertrack: bookkeeper not started")
+
+// OrderBookkeeper keeps the account's open orders in memory so strategies
+// can query them instead of calling GetOrders in tight loops. Start loads a
+// snapshot of every open order (not only those placed through it); Run
+// refreshes it, and stream consumers feed updates through Apply.
+//
+// Queries reflect every update applied before they were made: orders placed
+// or canceled through the bookkeeper show up immediately, and a refresh that
+// started before such a change cannot undo it.
+type OrderBookkeeper struct {
+	*Tracker
+	started atomic.Bool
+}
+
+// NewOrderBookkeeper tracks the open orders of b. Call Start before
+// querying and Run to keep the set fresh.
+func NewOrderBookkeeper(b broker.Broker, config Config) *OrderBookkeeper {
+	return &OrderBookkeeper{Tracker: Wrap(b, config)}
+}
+
+// Start loads the open-order snapshot
+func (k *OrderBookkeeper) Start(ctx context.Context) error {
+	if err := k.Sync(ctx); err != nil {
+		return err
+	}
+	k.started.Store(true)
+	return nil
+}
+
+// Run refreshes the open orders at the configured interval until the
+// context is canceled. The first refresh also starts the bookkeeper.
+func (k *OrderBookkeeper) Run(ctx context.Context) error {
+	if err := k.Start(ctx); err != nil {
+		return err
+	}
+	return k.Tracker.Run(ctx)
+}
+
+// OpenOrders returns the open orders of symbol (all symbols if empty),
+// oldest first
+func (k *OrderBookkeeper) OpenOrders(symbol string) ([]broker.Order, error) {
+	if !k.started.Load() {
+		return nil, ErrNotStarted
+	}
+	var orders []broker.Order
+	for _, o := range k.Open() {
+		if symbol == "" || o.Symbol == symbol {
+			orders = append(orders, o)
+		}
+	}
+	return orders, nil
+}
+
+// ProtectiveOrdersFor returns the open reduce-only stop, take-profit and
+// trailing orders protecting pos. Orders name the position leg they act on
+// in Side, as GetOrders reports them.
+func (k *OrderBookkeeper) ProtectiveOrdersFor(pos *broker.Position) ([]broker.Order, error) {
+	open, err := k.OpenOrders(pos.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	var orders []broker.Order
+	for _, o := range open {
+		if o.ReduceOnly && o.Side == pos.Side && isProtective(o.Type) {
+			orders = append(orders, o)
+		}
+	}
+	return orders, nil
+}
+
+// GetOrders answers open-order queries from memory once started and asks
+// the broker otherwise
+func (k *OrderBookkeeper) GetOrders(ctx context.Context, filter *broker.OrderFilter) ([]*broker.Order, error) {
+	if !k.started.Load() {
+		return k.Tracker.GetOrders(ctx, filter)
+	}
+
+	symbol := ""
+	if filter != nil {
+		symbol = filter.Symbol
+	}
+	open, err := k.OpenOrders(symbol)
+	if err != nil {
+		return nil, err
+	}
+	orders := make([]*broker.Order, 0, len(open))
+	for _, o := range open {
+		if filter != nil && filter.Status != nil && *filter.Status != o.Status {
+			continue
+		}
+		orders = append(orders, &o)
+	}
+	return orders, nil
+}
+
+// isProtective reports whether orders of type t close a position at a
+// trigger price
+func isProtective(t broker.OrderType) bool {
+	switch t {
+	case broker.OrderTypeStop, broker.OrderTypeStopMarket, broker.OrderTypeTakeProfit,
+		broker.OrderTypeTakeProfitMarket, broker.OrderTypeTrailingStop:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,51 @@
+package ordertrack
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestOrderBookkeeper(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	ctx := context.Background()
+
+	// Placed before the bookkeeper existed, e.g. by another process
+	stop := b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeStop, Size: 1, StopPrice: 48000, ReduceOnly: true})
+	b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeStop, Size: 1, StopPrice: 52000, ReduceOnly: true})
+	b.AddOrder(broker.Order{Symbol: "ETH-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 2500})
+
+	k := NewOrderBookkeeper(b, Config{})
+	if _, err := k.OpenOrders(""); !errors.Is(err, ErrNotStarted) {
+		t.Fatalf("OpenOrders() before Start: error = %v, want ErrNotStarted", err)
+	}
+	if err := k.Start(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	entry, err := k.PlaceOrder(ctx, limit(1, 45000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if open, _ := k.OpenOrders("BTC-USDT"); len(open) != 3 {
+		t.Errorf("OpenOrders(BTC-USDT) = %d orders, want 3", len(open))
+	}
+
+	protective, err := k.ProtectiveOrdersFor(&broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong})
+	if err != nil || len(protective) != 1 || protective[0].ID != stop.ID {
+		t.Errorf("ProtectiveOrdersFor(long) = %+v, %v, want the long stop", protective, err)
+	}
+
+	if err := k.CancelOrder(ctx, "BTC-USDT", entry.ID); err != nil {
+		t.Fatal(err)
+	}
+	b.Err = errors.New("GetOrders must not be called once started")
+	orders, err := k.GetOrders(ctx, &broker.OrderFilter{Symbol: "BTC-USDT"})
+	if err != nil || len(orders) != 2 {
+		t.Errorf("GetOrders() = %d orders, %v, want 2 from memory", len(orders), err)
+	}
+}
@@ -0,0 +1,409 @@
+// Package ordertrack owns the lifecycle of submitted orders. It wraps a
+// broker.Broker, records every order it places, and merges later REST
+// snapshots and stream updates into one consistent state per order:
+//
+//	NEW/PENDING → PARTIALLY_FILLED → FILLED | CANCELED | REJECTED | EXPIRED
+//
+// Updates are applied only when they move an order forward, so duplicated
+// or stale events replayed after a reconnect cannot roll an order back.
+package ordertrack
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// DefaultInterval is the default time between Sync polls in Run
+const DefaultInterval = 5 * time.Second
+
+// StatusClosed marks an order that left the open-order list while its final
+// status could not be fetched (the broker does not implement
+// broker.OrderGetter)
+const StatusClosed broker.OrderStatus = "CLOSED"
+
+// Source identifies where an order update came from
+type Source string
+
+const (
+	SourcePlace  Source = "PLACE"  // PlaceOrder response
+	SourceCancel Source = "CANCEL" // Successful cancel request
+	SourceREST   Source = "REST"   // Open-order snapshot from Sync
+	SourceStream Source = "STREAM" // Update passed to Apply by a stream consumer
+)
+
+// Event describes an order state change
+type Event struct {
+	Order    broker.Order
+	Previous broker.OrderStatus // Empty for the first update of an order
+	Source   Source
+	Time     time.Time
+}
+
+// Handler receives order events. Handlers run synchronously on the goroutine
+// that applied the update and should return quickly.
+type Handler func(ctx context.Context, e Event)
+
+// Config configures a Tracker
+type Config struct {
+	// Interval between Sync polls in Run (default 5s)
+	Interval time.Duration
+	Logger   *slog.Logger
+}
+
+// Tracker is a broker.Broker that tracks the orders placed through it
+type Tracker struct {
+	broker.Broker
+	config Config
+	log    *slog.Logger
+
+	mu          sync.Mutex
+	orders      map[string]*entry
+	handlers    []Handler
+	gapHandlers []GapHandler
+	seq         uint64    // Latest user data stream sequence number
+	lastEvent   time.Time // Latest user data stream event time
+	gaps        int
+	now         func() time.Time
+}
+
+// entry is a tracked order and when the tracker last changed it
+type entry struct {
+	order   broker.Order
+	updated time.Time
+}
+
+// Wrap starts tracking the orders placed through b
+func Wrap(b broker.Broker, config Config) *Tracker {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Tracker{
+		Broker: b,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "ordertrack"),
+		orders: make(map[string]*entry),
+		now:    time.Now,
+	}
+}
+
+// OnChange registers a handler for order state changes
+func (t *Tracker) OnChange(h Handler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handlers = append(t.handlers, h)
+}
+
+// PlaceOrder places the order and starts tracking it
+func (t *Tracker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	order, err := t.Broker.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	t.Apply(ctx, *order, SourcePlace)
+	return order, nil
+}
+
+// CancelOrder cancels the order and marks it canceled
+func (t *Tracker) CancelOrder(ctx context.Context, symbol string, orderID string) error {
+	if err := t.Broker.CancelOrder(ctx, symbol, orderID); err != nil {
+		return err
+	}
+	if order, ok := t.Get(orderID); ok {
+		order.Status = broker.OrderStatusCanceled
+		t.Apply(ctx, order, SourceCancel)
+	}
+	return nil
+}
+
+// CancelAllOrders cancels the symbol's orders (all symbols if empty) and
+// marks the tracked open ones canceled
+func (t *Tracker) CancelAllOrders(ctx context.Context, symbol string) error {
+	if err := t.Broker.CancelAllOrders(ctx, symbol); err != nil {
+		return err
+	}
+	for _, order := range t.Open() {
+		if symbol == "" || order.Symbol == symbol {
+			order.Status = broker.OrderStatusCanceled
+			t.Apply(ctx, order, SourceCancel)
+		}
+	}
+	return nil
+}
+
+// Apply merges an order update, e.g. from a user data stream, and reports
+// whether it changed the tracked state. Unknown orders start being tracked.
+// Duplicates and updates that would move an order backwards are ignored.
+func (t *Tracker) Apply(ctx context.Context, order broker.Order, source Source) bool {
+	t.mu.Lock()
+	event, ok := t.apply(order, source)
+	handlers := t.handlers
+	t.mu.Unlock()
+
+	if ok {
+		for _, h := range handlers {
+			h(ctx, event)
+		}
+	}
+	return ok
+}
+
+// apply merges an update. Callers must hold t.mu.
+func (t *Tracker) apply(update broker.Order, source Source) (Event, bool) {
+	now := t.now()
+	e, ok := t.orders[update.ID]
+	if !ok {
+		t.orders[update.ID] = &entry{order: update, updated: now}
+		return Event{Order: update, Source: source, Time: now}, true
+	}
+
+	cur := e.order
+	if !advances(cur, update) {
+		return Event{}, false
+	}
+
+	merged := merge(cur, update)
+	e.order = merged
+	e.updated = now
+	return Event{Order: merged, Previous: cur.Status, Source: source, Time: now}, true
+}
+
+// Sync fetches open orders and merges them. Tracked orders missing from the
+// snapshot have left the book: their final status is fetched when the
+// broker implements broker.OrderGetter and set to StatusClosed otherwise.
+// An order whose lookup fails stays as it was until the next Sync; the
+// others are still updated and the lookup errors are returned together.
+func (t *Tracker) Sync(ctx context.Context) error {
+	started := t.now()
+	open, err := t.Broker.GetOrders(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	listed := make(map[string]bool, len(open))
+	for _, o := range open {
+		listed[o.ID] = true
+		t.Apply(ctx, *o, SourceREST)
+	}
+
+	var errs []error
+	for _, order := range t.missing(listed, started) {
+		if getter, ok := t.Broker.(broker.OrderGetter); ok {
+			final, err := getter.GetOrder(ctx, order.Symbol, order.ID)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			t.Apply(ctx, *final, SourceREST)
+			continue
+		}
+		order.Status = StatusClosed
+		t.Apply(ctx, order, SourceREST)
+	}
+	return errors.Join(errs...)
+}
+
+// missing returns open tracked orders absent from a snapshot taken at
+// started. Orders changed after that may not be in it yet and are skipped.
+func (t *Tracker) missing(listed map[string]bool, started time.Time) []broker.Order {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var orders []broker.Order
+	for id, e := range t.orders {
+		if !listed[id] && !isFinal(e.order.Status) && e.updated.Before(started) {
+			orders = append(orders, e.order)
+		}
+	}
+	return orders
+}
+
+// Run calls Sync at the configured interval until the context is canceled.
+// Sync errors are logged and the next poll tries again.
+func (t *Tracker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(t.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := t.Sync(ctx); err != nil && ctx.Err() == nil {
+			t.log.Warn("order sync failed", logging.KeyError, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Get returns a tracked order by ID
+func (t *Tracker) Get(orderID string) (broker.Order, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	e, ok := t.orders[orderID]
+	if !ok {
+		return broker.Order{}, false
+	}
+	return e.order, true
+}
+
+// Orders returns the tracked orders matching the filter (nil = all), oldest
+// first
+func (t *Tracker) Orders(filter *broker.OrderFilter) []broker.Order {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var orders []broker.Order
+	for _, e := range t.orders {
+		if filter != nil && filter.Symbol != "" && filter.Symbol != e.order.Symbol {
+			continue
+		}
+		if filter != nil && filter.Status != nil && *filter.Status != e.order.Status {
+			continue
+		}
+		orders = append(orders, e.order)
+	}
+	slices.SortFunc(orders, func(a, b broker.Order) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+	return orders
+}
+
+// Open returns the tracked orders that are not in a final state
+func (t *Tracker) Open() []broker.Order {
+	var open []broker.Order
+	for _, o := range t.Orders(nil) {
+		if !isFinal(o.Status) {
+			open = append(open, o)
+		}
+	}
+	return open
+}
+
+// Prune stops tracking orders that reached a final state more than age ago
+// and returns how many were removed
+func (t *Tracker) Prune(age time.Duration) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	cutoff := t.now().Add(-age)
+	removed := 0
+	for id, e := range t.orders {
+		if isFinal(e.order.Status) && e.updated.Before(cutoff) {
+			delete(t.orders, id)
+			removed++
+		}
+	}
+	return removed
+}
+
+// advances reports whether update moves the order forward: to a later
+// stage, or to more filled quantity within the same stage. StatusClosed can
+// still be resolved to the real final status.
+func advances(cur, update broker.Order) bool {
+	if isFinal(cur.Status) && cur.Status != StatusClosed {
+		return false
+	}
+
+	curStage, newStage := stage(cur.Status), stage(update.Status)
+	switch {
+	case cur.Status == StatusClosed:
+		return isFinal(update.Status) && update.Status != StatusClosed
+	case newStage > curStage:
+		return true
+	case newStage == curStage:
+		return update.FilledSize > cur.FilledSize
+	default:
+		return false
+	}
+}
+
+// merge applies update over cur, keeping fields the update leaves empty and
+// never lowering the filled quantity
+func merge(cur, update broker.Order) broker.Order {
+	merged := update
+	if merged.ClientOrderID == "" {
+		merged.ClientOrderID = cur.ClientOrderID
+	}
+	if merged.Symbol == "" {
+		merged.Symbol = cur.Symbol
+	}
+	if merged.Side == "" {
+		merged.Side = cur.Side
+	}
+	if merged.Type == "" {
+		merged.Type = cur.Type
+	}
+	if merged.Size == 0 {
+		merged.Size = cur.Size
+	}
+	if merged.Price == 0 {
+		merged.Price = cur.Price
+	}
+	if merged.StopPrice == 0 {
+		merged.StopPrice = cur.StopPrice
+	}
+	if merged.TimeInForce == "" {
+		merged.TimeInForce = cur.TimeInForce
+	}
+	if !cur.CreatedAt.IsZero() {
+		merged.CreatedAt = cur.CreatedAt
+	}
+	if merged.FilledSize < cur.FilledSize {
+		merged.FilledSize = cur.FilledSize
+		merged.AveragePrice = cur.AveragePrice
+	}
+	if merged.AveragePrice == 0 {
+		merged.AveragePrice = cur.AveragePrice
+	}
+	merged.ReduceOnly = merged.ReduceOnly || cur.ReduceOnly
+	return merged
+}
+
+// stage orders statuses along the lifecycle
+func stage(status broker.OrderStatus) int {
+	switch {
+	case isFinal(status):
+		return 2
+	case status == broker.OrderStatusPartiallyFilled:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// isFinal reports whether no further updates are expected for status
+func isFinal(status broker.OrderStatus) bool {
+	switch status {
+	case broker.OrderStatusFilled, broker.OrderStatusCanceled, broker.OrderStatusRejected,
+		broker.OrderStatusExpired, StatusClosed:
+		return true
+	}
+	return false
+}
+
+// compareIDs orders IDs by length, then lexically, so numeric IDs sort
+// numerically
+func compareIDs(a, b string) int {
+	if len(a) != len(b) {
+		return len(a) - len(b)
+	}
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,253 @@
+package ordertrack
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newTracker() (*brokertest.Broker, *Tracker) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	return b, Wrap(b, Config{})
+}
+
+func limit(size, price float64) *broker.OrderRequest {
+	return &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: size, Price: price}
+}
+
+func TestTracker_Lifecycle(t *testing.T) {
+	_, tr := newTracker()
+	ctx := context.Background()
+
+	var events []Event
+	tr.OnChange(func(ctx context.Context, e Event) { events = append(events, e) })
+
+	order, err := tr.PlaceOrder(ctx, limit(1, 45000))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	partial := *order
+	partial.Status = broker.OrderStatusPartiallyFilled
+	partial.FilledSize = 0.4
+	filled := partial
+	filled.Status = broker.OrderStatusFilled
+	filled.FilledSize = 1
+	filled.AveragePrice = 45000
+
+	updates := []struct {
+		name   string
+		order  broker.Order
+		source Source
+		want   bool
+	}{
+		{"partial fill", partial, SourceStream, true},
+		{"duplicate partial fill", partial, SourceStream, false},
+		{"stale NEW from REST", *order, SourceREST, false},
+		{"filled", filled, SourceStream, true},
+		{"replayed partial after reconnect", partial, SourceStream, false},
+	}
+	for _, u := range updates {
+		if got := tr.Apply(ctx, u.order, u.source); got != u.want {
+			t.Errorf("%s: Apply() = %v, want %v", u.name, got, u.want)
+		}
+	}
+
+	got, ok := tr.Get(order.ID)
+	if !ok || got.Status != broker.OrderStatusFilled || got.FilledSize != 1 || got.Price != 45000 {
+		t.Errorf("Get() = %+v, want FILLED 1 @ 45000", got)
+	}
+
+	wantPrevious := []broker.OrderStatus{"", broker.OrderStatusNew, broker.OrderStatusPartiallyFilled}
+	if len(events) != len(wantPrevious) {
+		t.Fatalf("events = %d, want %d", len(events), len(wantPrevious))
+	}
+	for i, e := range events {
+		if e.Previous != wantPrevious[i] {
+			t.Errorf("event %d Previous = %q, want %q", i, e.Previous, wantPrevious[i])
+		}
+	}
+	if events[0].Source != SourcePlace {
+		t.Errorf("first event source = %s, want PLACE", events[0].Source)
+	}
+}
+
+func TestTracker_Cancel(t *testing.T) {
+	_, tr := newTracker()
+	ctx := context.Background()
+
+	a, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+	b, _ := tr.PlaceOrder(ctx, limit(1, 44000))
+	c, _ := tr.PlaceOrder(ctx, limit(1, 43000))
+
+	if err := tr.CancelOrder(ctx, "BTC-USDT", a.ID); err != nil {
+		t.Fatal(err)
+	}
+	if o, _ := tr.Get(a.ID); o.Status != broker.OrderStatusCanceled {
+		t.Errorf("canceled order status = %s", o.Status)
+	}
+	if open := tr.Open(); len(open) != 2 || open[0].ID != b.ID || open[1].ID != c.ID {
+		t.Errorf("Open() = %v, want [%s %s]", open, b.ID, c.ID)
+	}
+
+	if err := tr.CancelAllOrders(ctx, "BTC-USDT"); err != nil {
+		t.Fatal(err)
+	}
+	if open := tr.Open(); len(open) != 0 {
+		t.Errorf("Open() after CancelAllOrders = %v", open)
+	}
+}
+
+// getter adds order lookups to the in-memory broker
+type getter struct {
+	*brokertest.Broker
+	final map[string]broker.Order
+}
+
+func (g *getter) GetOrder(ctx context.Context, symbol, orderID string) (*broker.Order, error) {
+	o, ok := g.final[orderID]
+	if !ok {
+		return nil, broker.ErrOrderNotFound
+	}
+	return &o, nil
+}
+
+func TestTracker_Sync(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("adopts open orders and closes missing ones", func(t *testing.T) {
+		b, tr := newTracker()
+		placed, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+		external := b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeLimit, Size: 2, Price: 55000})
+
+		// The placed order leaves the book behind the tracker's back
+		b.CancelOrder(ctx, "BTC-USDT", placed.ID)
+		tr.now = func() time.Time { return time.Now().Add(time.Second) }
+
+		if err := tr.Sync(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if o, _ := tr.Get(placed.ID); o.Status != StatusClosed {
+			t.Errorf("missing order status = %s, want CLOSED", o.Status)
+		}
+		if o, ok := tr.Get(external.ID); !ok || o.Status != broker.OrderStatusNew {
+			t.Errorf("external order = %+v, want tracked as NEW", o)
+		}
+	})
+
+	t.Run("fetches final status", func(t *testing.T) {
+		inner := brokertest.New()
+		g := &getter{Broker: inner, final: map[string]broker.Order{}}
+		tr := Wrap(g, Config{})
+
+		placed, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+		inner.CancelOrder(ctx, "BTC-USDT", placed.ID)
+		filled := *placed
+		filled.Status = broker.OrderStatusFilled
+		filled.FilledSize = 1
+		g.final[placed.ID] = filled
+		tr.now = func() time.Time { return time.Now().Add(time.Second) }
+
+		if err := tr.Sync(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if o, _ := tr.Get(placed.ID); o.Status != broker.OrderStatusFilled {
+			t.Errorf("status = %s, want FILLED", o.Status)
+		}
+	})
+
+	t.Run("continues past a failed lookup", func(t *testing.T) {
+		inner := brokertest.New()
+		g := &getter{Broker: inner, final: map[string]broker.Order{}}
+		tr := Wrap(g, Config{})
+
+		lost, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+		placed, _ := tr.PlaceOrder(ctx, limit(1, 44000))
+		inner.CancelAllOrders(ctx, "BTC-USDT")
+		filled := *placed
+		filled.Status, filled.FilledSize = broker.OrderStatusFilled, 1
+		g.final[placed.ID] = filled // No record of lost: its lookup fails
+		tr.now = func() time.Time { return time.Now().Add(time.Second) }
+
+		if err := tr.Sync(ctx); !errors.Is(err, broker.ErrOrderNotFound) {
+			t.Errorf("Sync() error = %v, want the failed lookup", err)
+		}
+		if o, _ := tr.Get(placed.ID); o.Status != broker.OrderStatusFilled {
+			t.Errorf("status = %s, want FILLED despite the other lookup failing", o.Status)
+		}
+		if o, _ := tr.Get(lost.ID); o.Status != broker.OrderStatusNew {
+			t.Errorf("status = %s, want NEW until a lookup succeeds", o.Status)
+		}
+	})
+
+	t.Run("keeps orders placed during the snapshot", func(t *testing.T) {
+		b, tr := newTracker()
+		placed, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+		b.CancelOrder(ctx, "BTC-USDT", placed.ID)
+		tr.now = func() time.Time { return time.Now().Add(-time.Second) }
+
+		if err := tr.Sync(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if o, _ := tr.Get(placed.ID); o.Status != broker.OrderStatusNew {
+			t.Errorf("status = %s, want NEW until the next sync", o.Status)
+		}
+	})
+}
+
+func TestTracker_Prune(t *testing.T) {
+	_, tr := newTracker()
+	ctx := context.Background()
+
+	a, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+	tr.PlaceOrder(ctx, limit(1, 44000))
+	tr.CancelOrder(ctx, "BTC-USDT", a.ID)
+
+	tr.now = func() time.Time { return time.Now().Add(time.Hour) }
+	if n := tr.Prune(time.Minute); n != 1 {
+		t.Errorf("Prune() = %d, want 1", n)
+	}
+	if _, ok := tr.Get(a.ID); ok {
+		t.Error("pruned order still tracked")
+	}
+	if len(tr.Orders(nil)) != 1 {
+		t.Errorf("Orders() = %d, want the open order kept", len(tr.Orders(nil)))
+	}
+}
+
+// flaky fails its first GetOrders calls
+type flaky struct {
+	*brokertest.Broker
+	failures atomic.Int32
+}
+
+func (f *flaky) GetOrders(ctx context.Context, filter *broker.OrderFilter) ([]*broker.Order, error) {
+	if f.failures.Add(-1) >= 0 {
+		return nil, broker.ErrRateLimited
+	}
+	return f.Broker.GetOrders(ctx, filter)
+}
+
+func TestTracker_RunSurvivesErrors(t *testing.T) {
+	b := &flaky{Broker: brokertest.New()}
+	b.failures.Store(2)
+	external := b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeLimit, Size: 2, Price: 55000})
+	tr := Wrap(b, Config{Interval: time.Millisecond})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tr.OnChange(func(ctx context.Context, e Event) {
+		if e.Order.ID == external.ID {
+			cancel()
+		}
+	})
+	if err := tr.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() = %v, want it to keep syncing past the errors until canceled", err)
+	}
+}
@@ -0,0 +1,127 @@
+package ordertrack
+
+import (
+	"context"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// GapReason explains why the user data stream can no longer be trusted to
+// be complete
+type GapReason string
+
+const (
+	GapSequence   GapReason = "SEQUENCE"     // Sequence number skipped ahead
+	GapOutOfOrder GapReason = "OUT_OF_ORDER" // Event older than one already applied
+	GapReconnect  GapReason = "RECONNECT"    // Stream reconnected; events may have been missed
+)
+
+// StreamUpdate is an order update from a user data stream
+type StreamUpdate struct {
+	Order broker.Order
+	// Seq is the stream's sequence number (0 = the stream has none, order by
+	// Time instead)
+	Seq uint64
+	// Time is the exchange event time
+	Time time.Time
+}
+
+// Gap describes a detected break in the user data stream
+type Gap struct {
+	Reason   GapReason
+	Expected uint64    // Next sequence number expected (GapSequence)
+	Got      uint64    // Sequence number received (GapSequence)
+	Last     time.Time // Latest event time applied before the gap
+	Time     time.Time
+	SyncErr  error // Error of the reconciliation Sync, nil when it succeeded
+}
+
+// GapHandler receives detected gaps, after the reconciliation Sync ran.
+// Handlers run synchronously and should return quickly.
+type GapHandler func(ctx context.Context, g Gap)
+
+// OnGap registers a handler for user data stream gaps, e.g. to count them
+// in a metric or raise an alarm
+func (t *Tracker) OnGap(h GapHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.gapHandlers = append(t.gapHandlers, h)
+}
+
+// ApplyStream applies a user data stream update after validating its order:
+// a skipped sequence number or an event older than the latest applied one
+// means updates were lost or reordered, so the tracker reconciles with Sync
+// and reports the gap. The update itself is always applied; stale ones are
+// ignored as with Apply. It returns whether the update changed the tracked
+// state and the Sync error, if any.
+func (t *Tracker) ApplyStream(ctx context.Context, u StreamUpdate) (bool, error) {
+	t.mu.Lock()
+	gap, found := t.checkSequence(u)
+	t.mu.Unlock()
+
+	changed := t.Apply(ctx, u.Order, SourceStream)
+	if !found {
+		return changed, nil
+	}
+	return changed, t.reconcile(ctx, gap)
+}
+
+// Reconnected tells the tracker that the user data stream reconnected.
+// Updates sent while it was down are lost, so it reconciles with Sync and
+// reports a GapReconnect. Sequence validation restarts with the next update.
+func (t *Tracker) Reconnected(ctx context.Context) error {
+	t.mu.Lock()
+	gap := Gap{Reason: GapReconnect, Last: t.lastEvent, Time: t.now()}
+	t.seq = 0
+	t.mu.Unlock()
+	return t.reconcile(ctx, gap)
+}
+
+// Gaps returns how many gaps have been detected
+func (t *Tracker) Gaps() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.gaps
+}
+
+// checkSequence records u as the latest update and reports whether it
+// reveals a gap. Callers must hold t.mu.
+func (t *Tracker) checkSequence(u StreamUpdate) (Gap, bool) {
+	gap := Gap{Last: t.lastEvent, Time: t.now()}
+	found := false
+
+	switch {
+	case u.Seq > 0 && t.seq > 0 && u.Seq > t.seq+1:
+		gap.Reason, gap.Expected, gap.Got = GapSequence, t.seq+1, u.Seq
+		found = true
+	case u.Seq > 0 && t.seq > 0 && u.Seq <= t.seq:
+		// Duplicate or replayed update; Apply discards it
+	case u.Seq == 0 && !u.Time.IsZero() && u.Time.Before(t.lastEvent):
+		gap.Reason = GapOutOfOrder
+		found = true
+	}
+
+	if u.Seq > t.seq {
+		t.seq = u.Seq
+	}
+	if u.Time.After(t.lastEvent) {
+		t.lastEvent = u.Time
+	}
+	return gap, found
+}
+
+// reconcile syncs with the REST snapshot and notifies gap handlers
+func (t *Tracker) reconcile(ctx context.Context, gap Gap) error {
+	gap.SyncErr = t.Sync(ctx)
+
+	t.mu.Lock()
+	t.gaps++
+	handlers := t.gapHandlers
+	t.mu.Unlock()
+
+	for _, h := range handlers {
+		h(ctx, gap)
+	}
+	return gap.SyncErr
+}
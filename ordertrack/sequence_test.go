@@ -0,0 +1,110 @@
+package ordertrack
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func TestTracker_ApplyStream(t *testing.T) {
+	ctx := context.Background()
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		updates []StreamUpdate
+		want    []GapReason
+	}{
+		{"contiguous sequence", []StreamUpdate{{Seq: 1}, {Seq: 2}, {Seq: 3}}, nil},
+		{"duplicate sequence", []StreamUpdate{{Seq: 1}, {Seq: 2}, {Seq: 2}}, nil},
+		{"skipped sequence", []StreamUpdate{{Seq: 1}, {Seq: 4}, {Seq: 5}}, []GapReason{GapSequence}},
+		{"ordered timestamps", []StreamUpdate{{Time: at}, {Time: at}, {Time: at.Add(time.Second)}}, nil},
+		{"timestamp goes back", []StreamUpdate{{Time: at.Add(time.Second)}, {Time: at}}, []GapReason{GapOutOfOrder}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, tr := newTracker()
+			order, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+
+			var gaps []Gap
+			tr.OnGap(func(ctx context.Context, g Gap) { gaps = append(gaps, g) })
+
+			for _, u := range tt.updates {
+				u.Order = *order
+				if _, err := tr.ApplyStream(ctx, u); err != nil {
+					t.Fatalf("ApplyStream() error = %v", err)
+				}
+			}
+			if len(gaps) != len(tt.want) || tr.Gaps() != len(tt.want) {
+				t.Fatalf("gaps = %+v (Gaps() = %d), want %v", gaps, tr.Gaps(), tt.want)
+			}
+			for i, g := range gaps {
+				if g.Reason != tt.want[i] {
+					t.Errorf("gap %d reason = %s, want %s", i, g.Reason, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestTracker_ApplyStream_ReconcilesMissedUpdate(t *testing.T) {
+	ctx := context.Background()
+	b, tr := newTracker()
+	first, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+	second, _ := tr.PlaceOrder(ctx, limit(1, 44000))
+
+	// Update 2, the cancel of the first order, is lost
+	b.CancelOrder(ctx, "BTC-USDT", first.ID)
+	tr.now = func() time.Time { return time.Now().Add(time.Second) }
+
+	var gap Gap
+	tr.OnGap(func(ctx context.Context, g Gap) { gap = g })
+
+	tr.ApplyStream(ctx, StreamUpdate{Order: *second, Seq: 1})
+	partial := *second
+	partial.Status = broker.OrderStatusPartiallyFilled
+	partial.FilledSize = 0.5
+	if _, err := tr.ApplyStream(ctx, StreamUpdate{Order: partial, Seq: 3}); err != nil {
+		t.Fatalf("ApplyStream() error = %v", err)
+	}
+
+	if gap.Reason != GapSequence || gap.Expected != 2 || gap.Got != 3 {
+		t.Errorf("gap = %+v, want SEQUENCE expecting 2, got 3", gap)
+	}
+	if o, _ := tr.Get(first.ID); o.Status != StatusClosed {
+		t.Errorf("first order status = %s, want CLOSED after reconciliation", o.Status)
+	}
+	if o, _ := tr.Get(second.ID); o.FilledSize != 0.5 {
+		t.Errorf("second order filled = %v, want 0.5", o.FilledSize)
+	}
+}
+
+func TestTracker_Reconnected(t *testing.T) {
+	ctx := context.Background()
+	b, tr := newTracker()
+	order, _ := tr.PlaceOrder(ctx, limit(1, 45000))
+
+	var gaps []Gap
+	tr.OnGap(func(ctx context.Context, g Gap) { gaps = append(gaps, g) })
+	tr.ApplyStream(ctx, StreamUpdate{Order: *order, Seq: 10})
+
+	b.Err = broker.ErrRateLimited
+	if err := tr.Reconnected(ctx); !errors.Is(err, broker.ErrRateLimited) {
+		t.Errorf("Reconnected() error = %v, want %v", err, broker.ErrRateLimited)
+	}
+	b.Err = nil
+	if len(gaps) != 1 || gaps[0].Reason != GapReconnect || gaps[0].SyncErr == nil {
+		t.Fatalf("gaps = %+v, want one RECONNECT with the sync error", gaps)
+	}
+
+	// The new connection starts its own sequence
+	if _, err := tr.ApplyStream(ctx, StreamUpdate{Order: *order, Seq: 1}); err != nil {
+		t.Fatal(err)
+	}
+	if len(gaps) != 1 {
+		t.Errorf("gaps = %+v, want no gap after the reconnect reset", gaps)
+	}
+}
@@ -0,0 +1,287 @@
+// Package parity checks backtest assumptions against a live exchange. A
+// Broker sends every order to the exchange (typically its demo environment)
+// and to a simulator at the same time, then reports how the simulated
+// fills and PnL diverge from what the exchange reported over the session.
+package parity
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/analytics"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/clock"
+)
+
+// Simulator is the backtest fill model under test. It fills orders at the
+// price set before each order; brokertest.Broker implements it.
+type Simulator interface {
+	broker.Broker
+	SetPrice(symbol string, price float64)
+}
+
+// Config configures a Broker
+type Config struct {
+	// FeeRate is charged on both sides' fills when computing PnL, as a
+	// fraction of notional
+	FeeRate float64
+	// Clock stamps orders and the report (default: the system clock)
+	Clock clock.Clock
+}
+
+// Broker is a broker.Broker that mirrors orders to a simulator. The
+// strategy sees the live exchange; the simulator only shadows it.
+type Broker struct {
+	broker.Broker
+	sim    Simulator
+	config Config
+
+	mu    sync.Mutex
+	pairs []*pair
+	start time.Time
+}
+
+// pair is one order as placed on both sides
+type pair struct {
+	time     time.Time
+	req      broker.OrderRequest
+	decision float64 // Live price when the order was sent
+	live     *broker.Order
+	sim      *broker.Order
+	liveErr  error
+	simErr   error
+}
+
+// Wrap mirrors the orders placed through live to sim
+func Wrap(live broker.Broker, sim Simulator, config Config) *Broker {
+	if config.Clock == nil {
+		config.Clock = clock.Real{}
+	}
+	return &Broker{Broker: live, sim: sim, config: config, start: config.Clock.Now()}
+}
+
+// PlaceOrder places the order on the exchange and on the simulator, which
+// is first moved to the exchange's current price. The exchange result is
+// returned; simulator failures are only recorded.
+func (b *Broker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	p := &pair{time: b.config.Clock.Now(), req: *req}
+	if price, err := b.Broker.GetCurrentPrice(ctx, req.Symbol); err == nil {
+		p.decision = price
+		b.sim.SetPrice(req.Symbol, price)
+	}
+
+	p.live, p.liveErr = b.Broker.PlaceOrder(ctx, req)
+	p.sim, p.simErr = b.sim.PlaceOrder(ctx, req)
+
+	b.mu.Lock()
+	b.pairs = append(b.pairs, p)
+	b.mu.Unlock()
+	return p.live, p.liveErr
+}
+
+// Divergence compares one order's simulated and live outcome
+type Divergence struct {
+	Time       time.Time          `json:"time"`
+	Symbol     string             `json:"symbol"`
+	Side       broker.Side        `json:"side"`
+	Type       broker.OrderType   `json:"type"`
+	Decision   float64            `json:"decisionPrice"`
+	SimStatus  broker.OrderStatus `json:"simStatus,omitempty"`
+	SimPrice   float64            `json:"simPrice"`
+	SimSize    float64            `json:"simSize"` // Filled size
+	LiveID     string             `json:"liveId,omitempty"`
+	LiveStatus broker.OrderStatus `json:"liveStatus,omitempty"`
+	LivePrice  float64            `json:"livePrice"`
+	LiveSize   float64            `json:"liveSize"` // Filled size
+	// Slippage is the live fill price's move against the order relative to
+	// the simulated fill price: positive when live filled worse
+	Slippage float64 `json:"slippage"`
+	// Mismatch is set when one side filled, errored or rejected and the
+	// other did not
+	Mismatch bool   `json:"mismatch"`
+	SimErr   string `json:"simError,omitempty"`
+	LiveErr  string `json:"liveError,omitempty"`
+}
+
+// Report summarizes the divergence over a session
+type Report struct {
+	Start       time.Time    `json:"start"`
+	End         time.Time    `json:"end"`
+	Orders      int          `json:"orders"`
+	Mismatches  int          `json:"mismatches"`
+	AvgSlippage float64      `json:"avgSlippage"` // Over orders filled on both sides
+	MaxSlippage float64      `json:"maxSlippage"`
+	SimPnL      float64      `json:"simPnl"`  // Net PnL of closed simulated trades
+	LivePnL     float64      `json:"livePnl"` // Net PnL of closed live trades
+	PnLDiff     float64      `json:"pnlDiff"` // LivePnL - SimPnL
+	Divergences []Divergence `json:"divergences"`
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r)
+}
+
+// Report compares every order mirrored so far. Live orders whose fill
+// isn't known yet (still open, or acknowledged without a filled size) are
+// refreshed first, by ID if the exchange implements broker.OrderGetter and
+// from its open orders otherwise; refresh failures keep the last known
+// state.
+func (b *Broker) Report(ctx context.Context) *Report {
+	b.mu.Lock()
+	pairs := append([]*pair(nil), b.pairs...)
+	b.mu.Unlock()
+
+	b.refresh(ctx, pairs)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	report := &Report{Start: b.start, End: b.config.Clock.Now(), Orders: len(pairs)}
+	var simFills, liveFills []analytics.Fill
+	var slippages int
+	for _, p := range pairs {
+		d := b.compare(p)
+		if d.Mismatch {
+			report.Mismatches++
+		}
+		if d.SimSize > 0 && d.LiveSize > 0 {
+			slippages++
+			report.AvgSlippage += d.Slippage
+			report.MaxSlippage = math.Max(report.MaxSlippage, d.Slippage)
+		}
+		simFills = append(simFills, b.fill(p, d.SimPrice, d.SimSize))
+		liveFills = append(liveFills, b.fill(p, d.LivePrice, d.LiveSize))
+		report.Divergences = append(report.Divergences, d)
+	}
+	if slippages > 0 {
+		report.AvgSlippage /= float64(slippages)
+	}
+
+	report.SimPnL = netPnL(simFills)
+	report.LivePnL = netPnL(liveFills)
+	report.PnLDiff = report.LivePnL - report.SimPnL
+	return report
+}
+
+// refresh updates the live orders of pairs whose fill isn't known yet.
+// Without a broker.OrderGetter only orders still open can be updated: those
+// that left the open orders keep their acknowledgement.
+func (b *Broker) refresh(ctx context.Context, pairs []*pair) {
+	var stale []*pair
+	for _, p := range pairs {
+		if p.live != nil && unsettled(p.live) {
+			stale = append(stale, p)
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	getter, ok := b.Broker.(broker.OrderGetter)
+	var open map[string]*broker.Order
+	if !ok {
+		orders, err := b.Broker.GetOrders(ctx, nil)
+		if err != nil {
+			return
+		}
+		open = make(map[string]*broker.Order, len(orders))
+		for _, o := range orders {
+			open[o.ID] = o
+		}
+	}
+
+	for _, p := range stale {
+		var order *broker.Order
+		if ok {
+			order, _ = getter.GetOrder(ctx, p.live.Symbol, p.live.ID)
+		} else {
+			order = open[p.live.ID]
+		}
+		if order != nil {
+			b.mu.Lock()
+			p.live = order
+			b.mu.Unlock()
+		}
+	}
+}
+
+// unsettled reports whether the order's fill may still change or was
+// reported without its size, as in bingx order acknowledgements
+func unsettled(o *broker.Order) bool {
+	return !final(o.Status) || (o.Status == broker.OrderStatusFilled && o.FilledSize == 0)
+}
+
+// compare builds the divergence of one pair
+func (b *Broker) compare(p *pair) Divergence {
+	d := Divergence{
+		Time:     p.time,
+		Symbol:   p.req.Symbol,
+		Side:     p.req.Side,
+		Type:     p.req.Type,
+		Decision: p.decision,
+	}
+	if p.sim != nil {
+		d.SimStatus, d.SimPrice, d.SimSize = p.sim.Status, fillPrice(p.sim), p.sim.FilledSize
+	}
+	if p.live != nil {
+		d.LiveID, d.LiveStatus, d.LivePrice, d.LiveSize = p.live.ID, p.live.Status, fillPrice(p.live), p.live.FilledSize
+	}
+	if p.simErr != nil {
+		d.SimErr = p.simErr.Error()
+	}
+	if p.liveErr != nil {
+		d.LiveErr = p.liveErr.Error()
+	}
+
+	if d.SimSize > 0 && d.LiveSize > 0 && d.SimPrice > 0 {
+		d.Slippage = (d.LivePrice - d.SimPrice) / d.SimPrice
+		if p.req.Side == broker.SideShort {
+			d.Slippage = -d.Slippage
+		}
+	}
+	d.Mismatch = (p.simErr == nil) != (p.liveErr == nil) || (d.SimSize > 0) != (d.LiveSize > 0)
+	return d
+}
+
+// fill returns one side's execution of p for PnL
+func (b *Broker) fill(p *pair, price, size float64) analytics.Fill {
+	return analytics.Fill{
+		Symbol: p.req.Symbol,
+		Side:   p.req.Side,
+		Price:  price,
+		Size:   size,
+		Fee:    price * size * b.config.FeeRate,
+		Time:   p.time,
+	}
+}
+
+// fillPrice is the order's average fill price, falling back to its limit
+// price for fills reported without one
+func fillPrice(o *broker.Order) float64 {
+	if o.AveragePrice > 0 {
+		return o.AveragePrice
+	}
+	return o.Price
+}
+
+func netPnL(fills []analytics.Fill) float64 {
+	var pnl float64
+	for _, t := range analytics.Trades(fills) {
+		pnl += t.NetPnL()
+	}
+	return pnl
+}
+
+func final(status broker.OrderStatus) bool {
+	switch status {
+	case broker.OrderStatusFilled, broker.OrderStatusCanceled, broker.OrderStatusRejected, broker.OrderStatusExpired:
+		return true
+	}
+	return false
+}
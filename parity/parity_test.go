@@ -0,0 +1,138 @@
+package parity
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// slipping fills market orders slip worse than its quoted price, the way
+// an exchange with thin books does
+type slipping struct {
+	*brokertest.Broker
+	slip float64
+}
+
+func (s *slipping) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	order, err := s.Broker.PlaceOrder(ctx, req)
+	if err == nil && order.Status == broker.OrderStatusFilled {
+		if req.Side == broker.SideLong {
+			order.AveragePrice += s.slip
+		} else {
+			order.AveragePrice -= s.slip
+		}
+	}
+	return order, err
+}
+
+// acking acknowledges orders the way bingx does, without their fill, and
+// reports the fill through GetOrder
+type acking struct {
+	*brokertest.Broker
+	placed map[string]broker.Order
+}
+
+func (a *acking) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	order, err := a.Broker.PlaceOrder(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	a.placed[order.ID] = *order
+	ack := *order
+	ack.Status, ack.FilledSize, ack.AveragePrice = broker.OrderStatusNew, 0, 0
+	return &ack, nil
+}
+
+func (a *acking) GetOrder(ctx context.Context, symbol, orderID string) (*broker.Order, error) {
+	order, ok := a.placed[orderID]
+	if !ok {
+		return nil, broker.ErrOrderNotFound
+	}
+	return &order, nil
+}
+
+func market(side broker.Side, reduceOnly bool) *broker.OrderRequest {
+	return &broker.OrderRequest{Symbol: "BTC-USDT", Side: side, Type: broker.OrderTypeMarket, Size: 1, ReduceOnly: reduceOnly}
+}
+
+func TestBroker_Report(t *testing.T) {
+	live := &slipping{Broker: brokertest.New(), slip: 10}
+	live.SetPrice("BTC-USDT", 50000)
+	sim := brokertest.New()
+	b := Wrap(live, sim, Config{})
+	ctx := context.Background()
+
+	if _, err := b.PlaceOrder(ctx, market(broker.SideLong, false)); err != nil {
+		t.Fatal(err)
+	}
+	live.SetPrice("BTC-USDT", 51000)
+	if _, err := b.PlaceOrder(ctx, market(broker.SideShort, true)); err != nil {
+		t.Fatal(err)
+	}
+	// A resting limit order: neither side fills
+	b.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Size: 1, Price: 40000})
+
+	r := b.Report(ctx)
+	if r.Orders != 3 || r.Mismatches != 0 {
+		t.Errorf("orders = %d, mismatches = %d, want 3 and 0", r.Orders, r.Mismatches)
+	}
+	if d := r.Divergences[0]; d.Decision != 50000 || d.SimPrice != 50000 || d.LivePrice != 50010 {
+		t.Errorf("first divergence = %+v", d)
+	}
+	if want := 10.0 / 50000; math.Abs(r.Divergences[0].Slippage-want) > 1e-12 {
+		t.Errorf("buy slippage = %v, want %v", r.Divergences[0].Slippage, want)
+	}
+	if want := 10.0 / 51000; math.Abs(r.Divergences[1].Slippage-want) > 1e-12 {
+		t.Errorf("sell slippage = %v, want %v (positive: filled worse)", r.Divergences[1].Slippage, want)
+	}
+	if r.SimPnL != 1000 || r.LivePnL != 980 || r.PnLDiff != -20 {
+		t.Errorf("PnL sim = %v, live = %v, diff = %v, want 1000, 980, -20", r.SimPnL, r.LivePnL, r.PnLDiff)
+	}
+
+	var buf bytes.Buffer
+	if err := r.WriteJSON(&buf); err != nil || !strings.Contains(buf.String(), `"pnlDiff": -20`) {
+		t.Errorf("WriteJSON() = %s, %v", buf.String(), err)
+	}
+}
+
+func TestBroker_ReportRefreshesAcks(t *testing.T) {
+	live := &acking{Broker: brokertest.New(), placed: map[string]broker.Order{}}
+	live.SetPrice("BTC-USDT", 50000)
+	b := Wrap(live, brokertest.New(), Config{})
+	ctx := context.Background()
+
+	b.PlaceOrder(ctx, market(broker.SideLong, false))
+	live.SetPrice("BTC-USDT", 50500)
+	b.PlaceOrder(ctx, market(broker.SideShort, true))
+
+	r := b.Report(ctx)
+	if d := r.Divergences[0]; d.LiveStatus != broker.OrderStatusFilled || d.LiveSize != 1 || d.LivePrice != 50000 || d.Mismatch {
+		t.Errorf("first divergence = %+v, want the live fill looked up by ID", d)
+	}
+	if r.Mismatches != 0 || r.LivePnL != 500 {
+		t.Errorf("mismatches = %d, live PnL = %v, want 0 and 500", r.Mismatches, r.LivePnL)
+	}
+}
+
+func TestBroker_Mismatch(t *testing.T) {
+	live := brokertest.New()
+	live.SetPrice("BTC-USDT", 50000)
+	sim := brokertest.New()
+	sim.Err = broker.ErrInsufficientBalance
+	b := Wrap(live, sim, Config{})
+
+	order, err := b.PlaceOrder(context.Background(), market(broker.SideLong, false))
+	if err != nil || order.Status != broker.OrderStatusFilled {
+		t.Fatalf("PlaceOrder() = %+v, %v, want the live fill", order, err)
+	}
+
+	r := b.Report(context.Background())
+	if r.Mismatches != 1 || r.Divergences[0].SimErr == "" || !r.Divergences[0].Mismatch {
+		t.Errorf("report = %+v, want a mismatch with the simulator error", r)
+	}
+}
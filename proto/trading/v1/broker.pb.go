@@ -0,0 +1,2148 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: proto/trading/v1/broker.proto
+
+package tradingv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Side int32
+
+const (
+	Side_SIDE_UNSPECIFIED Side = 0
+	Side_SIDE_LONG        Side = 1
+	Side_SIDE_SHORT       Side = 2
+)
+
+// Enum value maps for Side.
+var (
+	Side_name = map[int32]string{
+		0: "SIDE_UNSPECIFIED",
+		1: "SIDE_LONG",
+		2: "SIDE_SHORT",
+	}
+	Side_value = map[string]int32{
+		"SIDE_UNSPECIFIED": 0,
+		"SIDE_LONG":        1,
+		"SIDE_SHORT":       2,
+	}
+)
+
+func (x Side) Enum() *Side {
+	p := new(Side)
+	*p = x
+	return p
+}
+
+func (x Side) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Side) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_trading_v1_broker_proto_enumTypes[0].Descriptor()
+}
+
+func (Side) Type() protoreflect.EnumType {
+	return &file_proto_trading_v1_broker_proto_enumTypes[0]
+}
+
+func (x Side) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Side.Descriptor instead.
+func (Side) EnumDescriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{0}
+}
+
+type Balance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         string                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	Total         float64                `protobuf:"fixed64,2,opt,name=total,proto3" json:"total,omitempty"`
+	Available     float64                `protobuf:"fixed64,3,opt,name=available,proto3" json:"available,omitempty"`
+	InUse         float64                `protobuf:"fixed64,4,opt,name=in_use,json=inUse,proto3" json:"in_use,omitempty"`
+	UnrealizedPnl float64                `protobuf:"fixed64,5,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	RealizedPnl   float64                `protobuf:"fixed64,6,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Balance) Reset() {
+	*x = Balance{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Balance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Balance) ProtoMessage() {}
+
+func (x *Balance) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Balance.ProtoReflect.Descriptor instead.
+func (*Balance) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Balance) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *Balance) GetTotal() float64 {
+	if x != nil {
+		return x.Total
+	}
+	return 0
+}
+
+func (x *Balance) GetAvailable() float64 {
+	if x != nil {
+		return x.Available
+	}
+	return 0
+}
+
+func (x *Balance) GetInUse() float64 {
+	if x != nil {
+		return x.InUse
+	}
+	return 0
+}
+
+func (x *Balance) GetUnrealizedPnl() float64 {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return 0
+}
+
+func (x *Balance) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *Balance) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type Position struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Symbol            string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side              Side                   `protobuf:"varint,2,opt,name=side,proto3,enum=trading.v1.Side" json:"side,omitempty"`
+	Size              float64                `protobuf:"fixed64,3,opt,name=size,proto3" json:"size,omitempty"`
+	EntryPrice        float64                `protobuf:"fixed64,4,opt,name=entry_price,json=entryPrice,proto3" json:"entry_price,omitempty"`
+	MarkPrice         float64                `protobuf:"fixed64,5,opt,name=mark_price,json=markPrice,proto3" json:"mark_price,omitempty"`
+	LiquidationPrice  float64                `protobuf:"fixed64,6,opt,name=liquidation_price,json=liquidationPrice,proto3" json:"liquidation_price,omitempty"`
+	Leverage          int32                  `protobuf:"varint,7,opt,name=leverage,proto3" json:"leverage,omitempty"`
+	UnrealizedPnl     float64                `protobuf:"fixed64,8,opt,name=unrealized_pnl,json=unrealizedPnl,proto3" json:"unrealized_pnl,omitempty"`
+	RealizedPnl       float64                `protobuf:"fixed64,9,opt,name=realized_pnl,json=realizedPnl,proto3" json:"realized_pnl,omitempty"`
+	Margin            float64                `protobuf:"fixed64,10,opt,name=margin,proto3" json:"margin,omitempty"`
+	MaintenanceMargin float64                `protobuf:"fixed64,11,opt,name=maintenance_margin,json=maintenanceMargin,proto3" json:"maintenance_margin,omitempty"`
+	Timestamp         *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Position) Reset() {
+	*x = Position{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Position) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Position) ProtoMessage() {}
+
+func (x *Position) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Position.ProtoReflect.Descriptor instead.
+func (*Position) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Position) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Position) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Position) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Position) GetEntryPrice() float64 {
+	if x != nil {
+		return x.EntryPrice
+	}
+	return 0
+}
+
+func (x *Position) GetMarkPrice() float64 {
+	if x != nil {
+		return x.MarkPrice
+	}
+	return 0
+}
+
+func (x *Position) GetLiquidationPrice() float64 {
+	if x != nil {
+		return x.LiquidationPrice
+	}
+	return 0
+}
+
+func (x *Position) GetLeverage() int32 {
+	if x != nil {
+		return x.Leverage
+	}
+	return 0
+}
+
+func (x *Position) GetUnrealizedPnl() float64 {
+	if x != nil {
+		return x.UnrealizedPnl
+	}
+	return 0
+}
+
+func (x *Position) GetRealizedPnl() float64 {
+	if x != nil {
+		return x.RealizedPnl
+	}
+	return 0
+}
+
+func (x *Position) GetMargin() float64 {
+	if x != nil {
+		return x.Margin
+	}
+	return 0
+}
+
+func (x *Position) GetMaintenanceMargin() float64 {
+	if x != nil {
+		return x.MaintenanceMargin
+	}
+	return 0
+}
+
+func (x *Position) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+type Order struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ClientOrderId string                 `protobuf:"bytes,2,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          Side                   `protobuf:"varint,4,opt,name=side,proto3,enum=trading.v1.Side" json:"side,omitempty"`
+	Type          string                 `protobuf:"bytes,5,opt,name=type,proto3" json:"type,omitempty"`     // broker.OrderType, e.g. "MARKET", "LIMIT"
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"` // broker.OrderStatus, e.g. "NEW", "FILLED"
+	Size          float64                `protobuf:"fixed64,7,opt,name=size,proto3" json:"size,omitempty"`
+	Price         float64                `protobuf:"fixed64,8,opt,name=price,proto3" json:"price,omitempty"`
+	StopPrice     float64                `protobuf:"fixed64,9,opt,name=stop_price,json=stopPrice,proto3" json:"stop_price,omitempty"`
+	FilledSize    float64                `protobuf:"fixed64,10,opt,name=filled_size,json=filledSize,proto3" json:"filled_size,omitempty"`
+	AveragePrice  float64                `protobuf:"fixed64,11,opt,name=average_price,json=averagePrice,proto3" json:"average_price,omitempty"`
+	ReduceOnly    bool                   `protobuf:"varint,12,opt,name=reduce_only,json=reduceOnly,proto3" json:"reduce_only,omitempty"`
+	TimeInForce   string                 `protobuf:"bytes,13,opt,name=time_in_force,json=timeInForce,proto3" json:"time_in_force,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Order) Reset() {
+	*x = Order{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Order) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Order) ProtoMessage() {}
+
+func (x *Order) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Order.ProtoReflect.Descriptor instead.
+func (*Order) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Order) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Order) GetClientOrderId() string {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return ""
+}
+
+func (x *Order) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Order) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Order) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Order) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *Order) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Order) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Order) GetStopPrice() float64 {
+	if x != nil {
+		return x.StopPrice
+	}
+	return 0
+}
+
+func (x *Order) GetFilledSize() float64 {
+	if x != nil {
+		return x.FilledSize
+	}
+	return 0
+}
+
+func (x *Order) GetAveragePrice() float64 {
+	if x != nil {
+		return x.AveragePrice
+	}
+	return 0
+}
+
+func (x *Order) GetReduceOnly() bool {
+	if x != nil {
+		return x.ReduceOnly
+	}
+	return false
+}
+
+func (x *Order) GetTimeInForce() string {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return ""
+}
+
+func (x *Order) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type TriggerConfig struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TriggerPrice  float64                `protobuf:"fixed64,1,opt,name=trigger_price,json=triggerPrice,proto3" json:"trigger_price,omitempty"`
+	OrderPrice    float64                `protobuf:"fixed64,2,opt,name=order_price,json=orderPrice,proto3" json:"order_price,omitempty"`  // 0 = market
+	WorkingType   string                 `protobuf:"bytes,3,opt,name=working_type,json=workingType,proto3" json:"working_type,omitempty"` // "MARK_PRICE" or "LAST_PRICE"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TriggerConfig) Reset() {
+	*x = TriggerConfig{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TriggerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TriggerConfig) ProtoMessage() {}
+
+func (x *TriggerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TriggerConfig.ProtoReflect.Descriptor instead.
+func (*TriggerConfig) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TriggerConfig) GetTriggerPrice() float64 {
+	if x != nil {
+		return x.TriggerPrice
+	}
+	return 0
+}
+
+func (x *TriggerConfig) GetOrderPrice() float64 {
+	if x != nil {
+		return x.OrderPrice
+	}
+	return 0
+}
+
+func (x *TriggerConfig) GetWorkingType() string {
+	if x != nil {
+		return x.WorkingType
+	}
+	return ""
+}
+
+type TrailingConfig struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	ActivationPrice float64                `protobuf:"fixed64,1,opt,name=activation_price,json=activationPrice,proto3" json:"activation_price,omitempty"`
+	CallbackRate    float64                `protobuf:"fixed64,2,opt,name=callback_rate,json=callbackRate,proto3" json:"callback_rate,omitempty"`
+	WorkingType     string                 `protobuf:"bytes,3,opt,name=working_type,json=workingType,proto3" json:"working_type,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TrailingConfig) Reset() {
+	*x = TrailingConfig{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrailingConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrailingConfig) ProtoMessage() {}
+
+func (x *TrailingConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrailingConfig.ProtoReflect.Descriptor instead.
+func (*TrailingConfig) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TrailingConfig) GetActivationPrice() float64 {
+	if x != nil {
+		return x.ActivationPrice
+	}
+	return 0
+}
+
+func (x *TrailingConfig) GetCallbackRate() float64 {
+	if x != nil {
+		return x.CallbackRate
+	}
+	return 0
+}
+
+func (x *TrailingConfig) GetWorkingType() string {
+	if x != nil {
+		return x.WorkingType
+	}
+	return ""
+}
+
+type Trade struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Id            string                 `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Price         float64                `protobuf:"fixed64,3,opt,name=price,proto3" json:"price,omitempty"`
+	Size          float64                `protobuf:"fixed64,4,opt,name=size,proto3" json:"size,omitempty"`
+	Side          Side                   `protobuf:"varint,5,opt,name=side,proto3,enum=trading.v1.Side" json:"side,omitempty"` // Aggressor side
+	Time          *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Trade) Reset() {
+	*x = Trade{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Trade) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Trade) ProtoMessage() {}
+
+func (x *Trade) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Trade.ProtoReflect.Descriptor instead.
+func (*Trade) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *Trade) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Trade) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Trade) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *Trade) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *Trade) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *Trade) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+type GetBalanceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalanceRequest) Reset() {
+	*x = GetBalanceRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalanceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalanceRequest) ProtoMessage() {}
+
+func (x *GetBalanceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalanceRequest.ProtoReflect.Descriptor instead.
+func (*GetBalanceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{6}
+}
+
+type GetPositionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`                   // Empty = all
+	Side          Side                   `protobuf:"varint,2,opt,name=side,proto3,enum=trading.v1.Side" json:"side,omitempty"` // Unspecified = all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionsRequest) Reset() {
+	*x = GetPositionsRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionsRequest) ProtoMessage() {}
+
+func (x *GetPositionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionsRequest.ProtoReflect.Descriptor instead.
+func (*GetPositionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetPositionsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetPositionsRequest) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+type GetPositionsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Positions     []*Position            `protobuf:"bytes,1,rep,name=positions,proto3" json:"positions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionsResponse) Reset() {
+	*x = GetPositionsResponse{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionsResponse) ProtoMessage() {}
+
+func (x *GetPositionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionsResponse.ProtoReflect.Descriptor instead.
+func (*GetPositionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetPositionsResponse) GetPositions() []*Position {
+	if x != nil {
+		return x.Positions
+	}
+	return nil
+}
+
+type GetPositionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPositionRequest) Reset() {
+	*x = GetPositionRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPositionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPositionRequest) ProtoMessage() {}
+
+func (x *GetPositionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPositionRequest.ProtoReflect.Descriptor instead.
+func (*GetPositionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetPositionRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type PlaceOrderRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Symbol      string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side        Side                   `protobuf:"varint,2,opt,name=side,proto3,enum=trading.v1.Side" json:"side,omitempty"`
+	Type        string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Size        float64                `protobuf:"fixed64,4,opt,name=size,proto3" json:"size,omitempty"`
+	Price       float64                `protobuf:"fixed64,5,opt,name=price,proto3" json:"price,omitempty"`
+	StopPrice   float64                `protobuf:"fixed64,6,opt,name=stop_price,json=stopPrice,proto3" json:"stop_price,omitempty"`
+	TimeInForce string                 `protobuf:"bytes,7,opt,name=time_in_force,json=timeInForce,proto3" json:"time_in_force,omitempty"`
+	ReduceOnly  bool                   `protobuf:"varint,8,opt,name=reduce_only,json=reduceOnly,proto3" json:"reduce_only,omitempty"`
+	StopLoss    *TriggerConfig         `protobuf:"bytes,9,opt,name=stop_loss,json=stopLoss,proto3" json:"stop_loss,omitempty"`
+	TakeProfit  *TriggerConfig         `protobuf:"bytes,10,opt,name=take_profit,json=takeProfit,proto3" json:"take_profit,omitempty"`
+	Trailing    *TrailingConfig        `protobuf:"bytes,11,opt,name=trailing,proto3" json:"trailing,omitempty"`
+	// broker.OrderOptions
+	ClientOrderId       string                 `protobuf:"bytes,12,opt,name=client_order_id,json=clientOrderId,proto3" json:"client_order_id,omitempty"`
+	WorkingType         string                 `protobuf:"bytes,13,opt,name=working_type,json=workingType,proto3" json:"working_type,omitempty"`
+	PriceProtect        bool                   `protobuf:"varint,14,opt,name=price_protect,json=priceProtect,proto3" json:"price_protect,omitempty"`
+	ExpireTime          *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+	SelfTradePrevention string                 `protobuf:"bytes,16,opt,name=self_trade_prevention,json=selfTradePrevention,proto3" json:"self_trade_prevention,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *PlaceOrderRequest) Reset() {
+	*x = PlaceOrderRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlaceOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlaceOrderRequest) ProtoMessage() {}
+
+func (x *PlaceOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlaceOrderRequest.ProtoReflect.Descriptor instead.
+func (*PlaceOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PlaceOrderRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetSide() Side {
+	if x != nil {
+		return x.Side
+	}
+	return Side_SIDE_UNSPECIFIED
+}
+
+func (x *PlaceOrderRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetSize() float64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *PlaceOrderRequest) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PlaceOrderRequest) GetStopPrice() float64 {
+	if x != nil {
+		return x.StopPrice
+	}
+	return 0
+}
+
+func (x *PlaceOrderRequest) GetTimeInForce() string {
+	if x != nil {
+		return x.TimeInForce
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetReduceOnly() bool {
+	if x != nil {
+		return x.ReduceOnly
+	}
+	return false
+}
+
+func (x *PlaceOrderRequest) GetStopLoss() *TriggerConfig {
+	if x != nil {
+		return x.StopLoss
+	}
+	return nil
+}
+
+func (x *PlaceOrderRequest) GetTakeProfit() *TriggerConfig {
+	if x != nil {
+		return x.TakeProfit
+	}
+	return nil
+}
+
+func (x *PlaceOrderRequest) GetTrailing() *TrailingConfig {
+	if x != nil {
+		return x.Trailing
+	}
+	return nil
+}
+
+func (x *PlaceOrderRequest) GetClientOrderId() string {
+	if x != nil {
+		return x.ClientOrderId
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetWorkingType() string {
+	if x != nil {
+		return x.WorkingType
+	}
+	return ""
+}
+
+func (x *PlaceOrderRequest) GetPriceProtect() bool {
+	if x != nil {
+		return x.PriceProtect
+	}
+	return false
+}
+
+func (x *PlaceOrderRequest) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+func (x *PlaceOrderRequest) GetSelfTradePrevention() string {
+	if x != nil {
+		return x.SelfTradePrevention
+	}
+	return ""
+}
+
+type GetOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // Empty = all
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrdersRequest) Reset() {
+	*x = GetOrdersRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrdersRequest) ProtoMessage() {}
+
+func (x *GetOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrdersRequest.ProtoReflect.Descriptor instead.
+func (*GetOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetOrdersRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetOrdersRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type GetOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetOrdersResponse) Reset() {
+	*x = GetOrdersResponse{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetOrdersResponse) ProtoMessage() {}
+
+func (x *GetOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetOrdersResponse.ProtoReflect.Descriptor instead.
+func (*GetOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetOrdersResponse) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
+	}
+	return nil
+}
+
+type CancelOrderRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	OrderId       string                 `protobuf:"bytes,2,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderRequest) Reset() {
+	*x = CancelOrderRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderRequest) ProtoMessage() {}
+
+func (x *CancelOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderRequest.ProtoReflect.Descriptor instead.
+func (*CancelOrderRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CancelOrderRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CancelOrderRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+type CancelOrderResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelOrderResponse) Reset() {
+	*x = CancelOrderResponse{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelOrderResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelOrderResponse) ProtoMessage() {}
+
+func (x *CancelOrderResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelOrderResponse.ProtoReflect.Descriptor instead.
+func (*CancelOrderResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{14}
+}
+
+type CancelAllOrdersRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelAllOrdersRequest) Reset() {
+	*x = CancelAllOrdersRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelAllOrdersRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelAllOrdersRequest) ProtoMessage() {}
+
+func (x *CancelAllOrdersRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelAllOrdersRequest.ProtoReflect.Descriptor instead.
+func (*CancelAllOrdersRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CancelAllOrdersRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type CancelAllOrdersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CancelAllOrdersResponse) Reset() {
+	*x = CancelAllOrdersResponse{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CancelAllOrdersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CancelAllOrdersResponse) ProtoMessage() {}
+
+func (x *CancelAllOrdersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CancelAllOrdersResponse.ProtoReflect.Descriptor instead.
+func (*CancelAllOrdersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{16}
+}
+
+type GetCurrentPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCurrentPriceRequest) Reset() {
+	*x = GetCurrentPriceRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCurrentPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCurrentPriceRequest) ProtoMessage() {}
+
+func (x *GetCurrentPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCurrentPriceRequest.ProtoReflect.Descriptor instead.
+func (*GetCurrentPriceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetCurrentPriceRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type Price struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Price) Reset() {
+	*x = Price{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Price) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Price) ProtoMessage() {}
+
+func (x *Price) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Price.ProtoReflect.Descriptor instead.
+func (*Price) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *Price) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *Price) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+type SetLeverageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Side          string                 `protobuf:"bytes,2,opt,name=side,proto3" json:"side,omitempty"`
+	Leverage      int32                  `protobuf:"varint,3,opt,name=leverage,proto3" json:"leverage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLeverageRequest) Reset() {
+	*x = SetLeverageRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLeverageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLeverageRequest) ProtoMessage() {}
+
+func (x *SetLeverageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLeverageRequest.ProtoReflect.Descriptor instead.
+func (*SetLeverageRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *SetLeverageRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *SetLeverageRequest) GetSide() string {
+	if x != nil {
+		return x.Side
+	}
+	return ""
+}
+
+func (x *SetLeverageRequest) GetLeverage() int32 {
+	if x != nil {
+		return x.Leverage
+	}
+	return 0
+}
+
+type SetLeverageResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLeverageResponse) Reset() {
+	*x = SetLeverageResponse{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLeverageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLeverageResponse) ProtoMessage() {}
+
+func (x *SetLeverageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLeverageResponse.ProtoReflect.Descriptor instead.
+func (*SetLeverageResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{20}
+}
+
+type PingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingRequest) Reset() {
+	*x = PingRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingRequest) ProtoMessage() {}
+
+func (x *PingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingRequest.ProtoReflect.Descriptor instead.
+func (*PingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{21}
+}
+
+type PingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PingResponse) Reset() {
+	*x = PingResponse{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PingResponse) ProtoMessage() {}
+
+func (x *PingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PingResponse.ProtoReflect.Descriptor instead.
+func (*PingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{22}
+}
+
+type GetStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStatusRequest) Reset() {
+	*x = GetStatusRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStatusRequest) ProtoMessage() {}
+
+func (x *GetStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{23}
+}
+
+type ExchangeStatus struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	LatencyMicros    int64                  `protobuf:"varint,1,opt,name=latency_micros,json=latencyMicros,proto3" json:"latency_micros,omitempty"` // Round trip of the server's status request
+	ServerTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=server_time,json=serverTime,proto3" json:"server_time,omitempty"`
+	ClockDriftMicros int64                  `protobuf:"varint,3,opt,name=clock_drift_micros,json=clockDriftMicros,proto3" json:"clock_drift_micros,omitempty"` // Exchange clock minus the server's clock
+	Maintenance      bool                   `protobuf:"varint,4,opt,name=maintenance,proto3" json:"maintenance,omitempty"`
+	CheckedAt        *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=checked_at,json=checkedAt,proto3" json:"checked_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *ExchangeStatus) Reset() {
+	*x = ExchangeStatus{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExchangeStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExchangeStatus) ProtoMessage() {}
+
+func (x *ExchangeStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExchangeStatus.ProtoReflect.Descriptor instead.
+func (*ExchangeStatus) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ExchangeStatus) GetLatencyMicros() int64 {
+	if x != nil {
+		return x.LatencyMicros
+	}
+	return 0
+}
+
+func (x *ExchangeStatus) GetServerTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ServerTime
+	}
+	return nil
+}
+
+func (x *ExchangeStatus) GetClockDriftMicros() int64 {
+	if x != nil {
+		return x.ClockDriftMicros
+	}
+	return 0
+}
+
+func (x *ExchangeStatus) GetMaintenance() bool {
+	if x != nil {
+		return x.Maintenance
+	}
+	return false
+}
+
+func (x *ExchangeStatus) GetCheckedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CheckedAt
+	}
+	return nil
+}
+
+type GetInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInfoRequest) Reset() {
+	*x = GetInfoRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoRequest) ProtoMessage() {}
+
+func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{25}
+}
+
+type BrokerInfo struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Name             string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	TrailingStop     bool                   `protobuf:"varint,2,opt,name=trailing_stop,json=trailingStop,proto3" json:"trailing_stop,omitempty"`
+	MultipleTp       bool                   `protobuf:"varint,3,opt,name=multiple_tp,json=multipleTp,proto3" json:"multiple_tp,omitempty"`
+	BracketOrders    bool                   `protobuf:"varint,4,opt,name=bracket_orders,json=bracketOrders,proto3" json:"bracket_orders,omitempty"`
+	MaxLeverage      int32                  `protobuf:"varint,5,opt,name=max_leverage,json=maxLeverage,proto3" json:"max_leverage,omitempty"`
+	ReduceOnlyOrders bool                   `protobuf:"varint,6,opt,name=reduce_only_orders,json=reduceOnlyOrders,proto3" json:"reduce_only_orders,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *BrokerInfo) Reset() {
+	*x = BrokerInfo{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BrokerInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BrokerInfo) ProtoMessage() {}
+
+func (x *BrokerInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BrokerInfo.ProtoReflect.Descriptor instead.
+func (*BrokerInfo) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *BrokerInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BrokerInfo) GetTrailingStop() bool {
+	if x != nil {
+		return x.TrailingStop
+	}
+	return false
+}
+
+func (x *BrokerInfo) GetMultipleTp() bool {
+	if x != nil {
+		return x.MultipleTp
+	}
+	return false
+}
+
+func (x *BrokerInfo) GetBracketOrders() bool {
+	if x != nil {
+		return x.BracketOrders
+	}
+	return false
+}
+
+func (x *BrokerInfo) GetMaxLeverage() int32 {
+	if x != nil {
+		return x.MaxLeverage
+	}
+	return 0
+}
+
+func (x *BrokerInfo) GetReduceOnlyOrders() bool {
+	if x != nil {
+		return x.ReduceOnlyOrders
+	}
+	return false
+}
+
+type StreamTradesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTradesRequest) Reset() {
+	*x = StreamTradesRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTradesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTradesRequest) ProtoMessage() {}
+
+func (x *StreamTradesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTradesRequest.ProtoReflect.Descriptor instead.
+func (*StreamTradesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *StreamTradesRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+type WatchPositionsRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Symbol         string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`                                        // Empty = all
+	IntervalMillis int64                  `protobuf:"varint,2,opt,name=interval_millis,json=intervalMillis,proto3" json:"interval_millis,omitempty"` // Poll interval for brokers without a user stream
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WatchPositionsRequest) Reset() {
+	*x = WatchPositionsRequest{}
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchPositionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchPositionsRequest) ProtoMessage() {}
+
+func (x *WatchPositionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_trading_v1_broker_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchPositionsRequest.ProtoReflect.Descriptor instead.
+func (*WatchPositionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_trading_v1_broker_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WatchPositionsRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *WatchPositionsRequest) GetIntervalMillis() int64 {
+	if x != nil {
+		return x.IntervalMillis
+	}
+	return 0
+}
+
+var File_proto_trading_v1_broker_proto protoreflect.FileDescriptor
+
+const file_proto_trading_v1_broker_proto_rawDesc = "" +
+	"\n" +
+	"\x1dproto/trading/v1/broker.proto\x12\n" +
+	"trading.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xee\x01\n" +
+	"\aBalance\x12\x14\n" +
+	"\x05asset\x18\x01 \x01(\tR\x05asset\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x01R\x05total\x12\x1c\n" +
+	"\tavailable\x18\x03 \x01(\x01R\tavailable\x12\x15\n" +
+	"\x06in_use\x18\x04 \x01(\x01R\x05inUse\x12%\n" +
+	"\x0eunrealized_pnl\x18\x05 \x01(\x01R\runrealizedPnl\x12!\n" +
+	"\frealized_pnl\x18\x06 \x01(\x01R\vrealizedPnl\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xb0\x03\n" +
+	"\bPosition\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12$\n" +
+	"\x04side\x18\x02 \x01(\x0e2\x10.trading.v1.SideR\x04side\x12\x12\n" +
+	"\x04size\x18\x03 \x01(\x01R\x04size\x12\x1f\n" +
+	"\ventry_price\x18\x04 \x01(\x01R\n" +
+	"entryPrice\x12\x1d\n" +
+	"\n" +
+	"mark_price\x18\x05 \x01(\x01R\tmarkPrice\x12+\n" +
+	"\x11liquidation_price\x18\x06 \x01(\x01R\x10liquidationPrice\x12\x1a\n" +
+	"\bleverage\x18\a \x01(\x05R\bleverage\x12%\n" +
+	"\x0eunrealized_pnl\x18\b \x01(\x01R\runrealizedPnl\x12!\n" +
+	"\frealized_pnl\x18\t \x01(\x01R\vrealizedPnl\x12\x16\n" +
+	"\x06margin\x18\n" +
+	" \x01(\x01R\x06margin\x12-\n" +
+	"\x12maintenance_margin\x18\v \x01(\x01R\x11maintenanceMargin\x128\n" +
+	"\ttimestamp\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xf3\x03\n" +
+	"\x05Order\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12&\n" +
+	"\x0fclient_order_id\x18\x02 \x01(\tR\rclientOrderId\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12$\n" +
+	"\x04side\x18\x04 \x01(\x0e2\x10.trading.v1.SideR\x04side\x12\x12\n" +
+	"\x04type\x18\x05 \x01(\tR\x04type\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x12\n" +
+	"\x04size\x18\a \x01(\x01R\x04size\x12\x14\n" +
+	"\x05price\x18\b \x01(\x01R\x05price\x12\x1d\n" +
+	"\n" +
+	"stop_price\x18\t \x01(\x01R\tstopPrice\x12\x1f\n" +
+	"\vfilled_size\x18\n" +
+	" \x01(\x01R\n" +
+	"filledSize\x12#\n" +
+	"\raverage_price\x18\v \x01(\x01R\faveragePrice\x12\x1f\n" +
+	"\vreduce_only\x18\f \x01(\bR\n" +
+	"reduceOnly\x12\"\n" +
+	"\rtime_in_force\x18\r \x01(\tR\vtimeInForce\x129\n" +
+	"\n" +
+	"created_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"x\n" +
+	"\rTriggerConfig\x12#\n" +
+	"\rtrigger_price\x18\x01 \x01(\x01R\ftriggerPrice\x12\x1f\n" +
+	"\vorder_price\x18\x02 \x01(\x01R\n" +
+	"orderPrice\x12!\n" +
+	"\fworking_type\x18\x03 \x01(\tR\vworkingType\"\x83\x01\n" +
+	"\x0eTrailingConfig\x12)\n" +
+	"\x10activation_price\x18\x01 \x01(\x01R\x0factivationPrice\x12#\n" +
+	"\rcallback_rate\x18\x02 \x01(\x01R\fcallbackRate\x12!\n" +
+	"\fworking_type\x18\x03 \x01(\tR\vworkingType\"\xaf\x01\n" +
+	"\x05Trade\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x14\n" +
+	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x01R\x04size\x12$\n" +
+	"\x04side\x18\x05 \x01(\x0e2\x10.trading.v1.SideR\x04side\x12.\n" +
+	"\x04time\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"\x13\n" +
+	"\x11GetBalanceRequest\"S\n" +
+	"\x13GetPositionsRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12$\n" +
+	"\x04side\x18\x02 \x01(\x0e2\x10.trading.v1.SideR\x04side\"J\n" +
+	"\x14GetPositionsResponse\x122\n" +
+	"\tpositions\x18\x01 \x03(\v2\x14.trading.v1.PositionR\tpositions\",\n" +
+	"\x12GetPositionRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\x80\x05\n" +
+	"\x11PlaceOrderRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12$\n" +
+	"\x04side\x18\x02 \x01(\x0e2\x10.trading.v1.SideR\x04side\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x01R\x04size\x12\x14\n" +
+	"\x05price\x18\x05 \x01(\x01R\x05price\x12\x1d\n" +
+	"\n" +
+	"stop_price\x18\x06 \x01(\x01R\tstopPrice\x12\"\n" +
+	"\rtime_in_force\x18\a \x01(\tR\vtimeInForce\x12\x1f\n" +
+	"\vreduce_only\x18\b \x01(\bR\n" +
+	"reduceOnly\x126\n" +
+	"\tstop_loss\x18\t \x01(\v2\x19.trading.v1.TriggerConfigR\bstopLoss\x12:\n" +
+	"\vtake_profit\x18\n" +
+	" \x01(\v2\x19.trading.v1.TriggerConfigR\n" +
+	"takeProfit\x126\n" +
+	"\btrailing\x18\v \x01(\v2\x1a.trading.v1.TrailingConfigR\btrailing\x12&\n" +
+	"\x0fclient_order_id\x18\f \x01(\tR\rclientOrderId\x12!\n" +
+	"\fworking_type\x18\r \x01(\tR\vworkingType\x12#\n" +
+	"\rprice_protect\x18\x0e \x01(\bR\fpriceProtect\x12;\n" +
+	"\vexpire_time\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"expireTime\x122\n" +
+	"\x15self_trade_prevention\x18\x10 \x01(\tR\x13selfTradePrevention\"B\n" +
+	"\x10GetOrdersRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\">\n" +
+	"\x11GetOrdersResponse\x12)\n" +
+	"\x06orders\x18\x01 \x03(\v2\x11.trading.v1.OrderR\x06orders\"G\n" +
+	"\x12CancelOrderRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x19\n" +
+	"\border_id\x18\x02 \x01(\tR\aorderId\"\x15\n" +
+	"\x13CancelOrderResponse\"0\n" +
+	"\x16CancelAllOrdersRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"\x19\n" +
+	"\x17CancelAllOrdersResponse\"0\n" +
+	"\x16GetCurrentPriceRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"5\n" +
+	"\x05Price\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\"\\\n" +
+	"\x12SetLeverageRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04side\x18\x02 \x01(\tR\x04side\x12\x1a\n" +
+	"\bleverage\x18\x03 \x01(\x05R\bleverage\"\x15\n" +
+	"\x13SetLeverageResponse\"\r\n" +
+	"\vPingRequest\"\x0e\n" +
+	"\fPingResponse\"\x12\n" +
+	"\x10GetStatusRequest\"\xff\x01\n" +
+	"\x0eExchangeStatus\x12%\n" +
+	"\x0elatency_micros\x18\x01 \x01(\x03R\rlatencyMicros\x12;\n" +
+	"\vserver_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"serverTime\x12,\n" +
+	"\x12clock_drift_micros\x18\x03 \x01(\x03R\x10clockDriftMicros\x12 \n" +
+	"\vmaintenance\x18\x04 \x01(\bR\vmaintenance\x129\n" +
+	"\n" +
+	"checked_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tcheckedAt\"\x10\n" +
+	"\x0eGetInfoRequest\"\xde\x01\n" +
+	"\n" +
+	"BrokerInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12#\n" +
+	"\rtrailing_stop\x18\x02 \x01(\bR\ftrailingStop\x12\x1f\n" +
+	"\vmultiple_tp\x18\x03 \x01(\bR\n" +
+	"multipleTp\x12%\n" +
+	"\x0ebracket_orders\x18\x04 \x01(\bR\rbracketOrders\x12!\n" +
+	"\fmax_leverage\x18\x05 \x01(\x05R\vmaxLeverage\x12,\n" +
+	"\x12reduce_only_orders\x18\x06 \x01(\bR\x10reduceOnlyOrders\"-\n" +
+	"\x13StreamTradesRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"X\n" +
+	"\x15WatchPositionsRequest\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12'\n" +
+	"\x0finterval_millis\x18\x02 \x01(\x03R\x0eintervalMillis*;\n" +
+	"\x04Side\x12\x14\n" +
+	"\x10SIDE_UNSPECIFIED\x10\x00\x12\r\n" +
+	"\tSIDE_LONG\x10\x01\x12\x0e\n" +
+	"\n" +
+	"SIDE_SHORT\x10\x022\x99\b\n" +
+	"\rBrokerService\x12@\n" +
+	"\n" +
+	"GetBalance\x12\x1d.trading.v1.GetBalanceRequest\x1a\x13.trading.v1.Balance\x12Q\n" +
+	"\fGetPositions\x12\x1f.trading.v1.GetPositionsRequest\x1a .trading.v1.GetPositionsResponse\x12C\n" +
+	"\vGetPosition\x12\x1e.trading.v1.GetPositionRequest\x1a\x14.trading.v1.Position\x12>\n" +
+	"\n" +
+	"PlaceOrder\x12\x1d.trading.v1.PlaceOrderRequest\x1a\x11.trading.v1.Order\x12H\n" +
+	"\tGetOrders\x12\x1c.trading.v1.GetOrdersRequest\x1a\x1d.trading.v1.GetOrdersResponse\x12N\n" +
+	"\vCancelOrder\x12\x1e.trading.v1.CancelOrderRequest\x1a\x1f.trading.v1.CancelOrderResponse\x12Z\n" +
+	"\x0fCancelAllOrders\x12\".trading.v1.CancelAllOrdersRequest\x1a#.trading.v1.CancelAllOrdersResponse\x12H\n" +
+	"\x0fGetCurrentPrice\x12\".trading.v1.GetCurrentPriceRequest\x1a\x11.trading.v1.Price\x12N\n" +
+	"\vSetLeverage\x12\x1e.trading.v1.SetLeverageRequest\x1a\x1f.trading.v1.SetLeverageResponse\x129\n" +
+	"\x04Ping\x12\x17.trading.v1.PingRequest\x1a\x18.trading.v1.PingResponse\x12E\n" +
+	"\tGetStatus\x12\x1c.trading.v1.GetStatusRequest\x1a\x1a.trading.v1.ExchangeStatus\x12=\n" +
+	"\aGetInfo\x12\x1a.trading.v1.GetInfoRequest\x1a\x16.trading.v1.BrokerInfo\x12D\n" +
+	"\fStreamTrades\x12\x1f.trading.v1.StreamTradesRequest\x1a\x11.trading.v1.Trade0\x01\x12W\n" +
+	"\x0eWatchPositions\x12!.trading.v1.WatchPositionsRequest\x1a .trading.v1.GetPositionsResponse0\x01B>Z<github.com/agatticelli/trading-go/proto/trading/v1;tradingv1b\x06proto3"
+
+var (
+	file_proto_trading_v1_broker_proto_rawDescOnce sync.Once
+	file_proto_trading_v1_broker_proto_rawDescData []byte
+)
+
+func file_proto_trading_v1_broker_proto_rawDescGZIP() []byte {
+	file_proto_trading_v1_broker_proto_rawDescOnce.Do(func() {
+		file_proto_trading_v1_broker_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_trading_v1_broker_proto_rawDesc), len(file_proto_trading_v1_broker_proto_rawDesc)))
+	})
+	return file_proto_trading_v1_broker_proto_rawDescData
+}
+
+var file_proto_trading_v1_broker_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_trading_v1_broker_proto_msgTypes = make([]protoimpl.MessageInfo, 29)
+var file_proto_trading_v1_broker_proto_goTypes = []any{
+	(Side)(0),                       // 0: trading.v1.Side
+	(*Balance)(nil),                 // 1: trading.v1.Balance
+	(*Position)(nil),                // 2: trading.v1.Position
+	(*Order)(nil),                   // 3: trading.v1.Order
+	(*TriggerConfig)(nil),           // 4: trading.v1.TriggerConfig
+	(*TrailingConfig)(nil),          // 5: trading.v1.TrailingConfig
+	(*Trade)(nil),                   // 6: trading.v1.Trade
+	(*GetBalanceRequest)(nil),       // 7: trading.v1.GetBalanceRequest
+	(*GetPositionsRequest)(nil),     // 8: trading.v1.GetPositionsRequest
+	(*GetPositionsResponse)(nil),    // 9: trading.v1.GetPositionsResponse
+	(*GetPositionRequest)(nil),      // 10: trading.v1.GetPositionRequest
+	(*PlaceOrderRequest)(nil),       // 11: trading.v1.PlaceOrderRequest
+	(*GetOrdersRequest)(nil),        // 12: trading.v1.GetOrdersRequest
+	(*GetOrdersResponse)(nil),       // 13: trading.v1.GetOrdersResponse
+	(*CancelOrderRequest)(nil),      // 14: trading.v1.CancelOrderRequest
+	(*CancelOrderResponse)(nil),     // 15: trading.v1.CancelOrderResponse
+	(*CancelAllOrdersRequest)(nil),  // 16: trading.v1.CancelAllOrdersRequest
+	(*CancelAllOrdersResponse)(nil), // 17: trading.v1.CancelAllOrdersResponse
+	(*GetCurrentPriceRequest)(nil),  // 18: trading.v1.GetCurrentPriceRequest
+	(*Price)(nil),                   // 19: trading.v1.Price
+	(*SetLeverageRequest)(nil),      // 20: trading.v1.SetLeverageRequest
+	(*SetLeverageResponse)(nil),     // 21: trading.v1.SetLeverageResponse
+	(*PingRequest)(nil),             // 22: trading.v1.PingRequest
+	(*PingResponse)(nil),            // 23: trading.v1.PingResponse
+	(*GetStatusRequest)(nil),        // 24: trading.v1.GetStatusRequest
+	(*ExchangeStatus)(nil),          // 25: trading.v1.ExchangeStatus
+	(*GetInfoRequest)(nil),          // 26: trading.v1.GetInfoRequest
+	(*BrokerInfo)(nil),              // 27: trading.v1.BrokerInfo
+	(*StreamTradesRequest)(nil),     // 28: trading.v1.StreamTradesRequest
+	(*WatchPositionsRequest)(nil),   // 29: trading.v1.WatchPositionsRequest
+	(*timestamppb.Timestamp)(nil),   // 30: google.protobuf.Timestamp
+}
+var file_proto_trading_v1_broker_proto_depIdxs = []int32{
+	30, // 0: trading.v1.Balance.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 1: trading.v1.Position.side:type_name -> trading.v1.Side
+	30, // 2: trading.v1.Position.timestamp:type_name -> google.protobuf.Timestamp
+	0,  // 3: trading.v1.Order.side:type_name -> trading.v1.Side
+	30, // 4: trading.v1.Order.created_at:type_name -> google.protobuf.Timestamp
+	30, // 5: trading.v1.Order.updated_at:type_name -> google.protobuf.Timestamp
+	0,  // 6: trading.v1.Trade.side:type_name -> trading.v1.Side
+	30, // 7: trading.v1.Trade.time:type_name -> google.protobuf.Timestamp
+	0,  // 8: trading.v1.GetPositionsRequest.side:type_name -> trading.v1.Side
+	2,  // 9: trading.v1.GetPositionsResponse.positions:type_name -> trading.v1.Position
+	0,  // 10: trading.v1.PlaceOrderRequest.side:type_name -> trading.v1.Side
+	4,  // 11: trading.v1.PlaceOrderRequest.stop_loss:type_name -> trading.v1.TriggerConfig
+	4,  // 12: trading.v1.PlaceOrderRequest.take_profit:type_name -> trading.v1.TriggerConfig
+	5,  // 13: trading.v1.PlaceOrderRequest.trailing:type_name -> trading.v1.TrailingConfig
+	30, // 14: trading.v1.PlaceOrderRequest.expire_time:type_name -> google.protobuf.Timestamp
+	3,  // 15: trading.v1.GetOrdersResponse.orders:type_name -> trading.v1.Order
+	30, // 16: trading.v1.ExchangeStatus.server_time:type_name -> google.protobuf.Timestamp
+	30, // 17: trading.v1.ExchangeStatus.checked_at:type_name -> google.protobuf.Timestamp
+	7,  // 18: trading.v1.BrokerService.GetBalance:input_type -> trading.v1.GetBalanceRequest
+	8,  // 19: trading.v1.BrokerService.GetPositions:input_type -> trading.v1.GetPositionsRequest
+	10, // 20: trading.v1.BrokerService.GetPosition:input_type -> trading.v1.GetPositionRequest
+	11, // 21: trading.v1.BrokerService.PlaceOrder:input_type -> trading.v1.PlaceOrderRequest
+	12, // 22: trading.v1.BrokerService.GetOrders:input_type -> trading.v1.GetOrdersRequest
+	14, // 23: trading.v1.BrokerService.CancelOrder:input_type -> trading.v1.CancelOrderRequest
+	16, // 24: trading.v1.BrokerService.CancelAllOrders:input_type -> trading.v1.CancelAllOrdersRequest
+	18, // 25: trading.v1.BrokerService.GetCurrentPrice:input_type -> trading.v1.GetCurrentPriceRequest
+	20, // 26: trading.v1.BrokerService.SetLeverage:input_type -> trading.v1.SetLeverageRequest
+	22, // 27: trading.v1.BrokerService.Ping:input_type -> trading.v1.PingRequest
+	24, // 28: trading.v1.BrokerService.GetStatus:input_type -> trading.v1.GetStatusRequest
+	26, // 29: trading.v1.BrokerService.GetInfo:input_type -> trading.v1.GetInfoRequest
+	28, // 30: trading.v1.BrokerService.StreamTrades:input_type -> trading.v1.StreamTradesRequest
+	29, // 31: trading.v1.BrokerService.WatchPositions:input_type -> trading.v1.WatchPositionsRequest
+	1,  // 32: trading.v1.BrokerService.GetBalance:output_type -> trading.v1.Balance
+	9,  // 33: trading.v1.BrokerService.GetPositions:output_type -> trading.v1.GetPositionsResponse
+	2,  // 34: trading.v1.BrokerService.GetPosition:output_type -> trading.v1.Position
+	3,  // 35: trading.v1.BrokerService.PlaceOrder:output_type -> trading.v1.Order
+	13, // 36: trading.v1.BrokerService.GetOrders:output_type -> trading.v1.GetOrdersResponse
+	15, // 37: trading.v1.BrokerService.CancelOrder:output_type -> trading.v1.CancelOrderResponse
+	17, // 38: trading.v1.BrokerService.CancelAllOrders:output_type -> trading.v1.CancelAllOrdersResponse
+	19, // 39: trading.v1.BrokerService.GetCurrentPrice:output_type -> trading.v1.Price
+	21, // 40: trading.v1.BrokerService.SetLeverage:output_type -> trading.v1.SetLeverageResponse
+	23, // 41: trading.v1.BrokerService.Ping:output_type -> trading.v1.PingResponse
+	25, // 42: trading.v1.BrokerService.GetStatus:output_type -> trading.v1.ExchangeStatus
+	27, // 43: trading.v1.BrokerService.GetInfo:output_type -> trading.v1.BrokerInfo
+	6,  // 44: trading.v1.BrokerService.StreamTrades:output_type -> trading.v1.Trade
+	9,  // 45: trading.v1.BrokerService.WatchPositions:output_type -> trading.v1.GetPositionsResponse
+	32, // [32:46] is the sub-list for method output_type
+	18, // [18:32] is the sub-list for method input_type
+	18, // [18:18] is the sub-list for extension type_name
+	18, // [18:18] is the sub-list for extension extendee
+	0,  // [0:18] is the sub-list for field type_name
+}
+
+func init() { file_proto_trading_v1_broker_proto_init() }
+func file_proto_trading_v1_broker_proto_init() {
+	if File_proto_trading_v1_broker_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_trading_v1_broker_proto_rawDesc), len(file_proto_trading_v1_broker_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   29,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_trading_v1_broker_proto_goTypes,
+		DependencyIndexes: file_proto_trading_v1_broker_proto_depIdxs,
+		EnumInfos:         file_proto_trading_v1_broker_proto_enumTypes,
+		MessageInfos:      file_proto_trading_v1_broker_proto_msgTypes,
+	}.Build()
+	File_proto_trading_v1_broker_proto = out.File
+	file_proto_trading_v1_broker_proto_goTypes = nil
+	file_proto_trading_v1_broker_proto_depIdxs = nil
+}
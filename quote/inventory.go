@@ -0,0 +1,209 @@
+package quote
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+	"github.com/agatticelli/trading-go/ordertrack"
+)
+
+// Action is an inventory control taken by the engine
+type Action string
+
+const (
+	ActionPull    Action = "PULL"    // Stopped quoting the side adding to inventory
+	ActionResume  Action = "RESUME"  // Quoting both sides again
+	ActionHedge   Action = "HEDGE"   // Trimmed inventory back to SoftLimit
+	ActionFlatten Action = "FLATTEN" // Pulled all quotes and closed the position
+)
+
+// InventoryEvent reports an inventory control
+type InventoryEvent struct {
+	Symbol    string
+	Action    Action
+	Side      broker.Side // Side pulled, or of the hedge order
+	Inventory float64     // Inventory that triggered the action
+	Size      float64     // Size hedged or flattened
+	Time      time.Time
+	Err       error
+}
+
+// InventoryHandler receives inventory events. Handlers run synchronously
+// on the goroutine running the update.
+type InventoryHandler func(InventoryEvent)
+
+// OnInventory registers a handler for inventory controls
+func (e *Engine) OnInventory(h InventoryHandler) {
+	e.invMu.Lock()
+	defer e.invMu.Unlock()
+	e.handlers = append(e.handlers, h)
+}
+
+// Inventory returns the signed position (positive long) quotes are skewed
+// and limited by
+func (e *Engine) Inventory() float64 {
+	e.invMu.Lock()
+	defer e.invMu.Unlock()
+	return e.inventory
+}
+
+// SetInventory sets the inventory, e.g. at startup
+func (e *Engine) SetInventory(size float64) {
+	e.invMu.Lock()
+	defer e.invMu.Unlock()
+	e.inventory = size
+}
+
+// SyncInventory sets the inventory to the exchange's net position
+func (e *Engine) SyncInventory(ctx context.Context) error {
+	net, err := broker.GetNetPosition(ctx, e.broker, e.config.Symbol)
+	if err != nil {
+		return err
+	}
+	e.SetInventory(net.NetSize)
+	return nil
+}
+
+// Track applies the fills of the engine's quotes to the inventory as their
+// order updates arrive; register it with ordertrack.Tracker.OnChange.
+// Updates of other orders are ignored.
+func (e *Engine) Track(ctx context.Context, ev ordertrack.Event) {
+	e.applyFill(ev.Order)
+}
+
+// watch starts applying a quote's fills, including any it reported when
+// placed
+func (e *Engine) watch(order broker.Order) {
+	e.invMu.Lock()
+	if _, ok := e.fills[order.ID]; !ok {
+		e.fills[order.ID] = 0
+	}
+	e.invMu.Unlock()
+	e.applyFill(order)
+}
+
+func (e *Engine) applyFill(order broker.Order) {
+	e.invMu.Lock()
+	defer e.invMu.Unlock()
+	applied, ok := e.fills[order.ID]
+	if !ok {
+		return
+	}
+	if delta := order.FilledSize - applied; delta > 0 {
+		if order.Side == broker.SideShort {
+			delta = -delta
+		}
+		e.inventory += delta
+		e.fills[order.ID] = order.FilledSize
+	}
+	if final(order.Status) {
+		delete(e.fills, order.ID)
+	}
+}
+
+// resting reports whether the quote orderID has not reached a final status
+func (e *Engine) resting(orderID string) bool {
+	e.invMu.Lock()
+	defer e.invMu.Unlock()
+	_, ok := e.fills[orderID]
+	return ok
+}
+
+// pull returns the side to stop quoting for the inventory, "" for none,
+// and reports transitions. Callers must hold e.mu.
+func (e *Engine) pull(inventory float64) broker.Side {
+	var side broker.Side
+	if e.config.SoftLimit > 0 && math.Abs(inventory) >= e.config.SoftLimit {
+		side = broker.SideLong // Long: stop bidding
+		if inventory < 0 {
+			side = broker.SideShort
+		}
+	}
+	if side == e.pulled {
+		return side
+	}
+
+	e.pulled = side
+	if side != "" {
+		e.log.Warn("inventory past soft limit", logging.KeySymbol, e.config.Symbol, "inventory", inventory, "pulled", side)
+		e.emit(InventoryEvent{Action: ActionPull, Side: side, Inventory: inventory})
+	} else {
+		e.emit(InventoryEvent{Action: ActionResume, Inventory: inventory})
+	}
+	return side
+}
+
+// hedge trims inventory past SoftLimit with a reduce-only market order,
+// then re-reads the position. Callers must hold e.mu.
+func (e *Engine) hedge(ctx context.Context, inventory float64) error {
+	excess := math.Abs(inventory) - e.config.SoftLimit
+	if excess <= 0 {
+		return nil
+	}
+	side := broker.SideShort
+	if inventory < 0 {
+		side = broker.SideLong
+	}
+
+	_, err := e.broker.PlaceOrder(ctx, &broker.OrderRequest{
+		Symbol:     e.config.Symbol,
+		Side:       side,
+		Type:       broker.OrderTypeMarket,
+		Size:       excess,
+		ReduceOnly: true,
+	})
+	if err == nil {
+		e.resync(ctx, inventory-math.Copysign(excess, inventory))
+	} else {
+		err = fmt.Errorf("quote: hedging: %w", err)
+	}
+	e.emit(InventoryEvent{Action: ActionHedge, Side: side, Inventory: inventory, Size: excess, Err: err})
+	return err
+}
+
+// flatten pulls both quotes and closes the position. Callers must hold
+// e.mu.
+func (e *Engine) flatten(ctx context.Context, inventory float64) error {
+	e.log.Error("inventory hit hard limit, flattening", logging.KeySymbol, e.config.Symbol, "inventory", inventory)
+	err := e.cancelAll(ctx)
+	if err == nil {
+		_, err = broker.FlattenPosition(ctx, e.broker, e.config.Symbol)
+	}
+	if err == nil {
+		e.resync(ctx, 0)
+	} else {
+		err = fmt.Errorf("quote: flattening: %w", err)
+	}
+	e.emit(InventoryEvent{Action: ActionFlatten, Inventory: inventory, Size: math.Abs(inventory), Err: err})
+	return err
+}
+
+// resync re-reads the inventory after a market order, assuming expected
+// when the position can't be read
+func (e *Engine) resync(ctx context.Context, expected float64) {
+	if err := e.SyncInventory(ctx); err != nil {
+		e.SetInventory(expected)
+	}
+}
+
+func (e *Engine) emit(ev InventoryEvent) {
+	ev.Symbol, ev.Time = e.config.Symbol, time.Now()
+	e.invMu.Lock()
+	handlers := append([]InventoryHandler(nil), e.handlers...)
+	e.invMu.Unlock()
+	for _, h := range handlers {
+		h(ev)
+	}
+}
+
+func final(status broker.OrderStatus) bool {
+	switch status {
+	case broker.OrderStatusFilled, broker.OrderStatusCanceled, broker.OrderStatusRejected, broker.OrderStatusExpired:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package quote
+
+import (
+	"context"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"github.com/agatticelli/trading-go/ordertrack"
+)
+
+func TestEngine_Track(t *testing.T) {
+	b := brokertest.New()
+	e, _ := New(b, Config{Symbol: "BTC-USDT", Spread: 0.002, Size: 1})
+	ctx := context.Background()
+	e.Update(ctx, features(99.99, 100.01))
+	bid, ask := e.Orders()
+
+	fill := func(o *broker.Order, filled float64, status broker.OrderStatus) {
+		update := *o
+		update.FilledSize, update.Status = filled, status
+		e.Track(ctx, ordertrack.Event{Order: update})
+	}
+	fill(bid, 0.4, broker.OrderStatusPartiallyFilled)
+	fill(bid, 0.4, broker.OrderStatusPartiallyFilled) // Repeated update
+	fill(ask, 1, broker.OrderStatusFilled)
+	fill(&broker.Order{ID: "other", Side: broker.SideLong}, 5, broker.OrderStatusFilled)
+	if got := e.Inventory(); !near(got, -0.6) {
+		t.Errorf("Inventory() = %v, want 0.4 bought - 1 sold", got)
+	}
+}
+
+func TestEngine_SoftLimit(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 100)
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1.5, EntryPrice: 100})
+	e, _ := New(b, Config{Symbol: "BTC-USDT", Spread: 0.002, Size: 1, SoftLimit: 1, HedgeExcess: true, HardLimit: 3})
+	var events []InventoryEvent
+	e.OnInventory(func(ev InventoryEvent) { events = append(events, ev) })
+	ctx := context.Background()
+
+	e.Update(ctx, features(99.99, 100.01))
+	e.SetInventory(1.5)
+	if _, err := e.Update(ctx, features(99.99, 100.01)); err != nil {
+		t.Fatal(err)
+	}
+
+	bid, ask := e.Orders()
+	if bid != nil || ask == nil {
+		t.Errorf("quotes = %+v / %+v, want the bid pulled while long past the soft limit", bid, ask)
+	}
+	placed := b.PlacedOrders()
+	hedge := placed[len(placed)-1]
+	if hedge.Type != broker.OrderTypeMarket || hedge.Side != broker.SideShort || !hedge.ReduceOnly || !near(hedge.Size, 0.5) {
+		t.Errorf("hedge = %+v, want a reduce-only market sell of the 0.5 excess", hedge)
+	}
+	if !near(e.Inventory(), 1) {
+		t.Errorf("Inventory() = %v, want 1 re-read after the hedge", e.Inventory())
+	}
+	if len(events) != 2 || events[0].Action != ActionPull || events[0].Side != broker.SideLong || events[1].Action != ActionHedge {
+		t.Errorf("events = %+v, want pull then hedge", events)
+	}
+
+	// Back inside the band: the bid returns
+	e.SetInventory(0.5)
+	e.Update(ctx, features(99.99, 100.01))
+	if bid, _ := e.Orders(); bid == nil || events[len(events)-1].Action != ActionResume {
+		t.Errorf("bid = %+v, last event %+v; want quoting resumed", bid, events[len(events)-1])
+	}
+}
+
+func TestEngine_HardLimit(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 100)
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 3, EntryPrice: 100})
+	e, _ := New(b, Config{Symbol: "BTC-USDT", Spread: 0.002, Size: 1, HardLimit: 3})
+	var events []InventoryEvent
+	e.OnInventory(func(ev InventoryEvent) { events = append(events, ev) })
+	ctx := context.Background()
+
+	e.Update(ctx, features(99.99, 100.01))
+	e.SetInventory(-3)
+	if _, err := e.Update(ctx, features(99.99, 100.01)); err != nil {
+		t.Fatal(err)
+	}
+
+	if orders, _ := b.GetOrders(ctx, nil); len(orders) != 0 {
+		t.Errorf("%d open orders, want the quotes pulled", len(orders))
+	}
+	if positions, _ := b.GetPositions(ctx, nil); len(positions) != 0 || e.Inventory() != 0 {
+		t.Errorf("positions = %+v, inventory %v; want flat", positions, e.Inventory())
+	}
+	if len(events) != 1 || events[0].Action != ActionFlatten || events[0].Size != 3 {
+		t.Errorf("events = %+v, want one flatten of 3", events)
+	}
+}
+
+func TestNew_Limits(t *testing.T) {
+	if _, err := New(brokertest.New(), Config{Symbol: "BTC-USDT", Spread: 0.002, Size: 1, SoftLimit: 3, HardLimit: 2}); err == nil {
+		t.Error("New() error = nil, want an error for a soft limit above the hard limit")
+	}
+}
+
+func TestEngine_RequoteAfterFill(t *testing.T) {
+	b := brokertest.New()
+	e, _ := New(b, Config{Symbol: "BTC-USDT", Spread: 0.002, Size: 1})
+	ctx := context.Background()
+	e.Update(ctx, features(99.99, 100.01))
+	bid, ask := e.Orders()
+
+	filled := *bid
+	filled.FilledSize, filled.Status = 1, broker.OrderStatusFilled
+	e.Track(ctx, ordertrack.Event{Order: filled})
+
+	// The book didn't move: only the filled side is quoted again
+	if _, err := e.Update(ctx, features(99.99, 100.01)); err != nil {
+		t.Fatal(err)
+	}
+	newBid, newAsk := e.Orders()
+	if newBid == nil || newBid.ID == bid.ID || !near(newBid.Price, bid.Price) {
+		t.Errorf("bid = %+v, want a new quote at %v after the fill", newBid, bid.Price)
+	}
+	if newAsk == nil || newAsk.ID != ask.ID {
+		t.Errorf("ask = %+v, want the resting ask %s kept", newAsk, ask.ID)
+	}
+	if placed := b.PlacedOrders(); len(placed) != 3 {
+		t.Errorf("placed %d orders, want 3", len(placed))
+	}
+}
@@ -0,0 +1,320 @@
+// Package quote keeps two-sided maker quotes around a reference price. On
+// every order book update the Engine computes a bid and an ask from the mid
+// or microprice, the configured spread and a skew by inventory, and moves
+// its resting orders only when the target moved past the re-quote
+// threshold, replacing both sides in one batch request where the exchange
+// supports it. Inventory past a soft limit pulls the side adding to it (and
+// optionally hedges the excess); at a hard limit the engine flattens.
+package quote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+
+	"github.com/agatticelli/trading-go/book"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// Reference is the price quotes are centered on
+type Reference string
+
+const (
+	ReferenceMid        Reference = "MID"        // Midpoint of the best bid and ask
+	ReferenceMicroprice Reference = "MICROPRICE" // Mid weighted by top-of-book sizes
+)
+
+// DefaultRequoteThreshold is the default target move that re-quotes a side
+const DefaultRequoteThreshold = 0.0005
+
+// Config configures an Engine. Spread, skew and thresholds are fractions
+// of the reference price (0.001 = 0.1%).
+type Config struct {
+	Symbol string
+	// Reference price (default ReferenceMid)
+	Reference Reference
+	// Spread between bid and ask, centered on the reference
+	Spread float64
+	// Size quoted on each side
+	Size float64
+	// Skew moves both quotes down by this much per unit of long inventory
+	// (up when short), so the engine leans towards flattening
+	Skew float64
+	// RequoteThreshold is how far a side's target must move from its
+	// resting price before the order is replaced (default 0.05%)
+	RequoteThreshold float64
+	// TickSize rounds bids down and asks up (0 = no rounding)
+	TickSize float64
+	// PostOnly sends quotes as post-only, so they never take liquidity
+	PostOnly bool
+
+	// SoftLimit is the inventory, long or short, past which the engine
+	// stops quoting the side that would add to it (0 = no limit)
+	SoftLimit float64
+	// HedgeExcess trims inventory past SoftLimit back to it with a
+	// reduce-only market order
+	HedgeExcess bool
+	// HardLimit is the inventory at which the engine pulls both quotes and
+	// flattens the position with market orders (0 = no limit)
+	HardLimit float64
+
+	Logger *slog.Logger
+}
+
+// Quote is a target two-sided quote
+type Quote struct {
+	Reference float64
+	Bid       float64
+	Ask       float64
+	Inventory float64 // Inventory the skew was computed from
+}
+
+// Engine keeps quotes on one symbol
+type Engine struct {
+	broker broker.Broker
+	config Config
+	log    *slog.Logger
+
+	// mu serializes updates, which hold it while orders are sent
+	mu     sync.Mutex
+	bid    *broker.Order
+	ask    *broker.Order
+	last   Quote
+	pulled broker.Side // Side pulled by SoftLimit, if any
+
+	// invMu guards the inventory, which fill handlers update while an
+	// update is sending orders
+	invMu     sync.Mutex
+	inventory float64
+	fills     map[string]float64 // Filled size applied per quote order
+	handlers  []InventoryHandler
+}
+
+// New creates a quoting engine placing its orders through b
+func New(b broker.Broker, config Config) (*Engine, error) {
+	if config.Symbol == "" || config.Spread <= 0 || config.Size <= 0 {
+		return nil, errors.New("quote: Symbol, Spread and Size are required")
+	}
+	if config.Reference == "" {
+		config.Reference = ReferenceMid
+	}
+	if config.RequoteThreshold <= 0 {
+		config.RequoteThreshold = DefaultRequoteThreshold
+	}
+	if config.HardLimit > 0 && config.SoftLimit >= config.HardLimit {
+		return nil, errors.New("quote: SoftLimit must be below HardLimit")
+	}
+	return &Engine{
+		broker: b,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "quote"),
+		fills:  make(map[string]float64),
+	}, nil
+}
+
+// Orders returns the resting bid and ask, nil for a side not quoted
+func (e *Engine) Orders() (bid, ask *broker.Order) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return copyOrder(e.bid), copyOrder(e.ask)
+}
+
+// Last returns the latest target quote
+func (e *Engine) Last() Quote {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.last
+}
+
+// Target computes the quote for the book features and inventory. It
+// returns false when the book has no reference price.
+func (e *Engine) Target(f book.Features, inventory float64) (Quote, bool) {
+	ref := f.Mid
+	if e.config.Reference == ReferenceMicroprice && f.Microprice > 0 {
+		ref = f.Microprice
+	}
+	if ref <= 0 {
+		return Quote{}, false
+	}
+
+	center := ref * (1 - inventory*e.config.Skew)
+	half := ref * e.config.Spread / 2
+	q := Quote{Reference: ref, Bid: center - half, Ask: center + half, Inventory: inventory}
+	if tick := e.config.TickSize; tick > 0 {
+		q.Bid = math.Floor(q.Bid/tick+1e-9) * tick
+		q.Ask = math.Ceil(q.Ask/tick-1e-9) * tick
+	}
+
+	// Never cross the book: a quote skewed through the touch joins it
+	if f.BestAsk > 0 && q.Bid >= f.BestAsk {
+		q.Bid = f.BestBid
+	}
+	if f.BestBid > 0 && q.Ask <= f.BestBid {
+		q.Ask = f.BestAsk
+	}
+	return q, true
+}
+
+// Update moves the quotes to the target for the book features. Sides
+// without a resting order are placed; sides whose target moved past the
+// re-quote threshold are replaced together through broker.ReplaceOrders.
+// A side whose order reached a final status (see Track), or filled before
+// it could be replaced, is placed afresh on the next update.
+func (e *Engine) Update(ctx context.Context, f book.Features) (Quote, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	inventory := e.Inventory()
+	if e.config.HardLimit > 0 && math.Abs(inventory) >= e.config.HardLimit {
+		return Quote{Inventory: inventory}, e.flatten(ctx, inventory)
+	}
+
+	q, ok := e.Target(f, inventory)
+	if !ok {
+		return Quote{}, fmt.Errorf("quote: no reference price for %s", e.config.Symbol)
+	}
+	e.last = q
+	for _, side := range []**broker.Order{&e.bid, &e.ask} {
+		if *side != nil && !e.resting((*side).ID) {
+			*side = nil // Filled or canceled: quote it again
+		}
+	}
+
+	var errs []error
+	pull := e.pull(inventory)
+	if pull != "" && e.config.HedgeExcess {
+		if err := e.hedge(ctx, inventory); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	sides := []struct {
+		order *broker.Order
+		req   *broker.OrderRequest
+		set   func(*broker.Order)
+	}{
+		{e.bid, e.request(broker.SideLong, q.Bid), func(o *broker.Order) { e.bid = o }},
+		{e.ask, e.request(broker.SideShort, q.Ask), func(o *broker.Order) { e.ask = o }},
+	}
+
+	var replacements []broker.Replacement
+	var replaced []func(*broker.Order)
+	for _, s := range sides {
+		switch {
+		case s.req.Side == pull:
+			if s.order == nil {
+				continue
+			}
+			if err := e.cancel(ctx, s.order); err != nil {
+				errs = append(errs, fmt.Errorf("quote: pulling %s: %w", s.req.Side, err))
+				continue
+			}
+			s.set(nil)
+		case s.req.Price <= 0:
+			continue
+		case s.order == nil:
+			order, err := e.broker.PlaceOrder(ctx, s.req)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("quote: placing %s: %w", s.req.Side, err))
+				continue
+			}
+			e.watch(*order)
+			s.set(order)
+		case math.Abs(s.req.Price-s.order.Price) >= s.order.Price*e.config.RequoteThreshold:
+			replacements = append(replacements, broker.Replacement{OrderID: s.order.ID, Order: s.req})
+			replaced = append(replaced, s.set)
+		}
+	}
+	if len(replacements) == 0 {
+		return q, errors.Join(errs...)
+	}
+
+	results, err := broker.ReplaceOrders(ctx, e.broker, e.config.Symbol, replacements, broker.ReplaceOptions{})
+	if err != nil {
+		return q, errors.Join(append(errs, fmt.Errorf("quote: replacing: %w", err))...)
+	}
+	for i, result := range results {
+		switch {
+		case result.Err == nil:
+			e.watch(*result.Order)
+			replaced[i](result.Order)
+		case errors.Is(result.Err, broker.ErrOrderNotFound):
+			replaced[i](nil) // Filled before it could be moved
+		default:
+			var replaceErr *broker.ReplaceError
+			if errors.As(result.Err, &replaceErr) {
+				replaced[i](nil) // Canceled, but the new quote failed
+			}
+			errs = append(errs, fmt.Errorf("quote: replacing %s: %w", replacements[i].Order.Side, result.Err))
+		}
+	}
+	return q, errors.Join(errs...)
+}
+
+func (e *Engine) request(side broker.Side, price float64) *broker.OrderRequest {
+	req := &broker.OrderRequest{
+		Symbol: e.config.Symbol,
+		Side:   side,
+		Type:   broker.OrderTypeLimit,
+		Size:   e.config.Size,
+		Price:  price,
+	}
+	if e.config.PostOnly {
+		req.TimeInForce = broker.TimeInForcePostOnly
+	}
+	return req
+}
+
+// Handler returns a book handler that updates the quotes on every depth
+// update, logging failures. Register it with book.Book.OnUpdate.
+func (e *Engine) Handler(ctx context.Context) book.Handler {
+	return func(u book.Update) {
+		if _, err := e.Update(ctx, u.Features); err != nil {
+			e.log.Error("quote update failed", logging.KeySymbol, e.config.Symbol, logging.KeyError, err)
+		}
+	}
+}
+
+// Cancel pulls both quotes. Quotes that can't be canceled stay tracked.
+func (e *Engine) Cancel(ctx context.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.cancelAll(ctx)
+}
+
+// cancelAll pulls both quotes. Callers must hold e.mu.
+func (e *Engine) cancelAll(ctx context.Context) error {
+	var errs []error
+	for _, side := range []**broker.Order{&e.bid, &e.ask} {
+		if *side == nil {
+			continue
+		}
+		if err := e.cancel(ctx, *side); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		*side = nil
+	}
+	return errors.Join(errs...)
+}
+
+// cancel cancels a quote, treating one already gone as canceled
+func (e *Engine) cancel(ctx context.Context, order *broker.Order) error {
+	err := e.broker.CancelOrder(ctx, e.config.Symbol, order.ID)
+	if err != nil && !errors.Is(err, broker.ErrOrderNotFound) {
+		return err
+	}
+	return nil
+}
+
+func copyOrder(o *broker.Order) *broker.Order {
+	if o == nil {
+		return nil
+	}
+	c := *o
+	return &c
+}
@@ -0,0 +1,112 @@
+package quote
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/agatticelli/trading-go/book"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// batchBroker replaces through the fallback path but counts batch requests
+type batchBroker struct {
+	*brokertest.Broker
+	batches int
+}
+
+func (b *batchBroker) ReplaceOrders(ctx context.Context, symbol string, replacements []broker.Replacement) ([]broker.ReplaceResult, error) {
+	b.batches++
+	results := make([]broker.ReplaceResult, len(replacements))
+	for i, r := range replacements {
+		results[i].Order, results[i].Err = broker.ReplaceOrder(ctx, b.Broker, symbol, r.OrderID, r.Order, broker.ReplaceOptions{})
+	}
+	return results, nil
+}
+
+func features(bid, ask float64) book.Features {
+	return book.Features{BestBid: bid, BestAsk: ask, Mid: (bid + ask) / 2, Microprice: bid + (ask-bid)*0.75}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestEngine_Target(t *testing.T) {
+	e, err := New(brokertest.New(), Config{Symbol: "BTC-USDT", Spread: 0.002, Size: 1, Skew: 0.001})
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := features(99.99, 100.01)
+
+	if q, _ := e.Target(f, 0); !near(q.Bid, 99.9) || !near(q.Ask, 100.1) {
+		t.Errorf("Target() = %+v, want 99.9 / 100.1 around the mid", q)
+	}
+	// Long 2: both quotes move down 0.2%
+	if q, _ := e.Target(features(99.5, 100.5), 2); !near(q.Bid, 99.7) || !near(q.Ask, 99.9) {
+		t.Errorf("Target(long) = %+v, want 99.7 / 99.9", q)
+	}
+	// Skewed through the touch: the ask joins the best ask
+	if q, _ := e.Target(f, 2); !near(q.Bid, 99.7) || q.Ask != f.BestAsk {
+		t.Errorf("Target(long, tight book) = %+v, want the ask at the best ask", q)
+	}
+
+	e.config.Reference, e.config.TickSize = ReferenceMicroprice, 0.5
+	if q, _ := e.Target(f, 0); q.Reference != 100.005 || q.Bid != 99.5 || q.Ask != 100.5 {
+		t.Errorf("Target(microprice, ticks) = %+v, want bid 99.5 and ask 100.5 around 100.005", q)
+	}
+	if _, ok := e.Target(book.Features{}, 0); ok {
+		t.Error("Target(empty book) ok = true")
+	}
+}
+
+func TestEngine_Update(t *testing.T) {
+	b := &batchBroker{Broker: brokertest.New()}
+	e, _ := New(b, Config{Symbol: "BTC-USDT", Spread: 0.002, Size: 1, RequoteThreshold: 0.001})
+	ctx := context.Background()
+
+	if _, err := e.Update(ctx, features(99.99, 100.01)); err != nil {
+		t.Fatal(err)
+	}
+	bid, ask := e.Orders()
+	if bid == nil || ask == nil || !near(bid.Price, 99.9) || ask.Side != broker.SideShort {
+		t.Fatalf("quotes = %+v / %+v, want both sides placed", bid, ask)
+	}
+
+	// A move under the threshold leaves the quotes alone
+	e.Update(ctx, features(100.04, 100.06))
+	if len(b.PlacedOrders()) != 2 || b.batches != 0 {
+		t.Errorf("placed %d orders in %d batches, want no re-quote", len(b.PlacedOrders()), b.batches)
+	}
+
+	// Past the threshold both sides move in one batch
+	if _, err := e.Update(ctx, features(100.49, 100.51)); err != nil {
+		t.Fatal(err)
+	}
+	if bid, _ := e.Orders(); b.batches != 1 || !near(bid.Price, 100.3995) {
+		t.Errorf("%d batches, bid %+v; want one batch moving the bid to 100.3995", b.batches, bid)
+	}
+	if orders, _ := b.GetOrders(ctx, nil); len(orders) != 2 {
+		t.Errorf("%d open orders, want the two replaced quotes", len(orders))
+	}
+
+	// The bid fills before it can be moved: dropped, then quoted again
+	bid, _ = e.Orders()
+	b.CancelOrder(ctx, "BTC-USDT", bid.ID)
+	e.Update(ctx, features(100.99, 101.01))
+	if bid, ask := e.Orders(); bid != nil || ask == nil || !near(ask.Price, 101.101) {
+		t.Errorf("quotes after fill = %+v / %+v, want no bid and the ask moved", bid, ask)
+	}
+	e.Update(ctx, features(100.99, 101.01))
+	if bid, _ := e.Orders(); bid == nil || !near(bid.Price, 100.899) {
+		t.Errorf("bid = %+v, want a fresh bid at 100.899", bid)
+	}
+
+	if err := e.Cancel(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if orders, _ := b.GetOrders(ctx, nil); len(orders) != 0 {
+		t.Errorf("%d open orders after Cancel", len(orders))
+	}
+}
@@ -0,0 +1,135 @@
+package recovery
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Role is what an order is for
+type Role string
+
+const (
+	RoleEntry      Role = "ENTRY"
+	RoleStopLoss   Role = "STOP_LOSS"
+	RoleTakeProfit Role = "TAKE_PROFIT"
+	RoleExit       Role = "EXIT" // Other reduce-only orders
+)
+
+// Record journals an order placed by the bot
+type Record struct {
+	Time          time.Time   `json:"time"`
+	ClientOrderID string      `json:"clientOrderId"`
+	OrderID       string      `json:"orderId"`
+	Symbol        string      `json:"symbol"`
+	Side          broker.Side `json:"side"`
+	Role          Role        `json:"role"`
+	Tag           string      `json:"tag,omitempty"`
+}
+
+// Journal keeps order records across restarts
+type Journal interface {
+	Append(r Record) error
+	Load() ([]Record, error)
+}
+
+// FileJournal keeps records in a file, one JSON object per line. Appends are
+// synced to disk so records survive crashes.
+type FileJournal struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileJournal returns a journal writing to path, which is created on the
+// first append
+func NewFileJournal(path string) *FileJournal {
+	return &FileJournal{path: path}
+}
+
+// Append adds a record to the end of the journal
+func (f *FileJournal) Append(r Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads every record in the journal. A missing journal is empty, and a
+// final line cut short by a crash is skipped.
+func (f *FileJournal) Load() ([]Record, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var records []Record
+	scanner := bufio.NewScanner(file)
+	var bad error
+	for line := 1; scanner.Scan(); line++ {
+		if bad != nil {
+			return nil, bad // Only the last line may be damaged
+		}
+		var r Record
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			bad = fmt.Errorf("%s:%d: %w", f.path, line, err)
+			continue
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// positionSide returns the side of the position the order opens or closes:
+// reduce-only orders trade against it
+func (r Record) positionSide() broker.Side {
+	if r.Role == RoleEntry {
+		return r.Side
+	}
+	if r.Side == broker.SideLong {
+		return broker.SideShort
+	}
+	return broker.SideLong
+}
+
+// roleOf classifies an order request: reduce-only stops protect against
+// losses, reduce-only take-profits lock in gains, and the rest are entries
+func roleOf(req *broker.OrderRequest) Role {
+	if !req.ReduceOnly {
+		return RoleEntry
+	}
+	switch req.Type {
+	case broker.OrderTypeStop, broker.OrderTypeStopMarket, broker.OrderTypeTrailingStop:
+		return RoleStopLoss
+	case broker.OrderTypeTakeProfit, broker.OrderTypeTakeProfitMarket:
+		return RoleTakeProfit
+	}
+	return RoleExit
+}
@@ -0,0 +1,272 @@
+// Package recovery adopts live exchange state when a bot restarts. Orders
+// placed through Wrap are journaled by client order ID; on start, Recover
+// loads the open positions and orders, matches them to the journal, hands
+// the stops of journaled positions back to a stops.Manager and reports
+// whatever it could not account for, so a restart doesn't orphan live risk.
+package recovery
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/clock"
+	"github.com/agatticelli/trading-go/logging"
+	"github.com/agatticelli/trading-go/stops"
+)
+
+// DefaultTag tags orders that Config.IDs names
+const DefaultTag = "bot"
+
+// Broker journals the orders placed through it to Config.Journal. Orders
+// need a client order ID to be matched after a restart: those placed
+// without one get an ID from Config.IDs, or are not journaled without it.
+type Broker struct {
+	broker.Broker
+	config Config
+	log    *slog.Logger
+}
+
+// Wrap journals b's orders
+func Wrap(b broker.Broker, config Config) *Broker {
+	if config.Tag == "" {
+		config.Tag = DefaultTag
+	}
+	if config.Clock == nil {
+		config.Clock = clock.Real{}
+	}
+	return &Broker{
+		Broker: b,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "recovery"),
+	}
+}
+
+// PlaceOrder places the order and journals it. A journal failure does not
+// fail the order, which is live by then; it is logged instead.
+func (b *Broker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	clientOrderID := broker.ClientOrderIDOf(ctx, req)
+	if clientOrderID == "" && b.config.IDs != nil {
+		var err error
+		if ctx, err = b.config.IDs.WithTag(ctx, b.config.Tag); err != nil {
+			return nil, err
+		}
+		clientOrderID = broker.OrderOptionsFrom(ctx).ClientOrderID
+	}
+
+	order, err := b.Broker.PlaceOrder(ctx, req)
+	if err != nil {
+		return order, err
+	}
+	if order.ClientOrderID != "" {
+		clientOrderID = order.ClientOrderID
+	}
+	if clientOrderID == "" || b.config.Journal == nil {
+		return order, nil
+	}
+
+	id, _ := broker.ParseClientOrderID(clientOrderID)
+	record := Record{
+		Time:          b.config.Clock.Now(),
+		ClientOrderID: clientOrderID,
+		OrderID:       order.ID,
+		Symbol:        req.Symbol,
+		Side:          req.Side,
+		Role:          roleOf(req),
+		Tag:           id.Tag,
+	}
+	if err := b.config.Journal.Append(record); err != nil {
+		b.log.Error("journaling order failed", logging.KeyOrderID, order.ID, logging.KeyError, err)
+	}
+	return order, nil
+}
+
+// Config configures Wrap and Recover
+type Config struct {
+	Journal Journal
+	// IDs names orders placed through Wrap without a client order ID, and
+	// limits Recover to its namespace: other open orders are another bot's
+	// or manual and left out of the report. Nil treats every order as the
+	// bot's.
+	IDs *broker.ClientOrderIDs
+	// Tag is the order tag of IDs-named orders (default "bot")
+	Tag string
+	// Stops, if set, takes over the stop-loss orders of journaled
+	// positions in Recover. Give it the wrapped broker so moved stops are
+	// journaled too.
+	Stops  *stops.Manager
+	Clock  clock.Clock
+	Logger *slog.Logger
+}
+
+// Match is an open order and its journal record
+type Match struct {
+	Order  *broker.Order
+	Record Record
+}
+
+// Report is the outcome of Recover
+type Report struct {
+	// Orders are the open orders found in the journal
+	Orders []Match
+	// Protected are positions whose stop order was handed to Config.Stops
+	// (or found, without a manager)
+	Protected []*broker.Position
+	// Unprotected are journaled positions without a resting stop order
+	Unprotected []*broker.Position
+	// UnmatchedPositions have no journaled entry for their symbol and side
+	UnmatchedPositions []*broker.Position
+	// UnmatchedOrders are the bot's open orders missing from the journal
+	UnmatchedOrders []*broker.Order
+}
+
+// Clean reports whether every position and order was accounted for and
+// every position is protected
+func (r *Report) Clean() bool {
+	return len(r.Unprotected) == 0 && len(r.UnmatchedPositions) == 0 && len(r.UnmatchedOrders) == 0
+}
+
+// Recover loads b's open positions and orders and matches them to the
+// journal by client order ID. Positions are matched by a journaled entry on
+// their symbol and side; the resting stop-loss order of a matched position
+// is handed to config.Stops. Without a journaled stop, a reduce-only stop
+// order resting on the position's leg is adopted, as the exchange creates
+// the stops attached to entries under its own client order IDs. Nothing is placed or canceled: unmatched and
+// unprotected items are reported for the caller to handle.
+func Recover(ctx context.Context, b broker.Broker, config Config) (*Report, error) {
+	log := logging.Component(logging.OrDiscard(config.Logger), "recovery")
+
+	var records []Record
+	if config.Journal != nil {
+		var err error
+		if records, err = config.Journal.Load(); err != nil {
+			return nil, fmt.Errorf("recovery: loading journal: %w", err)
+		}
+	}
+	byClientID := make(map[string]Record, len(records))
+	entries := make(map[sideKey]bool)
+	for _, r := range records {
+		byClientID[r.ClientOrderID] = r
+		if r.Role == RoleEntry {
+			entries[sideKey{r.Symbol, r.positionSide()}] = true
+		}
+	}
+
+	positions, err := b.GetPositions(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("recovery: loading positions: %w", err)
+	}
+	orders, err := b.GetOrders(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("recovery: loading orders: %w", err)
+	}
+
+	report := &Report{}
+	stopOrders := make(map[sideKey]*broker.Order)
+	attached := make(map[sideKey]*broker.Order)
+	var unjournaled []*broker.Order
+	for _, o := range orders {
+		if !open(o.Status) {
+			continue
+		}
+		r, ok := byClientID[o.ClientOrderID]
+		if !ok || o.ClientOrderID == "" {
+			// Stops attached to an entry (OrderRequest.StopLoss) get their
+			// client order ID from the exchange, so they are never journaled
+			if key := (sideKey{o.Symbol, o.Side}); isStop(o) && attached[key] == nil {
+				attached[key] = o
+			}
+			unjournaled = append(unjournaled, o)
+			continue
+		}
+		if config.IDs != nil && !config.IDs.Owns(o.ClientOrderID) {
+			continue
+		}
+		report.Orders = append(report.Orders, Match{Order: o, Record: r})
+		if r.Role == RoleStopLoss {
+			stopOrders[sideKey{o.Symbol, r.positionSide()}] = o
+		}
+	}
+
+	adopted := make(map[*broker.Order]bool)
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].Symbol != positions[j].Symbol {
+			return positions[i].Symbol < positions[j].Symbol
+		}
+		return positions[i].Side < positions[j].Side
+	})
+	for _, p := range positions {
+		if p.Size == 0 {
+			continue
+		}
+		key := sideKey{p.Symbol, p.Side}
+		if !entries[key] {
+			report.UnmatchedPositions = append(report.UnmatchedPositions, p)
+			continue
+		}
+		stop, ok := stopOrders[key]
+		if !ok {
+			stop, ok = attached[key]
+		}
+		if !ok {
+			report.Unprotected = append(report.Unprotected, p)
+			continue
+		}
+		adopted[stop] = true
+		if config.Stops != nil {
+			config.Stops.Track(p, stop.ID, stop.StopPrice)
+		}
+		report.Protected = append(report.Protected, p)
+	}
+
+	for _, o := range unjournaled {
+		if adopted[o] || (config.IDs != nil && !config.IDs.Owns(o.ClientOrderID)) {
+			continue
+		}
+		report.UnmatchedOrders = append(report.UnmatchedOrders, o)
+	}
+
+	for _, p := range report.UnmatchedPositions {
+		log.Warn("position not in journal", logging.KeySymbol, p.Symbol, "side", p.Side, "size", p.Size)
+	}
+	for _, p := range report.Unprotected {
+		log.Warn("position has no stop order", logging.KeySymbol, p.Symbol, "side", p.Side, "size", p.Size)
+	}
+	for _, o := range report.UnmatchedOrders {
+		log.Warn("open order not in journal", logging.KeySymbol, o.Symbol, logging.KeyOrderID, o.ID, "client_order_id", o.ClientOrderID)
+	}
+	log.Info("recovered exchange state", "orders", len(report.Orders), "protected", len(report.Protected),
+		"unprotected", len(report.Unprotected), "unmatched_positions", len(report.UnmatchedPositions),
+		"unmatched_orders", len(report.UnmatchedOrders))
+	return report, nil
+}
+
+// isStop reports whether o is a resting stop-loss. Its Side is the leg of
+// the position it closes.
+func isStop(o *broker.Order) bool {
+	if !o.ReduceOnly {
+		return false
+	}
+	switch o.Type {
+	case broker.OrderTypeStop, broker.OrderTypeStopMarket, broker.OrderTypeTrailingStop:
+		return true
+	}
+	return false
+}
+
+// sideKey identifies one side of a symbol
+type sideKey struct {
+	symbol string
+	side   broker.Side
+}
+
+// open reports whether an order with status is still resting
+func open(status broker.OrderStatus) bool {
+	switch status {
+	case broker.OrderStatusFilled, broker.OrderStatusCanceled, broker.OrderStatusRejected, broker.OrderStatusExpired:
+		return false
+	}
+	return true
+}
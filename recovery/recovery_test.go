@@ -0,0 +1,116 @@
+package recovery
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"github.com/agatticelli/trading-go/stops"
+)
+
+func TestRecover(t *testing.T) {
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	inner.SetPrice("ETH-USDT", 3000)
+	ids, _ := broker.NewClientOrderIDs("bot1")
+	journal := NewFileJournal(filepath.Join(t.TempDir(), "orders.jsonl"))
+	config := Config{Journal: journal, IDs: ids}
+	ctx := context.Background()
+
+	// Before the restart: a protected BTC long and an unprotected ETH short
+	b := Wrap(inner, config)
+	place := func(req *broker.OrderRequest) *broker.Order {
+		order, err := b.PlaceOrder(ctx, req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return order
+	}
+	place(&broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 0.1})
+	stop := place(&broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeStop, Size: 0.1, StopPrice: 48000, ReduceOnly: true})
+	place(&broker.OrderRequest{Symbol: "ETH-USDT", Side: broker.SideShort, Type: broker.OrderTypeMarket, Size: 1})
+
+	// Placed elsewhere: a manual position, a manual order and one of this
+	// bot's orders the journal lost
+	inner.SetPosition(broker.Position{Symbol: "SOL-USDT", Side: broker.SideLong, Size: 10, EntryPrice: 100})
+	inner.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Status: broker.OrderStatusNew, Size: 1, Price: 40000})
+	lostID, _ := ids.Next("grid")
+	lost := inner.AddOrder(broker.Order{ClientOrderID: lostID, Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeLimit, Status: broker.OrderStatusNew, Size: 1, Price: 45000})
+
+	// After the restart
+	manager := stops.New(Wrap(inner, config), stops.Config{TrailPercent: 0.01})
+	config.Stops = manager
+	report, err := Recover(ctx, inner, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(report.Orders) != 1 || report.Orders[0].Order.ID != stop.ID || report.Orders[0].Record.Role != RoleStopLoss {
+		t.Errorf("orders = %+v, want the journaled stop", report.Orders)
+	}
+	if len(report.Protected) != 1 || report.Protected[0].Symbol != "BTC-USDT" {
+		t.Errorf("protected = %+v, want the BTC long", report.Protected)
+	}
+	if price, id, ok := manager.Stop("BTC-USDT", broker.SideLong); !ok || id != stop.ID || price != 48000 {
+		t.Errorf("managed stop = %v, %q, %v, want the recovered stop", price, id, ok)
+	}
+	if len(report.Unprotected) != 1 || report.Unprotected[0].Symbol != "ETH-USDT" {
+		t.Errorf("unprotected = %+v, want the ETH short", report.Unprotected)
+	}
+	if len(report.UnmatchedPositions) != 1 || report.UnmatchedPositions[0].Symbol != "SOL-USDT" {
+		t.Errorf("unmatched positions = %+v, want the manual SOL long", report.UnmatchedPositions)
+	}
+	if len(report.UnmatchedOrders) != 1 || report.UnmatchedOrders[0].ID != lost.ID {
+		t.Errorf("unmatched orders = %+v, want only the bot's lost order", report.UnmatchedOrders)
+	}
+	if report.Clean() {
+		t.Error("Clean() = true with unmatched items")
+	}
+}
+
+func TestRecover_AttachedStop(t *testing.T) {
+	inner := brokertest.New()
+	inner.SetPrice("ETH-USDT", 3000)
+	ids, _ := broker.NewClientOrderIDs("bot1")
+	config := Config{Journal: NewFileJournal(filepath.Join(t.TempDir(), "orders.jsonl")), IDs: ids}
+	ctx := context.Background()
+
+	b := Wrap(inner, config)
+	if _, err := b.PlaceOrder(ctx, &broker.OrderRequest{Symbol: "ETH-USDT", Side: broker.SideShort, Type: broker.OrderTypeMarket, Size: 1,
+		StopLoss: &broker.StopLossConfig{TriggerPrice: 3200}}); err != nil {
+		t.Fatal(err)
+	}
+	// The exchange creates the attached stop under its own client order ID
+	stop := inner.AddOrder(broker.Order{ClientOrderID: "exchange-1", Symbol: "ETH-USDT", Side: broker.SideShort, Type: broker.OrderTypeStopMarket,
+		Status: broker.OrderStatusNew, Size: 1, StopPrice: 3200, ReduceOnly: true})
+
+	manager := stops.New(b, stops.Config{TrailPercent: 0.01})
+	config.Stops = manager
+	report, err := Recover(ctx, inner, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Protected) != 1 || len(report.Unprotected) != 0 || !report.Clean() {
+		t.Errorf("report = %+v, want the ETH short protected by its attached stop", report)
+	}
+	if price, id, ok := manager.Stop("ETH-USDT", broker.SideShort); !ok || id != stop.ID || price != 3200 {
+		t.Errorf("managed stop = %v, %q, %v, want the attached stop", price, id, ok)
+	}
+}
+
+func TestFileJournal(t *testing.T) {
+	journal := NewFileJournal(filepath.Join(t.TempDir(), "orders.jsonl"))
+	if records, err := journal.Load(); err != nil || len(records) != 0 {
+		t.Fatalf("missing journal = %v, %v, want empty", records, err)
+	}
+	want := Record{ClientOrderID: "grid-000000000001", OrderID: "1", Symbol: "BTC-USDT", Side: broker.SideLong, Role: RoleEntry, Tag: "grid"}
+	if err := journal.Append(want); err != nil {
+		t.Fatal(err)
+	}
+	records, err := journal.Load()
+	if err != nil || len(records) != 1 || records[0] != want {
+		t.Errorf("Load() = %+v, %v, want the appended record", records, err)
+	}
+}
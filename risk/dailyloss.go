@@ -0,0 +1,230 @@
+// Package risk enforces account-level trading limits and estimates
+// portfolio margin usage for pre-trade checks
+package risk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// DefaultInterval is the default time between PnL checks in Run
+const DefaultInterval = 10 * time.Second
+
+// ErrTradingHalted is returned for entry orders while trading is halted
+var ErrTradingHalted = errors.New("trading halted")
+
+// DailyLossConfig configures a DailyLossLimit. At least one of MaxLoss and
+// MaxLossFraction must be set; when both are, the first one hit halts.
+type DailyLossConfig struct {
+	// MaxLoss halts trading when the day's PnL falls to -MaxLoss, in the
+	// balance asset
+	MaxLoss float64
+	// MaxLossFraction halts trading when the day's PnL falls to this
+	// fraction of the equity at the start of the day, e.g. 0.05
+	MaxLossFraction float64
+	// Rollover is when the trading day starts, as an offset from midnight
+	// UTC (default 0)
+	Rollover time.Duration
+	// Interval between checks in Run (default 10s)
+	Interval time.Duration
+	// StatePath persists the day's baseline and the halt so both survive
+	// restarts (empty = in memory only)
+	StatePath string
+}
+
+// DailyState is the persisted state of the current trading day
+type DailyState struct {
+	Day             time.Time `json:"day"`         // When the trading day started
+	StartEquity     float64   `json:"startEquity"` // Balance total, which already includes unrealized PnL
+	StartRealized   float64   `json:"startRealized"`
+	StartUnrealized float64   `json:"startUnrealized"`
+	PnL             float64   `json:"pnl"` // Realized plus unrealized PnL since Day, as of the last check
+	Halted          bool      `json:"halted"`
+	HaltedAt        time.Time `json:"haltedAt"`
+}
+
+// HaltEvent is emitted when the daily loss limit halts trading
+type HaltEvent struct {
+	Day   time.Time
+	PnL   float64
+	Limit float64 // The loss that was reached, as a positive amount
+	Time  time.Time
+}
+
+// HaltHandler receives halt events. Handlers run synchronously on the
+// goroutine that ran the check and should return quickly.
+type HaltHandler func(ctx context.Context, e HaltEvent)
+
+// DailyLossLimit wraps a broker.Broker and halts new entries for the rest
+// of the trading day once the day's PnL reaches the configured loss.
+// Reduce-only orders are still accepted so positions can be closed.
+type DailyLossLimit struct {
+	broker.Broker
+	config DailyLossConfig
+
+	mu       sync.Mutex
+	state    DailyState
+	handlers []HaltHandler
+	now      func() time.Time
+}
+
+// NewDailyLossLimit wraps b, restoring the state saved at config.StatePath
+// if there is one
+func NewDailyLossLimit(b broker.Broker, config DailyLossConfig) (*DailyLossLimit, error) {
+	if config.MaxLoss <= 0 && config.MaxLossFraction <= 0 {
+		return nil, errors.New("risk: MaxLoss or MaxLossFraction is required")
+	}
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+
+	l := &DailyLossLimit{Broker: b, config: config, now: time.Now}
+	if config.StatePath == "" {
+		return l, nil
+	}
+
+	data, err := os.ReadFile(config.StatePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &l.state); err != nil {
+		return nil, fmt.Errorf("risk: reading %s: %w", config.StatePath, err)
+	}
+	return l, nil
+}
+
+// OnHalt registers a handler for halts
+func (l *DailyLossLimit) OnHalt(h HaltHandler) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.handlers = append(l.handlers, h)
+}
+
+// PlaceOrder places the order unless trading is halted. Reduce-only orders
+// are always placed.
+func (l *DailyLossLimit) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	if !order.ReduceOnly {
+		if state := l.State(); state.Halted {
+			return nil, fmt.Errorf("%w: daily PnL %.2f reached the loss limit", ErrTradingHalted, state.PnL)
+		}
+	}
+	return l.Broker.PlaceOrder(ctx, order)
+}
+
+// State returns the current day's state. A halt from a previous day is
+// reported until the next check rolls the day over.
+func (l *DailyLossLimit) State() DailyState {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state
+}
+
+// Run checks PnL at the configured interval until the context is canceled.
+// Check errors are returned immediately.
+func (l *DailyLossLimit) Run(ctx context.Context) error {
+	ticker := time.NewTicker(l.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := l.Check(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches the balance, starts a new day (lifting any halt) when the
+// rollover has passed, and halts trading when the day's PnL reaches the
+// limit
+func (l *DailyLossLimit) Check(ctx context.Context) (DailyState, error) {
+	balance, err := l.Broker.GetBalance(ctx)
+	if err != nil {
+		return DailyState{}, err
+	}
+
+	l.mu.Lock()
+	now := l.now()
+	day := l.dayStart(now)
+	changed := false
+	if !l.state.Day.Equal(day) {
+		l.state = DailyState{
+			Day:             day,
+			StartEquity:     balance.Total,
+			StartRealized:   balance.RealizedPnL,
+			StartUnrealized: balance.UnrealizedPnL,
+		}
+		changed = true
+	}
+
+	l.state.PnL = (balance.RealizedPnL - l.state.StartRealized) + (balance.UnrealizedPnL - l.state.StartUnrealized)
+	var event *HaltEvent
+	if limit := l.limit(); limit > 0 && !l.state.Halted && l.state.PnL <= -limit {
+		l.state.Halted = true
+		l.state.HaltedAt = now
+		event = &HaltEvent{Day: day, PnL: l.state.PnL, Limit: limit, Time: now}
+		changed = true
+	}
+	state := l.state
+	handlers := l.handlers
+	l.mu.Unlock()
+
+	if changed {
+		if err := l.save(state); err != nil {
+			return state, err
+		}
+	}
+	if event != nil {
+		for _, h := range handlers {
+			h(ctx, *event)
+		}
+	}
+	return state, nil
+}
+
+// limit returns the loss that halts trading today. Callers must hold l.mu.
+func (l *DailyLossLimit) limit() float64 {
+	limit := l.config.MaxLoss
+	if f := l.config.MaxLossFraction; f > 0 && l.state.StartEquity > 0 {
+		if byFraction := f * l.state.StartEquity; limit <= 0 || byFraction < limit {
+			limit = byFraction
+		}
+	}
+	return limit
+}
+
+// dayStart returns when the trading day containing t started
+func (l *DailyLossLimit) dayStart(t time.Time) time.Time {
+	rollover := l.config.Rollover
+	return t.UTC().Add(-rollover).Truncate(24 * time.Hour).Add(rollover)
+}
+
+// save writes state to the state file, if configured
+func (l *DailyLossLimit) save(state DailyState) error {
+	if l.config.StatePath == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := l.config.StatePath + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, l.config.StatePath)
+}
@@ -0,0 +1,108 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestDailyLossLimit(t *testing.T) {
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	inner.SetBalance(broker.Balance{Asset: "USDT", Total: 10000, RealizedPnL: 300, UnrealizedPnL: 50})
+
+	path := filepath.Join(t.TempDir(), "daily.json")
+	config := DailyLossConfig{MaxLoss: 500, MaxLossFraction: 0.1, Rollover: 8 * time.Hour, StatePath: path}
+	l, err := NewDailyLossLimit(inner, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 5, 9, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	var events []HaltEvent
+	l.OnHalt(func(_ context.Context, e HaltEvent) { events = append(events, e) })
+	ctx := context.Background()
+
+	state, err := l.Check(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2024, 1, 5, 8, 0, 0, 0, time.UTC); !state.Day.Equal(want) || state.PnL != 0 {
+		t.Fatalf("first check = %+v, want day starting %v with zero PnL", state, want)
+	}
+
+	// Realized -200 and unrealized -250 since the day started: -450 is
+	// within the 500 limit (10% of equity would be 1005)
+	inner.SetBalance(broker.Balance{Asset: "USDT", Total: 9800, RealizedPnL: 100, UnrealizedPnL: -200})
+	if state, _ := l.Check(ctx); state.PnL != -450 || state.Halted {
+		t.Fatalf("check = %+v, want PnL -450 and not halted", state)
+	}
+
+	inner.SetBalance(broker.Balance{Asset: "USDT", Total: 9800, RealizedPnL: 100, UnrealizedPnL: -250})
+	if state, _ := l.Check(ctx); !state.Halted {
+		t.Fatalf("check = %+v, want halted", state)
+	}
+	l.Check(ctx)
+	if len(events) != 1 || events[0].PnL != -500 || events[0].Limit != 500 {
+		t.Errorf("events = %+v, want one halt at -500", events)
+	}
+
+	entry := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 0.1}
+	if _, err := l.PlaceOrder(ctx, entry); !errors.Is(err, ErrTradingHalted) {
+		t.Errorf("entry while halted error = %v, want ErrTradingHalted", err)
+	}
+	exit := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeMarket, Size: 0.1, ReduceOnly: true}
+	if _, err := l.PlaceOrder(ctx, exit); err != nil {
+		t.Errorf("reduce-only order while halted: %v", err)
+	}
+
+	// The halt survives a restart
+	restarted, err := NewDailyLossLimit(inner, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	restarted.now = l.now
+	if _, err := restarted.PlaceOrder(ctx, entry); !errors.Is(err, ErrTradingHalted) {
+		t.Errorf("entry after restart error = %v, want ErrTradingHalted", err)
+	}
+
+	// The next day starts at 08:00 UTC and lifts the halt
+	now = time.Date(2024, 1, 6, 8, 0, 0, 0, time.UTC)
+	if state, _ := restarted.Check(ctx); state.Halted || state.PnL != 0 {
+		t.Errorf("check after rollover = %+v, want a fresh day", state)
+	}
+	if _, err := restarted.PlaceOrder(ctx, entry); err != nil {
+		t.Errorf("entry on the next day: %v", err)
+	}
+}
+
+func TestDailyLossLimit_Fraction(t *testing.T) {
+	inner := brokertest.New()
+	inner.SetBalance(broker.Balance{Asset: "USDT", Total: 1000, UnrealizedPnL: 200})
+
+	l, err := NewDailyLossLimit(inner, DailyLossConfig{MaxLoss: 500, MaxLossFraction: 0.05})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if state, _ := l.Check(ctx); state.StartEquity != 1000 {
+		t.Errorf("start equity = %v, want the 1000 total, which includes the unrealized PnL", state.StartEquity)
+	}
+
+	inner.SetBalance(broker.Balance{Asset: "USDT", Total: 950, UnrealizedPnL: 150})
+	if state, _ := l.Check(ctx); !state.Halted {
+		t.Errorf("check = %+v, want halted at 5%% of 1000 equity", state)
+	}
+}
+
+func TestNewDailyLossLimit_RequiresLimit(t *testing.T) {
+	if _, err := NewDailyLossLimit(brokertest.New(), DailyLossConfig{}); err == nil {
+		t.Error("NewDailyLossLimit() without a limit succeeded")
+	}
+}
@@ -0,0 +1,190 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/events"
+)
+
+const (
+	// DefaultEventWindow is how long before and after an event entries are
+	// restricted by default
+	DefaultEventWindow = 15 * time.Minute
+	// DefaultEventRefresh is how often the schedule is reloaded by default
+	DefaultEventRefresh = time.Hour
+)
+
+// EventAction decides what happens to entries around an event
+type EventAction string
+
+const (
+	EventBlock  EventAction = "BLOCK"  // Reject entries
+	EventReduce EventAction = "REDUCE" // Scale entries down by SizeFactor
+)
+
+// EventGuardConfig configures an EventGuard
+type EventGuardConfig struct {
+	// Provider supplies the scheduled events (required)
+	Provider events.Provider
+	// Before and After bound the window around each event (default 15m
+	// each)
+	Before time.Duration
+	After  time.Duration
+	// MinImpact ignores less important events (default events.ImpactHigh)
+	MinImpact events.Impact
+	// Currencies only restricts entries around events of these currencies
+	// (default: every event)
+	Currencies []string
+	// Action for entries in a window (default EventBlock)
+	Action EventAction
+	// SizeFactor scales entries with EventReduce (default 0.5)
+	SizeFactor float64
+	// Refresh is how often the schedule is reloaded (default 1h)
+	Refresh time.Duration
+}
+
+// EventGuard wraps a broker.Broker and restricts new entries in a window
+// around scheduled events such as rate decisions and inflation releases.
+// Reduce-only orders and every other operation pass through.
+type EventGuard struct {
+	broker.Broker
+	config EventGuardConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	schedule []events.Event
+	loaded   bool
+	loadedAt time.Time
+}
+
+// NewEventGuard wraps b
+func NewEventGuard(b broker.Broker, config EventGuardConfig) (*EventGuard, error) {
+	if config.Provider == nil {
+		return nil, errors.New("risk: Provider is required")
+	}
+	if config.Before <= 0 {
+		config.Before = DefaultEventWindow
+	}
+	if config.After <= 0 {
+		config.After = DefaultEventWindow
+	}
+	if config.MinImpact == 0 {
+		config.MinImpact = events.ImpactHigh
+	}
+	if config.Action == "" {
+		config.Action = EventBlock
+	}
+	if config.SizeFactor <= 0 || config.SizeFactor > 1 {
+		config.SizeFactor = 0.5
+	}
+	if config.Refresh <= 0 {
+		config.Refresh = DefaultEventRefresh
+	}
+	return &EventGuard{Broker: b, config: config, now: time.Now}, nil
+}
+
+// Active returns the event whose window covers the current time, or nil.
+// The schedule is reloaded when older than Refresh; when that fails the
+// previous schedule is used, and the error is returned only if there is
+// none.
+func (g *EventGuard) Active(ctx context.Context) (*events.Event, error) {
+	now := g.now()
+	schedule, err := g.load(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range schedule {
+		if !now.Before(e.Time.Add(-g.config.Before)) && now.Before(e.Time.Add(g.config.After)) {
+			return &e, nil
+		}
+	}
+	return nil, nil
+}
+
+// Upcoming returns the relevant events in the loaded schedule that haven't
+// left their window yet
+func (g *EventGuard) Upcoming(ctx context.Context) ([]events.Event, error) {
+	now := g.now()
+	schedule, err := g.load(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	var upcoming []events.Event
+	for _, e := range schedule {
+		if now.Before(e.Time.Add(g.config.After)) {
+			upcoming = append(upcoming, e)
+		}
+	}
+	return upcoming, nil
+}
+
+// PlaceOrder places reduce-only orders and orders outside event windows.
+// Inside a window, entries fail with an *EntryBlockedError (EventBlock) or
+// are placed with their size scaled by SizeFactor and rounded down to the
+// lot (EventReduce), failing like EventBlock when nothing is left.
+func (g *EventGuard) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	if order.ReduceOnly {
+		return g.Broker.PlaceOrder(ctx, order)
+	}
+	event, err := g.Active(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("risk: loading event schedule: %w", err)
+	}
+	if event == nil {
+		return g.Broker.PlaceOrder(ctx, order)
+	}
+
+	blocked := &EntryBlockedError{
+		Symbol: order.Symbol,
+		Side:   order.Side,
+		Size:   order.Size,
+		Reason: fmt.Sprintf("%s %s at %s", event.Currency, event.Title, event.Time.UTC().Format("15:04 MST")),
+	}
+	if g.config.Action != EventReduce {
+		return nil, blocked
+	}
+
+	spec, err := broker.GetContractSpec(ctx, g.Broker, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	reduced := *order
+	if reduced.Size = spec.RoundQuantity(order.Size * g.config.SizeFactor); reduced.Size <= 0 {
+		return nil, blocked
+	}
+	return g.Broker.PlaceOrder(ctx, &reduced)
+}
+
+// load returns the relevant events whose windows can overlap the time until
+// the next reload, reloading the schedule when it is older than Refresh
+func (g *EventGuard) load(ctx context.Context, now time.Time) ([]events.Event, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.loaded && now.Sub(g.loadedAt) < g.config.Refresh {
+		return g.schedule, nil
+	}
+
+	loaded, err := g.config.Provider.Events(ctx, now.Add(-g.config.After), now.Add(g.config.Refresh+g.config.Before))
+	if err != nil {
+		if !g.loaded {
+			return nil, err
+		}
+		g.loadedAt = now // Retry on the next refresh, keeping the old schedule
+		return g.schedule, nil
+	}
+
+	var schedule []events.Event // Fresh, as callers read the previous one unlocked
+	for _, e := range loaded {
+		if e.Impact >= g.config.MinImpact && (len(g.config.Currencies) == 0 || slices.Contains(g.config.Currencies, e.Currency)) {
+			schedule = append(schedule, e)
+		}
+	}
+	g.schedule, g.loaded, g.loadedAt = schedule, true, now
+	return g.schedule, nil
+}
@@ -0,0 +1,124 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"github.com/agatticelli/trading-go/events"
+)
+
+// countingProvider counts schedule loads and can fail them
+type countingProvider struct {
+	events.Static
+	loads int
+	err   error
+}
+
+func (p *countingProvider) Events(ctx context.Context, from, to time.Time) ([]events.Event, error) {
+	p.loads++
+	if p.err != nil {
+		return nil, p.err
+	}
+	return p.Static.Events(ctx, from, to)
+}
+
+func TestEventGuard_Windows(t *testing.T) {
+	release := time.Date(2026, 3, 11, 12, 30, 0, 0, time.UTC)
+	provider := &countingProvider{Static: events.Static{
+		{Title: "CPI", Currency: "USD", Impact: events.ImpactHigh, Time: release},
+		{Title: "Retail Sales", Currency: "USD", Impact: events.ImpactMedium, Time: release.Add(2 * time.Hour)},
+		{Title: "Rate Decision", Currency: "JPY", Impact: events.ImpactHigh, Time: release.Add(4 * time.Hour)},
+	}}
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	g, err := NewEventGuard(inner, EventGuardConfig{Provider: provider, Currencies: []string{"USD"}, Refresh: 6 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		at          time.Duration // From the CPI release
+		reduceOnly  bool
+		wantBlocked bool
+	}{
+		{-16 * time.Minute, false, false},
+		{-15 * time.Minute, false, true},
+		{14 * time.Minute, false, true},
+		{14 * time.Minute, true, false}, // Exits always pass
+		{15 * time.Minute, false, false},
+		{2 * time.Hour, false, false}, // Medium impact
+		{4 * time.Hour, false, false}, // Other currency
+	}
+	for _, tt := range tests {
+		g.now = func() time.Time { return release.Add(tt.at) }
+		_, err := g.PlaceOrder(ctx, order(broker.SideLong, 0.1, tt.reduceOnly))
+		var blocked *EntryBlockedError
+		if errors.As(err, &blocked) != tt.wantBlocked {
+			t.Errorf("at %v reduce-only %v: error = %v, want blocked %v", tt.at, tt.reduceOnly, err, tt.wantBlocked)
+		}
+		if tt.wantBlocked && blocked.Reason != "USD CPI at 12:30 UTC" {
+			t.Errorf("reason = %q", blocked.Reason)
+		}
+	}
+	if provider.loads != 1 {
+		t.Errorf("schedule loaded %d times, want once per Refresh", provider.loads)
+	}
+}
+
+func TestEventGuard_Reduce(t *testing.T) {
+	release := time.Date(2026, 3, 18, 18, 0, 0, 0, time.UTC)
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	g, _ := NewEventGuard(inner, EventGuardConfig{
+		Provider:   events.Static{{Title: "FOMC", Currency: "USD", Impact: events.ImpactHigh, Time: release}},
+		Action:     EventReduce,
+		SizeFactor: 0.25,
+	})
+	g.now = func() time.Time { return release }
+	ctx := context.Background()
+
+	if _, err := g.PlaceOrder(ctx, order(broker.SideLong, 0.4, false)); err != nil {
+		t.Fatal(err)
+	}
+	if placed := inner.PlacedOrders(); len(placed) != 1 || placed[0].Size != 0.1 {
+		t.Errorf("placed %+v, want the entry at a quarter size", placed)
+	}
+}
+
+func TestEventGuard_ProviderFailure(t *testing.T) {
+	release := time.Date(2026, 3, 11, 12, 30, 0, 0, time.UTC)
+	provider := &countingProvider{err: errors.New("calendar unavailable")}
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	g, _ := NewEventGuard(inner, EventGuardConfig{Provider: provider})
+	ctx := context.Background()
+	g.now = func() time.Time { return release }
+
+	// Without a schedule entries fail closed
+	if _, err := g.PlaceOrder(ctx, order(broker.SideLong, 0.1, false)); err == nil {
+		t.Fatal("entry placed without a schedule")
+	}
+
+	// Once loaded, a failed refresh keeps the previous schedule
+	provider.err = nil
+	provider.Static = events.Static{{Title: "CPI", Currency: "USD", Impact: events.ImpactHigh, Time: release.Add(60 * time.Minute)}}
+	if _, err := g.PlaceOrder(ctx, order(broker.SideLong, 0.1, false)); err != nil {
+		t.Fatal(err)
+	}
+	provider.err = errors.New("calendar unavailable")
+	g.now = func() time.Time { return release.Add(65 * time.Minute) }
+	if _, err := g.PlaceOrder(ctx, order(broker.SideLong, 0.1, false)); !errors.Is(err, ErrEntryBlocked) {
+		t.Errorf("entry during the window after a failed refresh: error = %v, want blocked", err)
+	}
+}
+
+func TestNewEventGuard_RequiresProvider(t *testing.T) {
+	if _, err := NewEventGuard(brokertest.New(), EventGuardConfig{}); err == nil {
+		t.Error("NewEventGuard() without a provider succeeded")
+	}
+}
@@ -0,0 +1,219 @@
+package risk
+
+import (
+	"math"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// MarginParams are the margin rates of a symbol, as fractions of notional
+type MarginParams struct {
+	// InitialRate is the margin needed to hold the position (default
+	// 1/leverage of the position)
+	InitialRate float64
+	// MaintenanceRate is the margin below which the position is liquidated
+	MaintenanceRate float64
+}
+
+// Correlations holds pairwise return correlations between symbols. Pairs
+// are unordered: {"BTC-USDT", "ETH-USDT"} also covers ETH/BTC.
+type Correlations map[[2]string]float64
+
+// Get returns the correlation of a and b: 1 for a symbol with itself, the
+// configured value for a known pair and fallback otherwise
+func (c Correlations) Get(a, b string, fallback float64) float64 {
+	if a == b {
+		return 1
+	}
+	if rho, ok := c[[2]string{a, b}]; ok {
+		return rho
+	}
+	if rho, ok := c[[2]string{b, a}]; ok {
+		return rho
+	}
+	return fallback
+}
+
+// PortfolioConfig configures an Estimator
+type PortfolioConfig struct {
+	// Params holds margin rates per symbol; symbols without an entry use
+	// Default
+	Params  map[string]MarginParams
+	Default MarginParams
+	// Correlations between symbols; pairs without an entry use
+	// DefaultCorrelation, or 1 (fully correlated, the worst case for
+	// same-direction exposure) when it is nil
+	Correlations       Correlations
+	DefaultCorrelation *float64
+	// Shocks are the price moves to estimate losses for, as fractions,
+	// e.g. 0.05 for ±5% (default 0.05 and 0.10)
+	Shocks []float64
+}
+
+// ShockLoss is the estimated portfolio loss for a price move of Shock in the
+// adverse direction of every position, reduced by the correlations between
+// symbols
+type ShockLoss struct {
+	Shock float64
+	Loss  float64
+}
+
+// PortfolioEstimate summarizes margin usage and stress losses of a set of
+// positions
+type PortfolioEstimate struct {
+	Equity            float64 // Balance total, which includes unrealized PnL
+	InitialMargin     float64
+	MaintenanceMargin float64
+	GrossNotional     float64 // Sum of all legs at mark price
+	NetNotional       float64 // Sum of per-symbol net exposure, signed (long positive)
+	Shocks            []ShockLoss
+	WorstLoss         float64 // Largest shock loss
+	// FreeMargin is equity not committed as initial margin
+	FreeMargin float64
+	// Headroom is equity left above maintenance margin after the worst
+	// shock; negative means the worst shock would liquidate the account
+	Headroom float64
+}
+
+// Estimator estimates portfolio margin and stress losses
+type Estimator struct {
+	config PortfolioConfig
+}
+
+// NewEstimator creates an Estimator
+func NewEstimator(config PortfolioConfig) *Estimator {
+	if len(config.Shocks) == 0 {
+		config.Shocks = []float64{0.05, 0.10}
+	}
+	return &Estimator{config: config}
+}
+
+// Estimate evaluates positions against balance. Hedge-mode legs of a symbol
+// are netted for stress losses but both count toward margin. The balance
+// must be in the positions' quote currency: combine accounts settled in
+// other assets with fx.Converter.Total.
+func (e *Estimator) Estimate(balance *broker.Balance, positions []*broker.Position) PortfolioEstimate {
+	est := PortfolioEstimate{Equity: balance.Total}
+
+	for _, p := range positions {
+		notional := p.Size * p.MarkPrice
+		params := e.params(p.Symbol)
+		est.GrossNotional += notional
+
+		switch {
+		case p.Margin > 0:
+			est.InitialMargin += p.Margin
+		case params.InitialRate > 0:
+			est.InitialMargin += notional * params.InitialRate
+		case p.Leverage > 0:
+			est.InitialMargin += notional / float64(p.Leverage)
+		}
+		if p.MaintenanceMargin > 0 {
+			est.MaintenanceMargin += p.MaintenanceMargin
+		} else {
+			est.MaintenanceMargin += notional * params.MaintenanceRate
+		}
+	}
+
+	nets := broker.NetAll(positions)
+	exposure := make([]float64, len(nets))
+	for i, net := range nets {
+		exposure[i] = net.NetSize * markPrice(net)
+		est.NetNotional += exposure[i]
+	}
+
+	for _, shock := range e.config.Shocks {
+		loss := e.shockLoss(nets, exposure, math.Abs(shock))
+		est.Shocks = append(est.Shocks, ShockLoss{Shock: shock, Loss: loss})
+		est.WorstLoss = max(est.WorstLoss, loss)
+	}
+
+	est.FreeMargin = est.Equity - est.InitialMargin
+	est.Headroom = est.Equity - est.MaintenanceMargin - est.WorstLoss
+	return est
+}
+
+// EstimateOrder evaluates the portfolio as if req were filled at price, for
+// pre-trade checks
+func (e *Estimator) EstimateOrder(balance *broker.Balance, positions []*broker.Position, req *broker.OrderRequest, price float64) PortfolioEstimate {
+	return e.Estimate(balance, withOrder(positions, req, price))
+}
+
+// shockLoss aggregates the loss of each symbol's net exposure under a move
+// of shock as sqrt(Σ Σ ρij li lj). Opposite exposures in positively
+// correlated symbols offset each other.
+func (e *Estimator) shockLoss(nets []broker.NetPosition, exposure []float64, shock float64) float64 {
+	fallback := 1.0
+	if e.config.DefaultCorrelation != nil {
+		fallback = *e.config.DefaultCorrelation
+	}
+	var variance float64
+	for i := range nets {
+		for j := range nets {
+			rho := e.config.Correlations.Get(nets[i].Symbol, nets[j].Symbol, fallback)
+			variance += rho * exposure[i] * shock * exposure[j] * shock
+		}
+	}
+	return math.Sqrt(max(variance, 0))
+}
+
+// params returns the margin rates of a symbol
+func (e *Estimator) params(symbol string) MarginParams {
+	if p, ok := e.config.Params[symbol]; ok {
+		return p
+	}
+	return e.config.Default
+}
+
+// markPrice returns the mark price of either leg
+func markPrice(net broker.NetPosition) float64 {
+	for _, leg := range []*broker.Position{net.Long, net.Short} {
+		if leg != nil && leg.MarkPrice > 0 {
+			return leg.MarkPrice
+		}
+	}
+	return 0
+}
+
+// withOrder returns positions with req applied as a fill at price. Reduce-
+// only orders shrink the opposite leg; others grow the leg of their side.
+func withOrder(positions []*broker.Position, req *broker.OrderRequest, price float64) []*broker.Position {
+	side := req.Side
+	if req.ReduceOnly {
+		side = broker.SideLong
+		if req.Side == broker.SideLong {
+			side = broker.SideShort
+		}
+	}
+
+	result := make([]*broker.Position, 0, len(positions)+1)
+	found := false
+	for _, p := range positions {
+		if p.Symbol != req.Symbol || p.Side != side {
+			result = append(result, p)
+			continue
+		}
+		found = true
+
+		leg := *p
+		leg.MarkPrice = price
+		if req.ReduceOnly {
+			leg.Size = max(leg.Size-req.Size, 0)
+		} else {
+			leg.Size += req.Size
+		}
+		// Reported margins scale with the leg
+		if p.Size > 0 {
+			leg.Margin *= leg.Size / p.Size
+			leg.MaintenanceMargin *= leg.Size / p.Size
+		}
+		if leg.Size > 0 {
+			result = append(result, &leg)
+		}
+	}
+
+	if !found && !req.ReduceOnly {
+		result = append(result, &broker.Position{Symbol: req.Symbol, Side: side, Size: req.Size, EntryPrice: price, MarkPrice: price})
+	}
+	return result
+}
@@ -0,0 +1,99 @@
+package risk
+
+import (
+	"math"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+func approx(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestEstimator_Estimate(t *testing.T) {
+	e := NewEstimator(PortfolioConfig{
+		Params: map[string]MarginParams{
+			"BTC-USDT": {InitialRate: 0.1, MaintenanceRate: 0.005},
+		},
+		Default:      MarginParams{MaintenanceRate: 0.01},
+		Correlations: Correlations{{"BTC-USDT", "ETH-USDT"}: 0.8},
+		Shocks:       []float64{0.05, -0.10},
+	})
+	balance := &broker.Balance{Total: 10000, UnrealizedPnL: 500}
+	positions := []*broker.Position{
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, MarkPrice: 50000},
+		{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 0.6, MarkPrice: 50000},
+		{Symbol: "ETH-USDT", Side: broker.SideShort, Size: 5, MarkPrice: 3000, Leverage: 10},
+	}
+
+	est := e.Estimate(balance, positions)
+
+	// BTC 80000 gross at 10%, ETH 15000 at 1/10
+	if !approx(est.InitialMargin, 9500) || !approx(est.MaintenanceMargin, 400+150) {
+		t.Errorf("margin = %v initial, %v maintenance, want 9500, 550", est.InitialMargin, est.MaintenanceMargin)
+	}
+	if !approx(est.GrossNotional, 95000) || !approx(est.NetNotional, 20000-15000) {
+		t.Errorf("notional = %v gross, %v net, want 95000, 5000", est.GrossNotional, est.NetNotional)
+	}
+
+	// Net BTC +20000, ETH -15000, ρ = 0.8:
+	// sqrt(20000² + 15000² - 2·0.8·20000·15000) = sqrt(145e6) per unit of shock
+	base := math.Sqrt(145e6)
+	if len(est.Shocks) != 2 || !approx(est.Shocks[0].Loss, 0.05*base) || !approx(est.Shocks[1].Loss, 0.10*base) {
+		t.Errorf("shocks = %+v, want losses %v and %v", est.Shocks, 0.05*base, 0.10*base)
+	}
+	if !approx(est.WorstLoss, 0.10*base) {
+		t.Errorf("WorstLoss = %v, want %v", est.WorstLoss, 0.10*base)
+	}
+	if !approx(est.FreeMargin, 500) || !approx(est.Headroom, 10000-550-0.10*base) {
+		t.Errorf("free margin %v, headroom %v", est.FreeMargin, est.Headroom)
+	}
+}
+
+func TestEstimator_DefaultCorrelation(t *testing.T) {
+	balance := &broker.Balance{Total: 10000}
+	positions := []*broker.Position{
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.3, MarkPrice: 50000},
+		{Symbol: "SOL-USDT", Side: broker.SideLong, Size: 40, MarkPrice: 100},
+	}
+
+	// Unlisted pairs move together unless configured otherwise
+	worst := NewEstimator(PortfolioConfig{Shocks: []float64{0.1}}).Estimate(balance, positions)
+	if !approx(worst.WorstLoss, 1500+400) {
+		t.Errorf("WorstLoss = %v, want 1900 with the symbols fully correlated", worst.WorstLoss)
+	}
+
+	independent := 0.0
+	est := NewEstimator(PortfolioConfig{Shocks: []float64{0.1}, DefaultCorrelation: &independent}).Estimate(balance, positions)
+	if !approx(est.WorstLoss, math.Sqrt(1500*1500+400*400)) {
+		t.Errorf("WorstLoss = %v, want %v with the symbols independent", est.WorstLoss, math.Sqrt(1500*1500+400*400))
+	}
+}
+
+func TestEstimator_EstimateOrder(t *testing.T) {
+	e := NewEstimator(PortfolioConfig{Default: MarginParams{InitialRate: 0.1}, Shocks: []float64{0.1}})
+	balance := &broker.Balance{Total: 1000}
+	positions := []*broker.Position{
+		{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.1, MarkPrice: 50000, Margin: 500},
+	}
+
+	more := e.EstimateOrder(balance, positions, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.1}, 50000)
+	if !approx(more.InitialMargin, 1000) || !approx(more.WorstLoss, 1000) {
+		t.Errorf("adding to the leg: margin %v, worst loss %v, want 1000, 1000", more.InitialMargin, more.WorstLoss)
+	}
+
+	closed := e.EstimateOrder(balance, positions, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 0.1, ReduceOnly: true}, 50000)
+	if closed.InitialMargin != 0 || closed.WorstLoss != 0 || closed.FreeMargin != 1000 {
+		t.Errorf("closing the leg = %+v, want no margin or risk left", closed)
+	}
+
+	hedge := e.EstimateOrder(balance, positions, &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 0.1}, 50000)
+	if !approx(hedge.InitialMargin, 1000) || hedge.WorstLoss != 0 {
+		t.Errorf("hedging the leg: margin %v, worst loss %v, want 1000, 0", hedge.InitialMargin, hedge.WorstLoss)
+	}
+
+	if len(positions) != 1 || positions[0].Size != 0.1 || positions[0].Margin != 500 {
+		t.Error("EstimateOrder must not modify the positions")
+	}
+}
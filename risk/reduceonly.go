@@ -0,0 +1,146 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// ErrEntryBlocked is matched by errors.Is for orders rejected by an
+// enforced ReduceOnly
+var ErrEntryBlocked = errors.New("entry blocked")
+
+// EntryBlockedError reports an order that would open or increase a
+// position while only reductions are allowed
+type EntryBlockedError struct {
+	Symbol string
+	Side   broker.Side
+	Size   float64
+	Reason string // Why the account is exit-only, as given to Enforce
+}
+
+func (e *EntryBlockedError) Error() string {
+	msg := fmt.Sprintf("%s %v %s order: %v", e.Side, e.Size, e.Symbol, ErrEntryBlocked)
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	return msg
+}
+
+func (e *EntryBlockedError) Unwrap() error {
+	return ErrEntryBlocked
+}
+
+// ReduceOnlyMode decides what happens to orders not marked reduce-only
+type ReduceOnlyMode string
+
+const (
+	// ReduceOnlyReject rejects every order not marked reduce-only
+	ReduceOnlyReject ReduceOnlyMode = "REJECT"
+	// ReduceOnlyConvert marks an order reduce-only when the account holds
+	// an opposite leg at least as large, and rejects it otherwise
+	ReduceOnlyConvert ReduceOnlyMode = "CONVERT"
+)
+
+// ReduceOnlyConfig configures a ReduceOnly
+type ReduceOnlyConfig struct {
+	// Mode for orders not marked reduce-only (default ReduceOnlyReject)
+	Mode ReduceOnlyMode
+	// Reason is reported in blocked-entry errors, e.g. "wind-down"
+	Reason string
+	// Lifted starts with enforcement off, until Enforce is called
+	Lifted bool
+}
+
+// ReduceOnlyState describes whether a ReduceOnly is enforced
+type ReduceOnlyState struct {
+	Enforced bool
+	Reason   string
+	Since    time.Time
+}
+
+// ReduceOnly wraps a broker.Broker and only lets orders through that reduce
+// positions, for wind-down periods or accounts designated exit-only.
+// Cancellations and every other operation pass through.
+type ReduceOnly struct {
+	broker.Broker
+	config ReduceOnlyConfig
+
+	mu    sync.Mutex
+	state ReduceOnlyState
+	now   func() time.Time
+}
+
+// NewReduceOnly wraps b, enforcing reduce-only unless config.Lifted
+func NewReduceOnly(b broker.Broker, config ReduceOnlyConfig) *ReduceOnly {
+	if config.Mode == "" {
+		config.Mode = ReduceOnlyReject
+	}
+	r := &ReduceOnly{Broker: b, config: config, now: time.Now}
+	if !config.Lifted {
+		r.state = ReduceOnlyState{Enforced: true, Reason: config.Reason, Since: r.now()}
+	}
+	return r
+}
+
+// Enforce starts blocking entries. An empty reason keeps the configured one.
+func (r *ReduceOnly) Enforce(reason string) {
+	if reason == "" {
+		reason = r.config.Reason
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.state.Enforced {
+		r.state.Since = r.now()
+	}
+	r.state.Enforced, r.state.Reason = true, reason
+}
+
+// Lift lets entries through again
+func (r *ReduceOnly) Lift() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state = ReduceOnlyState{}
+}
+
+// State returns whether reduce-only is enforced, and why
+func (r *ReduceOnly) State() ReduceOnlyState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.state
+}
+
+// PlaceOrder places reduce-only orders. While enforced, other orders fail
+// with an *EntryBlockedError, or in ReduceOnlyConvert mode are placed as
+// reduce-only when they only close existing exposure.
+func (r *ReduceOnly) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	state := r.State()
+	if !state.Enforced || order.ReduceOnly {
+		return r.Broker.PlaceOrder(ctx, order)
+	}
+
+	blocked := &EntryBlockedError{Symbol: order.Symbol, Side: order.Side, Size: order.Size, Reason: state.Reason}
+	if r.config.Mode != ReduceOnlyConvert {
+		return nil, blocked
+	}
+
+	net, err := broker.GetNetPosition(ctx, r.Broker, order.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	leg := net.Long // A SHORT order reduces the long leg
+	if order.Side == broker.SideLong {
+		leg = net.Short
+	}
+	if leg == nil || order.Size > leg.Size {
+		return nil, blocked
+	}
+
+	reduce := *order
+	reduce.ReduceOnly = true
+	return r.Broker.PlaceOrder(ctx, &reduce)
+}
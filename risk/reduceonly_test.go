@@ -0,0 +1,67 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func order(side broker.Side, size float64, reduceOnly bool) *broker.OrderRequest {
+	return &broker.OrderRequest{Symbol: "BTC-USDT", Side: side, Type: broker.OrderTypeMarket, Size: size, ReduceOnly: reduceOnly}
+}
+
+func TestReduceOnly_Reject(t *testing.T) {
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	r := NewReduceOnly(inner, ReduceOnlyConfig{Reason: "wind-down"})
+	ctx := context.Background()
+
+	_, err := r.PlaceOrder(ctx, order(broker.SideLong, 0.1, false))
+	var blocked *EntryBlockedError
+	if !errors.As(err, &blocked) || !errors.Is(err, ErrEntryBlocked) || blocked.Reason != "wind-down" {
+		t.Fatalf("entry error = %v, want *EntryBlockedError for wind-down", err)
+	}
+	if _, err := r.PlaceOrder(ctx, order(broker.SideShort, 0.1, true)); err != nil {
+		t.Errorf("reduce-only error = %v", err)
+	}
+
+	r.Lift()
+	if _, err := r.PlaceOrder(ctx, order(broker.SideLong, 0.1, false)); err != nil || r.State().Enforced {
+		t.Errorf("entry after Lift error = %v, state %+v", err, r.State())
+	}
+
+	r.Enforce("")
+	if state := r.State(); !state.Enforced || state.Reason != "wind-down" {
+		t.Errorf("State() after Enforce = %+v, want the configured reason", state)
+	}
+	if got := len(inner.PlacedOrders()); got != 2 {
+		t.Errorf("placed %d orders, want the reduce-only one and the entry after Lift", got)
+	}
+}
+
+func TestReduceOnly_Convert(t *testing.T) {
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	inner.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.5, EntryPrice: 50000})
+	r := NewReduceOnly(inner, ReduceOnlyConfig{Mode: ReduceOnlyConvert})
+	ctx := context.Background()
+
+	if _, err := r.PlaceOrder(ctx, order(broker.SideShort, 0.2, false)); err != nil {
+		t.Fatalf("closing order error = %v", err)
+	}
+	if placed := inner.PlacedOrders(); len(placed) != 1 || !placed[0].ReduceOnly {
+		t.Errorf("placed %+v, want the order converted to reduce-only", placed)
+	}
+
+	for _, o := range []*broker.OrderRequest{
+		order(broker.SideShort, 0.5, false), // Larger than the 0.3 left: would flip short
+		order(broker.SideLong, 0.1, false),  // Adds to the long
+	} {
+		if _, err := r.PlaceOrder(ctx, o); !errors.Is(err, ErrEntryBlocked) {
+			t.Errorf("%s %v error = %v, want ErrEntryBlocked", o.Side, o.Size, err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// ErrSessionStopped is returned for entry orders after a SessionGuard
+// stopped the run
+var ErrSessionStopped = errors.New("session stopped")
+
+// SessionConfig configures a SessionGuard. At least one limit must be set;
+// the first one reached stops the session.
+type SessionConfig struct {
+	// MaxLoss stops the session when the PnL of its recorded trades falls
+	// to -MaxLoss
+	MaxLoss float64
+	// MaxConsecutiveLosses stops the session after this many losing trades
+	// in a row
+	MaxConsecutiveLosses int
+	// MaxTrades stops the session once this many trades were recorded
+	MaxTrades int
+	// Flatten cancels orders and closes positions on every symbol the
+	// session ordered when it stops
+	Flatten bool
+}
+
+// SessionState is the state of the current run
+type SessionState struct {
+	Started           time.Time
+	Trades            int
+	PnL               float64 // Net PnL of the recorded trades
+	ConsecutiveLosses int
+	Stopped           bool
+	Reason            string // Which limit stopped the session
+	StoppedAt         time.Time
+}
+
+// StopHandler is notified when the session stops. Handlers run
+// synchronously on the goroutine that stopped it, after flattening.
+type StopHandler func(ctx context.Context, state SessionState)
+
+// SessionGuard tracks the PnL of one strategy run (the process lifetime)
+// and stops it at the configured limits, independently of account-level
+// limits such as DailyLossLimit. The strategy reports each closed trade
+// with RecordTrade and watches Done to exit; once stopped the wrapped
+// broker rejects new entries with ErrSessionStopped.
+type SessionGuard struct {
+	broker.Broker
+	config SessionConfig
+	now    func() time.Time
+
+	mu       sync.Mutex
+	state    SessionState
+	symbols  map[string]bool // Symbols ordered through the guard
+	handlers []StopHandler
+	done     chan struct{}
+}
+
+// NewSessionGuard wraps b and starts the session
+func NewSessionGuard(b broker.Broker, config SessionConfig) (*SessionGuard, error) {
+	if config.MaxLoss <= 0 && config.MaxConsecutiveLosses <= 0 && config.MaxTrades <= 0 {
+		return nil, errors.New("risk: MaxLoss, MaxConsecutiveLosses or MaxTrades is required")
+	}
+	g := &SessionGuard{
+		Broker:  b,
+		config:  config,
+		now:     time.Now,
+		symbols: make(map[string]bool),
+		done:    make(chan struct{}),
+	}
+	g.state.Started = g.now()
+	return g, nil
+}
+
+// OnStop registers a handler for the session stopping
+func (g *SessionGuard) OnStop(h StopHandler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.handlers = append(g.handlers, h)
+}
+
+// Done is closed when the session stops
+func (g *SessionGuard) Done() <-chan struct{} {
+	return g.done
+}
+
+// State returns the session's state
+func (g *SessionGuard) State() SessionState {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return g.state
+}
+
+// PlaceOrder places the order unless the session stopped. Reduce-only
+// orders are always placed.
+func (g *SessionGuard) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	g.mu.Lock()
+	state := g.state
+	if !state.Stopped {
+		g.symbols[order.Symbol] = true
+	}
+	g.mu.Unlock()
+
+	if state.Stopped && !order.ReduceOnly {
+		return nil, fmt.Errorf("%w: %s", ErrSessionStopped, state.Reason)
+	}
+	return g.Broker.PlaceOrder(ctx, order)
+}
+
+// RecordTrade adds a closed trade's net PnL to the session and stops it
+// when a limit is reached. The error is from flattening, if any.
+func (g *SessionGuard) RecordTrade(ctx context.Context, pnl float64) error {
+	g.mu.Lock()
+	if g.state.Stopped {
+		g.mu.Unlock()
+		return nil
+	}
+	g.state.Trades++
+	g.state.PnL += pnl
+	if pnl < 0 {
+		g.state.ConsecutiveLosses++
+	} else {
+		g.state.ConsecutiveLosses = 0
+	}
+
+	var reason string
+	switch s := g.state; {
+	case g.config.MaxLoss > 0 && s.PnL <= -g.config.MaxLoss:
+		reason = fmt.Sprintf("session PnL %.2f reached the loss limit", s.PnL)
+	case g.config.MaxConsecutiveLosses > 0 && s.ConsecutiveLosses >= g.config.MaxConsecutiveLosses:
+		reason = fmt.Sprintf("%d consecutive losing trades", s.ConsecutiveLosses)
+	case g.config.MaxTrades > 0 && s.Trades >= g.config.MaxTrades:
+		reason = fmt.Sprintf("%d trades reached the trade limit", s.Trades)
+	}
+	g.mu.Unlock()
+
+	if reason == "" {
+		return nil
+	}
+	return g.Stop(ctx, reason)
+}
+
+// Stop ends the session, e.g. on shutdown or an operator's request.
+// Stopping a stopped session does nothing.
+func (g *SessionGuard) Stop(ctx context.Context, reason string) error {
+	g.mu.Lock()
+	if g.state.Stopped {
+		g.mu.Unlock()
+		return nil
+	}
+	g.state.Stopped = true
+	g.state.Reason = reason
+	g.state.StoppedAt = g.now()
+	close(g.done)
+	state := g.state
+	symbols := make([]string, 0, len(g.symbols))
+	for symbol := range g.symbols {
+		symbols = append(symbols, symbol)
+	}
+	handlers := append([]StopHandler(nil), g.handlers...)
+	g.mu.Unlock()
+
+	var err error
+	if g.config.Flatten {
+		sort.Strings(symbols)
+		err = g.flatten(ctx, symbols)
+	}
+	for _, h := range handlers {
+		h(ctx, state)
+	}
+	return err
+}
+
+// flatten cancels orders and closes positions on symbols, attempting every
+// symbol even when one fails
+func (g *SessionGuard) flatten(ctx context.Context, symbols []string) error {
+	var errs []error
+	for _, symbol := range symbols {
+		if err := g.Broker.CancelAllOrders(ctx, symbol); err != nil {
+			errs = append(errs, fmt.Errorf("%s: canceling orders: %w", symbol, err))
+		}
+		if _, err := broker.FlattenPosition(ctx, g.Broker, symbol); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", symbol, err))
+		}
+	}
+	return errors.Join(errs...)
+}
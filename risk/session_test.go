@@ -0,0 +1,86 @@
+package risk
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func TestSessionGuard(t *testing.T) {
+	inner := brokertest.New()
+	inner.SetPrice("BTC-USDT", 50000)
+	inner.SetPrice("ETH-USDT", 3000)
+	// Another strategy's position, left alone when flattening
+	inner.SetPosition(broker.Position{Symbol: "ETH-USDT", Side: broker.SideLong, Size: 1, EntryPrice: 3000})
+
+	g, err := NewSessionGuard(inner, SessionConfig{MaxLoss: 500, MaxConsecutiveLosses: 3, MaxTrades: 10, Flatten: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stops []SessionState
+	g.OnStop(func(_ context.Context, s SessionState) { stops = append(stops, s) })
+	ctx := context.Background()
+
+	entry := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 0.1}
+	if _, err := g.PlaceOrder(ctx, entry); err != nil {
+		t.Fatal(err)
+	}
+	for _, pnl := range []float64{-100, 50, -100, -100} {
+		if err := g.RecordTrade(ctx, pnl); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if state := g.State(); state.Stopped || state.Trades != 4 || state.PnL != -250 || state.ConsecutiveLosses != 2 {
+		t.Fatalf("state = %+v, want running after 4 trades at -250", state)
+	}
+
+	// Third loss in a row
+	if err := g.RecordTrade(ctx, -10); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-g.Done():
+	default:
+		t.Fatal("Done not closed after stopping")
+	}
+	if len(stops) != 1 || stops[0].Reason != "3 consecutive losing trades" {
+		t.Errorf("stops = %+v, want one for consecutive losses", stops)
+	}
+
+	if _, err := g.PlaceOrder(ctx, entry); !errors.Is(err, ErrSessionStopped) {
+		t.Errorf("entry after stop error = %v, want ErrSessionStopped", err)
+	}
+	if p, _ := inner.GetPosition(ctx, "BTC-USDT"); p != nil {
+		t.Errorf("BTC position = %+v, want flattened", p)
+	}
+	if p, _ := inner.GetPosition(ctx, "ETH-USDT"); p == nil {
+		t.Error("ETH position was not ordered by the session and should remain")
+	}
+
+	g.RecordTrade(ctx, -1000)
+	if len(stops) != 1 {
+		t.Error("a stopped session should not stop again")
+	}
+}
+
+func TestSessionGuard_Limits(t *testing.T) {
+	if _, err := NewSessionGuard(brokertest.New(), SessionConfig{}); err == nil {
+		t.Error("config without limits accepted")
+	}
+
+	g, _ := NewSessionGuard(brokertest.New(), SessionConfig{MaxLoss: 100, MaxTrades: 2})
+	g.RecordTrade(context.Background(), -150)
+	if state := g.State(); !state.Stopped || state.Reason != "session PnL -150.00 reached the loss limit" {
+		t.Errorf("state = %+v, want stopped by the loss limit", state)
+	}
+
+	g, _ = NewSessionGuard(brokertest.New(), SessionConfig{MaxTrades: 2})
+	g.RecordTrade(context.Background(), 10)
+	g.RecordTrade(context.Background(), 10)
+	if state := g.State(); !state.Stopped || state.Trades != 2 {
+		t.Errorf("state = %+v, want stopped after 2 trades", state)
+	}
+}
@@ -0,0 +1,507 @@
+// Package router executes orders maker-first. The Router posts a passive
+// limit at the touch, waits for it to fill, and escalates whatever is left
+// to a marketable limit or a market order once the wait runs out or the
+// price moves away. Every execution records how much filled as maker, the
+// fees paid and the slippage against the price on arrival.
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/book"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+const (
+	// DefaultWait is how long the passive order rests by default
+	DefaultWait = 10 * time.Second
+	// DefaultPollInterval is how often the passive order is checked by default
+	DefaultPollInterval = 500 * time.Millisecond
+	// DefaultMarketableOffset is how far past the touch marketable limits
+	// are priced by default
+	DefaultMarketableOffset = 0.001
+)
+
+// Escalation is how the unfilled remainder is executed
+type Escalation string
+
+const (
+	EscalateMarket     Escalation = "MARKET"           // Market order
+	EscalateMarketable Escalation = "MARKETABLE_LIMIT" // IOC limit past the touch, capping slippage
+)
+
+// Reason explains why an execution escalated
+type Reason string
+
+const (
+	ReasonTimeout  Reason = "TIMEOUT"  // The wait ran out
+	ReasonAdverse  Reason = "ADVERSE"  // The price moved away past AdverseMove
+	ReasonRejected Reason = "REJECTED" // The passive order was rejected, e.g. post-only would cross
+)
+
+// TouchFunc returns the best bid and ask of a symbol
+type TouchFunc func(ctx context.Context, symbol string) (bid, ask float64, err error)
+
+// BookTouch reads the touch from an order book kept by book.Book. The
+// book holds one symbol; asking for another fails.
+func BookTouch(b *book.Book) TouchFunc {
+	return func(ctx context.Context, symbol string) (float64, float64, error) {
+		last := b.Last()
+		if last.Depth.Symbol != symbol {
+			return 0, 0, fmt.Errorf("router: order book is for %q, not %s", last.Depth.Symbol, symbol)
+		}
+		f := last.Features
+		if f.BestBid <= 0 || f.BestAsk <= 0 {
+			return 0, 0, fmt.Errorf("router: no order book for %s", symbol)
+		}
+		return f.BestBid, f.BestAsk, nil
+	}
+}
+
+// Config configures a Router
+type Config struct {
+	// Touch returns the best bid and ask (default: the broker's current
+	// price on both sides)
+	Touch TouchFunc
+	// Wait is the longest the passive order rests (default 10s)
+	Wait time.Duration
+	// AdverseMove escalates early once the touch moves this fraction away
+	// from the passive price (0 = wait the full time)
+	AdverseMove float64
+	// PollInterval between checks of the passive order (default 500ms)
+	PollInterval time.Duration
+	// Escalation for the remainder (default EscalateMarket)
+	Escalation Escalation
+	// MarketableOffset prices marketable limits this fraction past the
+	// touch (default 0.1%)
+	MarketableOffset float64
+	// MakerFee and TakerFee estimate fees, as fractions of notional
+	MakerFee float64
+	TakerFee float64
+	Logger   *slog.Logger
+}
+
+// Outcome records one execution
+type Outcome struct {
+	Symbol    string        `json:"symbol"`
+	Side      broker.Side   `json:"side"`
+	Size      float64       `json:"size"`
+	Arrival   float64       `json:"arrival"` // Mid when the execution started
+	Passive   float64       `json:"passive"` // Price of the passive order
+	MakerSize float64       `json:"makerSize"`
+	TakerSize float64       `json:"takerSize"`
+	AvgPrice  float64       `json:"avgPrice"`
+	Fees      float64       `json:"fees"` // Estimated from the configured rates
+	Escalated bool          `json:"escalated"`
+	Reason    Reason        `json:"reason,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	// Slippage is the average fill price's move against the order relative
+	// to Arrival: positive when filled worse than the mid
+	Slippage float64 `json:"slippage"`
+}
+
+// MakerRatio is the fraction of the filled size executed as maker
+func (o Outcome) MakerRatio() float64 {
+	if filled := o.MakerSize + o.TakerSize; filled > 0 {
+		return o.MakerSize / filled
+	}
+	return 0
+}
+
+// Stats aggregates outcomes
+type Stats struct {
+	Executions  int     `json:"executions"`
+	Escalated   int     `json:"escalated"`
+	MakerRatio  float64 `json:"makerRatio"`  // By size
+	AvgSlippage float64 `json:"avgSlippage"` // Weighted by notional
+	Fees        float64 `json:"fees"`
+}
+
+// Handler receives execution outcomes
+type Handler func(Outcome)
+
+// Router executes orders maker-first through a broker
+type Router struct {
+	broker broker.Broker
+	config Config
+	log    *slog.Logger
+
+	mu       sync.Mutex
+	handlers []Handler
+	stats    totals
+	now      func() time.Time
+}
+
+type totals struct {
+	executions, escalated int
+	maker, taker          float64 // Filled sizes
+	notional, slippage    float64 // Sum of notional, and of slippage times notional
+	fees                  float64
+}
+
+// New creates a router placing orders through b
+func New(b broker.Broker, config Config) *Router {
+	if config.Wait <= 0 {
+		config.Wait = DefaultWait
+	}
+	if config.PollInterval <= 0 {
+		config.PollInterval = DefaultPollInterval
+	}
+	if config.Escalation == "" {
+		config.Escalation = EscalateMarket
+	}
+	if config.MarketableOffset <= 0 {
+		config.MarketableOffset = DefaultMarketableOffset
+	}
+	if config.Touch == nil {
+		config.Touch = func(ctx context.Context, symbol string) (float64, float64, error) {
+			price, err := b.GetCurrentPrice(ctx, symbol)
+			return price, price, err
+		}
+	}
+	return &Router{
+		broker: b,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "router"),
+		now:    time.Now,
+	}
+}
+
+// OnOutcome registers a handler for execution outcomes
+func (r *Router) OnOutcome(h Handler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers = append(r.handlers, h)
+}
+
+// Stats returns totals over every execution so far
+func (r *Router) Stats() Stats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t := r.stats
+	s := Stats{Executions: t.executions, Escalated: t.escalated, Fees: t.fees}
+	if filled := t.maker + t.taker; filled > 0 {
+		s.MakerRatio = t.maker / filled
+	}
+	if t.notional > 0 {
+		s.AvgSlippage = t.slippage / t.notional
+	}
+	return s
+}
+
+// Execute fills req's Symbol, Side and Size (and ReduceOnly), maker first.
+// Its Type, Price and TimeInForce are ignored. The outcome is returned with
+// whatever filled even when escalation fails.
+func (r *Router) Execute(ctx context.Context, req *broker.OrderRequest) (*Outcome, error) {
+	if req.Size <= 0 {
+		return nil, broker.ErrInvalidQuantity
+	}
+	bid, ask, err := r.config.Touch(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	start := r.now()
+	out := &Outcome{Symbol: req.Symbol, Side: req.Side, Size: req.Size, Arrival: (bid + ask) / 2, Passive: bid}
+	if req.Side == broker.SideShort {
+		out.Passive = ask
+	}
+	f := &fills{}
+
+	passive := r.order(req, broker.OrderTypeLimit, out.Passive, req.Size)
+	if r.broker.SupportedFeatures().SupportsTimeInForce(broker.TimeInForcePostOnly) {
+		passive.TimeInForce = broker.TimeInForcePostOnly
+	}
+	order, err := r.broker.PlaceOrder(ctx, passive)
+	if err != nil {
+		r.log.Info("passive order rejected, escalating", logging.KeySymbol, req.Symbol, logging.KeyError, err)
+		out.Reason = ReasonRejected
+	} else {
+		out.Reason, err = r.wait(ctx, req.Side, order, f)
+		if err != nil {
+			return r.finish(out, f, start), err
+		}
+	}
+
+	if remaining := req.Size - f.size; out.Reason != "" && remaining > 1e-12 {
+		out.Escalated = true
+		if err := r.escalate(ctx, req, remaining, f); err != nil {
+			return r.finish(out, f, start), fmt.Errorf("router: escalating: %w", err)
+		}
+	}
+	return r.finish(out, f, start), nil
+}
+
+// ExecuteNotional executes req sized to notional quote currency at the mid
+// on arrival, converted with the broker's contract spec (see
+// broker.GetContractSpec), so the same exposure works on linear and inverse
+// contracts. req.Size is ignored.
+func (r *Router) ExecuteNotional(ctx context.Context, req *broker.OrderRequest, notional float64) (*Outcome, error) {
+	bid, ask, err := r.config.Touch(ctx, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	spec, err := broker.GetContractSpec(ctx, r.broker, req.Symbol)
+	if err != nil {
+		return nil, err
+	}
+	size := spec.Quantity(notional, (bid+ask)/2)
+	if size <= 0 {
+		return nil, fmt.Errorf("%w: %v of %s is under the minimum size", broker.ErrInvalidQuantity, notional, req.Symbol)
+	}
+
+	sized := *req
+	sized.Size = size
+	return r.Execute(ctx, &sized)
+}
+
+// wait polls the passive order, trading in direction side, until it fills,
+// the wait runs out or the price moves away, then cancels what is left. It
+// returns the reason to escalate, "" when the order filled.
+func (r *Router) wait(ctx context.Context, side broker.Side, order *broker.Order, f *fills) (Reason, error) {
+	f.update(*order)
+	if order.Status == broker.OrderStatusFilled {
+		return "", nil
+	}
+
+	timer := time.NewTimer(r.config.Wait)
+	defer timer.Stop()
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	var reason Reason
+	for reason == "" {
+		select {
+		case <-ctx.Done():
+			if _, err := r.cancel(context.WithoutCancel(ctx), order, f); err != nil {
+				r.log.Error("canceling passive order failed", logging.KeyOrderID, order.ID, logging.KeyError, err)
+			}
+			return "", ctx.Err()
+		case <-timer.C:
+			reason = ReasonTimeout
+		case <-ticker.C:
+			status, err := r.poll(ctx, order, f)
+			if err != nil {
+				r.log.Warn("polling passive order failed", logging.KeyOrderID, order.ID, logging.KeyError, err)
+				continue
+			}
+			if status == broker.OrderStatusFilled {
+				return "", nil
+			}
+			if final(status) {
+				return ReasonRejected, nil // Canceled by the exchange, e.g. post-only would cross
+			}
+			if r.adverse(ctx, side, order) {
+				reason = ReasonAdverse
+			}
+		}
+	}
+
+	filled, err := r.cancel(ctx, order, f)
+	if err != nil {
+		return "", err
+	}
+	if filled {
+		return "", nil
+	}
+	return reason, nil
+}
+
+// poll refreshes the passive order's fills and returns its status
+func (r *Router) poll(ctx context.Context, order *broker.Order, f *fills) (broker.OrderStatus, error) {
+	if getter, ok := r.broker.(broker.OrderGetter); ok {
+		current, err := getter.GetOrder(ctx, order.Symbol, order.ID)
+		if err != nil {
+			return "", err
+		}
+		f.update(*current)
+		return current.Status, nil
+	}
+
+	open, err := r.broker.GetOrders(ctx, &broker.OrderFilter{Symbol: order.Symbol})
+	if err != nil {
+		return "", err
+	}
+	for _, o := range open {
+		if o.ID == order.ID {
+			f.update(*o)
+			return o.Status, nil
+		}
+	}
+	f.update(filledAtLimit(*order)) // Gone from the open orders
+	return broker.OrderStatusFilled, nil
+}
+
+// adverse reports whether the touch moved away from the passive price of
+// an order trading in direction side. The order's own Side is the position
+// leg, which is the opposite direction for reduce-only orders.
+func (r *Router) adverse(ctx context.Context, side broker.Side, order *broker.Order) bool {
+	if r.config.AdverseMove <= 0 {
+		return false
+	}
+	bid, ask, err := r.config.Touch(ctx, order.Symbol)
+	if err != nil {
+		return false
+	}
+	if side == broker.SideShort {
+		return ask < order.Price*(1-r.config.AdverseMove)
+	}
+	return bid > order.Price*(1+r.config.AdverseMove)
+}
+
+// cancel cancels the passive order and records its final fills. It
+// reports whether the order filled completely before it could be canceled,
+// and fails when the order may still be resting, so the remainder isn't
+// escalated into a double fill.
+func (r *Router) cancel(ctx context.Context, order *broker.Order, f *fills) (bool, error) {
+	err := r.broker.CancelOrder(ctx, order.Symbol, order.ID)
+	if getter, ok := r.broker.(broker.OrderGetter); ok {
+		if current, getErr := getter.GetOrder(ctx, order.Symbol, order.ID); getErr == nil && final(current.Status) {
+			f.update(*current)
+			return current.Status == broker.OrderStatusFilled, nil
+		}
+	}
+	switch {
+	case err == nil:
+		return false, nil
+	case errors.Is(err, broker.ErrOrderNotFound):
+		f.update(filledAtLimit(*order)) // Filled between the last poll and the cancel
+		return true, nil
+	}
+	return false, fmt.Errorf("router: canceling passive order %s: %w", order.ID, err)
+}
+
+// escalate executes the remainder as a market order or marketable limit
+func (r *Router) escalate(ctx context.Context, req *broker.OrderRequest, remaining float64, f *fills) error {
+	var order *broker.OrderRequest
+	if r.config.Escalation == EscalateMarketable {
+		bid, ask, err := r.config.Touch(ctx, req.Symbol)
+		if err != nil {
+			return err
+		}
+		price := ask * (1 + r.config.MarketableOffset)
+		if req.Side == broker.SideShort {
+			price = bid * (1 - r.config.MarketableOffset)
+		}
+		order = r.order(req, broker.OrderTypeLimit, price, remaining)
+		order.TimeInForce = broker.TimeInForceIOC
+	} else {
+		order = r.order(req, broker.OrderTypeMarket, 0, remaining)
+	}
+
+	placed, err := r.broker.PlaceOrder(ctx, order)
+	if err != nil {
+		return err
+	}
+	taker := &fills{}
+	if placed.FilledSize > 0 {
+		taker.update(*placed)
+	} else if placed.Status != broker.OrderStatusCanceled && placed.Status != broker.OrderStatusExpired {
+		// Reported without fill details: assume it filled at the order or arrival price
+		assumed := *placed
+		assumed.FilledSize = remaining
+		if assumed.Price <= 0 {
+			if price, err := r.broker.GetCurrentPrice(ctx, req.Symbol); err == nil {
+				assumed.Price = price
+			}
+		}
+		taker.update(filledAtLimit(assumed))
+	}
+	f.taker, f.takerValue = taker.size, taker.value
+	return nil
+}
+
+func (r *Router) order(req *broker.OrderRequest, typ broker.OrderType, price, size float64) *broker.OrderRequest {
+	return &broker.OrderRequest{
+		Symbol:     req.Symbol,
+		Side:       req.Side,
+		Type:       typ,
+		Size:       size,
+		Price:      price,
+		ReduceOnly: req.ReduceOnly,
+	}
+}
+
+// finish completes the outcome, records it and notifies handlers
+func (r *Router) finish(out *Outcome, f *fills, start time.Time) *Outcome {
+	out.MakerSize, out.TakerSize = f.size, f.taker
+	out.Duration = r.now().Sub(start)
+	filled := f.size + f.taker
+	if filled > 0 {
+		out.AvgPrice = (f.value + f.takerValue) / filled
+		out.Fees = f.value*r.config.MakerFee + f.takerValue*r.config.TakerFee
+		if out.Arrival > 0 {
+			out.Slippage = (out.AvgPrice - out.Arrival) / out.Arrival
+			if out.Side == broker.SideShort {
+				out.Slippage = -out.Slippage
+			}
+		}
+	}
+
+	r.mu.Lock()
+	r.stats.executions++
+	if out.Escalated {
+		r.stats.escalated++
+	}
+	r.stats.maker += out.MakerSize
+	r.stats.taker += out.TakerSize
+	notional := f.value + f.takerValue
+	r.stats.notional += notional
+	r.stats.slippage += out.Slippage * notional
+	r.stats.fees += out.Fees
+	handlers := append([]Handler(nil), r.handlers...)
+	r.mu.Unlock()
+
+	r.log.Info("order executed", logging.KeySymbol, out.Symbol, "side", out.Side, "maker_size", out.MakerSize,
+		"taker_size", out.TakerSize, "slippage", out.Slippage, "reason", out.Reason)
+	for _, h := range handlers {
+		h(*out)
+	}
+	return out
+}
+
+// fills accumulates the maker fills of the passive order and the taker
+// fills of the escalation
+type fills struct {
+	size, value       float64 // Maker filled size and size times price
+	taker, takerValue float64
+}
+
+// update records the passive order's latest fill state; only growth counts
+func (f *fills) update(o broker.Order) {
+	if o.FilledSize <= f.size {
+		return
+	}
+	price := o.AveragePrice
+	if price <= 0 {
+		price = o.Price
+	}
+	f.size, f.value = o.FilledSize, o.FilledSize*price
+}
+
+// filledAtLimit returns the order as fully filled at its limit price
+func filledAtLimit(o broker.Order) broker.Order {
+	o.Status = broker.OrderStatusFilled
+	if o.FilledSize < o.Size {
+		o.FilledSize = o.Size
+	}
+	if o.AveragePrice <= 0 {
+		o.AveragePrice = o.Price
+	}
+	return o
+}
+
+// final reports whether an order can no longer fill
+func final(status broker.OrderStatus) bool {
+	switch status {
+	case broker.OrderStatusFilled, broker.OrderStatusCanceled, broker.OrderStatusRejected, broker.OrderStatusExpired:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/book"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// fillBroker reports fills of resting orders set by the test, and keeps
+// canceled orders queryable like an exchange does
+type fillBroker struct {
+	*brokertest.Broker
+	mu     sync.Mutex
+	orders map[string]broker.Order
+}
+
+func newFillBroker() *fillBroker {
+	return &fillBroker{Broker: brokertest.New(), orders: map[string]broker.Order{}}
+}
+
+func (b *fillBroker) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	order, err := b.Broker.PlaceOrder(ctx, req)
+	if err == nil && order.Type == broker.OrderTypeLimit {
+		b.mu.Lock()
+		b.orders[order.ID] = *order
+		b.mu.Unlock()
+	}
+	return order, err
+}
+
+// fill waits for the passive order, then fills it up to size
+func (b *fillBroker) fill(size float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for len(b.orders) == 0 {
+		b.mu.Unlock()
+		time.Sleep(time.Millisecond)
+		b.mu.Lock()
+	}
+	var o broker.Order
+	for _, order := range b.orders {
+		o = order // Only the passive order rests yet
+	}
+	o.FilledSize, o.AveragePrice, o.Status = size, o.Price, broker.OrderStatusPartiallyFilled
+	if size >= o.Size {
+		o.Status = broker.OrderStatusFilled
+		b.Broker.CancelOrder(context.Background(), o.Symbol, o.ID)
+	}
+	b.orders[o.ID] = o
+}
+
+func (b *fillBroker) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	if err := b.Broker.CancelOrder(ctx, symbol, orderID); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	o := b.orders[orderID]
+	o.Status = broker.OrderStatusCanceled
+	b.orders[orderID] = o
+	return nil
+}
+
+func (b *fillBroker) GetOrder(ctx context.Context, symbol, orderID string) (*broker.Order, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	o, ok := b.orders[orderID]
+	if !ok {
+		return nil, broker.ErrOrderNotFound
+	}
+	return &o, nil
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+// market is a touch the test moves
+type market struct {
+	mu       sync.Mutex
+	bid, ask float64
+}
+
+func (m *market) move(delta float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.bid, m.ask = m.bid+delta, m.ask+delta
+}
+
+func (m *market) touch(ctx context.Context, symbol string) (float64, float64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.bid, m.ask, nil
+}
+
+func TestRouter_MakerFill(t *testing.T) {
+	b := newFillBroker()
+	m := &market{bid: 99, ask: 101}
+	r := New(b, Config{Touch: m.touch, Wait: time.Second, PollInterval: time.Millisecond, MakerFee: 0.0002})
+
+	go b.fill(1)
+	out, err := r.Execute(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	placed := b.PlacedOrders()
+	if len(placed) != 1 || placed[0].Price != 99 || placed[0].TimeInForce != broker.TimeInForcePostOnly {
+		t.Errorf("placed %+v, want one post-only bid at 99", placed)
+	}
+	if out.Escalated || out.MakerSize != 1 || out.AvgPrice != 99 || !near(out.Slippage, -0.01) || !near(out.Fees, 0.0198) {
+		t.Errorf("outcome = %+v, want a maker fill at 99, 1%% better than the mid", out)
+	}
+}
+
+func TestRouter_Escalation(t *testing.T) {
+	tests := []struct {
+		name       string
+		config     Config
+		move       float64 // Touch move after the passive order is placed
+		wantReason Reason
+		wantType   broker.OrderType
+		wantPrice  float64
+	}{
+		{"timeout to market", Config{Wait: 20 * time.Millisecond}, 0, ReasonTimeout, broker.OrderTypeMarket, 0},
+		{"adverse move", Config{Wait: time.Minute, AdverseMove: 0.01}, 2, ReasonAdverse, broker.OrderTypeMarket, 0},
+		{"marketable limit", Config{Wait: 20 * time.Millisecond, Escalation: EscalateMarketable}, 0, ReasonTimeout, broker.OrderTypeLimit, 98.901},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := newFillBroker()
+			b.SetPrice("BTC-USDT", 100)
+			m := &market{bid: 99, ask: 101}
+			tt.config.Touch, tt.config.PollInterval, tt.config.TakerFee = m.touch, time.Millisecond, 0.0005
+			r := New(b, tt.config)
+			var outcomes []Outcome
+			r.OnOutcome(func(o Outcome) { outcomes = append(outcomes, o) })
+
+			go func() {
+				b.fill(0.4)
+				m.move(-tt.move) // The touch falls away from the resting ask
+			}()
+			out, err := r.Execute(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 1})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			placed := b.PlacedOrders()
+			if len(placed) != 2 || placed[1].Type != tt.wantType || !near(placed[1].Price, tt.wantPrice) || !near(placed[1].Size, 0.6) {
+				t.Fatalf("placed %+v, want the 0.6 remainder escalated as %s", placed, tt.wantType)
+			}
+			if orders, _ := b.GetOrders(context.Background(), nil); len(orders) != 0 && tt.wantType == broker.OrderTypeMarket {
+				t.Errorf("%d open orders, want the passive order canceled", len(orders))
+			}
+			if !out.Escalated || out.Reason != tt.wantReason || !near(out.MakerSize, 0.4) || !near(out.TakerSize, 0.6) {
+				t.Errorf("outcome = %+v, want %s with 0.4 maker and 0.6 taker", out, tt.wantReason)
+			}
+			if len(outcomes) != 1 || r.Stats().Escalated != 1 || !near(r.Stats().MakerRatio, 0.4) {
+				t.Errorf("outcomes %d, stats %+v; want one escalated outcome at 40%% maker", len(outcomes), r.Stats())
+			}
+		})
+	}
+}
+
+func TestRouter_ExchangeCanceled(t *testing.T) {
+	b := newFillBroker()
+	b.SetPrice("BTC-USDT", 100)
+	m := &market{bid: 99, ask: 101}
+	r := New(b, Config{Touch: m.touch, Wait: time.Minute, PollInterval: time.Millisecond})
+
+	// The exchange cancels the post-only order after acknowledging it
+	go func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		for len(b.orders) == 0 {
+			b.mu.Unlock()
+			time.Sleep(time.Millisecond)
+			b.mu.Lock()
+		}
+		for id, o := range b.orders {
+			b.Broker.CancelOrder(context.Background(), o.Symbol, id)
+			o.Status = broker.OrderStatusCanceled
+			b.orders[id] = o
+		}
+	}()
+	out, err := r.Execute(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !out.Escalated || out.Reason != ReasonRejected || out.MakerSize != 0 || !near(out.TakerSize, 1) {
+		t.Errorf("outcome = %+v, want the whole size escalated after the exchange canceled the passive order", out)
+	}
+}
+
+// stuckBroker fails cancels while the order keeps resting
+type stuckBroker struct {
+	*fillBroker
+}
+
+func (b *stuckBroker) CancelOrder(ctx context.Context, symbol, orderID string) error {
+	return errors.New("connection reset")
+}
+
+func TestRouter_CancelUnconfirmed(t *testing.T) {
+	b := &stuckBroker{newFillBroker()}
+	b.SetPrice("BTC-USDT", 100)
+	m := &market{bid: 99, ask: 101}
+	r := New(b, Config{Touch: m.touch, Wait: 20 * time.Millisecond, PollInterval: time.Millisecond})
+
+	go b.fill(0.4)
+	out, err := r.Execute(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1})
+	if err == nil {
+		t.Fatal("Execute() error = nil, want the failed cancel")
+	}
+	if placed := b.PlacedOrders(); len(placed) != 1 {
+		t.Errorf("placed %+v, want no escalation while the passive order may rest", placed)
+	}
+	if out == nil || out.Escalated || !near(out.MakerSize, 0.4) {
+		t.Errorf("outcome = %+v, want the 0.4 maker fill and no escalation", out)
+	}
+}
+
+// specBroker describes its contracts
+type specBroker struct {
+	*fillBroker
+	spec broker.ContractSpec
+}
+
+func (b *specBroker) ContractSpec(ctx context.Context, symbol string) (broker.ContractSpec, error) {
+	return b.spec, nil
+}
+
+func TestRouter_ExecuteNotional(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     *broker.ContractSpec
+		notional float64
+		wantSize float64
+	}{
+		{"base units", nil, 1000, 10},
+		{"lot size", &broker.ContractSpec{QuantityStep: 0.3}, 1000, 9.9},
+		{"inverse", &broker.ContractSpec{Inverse: true, ContractSize: 100, QuantityStep: 1}, 1050, 10},
+		{"under minimum", &broker.ContractSpec{Inverse: true, ContractSize: 100, MinQuantity: 1}, 50, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fb := newFillBroker()
+			var b broker.Broker = fb
+			if tt.spec != nil {
+				b = &specBroker{fillBroker: fb, spec: *tt.spec}
+			}
+			m := &market{bid: 99, ask: 101}
+			r := New(b, Config{Touch: m.touch, Wait: time.Second, PollInterval: time.Millisecond})
+
+			if tt.wantSize == 0 {
+				if _, err := r.ExecuteNotional(context.Background(), &broker.OrderRequest{Symbol: "BTC-USD", Side: broker.SideLong}, tt.notional); !errors.Is(err, broker.ErrInvalidQuantity) {
+					t.Errorf("ExecuteNotional() error = %v, want ErrInvalidQuantity", err)
+				}
+				return
+			}
+			go fb.fill(tt.wantSize)
+			out, err := r.ExecuteNotional(context.Background(), &broker.OrderRequest{Symbol: "BTC-USD", Side: broker.SideLong, Size: 1}, tt.notional)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !near(out.Size, tt.wantSize) || !near(fb.PlacedOrders()[0].Size, tt.wantSize) {
+				t.Errorf("executed %v, want %v", out.Size, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestRouter_ReduceOnlyAdverse(t *testing.T) {
+	b := newFillBroker()
+	b.SetPrice("BTC-USDT", 100)
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, EntryPrice: 100})
+	m := &market{bid: 99, ask: 101}
+	r := New(b, Config{Touch: m.touch, Wait: 5 * time.Second, AdverseMove: 0.01, PollInterval: time.Millisecond})
+
+	// Closing the long sells at the ask; the touch falls away from it. The
+	// acknowledged order reports the LONG leg it closes.
+	go func() {
+		b.fill(0.4)
+		m.move(-2)
+	}()
+	out, err := r.Execute(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 1, ReduceOnly: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Reason != ReasonAdverse || !near(out.TakerSize, 0.6) {
+		t.Errorf("outcome = %+v, want the remainder escalated on the adverse move", out)
+	}
+}
+
+func TestBookTouch(t *testing.T) {
+	b := book.New(book.Config{})
+	touch := BookTouch(b)
+	if _, _, err := touch(context.Background(), "BTC-USDT"); err == nil {
+		t.Error("touch of an empty book: error = nil")
+	}
+
+	b.Apply(broker.Depth{Symbol: "BTC-USDT", Bids: []broker.Level{{Price: 99, Size: 1}}, Asks: []broker.Level{{Price: 101, Size: 1}}})
+	if bid, ask, err := touch(context.Background(), "BTC-USDT"); err != nil || bid != 99 || ask != 101 {
+		t.Errorf("touch = %v, %v, %v, want 99, 101", bid, ask, err)
+	}
+	if _, _, err := touch(context.Background(), "ETH-USDT"); err == nil {
+		t.Error("touch of another symbol: error = nil")
+	}
+}
@@ -0,0 +1,119 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// ErrOutsideSession is matched by errors.Is for every SessionError
+var ErrOutsideSession = errors.New("outside trading session")
+
+// SessionError reports an order rejected because its symbol's session is closed
+type SessionError struct {
+	Symbol   string
+	Time     time.Time
+	Reason   string
+	NextOpen time.Time // Zero if the session does not reopen within a week
+}
+
+func (e *SessionError) Error() string {
+	if e.NextOpen.IsZero() {
+		return fmt.Sprintf("session closed for %s: %s", e.Symbol, e.Reason)
+	}
+	return fmt.Sprintf("session closed for %s: %s (reopens %s)", e.Symbol, e.Reason, e.NextOpen.Format(time.RFC3339))
+}
+
+func (e *SessionError) Unwrap() error {
+	return ErrOutsideSession
+}
+
+// Mode selects what happens to orders placed outside a session
+type Mode int
+
+const (
+	// ModeReject fails the order with a *SessionError
+	ModeReject Mode = iota
+	// ModeQueue blocks PlaceOrder until the session reopens (or the
+	// context is done) and then places the order
+	ModeQueue
+)
+
+// Config configures the scheduling wrapper
+type Config struct {
+	// Default applies to symbols without their own schedule (nil = always open)
+	Default *Schedule
+	// Symbols overrides the default schedule per symbol
+	Symbols map[string]*Schedule
+	Mode    Mode
+	// AllowReduceOnly lets reduce-only orders through at any time so
+	// positions can always be closed
+	AllowReduceOnly bool
+}
+
+// Broker wraps a broker.Broker and restricts PlaceOrder to the configured
+// sessions. All other operations pass through unchanged.
+type Broker struct {
+	broker.Broker
+	config Config
+	now    func() time.Time
+}
+
+// Wrap returns b restricted to the configured sessions
+func Wrap(b broker.Broker, config Config) *Broker {
+	return &Broker{Broker: b, config: config, now: time.Now}
+}
+
+// PlaceOrder places the order if its symbol's session is open. Outside the
+// session it is rejected or held until reopening, depending on Mode.
+func (b *Broker) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	if err := b.check(ctx, order); err != nil {
+		return nil, err
+	}
+	return b.Broker.PlaceOrder(ctx, order)
+}
+
+// Schedule returns the schedule that applies to a symbol (nil = always open)
+func (b *Broker) Schedule(symbol string) *Schedule {
+	if s, ok := b.config.Symbols[symbol]; ok {
+		return s
+	}
+	return b.config.Default
+}
+
+// check enforces the session for an order, waiting in ModeQueue
+func (b *Broker) check(ctx context.Context, order *broker.OrderRequest) error {
+	schedule := b.Schedule(order.Symbol)
+	if schedule == nil || (order.ReduceOnly && b.config.AllowReduceOnly) {
+		return nil
+	}
+
+	for {
+		now := b.now()
+		ok, reason := schedule.Allowed(now)
+		if ok {
+			return nil
+		}
+
+		sessionErr := &SessionError{
+			Symbol:   order.Symbol,
+			Time:     now,
+			Reason:   reason,
+			NextOpen: schedule.NextOpen(now),
+		}
+		if b.config.Mode != ModeQueue || sessionErr.NextOpen.IsZero() {
+			return sessionErr
+		}
+
+		timer := time.NewTimer(sessionErr.NextOpen.Sub(now))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
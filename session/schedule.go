@@ -0,0 +1,179 @@
+// Package session restricts order placement to configured trading windows
+package session
+
+import (
+	"sort"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+// Clock returns a time of day for use in a Window
+func Clock(hour, minute int) time.Duration {
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute
+}
+
+// Window is a recurring daily time range. Start and End are offsets from
+// midnight; when End is before Start the window wraps past midnight and
+// belongs to the day it starts on.
+type Window struct {
+	Start  time.Duration
+	End    time.Duration
+	Days   []time.Weekday // Empty = every day
+	Reason string         // Reported when a deny window blocks an order
+}
+
+// contains reports whether t falls inside the window
+func (w Window) contains(t time.Time) bool {
+	tod := sinceMidnight(t)
+	if w.Start <= w.End {
+		return tod >= w.Start && tod < w.End && w.onDay(t.Weekday())
+	}
+	// Wrapping window: late part today or early part carried from yesterday
+	if tod >= w.Start {
+		return w.onDay(t.Weekday())
+	}
+	return tod < w.End && w.onDay((t.Weekday()+6)%7)
+}
+
+func (w Window) onDay(d time.Weekday) bool {
+	if len(w.Days) == 0 {
+		return true
+	}
+	for _, wd := range w.Days {
+		if wd == d {
+			return true
+		}
+	}
+	return false
+}
+
+// Blackout is a one-off period in which trading is blocked, such as a
+// scheduled macro release
+type Blackout struct {
+	Start  time.Time
+	End    time.Time
+	Reason string
+}
+
+// Schedule decides when orders may be placed. An order is allowed when it
+// falls in any Allow window (or Allow is empty), outside every Deny window
+// and outside every Blackout.
+type Schedule struct {
+	Location  *time.Location // Time zone for windows (default UTC)
+	Allow     []Window
+	Deny      []Window
+	Blackouts []Blackout
+}
+
+// Allowed reports whether orders may be placed at t and, if not, why
+func (s *Schedule) Allowed(t time.Time) (bool, string) {
+	t = t.In(s.location())
+
+	for _, b := range s.Blackouts {
+		if !t.Before(b.Start) && t.Before(b.End) {
+			return false, reasonOr(b.Reason, "blackout")
+		}
+	}
+	for _, w := range s.Deny {
+		if w.contains(t) {
+			return false, reasonOr(w.Reason, "deny window")
+		}
+	}
+	if len(s.Allow) == 0 {
+		return true, ""
+	}
+	for _, w := range s.Allow {
+		if w.contains(t) {
+			return true, ""
+		}
+	}
+	return false, "outside trading hours"
+}
+
+// NextOpen returns the earliest time at or after t when orders are allowed.
+// It returns the zero time if the schedule stays closed for the next week.
+func (s *Schedule) NextOpen(t time.Time) time.Time {
+	if ok, _ := s.Allowed(t); ok {
+		return t
+	}
+
+	// The schedule can only open at the start of an allow window or the
+	// end of a deny window or blackout, so those are the only candidates
+	loc := s.location()
+	local := t.In(loc)
+	midnight := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, loc)
+
+	var candidates []time.Time
+	for d := -1; d <= 8; d++ {
+		base := midnight.AddDate(0, 0, d)
+		for _, w := range s.Allow {
+			candidates = append(candidates, base.Add(w.Start))
+		}
+		for _, w := range s.Deny {
+			end := base.Add(w.End)
+			if w.End < w.Start {
+				end = end.Add(day)
+			}
+			candidates = append(candidates, end)
+		}
+	}
+	for _, b := range s.Blackouts {
+		candidates = append(candidates, b.End)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+	for _, c := range candidates {
+		if !c.After(t) {
+			continue
+		}
+		if ok, _ := s.Allowed(c); ok {
+			return c
+		}
+	}
+	return time.Time{}
+}
+
+func (s *Schedule) location() *time.Location {
+	if s.Location == nil {
+		return time.UTC
+	}
+	return s.Location
+}
+
+// Weekdays returns an allow window covering Monday through Friday
+func Weekdays() Window {
+	return Window{
+		Start: 0,
+		End:   day,
+		Days:  []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday},
+	}
+}
+
+// FundingBlackout returns deny windows around the 00:00, 08:00 and 16:00
+// UTC funding settlements used by most perpetual exchanges. Use it with a
+// UTC schedule.
+func FundingBlackout(before, after time.Duration) []Window {
+	windows := make([]Window, 0, 3)
+	for _, hour := range []int{0, 8, 16} {
+		at := Clock(hour, 0)
+		windows = append(windows, Window{
+			Start:  (at - before + day) % day,
+			End:    (at + after) % day,
+			Reason: "funding settlement",
+		})
+	}
+	return windows
+}
+
+func sinceMidnight(t time.Time) time.Duration {
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second + time.Duration(t.Nanosecond())
+}
+
+func reasonOr(reason, fallback string) string {
+	if reason != "" {
+		return reason
+	}
+	return fallback
+}
@@ -0,0 +1,156 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+// 2024-01-05 is a Friday
+func at(day, hour, minute int) time.Time {
+	return time.Date(2024, 1, day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestSchedule_Allowed(t *testing.T) {
+	cpi := Blackout{Start: at(5, 13, 25), End: at(5, 13, 45), Reason: "CPI"}
+	s := &Schedule{
+		Allow:     []Window{Weekdays()},
+		Deny:      FundingBlackout(time.Minute, time.Minute),
+		Blackouts: []Blackout{cpi},
+	}
+
+	tests := []struct {
+		name       string
+		time       time.Time
+		want       bool
+		wantReason string
+	}{
+		{"Friday afternoon", at(5, 15, 0), true, ""},
+		{"Saturday", at(6, 12, 0), false, "outside trading hours"},
+		{"Funding minute", at(5, 7, 59), false, "funding settlement"},
+		{"Midnight funding wraps", at(4, 23, 59), false, "funding settlement"},
+		{"After funding", at(5, 8, 1), true, ""},
+		{"CPI blackout", at(5, 13, 30), false, "CPI"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := s.Allowed(tt.time)
+			if got != tt.want || reason != tt.wantReason {
+				t.Errorf("Allowed() = %v, %q, want %v, %q", got, reason, tt.want, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestSchedule_WrappingWindow(t *testing.T) {
+	// Friday 22:00 to Saturday 02:00
+	s := &Schedule{Allow: []Window{{Start: Clock(22, 0), End: Clock(2, 0), Days: []time.Weekday{time.Friday}}}}
+
+	for _, tt := range []struct {
+		time time.Time
+		want bool
+	}{
+		{at(5, 23, 0), true},
+		{at(6, 1, 0), true},
+		{at(6, 3, 0), false},
+		{at(4, 23, 0), false},
+	} {
+		if got, _ := s.Allowed(tt.time); got != tt.want {
+			t.Errorf("Allowed(%s) = %v, want %v", tt.time, got, tt.want)
+		}
+	}
+}
+
+func TestSchedule_NextOpen(t *testing.T) {
+	s := &Schedule{
+		Allow: []Window{Weekdays()},
+		Deny:  FundingBlackout(time.Minute, time.Minute),
+	}
+
+	tests := []struct {
+		name string
+		time time.Time
+		want time.Time
+	}{
+		{"Already open", at(5, 12, 0), at(5, 12, 0)},
+		{"Funding minute", at(5, 7, 59), at(5, 8, 1)},
+		{"Weekend waits for Monday funding", at(6, 12, 0), at(8, 0, 1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := s.NextOpen(tt.time); !got.Equal(tt.want) {
+				t.Errorf("NextOpen() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	closed := &Schedule{Allow: []Window{{Start: 0, End: 0}}}
+	if got := closed.NextOpen(at(5, 12, 0)); !got.IsZero() {
+		t.Errorf("NextOpen() on closed schedule = %s, want zero", got)
+	}
+}
+
+func TestBroker_Reject(t *testing.T) {
+	fake := brokertest.New()
+	fake.SetPrice("BTC-USDT", 100)
+	fake.SetPrice("ETH-USDT", 10)
+
+	b := Wrap(fake, Config{
+		Symbols:         map[string]*Schedule{"BTC-USDT": {Allow: []Window{Weekdays()}}},
+		AllowReduceOnly: true,
+	})
+	b.now = func() time.Time { return at(6, 12, 0) }
+
+	_, err := b.PlaceOrder(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1})
+	var sessionErr *SessionError
+	if !errors.As(err, &sessionErr) || !errors.Is(err, ErrOutsideSession) {
+		t.Fatalf("PlaceOrder() error = %v, want *SessionError", err)
+	}
+	if !sessionErr.NextOpen.Equal(at(8, 0, 0)) {
+		t.Errorf("NextOpen = %s, want %s", sessionErr.NextOpen, at(8, 0, 0))
+	}
+
+	// Reduce-only and unscheduled symbols pass through
+	if _, err := b.PlaceOrder(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeMarket, Size: 1, ReduceOnly: true}); err != nil {
+		t.Errorf("reduce-only PlaceOrder() error = %v", err)
+	}
+	if _, err := b.PlaceOrder(context.Background(), &broker.OrderRequest{Symbol: "ETH-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1}); err != nil {
+		t.Errorf("unscheduled PlaceOrder() error = %v", err)
+	}
+	if got := len(fake.PlacedOrders()); got != 2 {
+		t.Errorf("placed %d orders, want 2", got)
+	}
+}
+
+func TestBroker_Queue(t *testing.T) {
+	fake := brokertest.New()
+	fake.SetPrice("BTC-USDT", 100)
+
+	opensAt := time.Now().Add(20 * time.Millisecond)
+	b := Wrap(fake, Config{
+		Default: &Schedule{Blackouts: []Blackout{{Start: opensAt.Add(-time.Hour), End: opensAt}}},
+		Mode:    ModeQueue,
+	})
+
+	order := &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1}
+	if _, err := b.PlaceOrder(context.Background(), order); err != nil {
+		t.Fatalf("PlaceOrder() error = %v", err)
+	}
+	if time.Now().Before(opensAt) {
+		t.Error("queued order placed before the session opened")
+	}
+
+	// Context ends before the session opens
+	b.config.Default.Blackouts[0].End = time.Now().Add(time.Hour)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	if _, err := b.PlaceOrder(ctx, order); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("PlaceOrder() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,144 @@
+// Package shutdown wraps a broker.Broker so an application can stop trading
+// gracefully: refuse new orders, let in-flight orders and execution algos
+// settle, optionally cancel resting orders, then release the broker's
+// connections.
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/internal/inflight"
+)
+
+// CancelPolicy selects which open orders Shutdown cancels
+type CancelPolicy int
+
+const (
+	// CancelNone leaves every open order resting
+	CancelNone CancelPolicy = iota
+	// CancelEntries cancels orders that could open or grow a position and
+	// keeps reduce-only orders (stop losses, take profits) protecting it
+	CancelEntries
+	// CancelAll cancels every open order
+	CancelAll
+)
+
+// Config configures shutdown behavior
+type Config struct {
+	Cancel CancelPolicy
+	// Symbols limits cancellation to these symbols (default: every symbol
+	// with open orders)
+	Symbols []string
+}
+
+// Broker is a broker.Broker with graceful shutdown
+type Broker struct {
+	broker.Broker
+	cfg      Config
+	inflight inflight.Tracker
+}
+
+// Wrap adds graceful shutdown to b
+func Wrap(b broker.Broker, cfg Config) *Broker {
+	return &Broker{Broker: b, cfg: cfg}
+}
+
+// PlaceOrder forwards the order unless Shutdown has started
+func (b *Broker) PlaceOrder(ctx context.Context, order *broker.OrderRequest) (*broker.Order, error) {
+	if !b.inflight.Begin() {
+		return nil, broker.ErrShuttingDown
+	}
+	defer b.inflight.End()
+
+	return b.Broker.PlaceOrder(ctx, order)
+}
+
+// Hold registers pending work, such as an execution algo that still has
+// child orders to place, and delays Shutdown until release is called.
+// It fails with broker.ErrShuttingDown once Shutdown has started; algos
+// should stop scheduling child orders when it does.
+func (b *Broker) Hold() (release func(), err error) {
+	if !b.inflight.Begin() {
+		return nil, broker.ErrShuttingDown
+	}
+	return b.inflight.End, nil
+}
+
+// ShuttingDown reports whether Shutdown has been called
+func (b *Broker) ShuttingDown() bool {
+	return b.inflight.Closed()
+}
+
+// Shutdown stops accepting orders, waits for in-flight orders and holds to
+// finish, applies the cancel policy and finally shuts down the underlying
+// broker if it implements broker.Shutdowner. If ctx ends while waiting, the
+// cancel policy is still applied before returning the context error.
+func (b *Broker) Shutdown(ctx context.Context) error {
+	if !b.inflight.Close() {
+		return nil
+	}
+
+	var errs []error
+	if err := b.inflight.Wait(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("waiting for in-flight orders: %w", err))
+		// Cancellation must still be attempted with a live context
+		ctx = context.WithoutCancel(ctx)
+	}
+
+	if err := b.cancelOpenOrders(ctx); err != nil {
+		errs = append(errs, err)
+	}
+
+	if s, ok := b.Broker.(broker.Shutdowner); ok {
+		if err := s.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (b *Broker) cancelOpenOrders(ctx context.Context) error {
+	if b.cfg.Cancel == CancelNone {
+		return nil
+	}
+
+	symbols := b.cfg.Symbols
+	if len(symbols) == 0 {
+		symbols = []string{""}
+	}
+
+	var errs []error
+	for _, symbol := range symbols {
+		orders, err := b.Broker.GetOrders(ctx, &broker.OrderFilter{Symbol: symbol})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("listing open orders: %w", err))
+			continue
+		}
+
+		canceledAll := map[string]bool{}
+		for _, o := range orders {
+			switch {
+			case b.cfg.Cancel == CancelEntries && o.ReduceOnly:
+				continue
+			case b.cfg.Cancel == CancelAll:
+				if canceledAll[o.Symbol] {
+					continue
+				}
+				canceledAll[o.Symbol] = true
+				if err := b.Broker.CancelAllOrders(ctx, o.Symbol); err != nil {
+					errs = append(errs, fmt.Errorf("canceling %s orders: %w", o.Symbol, err))
+				}
+			default:
+				if err := b.Broker.CancelOrder(ctx, o.Symbol, o.ID); err != nil && !errors.Is(err, broker.ErrOrderNotFound) {
+					errs = append(errs, fmt.Errorf("canceling order %s: %w", o.ID, err))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,119 @@
+package shutdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newBroker() *brokertest.Broker {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetPrice("ETH-USDT", 3000)
+	b.AddOrder(broker.Order{ID: "entry-btc", Symbol: "BTC-USDT", Type: broker.OrderTypeLimit, Size: 1, Price: 45000})
+	b.AddOrder(broker.Order{ID: "stop-btc", Symbol: "BTC-USDT", Type: broker.OrderTypeStop, Size: 1, StopPrice: 44000, ReduceOnly: true})
+	b.AddOrder(broker.Order{ID: "entry-eth", Symbol: "ETH-USDT", Type: broker.OrderTypeLimit, Size: 1, Price: 2500})
+	return b
+}
+
+func openOrderIDs(t *testing.T, b broker.Broker) map[string]bool {
+	t.Helper()
+	orders, err := b.GetOrders(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]bool{}
+	for _, o := range orders {
+		ids[o.ID] = true
+	}
+	return ids
+}
+
+func TestShutdown_CancelPolicies(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{"none", Config{Cancel: CancelNone}, []string{"entry-btc", "stop-btc", "entry-eth"}},
+		{"entries", Config{Cancel: CancelEntries}, []string{"stop-btc"}},
+		{"all", Config{Cancel: CancelAll}, nil},
+		{"all for symbol", Config{Cancel: CancelAll, Symbols: []string{"ETH-USDT"}}, []string{"entry-btc", "stop-btc"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := newBroker()
+			b := Wrap(inner, tt.cfg)
+
+			if err := b.Shutdown(context.Background()); err != nil {
+				t.Fatalf("Shutdown() error = %v", err)
+			}
+
+			ids := openOrderIDs(t, inner)
+			if len(ids) != len(tt.want) {
+				t.Errorf("open orders = %v, want %v", ids, tt.want)
+			}
+			for _, id := range tt.want {
+				if !ids[id] {
+					t.Errorf("order %s was canceled", id)
+				}
+			}
+		})
+	}
+}
+
+func TestShutdown_RejectsOrdersAndWaitsForHolds(t *testing.T) {
+	b := Wrap(newBroker(), Config{Cancel: CancelAll})
+
+	release, err := b.Hold()
+	if err != nil {
+		t.Fatalf("Hold() error = %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- b.Shutdown(context.Background()) }()
+
+	// Shutdown must wait for the hold, but new orders are refused at once
+	for !b.ShuttingDown() {
+		time.Sleep(time.Millisecond)
+	}
+	_, err = b.PlaceOrder(context.Background(), &broker.OrderRequest{Symbol: "BTC-USDT", Side: broker.SideLong, Type: broker.OrderTypeMarket, Size: 1})
+	if !errors.Is(err, broker.ErrShuttingDown) {
+		t.Errorf("PlaceOrder() error = %v, want ErrShuttingDown", err)
+	}
+	if _, err := b.Hold(); !errors.Is(err, broker.ErrShuttingDown) {
+		t.Errorf("Hold() error = %v, want ErrShuttingDown", err)
+	}
+
+	select {
+	case <-done:
+		t.Fatal("Shutdown() returned while a hold was active")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	release()
+	if err := <-done; err != nil {
+		t.Errorf("Shutdown() error = %v", err)
+	}
+}
+
+func TestShutdown_TimeoutStillCancels(t *testing.T) {
+	inner := newBroker()
+	b := Wrap(inner, Config{Cancel: CancelAll})
+	b.Hold()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if err := b.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Shutdown() error = %v, want DeadlineExceeded", err)
+	}
+	if ids := openOrderIDs(t, inner); len(ids) != 0 {
+		t.Errorf("open orders = %v, want all canceled", ids)
+	}
+}
@@ -0,0 +1,190 @@
+// Package signing implements the request signature schemes exchanges use for
+// API key authentication: HMAC-SHA256 with a shared secret, and RSA or
+// Ed25519 with a private key.
+//
+// Signers receive the secret on every call (the credentials.Credentials
+// SecretKey) so rotated keys take effect immediately. For RSA and Ed25519 the
+// secret is the PEM encoded private key; Ed25519 also accepts a base64 seed.
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"hash"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidKey is returned when a secret cannot be parsed as a signing key
+var ErrInvalidKey = errors.New("invalid signing key")
+
+// Signer signs a request payload with a secret
+type Signer interface {
+	// Sign returns the encoded signature of payload
+	Sign(secret string, payload []byte) (string, error)
+	// Algorithm names the scheme, e.g. "HMAC-SHA256"
+	Algorithm() string
+}
+
+// HMAC returns a Signer producing hex encoded HMAC-SHA256 signatures
+func HMAC() Signer {
+	return &hmacSigner{}
+}
+
+// RSA returns a Signer producing base64 encoded RSASSA-PKCS1-v1_5 SHA-256
+// signatures. The secret is a PKCS#1 or PKCS#8 PEM private key.
+func RSA() Signer {
+	return &rsaSigner{}
+}
+
+// Ed25519 returns a Signer producing base64 encoded Ed25519 signatures. The
+// secret is a PKCS#8 PEM private key or a base64 encoded 32-byte seed.
+func Ed25519() Signer {
+	return &ed25519Signer{}
+}
+
+type hmacSigner struct {
+	mu     sync.Mutex
+	secret string
+	hashes *sync.Pool // Keyed HMACs of secret; keying one costs more than signing
+}
+
+func (s *hmacSigner) Sign(secret string, payload []byte) (string, error) {
+	hashes := s.pool(secret)
+	h := hashes.Get().(hash.Hash)
+	defer hashes.Put(h)
+
+	h.Reset()
+	h.Write(payload)
+	var sum [sha256.Size]byte
+	return hex.EncodeToString(h.Sum(sum[:0])), nil
+}
+
+// pool returns the HMAC pool of secret, starting a new one when the secret
+// changes
+func (s *hmacSigner) pool(secret string) *sync.Pool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.hashes == nil || s.secret != secret {
+		key := []byte(secret)
+		s.secret = secret
+		s.hashes = &sync.Pool{New: func() any { return hmac.New(sha256.New, key) }}
+	}
+	return s.hashes
+}
+
+func (s *hmacSigner) Algorithm() string { return "HMAC-SHA256" }
+
+type rsaSigner struct {
+	keys keyCache
+}
+
+func (s *rsaSigner) Sign(secret string, payload []byte) (string, error) {
+	key, err := s.keys.get(secret, parseRSA)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(payload)
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key.(*rsa.PrivateKey), crypto.SHA256, digest[:])
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(sig), nil
+}
+
+func (s *rsaSigner) Algorithm() string { return "RSA-SHA256" }
+
+type ed25519Signer struct {
+	keys keyCache
+}
+
+func (s *ed25519Signer) Sign(secret string, payload []byte) (string, error) {
+	key, err := s.keys.get(secret, parseEd25519)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(key.(ed25519.PrivateKey), payload)), nil
+}
+
+func (s *ed25519Signer) Algorithm() string { return "Ed25519" }
+
+// keyCache keeps the most recently parsed private key so PEM decoding only
+// happens when the secret changes
+type keyCache struct {
+	mu     sync.Mutex
+	secret string
+	key    any
+}
+
+func (c *keyCache) get(secret string, parse func(string) (any, error)) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.key != nil && c.secret == secret {
+		return c.key, nil
+	}
+
+	key, err := parse(secret)
+	if err != nil {
+		return nil, err
+	}
+	c.secret, c.key = secret, key
+	return key, nil
+}
+
+func parseRSA(secret string) (any, error) {
+	block, _ := pem.Decode([]byte(secret))
+	if block == nil {
+		return nil, errors.Join(ErrInvalidKey, errors.New("RSA key must be PEM encoded"))
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Join(ErrInvalidKey, err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.Join(ErrInvalidKey, errors.New("PEM key is not an RSA key"))
+	}
+	return rsaKey, nil
+}
+
+func parseEd25519(secret string) (any, error) {
+	if block, _ := pem.Decode([]byte(secret)); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Join(ErrInvalidKey, err)
+		}
+		edKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, errors.Join(ErrInvalidKey, errors.New("PEM key is not an Ed25519 key"))
+		}
+		return edKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(secret))
+	if err != nil {
+		return nil, errors.Join(ErrInvalidKey, err)
+	}
+	switch len(raw) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	}
+	return nil, errors.Join(ErrInvalidKey, errors.New("Ed25519 seed must be 32 bytes"))
+}
@@ -0,0 +1,114 @@
+package signing
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"testing"
+)
+
+func TestHMAC_Sign(t *testing.T) {
+	// RFC 4231 test case 2
+	got, err := HMAC().Sign("Jefe", []byte("what do ya want for nothing?"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "5bdcc146bf60754e6a042426089575c75a003f089d2739839dec58b964ec3843"
+	if got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+}
+
+func TestHMAC_Sign_Reuse(t *testing.T) {
+	s := HMAC()
+	sign := func(secret, payload string) string {
+		sig, err := s.Sign(secret, []byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sig
+	}
+
+	// Pooled hashers must not carry state between payloads or secrets
+	first := sign("Jefe", "what do ya want for nothing?")
+	sign("Jefe", "something else")
+	rotated := sign("other", "what do ya want for nothing?")
+	if again := sign("Jefe", "what do ya want for nothing?"); again != first || rotated == first {
+		t.Errorf("Sign() = %s after reuse and %s after rotation, want %s only for the original secret", again, rotated, first)
+	}
+}
+
+func BenchmarkHMAC_Sign(b *testing.B) {
+	s := HMAC()
+	payload := []byte("positionSide=LONG&quantity=0.00150000&side=BUY&symbol=BTC-USDT&timestamp=1760500000000&type=MARKET")
+	b.ReportAllocs()
+	for b.Loop() {
+		s.Sign("secret", payload)
+	}
+}
+
+func TestRSA_Sign(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte("symbol=BTC-USDT&timestamp=1")
+	digest := sha256.Sum256(payload)
+
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(key)
+	for name, secret := range map[string][]byte{
+		"PKCS1": pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}),
+		"PKCS8": pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sig, err := RSA().Sign(string(secret), payload)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			raw, _ := base64.StdEncoding.DecodeString(sig)
+			if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, digest[:], raw); err != nil {
+				t.Errorf("signature does not verify: %v", err)
+			}
+		})
+	}
+
+	if _, err := RSA().Sign("not a key", payload); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Sign() error = %v, want ErrInvalidKey", err)
+	}
+}
+
+func TestEd25519_Sign(t *testing.T) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte("symbol=BTC-USDT&timestamp=1")
+
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(priv)
+	signer := Ed25519()
+	for name, secret := range map[string]string{
+		"PEM":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: pkcs8})),
+		"seed": base64.StdEncoding.EncodeToString(priv.Seed()),
+	} {
+		t.Run(name, func(t *testing.T) {
+			sig, err := signer.Sign(secret, payload)
+			if err != nil {
+				t.Fatalf("Sign() error = %v", err)
+			}
+			raw, _ := base64.StdEncoding.DecodeString(sig)
+			if !ed25519.Verify(pub, payload, raw) {
+				t.Error("signature does not verify")
+			}
+		})
+	}
+
+	if _, err := signer.Sign(base64.StdEncoding.EncodeToString([]byte("short")), payload); !errors.Is(err, ErrInvalidKey) {
+		t.Errorf("Sign() error = %v, want ErrInvalidKey", err)
+	}
+}
@@ -0,0 +1,79 @@
+package snapshot
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// FileStore keeps snapshots in a journal file, one JSON object per line.
+// Appends are synced to disk so the history survives crashes.
+type FileStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileStore returns a store journaling to path, which is created on the
+// first append
+func NewFileStore(path string) *FileStore {
+	return &FileStore{path: path}
+}
+
+// Append adds a snapshot to the end of the journal
+func (f *FileStore) Append(s Snapshot) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// Load reads every snapshot in the journal. A missing journal is empty, and
+// a final line cut short by a crash is skipped.
+func (f *FileStore) Load() ([]Snapshot, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	file, err := os.Open(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var snaps []Snapshot
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 64<<20)
+	var bad error
+	for line := 1; scanner.Scan(); line++ {
+		if bad != nil {
+			return nil, bad // Only the last line may be damaged
+		}
+		var s Snapshot
+		if err := json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			bad = fmt.Errorf("%s:%d: %w", f.path, line, err)
+			continue
+		}
+		snaps = append(snaps, s)
+	}
+	return snaps, scanner.Err()
+}
@@ -0,0 +1,198 @@
+// Package snapshot captures the account at a fixed interval (balance,
+// equity, positions and open orders) and keeps the history in a store, so
+// the equity curve and drawdown survive restarts.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/analytics"
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/clock"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// DefaultInterval is the default time between snapshots in Run
+const DefaultInterval = time.Minute
+
+// Snapshot is the state of the account at a point in time
+type Snapshot struct {
+	Time      time.Time         `json:"time"`
+	Balance   broker.Balance    `json:"balance"`
+	Equity    float64           `json:"equity"` // Total balance, which includes unrealized PnL
+	Positions []broker.Position `json:"positions"`
+	Orders    []broker.Order    `json:"orders"` // Open orders
+}
+
+// Store persists snapshots in the order they were taken
+type Store interface {
+	Append(s Snapshot) error
+	Load() ([]Snapshot, error)
+}
+
+// Config configures a Scheduler
+type Config struct {
+	// Interval between snapshots in Run (default 1m)
+	Interval time.Duration
+	// Store keeps the history (default: in memory, lost on restart)
+	Store Store
+	// Clock stamps snapshots (default: the system clock)
+	Clock  clock.Clock
+	Logger *slog.Logger
+}
+
+// Scheduler takes account snapshots
+type Scheduler struct {
+	broker broker.Broker
+	config Config
+	log    *slog.Logger
+}
+
+// New creates a scheduler snapshotting b
+func New(b broker.Broker, config Config) *Scheduler {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	if config.Store == nil {
+		config.Store = &memoryStore{}
+	}
+	if config.Clock == nil {
+		config.Clock = clock.Real{}
+	}
+	return &Scheduler{
+		broker: b,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "snapshot"),
+	}
+}
+
+// Capture takes a snapshot and appends it to the store. Nothing is stored
+// when any part of the account can't be fetched.
+func (s *Scheduler) Capture(ctx context.Context) (*Snapshot, error) {
+	balance, err := s.broker.GetBalance(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: balance: %w", err)
+	}
+	positions, err := s.broker.GetPositions(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: positions: %w", err)
+	}
+	orders, err := s.broker.GetOrders(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: orders: %w", err)
+	}
+
+	snap := &Snapshot{
+		Time:    s.config.Clock.Now(),
+		Balance: *balance,
+		Equity:  balance.Total,
+	}
+	for _, p := range positions {
+		snap.Positions = append(snap.Positions, *p)
+	}
+	for _, o := range orders {
+		snap.Orders = append(snap.Orders, *o)
+	}
+
+	if err := s.config.Store.Append(*snap); err != nil {
+		return nil, fmt.Errorf("snapshot: store: %w", err)
+	}
+	return snap, nil
+}
+
+// Run takes a snapshot every interval until ctx is canceled. Failed
+// snapshots are logged and skipped.
+func (s *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if _, err := s.Capture(ctx); err != nil {
+				s.log.Error("snapshot failed", logging.KeyError, err)
+			}
+		}
+	}
+}
+
+// History returns the stored snapshots taken in [from, to). Zero times
+// leave that end open.
+func (s *Scheduler) History(from, to time.Time) ([]Snapshot, error) {
+	all, err := s.config.Store.Load()
+	if err != nil {
+		return nil, fmt.Errorf("snapshot: store: %w", err)
+	}
+	var snaps []Snapshot
+	for _, snap := range all {
+		if (from.IsZero() || !snap.Time.Before(from)) && (to.IsZero() || snap.Time.Before(to)) {
+			snaps = append(snaps, snap)
+		}
+	}
+	return snaps, nil
+}
+
+// Equity returns the equity curve of the snapshots taken in [from, to),
+// ready for analytics.Config.Equity
+func (s *Scheduler) Equity(from, to time.Time) ([]analytics.EquityPoint, error) {
+	snaps, err := s.History(from, to)
+	if err != nil {
+		return nil, err
+	}
+	curve := make([]analytics.EquityPoint, 0, len(snaps))
+	for _, snap := range snaps {
+		curve = append(curve, analytics.EquityPoint{Time: snap.Time, Equity: snap.Equity})
+	}
+	return curve, nil
+}
+
+// Drawdown is the current decline from the highest equity ever snapshotted
+type Drawdown struct {
+	Peak     analytics.EquityPoint
+	Equity   float64 // Latest snapshotted equity
+	Drawdown float64 // (Peak - Equity) / Peak, 0 at a new high
+}
+
+// Drawdown returns the drawdown as of the latest snapshot, including those
+// stored before a restart
+func (s *Scheduler) Drawdown() (Drawdown, error) {
+	snaps, err := s.History(time.Time{}, time.Time{})
+	if err != nil {
+		return Drawdown{}, err
+	}
+	var d Drawdown
+	for _, snap := range snaps {
+		if snap.Equity > d.Peak.Equity {
+			d.Peak = analytics.EquityPoint{Time: snap.Time, Equity: snap.Equity}
+		}
+		d.Equity = snap.Equity
+	}
+	if d.Peak.Equity > 0 {
+		d.Drawdown = (d.Peak.Equity - d.Equity) / d.Peak.Equity
+	}
+	return d, nil
+}
+
+// memoryStore keeps snapshots in memory
+type memoryStore struct {
+	mu    sync.Mutex
+	snaps []Snapshot
+}
+
+func (m *memoryStore) Append(s Snapshot) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snaps = append(m.snaps, s)
+	return nil
+}
+
+func (m *memoryStore) Load() ([]Snapshot, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Snapshot(nil), m.snaps...), nil
+}
@@ -0,0 +1,104 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+	"github.com/agatticelli/trading-go/clock"
+)
+
+var start = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func TestScheduler_Capture(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetBalance(broker.Balance{Asset: "USDT", Total: 10000, Available: 9000, UnrealizedPnL: 50})
+	b.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 0.1, EntryPrice: 49500})
+	b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: broker.SideShort, Type: broker.OrderTypeLimit, Size: 0.1, Price: 52000})
+
+	s := New(b, Config{Clock: clock.NewSim(start)})
+	snap, err := s.Capture(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !snap.Time.Equal(start) || snap.Equity != 10000 || len(snap.Positions) != 1 || len(snap.Orders) != 1 {
+		t.Errorf("Capture() = %+v", snap)
+	}
+
+	b.Err = broker.ErrRateLimited
+	if _, err := s.Capture(context.Background()); !errors.Is(err, broker.ErrRateLimited) {
+		t.Errorf("Capture() error = %v, want %v", err, broker.ErrRateLimited)
+	}
+	if history, _ := s.History(time.Time{}, time.Time{}); len(history) != 1 {
+		t.Errorf("stored %d snapshots, want the failed one skipped", len(history))
+	}
+}
+
+func TestScheduler_DrawdownAcrossRestarts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.jsonl")
+	ctx := context.Background()
+	b := brokertest.New()
+	sim := clock.NewSim(start)
+
+	capture := func(s *Scheduler, total float64) {
+		t.Helper()
+		b.SetBalance(broker.Balance{Asset: "USDT", Total: total})
+		if _, err := s.Capture(ctx); err != nil {
+			t.Fatal(err)
+		}
+		sim.Advance(time.Hour)
+	}
+
+	first := New(b, Config{Store: NewFileStore(path), Clock: sim})
+	capture(first, 10000)
+	capture(first, 12000)
+
+	restarted := New(b, Config{Store: NewFileStore(path), Clock: sim})
+	capture(restarted, 9000)
+
+	d, err := restarted.Drawdown()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Peak.Equity != 12000 || !d.Peak.Time.Equal(start.Add(time.Hour)) || d.Equity != 9000 || math.Abs(d.Drawdown-0.25) > 1e-9 {
+		t.Errorf("Drawdown() = %+v, want 25%% below the 12000 peak from before the restart", d)
+	}
+
+	curve, err := restarted.Equity(start.Add(time.Hour), start.Add(3*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(curve) != 2 || curve[0].Equity != 12000 || curve[1].Equity != 9000 {
+		t.Errorf("Equity() = %+v, want the last two snapshots", curve)
+	}
+}
+
+func TestFileStore_Damaged(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "snapshots.jsonl")
+	store := NewFileStore(path)
+	if snaps, err := store.Load(); err != nil || len(snaps) != 0 {
+		t.Fatalf("Load() of a missing journal = %v, %v", snaps, err)
+	}
+	store.Append(Snapshot{Time: start, Equity: 100})
+
+	file, _ := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	file.WriteString(`{"time":"2024-01-01T01:00:00Z","equ`)
+	file.Close()
+	if snaps, err := store.Load(); err != nil || len(snaps) != 1 {
+		t.Errorf("Load() with a torn last line = %d snapshots, %v; want it skipped", len(snaps), err)
+	}
+
+	file, _ = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0)
+	file.WriteString("\n" + `{"time":"2024-01-01T02:00:00Z","equity":90}` + "\n")
+	file.Close()
+	if _, err := store.Load(); err == nil {
+		t.Error("Load() error = nil, want an error for a damaged line mid-journal")
+	}
+}
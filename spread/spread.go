@@ -0,0 +1,239 @@
+// Package spread watches the same instrument across several brokers and
+// reports when the price gap, net of taker fees, is wide enough to trade
+package spread
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// DefaultInterval is the default price polling period
+const DefaultInterval = time.Second
+
+// UnwindTimeout bounds unwinding a one-sided fill
+const UnwindTimeout = 30 * time.Second
+
+// Venue is one broker quoting the instrument
+type Venue struct {
+	Broker   broker.Broker
+	Symbol   string  // Symbol on this broker, which may differ between venues
+	TakerFee float64 // Fraction of notional, e.g. 0.0005
+}
+
+// Name identifies the venue in logs and errors
+func (v Venue) Name() string {
+	return v.Broker.Name() + ":" + v.Symbol
+}
+
+// Config controls polling and the opportunity threshold
+type Config struct {
+	// Interval between price polls (default 1s)
+	Interval time.Duration
+	// Threshold is the minimum spread after fees, as a fraction of the buy
+	// price, e.g. 0.001 for 10 bps
+	Threshold float64
+	Logger    *slog.Logger
+}
+
+// Opportunity is a venue pair whose net spread exceeds the threshold
+type Opportunity struct {
+	Time      time.Time
+	Buy       Venue // Cheaper venue
+	Sell      Venue // Richer venue
+	BuyPrice  float64
+	SellPrice float64
+
+	// GrossSpread is (SellPrice - BuyPrice) / BuyPrice
+	GrossSpread float64
+	// NetSpread is GrossSpread minus both taker fees
+	NetSpread float64
+}
+
+// Handler receives opportunities
+type Handler func(ctx context.Context, o Opportunity)
+
+// Monitor polls prices on every venue and emits opportunities
+type Monitor struct {
+	venues []Venue
+	config Config
+	log    *slog.Logger
+
+	mu       sync.Mutex
+	handlers []Handler
+}
+
+// NewMonitor creates a spread monitor across the given venues
+func NewMonitor(config Config, venues ...Venue) *Monitor {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Monitor{
+		venues: venues,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "spread"),
+	}
+}
+
+// OnOpportunity registers a handler for opportunities
+func (m *Monitor) OnOpportunity(h Handler) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.handlers = append(m.handlers, h)
+}
+
+// Run polls at the configured interval until the context is canceled.
+// Price errors are logged and the next poll tries again.
+func (m *Monitor) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := m.Check(ctx); err != nil && ctx.Err() == nil {
+			m.log.Warn("spread check failed", logging.KeyError, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches all venue prices concurrently, so quotes are as close in
+// time as possible, and dispatches every opportunity found
+func (m *Monitor) Check(ctx context.Context) ([]Opportunity, error) {
+	prices := make([]float64, len(m.venues))
+	errs := make([]error, len(m.venues))
+
+	var wg sync.WaitGroup
+	for i, v := range m.venues {
+		wg.Add(1)
+		go func(i int, v Venue) {
+			defer wg.Done()
+			prices[i], errs[i] = v.Broker.GetCurrentPrice(ctx, v.Symbol)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", v.Name(), errs[i])
+			}
+		}(i, v)
+	}
+	wg.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	opportunities := m.evaluate(time.Now(), prices)
+
+	m.mu.Lock()
+	handlers := append([]Handler(nil), m.handlers...)
+	m.mu.Unlock()
+
+	for _, o := range opportunities {
+		for _, h := range handlers {
+			h(ctx, o)
+		}
+	}
+	return opportunities, nil
+}
+
+// evaluate compares every venue pair in the direction of the spread
+func (m *Monitor) evaluate(now time.Time, prices []float64) []Opportunity {
+	var opportunities []Opportunity
+	for i := range m.venues {
+		for j := range m.venues {
+			if i == j || prices[i] <= 0 || prices[j] <= prices[i] {
+				continue
+			}
+
+			buy, sell := m.venues[i], m.venues[j]
+			gross := (prices[j] - prices[i]) / prices[i]
+			net := gross - buy.TakerFee - sell.TakerFee
+			if net < m.config.Threshold {
+				continue
+			}
+
+			opportunities = append(opportunities, Opportunity{
+				Time:        now,
+				Buy:         buy,
+				Sell:        sell,
+				BuyPrice:    prices[i],
+				SellPrice:   prices[j],
+				GrossSpread: gross,
+				NetSpread:   net,
+			})
+		}
+	}
+	return opportunities
+}
+
+// Execution is the result of firing both legs of an opportunity
+type Execution struct {
+	BuyOrder  *broker.Order
+	SellOrder *broker.Order
+}
+
+// Executor fires simultaneous taker orders on both venues of an opportunity
+type Executor struct {
+	// Size is the order size on each venue
+	Size float64
+	// ReduceOnly closes existing positions instead of opening new ones
+	ReduceOnly bool
+}
+
+// Execute places a market buy on the cheap venue and a market sell on the
+// rich venue concurrently. If only one leg fills it is unwound so no
+// one-sided exposure remains, even when ctx was canceled; the returned
+// error then reports both the failure and any unwind error. The unwind
+// reverses what the leg did: it closes a leg that opened a position, and
+// reopens one that a ReduceOnly leg closed.
+func (e *Executor) Execute(ctx context.Context, o Opportunity) (*Execution, error) {
+	var exec Execution
+	var buyErr, sellErr error
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		exec.BuyOrder, buyErr = o.Buy.Broker.PlaceOrder(ctx, e.order(o.Buy.Symbol, broker.SideLong, false))
+	}()
+	go func() {
+		defer wg.Done()
+		exec.SellOrder, sellErr = o.Sell.Broker.PlaceOrder(ctx, e.order(o.Sell.Symbol, broker.SideShort, false))
+	}()
+	wg.Wait()
+
+	switch {
+	case buyErr == nil && sellErr == nil:
+		return &exec, nil
+	case buyErr != nil && sellErr != nil:
+		return nil, errors.Join(buyErr, sellErr)
+	}
+
+	// The failed leg may have failed because ctx ended: unwind regardless
+	unwindCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), UnwindTimeout)
+	defer cancel()
+	if buyErr != nil {
+		_, unwindErr := o.Sell.Broker.PlaceOrder(unwindCtx, e.order(o.Sell.Symbol, broker.SideLong, true))
+		return nil, errors.Join(fmt.Errorf("buy on %s: %w", o.Buy.Name(), buyErr), unwindErr)
+	}
+	_, unwindErr := o.Buy.Broker.PlaceOrder(unwindCtx, e.order(o.Buy.Symbol, broker.SideShort, true))
+	return nil, errors.Join(fmt.Errorf("sell on %s: %w", o.Sell.Name(), sellErr), unwindErr)
+}
+
+func (e *Executor) order(symbol string, side broker.Side, unwind bool) *broker.OrderRequest {
+	return &broker.OrderRequest{
+		Symbol:     symbol,
+		Side:       side,
+		Type:       broker.OrderTypeMarket,
+		Size:       e.Size,
+		ReduceOnly: e.ReduceOnly != unwind, // An unwind reverses the leg
+	}
+}
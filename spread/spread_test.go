@@ -0,0 +1,189 @@
+package spread
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func newVenue(name string, price float64) *brokertest.Broker {
+	b := brokertest.New()
+	b.SetName(name)
+	b.SetPrice("BTC-USDT", price)
+	return b
+}
+
+func TestMonitor_Check(t *testing.T) {
+	cheap := newVenue("cheap", 100)
+	rich := newVenue("rich", 100.5)
+	mid := newVenue("mid", 100.1)
+
+	m := NewMonitor(Config{Threshold: 0.0035},
+		Venue{cheap, "BTC-USDT", 0.0005},
+		Venue{rich, "BTC-USDT", 0.0005},
+		Venue{mid, "BTC-USDT", 0.0005},
+	)
+
+	var handled int
+	m.OnOpportunity(func(ctx context.Context, o Opportunity) { handled++ })
+
+	opportunities, err := m.Check(context.Background())
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+
+	// Only cheap->rich clears 35 bps after 10 bps of fees
+	if len(opportunities) != 1 || handled != 1 {
+		t.Fatalf("got %d opportunities (%d handled), want 1", len(opportunities), handled)
+	}
+	o := opportunities[0]
+	if o.Buy.Broker != cheap || o.Sell.Broker != rich {
+		t.Errorf("opportunity buys %s sells %s, want cheap/rich", o.Buy.Name(), o.Sell.Name())
+	}
+	if math.Abs(o.GrossSpread-0.005) > 1e-9 || math.Abs(o.NetSpread-0.004) > 1e-9 {
+		t.Errorf("spread gross/net = %v/%v, want 0.005/0.004", o.GrossSpread, o.NetSpread)
+	}
+}
+
+func TestMonitor_CheckError(t *testing.T) {
+	down := newVenue("down", 100)
+	down.Err = broker.ErrRateLimited
+	m := NewMonitor(Config{}, Venue{Broker: down, Symbol: "BTC-USDT"}, Venue{Broker: newVenue("up", 100), Symbol: "BTC-USDT"})
+
+	if _, err := m.Check(context.Background()); !errors.Is(err, broker.ErrRateLimited) {
+		t.Errorf("Check() error = %v, want %v", err, broker.ErrRateLimited)
+	}
+}
+
+func TestExecutor_Execute(t *testing.T) {
+	cheap := newVenue("cheap", 100)
+	rich := newVenue("rich", 101)
+	o := Opportunity{Buy: Venue{Broker: cheap, Symbol: "BTC-USDT"}, Sell: Venue{Broker: rich, Symbol: "BTC-USDT"}}
+
+	exec, err := (&Executor{Size: 2}).Execute(context.Background(), o)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if exec.BuyOrder.Side != broker.SideLong || exec.SellOrder.Side != broker.SideShort {
+		t.Errorf("orders = %s/%s, want LONG/SHORT", exec.BuyOrder.Side, exec.SellOrder.Side)
+	}
+}
+
+func TestExecutor_UnwindsOneSidedFill(t *testing.T) {
+	cheap := newVenue("cheap", 100)
+	rich := newVenue("rich", 101)
+	rich.Err = broker.ErrInsufficientBalance
+	o := Opportunity{Buy: Venue{Broker: cheap, Symbol: "BTC-USDT"}, Sell: Venue{Broker: rich, Symbol: "BTC-USDT"}}
+
+	if _, err := (&Executor{Size: 2}).Execute(context.Background(), o); !errors.Is(err, broker.ErrInsufficientBalance) {
+		t.Fatalf("Execute() error = %v, want %v", err, broker.ErrInsufficientBalance)
+	}
+
+	placed := cheap.PlacedOrders()
+	if len(placed) != 2 || !placed[1].ReduceOnly || placed[1].Side != broker.SideShort {
+		t.Errorf("cheap venue orders = %+v, want buy then reduce-only unwind", placed)
+	}
+	if positions, _ := cheap.GetPositions(context.Background(), nil); len(positions) != 0 {
+		t.Errorf("positions after unwind = %d, want 0", len(positions))
+	}
+}
+
+func TestExecutor_UnwindsReduceOnly(t *testing.T) {
+	cheap := newVenue("cheap", 100)
+	cheap.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 2, EntryPrice: 100})
+	rich := newVenue("rich", 101)
+	rich.Err = broker.ErrInsufficientBalance
+	o := Opportunity{Buy: Venue{Broker: cheap, Symbol: "BTC-USDT"}, Sell: Venue{Broker: rich, Symbol: "BTC-USDT"}}
+
+	if _, err := (&Executor{Size: 2, ReduceOnly: true}).Execute(context.Background(), o); !errors.Is(err, broker.ErrInsufficientBalance) {
+		t.Fatalf("Execute() error = %v, want %v", err, broker.ErrInsufficientBalance)
+	}
+
+	// The buy closed the short; the unwind sells to reopen it
+	placed := cheap.PlacedOrders()
+	if len(placed) != 2 || !placed[0].ReduceOnly || placed[1].ReduceOnly || placed[1].Side != broker.SideShort {
+		t.Errorf("cheap venue orders = %+v, want a reduce-only buy then an opening sell", placed)
+	}
+	positions, _ := cheap.GetPositions(context.Background(), nil)
+	if len(positions) != 1 || positions[0].Side != broker.SideShort || positions[0].Size != 2 {
+		t.Errorf("positions after unwind = %+v, want the 2 BTC short back", positions)
+	}
+}
+
+// flakyVenue fails price requests until failures runs out
+type flakyVenue struct {
+	*brokertest.Broker
+	failures atomic.Int32
+}
+
+func (v *flakyVenue) GetCurrentPrice(ctx context.Context, symbol string) (float64, error) {
+	if v.failures.Add(-1) >= 0 {
+		return 0, broker.ErrRateLimited
+	}
+	return v.Broker.GetCurrentPrice(ctx, symbol)
+}
+
+func TestMonitor_RunSurvivesErrors(t *testing.T) {
+	flaky := &flakyVenue{Broker: newVenue("flaky", 100)}
+	flaky.failures.Store(2)
+	m := NewMonitor(Config{Interval: time.Millisecond},
+		Venue{Broker: flaky, Symbol: "BTC-USDT"},
+		Venue{Broker: newVenue("rich", 101), Symbol: "BTC-USDT"},
+	)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	found := false
+	m.OnOpportunity(func(context.Context, Opportunity) {
+		found = true
+		cancel()
+	})
+	if err := m.Run(ctx); !errors.Is(err, context.Canceled) || !found {
+		t.Errorf("Run() = %v, found = %v; want the opportunity after the errors", err, found)
+	}
+}
+
+// ctxVenue fails orders once ctx is done, like an HTTP client. With cancel
+// set, it waits for filled and then cancels ctx, failing its own order.
+type ctxVenue struct {
+	*brokertest.Broker
+	filled chan struct{}
+	cancel context.CancelFunc
+}
+
+func (v *ctxVenue) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	if v.cancel != nil {
+		<-v.filled
+		v.cancel()
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	order, err := v.Broker.PlaceOrder(ctx, req)
+	if v.cancel == nil && !req.ReduceOnly {
+		close(v.filled)
+	}
+	return order, err
+}
+
+func TestExecutor_UnwindsAfterCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	filled := make(chan struct{})
+	cheap := &ctxVenue{Broker: newVenue("cheap", 100), filled: filled}
+	rich := &ctxVenue{Broker: newVenue("rich", 101), filled: filled, cancel: cancel}
+	o := Opportunity{Buy: Venue{Broker: cheap, Symbol: "BTC-USDT"}, Sell: Venue{Broker: rich, Symbol: "BTC-USDT"}}
+
+	if _, err := (&Executor{Size: 2}).Execute(ctx, o); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Execute() error = %v, want the canceled sell", err)
+	}
+	if positions, _ := cheap.GetPositions(context.Background(), nil); len(positions) != 0 {
+		t.Errorf("positions after unwind = %d, want 0", len(positions))
+	}
+}
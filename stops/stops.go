@@ -0,0 +1,410 @@
+// Package stops moves stop-loss orders as positions go into profit: first
+// to break-even once a profit threshold is reached, then trailing the best
+// price by a fixed percent or a multiple of ATR. Prices come from a trade
+// stream (Run) or any other feed (OnPrice); every adjustment is journaled.
+package stops
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+)
+
+// Reason explains a stop adjustment
+type Reason string
+
+const (
+	ReasonBreakEven Reason = "BREAK_EVEN" // Moved to entry plus fees
+	ReasonTrail     Reason = "TRAIL"      // Followed the best price
+	ReasonCrossed   Reason = "CROSSED"    // Price crossed the stop while unprotected: closed at market
+)
+
+const (
+	// DefaultRetryInterval is the default wait before placing a lost stop
+	// again
+	DefaultRetryInterval = time.Second
+	// MaxRetryInterval caps the backoff between attempts
+	MaxRetryInterval = time.Minute
+)
+
+// Config configures a Manager. Profit thresholds and distances are
+// fractions of price (0.01 = 1%), not of margin.
+type Config struct {
+	// BreakEvenAt is the profit that moves the stop to break-even
+	// (0 = skip break-even and trail from the start)
+	BreakEvenAt float64
+	// FeeRate is the round-trip fee added to the entry price, so a stop hit
+	// at break-even doesn't close at a loss after fees
+	FeeRate float64
+	// TrailPercent trails the stop this far behind the best price after
+	// break-even (0 = don't trail)
+	TrailPercent float64
+	// TrailATR trails the stop this many ATRs behind the best price instead
+	// of TrailPercent; requires ATR
+	TrailATR float64
+	// ATR returns the current ATR of a symbol, e.g. from an indicators.ATR
+	// fed with closed klines (0 = not ready, don't trail)
+	ATR func(symbol string) float64
+	// MinStep is the smallest move worth replacing the order for, as a
+	// fraction of price (default 0.1%)
+	MinStep float64
+	// Journal receives a record for every adjustment made or attempted
+	Journal JournalFunc
+	// OnUnprotected is called when a stop was canceled and neither its
+	// replacement nor the previous stop could be placed, leaving the
+	// position without one. The manager keeps placing the previous stop,
+	// waiting RetryInterval and then twice as long after every failure (up
+	// to MaxRetryInterval), until it rests again. A price past the stop
+	// closes the position with a reduce-only market order instead.
+	OnUnprotected JournalFunc
+	// RetryInterval is the first wait before retrying an unprotected
+	// position (default 1s)
+	RetryInterval time.Duration
+}
+
+// Adjustment records a stop move
+type Adjustment struct {
+	Time       time.Time   `json:"time"`
+	Symbol     string      `json:"symbol"`
+	Side       broker.Side `json:"side"`
+	Reason     Reason      `json:"reason"`
+	Price      float64     `json:"price"` // Market price that triggered the move
+	OldStop    float64     `json:"oldStop"`
+	NewStop    float64     `json:"newStop"`
+	OldOrderID string      `json:"oldOrderId"`
+	OrderID    string      `json:"orderId,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	// RestoredOrderID is the previous stop placed again after its
+	// replacement was rejected
+	RestoredOrderID string `json:"restoredOrderId,omitempty"`
+	// Unprotected is set when no stop rests for the position
+	Unprotected bool `json:"unprotected,omitempty"`
+}
+
+// JournalFunc receives stop adjustments
+type JournalFunc func(Adjustment)
+
+// JSONJournal returns a JournalFunc that writes one JSON object per line to w
+func JSONJournal(w io.Writer) JournalFunc {
+	var mu sync.Mutex
+	enc := json.NewEncoder(w)
+	return func(a Adjustment) {
+		mu.Lock()
+		defer mu.Unlock()
+		enc.Encode(a)
+	}
+}
+
+// Manager adjusts the stop-loss orders of tracked positions
+type Manager struct {
+	broker broker.Broker
+	config Config
+
+	mu        sync.Mutex
+	positions map[positionKey]*managed
+	now       func() time.Time
+}
+
+type positionKey struct {
+	symbol string
+	side   broker.Side
+}
+
+// managed is a position whose stop is being moved. mu serializes
+// adjustments so a slow replace doesn't race the next price.
+type managed struct {
+	mu        sync.Mutex
+	symbol    string
+	side      broker.Side
+	entry     float64
+	size      float64
+	stop      float64
+	orderID   string  // Empty while unprotected
+	best      float64 // Best price seen since tracking started
+	breakEven bool    // Break-even reached; trailing from here on
+
+	// Backoff of an unprotected position: no attempt before retryAt
+	retryAt   time.Time
+	retryWait time.Duration
+}
+
+// New creates a stop manager placing its orders through b
+func New(b broker.Broker, config Config) *Manager {
+	if config.MinStep <= 0 {
+		config.MinStep = 0.001
+	}
+	if config.RetryInterval <= 0 {
+		config.RetryInterval = DefaultRetryInterval
+	}
+	return &Manager{
+		broker:    b,
+		config:    config,
+		positions: make(map[positionKey]*managed),
+		now:       time.Now,
+	}
+}
+
+// Track starts managing the stop-loss order stopOrderID, resting at stop,
+// of pos. Tracking a position again replaces its state.
+func (m *Manager) Track(pos *broker.Position, stopOrderID string, stop float64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.positions[positionKey{pos.Symbol, pos.Side}] = &managed{
+		symbol:    pos.Symbol,
+		side:      pos.Side,
+		entry:     pos.EntryPrice,
+		size:      pos.Size,
+		stop:      stop,
+		orderID:   stopOrderID,
+		best:      pos.EntryPrice,
+		breakEven: m.config.BreakEvenAt == 0,
+	}
+}
+
+// Untrack stops managing a position's stop; the order is left in place
+func (m *Manager) Untrack(symbol string, side broker.Side) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.positions, positionKey{symbol, side})
+}
+
+// Stop returns the current stop price and order ID of a tracked position.
+// The order ID is empty while the position is unprotected.
+func (m *Manager) Stop(symbol string, side broker.Side) (price float64, orderID string, ok bool) {
+	m.mu.Lock()
+	p, ok := m.positions[positionKey{symbol, side}]
+	m.mu.Unlock()
+	if !ok {
+		return 0, "", false
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stop, p.orderID, true
+}
+
+// Run feeds the symbol's trades to OnPrice until the context is canceled or
+// the stream fails
+func (m *Manager) Run(ctx context.Context, streamer broker.TradeStreamer, symbol string) error {
+	return streamer.StreamTrades(ctx, symbol, func(t broker.Trade) {
+		m.OnPrice(ctx, t.Symbol, t.Price)
+	})
+}
+
+// OnPrice adjusts the stops of the symbol's tracked positions for a new
+// market price
+func (m *Manager) OnPrice(ctx context.Context, symbol string, price float64) {
+	m.mu.Lock()
+	var positions []*managed
+	for key, p := range m.positions {
+		if key.symbol == symbol {
+			positions = append(positions, p)
+		}
+	}
+	m.mu.Unlock()
+
+	for _, p := range positions {
+		m.adjust(ctx, p, price)
+	}
+}
+
+// adjust moves one position's stop if price warrants it
+func (m *Manager) adjust(ctx context.Context, p *managed, price float64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.side == broker.SideLong {
+		p.best = math.Max(p.best, price)
+	} else {
+		p.best = math.Min(p.best, price)
+	}
+	if p.orderID == "" {
+		m.restore(ctx, p, price)
+		return
+	}
+
+	reason := ReasonTrail
+	target := 0.0
+	if !p.breakEven {
+		if profit(p, price) < m.config.BreakEvenAt {
+			return
+		}
+		reason = ReasonBreakEven
+		target = p.entry + sign(p.side)*p.entry*m.config.FeeRate
+	} else if distance := m.trailDistance(p); distance > 0 {
+		target = p.best - sign(p.side)*distance
+	}
+	if target <= 0 {
+		return
+	}
+
+	// Stops only ever tighten, and only by a step worth an order change
+	if sign(p.side)*(target-p.stop) < m.config.MinStep*price {
+		if reason == ReasonBreakEven {
+			p.breakEven = true // Already at or beyond break-even
+		}
+		return
+	}
+
+	m.replace(ctx, p, reason, price, target)
+}
+
+// trailDistance returns how far behind the best price the stop trails
+// (0 = not trailing)
+func (m *Manager) trailDistance(p *managed) float64 {
+	if m.config.TrailATR > 0 && m.config.ATR != nil {
+		return m.config.TrailATR * m.config.ATR(p.symbol)
+	}
+	return m.config.TrailPercent * p.best
+}
+
+// replace moves the stop order to target and journals the result. A stop
+// that is gone (triggered or canceled) ends tracking. When the stop was
+// canceled but its replacement rejected, the previous stop is placed again
+// and the position stays tracked. Callers must hold p.mu.
+func (m *Manager) replace(ctx context.Context, p *managed, reason Reason, price, target float64) {
+	adj := Adjustment{
+		Time:       m.now(),
+		Symbol:     p.symbol,
+		Side:       p.side,
+		Reason:     reason,
+		Price:      price,
+		OldStop:    p.stop,
+		NewStop:    target,
+		OldOrderID: p.orderID,
+	}
+
+	order, err := broker.ReplaceOrder(ctx, m.broker, p.symbol, p.orderID, stopRequest(p, target), broker.ReplaceOptions{IfGone: broker.GoneAbort})
+
+	var replaceErr *broker.ReplaceError
+	switch {
+	case err == nil:
+		adj.OrderID = order.ID
+		p.stop = target
+		p.orderID = order.ID
+		if reason == ReasonBreakEven {
+			p.breakEven = true
+		}
+	case errors.As(err, &replaceErr):
+		// Canceled but not replaced: put the last good stop back
+		adj.Error = err.Error()
+		restored, restoreErr := m.broker.PlaceOrder(ctx, stopRequest(p, p.stop))
+		if restoreErr != nil {
+			adj.Error += "; restoring previous stop: " + restoreErr.Error()
+			adj.Unprotected = true
+			p.orderID = ""
+			m.backoff(p)
+		} else {
+			adj.RestoredOrderID = restored.ID
+			p.orderID = restored.ID
+		}
+	case errors.Is(err, broker.ErrOrderNotFound):
+		// The stop fired or was canceled: nothing left to manage
+		adj.Error = err.Error()
+		m.Untrack(p.symbol, p.side)
+	default:
+		adj.Error = err.Error()
+	}
+
+	if m.config.Journal != nil {
+		m.config.Journal(adj)
+	}
+	if adj.Unprotected && m.config.OnUnprotected != nil {
+		m.config.OnUnprotected(adj)
+	}
+}
+
+// restore places the last good stop of an unprotected position, or closes
+// it at market once price has crossed the stop, which the exchange would
+// reject or fire at once. Attempts back off while they fail, and only
+// success is journaled so a failing exchange doesn't flood the journal.
+// Callers must hold p.mu.
+func (m *Manager) restore(ctx context.Context, p *managed, price float64) {
+	if m.now().Before(p.retryAt) {
+		return
+	}
+
+	if sign(p.side)*(price-p.stop) <= 0 {
+		order, err := m.broker.PlaceOrder(ctx, &broker.OrderRequest{
+			Symbol:     p.symbol,
+			Side:       opposite(p.side),
+			Type:       broker.OrderTypeMarket,
+			Size:       p.size,
+			ReduceOnly: true,
+		})
+		if err != nil {
+			m.backoff(p)
+			return
+		}
+		m.Untrack(p.symbol, p.side)
+		m.journal(Adjustment{Reason: ReasonCrossed, OrderID: order.ID}, p, price)
+		return
+	}
+
+	order, err := m.broker.PlaceOrder(ctx, stopRequest(p, p.stop))
+	if err != nil {
+		m.backoff(p)
+		return
+	}
+	p.orderID = order.ID
+	p.retryAt, p.retryWait = time.Time{}, 0
+	m.journal(Adjustment{OrderID: order.ID, RestoredOrderID: order.ID}, p, price)
+}
+
+// backoff delays the next attempt to protect p, doubling the wait after
+// every failure. Callers must hold p.mu.
+func (m *Manager) backoff(p *managed) {
+	p.retryWait = min(max(2*p.retryWait, m.config.RetryInterval), MaxRetryInterval)
+	p.retryAt = m.now().Add(p.retryWait)
+}
+
+// journal records an action taken on an unprotected position at price
+func (m *Manager) journal(adj Adjustment, p *managed, price float64) {
+	if m.config.Journal == nil {
+		return
+	}
+	adj.Time, adj.Symbol, adj.Side, adj.Price = m.now(), p.symbol, p.side, price
+	adj.OldStop, adj.NewStop = p.stop, p.stop
+	m.config.Journal(adj)
+}
+
+// stopRequest returns the stop order closing p at stop
+func stopRequest(p *managed, stop float64) *broker.OrderRequest {
+	return &broker.OrderRequest{
+		Symbol:     p.symbol,
+		Side:       opposite(p.side),
+		Type:       broker.OrderTypeStop,
+		Size:       p.size,
+		StopPrice:  stop,
+		ReduceOnly: true,
+	}
+}
+
+// profit returns the price move in the position's favor as a fraction of
+// the entry price
+func profit(p *managed, price float64) float64 {
+	if p.entry == 0 {
+		return 0
+	}
+	return sign(p.side) * (price - p.entry) / p.entry
+}
+
+// sign is +1 for longs and -1 for shorts
+func sign(side broker.Side) float64 {
+	if side == broker.SideShort {
+		return -1
+	}
+	return 1
+}
+
+func opposite(side broker.Side) broker.Side {
+	if side == broker.SideLong {
+		return broker.SideShort
+	}
+	return broker.SideLong
+}
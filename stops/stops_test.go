@@ -0,0 +1,207 @@
+package stops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+func setup(config Config, side broker.Side, stop float64) (*brokertest.Broker, *Manager) {
+	b := brokertest.New()
+	order := b.AddOrder(broker.Order{Symbol: "BTC-USDT", Side: side, Type: broker.OrderTypeStop, Size: 1, StopPrice: stop, ReduceOnly: true})
+	m := New(b, config)
+	m.Track(&broker.Position{Symbol: "BTC-USDT", Side: side, Size: 1, EntryPrice: 50000}, order.ID, stop)
+	return b, m
+}
+
+func TestManager_BreakEvenThenTrail(t *testing.T) {
+	var journal []Adjustment
+	config := Config{BreakEvenAt: 0.02, FeeRate: 0.001, TrailPercent: 0.01, Journal: func(a Adjustment) { journal = append(journal, a) }}
+	_, m := setup(config, broker.SideLong, 48000)
+	ctx := context.Background()
+
+	steps := []struct {
+		price float64
+		want  float64
+	}{
+		{50500, 48000}, // +1%: below the break-even threshold
+		{51000, 50050}, // +2%: break-even plus fees
+		{51000, 50490}, // Trails 1% behind the best price
+		{51050, 50490}, // A move of less than the minimum step
+		{52000, 51480},
+		{51500, 51480}, // Never loosens
+		{48000, 51480},
+	}
+	for _, s := range steps {
+		m.OnPrice(ctx, "BTC-USDT", s.price)
+		if stop, _, _ := m.Stop("BTC-USDT", broker.SideLong); stop != s.want {
+			t.Fatalf("price %v: stop = %v, want %v", s.price, stop, s.want)
+		}
+	}
+
+	if len(journal) != 3 || journal[0].Reason != ReasonBreakEven || journal[1].Reason != ReasonTrail {
+		t.Fatalf("journal = %+v", journal)
+	}
+	if journal[1].OldOrderID != journal[0].OrderID || journal[1].OldStop != 50050 {
+		t.Errorf("adjustments not chained: %+v", journal)
+	}
+}
+
+func TestManager_ShortATR(t *testing.T) {
+	config := Config{TrailATR: 2, ATR: func(string) float64 { return 300 }}
+	b, m := setup(config, broker.SideShort, 51000)
+
+	m.OnPrice(context.Background(), "BTC-USDT", 49000)
+	stop, id, _ := m.Stop("BTC-USDT", broker.SideShort)
+	if stop != 49600 {
+		t.Errorf("stop = %v, want best price + 2 ATR", stop)
+	}
+	placed := b.PlacedOrders()
+	if len(placed) != 1 || placed[0].Side != broker.SideLong || placed[0].StopPrice != 49600 || !placed[0].ReduceOnly {
+		t.Errorf("placed = %+v", placed)
+	}
+	if orders, _ := b.GetOrders(context.Background(), nil); len(orders) != 1 || orders[0].ID != id {
+		t.Errorf("open orders = %+v, want only the new stop", orders)
+	}
+}
+
+func TestManager_StopGone(t *testing.T) {
+	var buf bytes.Buffer
+	b, m := setup(Config{TrailPercent: 0.01, Journal: JSONJournal(&buf)}, broker.SideLong, 48000)
+	b.CancelAllOrders(context.Background(), "") // The stop fired
+
+	m.OnPrice(context.Background(), "BTC-USDT", 52000)
+	if _, _, ok := m.Stop("BTC-USDT", broker.SideLong); ok {
+		t.Error("position still tracked after its stop was gone")
+	}
+	if len(b.PlacedOrders()) != 0 {
+		t.Errorf("placed %+v, want nothing", b.PlacedOrders())
+	}
+
+	var adj Adjustment
+	if err := json.Unmarshal(buf.Bytes(), &adj); err != nil || adj.Error == "" || adj.Reason != ReasonTrail {
+		t.Errorf("journal = %s (%v)", buf.String(), err)
+	}
+}
+
+// rejecting fails PlaceOrder for stops at reject, or every order while
+// down is set, counting attempts
+type rejecting struct {
+	*brokertest.Broker
+	reject   float64
+	down     bool
+	attempts int
+}
+
+func (b *rejecting) PlaceOrder(ctx context.Context, req *broker.OrderRequest) (*broker.Order, error) {
+	b.attempts++
+	if b.down || req.StopPrice == b.reject {
+		return nil, broker.ErrInvalidPrice
+	}
+	return b.Broker.PlaceOrder(ctx, req)
+}
+
+func TestManager_ReplacementRejected(t *testing.T) {
+	var journal []Adjustment
+	inner, _ := setup(Config{}, broker.SideLong, 48000)
+	b := &rejecting{Broker: inner, reject: 51480}
+	m := New(b, Config{TrailPercent: 0.01, Journal: func(a Adjustment) { journal = append(journal, a) }})
+	orders, _ := inner.GetOrders(context.Background(), nil)
+	m.Track(&broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, EntryPrice: 50000}, orders[0].ID, 48000)
+
+	// Trailing to 51480 cancels the stop, then the new one is rejected
+	m.OnPrice(context.Background(), "BTC-USDT", 52000)
+
+	stop, id, ok := m.Stop("BTC-USDT", broker.SideLong)
+	if !ok || stop != 48000 || id == "" || id == orders[0].ID {
+		t.Fatalf("Stop() = %v, %q, %v, want the previous stop at 48000 placed again", stop, id, ok)
+	}
+	if open, _ := inner.GetOrders(context.Background(), nil); len(open) != 1 || open[0].StopPrice != 48000 {
+		t.Errorf("open orders = %+v, want only the restored stop", open)
+	}
+	if len(journal) != 1 || journal[0].Error == "" || journal[0].RestoredOrderID != id || journal[0].Unprotected {
+		t.Errorf("journal = %+v, want the rejected move with the restored stop", journal)
+	}
+}
+
+func TestManager_Unprotected(t *testing.T) {
+	var alerts []Adjustment
+	inner, _ := setup(Config{}, broker.SideShort, 52000)
+	b := &rejecting{Broker: inner, down: true}
+	m := New(b, Config{TrailPercent: 0.01, OnUnprotected: func(a Adjustment) { alerts = append(alerts, a) }})
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	orders, _ := inner.GetOrders(context.Background(), nil)
+	m.Track(&broker.Position{Symbol: "BTC-USDT", Side: broker.SideShort, Size: 1, EntryPrice: 50000}, orders[0].ID, 52000)
+
+	m.OnPrice(context.Background(), "BTC-USDT", 48000)
+	if _, id, ok := m.Stop("BTC-USDT", broker.SideShort); !ok || id != "" {
+		t.Fatalf("Stop() order = %q, %v, want tracked without a stop", id, ok)
+	}
+	if len(alerts) != 1 || !alerts[0].Unprotected {
+		t.Fatalf("OnUnprotected calls = %+v, want one", alerts)
+	}
+
+	// Prices within the backoff don't reach the exchange
+	attempts := b.attempts
+	for range 100 {
+		m.OnPrice(context.Background(), "BTC-USDT", 47900)
+	}
+	if b.attempts != attempts {
+		t.Fatalf("%d orders sent during the backoff, want none", b.attempts-attempts)
+	}
+
+	// Still failing: no repeated alert, and the wait doubles
+	now = now.Add(DefaultRetryInterval)
+	m.OnPrice(context.Background(), "BTC-USDT", 47900)
+	now = now.Add(DefaultRetryInterval)
+	m.OnPrice(context.Background(), "BTC-USDT", 47900)
+	if b.attempts != attempts+1 {
+		t.Fatalf("%d attempts after 2s, want 1 with the wait doubled to 2s", b.attempts-attempts)
+	}
+
+	// Then the exchange accepts orders
+	b.down = false
+	now = now.Add(DefaultRetryInterval)
+	m.OnPrice(context.Background(), "BTC-USDT", 47800)
+	stop, id, _ := m.Stop("BTC-USDT", broker.SideShort)
+	if id == "" || stop != 52000 || len(alerts) != 1 {
+		t.Errorf("Stop() = %v, %q after recovery with %d alerts, want the stop at 52000 back", stop, id, len(alerts))
+	}
+}
+
+func TestManager_CrossedWhileUnprotected(t *testing.T) {
+	var journal []Adjustment
+	inner, _ := setup(Config{}, broker.SideLong, 48000)
+	inner.SetPrice("BTC-USDT", 47000)
+	inner.SetPosition(broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, EntryPrice: 50000})
+	b := &rejecting{Broker: inner, reject: -1, down: true}
+	m := New(b, Config{TrailPercent: 0.01, Journal: func(a Adjustment) { journal = append(journal, a) }})
+	now := time.Now()
+	m.now = func() time.Time { return now }
+	orders, _ := inner.GetOrders(context.Background(), nil)
+	m.Track(&broker.Position{Symbol: "BTC-USDT", Side: broker.SideLong, Size: 1, EntryPrice: 50000}, orders[0].ID, 48000)
+
+	m.OnPrice(context.Background(), "BTC-USDT", 52000) // Trail fails, unprotected at 48000
+	b.down = false
+	now = now.Add(time.Minute)
+
+	// The price fell through the lost stop: close instead of placing it
+	m.OnPrice(context.Background(), "BTC-USDT", 47000)
+	placed := inner.PlacedOrders()
+	last := placed[len(placed)-1]
+	if last.Type != broker.OrderTypeMarket || last.Side != broker.SideShort || !last.ReduceOnly || last.Size != 1 {
+		t.Errorf("last order = %+v, want a reduce-only market sell of the position", last)
+	}
+	if _, _, ok := m.Stop("BTC-USDT", broker.SideLong); ok {
+		t.Error("position still tracked after closing at market")
+	}
+	if n := len(journal); n != 2 || journal[1].Reason != ReasonCrossed || journal[1].OrderID == "" {
+		t.Errorf("journal = %+v, want the market close recorded", journal)
+	}
+}
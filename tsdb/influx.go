@@ -0,0 +1,128 @@
+package tsdb
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ErrWrite is matched by errors.Is for points rejected by the database
+var ErrWrite = errors.New("tsdb: write rejected")
+
+// InfluxConfig configures an Influx writer
+type InfluxConfig struct {
+	// URL of the InfluxDB server, e.g. http://localhost:8086
+	URL string
+	// Org and Bucket select where points are written
+	Org    string
+	Bucket string
+	// Token authenticates the writes
+	Token string
+	// HTTPClient sends the writes (default http.DefaultClient)
+	HTTPClient *http.Client
+}
+
+// Influx writes points to InfluxDB 2.x through its HTTP write API
+type Influx struct {
+	config   InfluxConfig
+	endpoint string
+}
+
+// NewInflux creates an InfluxDB writer
+func NewInflux(config InfluxConfig) *Influx {
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	query := url.Values{"org": {config.Org}, "bucket": {config.Bucket}, "precision": {"ns"}}
+	return &Influx{
+		config:   config,
+		endpoint: strings.TrimRight(config.URL, "/") + "/api/v2/write?" + query.Encode(),
+	}
+}
+
+// Write sends the points in one request
+func (w *Influx) Write(ctx context.Context, points ...Point) error {
+	var body bytes.Buffer
+	for _, p := range points {
+		writeLine(&body, p)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if w.config.Token != "" {
+		req.Header.Set("Authorization", "Token "+w.config.Token)
+	}
+
+	resp, err := w.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("tsdb: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("%w: %s: %s", ErrWrite, resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// writeLine appends p in line protocol. Tags and fields are sorted so lines
+// are stable.
+func writeLine(buf *bytes.Buffer, p Point) {
+	buf.WriteString(escape(p.Measurement, ", "))
+	for _, k := range sortedKeys(p.Tags) {
+		if p.Tags[k] == "" {
+			continue // Empty tag values are invalid
+		}
+		buf.WriteByte(',')
+		buf.WriteString(escape(k, ",= "))
+		buf.WriteByte('=')
+		buf.WriteString(escape(p.Tags[k], ",= "))
+	}
+	for i, k := range sortedKeys(p.Fields) {
+		if i == 0 {
+			buf.WriteByte(' ')
+		} else {
+			buf.WriteByte(',')
+		}
+		buf.WriteString(escape(k, ",= "))
+		buf.WriteByte('=')
+		buf.WriteString(strconv.FormatFloat(p.Fields[k], 'f', -1, 64))
+	}
+	buf.WriteByte(' ')
+	buf.WriteString(strconv.FormatInt(p.Time.UnixNano(), 10))
+	buf.WriteByte('\n')
+}
+
+// escape backslash-escapes the characters in special
+func escape(s, special string) string {
+	if !strings.ContainsAny(s, special) {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if strings.ContainsRune(special, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,85 @@
+package tsdb
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// DefaultTable is the default table SQL writes to
+const DefaultTable = "trading_metrics"
+
+// TimescaleSchema creates the table SQL writes to (with the default name)
+// as a TimescaleDB hypertable. Each field is stored as its own row, which
+// Grafana queries with WHERE measurement = ... AND field = ....
+const TimescaleSchema = `CREATE TABLE IF NOT EXISTS trading_metrics (
+    time        TIMESTAMPTZ      NOT NULL,
+    measurement TEXT             NOT NULL,
+    tags        JSONB            NOT NULL,
+    field       TEXT             NOT NULL,
+    value       DOUBLE PRECISION NOT NULL
+);
+SELECT create_hypertable('trading_metrics', 'time', if_not_exists => TRUE);`
+
+// Execer runs statements; *sql.DB and *sql.Tx implement it
+type Execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// SQLConfig configures a SQL writer
+type SQLConfig struct {
+	// Table points are inserted into (default "trading_metrics")
+	Table string
+}
+
+// SQL writes points to TimescaleDB, or any PostgreSQL-compatible database,
+// through a database/sql connection opened with the driver of your choice
+type SQL struct {
+	db    Execer
+	table string
+}
+
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// NewSQL creates a SQL writer on db
+func NewSQL(db Execer, config SQLConfig) (*SQL, error) {
+	if config.Table == "" {
+		config.Table = DefaultTable
+	}
+	if !tableName.MatchString(config.Table) {
+		return nil, fmt.Errorf("tsdb: invalid table name %q", config.Table)
+	}
+	return &SQL{db: db, table: config.Table}, nil
+}
+
+// Write inserts one row per field in a single statement
+func (w *SQL) Write(ctx context.Context, points ...Point) error {
+	var values []string
+	var args []any
+	for _, p := range points {
+		tags, err := json.Marshal(p.Tags)
+		if err != nil {
+			return fmt.Errorf("tsdb: %w", err)
+		}
+		if p.Tags == nil {
+			tags = []byte("{}")
+		}
+		for _, field := range sortedKeys(p.Fields) {
+			n := len(args)
+			values = append(values, fmt.Sprintf("($%d, $%d, $%d::jsonb, $%d, $%d)", n+1, n+2, n+3, n+4, n+5))
+			args = append(args, p.Time.UTC(), p.Measurement, string(tags), field, p.Fields[field])
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (time, measurement, tags, field, value) VALUES %s", w.table, strings.Join(values, ", "))
+	if _, err := w.db.ExecContext(ctx, query, args...); err != nil {
+		return fmt.Errorf("tsdb: %w", err)
+	}
+	return nil
+}
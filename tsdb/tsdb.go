@@ -0,0 +1,167 @@
+// Package tsdb records prices, funding rates and account equity into a
+// time-series database, so they can be charted in Grafana without glue
+// code. Writers for InfluxDB (line protocol over HTTP) and TimescaleDB (any
+// database/sql connection) are included; other stores only need to
+// implement Writer.
+package tsdb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/logging"
+)
+
+// DefaultInterval is the default time between snapshots in Run
+const DefaultInterval = time.Minute
+
+// Measurements written by Recorder
+const (
+	MeasurementPrice   = "price"   // Fields: price
+	MeasurementFunding = "funding" // Fields: rate, apr, mark_price, index_price
+	MeasurementEquity  = "equity"  // Fields: total, available, in_use, unrealized_pnl, realized_pnl
+)
+
+// Point is one timestamped measurement
+type Point struct {
+	Measurement string
+	Tags        map[string]string
+	Fields      map[string]float64
+	Time        time.Time
+}
+
+// Writer stores points
+type Writer interface {
+	Write(ctx context.Context, points ...Point) error
+}
+
+// Config configures a Recorder
+type Config struct {
+	// Symbols whose price and funding rate are recorded
+	Symbols []string
+	// Equity records the account balance
+	Equity bool
+	// Interval between snapshots in Run (default 1m)
+	Interval time.Duration
+	// Logger receives write failures in Run (default: discard)
+	Logger *slog.Logger
+}
+
+// Recorder takes periodic snapshots of a broker and writes them
+type Recorder struct {
+	broker broker.Broker
+	writer Writer
+	config Config
+	log    *slog.Logger
+	now    func() time.Time
+}
+
+// NewRecorder creates a recorder writing snapshots of b to w
+func NewRecorder(b broker.Broker, w Writer, config Config) *Recorder {
+	if config.Interval <= 0 {
+		config.Interval = DefaultInterval
+	}
+	return &Recorder{
+		broker: b,
+		writer: w,
+		config: config,
+		log:    logging.Component(logging.OrDiscard(config.Logger), "tsdb"),
+		now:    time.Now,
+	}
+}
+
+// Snapshot collects the configured points. Funding rates are skipped for
+// brokers that don't implement broker.FundingRateProvider. Points that
+// fail are left out and their errors joined.
+func (r *Recorder) Snapshot(ctx context.Context) ([]Point, error) {
+	now := r.now()
+	exchange := r.broker.Name()
+	var points []Point
+	var errs []error
+
+	for _, symbol := range r.config.Symbols {
+		tags := map[string]string{"broker": exchange, "symbol": symbol}
+
+		price, err := r.broker.GetCurrentPrice(ctx, symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("price %s: %w", symbol, err))
+		} else {
+			points = append(points, Point{Measurement: MeasurementPrice, Tags: tags, Fields: map[string]float64{"price": price}, Time: now})
+		}
+
+		provider, ok := r.broker.(broker.FundingRateProvider)
+		if !ok {
+			continue
+		}
+		rate, err := provider.GetFundingRate(ctx, symbol)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("funding %s: %w", symbol, err))
+			continue
+		}
+		points = append(points, Point{
+			Measurement: MeasurementFunding,
+			Tags:        tags,
+			Fields: map[string]float64{
+				"rate":        rate.Rate,
+				"apr":         rate.APR(),
+				"mark_price":  rate.MarkPrice,
+				"index_price": rate.IndexPrice,
+			},
+			Time: now,
+		})
+	}
+
+	if r.config.Equity {
+		balance, err := r.broker.GetBalance(ctx)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("balance: %w", err))
+		} else {
+			points = append(points, Point{
+				Measurement: MeasurementEquity,
+				Tags:        map[string]string{"broker": exchange, "asset": balance.Asset},
+				Fields: map[string]float64{
+					"total":          balance.Total,
+					"available":      balance.Available,
+					"in_use":         balance.InUse,
+					"unrealized_pnl": balance.UnrealizedPnL,
+					"realized_pnl":   balance.RealizedPnL,
+				},
+				Time: now,
+			})
+		}
+	}
+	return points, errors.Join(errs...)
+}
+
+// Record takes a snapshot and writes it. Points collected before a partial
+// failure are still written.
+func (r *Recorder) Record(ctx context.Context) error {
+	points, snapErr := r.Snapshot(ctx)
+	if len(points) == 0 {
+		return snapErr
+	}
+	return errors.Join(snapErr, r.writer.Write(ctx, points...))
+}
+
+// Run records at the configured interval until the context is canceled.
+// Failures are logged and retried on the next tick.
+func (r *Recorder) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.Record(ctx); err != nil {
+			r.log.Error("recording snapshot failed", logging.KeyError, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,151 @@
+package tsdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/agatticelli/trading-go/broker"
+	"github.com/agatticelli/trading-go/brokertest"
+)
+
+type memWriter struct {
+	points []Point
+}
+
+func (w *memWriter) Write(ctx context.Context, points ...Point) error {
+	w.points = append(w.points, points...)
+	return nil
+}
+
+func TestRecorder_Record(t *testing.T) {
+	b := brokertest.New()
+	b.SetPrice("BTC-USDT", 50000)
+	b.SetFundingRate("BTC-USDT", 0.0001)
+	b.SetBalance(broker.Balance{Asset: "USDT", Total: 1000, Available: 800, UnrealizedPnL: 25})
+
+	w := &memWriter{}
+	r := NewRecorder(b, w, Config{Symbols: []string{"BTC-USDT", "MISSING"}, Equity: true})
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	r.now = func() time.Time { return at }
+
+	err := r.Record(context.Background())
+	if !errors.Is(err, broker.ErrInvalidSymbol) {
+		t.Errorf("Record() error = %v, want %v for the missing symbol", err, broker.ErrInvalidSymbol)
+	}
+
+	got := map[string]Point{}
+	for _, p := range w.points {
+		got[p.Measurement] = p
+		if !p.Time.Equal(at) || p.Tags["broker"] != "brokertest" {
+			t.Errorf("point %+v, want time %v and broker tag", p, at)
+		}
+	}
+	if len(w.points) != 3 {
+		t.Fatalf("wrote %d points, want price, funding and equity: %+v", len(w.points), w.points)
+	}
+	if p := got[MeasurementPrice]; p.Fields["price"] != 50000 || p.Tags["symbol"] != "BTC-USDT" {
+		t.Errorf("price point = %+v", p)
+	}
+	if p := got[MeasurementFunding]; p.Fields["rate"] != 0.0001 || p.Fields["apr"] == 0 {
+		t.Errorf("funding point = %+v", p)
+	}
+	if p := got[MeasurementEquity]; p.Fields["total"] != 1000 || p.Fields["unrealized_pnl"] != 25 || p.Tags["asset"] != "USDT" {
+		t.Errorf("equity point = %+v", p)
+	}
+}
+
+func TestInflux_Write(t *testing.T) {
+	var body, auth, query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		body, auth, query = string(data), r.Header.Get("Authorization"), r.URL.RawQuery
+		if r.URL.Path != "/api/v2/write" {
+			t.Errorf("path = %s", r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	w := NewInflux(InfluxConfig{URL: server.URL + "/", Org: "desk", Bucket: "trading", Token: "secret"})
+	err := w.Write(context.Background(), Point{
+		Measurement: "price",
+		Tags:        map[string]string{"symbol": "BTC-USDT", "broker": "bingx", "note": "a b,c", "empty": ""},
+		Fields:      map[string]float64{"price": 43000.5, "size": 2},
+		Time:        time.Unix(0, 1700000000000000000),
+	})
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	want := "price,broker=bingx,note=a\\ b\\,c,symbol=BTC-USDT price=43000.5,size=2 1700000000000000000\n"
+	if body != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+	if auth != "Token secret" || !strings.Contains(query, "bucket=trading") || !strings.Contains(query, "org=desk") {
+		t.Errorf("auth = %q, query = %q", auth, query)
+	}
+}
+
+func TestInflux_WriteRejected(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"message":"unauthorized"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := NewInflux(InfluxConfig{URL: server.URL}).Write(context.Background(), Point{Measurement: "price", Fields: map[string]float64{"price": 1}})
+	if !errors.Is(err, ErrWrite) || !strings.Contains(err.Error(), "unauthorized") {
+		t.Errorf("Write() error = %v, want %v with the server message", err, ErrWrite)
+	}
+}
+
+type execer struct {
+	query string
+	args  []any
+}
+
+func (e *execer) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	e.query, e.args = query, args
+	return nil, nil
+}
+
+func TestSQL_Write(t *testing.T) {
+	db := &execer{}
+	w, err := NewSQL(db, SQLConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	err = w.Write(context.Background(),
+		Point{Measurement: "equity", Tags: map[string]string{"asset": "USDT"}, Fields: map[string]float64{"total": 1000, "available": 800}, Time: at},
+		Point{Measurement: "price", Fields: map[string]float64{"price": 50000}, Time: at},
+	)
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	wantQuery := "INSERT INTO trading_metrics (time, measurement, tags, field, value) VALUES " +
+		"($1, $2, $3::jsonb, $4, $5), ($6, $7, $8::jsonb, $9, $10), ($11, $12, $13::jsonb, $14, $15)"
+	if db.query != wantQuery {
+		t.Errorf("query = %q, want %q", db.query, wantQuery)
+	}
+	if got, want := fmt.Sprint(db.args[:5]...), fmt.Sprint(at, "equity", `{"asset":"USDT"}`, "available", 800.0); got != want {
+		t.Errorf("first row = %s, want %s", got, want)
+	}
+	if db.args[12] != "{}" {
+		t.Errorf("untagged point tags = %v, want {}", db.args[12])
+	}
+}
+
+func TestNewSQL_InvalidTable(t *testing.T) {
+	if _, err := NewSQL(&execer{}, SQLConfig{Table: "metrics; DROP TABLE x"}); err == nil {
+		t.Error("NewSQL() error = nil, want error for an unsafe table name")
+	}
+}